@@ -0,0 +1,229 @@
+// Package v1 是 Manager API v1 的 Go 客户端
+//
+// 接口方法和数据结构由 api/openapi/openapi.json 生成（zz_generated.go，make openapi），
+// 本文件提供请求发送、统一响应解包和错误类型。使用个人或服务账号 API Token 认证：
+//
+//	client := v1.New("https://mxsec.example.com", os.Getenv("MXSEC_TOKEN"))
+//	hosts, err := client.ListHosts(ctx, &v1.ListHostsParams{Status: "online"})
+//
+// 需要审批的高危操作返回 *PendingApprovalError（HTTP 202），其中包含审批单 ID
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client Manager API 客户端
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	userAgent  string
+}
+
+// Option 客户端选项
+type Option func(*Client)
+
+// WithHTTPClient 使用自定义 http.Client（如配置 TLS 或代理）
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithUserAgent 设置 User-Agent
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// New 创建客户端；baseURL 为 Manager 地址（如 https://mxsec.example.com），可带或不带 /api/v1 后缀
+func New(baseURL, token string, opts ...Option) *Client {
+	baseURL = strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api/v1")
+	c := &Client{
+		baseURL:    baseURL + "/api/v1",
+		token:      token,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		userAgent:  "mxsec-go-client/" + APIVersion,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError 接口返回的错误（HTTP 状态码非 2xx 或响应 code 非 0）
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API 请求失败: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API 请求失败: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// PendingApprovalError 操作需要审批（HTTP 202）：审批通过后由服务端执行
+type PendingApprovalError struct {
+	ApprovalID int64
+	Message    string
+}
+
+func (e *PendingApprovalError) Error() string {
+	return fmt.Sprintf("操作需要审批（审批单 #%d）: %s", e.ApprovalID, e.Message)
+}
+
+// IsNotFound 判断错误是否为资源不存在
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// envelope 统一响应结构
+type envelope struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// do 发送 JSON 请求并把响应 data 解码到 out（out 为 nil 时忽略 data）
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("编码请求失败: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+	return c.send(ctx, method, path, query, contentType, reader, out)
+}
+
+// doMultipart 发送 multipart 表单请求（文件上传）
+func (c *Client) doMultipart(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out interface{}) error {
+	return c.send(ctx, method, path, query, contentType, body, out)
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out interface{}) error {
+	resp, err := c.request(ctx, method, path, query, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		if resp.StatusCode >= 300 {
+			return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw))}
+		}
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if resp.StatusCode >= 300 || env.Code != 0 {
+		return &APIError{StatusCode: resp.StatusCode, Code: env.Code, Message: env.Message}
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		var pending struct {
+			ApprovalID int64 `json:"approval_id"`
+		}
+		_ = json.Unmarshal(env.Data, &pending)
+		return &PendingApprovalError{ApprovalID: pending.ApprovalID, Message: env.Message}
+	}
+	if out == nil || len(env.Data) == 0 || string(env.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("解析响应数据失败: %w", err)
+	}
+	return nil
+}
+
+// doRaw 发送请求并返回原始响应体（文件下载、导出）
+func (c *Client) doRaw(ctx context.Context, method, path string, query url.Values, body interface{}) ([]byte, error) {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("编码请求失败: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+	resp, err := c.request(ctx, method, path, query, contentType, reader)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw))}
+		var env envelope
+		if json.Unmarshal(raw, &env) == nil && env.Message != "" {
+			apiErr.Code, apiErr.Message = env.Code, env.Message
+		}
+		return nil, apiErr
+	}
+	return raw, nil
+}
+
+func (c *Client) request(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求 %s %s 失败: %w", method, path, err)
+	}
+	return resp, nil
+}
+
+// setQuery 设置非零值查询参数
+func setQuery[T comparable](q url.Values, key string, v T) {
+	var zero T
+	if v != zero {
+		q.Set(key, fmt.Sprint(v))
+	}
+}
+
+// addQueryArray 添加多值查询参数
+func addQueryArray(q url.Values, key string, values []string) {
+	for _, v := range values {
+		q.Add(key, v)
+	}
+}
@@ -0,0 +1,6556 @@
+// Code generated by openapi-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// APIVersion 生成客户端所用 OpenAPI 文档的版本
+const APIVersion = "1.0.0"
+
+// APIToken API Token（个人访问令牌或服务账号令牌），只保存哈希，明文仅在创建时返回一次
+type APIToken struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// 所属用户名（个人用户或服务账号），权限和业务线范围随所属用户
+	Owner string `json:"owner"`
+	// 明文前缀，用于识别 Token
+	Prefix string `json:"prefix"`
+	// 限定的权限（与所属用户权限取交集），为空表示继承所属用户的全部权限
+	Permissions []string `json:"permissions,omitempty"`
+	// 为空表示永不过期
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	LastUsedIP string  `json:"last_used_ip"`
+	// 格式 2006-01-02 15:04:05
+	RevokedAt *string `json:"revoked_at,omitempty"`
+	CreatedBy string  `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AgentCenterEndpoint AgentCenter 接入点（Agent 服务发现返回的地址）
+type AgentCenterEndpoint struct {
+	ID int64 `json:"id"`
+	// Agent 连接地址（host:port）
+	Address string `json:"address"`
+	// 对应的集群实例 ID（可选，用于按连接数计算负载）
+	InstanceID string `json:"instance_id"`
+	// 服务区域（空表示所有区域）
+	Region string `json:"region"`
+	// 服务业务线（空表示所有业务线）
+	BusinessLine string `json:"business_line"`
+	// 权重
+	Weight int64 `json:"weight"`
+	// 最大连接数（0 表示不限）
+	MaxAgents int64 `json:"max_agents"`
+	// 状态（可选值：active, draining, disabled）
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AgentCenterEndpointItem 接入点列表项（包含当前连接数）
+type AgentCenterEndpointItem struct {
+	ID int64 `json:"id"`
+	// Agent 连接地址（host:port）
+	Address string `json:"address"`
+	// 对应的集群实例 ID（可选，用于按连接数计算负载）
+	InstanceID string `json:"instance_id"`
+	// 服务区域（空表示所有区域）
+	Region string `json:"region"`
+	// 服务业务线（空表示所有业务线）
+	BusinessLine string `json:"business_line"`
+	// 权重
+	Weight int64 `json:"weight"`
+	// 最大连接数（0 表示不限）
+	MaxAgents int64 `json:"max_agents"`
+	// 状态（可选值：active, draining, disabled）
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 当前连接数（仅集群模式且配置了 instance_id 时有效）
+	Connections int64 `json:"connections"`
+	// 实例是否存活（未配置 instance_id 时为空）
+	Alive *bool `json:"alive,omitempty"`
+}
+
+// AgentCenterEndpointRequest 创建/更新接入点请求
+type AgentCenterEndpointRequest struct {
+	Address      string `json:"address"`
+	InstanceID   string `json:"instance_id"`
+	Region       string `json:"region"`
+	BusinessLine string `json:"business_line"`
+	Weight       *int64 `json:"weight,omitempty"`
+	MaxAgents    int64  `json:"max_agents"`
+	Status       string `json:"status"`
+	Description  string `json:"description"`
+}
+
+// AgentDiagnostics Agent 诊断信息收集记录 诊断包（tar.gz）由 Agent 通过 FileExt.Upload 上传，保存在 Content 中，列表查询时不加载
+type AgentDiagnostics struct {
+	ID       int64  `json:"id"`
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	// 可选值：pending, collecting, completed, failed
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	FileSize  int64  `json:"file_size"`
+	SHA256    string `json:"sha256"`
+	CreatedBy string `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	PushedAt *string `json:"pushed_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AgentRestartRecord Agent 重启记录
+type AgentRestartRecord struct {
+	ID int64 `json:"id"`
+	// all / selected
+	TargetType  string   `json:"target_type"`
+	TargetHosts []string `json:"target_hosts,omitempty"`
+	// 发起者的业务线范围（为空表示不限制）
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 可选值：pending, pushing, success, partial, failed
+	Status       string   `json:"status"`
+	TotalCount   int64    `json:"total_count"`
+	SuccessCount int64    `json:"success_count"`
+	FailedCount  int64    `json:"failed_count"`
+	FailedHosts  []string `json:"failed_hosts,omitempty"`
+	Message      string   `json:"message"`
+	CreatedBy    string   `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	PushedAt *string `json:"pushed_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+}
+
+// Alert 告警模型
+type Alert struct {
+	ID int64 `json:"id"`
+	// 关联 scan_results.result_id
+	ResultID string `json:"result_id"`
+	HostID   string `json:"host_id"`
+	RuleID   string `json:"rule_id"`
+	PolicyID string `json:"policy_id"`
+	// critical, high, medium, low
+	Severity      string `json:"severity"`
+	Category      string `json:"category"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Actual        string `json:"actual"`
+	Expected      string `json:"expected"`
+	FixSuggestion string `json:"fix_suggestion"`
+	// 可选值：active, resolved, ignored
+	Status string `json:"status"`
+	// 首次发现时间
+	FirstSeenAt string `json:"first_seen_at"`
+	// 最后发现时间
+	LastSeenAt string `json:"last_seen_at"`
+	// 上次通知时间（用于定期告警）
+	LastNotifiedAt *string `json:"last_notified_at,omitempty"`
+	// 通知次数
+	NotifyCount int64 `json:"notify_count"`
+	// 解决时间
+	ResolvedAt *string `json:"resolved_at,omitempty"`
+	// 解决人
+	ResolvedBy string `json:"resolved_by"`
+	// 解决原因
+	ResolveReason string `json:"resolve_reason"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 关联关系
+	Host Host `json:"host"`
+	Rule Rule `json:"rule"`
+}
+
+// AlertConfig 告警配置
+type AlertConfig struct {
+	// 重复告警通知间隔（分钟） 同一主机同一问题在此间隔内只会通知一次
+	RepeatAlertInterval int64 `json:"repeat_alert_interval"`
+	// 是否启用定期汇总（false = 只发首次告警，true = 按间隔定期发送）
+	EnablePeriodicSummary bool `json:"enable_periodic_summary"`
+}
+
+// App 应用资产模型
+type App struct {
+	ID     string `json:"id"`
+	HostID string `json:"host_id"`
+	// mysql、redis、nginx、kafka 等
+	AppType    string `json:"app_type"`
+	AppName    string `json:"app_name"`
+	Version    string `json:"version"`
+	Port       int64  `json:"port"`
+	ProcessID  string `json:"process_id"`
+	ConfigPath string `json:"config_path"`
+	DataPath   string `json:"data_path"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// ApprovalConfig 高危操作审批策略（key: approval_config, category: approval） 命中策略的操作需要另一位拥有相同权限的用户批准后才会执行
+type ApprovalConfig struct {
+	FixTask FixTaskApprovalRule `json:"fix_task"`
+	// Agent 更新推送
+	AgentPush bool `json:"agent_push"`
+	// 删除主机
+	HostDelete bool `json:"host_delete"`
+	// 删除策略（含批量删除）
+	PolicyDelete bool `json:"policy_delete"`
+}
+
+// ApprovalItem 审批单（附带当前用户能否审批）
+type ApprovalItem struct {
+	ID int64 `json:"id"`
+	// 操作标识，与审计日志 action 一致（如 fix.create）
+	Action string `json:"action"`
+	// 关联资源 ID（修复任务 ID、推送记录 ID、主机 ID、策略 ID）
+	ResourceID string `json:"resource_id"`
+	// 操作摘要，用于列表和通知
+	Summary string `json:"summary"`
+	// 触发审批的原因（命中的审批策略）
+	Reason string `json:"reason"`
+	// 目标主机或策略 ID
+	Targets []string `json:"targets,omitempty"`
+	// 目标主机所属业务线，审批人必须覆盖这些业务线
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 审批人需要的权限（与操作本身所需权限相同）
+	Permission string `json:"permission"`
+	// 可选值：pending_approval, approved, rejected, cancelled, failed
+	Status        string `json:"status"`
+	RequestedBy   string `json:"requested_by"`
+	ReviewedBy    string `json:"reviewed_by"`
+	ReviewComment string `json:"review_comment"`
+	// 格式 2006-01-02 15:04:05
+	ReviewedAt *string `json:"reviewed_at,omitempty"`
+	// 执行失败原因
+	ErrorMessage string `json:"error_message"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	CanReview bool   `json:"can_review"`
+}
+
+// ApprovalRequest 高危操作审批单 修复任务和 Agent 推送先以 pending_approval 状态创建，批准后转为 pending 由调度器下发； 删除主机和删除策略在批准后才执行
+type ApprovalRequest struct {
+	ID int64 `json:"id"`
+	// 操作标识，与审计日志 action 一致（如 fix.create）
+	Action string `json:"action"`
+	// 关联资源 ID（修复任务 ID、推送记录 ID、主机 ID、策略 ID）
+	ResourceID string `json:"resource_id"`
+	// 操作摘要，用于列表和通知
+	Summary string `json:"summary"`
+	// 触发审批的原因（命中的审批策略）
+	Reason string `json:"reason"`
+	// 目标主机或策略 ID
+	Targets []string `json:"targets,omitempty"`
+	// 目标主机所属业务线，审批人必须覆盖这些业务线
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 审批人需要的权限（与操作本身所需权限相同）
+	Permission string `json:"permission"`
+	// 可选值：pending_approval, approved, rejected, cancelled, failed
+	Status        string `json:"status"`
+	RequestedBy   string `json:"requested_by"`
+	ReviewedBy    string `json:"reviewed_by"`
+	ReviewComment string `json:"review_comment"`
+	// 格式 2006-01-02 15:04:05
+	ReviewedAt *string `json:"reviewed_at,omitempty"`
+	// 执行失败原因
+	ErrorMessage string `json:"error_message"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AssetRefreshHost 资产按需采集任务的主机明细（每台主机每个采集器一行）
+type AssetRefreshHost struct {
+	ID      int64  `json:"id"`
+	TaskID  int64  `json:"task_id"`
+	HostID  string `json:"host_id"`
+	Handler string `json:"handler"`
+	Status  string `json:"status"`
+	// 采集到的资产数量
+	Count        int64  `json:"count"`
+	ErrorMessage string `json:"error_message"`
+	// 格式 2006-01-02 15:04:05
+	DispatchedAt *string `json:"dispatched_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+}
+
+// AssetRefreshTask 资产按需采集任务（由 Manager 创建，AgentCenter 下发到 collector 插件）
+type AssetRefreshTask struct {
+	ID          int64    `json:"id"`
+	TargetHosts []string `json:"target_hosts,omitempty"`
+	// 采集器名称（process、port 等）
+	Handlers []string `json:"handlers,omitempty"`
+	// 可选值：pending, running, completed, partial, failed
+	Status string `json:"status"`
+	// 主机数 × 采集器数
+	TotalCount     int64  `json:"total_count"`
+	CompletedCount int64  `json:"completed_count"`
+	FailedCount    int64  `json:"failed_count"`
+	Message        string `json:"message"`
+	CreatedBy      string `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	DispatchedAt *string `json:"dispatched_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+}
+
+// AssetUser 账户资产模型（注意：与 User 用户模型区分开）
+type AssetUser struct {
+	ID          string `json:"id"`
+	HostID      string `json:"host_id"`
+	Username    string `json:"username"`
+	UID         string `json:"uid"`
+	GID         string `json:"gid"`
+	Groupname   string `json:"groupname"`
+	HomeDir     string `json:"home_dir"`
+	Shell       string `json:"shell"`
+	Comment     string `json:"comment"`
+	HasPassword bool   `json:"has_password"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// AuditChange 单个字段的变更前后值
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditLog 操作审计日志模型 只追加：不提供修改和删除接口；开启哈希链后每条记录的 Hash 覆盖上一条记录的 Hash，用于发现篡改和删除
+type AuditLog struct {
+	ID        int64  `json:"id"`
+	Actor     string `json:"actor"`
+	ActorRole string `json:"actor_role"`
+	SourceIP  string `json:"source_ip"`
+	// 操作，如 policy.update
+	Action string `json:"action"`
+	// 资源类型，如 policy
+	Resource   string   `json:"resource"`
+	TargetIds  []string `json:"target_ids,omitempty"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	StatusCode int64    `json:"status_code"`
+	// 可选值：success, failure
+	Result string `json:"result"`
+	// 失败原因
+	Message string                 `json:"message"`
+	Changes map[string]AuditChange `json:"changes,omitempty"`
+	// 通过 API Token 访问时的 Token ID
+	TokenID  int64  `json:"token_id"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+}
+
+// AuthConfig 登录认证配置（key: auth_config, category: auth）
+type AuthConfig struct {
+	// 是否允许本地账号密码登录
+	LocalLoginEnabled bool `json:"local_login_enabled"`
+	// 关闭本地登录后仍可用本地密码登录的应急账号
+	BreakGlassUsers []string `json:"break_glass_users,omitempty"`
+	// 目录组未匹配时的默认角色，为空表示拒绝登录
+	DefaultRole string     `json:"default_role"`
+	OIDC        OIDCConfig `json:"oidc"`
+	LDAP        LDAPConfig `json:"ldap"`
+	// 目录组到角色和业务线的映射，按顺序匹配
+	GroupMappings []GroupMapping `json:"group_mappings,omitempty"`
+	Session       SessionConfig  `json:"session"`
+	Lockout       LockoutConfig  `json:"lockout"`
+	// 仅对本地账号生效
+	PasswordPolicy PasswordPolicy `json:"password_policy"`
+	// 允许绑定 TOTP 两步验证的角色
+	TOTPRoles []string `json:"totp_roles,omitempty"`
+}
+
+// BatchAlertRequest 批量操作请求
+type BatchAlertRequest struct {
+	Ids    []int64 `json:"ids,omitempty"`
+	Reason string  `json:"reason"`
+}
+
+// BatchRefreshAssetsRequest 批量资产按需采集请求
+type BatchRefreshAssetsRequest struct {
+	HostIds []string `json:"host_ids,omitempty"`
+	// 采集器名称，为空表示全部
+	Handlers []string `json:"handlers,omitempty"`
+}
+
+// BusinessLine 业务线模型
+type BusinessLine struct {
+	ID int64 `json:"id"`
+	// 业务线名称
+	Name string `json:"name"`
+	// 业务线代码（唯一标识）
+	Code string `json:"code"`
+	// 描述
+	Description string `json:"description"`
+	// 负责人
+	Owner string `json:"owner"`
+	// 联系方式
+	Contact string `json:"contact"`
+	// 是否启用
+	Enabled bool `json:"enabled"`
+	// 关联主机数量（不存储，查询时计算）
+	HostCount int64 `json:"host_count"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// BusinessLineListItem 业务线列表项（包含主机数量）
+type BusinessLineListItem struct {
+	ID int64 `json:"id"`
+	// 业务线名称
+	Name string `json:"name"`
+	// 业务线代码（唯一标识）
+	Code string `json:"code"`
+	// 描述
+	Description string `json:"description"`
+	// 负责人
+	Owner string `json:"owner"`
+	// 联系方式
+	Contact string `json:"contact"`
+	// 是否启用
+	Enabled bool `json:"enabled"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	HostCount int64  `json:"host_count"`
+}
+
+// CategoryStats 类别统计（用于报告摘要）
+type CategoryStats struct {
+	// 类别英文标识
+	Category string `json:"category"`
+	// 类别中文名称
+	CategoryName string `json:"category_name"`
+	// 总检查项
+	TotalChecks int64 `json:"total_checks"`
+	// 通过项
+	PassedChecks int64 `json:"passed_checks"`
+	// 失败项
+	FailedChecks int64 `json:"failed_checks"`
+	// 通过率
+	PassRate float64 `json:"pass_rate"`
+}
+
+// ChangeDetail FIM 变更详情
+type ChangeDetail struct {
+	SizeBefore        string `json:"size_before"`
+	SizeAfter         string `json:"size_after"`
+	HashChanged       bool   `json:"hash_changed"`
+	PermissionChanged bool   `json:"permission_changed"`
+	OwnerChanged      bool   `json:"owner_changed"`
+	Attributes        string `json:"attributes"`
+}
+
+// ChangeFreezeItem 变更冻结列表项
+type ChangeFreezeItem struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// 格式 2006-01-02 15:04:05
+	StartAt string `json:"start_at"`
+	// 格式 2006-01-02 15:04:05
+	EndAt     string `json:"end_at"`
+	Enabled   bool   `json:"enabled"`
+	CreatedBy string `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 当前是否生效
+	Active bool `json:"active"`
+}
+
+// ChangeFreezeRequest 创建/更新变更冻结请求
+type ChangeFreezeRequest struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// 格式 2006-01-02 15:04:05
+	StartAt string `json:"start_at"`
+	// 格式 2006-01-02 15:04:05
+	EndAt   string `json:"end_at"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	// 还需符合认证配置中的密码策略
+	NewPassword string `json:"new_password"`
+}
+
+// CheckConfig 检查配置（JSON 格式）
+type CheckConfig struct {
+	// all/any/none
+	Condition string      `json:"condition"`
+	Rules     []CheckRule `json:"rules,omitempty"`
+}
+
+// CheckRule 单个检查规则
+type CheckRule struct {
+	Type   string   `json:"type"`
+	Param  []string `json:"param,omitempty"`
+	Result string   `json:"result"`
+}
+
+// ComplianceCoverage 合规与基线覆盖说明
+type ComplianceCoverage struct {
+	// 基线来源
+	BaselineSource string `json:"baseline_source"`
+	// 覆盖领域
+	CoveredAreas []string `json:"covered_areas,omitempty"`
+	// 未覆盖领域
+	UncoveredAreas []string `json:"uncovered_areas,omitempty"`
+	// 改进建议
+	ImprovementNote string `json:"improvement_note"`
+}
+
+// Component 组件表 存储组件的基本信息（agent、baseline、collector 等）
+type Component struct {
+	ID int64 `json:"id"`
+	// 组件名称 (agent, baseline, collector)
+	Name string `json:"name"`
+	// 组件分类 (agent, plugin)（可选值：agent, plugin）
+	Category string `json:"category"`
+	// 组件描述
+	Description string `json:"description"`
+	// 创建者
+	CreatedBy string `json:"created_by"`
+	// 创建时间
+	CreatedAt string `json:"created_at"`
+	// 更新时间
+	UpdatedAt string `json:"updated_at"`
+	// 关联
+	Versions []ComponentVersion `json:"versions,omitempty"`
+}
+
+// ComponentPackage 组件包表 存储组件版本的具体文件（不同平台、架构）
+type ComponentPackage struct {
+	ID int64 `json:"id"`
+	// 关联的版本 ID
+	VersionID int64 `json:"version_id"`
+	// 操作系统 (linux)
+	OS string `json:"os"`
+	// 架构 (amd64, arm64)
+	Arch string `json:"arch"`
+	// 包类型 (rpm, deb, binary)（可选值：rpm, deb, binary）
+	PkgType string `json:"pkg_type"`
+	// 文件存储路径
+	FilePath string `json:"file_path"`
+	// 原始文件名
+	FileName string `json:"file_name"`
+	// 文件大小 (字节)
+	FileSize int64 `json:"file_size"`
+	// 校验和
+	SHA256 string `json:"sha256"`
+	// 是否启用
+	Enabled bool `json:"enabled"`
+	// 上传用户
+	UploadedBy string `json:"uploaded_by"`
+	// 上传时间
+	UploadedAt string `json:"uploaded_at"`
+	// 关联
+	Version *ComponentVersion `json:"version,omitempty"`
+}
+
+// ComponentPushHost 组件推送主机记录表 记录每个主机的推送详细状态
+type ComponentPushHost struct {
+	ID int64 `json:"id"`
+	// 推送记录 ID
+	RecordID int64 `json:"record_id"`
+	// 主机 ID
+	HostID string `json:"host_id"`
+	// 主机名（冗余字段）
+	Hostname string `json:"hostname"`
+	// 推送状态（可选值：pending, waiting_window, success, failed）
+	Status string `json:"status"`
+	// 推送消息/错误信息
+	Message string `json:"message"`
+	// 推送时间
+	PushedAt *string `json:"pushed_at,omitempty"`
+	// 创建时间
+	CreatedAt string `json:"created_at"`
+	// 更新时间
+	UpdatedAt string `json:"updated_at"`
+	// 关联
+	PushRecord *ComponentPushRecord `json:"push_record,omitempty"`
+}
+
+// ComponentPushRecord 组件推送记录表 记录每次推送更新的操作
+type ComponentPushRecord struct {
+	ID int64 `json:"id"`
+	// 组件 ID
+	ComponentID int64 `json:"component_id"`
+	// 组件名称（冗余字段，方便查询）
+	ComponentName string `json:"component_name"`
+	// 推送的版本号
+	Version string `json:"version"`
+	// 推送目标类型：all/selected
+	TargetType string `json:"target_type"`
+	// 目标主机 ID 列表（如果 target_type=selected）
+	TargetHosts []string `json:"target_hosts,omitempty"`
+	// 推送状态（可选值：pending_approval, pending, pushing, success, failed, cancelled, rejected）
+	Status string `json:"status"`
+	// 总主机数
+	TotalCount int64 `json:"total_count"`
+	// 成功数
+	SuccessCount int64 `json:"success_count"`
+	// 失败数
+	FailedCount int64 `json:"failed_count"`
+	// 失败的主机 ID 列表
+	FailedHosts []string `json:"failed_hosts,omitempty"`
+	// 是否强制更新（即使版本相同也更新）
+	Force bool `json:"force"`
+	// 推送消息/备注
+	Message string `json:"message"`
+	// 创建者
+	CreatedBy string `json:"created_by"`
+	// 创建时间
+	CreatedAt string `json:"created_at"`
+	// 更新时间
+	UpdatedAt string `json:"updated_at"`
+	// 完成时间
+	CompletedAt *string `json:"completed_at,omitempty"`
+	// 放行原因，非空表示已紧急放行
+	EmergencyReason string `json:"emergency_reason"`
+	EmergencyBy     string `json:"emergency_by"`
+	// 格式 2006-01-02 15:04:05
+	EmergencyAt *string `json:"emergency_at,omitempty"`
+	// 关联
+	Component *Component `json:"component,omitempty"`
+}
+
+// ComponentVersion 组件版本表 存储组件的版本信息
+type ComponentVersion struct {
+	ID int64 `json:"id"`
+	// 关联的组件 ID
+	ComponentID int64 `json:"component_id"`
+	// 版本号 (1.0.0, 1.8.5.31)
+	Version string `json:"version"`
+	// 更新日志
+	Changelog string `json:"changelog"`
+	// 是否是最新版本
+	IsLatest bool `json:"is_latest"`
+	// 创建者/上传者
+	CreatedBy string `json:"created_by"`
+	// 创建时间
+	CreatedAt string `json:"created_at"`
+	// 更新时间
+	UpdatedAt string `json:"updated_at"`
+	// 关联
+	Component *Component         `json:"component,omitempty"`
+	Packages  []ComponentPackage `json:"packages,omitempty"`
+}
+
+// Container 容器资产模型
+type Container struct {
+	ID            string `json:"id"`
+	HostID        string `json:"host_id"`
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	Image         string `json:"image"`
+	ImageID       string `json:"image_id"`
+	// docker、containerd
+	Runtime string `json:"runtime"`
+	// running、stopped 等
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+	// 运行时详情（通过 CRI / Docker Engine API 采集）
+	PID          int64             `json:"pid"`
+	PodName      string            `json:"pod_name"`
+	PodNamespace string            `json:"pod_namespace"`
+	PodUID       string            `json:"pod_uid"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Privileged   bool              `json:"privileged"`
+	HostNetwork  bool              `json:"host_network"`
+	HostPID      bool              `json:"host_pid"`
+	HostIpc      bool              `json:"host_ipc"`
+	Capabilities []string          `json:"capabilities,omitempty"`
+	HostMounts   []string          `json:"host_mounts,omitempty"`
+}
+
+// CreateAPITokenRequest 创建 API Token 请求
+type CreateAPITokenRequest struct {
+	Name string `json:"name"`
+	// 限定的权限，必须是所属用户权限的子集，为空表示继承所属用户的全部权限
+	Permissions []string `json:"permissions,omitempty"`
+	// 有效天数，为 0 表示永不过期
+	ExpiresInDays int64 `json:"expires_in_days"`
+}
+
+// CreateAPITokenResponse 创建 API Token 响应，Token 明文只返回这一次
+type CreateAPITokenResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// 所属用户名（个人用户或服务账号），权限和业务线范围随所属用户
+	Owner string `json:"owner"`
+	// 明文前缀，用于识别 Token
+	Prefix string `json:"prefix"`
+	// 限定的权限（与所属用户权限取交集），为空表示继承所属用户的全部权限
+	Permissions []string `json:"permissions,omitempty"`
+	// 为空表示永不过期
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	LastUsedIP string  `json:"last_used_ip"`
+	// 格式 2006-01-02 15:04:05
+	RevokedAt *string `json:"revoked_at,omitempty"`
+	CreatedBy string  `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	Token     string `json:"token"`
+}
+
+// CreateBusinessLineRequest 创建业务线请求
+type CreateBusinessLineRequest struct {
+	// 业务线名称
+	Name string `json:"name"`
+	// 业务线代码
+	Code string `json:"code"`
+	// 描述
+	Description string `json:"description"`
+	// 负责人
+	Owner string `json:"owner"`
+	// 联系方式
+	Contact string `json:"contact"`
+	// 是否启用
+	Enabled bool `json:"enabled"`
+}
+
+// CreateComponentRequest 创建组件请求
+type CreateComponentRequest struct {
+	// 组件名称
+	Name string `json:"name"`
+	// 分类: agent, plugin
+	Category string `json:"category"`
+	// 描述
+	Description string `json:"description"`
+}
+
+// CreateFIMPolicyRequest 创建 FIM 策略请求
+type CreateFIMPolicyRequest struct {
+	Name               string       `json:"name"`
+	Description        string       `json:"description"`
+	WatchPaths         []WatchPath  `json:"watch_paths,omitempty"`
+	ExcludePaths       []string     `json:"exclude_paths,omitempty"`
+	CheckIntervalHours int64        `json:"check_interval_hours"`
+	TargetType         string       `json:"target_type"`
+	TargetConfig       TargetConfig `json:"target_config"`
+	Enabled            *bool        `json:"enabled,omitempty"`
+}
+
+// CreateFIMTaskRequest 创建 FIM 任务请求
+type CreateFIMTaskRequest struct {
+	PolicyID     string       `json:"policy_id"`
+	TargetType   string       `json:"target_type"`
+	TargetConfig TargetConfig `json:"target_config"`
+}
+
+// CreateFixTaskRequest 创建修复任务请求
+type CreateFixTaskRequest struct {
+	// 方式1：直接指定 result_ids（推荐，精确指定要修复的项）
+	ResultIds []string `json:"result_ids,omitempty"`
+	// 方式2：指定主机和规则ID（已废弃，会导致统计不准确）
+	HostIds    []string `json:"host_ids,omitempty"`
+	RuleIds    []string `json:"rule_ids,omitempty"`
+	Severities []string `json:"severities,omitempty"`
+	// 方式3：使用筛选条件（用于全选所有筛选结果）
+	UseFilters bool `json:"use_filters"`
+	// 业务线筛选
+	BusinessLine string `json:"business_line"`
+}
+
+// CreateNotificationRequest 创建通知请求
+type CreateNotificationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	// 可选值：lark, webhook
+	Type       string   `json:"type"`
+	Severities []string `json:"severities,omitempty"`
+	// 可选值：global, host_tags, business_line, specified
+	Scope       string             `json:"scope"`
+	ScopeValue  ScopeValueData     `json:"scope_value"`
+	FrontendURL string             `json:"frontend_url"`
+	Config      NotificationConfig `json:"config"`
+}
+
+// CreatePolicyGroupRequest 创建策略组请求
+type CreatePolicyGroupRequest struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Color       string `json:"color"`
+	SortOrder   int64  `json:"sort_order"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// CreatePolicyRequest 创建策略请求
+type CreatePolicyRequest struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	OSFamily    []string `json:"os_family,omitempty"`
+	OSVersion   string   `json:"os_version"`
+	// 详细 OS 版本要求
+	OSRequirements []OSRequirement `json:"os_requirements,omitempty"`
+	// 适用的运行时类型：["vm", "docker", "k8s"]
+	RuntimeTypes []string   `json:"runtime_types,omitempty"`
+	Enabled      bool       `json:"enabled"`
+	GroupID      string     `json:"group_id"`
+	Rules        []RuleData `json:"rules,omitempty"`
+}
+
+// CreateRoleRequest 创建角色请求
+type CreateRoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions,omitempty"`
+	// 业务线范围（业务线代码），为空表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+}
+
+// CreateRuleRequest 创建规则请求
+type CreateRuleRequest struct {
+	RuleID      string `json:"rule_id"`
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	// 可选，默认为 true
+	Enabled     *bool       `json:"enabled,omitempty"`
+	CheckConfig CheckConfig `json:"check_config"`
+	FixConfig   FixConfig   `json:"fix_config"`
+}
+
+// CreateTaskRequest 创建任务请求
+type CreateTaskRequest struct {
+	Name    string                 `json:"name"`
+	Type    string                 `json:"type"`
+	Targets map[string]interface{} `json:"targets,omitempty"`
+	// 兼容旧版本：单策略
+	PolicyID string `json:"policy_id"`
+	// 新版本：多策略
+	PolicyIds []string               `json:"policy_ids,omitempty"`
+	RuleIds   []string               `json:"rule_ids,omitempty"`
+	Schedule  map[string]interface{} `json:"schedule,omitempty"`
+}
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	// 服务账号不需要密码，其他用户必填
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Status   string `json:"status"`
+	// 业务线范围（业务线代码），为空表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 服务账号：不能登录，只能通过 API Token 访问
+	ServiceAccount bool `json:"service_account"`
+}
+
+// Cron 定时任务资产模型
+type Cron struct {
+	ID     string `json:"id"`
+	HostID string `json:"host_id"`
+	// root、username
+	User string `json:"user"`
+	// 调度表达式（* * * * *）
+	Schedule string `json:"schedule"`
+	// 执行的命令
+	Command string `json:"command"`
+	// crontab、systemd-timer
+	CronType string `json:"cron_type"`
+	// 是否启用
+	Enabled bool `json:"enabled"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// DisableTOTPRequest 停用两步验证请求
+type DisableTOTPRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// DiscoveredEndpoint 服务发现返回的接入点
+type DiscoveredEndpoint struct {
+	Address string `json:"address"`
+	Weight  int64  `json:"weight"`
+	Region  string `json:"region"`
+}
+
+// EmergencyOverrideRequest 紧急放行请求
+type EmergencyOverrideRequest struct {
+	// 放行原因（记录到任务和审计日志）
+	Reason string `json:"reason"`
+}
+
+// ExecutiveReportMeta 管理层报告元数据
+type ExecutiveReportMeta struct {
+	// 报告编号
+	ReportID string `json:"report_id"`
+	// 报告标题
+	ReportTitle string `json:"report_title"`
+	// 生成时间
+	GeneratedAt string `json:"generated_at"`
+	// 公司名称
+	CompanyName string `json:"company_name"`
+	// 基线类型
+	BaselineType string `json:"baseline_type"`
+	// 检查对象描述
+	CheckTarget string `json:"check_target"`
+}
+
+// ExecutiveSummary 执行摘要
+type ExecutiveSummary struct {
+	// 总体结论
+	OverallConclusion string `json:"overall_conclusion"`
+	// 检查范围描述
+	CheckScope string `json:"check_scope"`
+	// 合规率
+	ComplianceRate float64 `json:"compliance_rate"`
+	// 是否存在严重风险
+	HasCriticalRisk bool `json:"has_critical_risk"`
+	// 是否存在高危风险
+	HasHighRisk bool `json:"has_high_risk"`
+	// 结论陈述
+	ConclusionStatement string `json:"conclusion_statement"`
+	// 覆盖范围说明
+	CoverageNote string `json:"coverage_note"`
+}
+
+// ExecutiveTaskReport 管理层任务报告（完整版）
+type ExecutiveTaskReport struct {
+	Meta       ExecutiveReportMeta  `json:"meta"`
+	Summary    ExecutiveSummary     `json:"summary"`
+	TaskInfo   TaskReportSummary    `json:"task_info"`
+	Statistics TaskReportStatistics `json:"statistics"`
+	// 按类别统计（含通过率）
+	CategoryStats  []CategoryStats          `json:"category_stats,omitempty"`
+	SecurityScore  SecurityScore            `json:"security_score"`
+	HostDetails    []HostCheckDetail        `json:"host_details,omitempty"`
+	RiskItems      []RiskItem               `json:"risk_items,omitempty"`
+	FailedRules    []FailedRuleSummary      `json:"failed_rules,omitempty"`
+	Coverage       ComplianceCoverage       `json:"coverage"`
+	Recommendation ManagementRecommendation `json:"recommendation"`
+}
+
+// FIMEvent FIM 变更事件模型
+type FIMEvent struct {
+	EventID  string `json:"event_id"`
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	TaskID   string `json:"task_id"`
+	FilePath string `json:"file_path"`
+	// added/removed/changed
+	ChangeType   string       `json:"change_type"`
+	ChangeDetail ChangeDetail `json:"change_detail"`
+	Severity     string       `json:"severity"`
+	// binary/config/auth/log/other
+	Category string `json:"category"`
+	// 格式 2006-01-02 15:04:05
+	DetectedAt string `json:"detected_at"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+}
+
+// FIMEventStats FIM 事件统计响应
+type FIMEventStats struct {
+	Total    int64 `json:"total"`
+	Critical int64 `json:"critical"`
+	High     int64 `json:"high"`
+	Medium   int64 `json:"medium"`
+	Low      int64 `json:"low"`
+	// 按变更类型统计
+	Added   int64 `json:"added"`
+	Removed int64 `json:"removed"`
+	Changed int64 `json:"changed"`
+	// 按分类统计
+	ByCategory map[string]int64 `json:"by_category,omitempty"`
+	// Top 主机
+	TopHosts []FIMHostEventCount `json:"top_hosts,omitempty"`
+	// 趋势数据
+	Trend []FIMEventTrendPoint `json:"trend,omitempty"`
+}
+
+// FIMEventTrendPoint 事件趋势数据点
+type FIMEventTrendPoint struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// FIMHostEventCount 主机事件数统计
+type FIMHostEventCount struct {
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	Count    int64  `json:"count"`
+}
+
+// FIMPolicy FIM 策略模型
+type FIMPolicy struct {
+	PolicyID           string       `json:"policy_id"`
+	Name               string       `json:"name"`
+	Description        string       `json:"description"`
+	WatchPaths         []WatchPath  `json:"watch_paths,omitempty"`
+	ExcludePaths       []string     `json:"exclude_paths,omitempty"`
+	CheckIntervalHours int64        `json:"check_interval_hours"`
+	TargetType         string       `json:"target_type"`
+	TargetConfig       TargetConfig `json:"target_config"`
+	Enabled            bool         `json:"enabled"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// FIMTask FIM 任务模型
+type FIMTask struct {
+	TaskID   string `json:"task_id"`
+	PolicyID string `json:"policy_id"`
+	// pending/running/completed/failed
+	Status              string       `json:"status"`
+	TargetType          string       `json:"target_type"`
+	TargetConfig        TargetConfig `json:"target_config"`
+	DispatchedHostCount int64        `json:"dispatched_host_count"`
+	CompletedHostCount  int64        `json:"completed_host_count"`
+	TotalEvents         int64        `json:"total_events"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	ExecutedAt *string `json:"executed_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+}
+
+// FIMTaskHostStatus FIM 任务主机状态
+type FIMTaskHostStatus struct {
+	ID       int64  `json:"id"`
+	TaskID   string `json:"task_id"`
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	// dispatched/completed/timeout/failed
+	Status       string `json:"status"`
+	TotalEntries int64  `json:"total_entries"`
+	AddedCount   int64  `json:"added_count"`
+	RemovedCount int64  `json:"removed_count"`
+	ChangedCount int64  `json:"changed_count"`
+	RunTimeSec   int64  `json:"run_time_sec"`
+	ErrorMessage string `json:"error_message"`
+	// 格式 2006-01-02 15:04:05
+	DispatchedAt *string `json:"dispatched_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+}
+
+// FailedRuleSummary 失败规则汇总
+type FailedRuleSummary struct {
+	RuleID        string   `json:"rule_id"`
+	Title         string   `json:"title"`
+	Severity      string   `json:"severity"`
+	Category      string   `json:"category"`
+	AffectedHosts []string `json:"affected_hosts,omitempty"`
+	AffectedCount int64    `json:"affected_count"`
+	FixSuggestion string   `json:"fix_suggestion"`
+	Expected      string   `json:"expected"`
+}
+
+// FixConfig 修复配置（JSON 格式）
+type FixConfig struct {
+	Suggestion string `json:"suggestion"`
+	Command    string `json:"command"`
+	// 修复后需重启的服务
+	RestartServices []string `json:"restart_services,omitempty"`
+}
+
+// FixResultResponse 修复结果响应
+type FixResultResponse struct {
+	ResultID string `json:"result_id"`
+	TaskID   string `json:"task_id"`
+	HostID   string `json:"host_id"`
+	RuleID   string `json:"rule_id"`
+	// 可选值：success, failed, skipped
+	Status   string `json:"status"`
+	Command  string `json:"command"`
+	Output   string `json:"output"`
+	ErrorMsg string `json:"error_msg"`
+	Message  string `json:"message"`
+	// 格式 2006-01-02 15:04:05
+	FixedAt  string `json:"fixed_at"`
+	Hostname string `json:"hostname"`
+	Title    string `json:"title"`
+}
+
+// FixRuleSnapshot 修复任务中一条规则的快照
+type FixRuleSnapshot struct {
+	RuleID       string      `json:"rule_id"`
+	PolicyID     string      `json:"policy_id"`
+	Category     string      `json:"category"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	Severity     string      `json:"severity"`
+	Enabled      bool        `json:"enabled"`
+	RuntimeTypes []string    `json:"runtime_types,omitempty"`
+	Check        CheckConfig `json:"check"`
+	Fix          FixConfig   `json:"fix"`
+}
+
+// FixTask 修复任务模型
+type FixTask struct {
+	TaskID     string   `json:"task_id"`
+	HostIds    []string `json:"host_ids,omitempty"`
+	RuleIds    []string `json:"rule_ids,omitempty"`
+	Severities []string `json:"severities,omitempty"`
+	// 目标主机所属业务线（用于按业务线范围过滤）
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 可选值：pending_approval, pending, running, completed, failed, rejected
+	Status       string `json:"status"`
+	TotalCount   int64  `json:"total_count"`
+	SuccessCount int64  `json:"success_count"`
+	FailedCount  int64  `json:"failed_count"`
+	// 进度百分比 0-100
+	Progress  int64  `json:"progress"`
+	CreatedBy string `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+	// 创建任务时的规则快照（含修复命令），下发时使用快照而不是规则的当前内容， 避免审批通过后修改规则的修复命令；为空时（旧任务）使用规则的当前内容
+	RuleSnapshot []FixRuleSnapshot `json:"rule_snapshot,omitempty"`
+	// 放行原因，非空表示已紧急放行
+	EmergencyReason string `json:"emergency_reason"`
+	EmergencyBy     string `json:"emergency_by"`
+	// 格式 2006-01-02 15:04:05
+	EmergencyAt *string `json:"emergency_at,omitempty"`
+}
+
+// FixTaskApprovalRule 修复任务审批规则，主机数和严重级别满足其一即需要审批
+type FixTaskApprovalRule struct {
+	Enabled bool `json:"enabled"`
+	// 目标主机数超过该值时需要审批，0 表示所有修复任务都需要审批
+	HostThreshold int64 `json:"host_threshold"`
+	// 修复的规则包含这些严重级别时需要审批
+	Severities []string `json:"severities,omitempty"`
+}
+
+// FixTaskHostStatus 修复任务主机执行状态
+type FixTaskHostStatus struct {
+	ID       int64  `json:"id"`
+	TaskID   string `json:"task_id"`
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	// 冗余存储主机信息，避免主机删除后数据丢失
+	IPAddress string `json:"ip_address"`
+	// 业务线
+	BusinessLine string `json:"business_line"`
+	// OS 系列（如 rocky, ubuntu）
+	OSFamily string `json:"os_family"`
+	// OS 版本
+	OSVersion string `json:"os_version"`
+	// 运行时类型（vm, docker, k8s）
+	RuntimeType string `json:"runtime_type"`
+	// waiting_window, dispatched, completed, timeout, failed
+	Status string `json:"status"`
+	// 等待维护窗口的原因（窗口未开启或变更冻结中）
+	WaitingReason string `json:"waiting_reason"`
+	// 格式 2006-01-02 15:04:05
+	DispatchedAt *string `json:"dispatched_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt  *string `json:"completed_at,omitempty"`
+	ErrorMessage string  `json:"error_message"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+// FixableItemResponse 可修复项响应
+type FixableItemResponse struct {
+	ResultID      string `json:"result_id"`
+	HostID        string `json:"host_id"`
+	Hostname      string `json:"hostname"`
+	IP            string `json:"ip"`
+	BusinessLine  string `json:"business_line"`
+	RuleID        string `json:"rule_id"`
+	Title         string `json:"title"`
+	Category      string `json:"category"`
+	Severity      string `json:"severity"`
+	FixSuggestion string `json:"fix_suggestion"`
+	FixCommand    string `json:"fix_command"`
+	Actual        string `json:"actual"`
+	Expected      string `json:"expected"`
+	HasFix        bool   `json:"has_fix"`
+}
+
+// GroupMapping 目录组映射
+type GroupMapping struct {
+	// 组名（不区分大小写）
+	Group string `json:"group"`
+	// 映射的角色
+	Role string `json:"role"`
+	// 可访问的业务线，为空表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+}
+
+// GroupWithStats 为每个策略组计算统计信息
+type GroupWithStats struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 图标（可选）
+	Icon string `json:"icon"`
+	// 颜色（可选）
+	Color string `json:"color"`
+	// 排序顺序
+	SortOrder int64 `json:"sort_order"`
+	Enabled   bool  `json:"enabled"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 关联关系 - 策略组下的策略
+	Policies    []Policy `json:"policies,omitempty"`
+	PolicyCount int64    `json:"policy_count"`
+	RuleCount   int64    `json:"rule_count"`
+	PassRate    float64  `json:"pass_rate"`
+	HostCount   int64    `json:"host_count"`
+}
+
+// HealthResponse 健康检查响应
+type HealthResponse struct {
+	// 总体状态: "ok" 或 "degraded"
+	Status string `json:"status"`
+	// 检查时间戳
+	Timestamp string `json:"timestamp"`
+	// 各项检查结果
+	Checks map[string]string `json:"checks,omitempty"`
+	// 版本信息（可选）
+	Version string `json:"version"`
+}
+
+// Host 主机信息模型
+type Host struct {
+	HostID        string   `json:"host_id"`
+	Hostname      string   `json:"hostname"`
+	OSFamily      string   `json:"os_family"`
+	OSVersion     string   `json:"os_version"`
+	KernelVersion string   `json:"kernel_version"`
+	Arch          string   `json:"arch"`
+	IPv4          []string `json:"ipv4,omitempty"`
+	IPv6          []string `json:"ipv6,omitempty"`
+	// 公网IPv4地址
+	PublicIPv4 []string `json:"public_ipv4,omitempty"`
+	// 公网IPv6地址
+	PublicIPv6 []string `json:"public_ipv6,omitempty"`
+	// 可选值：online, offline
+	Status string `json:"status"`
+	// 格式 2006-01-02 15:04:05
+	LastHeartbeat *string `json:"last_heartbeat,omitempty"`
+	// 硬件信息
+	DeviceModel  string `json:"device_model"`
+	Manufacturer string `json:"manufacturer"`
+	DeviceSerial string `json:"device_serial"`
+	// 设备ID（可能与host_id相同）
+	DeviceID   string `json:"device_id"`
+	CPUInfo    string `json:"cpu_info"`
+	MemorySize string `json:"memory_size"`
+	SystemLoad string `json:"system_load"`
+	// 网络信息
+	DefaultGateway string   `json:"default_gateway"`
+	DNSServers     []string `json:"dns_servers,omitempty"`
+	NetworkMode    string   `json:"network_mode"`
+	// 磁盘信息（JSON 格式，存储磁盘列表）
+	DiskInfo string `json:"disk_info"`
+	// 网卡信息（JSON 格式，存储网卡列表）
+	NetworkInterfaces string `json:"network_interfaces"`
+	// 业务信息
+	BusinessLine string `json:"business_line"`
+	// 时间信息
+	SystemBootTime *string `json:"system_boot_time,omitempty"`
+	// 客户端启动时间
+	AgentStartTime *string `json:"agent_start_time,omitempty"`
+	// 最近活跃时间
+	LastActiveTime *string `json:"last_active_time,omitempty"`
+	// 运行时环境（可选值：vm, docker, k8s）
+	RuntimeType string `json:"runtime_type"`
+	// 是否为容器环境
+	IsContainer bool `json:"is_container"`
+	// 容器ID（如果是在容器中运行）
+	ContainerID string `json:"container_id"`
+	// K8s 相关字段
+	PodName string `json:"pod_name"`
+	// 命名空间（K8s 环境）
+	PodNamespace string `json:"pod_namespace"`
+	// Pod UID（K8s 环境）
+	PodUID string `json:"pod_uid"`
+	// Agent 版本信息
+	AgentVersion string `json:"agent_version"`
+	// Agent 资源保护状态
+	GuardState string `json:"guard_state"`
+	// JSON 对象，包含原因、截止时间和资源占用
+	GuardStatus string `json:"guard_status"`
+	// 标签
+	Tags []string `json:"tags,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// HostCheckDetail 主机检查明细
+type HostCheckDetail struct {
+	HostID       string  `json:"host_id"`
+	Hostname     string  `json:"hostname"`
+	IP           string  `json:"ip"`
+	OSFamily     string  `json:"os_family"`
+	PassedCount  int64   `json:"passed_count"`
+	FailedCount  int64   `json:"failed_count"`
+	WarningCount int64   `json:"warning_count"`
+	NaCount      int64   `json:"na_count"`
+	Score        float64 `json:"score"`
+	// pass/warning/fail
+	Status        string `json:"status"`
+	CriticalFails int64  `json:"critical_fails"`
+	HighFails     int64  `json:"high_fails"`
+}
+
+// HostListItem 主机列表项（包含基线得分）
+type HostListItem struct {
+	HostID        string   `json:"host_id"`
+	Hostname      string   `json:"hostname"`
+	OSFamily      string   `json:"os_family"`
+	OSVersion     string   `json:"os_version"`
+	KernelVersion string   `json:"kernel_version"`
+	Arch          string   `json:"arch"`
+	IPv4          []string `json:"ipv4,omitempty"`
+	IPv6          []string `json:"ipv6,omitempty"`
+	// 公网IPv4地址
+	PublicIPv4 []string `json:"public_ipv4,omitempty"`
+	// 公网IPv6地址
+	PublicIPv6 []string `json:"public_ipv6,omitempty"`
+	// 可选值：online, offline
+	Status string `json:"status"`
+	// 格式 2006-01-02 15:04:05
+	LastHeartbeat *string `json:"last_heartbeat,omitempty"`
+	// 硬件信息
+	DeviceModel  string `json:"device_model"`
+	Manufacturer string `json:"manufacturer"`
+	DeviceSerial string `json:"device_serial"`
+	// 设备ID（可能与host_id相同）
+	DeviceID   string `json:"device_id"`
+	CPUInfo    string `json:"cpu_info"`
+	MemorySize string `json:"memory_size"`
+	SystemLoad string `json:"system_load"`
+	// 网络信息
+	DefaultGateway string   `json:"default_gateway"`
+	DNSServers     []string `json:"dns_servers,omitempty"`
+	NetworkMode    string   `json:"network_mode"`
+	// 磁盘信息（JSON 格式，存储磁盘列表）
+	DiskInfo string `json:"disk_info"`
+	// 网卡信息（JSON 格式，存储网卡列表）
+	NetworkInterfaces string `json:"network_interfaces"`
+	// 业务信息
+	BusinessLine string `json:"business_line"`
+	// 时间信息
+	SystemBootTime *string `json:"system_boot_time,omitempty"`
+	// 客户端启动时间
+	AgentStartTime *string `json:"agent_start_time,omitempty"`
+	// 最近活跃时间
+	LastActiveTime *string `json:"last_active_time,omitempty"`
+	// 运行时环境（可选值：vm, docker, k8s）
+	RuntimeType string `json:"runtime_type"`
+	// 是否为容器环境
+	IsContainer bool `json:"is_container"`
+	// 容器ID（如果是在容器中运行）
+	ContainerID string `json:"container_id"`
+	// K8s 相关字段
+	PodName string `json:"pod_name"`
+	// 命名空间（K8s 环境）
+	PodNamespace string `json:"pod_namespace"`
+	// Pod UID（K8s 环境）
+	PodUID string `json:"pod_uid"`
+	// Agent 版本信息
+	AgentVersion string `json:"agent_version"`
+	// Agent 资源保护状态
+	GuardState string `json:"guard_state"`
+	// JSON 对象，包含原因、截止时间和资源占用
+	GuardStatus string `json:"guard_status"`
+	// 标签
+	Tags []string `json:"tags,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt        string  `json:"updated_at"`
+	BaselineScore    int64   `json:"baseline_score"`
+	BaselinePassRate float64 `json:"baseline_pass_rate"`
+}
+
+// HostMetrics 是主机监控数据
+type HostMetrics struct {
+	HostID string `json:"host_id"`
+	// 最新监控数据
+	Latest *LatestMetrics `json:"latest,omitempty"`
+	// 时间序列数据
+	TimeSeries *TimeSeriesMetrics `json:"time_series,omitempty"`
+	// 数据源：mysql 或 prometheus
+	Source string `json:"source"`
+}
+
+// HostPluginResponse 主机插件响应
+type HostPluginResponse struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Status        string `json:"status"`
+	StartTime     string `json:"start_time"`
+	UpdatedAt     string `json:"updated_at"`
+	LatestVersion string `json:"latest_version"`
+	NeedUpdate    bool   `json:"need_update"`
+	// 资源限制触发次数
+	LimitHits *PluginLimitHits `json:"limit_hits,omitempty"`
+}
+
+// HostRiskDistribution 主机基线风险分布统计（按严重程度）
+type HostRiskDistribution struct {
+	// 存在严重风险基线的主机数
+	Critical int64 `json:"critical"`
+	// 存在高危风险基线的主机数
+	High int64 `json:"high"`
+	// 存在中危风险基线的主机数
+	Medium int64 `json:"medium"`
+	// 存在低危风险基线的主机数
+	Low int64 `json:"low"`
+}
+
+// HostRiskStatistics 主机风险统计
+type HostRiskStatistics struct {
+	// 安全告警统计
+	Alerts *HostRiskStatisticsAlerts `json:"alerts,omitempty"`
+	// 漏洞风险统计
+	Vulnerabilities *HostRiskStatisticsVulnerabilities `json:"vulnerabilities,omitempty"`
+	// 基线风险统计
+	Baseline *HostRiskStatisticsBaseline `json:"baseline,omitempty"`
+}
+
+// HostRiskStatisticsAlerts 安全告警统计
+type HostRiskStatisticsAlerts struct {
+	// 未处理告警总数
+	Total int64 `json:"total"`
+	// 严重
+	Critical int64 `json:"critical"`
+	// 高危
+	High int64 `json:"high"`
+	// 中危
+	Medium int64 `json:"medium"`
+	// 低危
+	Low int64 `json:"low"`
+}
+
+// HostRiskStatisticsVulnerabilities 漏洞风险统计
+type HostRiskStatisticsVulnerabilities struct {
+	// 未处理高可利用漏洞总数
+	Total int64 `json:"total"`
+	// 严重
+	Critical int64 `json:"critical"`
+	// 高危
+	High int64 `json:"high"`
+	// 中危
+	Medium int64 `json:"medium"`
+	// 低危
+	Low int64 `json:"low"`
+}
+
+// HostRiskStatisticsBaseline 基线风险统计
+type HostRiskStatisticsBaseline struct {
+	// 待加固基线总数
+	Total int64 `json:"total"`
+	// 严重（基线中通常没有critical，但保留字段）
+	Critical int64 `json:"critical"`
+	// 高危
+	High int64 `json:"high"`
+	// 中危
+	Medium int64 `json:"medium"`
+	// 低危
+	Low int64 `json:"low"`
+}
+
+// HostStatusDistribution 主机状态分布统计
+type HostStatusDistribution struct {
+	// 运行中
+	Running int64 `json:"running"`
+	// 运行异常
+	Abnormal int64 `json:"abnormal"`
+	// 离线
+	Offline int64 `json:"offline"`
+	// 未安装
+	NotInstalled int64 `json:"not_installed"`
+	// 已卸载
+	Uninstalled int64 `json:"uninstalled"`
+}
+
+// InspectionHostItem 巡检主机项
+type InspectionHostItem struct {
+	HostID   string   `json:"host_id"`
+	Hostname string   `json:"hostname"`
+	IPv4     []string `json:"ipv4,omitempty"`
+	// 可选值：online, offline
+	Status       string `json:"status"`
+	AgentVersion string `json:"agent_version"`
+	// 格式 2006-01-02 15:04:05
+	AgentStartTime *string `json:"agent_start_time,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	SystemBootTime *string `json:"system_boot_time,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	LastHeartbeat *string        `json:"last_heartbeat,omitempty"`
+	OSFamily      string         `json:"os_family"`
+	OSVersion     string         `json:"os_version"`
+	Arch          string         `json:"arch"`
+	RuntimeType   string         `json:"runtime_type"`
+	BusinessLine  string         `json:"business_line"`
+	Plugins       []PluginStatus `json:"plugins,omitempty"`
+}
+
+// InspectionOverviewResponse 巡检概览响应
+type InspectionOverviewResponse struct {
+	Summary              InspectionSummary    `json:"summary"`
+	LatestAgentVersion   string               `json:"latest_agent_version"`
+	LatestPluginVersions map[string]string    `json:"latest_plugin_versions,omitempty"`
+	Hosts                []InspectionHostItem `json:"hosts,omitempty"`
+}
+
+// InspectionSummary 巡检统计摘要
+type InspectionSummary struct {
+	TotalHosts          int64 `json:"total_hosts"`
+	OnlineHosts         int64 `json:"online_hosts"`
+	OfflineHosts        int64 `json:"offline_hosts"`
+	AgentOutdatedCount  int64 `json:"agent_outdated_count"`
+	PluginErrorCount    int64 `json:"plugin_error_count"`
+	PluginOutdatedCount int64 `json:"plugin_outdated_count"`
+}
+
+// IssueHostKeyRequest 生成离线主机签名密钥请求
+type IssueHostKeyRequest struct {
+	// 离线主机的 Agent ID（mxsec-agent --print-id 输出）
+	HostID string `json:"host_id"`
+}
+
+// IssueHostKeyResponse 离线主机签名密钥
+type IssueHostKeyResponse struct {
+	HostID string `json:"host_id"`
+	// 写入离线主机 --key 指定的密钥文件
+	Key string `json:"key"`
+}
+
+// Kmod 内核模块资产模型
+type Kmod struct {
+	ID         string `json:"id"`
+	HostID     string `json:"host_id"`
+	ModuleName string `json:"module_name"`
+	// 模块大小（字节）
+	Size int64 `json:"size"`
+	// 引用计数
+	UsedBy int64 `json:"used_by"`
+	// Live、Loading、Unloading
+	State string `json:"state"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// KubernetesImageConfig Kubernetes 镜像配置
+type KubernetesImageConfig struct {
+	// 镜像仓库地址
+	Repository string `json:"repository"`
+	// 可用版本列表
+	Versions []string `json:"versions,omitempty"`
+	// 默认版本
+	DefaultVersion string `json:"default_version"`
+}
+
+// LDAPConfig LDAP 绑定/搜索登录配置
+type LDAPConfig struct {
+	Enabled bool `json:"enabled"`
+	// 如 ldap://ldap.example.com:389 或 ldaps://ldap.example.com:636
+	URL                string `json:"url"`
+	StartTLS           bool   `json:"start_tls"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	// 搜索用户使用的服务账号
+	BindDn       string `json:"bind_dn"`
+	BindPassword string `json:"bind_password"`
+	BaseDn       string `json:"base_dn"`
+	// {username} 替换为转义后的登录名
+	UserFilter   string `json:"user_filter"`
+	UsernameAttr string `json:"username_attr"`
+	EmailAttr    string `json:"email_attr"`
+	// 用户条目上的组属性（如 memberOf），取值为组 DN 时使用第一个 RDN 的值
+	GroupAttr string `json:"group_attr"`
+	// 为空时不搜索组条目
+	GroupBaseDn string `json:"group_base_dn"`
+	// {dn} 替换为用户 DN，{username} 替换为登录名
+	GroupFilter   string `json:"group_filter"`
+	GroupNameAttr string `json:"group_name_attr"`
+}
+
+// LatestMetrics 是最新监控数据
+type LatestMetrics struct {
+	CPUUsage     *float64 `json:"cpu_usage,omitempty"`
+	MemUsage     *float64 `json:"mem_usage,omitempty"`
+	DiskUsage    *float64 `json:"disk_usage,omitempty"`
+	NetBytesSent *int64   `json:"net_bytes_sent,omitempty"`
+	NetBytesRecv *int64   `json:"net_bytes_recv,omitempty"`
+	CollectedAt  *string  `json:"collected_at,omitempty"`
+}
+
+// ListUsersResponse 用户列表响应
+type ListUsersResponse struct {
+	Total int64  `json:"total"`
+	Items []User `json:"items,omitempty"`
+}
+
+// LockoutConfig 登录失败锁定配置，次数为 0 表示不限制
+type LockoutConfig struct {
+	// 同一用户在锁定时长内允许的失败次数
+	MaxUserFailures int64 `json:"max_user_failures"`
+	// 同一来源 IP 在锁定时长内允许的失败次数
+	MaxIPFailures int64 `json:"max_ip_failures"`
+	// 失败次数的统计窗口，也是锁定时长
+	LockMinutes int64 `json:"lock_minutes"`
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// 认证方式，默认 local
+	Provider string `json:"provider"`
+	// 已启用两步验证的本地账号需提交动态验证码
+	TOTPCode string `json:"totp_code"`
+}
+
+// LoginResponse 登录响应
+type LoginResponse struct {
+	// 访问令牌（JWT）
+	Token string `json:"token"`
+	// 刷新令牌，每次刷新后轮换
+	RefreshToken string `json:"refresh_token"`
+	// 访问令牌有效期（秒）
+	ExpiresIn int64              `json:"expires_in"`
+	User      *LoginResponseUser `json:"user,omitempty"`
+}
+
+// LoginResponseUser LoginResponse 的 user 字段
+type LoginResponseUser struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// LogoutRequest 登出请求，访问令牌已过期时通过刷新令牌定位会话
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// MaintenanceWindowItem 维护窗口列表项（包含当前开启状态）
+type MaintenanceWindowItem struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 窗口开始时间（5 段 cron：分 时 日 月 周）
+	Cron string `json:"cron"`
+	// 窗口持续时长（分钟）
+	DurationMinutes int64 `json:"duration_minutes"`
+	// cron 所在时区（IANA 名称，如 Asia/Shanghai）
+	Timezone string `json:"timezone"`
+	// 适用范围（可选值：global, business_line, host_tags, hosts）
+	Scope string `json:"scope"`
+	// 业务线、标签或主机 ID
+	ScopeValues []string `json:"scope_values,omitempty"`
+	Enabled     bool     `json:"enabled"`
+	CreatedBy   string   `json:"created_by"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 当前是否开启
+	Open bool `json:"open"`
+	// 开启时：本次窗口结束时间
+	EndsAt *string `json:"ends_at,omitempty"`
+	// 未开启时：下次开启时间
+	NextStart *string `json:"next_start,omitempty"`
+}
+
+// MaintenanceWindowRequest 创建/更新维护窗口请求
+type MaintenanceWindowRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 窗口开始时间（5 段 cron）
+	Cron string `json:"cron"`
+	// 窗口持续时长（分钟）
+	DurationMinutes int64 `json:"duration_minutes"`
+	// 默认 UTC
+	Timezone string `json:"timezone"`
+	// 可选值：global, business_line, host_tags, hosts
+	Scope       string   `json:"scope"`
+	ScopeValues []string `json:"scope_values,omitempty"`
+	Enabled     bool     `json:"enabled"`
+}
+
+// ManagementRecommendation 管理建议
+type ManagementRecommendation struct {
+	// 总体评估
+	OverallAssessment string `json:"overall_assessment"`
+	// 行动建议
+	ActionSuggestions []string `json:"action_suggestions,omitempty"`
+	// 声明
+	Disclaimer string `json:"disclaimer"`
+}
+
+// NetInterface 网络接口资产模型
+type NetInterface struct {
+	ID     string `json:"id"`
+	HostID string `json:"host_id"`
+	// eth0、ens33 等
+	InterfaceName string `json:"interface_name"`
+	MacAddress    string `json:"mac_address"`
+	// JSON 数组
+	IPv4Addresses []string `json:"ipv4_addresses,omitempty"`
+	// JSON 数组
+	IPv6Addresses []string `json:"ipv6_addresses,omitempty"`
+	Mtu           int64    `json:"mtu"`
+	// up、down
+	State string `json:"state"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// Notification 通知配置模型
+type Notification struct {
+	ID int64 `json:"id"`
+	// 通知名称（自定义名称）
+	Name string `json:"name"`
+	// 描述
+	Description string `json:"description"`
+	// 通知类别（baseline_alert/agent_offline）（可选值：baseline_alert, agent_offline, approval）
+	NotifyCategory string `json:"notify_category"`
+	// 是否启用
+	Enabled bool `json:"enabled"`
+	// 通知类型（lark/webhook）（可选值：lark, webhook）
+	Type string `json:"type"`
+	// 通知等级（仅基线告警：critical、high、medium、low）
+	Severities []string `json:"severities,omitempty"`
+	// 主机范围类型（可选值：global, host_tags, business_line, specified）
+	Scope string `json:"scope"`
+	// 主机范围值（JSON，根据 scope 类型存储不同数据）
+	ScopeValue string `json:"scope_value"`
+	// 前端地址（告警带上告警uri）
+	FrontendURL string `json:"frontend_url"`
+	// 通知配置（Webhook URL、Secret 等）
+	Config NotificationConfig `json:"config"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// NotificationConfig 通知配置（JSON 格式）
+type NotificationConfig struct {
+	// Lark 配置
+	WebhookURL string `json:"webhook_url"`
+	// Secret（用于签名验证）
+	Secret string `json:"secret"`
+	// 用户备注
+	UserNotes string `json:"user_notes"`
+}
+
+// OIDCConfig OIDC 授权码登录配置
+type OIDCConfig struct {
+	Enabled bool `json:"enabled"`
+	// 登录页按钮名称
+	DisplayName string `json:"display_name"`
+	// IdP 地址，用于发现 /.well-known/openid-configuration
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// 回调地址，如 https://mxsec.example.com/api/v1/auth/oidc/callback
+	RedirectURL string `json:"redirect_url"`
+	// 额外的 scope（openid 自动添加）
+	Scopes []string `json:"scopes,omitempty"`
+	// 用户名 claim
+	UsernameClaim string `json:"username_claim"`
+	// 邮箱 claim
+	EmailClaim string `json:"email_claim"`
+	// 组 claim（字符串或字符串数组）
+	GroupsClaim string `json:"groups_claim"`
+}
+
+// OSRequirement OS 版本要求（详细）
+type OSRequirement struct {
+	// rocky, centos, debian 等
+	OSFamily string `json:"os_family"`
+	// 最小版本（含）
+	MinVersion string `json:"min_version"`
+	// 最大版本（含）
+	MaxVersion string `json:"max_version"`
+}
+
+// PasswordPolicy 密码策略
+type PasswordPolicy struct {
+	MinLength int64 `json:"min_length"`
+	// 至少包含的字符类型数（大写字母、小写字母、数字、符号）
+	MinClasses int64 `json:"min_classes"`
+	// 不能与最近 N 个密码（含当前密码）相同，0 表示不限制
+	HistoryCount int64 `json:"history_count"`
+}
+
+// PermissionInfo 是权限说明（用于角色编辑界面）
+type PermissionInfo struct {
+	// 可选值：hosts:read, hosts:manage, assets:read, policies:read, policies:manage, tasks:read, tasks:execute, fix:read, fix:execute, alerts:read, alerts:manage, reports:read, fim:read, fim:manage, components:read, components:manage, components:release, business_lines:read, business_lines:manage, notifications:read, notifications:manage, system:read, system:manage, users:read, users:manage, audit:read
+	Permission  string `json:"permission"`
+	Group       string `json:"group"`
+	Description string `json:"description"`
+}
+
+// PluginConfigDeferralItem 等待维护窗口的插件配置下发主机
+type PluginConfigDeferralItem struct {
+	HostID string `json:"host_id"`
+	// 等待原因
+	Reason string `json:"reason"`
+	// 开始等待时间
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 放行原因，非空表示已紧急放行
+	EmergencyReason string `json:"emergency_reason"`
+	EmergencyBy     string `json:"emergency_by"`
+	// 格式 2006-01-02 15:04:05
+	EmergencyAt *string `json:"emergency_at,omitempty"`
+	Hostname    string  `json:"hostname"`
+}
+
+// PluginDeferralOverrideRequest 插件配置紧急放行请求
+type PluginDeferralOverrideRequest struct {
+	// 放行原因（记录到暂缓记录和审计日志）
+	Reason string `json:"reason"`
+	// 放行的主机，为空时放行当前用户范围内所有等待的主机
+	HostIds []string `json:"host_ids,omitempty"`
+}
+
+// PluginLimitHits 插件触发资源限制的累计次数（由 Agent 心跳上报，插件重启后清零）
+type PluginLimitHits struct {
+	// 内存达到上限的次数
+	MemoryMax int64 `json:"memory_max"`
+	// OOM Kill 次数
+	OomKill int64 `json:"oom_kill"`
+	// CPU 被限流的周期数
+	CPUThrottled int64 `json:"cpu_throttled"`
+	// CPU 被限流的总时长（微秒）
+	CPUThrottledUsec int64 `json:"cpu_throttled_usec"`
+	// 达到进程数上限的次数
+	PidsMax int64 `json:"pids_max"`
+}
+
+// PluginLimits 插件资源限制（Agent 为每个插件创建独立的 cgroup v2 实施，0 表示不限制）
+type PluginLimits struct {
+	// CPU 配额（单核百分比，200 表示两个核）
+	CPUPercent int32 `json:"cpu_percent"`
+	// 内存上限（MB）
+	MemoryMaxMb int64 `json:"memory_max_mb"`
+	// IO 权重（1-10000，0 表示默认权重 100）
+	IoWeight int32 `json:"io_weight"`
+	// 最大进程/线程数
+	PidsMax int32 `json:"pids_max"`
+}
+
+// PluginSandbox 插件进程加固选项（变更后 Agent 重启插件）
+type PluginSandbox struct {
+	// 禁止通过 setuid 等方式提权
+	NoNewPrivs bool `json:"no_new_privs"`
+	// 丢弃的 capabilities，ALL 表示全部丢弃
+	DropCapabilities []string `json:"drop_capabilities,omitempty"`
+	// 仅传递最小环境变量
+	MinimalEnv bool `json:"minimal_env"`
+}
+
+// PluginStatus
+type PluginStatus struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	ConfigVersion  string   `json:"config_version"`
+	ConfigSHA256   string   `json:"config_sha256"`
+	ConfigEnabled  bool     `json:"config_enabled"`
+	DownloadUrls   []string `json:"download_urls,omitempty"`
+	Description    string   `json:"description"`
+	HasPackage     bool     `json:"has_package"`
+	PackageVersion string   `json:"package_version"`
+	PackageArch    string   `json:"package_arch"`
+	Status         string   `json:"status"`
+}
+
+// Policy 策略集模型
+type Policy struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	// 简单 OS 列表（向后兼容）
+	OSFamily []string `json:"os_family,omitempty"`
+	// 简单版本要求（向后兼容）
+	OSVersion string `json:"os_version"`
+	// 详细 OS 版本要求
+	OSRequirements []OSRequirement `json:"os_requirements,omitempty"`
+	// 废弃，保留向后兼容
+	TargetType string `json:"target_type"`
+	// 适用的运行时类型：["vm", "docker", "k8s"]，空表示全部
+	RuntimeTypes []string `json:"runtime_types,omitempty"`
+	Enabled      bool     `json:"enabled"`
+	// 所属策略组ID
+	GroupID string `json:"group_id"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 关联关系
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// PolicyExportFormat 策略导出格式（匹配 JSON 配置文件格式）
+type PolicyExportFormat struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Version     string             `json:"version"`
+	Description string             `json:"description"`
+	OSFamily    []string           `json:"os_family,omitempty"`
+	OSVersion   string             `json:"os_version"`
+	Enabled     bool               `json:"enabled"`
+	Rules       []RuleExportFormat `json:"rules,omitempty"`
+}
+
+// PolicyGroup 策略组模型
+type PolicyGroup struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 图标（可选）
+	Icon string `json:"icon"`
+	// 颜色（可选）
+	Color string `json:"color"`
+	// 排序顺序
+	SortOrder int64 `json:"sort_order"`
+	Enabled   bool  `json:"enabled"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 关联关系 - 策略组下的策略
+	Policies []Policy `json:"policies,omitempty"`
+}
+
+// Port 端口资产模型
+type Port struct {
+	ID     string `json:"id"`
+	HostID string `json:"host_id"`
+	// tcp/udp
+	Protocol string `json:"protocol"`
+	Port     int64  `json:"port"`
+	// LISTEN/ESTABLISHED 等
+	State       string `json:"state"`
+	PID         string `json:"pid"`
+	ProcessName string `json:"process_name"`
+	ContainerID string `json:"container_id"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// Process 进程资产模型
+type Process struct {
+	ID          string `json:"id"`
+	HostID      string `json:"host_id"`
+	PID         string `json:"pid"`
+	PPID        string `json:"ppid"`
+	Cmdline     string `json:"cmdline"`
+	Exe         string `json:"exe"`
+	ExeHash     string `json:"exe_hash"`
+	ContainerID string `json:"container_id"`
+	UID         string `json:"uid"`
+	GID         string `json:"gid"`
+	Username    string `json:"username"`
+	Groupname   string `json:"groupname"`
+	// 格式 2006-01-02 15:04:05
+	StartTime   *string           `json:"start_time,omitempty"`
+	Cwd         string            `json:"cwd"`
+	ExeInode    int64             `json:"exe_inode"`
+	ExeDev      int64             `json:"exe_dev"`
+	ExeDeleted  bool              `json:"exe_deleted"`
+	ExeMemfd    bool              `json:"exe_memfd"`
+	CapInh      string            `json:"cap_inh"`
+	CapPrm      string            `json:"cap_prm"`
+	CapEff      string            `json:"cap_eff"`
+	CapBnd      string            `json:"cap_bnd"`
+	CapAmb      string            `json:"cap_amb"`
+	Namespaces  map[string]string `json:"namespaces,omitempty"`
+	PackageName string            `json:"package_name"`
+	// verified/modified/unpackaged
+	ExeIntegrity string `json:"exe_integrity"`
+	// 可疑特征
+	Suspicious []string `json:"suspicious,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// ProcessTreeNode 进程树节点
+type ProcessTreeNode struct {
+	ID          string `json:"id"`
+	HostID      string `json:"host_id"`
+	PID         string `json:"pid"`
+	PPID        string `json:"ppid"`
+	Cmdline     string `json:"cmdline"`
+	Exe         string `json:"exe"`
+	ExeHash     string `json:"exe_hash"`
+	ContainerID string `json:"container_id"`
+	UID         string `json:"uid"`
+	GID         string `json:"gid"`
+	Username    string `json:"username"`
+	Groupname   string `json:"groupname"`
+	// 格式 2006-01-02 15:04:05
+	StartTime   *string           `json:"start_time,omitempty"`
+	Cwd         string            `json:"cwd"`
+	ExeInode    int64             `json:"exe_inode"`
+	ExeDev      int64             `json:"exe_dev"`
+	ExeDeleted  bool              `json:"exe_deleted"`
+	ExeMemfd    bool              `json:"exe_memfd"`
+	CapInh      string            `json:"cap_inh"`
+	CapPrm      string            `json:"cap_prm"`
+	CapEff      string            `json:"cap_eff"`
+	CapBnd      string            `json:"cap_bnd"`
+	CapAmb      string            `json:"cap_amb"`
+	Namespaces  map[string]string `json:"namespaces,omitempty"`
+	PackageName string            `json:"package_name"`
+	// verified/modified/unpackaged
+	ExeIntegrity string `json:"exe_integrity"`
+	// 可疑特征
+	Suspicious []string `json:"suspicious,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string            `json:"collected_at"`
+	Children    []ProcessTreeNode `json:"children,omitempty"`
+}
+
+// RefreshAssetsRequest 单台主机资产按需采集请求
+type RefreshAssetsRequest struct {
+	// 采集器名称（process、port 等），为空表示全部
+	Handlers []string `json:"handlers,omitempty"`
+}
+
+// RefreshRequest 刷新访问令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ReleaseVersionRequest 发布版本请求
+type ReleaseVersionRequest struct {
+	// 版本号
+	Version string `json:"version"`
+	// 更新日志
+	Changelog string `json:"changelog"`
+	// 是否设为最新版本
+	SetLatest bool `json:"set_latest"`
+	// 是否强制覆盖已存在的版本
+	Force bool `json:"force"`
+}
+
+// ResolveAlertRequest 解决告警请求
+type ResolveAlertRequest struct {
+	// 解决原因
+	Reason string `json:"reason"`
+}
+
+// RestartAgentRequest Agent 重启请求
+type RestartAgentRequest struct {
+	// 为空表示全部在线主机
+	HostIds []string `json:"host_ids,omitempty"`
+}
+
+// ReviewApprovalRequest 审批请求，请求体可省略
+type ReviewApprovalRequest struct {
+	Comment string `json:"comment"`
+}
+
+// RiskItem 风险项
+type RiskItem struct {
+	// 风险类别
+	Category string `json:"category"`
+	// 风险描述（自然语言）
+	Description string `json:"description"`
+	// 可能影响
+	Impact string `json:"impact"`
+	// 风险等级
+	Severity string `json:"severity"`
+	// 风险等级中文
+	SeverityLabel string `json:"severity_label"`
+	// 整改建议方向
+	Recommendation string `json:"recommendation"`
+	// 影响数量
+	AffectedCount int64 `json:"affected_count"`
+}
+
+// Role 自定义角色（内置角色不存储在数据库中）
+type Role struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 权限标识列表，如 hosts:read
+	Permissions []string `json:"permissions,omitempty"`
+	// 可访问的业务线代码，为空表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// RoleItem 角色列表项（内置角色 ID 为 0）
+type RoleItem struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 可选值：hosts:read, hosts:manage, assets:read, policies:read, policies:manage, tasks:read, tasks:execute, fix:read, fix:execute, alerts:read, alerts:manage, reports:read, fim:read, fim:manage, components:read, components:manage, components:release, business_lines:read, business_lines:manage, notifications:read, notifications:manage, system:read, system:manage, users:read, users:manage, audit:read
+	Permissions   []string `json:"permissions,omitempty"`
+	BusinessLines []string `json:"business_lines,omitempty"`
+	Builtin       bool     `json:"builtin"`
+	UserCount     int64    `json:"user_count"`
+}
+
+// Rule 规则模型
+type Rule struct {
+	RuleID      string `json:"rule_id"`
+	PolicyID    string `json:"policy_id"`
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	Enabled     bool   `json:"enabled"`
+	// 废弃，保留向后兼容
+	TargetType string `json:"target_type"`
+	// 适用的运行时类型：["vm", "docker", "k8s"]，空表示全部
+	RuntimeTypes []string    `json:"runtime_types,omitempty"`
+	CheckConfig  CheckConfig `json:"check_config"`
+	FixConfig    FixConfig   `json:"fix_config"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 关联关系
+	Policy Policy `json:"policy"`
+}
+
+// RuleData 规则数据
+type RuleData struct {
+	RuleID      string      `json:"rule_id"`
+	Category    string      `json:"category"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Severity    string      `json:"severity"`
+	CheckConfig CheckConfig `json:"check_config"`
+	FixConfig   FixConfig   `json:"fix_config"`
+}
+
+// RuleExportFormat 规则导出格式
+type RuleExportFormat struct {
+	RuleID      string                 `json:"rule_id"`
+	Category    string                 `json:"category"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Severity    string                 `json:"severity"`
+	Check       map[string]interface{} `json:"check,omitempty"`
+	Fix         map[string]interface{} `json:"fix,omitempty"`
+}
+
+// ScanResult 检测结果模型
+type ScanResult struct {
+	ResultID string `json:"result_id"`
+	HostID   string `json:"host_id"`
+	// 冗余存储，避免主机删除后数据丢失
+	Hostname string `json:"hostname"`
+	PolicyID string `json:"policy_id"`
+	// 冗余存储，避免策略删除后数据丢失
+	PolicyName string `json:"policy_name"`
+	RuleID     string `json:"rule_id"`
+	TaskID     string `json:"task_id"`
+	// 可选值：pass, fail, error, na
+	Status        string `json:"status"`
+	Severity      string `json:"severity"`
+	Category      string `json:"category"`
+	Title         string `json:"title"`
+	Actual        string `json:"actual"`
+	Expected      string `json:"expected"`
+	FixSuggestion string `json:"fix_suggestion"`
+	// 格式 2006-01-02 15:04:05
+	CheckedAt string `json:"checked_at"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 容器检查结果归属（宿主机检查结果为空）
+	ContainerID    string `json:"container_id"`
+	ContainerName  string `json:"container_name"`
+	ContainerImage string `json:"container_image"`
+	// 关联关系（可选，主要用于查询时预加载）
+	Host Host `json:"host"`
+	Rule Rule `json:"rule"`
+}
+
+// ScopeValueData 主机范围值数据结构
+type ScopeValueData struct {
+	// 当 scope = "host_tags" 时
+	Tags []string `json:"tags,omitempty"`
+	// 当 scope = "business_line" 时
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// 当 scope = "specified" 时
+	HostIds []string `json:"host_ids,omitempty"`
+}
+
+// SecurityScore 安全评分
+type SecurityScore struct {
+	// 综合安全评分 (0-100)
+	Score float64 `json:"score"`
+	// 安全等级 (优秀/良好/一般/较差)
+	Grade string `json:"grade"`
+	// 等级颜色
+	GradeColor string `json:"grade_color"`
+	// 评分说明
+	ScoreExplanation string `json:"score_explanation"`
+	// 安全提示
+	SecurityNote string `json:"security_note"`
+}
+
+// Service 系统服务资产模型（注意：与 Service 服务模型区分开，这里指 systemd 服务）
+type Service struct {
+	ID          string `json:"id"`
+	HostID      string `json:"host_id"`
+	ServiceName string `json:"service_name"`
+	// systemd、sysv
+	ServiceType string `json:"service_type"`
+	// active、inactive、failed 等
+	Status string `json:"status"`
+	// 是否开机自启
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// SessionConfig 登录会话配置
+type SessionConfig struct {
+	// 访问令牌（JWT）有效期，过期后前端用刷新令牌换取
+	AccessTokenMinutes int64 `json:"access_token_minutes"`
+	// 会话最长有效期，刷新令牌轮换不延长，到期后需重新登录
+	RefreshTokenHours int64 `json:"refresh_token_hours"`
+}
+
+// SiteConfig 站点配置
+type SiteConfig struct {
+	// 站点名称
+	SiteName string `json:"site_name"`
+	// Logo URL（相对路径或完整URL）
+	SiteLogo string `json:"site_logo"`
+	// 前端访问域名（用于生成安装脚本）
+	SiteDomain string `json:"site_domain"`
+	// 后端接口地址（用于 Agent 下载更新）
+	BackendURL string `json:"backend_url"`
+}
+
+// Software 软件包资产模型
+type Software struct {
+	ID           string `json:"id"`
+	HostID       string `json:"host_id"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Architecture string `json:"architecture"`
+	// rpm、deb、pip、npm、jar 等
+	PackageType string `json:"package_type"`
+	Vendor      string `json:"vendor"`
+	InstallTime string `json:"install_time"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// TOTPCodeRequest 提交动态验证码请求
+type TOTPCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// TOTPSetupResponse 绑定两步验证响应，密钥仅在绑定时返回
+type TOTPSetupResponse struct {
+	// Base32 密钥，用于手动录入
+	Secret string `json:"secret"`
+	// otpauth:// 地址，可生成二维码供认证器 App 扫描
+	URI string `json:"uri"`
+}
+
+// TargetConfig 目标配置（JSON 格式）
+type TargetConfig struct {
+	HostIds  []string `json:"host_ids,omitempty"`
+	OSFamily []string `json:"os_family,omitempty"`
+	// 运行时类型筛选：vm/docker/k8s（可选值：vm, docker, k8s）
+	RuntimeType string `json:"runtime_type"`
+	// 容器检查：由宿主机 Agent 进入运行中的容器执行检查（runtime_type=docker 时自动启用）
+	ScanContainers bool `json:"scan_containers"`
+	// 仅检查指定容器（为空表示全部运行中的容器）
+	ContainerIds []string `json:"container_ids,omitempty"`
+	// 业务线范围：创建者受业务线范围限制时自动设置，任务只下发到这些业务线的主机
+	BusinessLines []string `json:"business_lines,omitempty"`
+}
+
+// TaskHostStatus 任务主机执行状态
+type TaskHostStatus struct {
+	ID       int64  `json:"id"`
+	TaskID   string `json:"task_id"`
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	// 冗余存储主机信息，避免主机删除后数据丢失
+	IPAddress string `json:"ip_address"`
+	// 业务线
+	BusinessLine string `json:"business_line"`
+	// OS 系列（如 rocky, ubuntu）
+	OSFamily string `json:"os_family"`
+	// OS 版本
+	OSVersion string `json:"os_version"`
+	// 运行时类型（vm, docker, k8s）
+	RuntimeType string `json:"runtime_type"`
+	// dispatched, completed, timeout, failed
+	Status string `json:"status"`
+	// 格式 2006-01-02 15:04:05
+	DispatchedAt *string `json:"dispatched_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt  *string `json:"completed_at,omitempty"`
+	ErrorMessage string  `json:"error_message"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+// TaskReportStatistics 任务报告统计
+type TaskReportStatistics struct {
+	TotalChecks   int64            `json:"total_checks"`
+	PassedChecks  int64            `json:"passed_checks"`
+	FailedChecks  int64            `json:"failed_checks"`
+	WarningChecks int64            `json:"warning_checks"`
+	NaChecks      int64            `json:"na_checks"`
+	PassRate      float64          `json:"pass_rate"`
+	BySeverity    map[string]int64 `json:"by_severity,omitempty"`
+	ByCategory    map[string]int64 `json:"by_category,omitempty"`
+}
+
+// TaskReportSummary 任务报告概要
+type TaskReportSummary struct {
+	TaskID   string `json:"task_id"`
+	TaskName string `json:"task_name"`
+	// 兼容旧版本
+	PolicyID string `json:"policy_id"`
+	// 新版本：多策略ID
+	PolicyIds []string `json:"policy_ids,omitempty"`
+	// 策略名称（多策略时显示数量）
+	PolicyName string `json:"policy_name"`
+	// 新版本：策略名称列表
+	PolicyNames []string `json:"policy_names,omitempty"`
+	ExecutedAt  *string  `json:"executed_at,omitempty"`
+	CompletedAt *string  `json:"completed_at,omitempty"`
+	HostCount   int64    `json:"host_count"`
+	RuleCount   int64    `json:"rule_count"`
+	Status      string   `json:"status"`
+}
+
+// TaskResponse 任务响应（包含计算字段）
+type TaskResponse struct {
+	TaskID string `json:"task_id"`
+	Name   string `json:"name"`
+	// 可选值：baseline_scan
+	Type string `json:"type"`
+	// 可选值：all, host_ids, os_family
+	TargetType   string       `json:"target_type"`
+	TargetConfig TargetConfig `json:"target_config"`
+	// 兼容旧数据
+	PolicyID string `json:"policy_id"`
+	// 新字段：支持多策略
+	PolicyIds []string `json:"policy_ids,omitempty"`
+	RuleIds   []string `json:"rule_ids,omitempty"`
+	// 可选值：created, pending, running, completed, failed, cancelled
+	Status string `json:"status"`
+	// 超时配置
+	TimeoutMinutes int64 `json:"timeout_minutes"`
+	// 执行统计
+	DispatchedHostCount int64 `json:"dispatched_host_count"`
+	// 已完成主机数（收到结果的主机数）
+	CompletedHostCount int64 `json:"completed_host_count"`
+	// 失败信息
+	FailedReason string `json:"failed_reason"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+	// 格式 2006-01-02 15:04:05
+	ExecutedAt *string `json:"executed_at,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CompletedAt *string `json:"completed_at,omitempty"`
+	// 目标主机 ID 列表
+	TargetHosts []string `json:"target_hosts,omitempty"`
+	// 匹配的主机数量（在线）
+	MatchedHostCount int64 `json:"matched_host_count"`
+	// 总目标主机数量（包括离线）
+	TotalHostCount int64 `json:"total_host_count"`
+	// 关联策略的规则总数
+	TotalRuleCount int64 `json:"total_rule_count"`
+	// 预期检查项总数（在线主机数 × 规则数）
+	ExpectedCheckCount int64 `json:"expected_check_count"`
+}
+
+// TestNotificationRequest 测试通知请求
+type TestNotificationRequest struct {
+	// 可选值：lark, webhook
+	Type   string             `json:"type"`
+	Config NotificationConfig `json:"config"`
+	// 可选，用于测试跳转链接
+	FrontendURL string `json:"frontend_url"`
+	// 可选，如果提供则使用完整的通知配置
+	NotificationID *int64 `json:"notification_id,omitempty"`
+}
+
+// TimeSeriesMetrics 是时间序列监控数据
+type TimeSeriesMetrics struct {
+	CPUUsage  []TimeSeriesPoint `json:"cpu_usage,omitempty"`
+	MemUsage  []TimeSeriesPoint `json:"mem_usage,omitempty"`
+	DiskUsage []TimeSeriesPoint `json:"disk_usage,omitempty"`
+}
+
+// TimeSeriesPoint 是时间序列数据点
+type TimeSeriesPoint struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// TopFailedRule Top 失败检查项
+type TopFailedRule struct {
+	RuleID        string `json:"rule_id"`
+	Title         string `json:"title"`
+	Severity      string `json:"severity"`
+	Category      string `json:"category"`
+	AffectedHosts int64  `json:"affected_hosts"`
+}
+
+// TopRiskHost Top 风险主机
+type TopRiskHost struct {
+	HostID        string  `json:"host_id"`
+	Hostname      string  `json:"hostname"`
+	IP            string  `json:"ip"`
+	OSFamily      string  `json:"os_family"`
+	Score         float64 `json:"score"`
+	FailCount     int64   `json:"fail_count"`
+	CriticalCount int64   `json:"critical_count"`
+	HighCount     int64   `json:"high_count"`
+}
+
+// UpdateAlertConfigRequest 更新告警配置请求
+type UpdateAlertConfigRequest struct {
+	RepeatAlertInterval   int64 `json:"repeat_alert_interval"`
+	EnablePeriodicSummary bool  `json:"enable_periodic_summary"`
+}
+
+// UpdateBusinessLineRequest 更新业务线请求
+type UpdateBusinessLineRequest struct {
+	// 业务线名称
+	Name string `json:"name"`
+	// 描述
+	Description string `json:"description"`
+	// 负责人
+	Owner string `json:"owner"`
+	// 联系方式
+	Contact string `json:"contact"`
+	// 是否启用
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// UpdateHostBusinessLineRequest 更新主机业务线请求
+type UpdateHostBusinessLineRequest struct {
+	// 业务线代码（空字符串表示取消绑定）
+	BusinessLine string `json:"business_line"`
+}
+
+// UpdateKubernetesImageConfigRequest 更新 Kubernetes 镜像配置请求
+type UpdateKubernetesImageConfigRequest struct {
+	Repository     string   `json:"repository"`
+	Versions       []string `json:"versions,omitempty"`
+	DefaultVersion string   `json:"default_version"`
+}
+
+// UpdateNotificationRequest 更新通知请求
+type UpdateNotificationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+	// 可选值：lark, webhook
+	Type       string   `json:"type"`
+	Severities []string `json:"severities,omitempty"`
+	// 可选值：global, host_tags, business_line, specified
+	Scope       string              `json:"scope"`
+	ScopeValue  *ScopeValueData     `json:"scope_value,omitempty"`
+	FrontendURL string              `json:"frontend_url"`
+	Config      *NotificationConfig `json:"config,omitempty"`
+}
+
+// UpdatePluginDetailRequest 更新插件运行配置请求
+type UpdatePluginDetailRequest struct {
+	// 插件配置详情（JSON 对象）
+	Detail []byte `json:"detail,omitempty"`
+}
+
+// UpdatePluginLimitsRequest 更新插件资源限制与加固选项请求
+type UpdatePluginLimitsRequest struct {
+	Limits  PluginLimits  `json:"limits"`
+	Sandbox PluginSandbox `json:"sandbox"`
+}
+
+// UpdatePolicyGroupRequest 更新策略组请求
+type UpdatePolicyGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Color       string `json:"color"`
+	SortOrder   *int64 `json:"sort_order,omitempty"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// UpdatePolicyRequest 更新策略请求
+type UpdatePolicyRequest struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	OSFamily    []string `json:"os_family,omitempty"`
+	OSVersion   string   `json:"os_version"`
+	// 详细 OS 版本要求
+	OSRequirements []OSRequirement `json:"os_requirements,omitempty"`
+	// 适用的运行时类型
+	RuntimeTypes []string   `json:"runtime_types,omitempty"`
+	Enabled      *bool      `json:"enabled,omitempty"`
+	GroupID      *string    `json:"group_id,omitempty"`
+	Rules        []RuleData `json:"rules,omitempty"`
+}
+
+// UpdateRoleRequest 更新角色请求（角色名称不可修改）
+type UpdateRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions,omitempty"`
+	// 业务线范围（业务线代码），为空表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+}
+
+// UpdateRuleRequest 更新规则请求
+type UpdateRuleRequest struct {
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	// 可选，更新启用状态
+	Enabled     *bool        `json:"enabled,omitempty"`
+	CheckConfig *CheckConfig `json:"check_config,omitempty"`
+	FixConfig   *FixConfig   `json:"fix_config,omitempty"`
+}
+
+// UpdateSiteConfigRequest 更新站点配置请求
+type UpdateSiteConfigRequest struct {
+	// 站点名称（必填，手动验证）
+	SiteName string `json:"site_name"`
+	// Logo URL（指针类型，nil表示不修改，空字符串表示删除）
+	SiteLogo *string `json:"site_logo,omitempty"`
+	// 前端访问域名（可选）
+	SiteDomain string `json:"site_domain"`
+	// 后端接口地址（必填）
+	BackendURL string `json:"backend_url"`
+}
+
+// UpdateUserRequest 更新用户请求
+type UpdateUserRequest struct {
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Status   string `json:"status"`
+	// 业务线范围，未提供时不修改，空数组表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+}
+
+// User 用户模型
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// 可选值：admin, operator, auditor, viewer, user
+	Role string `json:"role"`
+	// 可选值：active, inactive
+	Status string `json:"status"`
+	// 可访问的业务线代码（与角色的业务线合并），为空表示不限制
+	BusinessLines []string `json:"business_lines,omitempty"`
+	// SSO 用户的角色和业务线在每次登录时按目录组映射刷新（可选值：local, oidc, ldap, service）
+	Source string `json:"source"`
+	// 是否启用 TOTP 两步验证
+	TOTPEnabled bool `json:"totp_enabled"`
+	// 格式 2006-01-02 15:04:05
+	LastLogin *string `json:"last_login,omitempty"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt string `json:"updated_at"`
+}
+
+// VerifyResult 哈希链校验结果
+type VerifyResult struct {
+	Valid bool `json:"valid"`
+	// 已校验的链上记录数
+	Checked int64 `json:"checked"`
+	// 未开启哈希链时写入的记录数（不参与校验）
+	Unchained int64 `json:"unchained"`
+	// 第一条校验失败的记录 ID
+	BrokenID int64  `json:"broken_id"`
+	Reason   string `json:"reason"`
+}
+
+// Volume 磁盘资产模型
+type Volume struct {
+	ID     string `json:"id"`
+	HostID string `json:"host_id"`
+	// /dev/sda1
+	Device string `json:"device"`
+	// /、/home 等
+	MountPoint string `json:"mount_point"`
+	// ext4、xfs 等
+	FileSystem string `json:"file_system"`
+	// 总大小（字节）
+	TotalSize int64 `json:"total_size"`
+	// 已用大小（字节）
+	UsedSize int64 `json:"used_size"`
+	// 可用大小（字节）
+	AvailableSize int64 `json:"available_size"`
+	// 使用率（百分比）
+	UsagePercent float64 `json:"usage_percent"`
+	// 格式 2006-01-02 15:04:05
+	CollectedAt string `json:"collected_at"`
+}
+
+// WatchPath FIM 监控路径配置
+type WatchPath struct {
+	Path string `json:"path"`
+	// NORMAL, CONTENT, PERMS
+	Level string `json:"level"`
+	// 说明
+	Comment string `json:"comment"`
+}
+
+// DiscoverParams Discover 的查询参数
+type DiscoverParams struct {
+	Region string
+	HostID string
+}
+
+func (p *DiscoverParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "region", p.Region)
+	setQuery(q, "host_id", p.HostID)
+	return q
+}
+
+// Discover Agent 服务发现：返回适用于该 Agent 的加权接入点列表
+//
+// 只返回 active 状态的接入点；同时存在通用接入点和与 Agent 区域/业务线匹配的专属接入点时只返回最匹配的一组；
+// 集群模式下按连接注册表中的连接数降低负载较高接入点的权重，并排除实例已下线或已满的接入点
+//
+// GET /agent/discovery
+func (c *Client) Discover(ctx context.Context, params *DiscoverParams) (*DiscoverResult, error) {
+	var out DiscoverResult
+	if err := c.do(ctx, http.MethodGet, "/agent/discovery", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DiscoverResult Discover 的响应数据
+type DiscoverResult struct {
+	Endpoints       []DiscoveredEndpoint `json:"endpoints,omitempty"`
+	RefreshInterval int64                `json:"refresh_interval"`
+}
+
+// DownloadAgentPackage 下载 Agent 安装包 (无需认证)
+//
+// GET /agent/download/{pkg_type}/{arch}
+func (c *Client) DownloadAgentPackage(ctx context.Context, pkgType string, arch string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/agent/download/"+url.PathEscape(pkgType)+"/"+url.PathEscape(arch), nil, nil)
+}
+
+// CheckAgentUpdateParams CheckAgentUpdate 的查询参数
+type CheckAgentUpdateParams struct {
+	// 服务端默认 amd64
+	Arch           string
+	CurrentVersion string
+	// 服务端默认 rpm
+	PkgType string
+}
+
+func (p *CheckAgentUpdateParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "arch", p.Arch)
+	setQuery(q, "current_version", p.CurrentVersion)
+	setQuery(q, "pkg_type", p.PkgType)
+	return q
+}
+
+// CheckAgentUpdate 检查 Agent 是否有可用更新 (无需认证，供 Agent CLI 调用)
+//
+// GET /agent/update-check
+func (c *Client) CheckAgentUpdate(ctx context.Context, params *CheckAgentUpdateParams) (*CheckAgentUpdateResult, error) {
+	var out CheckAgentUpdateResult
+	if err := c.do(ctx, http.MethodGet, "/agent/update-check", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CheckAgentUpdateResult CheckAgentUpdate 的响应数据
+type CheckAgentUpdateResult struct {
+	HasUpdate      bool   `json:"has_update"`
+	LatestVersion  string `json:"latest_version"`
+	CurrentVersion string `json:"current_version"`
+	DownloadURL    string `json:"download_url"`
+	SHA256         string `json:"sha256"`
+	PkgType        string `json:"pkg_type"`
+	Arch           string `json:"arch"`
+	FileSize       int64  `json:"file_size"`
+}
+
+// ListEndpointsParams ListEndpoints 的查询参数
+type ListEndpointsParams struct {
+	Status string
+}
+
+func (p *ListEndpointsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// ListEndpoints 获取接入点列表
+//
+// GET /agentcenter-endpoints（权限 system:read）
+func (c *Client) ListEndpoints(ctx context.Context, params *ListEndpointsParams) (*ListEndpointsResult, error) {
+	var out ListEndpointsResult
+	if err := c.do(ctx, http.MethodGet, "/agentcenter-endpoints", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEndpointsResult ListEndpoints 的响应数据
+type ListEndpointsResult struct {
+	Total int64                     `json:"total"`
+	Items []AgentCenterEndpointItem `json:"items,omitempty"`
+}
+
+// CreateEndpoint 创建接入点
+//
+// POST /agentcenter-endpoints（权限 system:manage）
+func (c *Client) CreateEndpoint(ctx context.Context, body *AgentCenterEndpointRequest) (*AgentCenterEndpoint, error) {
+	var out AgentCenterEndpoint
+	if err := c.do(ctx, http.MethodPost, "/agentcenter-endpoints", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateEndpoint 更新接入点（排空时将 status 设置为 draining）
+//
+// PUT /agentcenter-endpoints/{id}（权限 system:manage）
+func (c *Client) UpdateEndpoint(ctx context.Context, id string, body *AgentCenterEndpointRequest) (*AgentCenterEndpoint, error) {
+	var out AgentCenterEndpoint
+	if err := c.do(ctx, http.MethodPut, "/agentcenter-endpoints/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteEndpoint 删除接入点
+//
+// DELETE /agentcenter-endpoints/{id}（权限 system:manage）
+func (c *Client) DeleteEndpoint(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/agentcenter-endpoints/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListAlertsParams ListAlerts 的查询参数
+type ListAlertsParams struct {
+	Page     int64
+	PageSize int64
+	// active, resolved, ignored
+	Status string
+	// critical, high, medium, low
+	Severity string
+	HostID   string
+	RuleID   string
+	Category string
+	// baseline, agent_offline
+	AlertType string
+	// 搜索标题或描述
+	Keyword string
+	// 根据 result_id 查询
+	ResultID string
+}
+
+func (p *ListAlertsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "severity", p.Severity)
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "rule_id", p.RuleID)
+	setQuery(q, "category", p.Category)
+	setQuery(q, "alert_type", p.AlertType)
+	setQuery(q, "keyword", p.Keyword)
+	setQuery(q, "result_id", p.ResultID)
+	return q
+}
+
+// ListAlerts 获取告警列表
+//
+// GET /alerts（权限 alerts:read）
+func (c *Client) ListAlerts(ctx context.Context, params *ListAlertsParams) (*ListAlertsResult, error) {
+	var out ListAlertsResult
+	if err := c.do(ctx, http.MethodGet, "/alerts", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAlertsResult ListAlerts 的响应数据
+type ListAlertsResult struct {
+	Items     []Alert `json:"items,omitempty"`
+	Total     int64   `json:"total"`
+	Page      int64   `json:"page"`
+	PageSize  int64   `json:"page_size"`
+	TotalPage int64   `json:"total_page"`
+}
+
+// BatchDeleteAlerts 批量删除告警
+//
+// POST /alerts/batch/delete（权限 alerts:manage）
+func (c *Client) BatchDeleteAlerts(ctx context.Context, body *BatchAlertRequest) error {
+	return c.do(ctx, http.MethodPost, "/alerts/batch/delete", nil, body, nil)
+}
+
+// BatchIgnoreAlerts 批量忽略告警
+//
+// POST /alerts/batch/ignore（权限 alerts:manage）
+func (c *Client) BatchIgnoreAlerts(ctx context.Context, body *BatchAlertRequest) error {
+	return c.do(ctx, http.MethodPost, "/alerts/batch/ignore", nil, body, nil)
+}
+
+// BatchResolveAlerts 批量解决告警
+//
+// POST /alerts/batch/resolve（权限 alerts:manage）
+func (c *Client) BatchResolveAlerts(ctx context.Context, body *BatchAlertRequest) error {
+	return c.do(ctx, http.MethodPost, "/alerts/batch/resolve", nil, body, nil)
+}
+
+// GetAlertStatistics 获取告警统计
+//
+// GET /alerts/statistics（权限 alerts:read）
+func (c *Client) GetAlertStatistics(ctx context.Context) (*GetAlertStatisticsResult, error) {
+	var out GetAlertStatisticsResult
+	if err := c.do(ctx, http.MethodGet, "/alerts/statistics", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAlertStatisticsResult GetAlertStatistics 的响应数据
+type GetAlertStatisticsResult struct {
+	Total    int64 `json:"total"`
+	Active   int64 `json:"active"`
+	Resolved int64 `json:"resolved"`
+	Ignored  int64 `json:"ignored"`
+	Critical int64 `json:"critical"`
+	High     int64 `json:"high"`
+	Medium   int64 `json:"medium"`
+	Low      int64 `json:"low"`
+}
+
+// GetAlert 获取告警详情
+//
+// GET /alerts/{id}（权限 alerts:read）
+func (c *Client) GetAlert(ctx context.Context, id string) (*Alert, error) {
+	var out Alert
+	if err := c.do(ctx, http.MethodGet, "/alerts/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IgnoreAlert 忽略告警
+//
+// POST /alerts/{id}/ignore（权限 alerts:manage）
+func (c *Client) IgnoreAlert(ctx context.Context, id string) (*Alert, error) {
+	var out Alert
+	if err := c.do(ctx, http.MethodPost, "/alerts/"+url.PathEscape(id)+"/ignore", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResolveAlert 解决告警
+//
+// POST /alerts/{id}/resolve（权限 alerts:manage）
+func (c *Client) ResolveAlert(ctx context.Context, id string, body *ResolveAlertRequest) (*Alert, error) {
+	var out Alert
+	if err := c.do(ctx, http.MethodPost, "/alerts/"+url.PathEscape(id)+"/resolve", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTokensParams ListTokens 的查询参数
+type ListTokensParams struct {
+	Page     int64
+	PageSize int64
+	Owner    string
+	Status   string
+}
+
+func (p *ListTokensParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "owner", p.Owner)
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// ListTokens 获取所有用户和服务账号的 API Token（不含明文和哈希）
+//
+// GET /api-tokens（权限 users:read）
+func (c *Client) ListTokens(ctx context.Context, params *ListTokensParams) (*ListTokensResult, error) {
+	var out ListTokensResult
+	if err := c.do(ctx, http.MethodGet, "/api-tokens", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTokensResult ListTokens 的响应数据
+type ListTokensResult struct {
+	Total int64      `json:"total"`
+	Items []APIToken `json:"items,omitempty"`
+}
+
+// RevokeToken 吊销任意用户或服务账号的 API Token
+//
+// DELETE /api-tokens/{id}（权限 users:manage）
+func (c *Client) RevokeToken(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api-tokens/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListApprovalsParams ListApprovals 的查询参数
+type ListApprovalsParams struct {
+	Page     int64
+	PageSize int64
+	// todo: 待我审批；mine: 我的申请；为空时返回全部可见的审批单
+	View   string
+	Status string
+	Action string
+}
+
+func (p *ListApprovalsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "view", p.View)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "action", p.Action)
+	return q
+}
+
+// ListApprovals 获取审批单列表：当前用户提交的申请，以及拥有相应权限可审批的申请
+//
+// GET /approvals
+func (c *Client) ListApprovals(ctx context.Context, params *ListApprovalsParams) (*ListApprovalsResult, error) {
+	var out ListApprovalsResult
+	if err := c.do(ctx, http.MethodGet, "/approvals", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListApprovalsResult ListApprovals 的响应数据
+type ListApprovalsResult struct {
+	Total int64          `json:"total"`
+	Items []ApprovalItem `json:"items,omitempty"`
+}
+
+// GetApproval 获取审批单详情
+//
+// GET /approvals/{id}
+func (c *Client) GetApproval(ctx context.Context, id string) (*ApprovalItem, error) {
+	var out ApprovalItem
+	if err := c.do(ctx, http.MethodGet, "/approvals/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ApproveApproval 批准并执行操作
+//
+// POST /approvals/{id}/approve
+func (c *Client) ApproveApproval(ctx context.Context, id string, body *ReviewApprovalRequest) (*ApprovalRequest, error) {
+	var out ApprovalRequest
+	if err := c.do(ctx, http.MethodPost, "/approvals/"+url.PathEscape(id)+"/approve", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelApproval 申请人撤销待审批的申请
+//
+// POST /approvals/{id}/cancel
+func (c *Client) CancelApproval(ctx context.Context, id string) (*ApprovalRequest, error) {
+	var out ApprovalRequest
+	if err := c.do(ctx, http.MethodPost, "/approvals/"+url.PathEscape(id)+"/cancel", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RejectApproval 驳回申请
+//
+// POST /approvals/{id}/reject
+func (c *Client) RejectApproval(ctx context.Context, id string, body *ReviewApprovalRequest) (*ApprovalRequest, error) {
+	var out ApprovalRequest
+	if err := c.do(ctx, http.MethodPost, "/approvals/"+url.PathEscape(id)+"/reject", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAppsParams ListApps 的查询参数
+type ListAppsParams struct {
+	HostID  string
+	AppType string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListAppsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "app_type", p.AppType)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListApps 获取应用列表
+//
+// GET /assets/apps（权限 assets:read）
+func (c *Client) ListApps(ctx context.Context, params *ListAppsParams) (*ListAppsResult, error) {
+	var out ListAppsResult
+	if err := c.do(ctx, http.MethodGet, "/assets/apps", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAppsResult ListApps 的响应数据
+type ListAppsResult struct {
+	Total int64 `json:"total"`
+	Items []App `json:"items,omitempty"`
+}
+
+// ListContainersParams ListContainers 的查询参数
+type ListContainersParams struct {
+	HostID       string
+	Runtime      string
+	Status       string
+	PodNamespace string
+	Privileged   string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListContainersParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "runtime", p.Runtime)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "pod_namespace", p.PodNamespace)
+	setQuery(q, "privileged", p.Privileged)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListContainers 获取容器列表
+//
+// GET /assets/containers（权限 assets:read）
+func (c *Client) ListContainers(ctx context.Context, params *ListContainersParams) (*ListContainersResult, error) {
+	var out ListContainersResult
+	if err := c.do(ctx, http.MethodGet, "/assets/containers", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListContainersResult ListContainers 的响应数据
+type ListContainersResult struct {
+	Total int64       `json:"total"`
+	Items []Container `json:"items,omitempty"`
+}
+
+// ListCronsParams ListCrons 的查询参数
+type ListCronsParams struct {
+	HostID   string
+	User     string
+	CronType string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListCronsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "user", p.User)
+	setQuery(q, "cron_type", p.CronType)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListCrons 获取定时任务列表
+//
+// GET /assets/crons（权限 assets:read）
+func (c *Client) ListCrons(ctx context.Context, params *ListCronsParams) (*ListCronsResult, error) {
+	var out ListCronsResult
+	if err := c.do(ctx, http.MethodGet, "/assets/crons", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCronsResult ListCrons 的响应数据
+type ListCronsResult struct {
+	Total int64  `json:"total"`
+	Items []Cron `json:"items,omitempty"`
+}
+
+// ListKmodsParams ListKmods 的查询参数
+type ListKmodsParams struct {
+	HostID string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListKmodsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListKmods 获取内核模块列表
+//
+// GET /assets/kmods（权限 assets:read）
+func (c *Client) ListKmods(ctx context.Context, params *ListKmodsParams) (*ListKmodsResult, error) {
+	var out ListKmodsResult
+	if err := c.do(ctx, http.MethodGet, "/assets/kmods", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListKmodsResult ListKmods 的响应数据
+type ListKmodsResult struct {
+	Total int64  `json:"total"`
+	Items []Kmod `json:"items,omitempty"`
+}
+
+// ListNetInterfacesParams ListNetInterfaces 的查询参数
+type ListNetInterfacesParams struct {
+	HostID string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListNetInterfacesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListNetInterfaces 获取网络接口列表
+//
+// GET /assets/network-interfaces（权限 assets:read）
+func (c *Client) ListNetInterfaces(ctx context.Context, params *ListNetInterfacesParams) (*ListNetInterfacesResult, error) {
+	var out ListNetInterfacesResult
+	if err := c.do(ctx, http.MethodGet, "/assets/network-interfaces", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNetInterfacesResult ListNetInterfaces 的响应数据
+type ListNetInterfacesResult struct {
+	Total int64          `json:"total"`
+	Items []NetInterface `json:"items,omitempty"`
+}
+
+// ListPortsParams ListPorts 的查询参数
+type ListPortsParams struct {
+	HostID   string
+	Protocol string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListPortsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "protocol", p.Protocol)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListPorts 获取端口列表
+//
+// GET /assets/ports（权限 assets:read）
+func (c *Client) ListPorts(ctx context.Context, params *ListPortsParams) (*ListPortsResult, error) {
+	var out ListPortsResult
+	if err := c.do(ctx, http.MethodGet, "/assets/ports", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPortsResult ListPorts 的响应数据
+type ListPortsResult struct {
+	Total int64  `json:"total"`
+	Items []Port `json:"items,omitempty"`
+}
+
+// ListProcessesParams ListProcesses 的查询参数
+type ListProcessesParams struct {
+	HostID       string
+	ExeIntegrity string
+	Suspicious   string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListProcessesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "exe_integrity", p.ExeIntegrity)
+	setQuery(q, "suspicious", p.Suspicious)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListProcesses 获取进程列表
+//
+// GET /assets/processes（权限 assets:read）
+func (c *Client) ListProcesses(ctx context.Context, params *ListProcessesParams) (*ListProcessesResult, error) {
+	var out ListProcessesResult
+	if err := c.do(ctx, http.MethodGet, "/assets/processes", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListProcessesResult ListProcesses 的响应数据
+type ListProcessesResult struct {
+	Total int64     `json:"total"`
+	Items []Process `json:"items,omitempty"`
+}
+
+// ListServicesParams ListServices 的查询参数
+type ListServicesParams struct {
+	HostID      string
+	ServiceType string
+	Status      string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListServicesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "service_type", p.ServiceType)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListServices 获取系统服务列表
+//
+// GET /assets/services（权限 assets:read）
+func (c *Client) ListServices(ctx context.Context, params *ListServicesParams) (*ListServicesResult, error) {
+	var out ListServicesResult
+	if err := c.do(ctx, http.MethodGet, "/assets/services", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListServicesResult ListServices 的响应数据
+type ListServicesResult struct {
+	Total int64     `json:"total"`
+	Items []Service `json:"items,omitempty"`
+}
+
+// ListSoftwareParams ListSoftware 的查询参数
+type ListSoftwareParams struct {
+	HostID      string
+	PackageType string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListSoftwareParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "package_type", p.PackageType)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListSoftware 获取软件包列表
+//
+// GET /assets/software（权限 assets:read）
+func (c *Client) ListSoftware(ctx context.Context, params *ListSoftwareParams) (*ListSoftwareResult, error) {
+	var out ListSoftwareResult
+	if err := c.do(ctx, http.MethodGet, "/assets/software", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSoftwareResult ListSoftware 的响应数据
+type ListSoftwareResult struct {
+	Total int64      `json:"total"`
+	Items []Software `json:"items,omitempty"`
+}
+
+// AssetsListUsersParams AssetsListUsers 的查询参数
+type AssetsListUsersParams struct {
+	HostID string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *AssetsListUsersParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// AssetsListUsers 获取账户列表
+//
+// GET /assets/users（权限 assets:read）
+func (c *Client) AssetsListUsers(ctx context.Context, params *AssetsListUsersParams) (*AssetsListUsersResult, error) {
+	var out AssetsListUsersResult
+	if err := c.do(ctx, http.MethodGet, "/assets/users", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AssetsListUsersResult AssetsListUsers 的响应数据
+type AssetsListUsersResult struct {
+	Total int64       `json:"total"`
+	Items []AssetUser `json:"items,omitempty"`
+}
+
+// ListVolumesParams ListVolumes 的查询参数
+type ListVolumesParams struct {
+	HostID string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *ListVolumesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// ListVolumes 获取磁盘列表
+//
+// GET /assets/volumes（权限 assets:read）
+func (c *Client) ListVolumes(ctx context.Context, params *ListVolumesParams) (*ListVolumesResult, error) {
+	var out ListVolumesResult
+	if err := c.do(ctx, http.MethodGet, "/assets/volumes", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListVolumesResult ListVolumes 的响应数据
+type ListVolumesResult struct {
+	Total int64    `json:"total"`
+	Items []Volume `json:"items,omitempty"`
+}
+
+// ListAuditLogsParams ListAuditLogs 的查询参数
+type ListAuditLogsParams struct {
+	Page     int64
+	PageSize int64
+	Actor    string
+	// 操作前缀匹配，如 policy 匹配 policy.create/policy.update
+	Action string
+	// 资源类型，如 policy
+	Resource string
+	// 目标 ID
+	Target   string
+	Result   string
+	SourceIP string
+	// 通过指定 API Token 发起的操作
+	TokenID int64
+	// 支持 2006-01-02、2006-01-02 15:04:05 和 RFC3339
+	StartTime string
+	EndTime   string
+}
+
+func (p *ListAuditLogsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "actor", p.Actor)
+	setQuery(q, "action", p.Action)
+	setQuery(q, "resource", p.Resource)
+	setQuery(q, "target", p.Target)
+	setQuery(q, "result", p.Result)
+	setQuery(q, "source_ip", p.SourceIP)
+	setQuery(q, "token_id", p.TokenID)
+	setQuery(q, "start_time", p.StartTime)
+	setQuery(q, "end_time", p.EndTime)
+	return q
+}
+
+// ListAuditLogs 查询审计日志
+//
+// GET /audit-logs（权限 audit:read）
+func (c *Client) ListAuditLogs(ctx context.Context, params *ListAuditLogsParams) (*ListAuditLogsResult, error) {
+	var out ListAuditLogsResult
+	if err := c.do(ctx, http.MethodGet, "/audit-logs", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAuditLogsResult ListAuditLogs 的响应数据
+type ListAuditLogsResult struct {
+	Total int64      `json:"total"`
+	Items []AuditLog `json:"items,omitempty"`
+}
+
+// ExportAuditLogsParams ExportAuditLogs 的查询参数
+type ExportAuditLogsParams struct {
+	Page     int64
+	PageSize int64
+	Actor    string
+	// 操作前缀匹配，如 policy 匹配 policy.create/policy.update
+	Action string
+	// 资源类型，如 policy
+	Resource string
+	// 目标 ID
+	Target   string
+	Result   string
+	SourceIP string
+	// 通过指定 API Token 发起的操作
+	TokenID int64
+	// 支持 2006-01-02、2006-01-02 15:04:05 和 RFC3339
+	StartTime string
+	EndTime   string
+}
+
+func (p *ExportAuditLogsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "actor", p.Actor)
+	setQuery(q, "action", p.Action)
+	setQuery(q, "resource", p.Resource)
+	setQuery(q, "target", p.Target)
+	setQuery(q, "result", p.Result)
+	setQuery(q, "source_ip", p.SourceIP)
+	setQuery(q, "token_id", p.TokenID)
+	setQuery(q, "start_time", p.StartTime)
+	setQuery(q, "end_time", p.EndTime)
+	return q
+}
+
+// ExportAuditLogs 按查询条件导出审计日志（CSV，UTF-8 BOM 便于 Excel 打开）
+//
+// GET /audit-logs/export（权限 audit:read）
+func (c *Client) ExportAuditLogs(ctx context.Context, params *ExportAuditLogsParams) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/audit-logs/export", params.values(), nil)
+}
+
+// VerifyAuditLogs 校验审计日志哈希链
+//
+// GET /audit-logs/verify（权限 audit:read）
+func (c *Client) VerifyAuditLogs(ctx context.Context) (*VerifyResult, error) {
+	var out VerifyResult
+	if err := c.do(ctx, http.MethodGet, "/audit-logs/verify", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ChangePassword 修改当前用户密码，成功后吊销该用户的其他会话
+//
+// POST /auth/change-password
+func (c *Client) ChangePassword(ctx context.Context, body *ChangePasswordRequest) error {
+	return c.do(ctx, http.MethodPost, "/auth/change-password", nil, body, nil)
+}
+
+// Login 用户登录（本地账号密码或 LDAP）
+//
+// POST /auth/login
+func (c *Client) Login(ctx context.Context, body *LoginRequest) (*LoginResponse, error) {
+	var out LoginResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/login", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Logout 用户登出，吊销当前会话（访问令牌和刷新令牌同时失效）
+//
+// POST /auth/logout
+func (c *Client) Logout(ctx context.Context, body *LogoutRequest) error {
+	return c.do(ctx, http.MethodPost, "/auth/logout", nil, body, nil)
+}
+
+// GetCurrentUser 获取当前用户信息、有效权限、业务线范围（前端据此隐藏无权限的操作）和两步验证状态
+//
+// GET /auth/me
+func (c *Client) GetCurrentUser(ctx context.Context) (*GetCurrentUserResult, error) {
+	var out GetCurrentUserResult
+	if err := c.do(ctx, http.MethodGet, "/auth/me", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCurrentUserResult GetCurrentUser 的响应数据
+type GetCurrentUserResult struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	// 可选值：local, oidc, ldap, service
+	Source        string          `json:"source"`
+	Permissions   json.RawMessage `json:"permissions,omitempty"`
+	BusinessLines []string        `json:"business_lines,omitempty"`
+	TOTPEnabled   bool            `json:"totp_enabled"`
+	TOTPAvailable bool            `json:"totp_available"`
+}
+
+// OIDCCallbackParams OIDCCallback 的查询参数
+type OIDCCallbackParams struct {
+	Error string
+	State string
+	Code  string
+}
+
+func (p *OIDCCallbackParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "error", p.Error)
+	setQuery(q, "state", p.State)
+	setQuery(q, "code", p.Code)
+	return q
+}
+
+// OIDCCallback IdP 登录回调：校验 state，用授权码换取并校验 ID Token，按组映射角色后签发平台 Token
+//
+// GET /auth/oidc/callback
+func (c *Client) OIDCCallback(ctx context.Context, params *OIDCCallbackParams) error {
+	return c.do(ctx, http.MethodGet, "/auth/oidc/callback", params.values(), nil, nil)
+}
+
+// OIDCLogin 发起 OIDC 授权码登录：生成 state/nonce/PKCE verifier 存入签名 Cookie 并重定向到 IdP
+//
+// GET /auth/oidc/login
+func (c *Client) OIDCLogin(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/auth/oidc/login", nil, nil, nil)
+}
+
+// GetAuthProviders 返回登录页可用的认证方式（无需认证）
+//
+// GET /auth/providers
+func (c *Client) GetAuthProviders(ctx context.Context) (*GetAuthProvidersResult, error) {
+	var out GetAuthProvidersResult
+	if err := c.do(ctx, http.MethodGet, "/auth/providers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuthProvidersResult GetAuthProviders 的响应数据
+type GetAuthProvidersResult struct {
+	LocalLoginEnabled bool   `json:"local_login_enabled"`
+	LDAPEnabled       bool   `json:"ldap_enabled"`
+	OIDCEnabled       bool   `json:"oidc_enabled"`
+	OIDCDisplayName   string `json:"oidc_display_name"`
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+//
+// POST /auth/refresh
+func (c *Client) Refresh(ctx context.Context, body *RefreshRequest) (*LoginResponse, error) {
+	var out LoginResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/refresh", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMyTokens 获取当前用户的个人访问令牌
+//
+// GET /auth/tokens
+func (c *Client) ListMyTokens(ctx context.Context) (*ListMyTokensResult, error) {
+	var out ListMyTokensResult
+	if err := c.do(ctx, http.MethodGet, "/auth/tokens", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMyTokensResult ListMyTokens 的响应数据
+type ListMyTokensResult struct {
+	Total int64      `json:"total"`
+	Items []APIToken `json:"items,omitempty"`
+}
+
+// CreateMyToken 为当前用户创建个人访问令牌，权限不能超出当前用户的权限
+//
+// POST /auth/tokens
+func (c *Client) CreateMyToken(ctx context.Context, body *CreateAPITokenRequest) (*CreateAPITokenResponse, error) {
+	var out CreateAPITokenResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/tokens", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeMyToken 吊销当前用户的个人访问令牌
+//
+// DELETE /auth/tokens/{id}
+func (c *Client) RevokeMyToken(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/auth/tokens/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DisableTOTP 校验密码和验证码后停用当前用户的两步验证
+//
+// POST /auth/totp/disable
+func (c *Client) DisableTOTP(ctx context.Context, body *DisableTOTPRequest) error {
+	return c.do(ctx, http.MethodPost, "/auth/totp/disable", nil, body, nil)
+}
+
+// EnableTOTP 校验认证器 App 生成的验证码并启用两步验证
+//
+// POST /auth/totp/enable
+func (c *Client) EnableTOTP(ctx context.Context, body *TOTPCodeRequest) error {
+	return c.do(ctx, http.MethodPost, "/auth/totp/enable", nil, body, nil)
+}
+
+// SetupTOTP 为当前用户生成新的 TOTP 密钥，调用 EnableTOTP 验证通过后才启用
+//
+// POST /auth/totp/setup
+func (c *Client) SetupTOTP(ctx context.Context) (*TOTPSetupResponse, error) {
+	var out TOTPSetupResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/totp/setup", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBusinessLinesParams ListBusinessLines 的查询参数
+type ListBusinessLinesParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Enabled  string
+	Keyword  string
+}
+
+func (p *ListBusinessLinesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "enabled", p.Enabled)
+	setQuery(q, "keyword", p.Keyword)
+	return q
+}
+
+// ListBusinessLines 获取业务线列表
+//
+// GET /business-lines（权限 business_lines:read）
+func (c *Client) ListBusinessLines(ctx context.Context, params *ListBusinessLinesParams) (*ListBusinessLinesResult, error) {
+	var out ListBusinessLinesResult
+	if err := c.do(ctx, http.MethodGet, "/business-lines", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBusinessLinesResult ListBusinessLines 的响应数据
+type ListBusinessLinesResult struct {
+	Total int64                  `json:"total"`
+	Items []BusinessLineListItem `json:"items,omitempty"`
+}
+
+// CreateBusinessLine 创建业务线
+//
+// POST /business-lines（权限 business_lines:manage）
+func (c *Client) CreateBusinessLine(ctx context.Context, body *CreateBusinessLineRequest) (*BusinessLine, error) {
+	var out BusinessLine
+	if err := c.do(ctx, http.MethodPost, "/business-lines", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBusinessLine 获取业务线详情
+//
+// GET /business-lines/{id}（权限 business_lines:read）
+func (c *Client) GetBusinessLine(ctx context.Context, id string) (*BusinessLine, error) {
+	var out BusinessLine
+	if err := c.do(ctx, http.MethodGet, "/business-lines/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateBusinessLine 更新业务线
+//
+// PUT /business-lines/{id}（权限 business_lines:manage）
+func (c *Client) UpdateBusinessLine(ctx context.Context, id string, body *UpdateBusinessLineRequest) (*BusinessLine, error) {
+	var out BusinessLine
+	if err := c.do(ctx, http.MethodPut, "/business-lines/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteBusinessLine 删除业务线
+//
+// DELETE /business-lines/{id}（权限 business_lines:manage）
+func (c *Client) DeleteBusinessLine(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/business-lines/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListChangeFreezes 获取变更冻结列表
+//
+// GET /change-freezes（权限 system:read）
+func (c *Client) ListChangeFreezes(ctx context.Context) (*ListChangeFreezesResult, error) {
+	var out ListChangeFreezesResult
+	if err := c.do(ctx, http.MethodGet, "/change-freezes", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListChangeFreezesResult ListChangeFreezes 的响应数据
+type ListChangeFreezesResult struct {
+	Total int64              `json:"total"`
+	Items []ChangeFreezeItem `json:"items,omitempty"`
+}
+
+// CreateChangeFreeze 创建变更冻结
+//
+// POST /change-freezes（权限 system:manage）
+func (c *Client) CreateChangeFreeze(ctx context.Context, body *ChangeFreezeRequest) (*ChangeFreezeItem, error) {
+	var out ChangeFreezeItem
+	if err := c.do(ctx, http.MethodPost, "/change-freezes", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateChangeFreeze 更新变更冻结（提前解除冻结时将 enabled 设置为 false）
+//
+// PUT /change-freezes/{id}（权限 system:manage）
+func (c *Client) UpdateChangeFreeze(ctx context.Context, id string, body *ChangeFreezeRequest) (*ChangeFreezeItem, error) {
+	var out ChangeFreezeItem
+	if err := c.do(ctx, http.MethodPut, "/change-freezes/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteChangeFreeze 删除变更冻结
+//
+// DELETE /change-freezes/{id}（权限 system:manage）
+func (c *Client) DeleteChangeFreeze(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/change-freezes/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListComponents 获取组件列表
+//
+// GET /components（权限 components:read）
+func (c *Client) ListComponents(ctx context.Context) ([]map[string]interface{}, error) {
+	var out []map[string]interface{}
+	err := c.do(ctx, http.MethodGet, "/components", nil, nil, &out)
+	return out, err
+}
+
+// CreateComponent 创建组件
+//
+// POST /components（权限 components:manage）
+func (c *Client) CreateComponent(ctx context.Context, body *CreateComponentRequest) (*Component, error) {
+	var out Component
+	if err := c.do(ctx, http.MethodPost, "/components", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PushAgentUpdate 手动推送 Agent 更新
+//
+// POST /components/agent/push-update（权限 components:release）
+func (c *Client) PushAgentUpdate(ctx context.Context, body *PushAgentUpdateRequest) (*PushAgentUpdateResult, error) {
+	var out PushAgentUpdateResult
+	if err := c.do(ctx, http.MethodPost, "/components/agent/push-update", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PushAgentUpdateRequest PushAgentUpdate 的请求体
+type PushAgentUpdateRequest struct {
+	// 主机 ID 列表，空则推送给所有在线主机
+	HostIds []string `json:"host_ids,omitempty"`
+	// 是否强制更新（即使版本相同也更新）
+	Force bool `json:"force"`
+}
+
+// PushAgentUpdateResult PushAgentUpdate 的响应数据
+type PushAgentUpdateResult struct {
+	Total         int64  `json:"total"`
+	Success       int64  `json:"success"`
+	Failed        int64  `json:"failed"`
+	LatestVersion string `json:"latest_version"`
+}
+
+// GetPluginSyncStatus 获取插件同步状态
+//
+// GET /components/plugin-status（权限 components:read）
+func (c *Client) GetPluginSyncStatus(ctx context.Context) ([]PluginStatus, error) {
+	var out []PluginStatus
+	err := c.do(ctx, http.MethodGet, "/components/plugin-status", nil, nil, &out)
+	return out, err
+}
+
+// BroadcastPluginConfigs 手动广播插件配置
+//
+// POST /components/plugins/broadcast（权限 components:manage）
+func (c *Client) BroadcastPluginConfigs(ctx context.Context) (*BroadcastPluginConfigsResult, error) {
+	var out BroadcastPluginConfigsResult
+	if err := c.do(ctx, http.MethodPost, "/components/plugins/broadcast", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BroadcastPluginConfigsResult BroadcastPluginConfigs 的响应数据
+type BroadcastPluginConfigsResult struct {
+	PluginCount int64 `json:"plugin_count"`
+}
+
+// ListPluginConfigDeferrals 获取等待维护窗口的插件配置下发主机
+//
+// GET /components/plugins/deferrals（权限 components:read）
+func (c *Client) ListPluginConfigDeferrals(ctx context.Context) (*ListPluginConfigDeferralsResult, error) {
+	var out ListPluginConfigDeferralsResult
+	if err := c.do(ctx, http.MethodGet, "/components/plugins/deferrals", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPluginConfigDeferralsResult ListPluginConfigDeferrals 的响应数据
+type ListPluginConfigDeferralsResult struct {
+	Total int64                      `json:"total"`
+	Items []PluginConfigDeferralItem `json:"items,omitempty"`
+}
+
+// EmergencyOverridePluginDeferrals 紧急放行等待维护窗口的插件配置：下一个调度周期立即补发
+//
+// POST /components/plugins/deferrals/emergency（权限 components:release）
+func (c *Client) EmergencyOverridePluginDeferrals(ctx context.Context, body *PluginDeferralOverrideRequest) (*EmergencyOverridePluginDeferralsResult, error) {
+	var out EmergencyOverridePluginDeferralsResult
+	if err := c.do(ctx, http.MethodPost, "/components/plugins/deferrals/emergency", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmergencyOverridePluginDeferralsResult EmergencyOverridePluginDeferrals 的响应数据
+type EmergencyOverridePluginDeferralsResult struct {
+	HostCount int64 `json:"host_count"`
+}
+
+// SyncAllPluginsToLatest 同步所有插件配置到最新版本
+//
+// POST /components/plugins/sync-latest（权限 components:release）
+func (c *Client) SyncAllPluginsToLatest(ctx context.Context) (*SyncAllPluginsToLatestResult, error) {
+	var out SyncAllPluginsToLatestResult
+	if err := c.do(ctx, http.MethodPost, "/components/plugins/sync-latest", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SyncAllPluginsToLatestResult SyncAllPluginsToLatest 的响应数据
+type SyncAllPluginsToLatestResult struct {
+	SyncedCount int64 `json:"synced_count"`
+}
+
+// GetPluginDetail 获取插件运行配置（grpc.Config.detail）
+//
+// GET /components/plugins/{name}/config（权限 components:read）
+func (c *Client) GetPluginDetail(ctx context.Context, name string) (*GetPluginDetailResult, error) {
+	var out GetPluginDetailResult
+	if err := c.do(ctx, http.MethodGet, "/components/plugins/"+url.PathEscape(name)+"/config", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPluginDetailResult GetPluginDetail 的响应数据
+type GetPluginDetailResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Detail  []byte `json:"detail,omitempty"`
+}
+
+// UpdatePluginDetail 更新插件运行配置，插件不重启即可生效
+//
+// PUT /components/plugins/{name}/config（权限 components:manage）
+func (c *Client) UpdatePluginDetail(ctx context.Context, name string, body *UpdatePluginDetailRequest) error {
+	return c.do(ctx, http.MethodPut, "/components/plugins/"+url.PathEscape(name)+"/config", nil, body, nil)
+}
+
+// GetPluginLimits 获取插件资源限制与加固选项
+//
+// GET /components/plugins/{name}/limits（权限 components:read）
+func (c *Client) GetPluginLimits(ctx context.Context, name string) (*GetPluginLimitsResult, error) {
+	var out GetPluginLimitsResult
+	if err := c.do(ctx, http.MethodGet, "/components/plugins/"+url.PathEscape(name)+"/limits", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPluginLimitsResult GetPluginLimits 的响应数据
+type GetPluginLimitsResult struct {
+	Name    string        `json:"name"`
+	Version string        `json:"version"`
+	Limits  PluginLimits  `json:"limits"`
+	Sandbox PluginSandbox `json:"sandbox"`
+}
+
+// UpdatePluginLimits 更新插件资源限制与加固选项
+//
+// 资源限制由 Agent 直接更新插件 cgroup，无需重启；加固选项变化时 Agent 重启插件
+//
+// PUT /components/plugins/{name}/limits（权限 components:manage）
+func (c *Client) UpdatePluginLimits(ctx context.Context, name string, body *UpdatePluginLimitsRequest) error {
+	return c.do(ctx, http.MethodPut, "/components/plugins/"+url.PathEscape(name)+"/limits", nil, body, nil)
+}
+
+// ListPushRecordsParams ListPushRecords 的查询参数
+type ListPushRecordsParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize      string
+	ComponentName string
+	Status        string
+}
+
+func (p *ListPushRecordsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "component_name", p.ComponentName)
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// ListPushRecords 获取推送记录列表
+//
+// GET /components/push-records（权限 components:read）
+func (c *Client) ListPushRecords(ctx context.Context, params *ListPushRecordsParams) (*ListPushRecordsResult, error) {
+	var out ListPushRecordsResult
+	if err := c.do(ctx, http.MethodGet, "/components/push-records", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPushRecordsResult ListPushRecords 的响应数据
+type ListPushRecordsResult struct {
+	Total int64                    `json:"total"`
+	Items []map[string]interface{} `json:"items,omitempty"`
+}
+
+// GetPushRecord 获取推送记录详情
+//
+// GET /components/push-records/{id}（权限 components:read）
+func (c *Client) GetPushRecord(ctx context.Context, id string) (*GetPushRecordResult, error) {
+	var out GetPushRecordResult
+	if err := c.do(ctx, http.MethodGet, "/components/push-records/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPushRecordResult GetPushRecord 的响应数据
+type GetPushRecordResult struct {
+	ID              int64               `json:"id"`
+	ComponentName   string              `json:"component_name"`
+	Version         string              `json:"version"`
+	TargetType      string              `json:"target_type"`
+	TargetHosts     []string            `json:"target_hosts,omitempty"`
+	Status          string              `json:"status"`
+	TotalCount      int64               `json:"total_count"`
+	SuccessCount    int64               `json:"success_count"`
+	FailedCount     int64               `json:"failed_count"`
+	FailedHosts     []string            `json:"failed_hosts,omitempty"`
+	Progress        float64             `json:"progress"`
+	Message         string              `json:"message"`
+	CreatedBy       string              `json:"created_by"`
+	CreatedAt       string              `json:"created_at"`
+	UpdatedAt       string              `json:"updated_at"`
+	CompletedAt     string              `json:"completed_at"`
+	PushHosts       []ComponentPushHost `json:"push_hosts,omitempty"`
+	EmergencyReason string              `json:"emergency_reason"`
+	EmergencyBy     string              `json:"emergency_by"`
+	EmergencyAt     string              `json:"emergency_at"`
+}
+
+// EmergencyOverridePushRecord 紧急放行 Agent 推送：等待维护窗口的主机在下一个调度周期立即推送
+//
+// POST /components/push-records/{id}/emergency（权限 components:release）
+func (c *Client) EmergencyOverridePushRecord(ctx context.Context, id string, body *EmergencyOverrideRequest) error {
+	return c.do(ctx, http.MethodPost, "/components/push-records/"+url.PathEscape(id)+"/emergency", nil, body, nil)
+}
+
+// GetComponent 获取组件详情
+//
+// GET /components/{id}（权限 components:read）
+func (c *Client) GetComponent(ctx context.Context, id string) (*Component, error) {
+	var out Component
+	if err := c.do(ctx, http.MethodGet, "/components/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteComponent 删除组件
+//
+// DELETE /components/{id}（权限 components:manage）
+func (c *Client) DeleteComponent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/components/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListVersions 获取组件的版本列表
+//
+// GET /components/{id}/versions（权限 components:read）
+func (c *Client) ListVersions(ctx context.Context, id string) (*ListVersionsResult, error) {
+	var out ListVersionsResult
+	if err := c.do(ctx, http.MethodGet, "/components/"+url.PathEscape(id)+"/versions", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListVersionsResult ListVersions 的响应数据
+type ListVersionsResult struct {
+	Component Component                `json:"component"`
+	Versions  []map[string]interface{} `json:"versions,omitempty"`
+}
+
+// ReleaseVersion 发布新版本（仅创建版本记录，包文件单独上传）
+//
+// POST /components/{id}/versions（权限 components:release）
+func (c *Client) ReleaseVersion(ctx context.Context, id string, body *ReleaseVersionRequest) (*ComponentVersion, error) {
+	var out ComponentVersion
+	if err := c.do(ctx, http.MethodPost, "/components/"+url.PathEscape(id)+"/versions", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVersion 获取版本详情
+//
+// GET /components/{id}/versions/{version_id}（权限 components:read）
+func (c *Client) GetVersion(ctx context.Context, id string, versionID string) (*ComponentVersion, error) {
+	var out ComponentVersion
+	if err := c.do(ctx, http.MethodGet, "/components/"+url.PathEscape(id)+"/versions/"+url.PathEscape(versionID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteVersion 删除版本
+//
+// DELETE /components/{id}/versions/{version_id}（权限 components:release）
+func (c *Client) DeleteVersion(ctx context.Context, id string, versionID string) error {
+	return c.do(ctx, http.MethodDelete, "/components/"+url.PathEscape(id)+"/versions/"+url.PathEscape(versionID), nil, nil, nil)
+}
+
+// UploadPackage 上传包文件到指定版本
+//
+// POST /components/{id}/versions/{version_id}/packages（权限 components:release）
+func (c *Client) UploadPackage(ctx context.Context, id string, versionID string, contentType string, body io.Reader) (*ComponentPackage, error) {
+	var out ComponentPackage
+	if err := c.doMultipart(ctx, http.MethodPost, "/components/"+url.PathEscape(id)+"/versions/"+url.PathEscape(versionID)+"/packages", nil, contentType, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetLatestVersion 设置为最新版本
+//
+// PUT /components/{id}/versions/{version_id}/set-latest（权限 components:release）
+func (c *Client) SetLatestVersion(ctx context.Context, id string, versionID string) error {
+	return c.do(ctx, http.MethodPut, "/components/"+url.PathEscape(id)+"/versions/"+url.PathEscape(versionID)+"/set-latest", nil, nil, nil)
+}
+
+// GetDashboardStats 获取 Dashboard 统计数据
+//
+// GET /dashboard/stats（权限 reports:read）
+func (c *Client) GetDashboardStats(ctx context.Context) (*GetDashboardStatsResult, error) {
+	var out GetDashboardStatsResult
+	if err := c.do(ctx, http.MethodGet, "/dashboard/stats", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDashboardStatsResult GetDashboardStats 的响应数据
+type GetDashboardStatsResult struct {
+	Hosts                    int64                                 `json:"hosts"`
+	Clusters                 int64                                 `json:"clusters"`
+	Containers               int64                                 `json:"containers"`
+	OnlineAgents             int64                                 `json:"onlineAgents"`
+	OfflineAgents            int64                                 `json:"offlineAgents"`
+	OnlineAgentsChange       int64                                 `json:"onlineAgentsChange"`
+	OfflineAgentsChange      int64                                 `json:"offlineAgentsChange"`
+	PendingAlerts            int64                                 `json:"pendingAlerts"`
+	PendingVulnerabilities   int64                                 `json:"pendingVulnerabilities"`
+	VulnDbUpdateTime         string                                `json:"vulnDbUpdateTime"`
+	HotPatchCount            int64                                 `json:"hotPatchCount"`
+	BaselineFailCount        int64                                 `json:"baselineFailCount"`
+	BaselineHardeningPercent float64                               `json:"baselineHardeningPercent"`
+	BaselineHostPercent      float64                               `json:"baselineHostPercent"`
+	BaselineRisks            []map[string]interface{}              `json:"baselineRisks,omitempty"`
+	AvgCpuUsage              float64                               `json:"avgCpuUsage"`
+	AvgCpuUsageChange        float64                               `json:"avgCpuUsageChange"`
+	AvgMemoryUsage           int64                                 `json:"avgMemoryUsage"`
+	AvgMemoryUsageChange     int64                                 `json:"avgMemoryUsageChange"`
+	HostAlertPercent         float64                               `json:"hostAlertPercent"`
+	VulnHostPercent          float64                               `json:"vulnHostPercent"`
+	RuntimeAlertPercent      float64                               `json:"runtimeAlertPercent"`
+	VirusHostPercent         float64                               `json:"virusHostPercent"`
+	ServiceStatus            *GetDashboardStatsResultServiceStatus `json:"serviceStatus,omitempty"`
+}
+
+// GetDashboardStatsResultServiceStatus GetDashboardStatsResult 的 serviceStatus 字段
+type GetDashboardStatsResultServiceStatus struct {
+	Database    string `json:"database"`
+	Agentcenter string `json:"agentcenter"`
+	Manager     string `json:"manager"`
+}
+
+// DownloadDiagnostics 下载诊断包（tar.gz）
+//
+// GET /diagnostics/{id}/download（权限 hosts:manage）
+func (c *Client) DownloadDiagnostics(ctx context.Context, id string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/diagnostics/"+url.PathEscape(id)+"/download", nil, nil)
+}
+
+// ListFIMEventsParams ListFIMEvents 的查询参数
+type ListFIMEventsParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize   string
+	HostID     string
+	Hostname   string
+	FilePath   string
+	ChangeType string
+	Severity   string
+	Category   string
+	TaskID     string
+	DateFrom   string
+	DateTo     string
+}
+
+func (p *ListFIMEventsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "hostname", p.Hostname)
+	setQuery(q, "file_path", p.FilePath)
+	setQuery(q, "change_type", p.ChangeType)
+	setQuery(q, "severity", p.Severity)
+	setQuery(q, "category", p.Category)
+	setQuery(q, "task_id", p.TaskID)
+	setQuery(q, "date_from", p.DateFrom)
+	setQuery(q, "date_to", p.DateTo)
+	return q
+}
+
+// ListFIMEvents 获取 FIM 事件列表
+//
+// GET /fim/events（权限 fim:read）
+func (c *Client) ListFIMEvents(ctx context.Context, params *ListFIMEventsParams) (*ListFIMEventsResult, error) {
+	var out ListFIMEventsResult
+	if err := c.do(ctx, http.MethodGet, "/fim/events", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFIMEventsResult ListFIMEvents 的响应数据
+type ListFIMEventsResult struct {
+	Total int64      `json:"total"`
+	Items []FIMEvent `json:"items,omitempty"`
+}
+
+// GetFIMEventStatsParams GetFIMEventStats 的查询参数
+type GetFIMEventStatsParams struct {
+	// 服务端默认 7
+	Days string
+}
+
+func (p *GetFIMEventStatsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "days", p.Days)
+	return q
+}
+
+// GetFIMEventStats 获取 FIM 事件统计
+//
+// GET /fim/events/stats（权限 fim:read）
+func (c *Client) GetFIMEventStats(ctx context.Context, params *GetFIMEventStatsParams) (*FIMEventStats, error) {
+	var out FIMEventStats
+	if err := c.do(ctx, http.MethodGet, "/fim/events/stats", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFIMEvent 获取单个 FIM 事件详情
+//
+// GET /fim/events/{id}（权限 fim:read）
+func (c *Client) GetFIMEvent(ctx context.Context, id string) (*FIMEvent, error) {
+	var out FIMEvent
+	if err := c.do(ctx, http.MethodGet, "/fim/events/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFIMPoliciesParams ListFIMPolicies 的查询参数
+type ListFIMPoliciesParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Name     string
+	Enabled  string
+}
+
+func (p *ListFIMPoliciesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "name", p.Name)
+	setQuery(q, "enabled", p.Enabled)
+	return q
+}
+
+// ListFIMPolicies 获取 FIM 策略列表
+//
+// GET /fim/policies（权限 fim:read）
+func (c *Client) ListFIMPolicies(ctx context.Context, params *ListFIMPoliciesParams) (*ListFIMPoliciesResult, error) {
+	var out ListFIMPoliciesResult
+	if err := c.do(ctx, http.MethodGet, "/fim/policies", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFIMPoliciesResult ListFIMPolicies 的响应数据
+type ListFIMPoliciesResult struct {
+	Total int64       `json:"total"`
+	Items []FIMPolicy `json:"items,omitempty"`
+}
+
+// CreateFIMPolicy 创建 FIM 策略
+//
+// POST /fim/policies（权限 fim:manage）
+func (c *Client) CreateFIMPolicy(ctx context.Context, body *CreateFIMPolicyRequest) (*FIMPolicy, error) {
+	var out FIMPolicy
+	if err := c.do(ctx, http.MethodPost, "/fim/policies", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFIMPolicy 获取单个 FIM 策略
+//
+// GET /fim/policies/{id}（权限 fim:read）
+func (c *Client) GetFIMPolicy(ctx context.Context, id string) (*FIMPolicy, error) {
+	var out FIMPolicy
+	if err := c.do(ctx, http.MethodGet, "/fim/policies/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateFIMPolicy 更新 FIM 策略
+//
+// PUT /fim/policies/{id}（权限 fim:manage）
+func (c *Client) UpdateFIMPolicy(ctx context.Context, id string, body *CreateFIMPolicyRequest) (*FIMPolicy, error) {
+	var out FIMPolicy
+	if err := c.do(ctx, http.MethodPut, "/fim/policies/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteFIMPolicy 删除 FIM 策略
+//
+// DELETE /fim/policies/{id}（权限 fim:manage）
+func (c *Client) DeleteFIMPolicy(ctx context.Context, id string) (*DeleteFIMPolicyResult, error) {
+	var out DeleteFIMPolicyResult
+	if err := c.do(ctx, http.MethodDelete, "/fim/policies/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteFIMPolicyResult DeleteFIMPolicy 的响应数据
+type DeleteFIMPolicyResult struct {
+	Message string `json:"message"`
+}
+
+// ListFIMTasksParams ListFIMTasks 的查询参数
+type ListFIMTasksParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	PolicyID string
+	Status   string
+}
+
+func (p *ListFIMTasksParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "policy_id", p.PolicyID)
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// ListFIMTasks 获取 FIM 任务列表
+//
+// GET /fim/tasks（权限 fim:read）
+func (c *Client) ListFIMTasks(ctx context.Context, params *ListFIMTasksParams) (*ListFIMTasksResult, error) {
+	var out ListFIMTasksResult
+	if err := c.do(ctx, http.MethodGet, "/fim/tasks", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFIMTasksResult ListFIMTasks 的响应数据
+type ListFIMTasksResult struct {
+	Total int64     `json:"total"`
+	Items []FIMTask `json:"items,omitempty"`
+}
+
+// CreateFIMTask 创建 FIM 任务
+//
+// POST /fim/tasks（权限 fim:manage）
+func (c *Client) CreateFIMTask(ctx context.Context, body *CreateFIMTaskRequest) (*FIMTask, error) {
+	var out FIMTask
+	if err := c.do(ctx, http.MethodPost, "/fim/tasks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFIMTask 获取单个 FIM 任务详情
+//
+// GET /fim/tasks/{id}（权限 fim:read）
+func (c *Client) GetFIMTask(ctx context.Context, id string) (*GetFIMTaskResult, error) {
+	var out GetFIMTaskResult
+	if err := c.do(ctx, http.MethodGet, "/fim/tasks/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFIMTaskResult GetFIMTask 的响应数据
+type GetFIMTaskResult struct {
+	Task         FIMTask             `json:"task"`
+	HostStatuses []FIMTaskHostStatus `json:"host_statuses,omitempty"`
+}
+
+// RunFIMTask 执行 FIM 任务（标记为 running，实际调度由 AgentCenter 处理）
+//
+// POST /fim/tasks/{id}/run（权限 fim:manage）
+func (c *Client) RunFIMTask(ctx context.Context, id string) (*FIMTask, error) {
+	var out FIMTask
+	if err := c.do(ctx, http.MethodPost, "/fim/tasks/"+url.PathEscape(id)+"/run", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFixTasksParams ListFixTasks 的查询参数
+type ListFixTasksParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Status   string
+}
+
+func (p *ListFixTasksParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// ListFixTasks 获取修复任务列表
+//
+// GET /fix-tasks（权限 fix:read）
+func (c *Client) ListFixTasks(ctx context.Context, params *ListFixTasksParams) (*ListFixTasksResult, error) {
+	var out ListFixTasksResult
+	if err := c.do(ctx, http.MethodGet, "/fix-tasks", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFixTasksResult ListFixTasks 的响应数据
+type ListFixTasksResult struct {
+	Items []FixTask `json:"items,omitempty"`
+	Total int64     `json:"total"`
+}
+
+// CreateFixTask 创建修复任务
+//
+// POST /fix-tasks（权限 fix:execute）
+func (c *Client) CreateFixTask(ctx context.Context, body *CreateFixTaskRequest) (*CreateFixTaskResult, error) {
+	var out CreateFixTaskResult
+	if err := c.do(ctx, http.MethodPost, "/fix-tasks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateFixTaskResult CreateFixTask 的响应数据
+type CreateFixTaskResult struct {
+	TaskID string `json:"task_id"`
+}
+
+// GetFixTask 获取修复任务详情
+//
+// GET /fix-tasks/{task_id}（权限 fix:read）
+func (c *Client) GetFixTask(ctx context.Context, taskID string) (*FixTask, error) {
+	var out FixTask
+	if err := c.do(ctx, http.MethodGet, "/fix-tasks/"+url.PathEscape(taskID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteFixTask 删除修复任务
+//
+// DELETE /fix-tasks/{task_id}（权限 fix:execute）
+func (c *Client) DeleteFixTask(ctx context.Context, taskID string) error {
+	return c.do(ctx, http.MethodDelete, "/fix-tasks/"+url.PathEscape(taskID), nil, nil, nil)
+}
+
+// CancelFixTask 取消修复任务
+//
+// POST /fix-tasks/{task_id}/cancel（权限 fix:execute）
+func (c *Client) CancelFixTask(ctx context.Context, taskID string) error {
+	return c.do(ctx, http.MethodPost, "/fix-tasks/"+url.PathEscape(taskID)+"/cancel", nil, nil, nil)
+}
+
+// EmergencyOverrideFixTask 紧急放行修复任务：不再等待维护窗口和变更冻结，下一个调度周期立即下发
+//
+// POST /fix-tasks/{task_id}/emergency（权限 fix:execute）
+func (c *Client) EmergencyOverrideFixTask(ctx context.Context, taskID string, body *EmergencyOverrideRequest) error {
+	return c.do(ctx, http.MethodPost, "/fix-tasks/"+url.PathEscape(taskID)+"/emergency", nil, body, nil)
+}
+
+// GetFixTaskHostStatusParams GetFixTaskHostStatus 的查询参数
+type GetFixTaskHostStatusParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Status   string
+}
+
+func (p *GetFixTaskHostStatusParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// GetFixTaskHostStatus 获取修复任务主机状态列表
+//
+// GET /fix-tasks/{task_id}/host-status（权限 fix:read）
+func (c *Client) GetFixTaskHostStatus(ctx context.Context, taskID string, params *GetFixTaskHostStatusParams) (*GetFixTaskHostStatusResult, error) {
+	var out GetFixTaskHostStatusResult
+	if err := c.do(ctx, http.MethodGet, "/fix-tasks/"+url.PathEscape(taskID)+"/host-status", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFixTaskHostStatusResult GetFixTaskHostStatus 的响应数据
+type GetFixTaskHostStatusResult struct {
+	Items []FixTaskHostStatus `json:"items,omitempty"`
+	Total int64               `json:"total"`
+}
+
+// GetFixResultsParams GetFixResults 的查询参数
+type GetFixResultsParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Status   string
+}
+
+func (p *GetFixResultsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "status", p.Status)
+	return q
+}
+
+// GetFixResults 获取修复结果
+//
+// GET /fix-tasks/{task_id}/results（权限 fix:read）
+func (c *Client) GetFixResults(ctx context.Context, taskID string, params *GetFixResultsParams) (*GetFixResultsResult, error) {
+	var out GetFixResultsResult
+	if err := c.do(ctx, http.MethodGet, "/fix-tasks/"+url.PathEscape(taskID)+"/results", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFixResultsResult GetFixResults 的响应数据
+type GetFixResultsResult struct {
+	Items []FixResultResponse `json:"items,omitempty"`
+	Total int64               `json:"total"`
+}
+
+// GetFixableItemsParams GetFixableItems 的查询参数
+type GetFixableItemsParams struct {
+	HostIds      []string
+	Severities   []string
+	BusinessLine string
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+}
+
+func (p *GetFixableItemsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addQueryArray(q, "host_ids[]", p.HostIds)
+	addQueryArray(q, "severities[]", p.Severities)
+	setQuery(q, "business_line", p.BusinessLine)
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	return q
+}
+
+// GetFixableItems 获取可修复项列表
+//
+// GET /fix/fixable-items（权限 fix:read）
+func (c *Client) GetFixableItems(ctx context.Context, params *GetFixableItemsParams) (*GetFixableItemsResult, error) {
+	var out GetFixableItemsResult
+	if err := c.do(ctx, http.MethodGet, "/fix/fixable-items", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFixableItemsResult GetFixableItems 的响应数据
+type GetFixableItemsResult struct {
+	Items []FixableItemResponse `json:"items,omitempty"`
+	Total int64                 `json:"total"`
+}
+
+// Health 健康检查端点
+//
+// GET /health
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	var out HealthResponse
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListHostsParams ListHosts 的查询参数
+type ListHostsParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize     string
+	OSFamily     string
+	Status       string
+	BusinessLine string
+	Search       string
+	IsContainer  string
+	RuntimeType  string
+}
+
+func (p *ListHostsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "os_family", p.OSFamily)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "business_line", p.BusinessLine)
+	setQuery(q, "search", p.Search)
+	setQuery(q, "is_container", p.IsContainer)
+	setQuery(q, "runtime_type", p.RuntimeType)
+	return q
+}
+
+// ListHosts 获取主机列表
+//
+// GET /hosts（权限 hosts:read）
+func (c *Client) ListHosts(ctx context.Context, params *ListHostsParams) (*ListHostsResult, error) {
+	var out ListHostsResult
+	if err := c.do(ctx, http.MethodGet, "/hosts", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListHostsResult ListHosts 的响应数据
+type ListHostsResult struct {
+	Total int64          `json:"total"`
+	Items []HostListItem `json:"items,omitempty"`
+}
+
+// BatchRefreshAssets 批量触发资产按需采集
+//
+// POST /hosts/assets/refresh（权限 hosts:manage）
+func (c *Client) BatchRefreshAssets(ctx context.Context, body *BatchRefreshAssetsRequest) (*BatchRefreshAssetsResult, error) {
+	var out BatchRefreshAssetsResult
+	if err := c.do(ctx, http.MethodPost, "/hosts/assets/refresh", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BatchRefreshAssetsResult BatchRefreshAssets 的响应数据
+type BatchRefreshAssetsResult struct {
+	TaskID     int64    `json:"task_id"`
+	HostCount  int64    `json:"host_count"`
+	Handlers   []string `json:"handlers,omitempty"`
+	TotalCount int64    `json:"total_count"`
+}
+
+// GetAssetRefreshTask 查询资产按需采集任务进度
+//
+// GET /hosts/assets/refresh/{task_id}（权限 hosts:read）
+func (c *Client) GetAssetRefreshTask(ctx context.Context, taskID string) (*GetAssetRefreshTaskResult, error) {
+	var out GetAssetRefreshTaskResult
+	if err := c.do(ctx, http.MethodGet, "/hosts/assets/refresh/"+url.PathEscape(taskID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAssetRefreshTaskResult GetAssetRefreshTask 的响应数据
+type GetAssetRefreshTaskResult struct {
+	Task  AssetRefreshTask   `json:"task"`
+	Hosts []AssetRefreshHost `json:"hosts,omitempty"`
+}
+
+// RestartAgent 重启 Agent
+//
+// POST /hosts/restart-agent（权限 hosts:manage）
+func (c *Client) RestartAgent(ctx context.Context, body *RestartAgentRequest) (*RestartAgentResult, error) {
+	var out RestartAgentResult
+	if err := c.do(ctx, http.MethodPost, "/hosts/restart-agent", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestartAgentResult RestartAgent 的响应数据
+type RestartAgentResult struct {
+	RecordID   int64 `json:"record_id"`
+	TotalCount int64 `json:"total_count"`
+}
+
+// GetRestartRecords 获取 Agent 重启记录
+//
+// GET /hosts/restart-records（权限 hosts:read）
+func (c *Client) GetRestartRecords(ctx context.Context) ([]AgentRestartRecord, error) {
+	var out []AgentRestartRecord
+	err := c.do(ctx, http.MethodGet, "/hosts/restart-records", nil, nil, &out)
+	return out, err
+}
+
+// GetHostRiskDistribution 获取主机基线风险分布（按严重程度）
+//
+// GET /hosts/risk-distribution（权限 hosts:read）
+func (c *Client) GetHostRiskDistribution(ctx context.Context) (*HostRiskDistribution, error) {
+	var out HostRiskDistribution
+	if err := c.do(ctx, http.MethodGet, "/hosts/risk-distribution", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostStatusDistribution 获取主机状态分布
+//
+// GET /hosts/status-distribution（权限 hosts:read）
+func (c *Client) GetHostStatusDistribution(ctx context.Context) (*HostStatusDistribution, error) {
+	var out HostStatusDistribution
+	if err := c.do(ctx, http.MethodGet, "/hosts/status-distribution", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHost 获取主机详情
+//
+// GET /hosts/{host_id}（权限 hosts:read）
+func (c *Client) GetHost(ctx context.Context, hostID string) (*GetHostResult, error) {
+	var out GetHostResult
+	if err := c.do(ctx, http.MethodGet, "/hosts/"+url.PathEscape(hostID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostResult GetHost 的响应数据
+type GetHostResult struct {
+	HostID        string   `json:"host_id"`
+	Hostname      string   `json:"hostname"`
+	OSFamily      string   `json:"os_family"`
+	OSVersion     string   `json:"os_version"`
+	KernelVersion string   `json:"kernel_version"`
+	Arch          string   `json:"arch"`
+	IPv4          []string `json:"ipv4,omitempty"`
+	IPv6          []string `json:"ipv6,omitempty"`
+	PublicIPv4    []string `json:"public_ipv4,omitempty"`
+	PublicIPv6    []string `json:"public_ipv6,omitempty"`
+	Status        string   `json:"status"`
+	// 格式 2006-01-02 15:04:05
+	LastHeartbeat string `json:"last_heartbeat"`
+	AgentVersion  string `json:"agent_version"`
+	// 格式 2006-01-02 15:04:05
+	CreatedAt string `json:"created_at"`
+	// 格式 2006-01-02 15:04:05
+	UpdatedAt       string       `json:"updated_at"`
+	BaselineResults []ScanResult `json:"baseline_results,omitempty"`
+	CPUUsage        string       `json:"cpu_usage"`
+	MemoryUsage     string       `json:"memory_usage"`
+	DeviceModel     string       `json:"device_model"`
+	Manufacturer    string       `json:"manufacturer"`
+	DeviceSerial    string       `json:"device_serial"`
+	DeviceID        string       `json:"device_id"`
+	CPUInfo         string       `json:"cpu_info"`
+	MemorySize      string       `json:"memory_size"`
+	SystemLoad      string       `json:"system_load"`
+	DefaultGateway  string       `json:"default_gateway"`
+	NetworkMode     string       `json:"network_mode"`
+	DNSServers      []string     `json:"dns_servers,omitempty"`
+	BusinessLine    string       `json:"business_line"`
+	GuardState      string       `json:"guard_state"`
+	GuardStatus     string       `json:"guard_status"`
+	// 格式 2006-01-02 15:04:05
+	SystemBootTime string `json:"system_boot_time"`
+	// 格式 2006-01-02 15:04:05
+	AgentStartTime string `json:"agent_start_time"`
+	// 格式 2006-01-02 15:04:05
+	LastActiveTime    string   `json:"last_active_time"`
+	Tags              []string `json:"tags,omitempty"`
+	DiskInfo          string   `json:"disk_info"`
+	NetworkInterfaces string   `json:"network_interfaces"`
+	IsContainer       bool     `json:"is_container"`
+	ContainerID       string   `json:"container_id"`
+}
+
+// DeleteHost 删除主机
+//
+// DELETE /hosts/{host_id}（权限 hosts:manage）
+func (c *Client) DeleteHost(ctx context.Context, hostID string) error {
+	return c.do(ctx, http.MethodDelete, "/hosts/"+url.PathEscape(hostID), nil, nil, nil)
+}
+
+// RefreshHostAssets 触发单台主机资产按需采集
+//
+// POST /hosts/{host_id}/assets/refresh（权限 hosts:manage）
+func (c *Client) RefreshHostAssets(ctx context.Context, hostID string, body *RefreshAssetsRequest) (*RefreshHostAssetsResult, error) {
+	var out RefreshHostAssetsResult
+	if err := c.do(ctx, http.MethodPost, "/hosts/"+url.PathEscape(hostID)+"/assets/refresh", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefreshHostAssetsResult RefreshHostAssets 的响应数据
+type RefreshHostAssetsResult struct {
+	TaskID     int64    `json:"task_id"`
+	HostCount  int64    `json:"host_count"`
+	Handlers   []string `json:"handlers,omitempty"`
+	TotalCount int64    `json:"total_count"`
+}
+
+// UpdateHostBusinessLine 更新主机业务线
+//
+// PUT /hosts/{host_id}/business-line（权限 hosts:manage）
+func (c *Client) UpdateHostBusinessLine(ctx context.Context, hostID string, body *UpdateHostBusinessLineRequest) (*Host, error) {
+	var out Host
+	if err := c.do(ctx, http.MethodPut, "/hosts/"+url.PathEscape(hostID)+"/business-line", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListDiagnostics 获取主机的诊断信息收集记录（不含诊断包内容）
+//
+// GET /hosts/{host_id}/diagnostics（权限 hosts:read）
+func (c *Client) ListDiagnostics(ctx context.Context, hostID string) ([]AgentDiagnostics, error) {
+	var out []AgentDiagnostics
+	err := c.do(ctx, http.MethodGet, "/hosts/"+url.PathEscape(hostID)+"/diagnostics", nil, nil, &out)
+	return out, err
+}
+
+// CollectDiagnostics 触发 Agent 诊断信息收集
+//
+// # AgentCenter 调度器下发带一次性上传令牌的诊断命令，Agent 打包配置、日志、任务状态等后通过 FileExt 上传
+//
+// POST /hosts/{host_id}/diagnostics（权限 hosts:manage）
+func (c *Client) CollectDiagnostics(ctx context.Context, hostID string) (*AgentDiagnostics, error) {
+	var out AgentDiagnostics
+	if err := c.do(ctx, http.MethodPost, "/hosts/"+url.PathEscape(hostID)+"/diagnostics", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostMetricsParams GetHostMetrics 的查询参数
+type GetHostMetricsParams struct {
+	StartTime string
+	EndTime   string
+}
+
+func (p *GetHostMetricsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "start_time", p.StartTime)
+	setQuery(q, "end_time", p.EndTime)
+	return q
+}
+
+// GetHostMetrics 获取主机监控数据
+//
+// GET /hosts/{host_id}/metrics（权限 hosts:read）
+func (c *Client) GetHostMetrics(ctx context.Context, hostID string, params *GetHostMetricsParams) (*HostMetrics, error) {
+	var out HostMetrics
+	if err := c.do(ctx, http.MethodGet, "/hosts/"+url.PathEscape(hostID)+"/metrics", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostPlugins 获取主机插件列表
+//
+// GET /hosts/{host_id}/plugins（权限 hosts:read）
+func (c *Client) GetHostPlugins(ctx context.Context, hostID string) ([]HostPluginResponse, error) {
+	var out []HostPluginResponse
+	err := c.do(ctx, http.MethodGet, "/hosts/"+url.PathEscape(hostID)+"/plugins", nil, nil, &out)
+	return out, err
+}
+
+// GetProcessTree 获取主机进程树
+//
+// GET /hosts/{host_id}/process-tree（权限 assets:read）
+func (c *Client) GetProcessTree(ctx context.Context, hostID string) (*GetProcessTreeResult, error) {
+	var out GetProcessTreeResult
+	if err := c.do(ctx, http.MethodGet, "/hosts/"+url.PathEscape(hostID)+"/process-tree", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProcessTreeResult GetProcessTree 的响应数据
+type GetProcessTreeResult struct {
+	Total           int64             `json:"total"`
+	SuspiciousCount int64             `json:"suspicious_count"`
+	ModifiedCount   int64             `json:"modified_count"`
+	Roots           []ProcessTreeNode `json:"roots,omitempty"`
+}
+
+// GetHostRiskStatistics 获取主机风险统计
+//
+// GET /hosts/{host_id}/risk-statistics（权限 hosts:read）
+func (c *Client) GetHostRiskStatistics(ctx context.Context, hostID string) (*HostRiskStatistics, error) {
+	var out HostRiskStatistics
+	if err := c.do(ctx, http.MethodGet, "/hosts/"+url.PathEscape(hostID)+"/risk-statistics", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateHostTags 更新主机标签
+//
+// PUT /hosts/{host_id}/tags（权限 hosts:manage）
+func (c *Client) UpdateHostTags(ctx context.Context, hostID string, body *UpdateHostTagsRequest) error {
+	return c.do(ctx, http.MethodPut, "/hosts/"+url.PathEscape(hostID)+"/tags", nil, body, nil)
+}
+
+// UpdateHostTagsRequest UpdateHostTags 的请求体
+type UpdateHostTagsRequest struct {
+	Tags []string `json:"tags,omitempty"`
+}
+
+// GetOverview 获取巡检概览
+//
+// GET /inspection/overview（权限 reports:read）
+func (c *Client) GetOverview(ctx context.Context) (*InspectionOverviewResponse, error) {
+	var out InspectionOverviewResponse
+	if err := c.do(ctx, http.MethodGet, "/inspection/overview", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMaintenanceWindows 获取维护窗口列表
+//
+// GET /maintenance-windows（权限 system:read）
+func (c *Client) ListMaintenanceWindows(ctx context.Context) (*ListMaintenanceWindowsResult, error) {
+	var out ListMaintenanceWindowsResult
+	if err := c.do(ctx, http.MethodGet, "/maintenance-windows", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMaintenanceWindowsResult ListMaintenanceWindows 的响应数据
+type ListMaintenanceWindowsResult struct {
+	Total int64                   `json:"total"`
+	Items []MaintenanceWindowItem `json:"items,omitempty"`
+}
+
+// CreateMaintenanceWindow 创建维护窗口
+//
+// POST /maintenance-windows（权限 system:manage）
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, body *MaintenanceWindowRequest) (*MaintenanceWindowItem, error) {
+	var out MaintenanceWindowItem
+	if err := c.do(ctx, http.MethodPost, "/maintenance-windows", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMaintenanceWindow 更新维护窗口
+//
+// PUT /maintenance-windows/{id}（权限 system:manage）
+func (c *Client) UpdateMaintenanceWindow(ctx context.Context, id string, body *MaintenanceWindowRequest) (*MaintenanceWindowItem, error) {
+	var out MaintenanceWindowItem
+	if err := c.do(ctx, http.MethodPut, "/maintenance-windows/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMaintenanceWindow 删除维护窗口
+//
+// DELETE /maintenance-windows/{id}（权限 system:manage）
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/maintenance-windows/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListNotificationsParams ListNotifications 的查询参数
+type ListNotificationsParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Enabled  string
+	Keyword  string
+}
+
+func (p *ListNotificationsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "enabled", p.Enabled)
+	setQuery(q, "keyword", p.Keyword)
+	return q
+}
+
+// ListNotifications 获取通知列表
+//
+// GET /notifications（权限 notifications:read）
+func (c *Client) ListNotifications(ctx context.Context, params *ListNotificationsParams) (*ListNotificationsResult, error) {
+	var out ListNotificationsResult
+	if err := c.do(ctx, http.MethodGet, "/notifications", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNotificationsResult ListNotifications 的响应数据
+type ListNotificationsResult struct {
+	Total int64          `json:"total"`
+	Items []Notification `json:"items,omitempty"`
+}
+
+// CreateNotification 创建通知
+//
+// POST /notifications（权限 notifications:manage）
+func (c *Client) CreateNotification(ctx context.Context, body *CreateNotificationRequest) (*Notification, error) {
+	var out Notification
+	if err := c.do(ctx, http.MethodPost, "/notifications", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TestNotification 测试通知
+//
+// POST /notifications/test（权限 notifications:manage）
+func (c *Client) TestNotification(ctx context.Context, body *TestNotificationRequest) error {
+	return c.do(ctx, http.MethodPost, "/notifications/test", nil, body, nil)
+}
+
+// GetNotification 获取通知详情
+//
+// GET /notifications/{id}（权限 notifications:read）
+func (c *Client) GetNotification(ctx context.Context, id string) (*Notification, error) {
+	var out Notification
+	if err := c.do(ctx, http.MethodGet, "/notifications/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateNotification 更新通知
+//
+// PUT /notifications/{id}（权限 notifications:manage）
+func (c *Client) UpdateNotification(ctx context.Context, id string, body *UpdateNotificationRequest) (*Notification, error) {
+	var out Notification
+	if err := c.do(ctx, http.MethodPut, "/notifications/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteNotification 删除通知
+//
+// DELETE /notifications/{id}（权限 notifications:manage）
+func (c *Client) DeleteNotification(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/notifications/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetOpenAPISpec 获取 Manager API 的 OpenAPI 3 文档
+//
+// GET /openapi.json
+func (c *Client) GetOpenAPISpec(ctx context.Context) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/openapi.json", nil, nil)
+}
+
+// DeletePackage 删除包
+//
+// DELETE /packages/{id}（权限 components:release）
+func (c *Client) DeletePackage(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/packages/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListPermissions 获取全部权限定义
+//
+// GET /permissions（权限 users:read）
+func (c *Client) ListPermissions(ctx context.Context) ([]PermissionInfo, error) {
+	var out []PermissionInfo
+	err := c.do(ctx, http.MethodGet, "/permissions", nil, nil, &out)
+	return out, err
+}
+
+// DownloadPluginPackageParams DownloadPluginPackage 的查询参数
+type DownloadPluginPackageParams struct {
+	// 服务端默认 amd64
+	Arch string
+}
+
+func (p *DownloadPluginPackageParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "arch", p.Arch)
+	return q
+}
+
+// DownloadPluginPackage 下载插件包 (供 Agent 调用)
+//
+// GET /plugins/download/{name}
+func (c *Client) DownloadPluginPackage(ctx context.Context, name string, params *DownloadPluginPackageParams) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/plugins/download/"+url.PathEscape(name), params.values(), nil)
+}
+
+// ListPoliciesParams ListPolicies 的查询参数
+type ListPoliciesParams struct {
+	OSFamily string
+	Enabled  string
+	GroupID  string
+}
+
+func (p *ListPoliciesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "os_family", p.OSFamily)
+	setQuery(q, "enabled", p.Enabled)
+	setQuery(q, "group_id", p.GroupID)
+	return q
+}
+
+// ListPolicies 获取策略列表
+//
+// GET /policies（权限 policies:read）
+func (c *Client) ListPolicies(ctx context.Context, params *ListPoliciesParams) (*ListPoliciesResult, error) {
+	var out ListPoliciesResult
+	if err := c.do(ctx, http.MethodGet, "/policies", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPoliciesResult ListPolicies 的响应数据
+type ListPoliciesResult struct {
+	Items []map[string]interface{} `json:"items,omitempty"`
+}
+
+// CreatePolicy 创建策略
+//
+// POST /policies（权限 policies:manage）
+func (c *Client) CreatePolicy(ctx context.Context, body *CreatePolicyRequest) (*Policy, error) {
+	var out Policy
+	if err := c.do(ctx, http.MethodPost, "/policies", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BatchDelete 批量删除策略
+//
+// POST /policies/batch/delete（权限 policies:manage）
+func (c *Client) BatchDelete(ctx context.Context, body *BatchDeleteRequest) (*BatchDeleteResult, error) {
+	var out BatchDeleteResult
+	if err := c.do(ctx, http.MethodPost, "/policies/batch/delete", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BatchDeleteRequest BatchDelete 的请求体
+type BatchDeleteRequest struct {
+	PolicyIds []string `json:"policy_ids,omitempty"`
+}
+
+// BatchDeleteResult BatchDelete 的响应数据
+type BatchDeleteResult struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// BatchEnableDisable 批量启用/禁用策略
+//
+// POST /policies/batch/enable（权限 policies:manage）
+func (c *Client) BatchEnableDisable(ctx context.Context, body *BatchEnableDisableRequest) (*BatchEnableDisableResult, error) {
+	var out BatchEnableDisableResult
+	if err := c.do(ctx, http.MethodPost, "/policies/batch/enable", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BatchEnableDisableRequest BatchEnableDisable 的请求体
+type BatchEnableDisableRequest struct {
+	PolicyIds []string `json:"policy_ids,omitempty"`
+	Enabled   bool     `json:"enabled"`
+}
+
+// BatchEnableDisableResult BatchEnableDisable 的响应数据
+type BatchEnableDisableResult struct {
+	Updated int64 `json:"updated"`
+}
+
+// BatchExport 批量导出策略
+//
+// POST /policies/batch/export（权限 policies:read）
+func (c *Client) BatchExport(ctx context.Context, body *BatchExportRequest) ([]PolicyExportFormat, error) {
+	var out []PolicyExportFormat
+	err := c.do(ctx, http.MethodPost, "/policies/batch/export", nil, body, &out)
+	return out, err
+}
+
+// BatchExportRequest BatchExport 的请求体
+type BatchExportRequest struct {
+	PolicyIds []string `json:"policy_ids,omitempty"`
+}
+
+// ExportAllPolicies 导出所有策略
+//
+// GET /policies/export（权限 policies:read）
+func (c *Client) ExportAllPolicies(ctx context.Context) ([]PolicyExportFormat, error) {
+	var out []PolicyExportFormat
+	err := c.do(ctx, http.MethodGet, "/policies/export", nil, nil, &out)
+	return out, err
+}
+
+// ImportPolicyParams ImportPolicy 的查询参数
+type ImportPolicyParams struct {
+	GroupID string
+	// 服务端默认 skip
+	Mode string
+}
+
+func (p *ImportPolicyParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "group_id", p.GroupID)
+	setQuery(q, "mode", p.Mode)
+	return q
+}
+
+// ImportPolicy 导入策略
+//
+// POST /policies/import（权限 policies:manage）
+func (c *Client) ImportPolicy(ctx context.Context, params *ImportPolicyParams, contentType string, body io.Reader) (*ImportPolicyResult, error) {
+	var out ImportPolicyResult
+	if err := c.doMultipart(ctx, http.MethodPost, "/policies/import", params.values(), contentType, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportPolicyResult ImportPolicy 的响应数据
+type ImportPolicyResult struct {
+	Imported int64    `json:"imported"`
+	Updated  int64    `json:"updated"`
+	Skipped  int64    `json:"skipped"`
+	Total    int64    `json:"total"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// GetPolicy 获取策略详情
+//
+// GET /policies/{policy_id}（权限 policies:read）
+func (c *Client) GetPolicy(ctx context.Context, policyID string) (*Policy, error) {
+	var out Policy
+	if err := c.do(ctx, http.MethodGet, "/policies/"+url.PathEscape(policyID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePolicy 更新策略
+//
+// PUT /policies/{policy_id}（权限 policies:manage）
+func (c *Client) UpdatePolicy(ctx context.Context, policyID string, body *UpdatePolicyRequest) (*Policy, error) {
+	var out Policy
+	if err := c.do(ctx, http.MethodPut, "/policies/"+url.PathEscape(policyID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePolicy 删除策略
+//
+// DELETE /policies/{policy_id}（权限 policies:manage）
+func (c *Client) DeletePolicy(ctx context.Context, policyID string) error {
+	return c.do(ctx, http.MethodDelete, "/policies/"+url.PathEscape(policyID), nil, nil, nil)
+}
+
+// ExportPolicy 导出单个策略
+//
+// GET /policies/{policy_id}/export（权限 policies:read）
+func (c *Client) ExportPolicy(ctx context.Context, policyID string) (*PolicyExportFormat, error) {
+	var out PolicyExportFormat
+	if err := c.do(ctx, http.MethodGet, "/policies/"+url.PathEscape(policyID)+"/export", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRules 获取策略的规则列表
+//
+// GET /policies/{policy_id}/rules（权限 policies:read）
+func (c *Client) ListRules(ctx context.Context, policyID string) (*ListRulesResult, error) {
+	var out ListRulesResult
+	if err := c.do(ctx, http.MethodGet, "/policies/"+url.PathEscape(policyID)+"/rules", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRulesResult ListRules 的响应数据
+type ListRulesResult struct {
+	Items []Rule `json:"items,omitempty"`
+	Total int64  `json:"total"`
+}
+
+// CreateRule 创建规则
+//
+// POST /policies/{policy_id}/rules（权限 policies:manage）
+func (c *Client) CreateRule(ctx context.Context, policyID string, body *CreateRuleRequest) (*Rule, error) {
+	var out Rule
+	if err := c.do(ctx, http.MethodPost, "/policies/"+url.PathEscape(policyID)+"/rules", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyStatistics 获取策略统计信息
+//
+// GET /policies/{policy_id}/statistics（权限 policies:read）
+func (c *Client) GetPolicyStatistics(ctx context.Context, policyID string) (*GetPolicyStatisticsResult, error) {
+	var out GetPolicyStatisticsResult
+	if err := c.do(ctx, http.MethodGet, "/policies/"+url.PathEscape(policyID)+"/statistics", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyStatisticsResult GetPolicyStatistics 的响应数据
+type GetPolicyStatisticsResult struct {
+	PolicyID      string  `json:"policy_id"`
+	PassRate      float64 `json:"pass_rate"`
+	HostCount     int64   `json:"host_count"`
+	HostPassCount int64   `json:"host_pass_count"`
+	RuleCount     int64   `json:"rule_count"`
+	RiskRuleCount int64   `json:"risk_rule_count"`
+	// 格式 2006-01-02 15:04:05
+	LastCheckTime string             `json:"last_check_time"`
+	RulePassRates map[string]float64 `json:"rule_pass_rates,omitempty"`
+}
+
+// ListPolicyGroupsParams ListPolicyGroups 的查询参数
+type ListPolicyGroupsParams struct {
+	WithPolicies string
+}
+
+func (p *ListPolicyGroupsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "with_policies", p.WithPolicies)
+	return q
+}
+
+// ListPolicyGroups 获取策略组列表
+//
+// GET /policy-groups（权限 policies:read）
+func (c *Client) ListPolicyGroups(ctx context.Context, params *ListPolicyGroupsParams) (*ListPolicyGroupsResult, error) {
+	var out ListPolicyGroupsResult
+	if err := c.do(ctx, http.MethodGet, "/policy-groups", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPolicyGroupsResult ListPolicyGroups 的响应数据
+type ListPolicyGroupsResult struct {
+	Total int64            `json:"total"`
+	Items []GroupWithStats `json:"items,omitempty"`
+}
+
+// CreatePolicyGroup 创建策略组
+//
+// POST /policy-groups（权限 policies:manage）
+func (c *Client) CreatePolicyGroup(ctx context.Context, body *CreatePolicyGroupRequest) (*PolicyGroup, error) {
+	var out PolicyGroup
+	if err := c.do(ctx, http.MethodPost, "/policy-groups", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyGroupParams GetPolicyGroup 的查询参数
+type GetPolicyGroupParams struct {
+	WithPolicies string
+}
+
+func (p *GetPolicyGroupParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "with_policies", p.WithPolicies)
+	return q
+}
+
+// GetPolicyGroup 获取策略组详情
+//
+// GET /policy-groups/{id}（权限 policies:read）
+func (c *Client) GetPolicyGroup(ctx context.Context, id string, params *GetPolicyGroupParams) (*PolicyGroup, error) {
+	var out PolicyGroup
+	if err := c.do(ctx, http.MethodGet, "/policy-groups/"+url.PathEscape(id), params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePolicyGroup 更新策略组
+//
+// PUT /policy-groups/{id}（权限 policies:manage）
+func (c *Client) UpdatePolicyGroup(ctx context.Context, id string, body *UpdatePolicyGroupRequest) (*PolicyGroup, error) {
+	var out PolicyGroup
+	if err := c.do(ctx, http.MethodPut, "/policy-groups/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePolicyGroup 删除策略组
+//
+// DELETE /policy-groups/{id}（权限 policies:manage）
+func (c *Client) DeletePolicyGroup(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/policy-groups/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetPolicyGroupStatistics 获取策略组统计信息
+//
+// GET /policy-groups/{id}/statistics（权限 policies:read）
+func (c *Client) GetPolicyGroupStatistics(ctx context.Context, id string) (*GetPolicyGroupStatisticsResult, error) {
+	var out GetPolicyGroupStatisticsResult
+	if err := c.do(ctx, http.MethodGet, "/policy-groups/"+url.PathEscape(id)+"/statistics", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyGroupStatisticsResult GetPolicyGroupStatistics 的响应数据
+type GetPolicyGroupStatisticsResult struct {
+	GroupID     string  `json:"group_id"`
+	PolicyCount int64   `json:"policy_count"`
+	RuleCount   int64   `json:"rule_count"`
+	HostCount   int64   `json:"host_count"`
+	PassRate    float64 `json:"pass_rate"`
+	PassCount   int64   `json:"pass_count"`
+	FailCount   int64   `json:"fail_count"`
+	RiskCount   int64   `json:"risk_count"`
+	// 格式 2006-01-02 15:04:05
+	LastCheckTime string `json:"last_check_time"`
+}
+
+// GetBaselineScoreTrendParams GetBaselineScoreTrend 的查询参数
+type GetBaselineScoreTrendParams struct {
+	HostID    string
+	PolicyID  string
+	StartTime string
+	EndTime   string
+	// 服务端默认 day
+	Interval string
+}
+
+func (p *GetBaselineScoreTrendParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "policy_id", p.PolicyID)
+	setQuery(q, "start_time", p.StartTime)
+	setQuery(q, "end_time", p.EndTime)
+	setQuery(q, "interval", p.Interval)
+	return q
+}
+
+// GetBaselineScoreTrend 获取基线得分趋势
+//
+// GET /reports/baseline-score-trend（权限 reports:read）
+func (c *Client) GetBaselineScoreTrend(ctx context.Context, params *GetBaselineScoreTrendParams) (*GetBaselineScoreTrendResult, error) {
+	var out GetBaselineScoreTrendResult
+	if err := c.do(ctx, http.MethodGet, "/reports/baseline-score-trend", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBaselineScoreTrendResult GetBaselineScoreTrend 的响应数据
+type GetBaselineScoreTrendResult struct {
+	Dates     []string  `json:"dates,omitempty"`
+	Scores    []float64 `json:"scores,omitempty"`
+	PassRates []float64 `json:"passRates,omitempty"`
+}
+
+// GetCheckResultTrendParams GetCheckResultTrend 的查询参数
+type GetCheckResultTrendParams struct {
+	HostID    string
+	PolicyID  string
+	StartTime string
+	EndTime   string
+	// 服务端默认 day
+	Interval string
+}
+
+func (p *GetCheckResultTrendParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "policy_id", p.PolicyID)
+	setQuery(q, "start_time", p.StartTime)
+	setQuery(q, "end_time", p.EndTime)
+	setQuery(q, "interval", p.Interval)
+	return q
+}
+
+// GetCheckResultTrend 获取检查结果趋势
+//
+// GET /reports/check-result-trend（权限 reports:read）
+func (c *Client) GetCheckResultTrend(ctx context.Context, params *GetCheckResultTrendParams) (*GetCheckResultTrendResult, error) {
+	var out GetCheckResultTrendResult
+	if err := c.do(ctx, http.MethodGet, "/reports/check-result-trend", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCheckResultTrendResult GetCheckResultTrend 的响应数据
+type GetCheckResultTrendResult struct {
+	Dates   []string `json:"dates,omitempty"`
+	Passed  []int64  `json:"passed,omitempty"`
+	Failed  []int64  `json:"failed,omitempty"`
+	Warning []int64  `json:"warning,omitempty"`
+}
+
+// GetStatsParams GetStats 的查询参数
+type GetStatsParams struct {
+	StartTime string
+	EndTime   string
+}
+
+func (p *GetStatsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "start_time", p.StartTime)
+	setQuery(q, "end_time", p.EndTime)
+	return q
+}
+
+// GetStats 获取报表统计数据
+//
+// GET /reports/stats（权限 reports:read）
+func (c *Client) GetStats(ctx context.Context, params *GetStatsParams) (*GetStatsResult, error) {
+	var out GetStatsResult
+	if err := c.do(ctx, http.MethodGet, "/reports/stats", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStatsResult GetStats 的响应数据
+type GetStatsResult struct {
+	HostStats     *GetStatsResultHostStats     `json:"hostStats,omitempty"`
+	BaselineStats *GetStatsResultBaselineStats `json:"baselineStats,omitempty"`
+	PolicyStats   *GetStatsResultPolicyStats   `json:"policyStats,omitempty"`
+	TaskStats     *GetStatsResultTaskStats     `json:"taskStats,omitempty"`
+}
+
+// GetStatsResultHostStats GetStatsResult 的 hostStats 字段
+type GetStatsResultHostStats struct {
+	Total      int64            `json:"total"`
+	Online     int64            `json:"online"`
+	Offline    int64            `json:"offline"`
+	ByOsFamily map[string]int64 `json:"byOsFamily,omitempty"`
+}
+
+// GetStatsResultBaselineStats GetStatsResult 的 baselineStats 字段
+type GetStatsResultBaselineStats struct {
+	TotalChecks int64            `json:"totalChecks"`
+	Passed      int64            `json:"passed"`
+	Failed      int64            `json:"failed"`
+	Warning     int64            `json:"warning"`
+	BySeverity  map[string]int64 `json:"bySeverity,omitempty"`
+	ByCategory  map[string]int64 `json:"byCategory,omitempty"`
+}
+
+// GetStatsResultPolicyStats GetStatsResult 的 policyStats 字段
+type GetStatsResultPolicyStats struct {
+	Total       int64   `json:"total"`
+	Enabled     int64   `json:"enabled"`
+	Disabled    int64   `json:"disabled"`
+	AvgPassRate float64 `json:"avgPassRate"`
+}
+
+// GetStatsResultTaskStats GetStatsResult 的 taskStats 字段
+type GetStatsResultTaskStats struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+	Running   int64 `json:"running"`
+	Failed    int64 `json:"failed"`
+}
+
+// GetTaskReport 获取任务报告
+//
+// GET /reports/task/{task_id}（权限 reports:read）
+func (c *Client) GetTaskReport(ctx context.Context, taskID string) (*GetTaskReportResult, error) {
+	var out GetTaskReportResult
+	if err := c.do(ctx, http.MethodGet, "/reports/task/"+url.PathEscape(taskID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskReportResult GetTaskReport 的响应数据
+type GetTaskReportResult struct {
+	Summary     TaskReportSummary    `json:"summary"`
+	Statistics  TaskReportStatistics `json:"statistics"`
+	HostDetails []HostCheckDetail    `json:"host_details,omitempty"`
+	FailedRules []FailedRuleSummary  `json:"failed_rules,omitempty"`
+}
+
+// GetExecutiveTaskReport 获取管理层任务报告（面向非技术管理者的专业报告）
+//
+// GET /reports/task/{task_id}/executive（权限 reports:read）
+func (c *Client) GetExecutiveTaskReport(ctx context.Context, taskID string) (*ExecutiveTaskReport, error) {
+	var out ExecutiveTaskReport
+	if err := c.do(ctx, http.MethodGet, "/reports/task/"+url.PathEscape(taskID)+"/executive", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskHostDetail 获取主机在任务中的详细检查结果
+//
+// GET /reports/task/{task_id}/host/{host_id}（权限 reports:read）
+func (c *Client) GetTaskHostDetail(ctx context.Context, taskID string, hostID string) (*GetTaskHostDetailResult, error) {
+	var out GetTaskHostDetailResult
+	if err := c.do(ctx, http.MethodGet, "/reports/task/"+url.PathEscape(taskID)+"/host/"+url.PathEscape(hostID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskHostDetailResult GetTaskHostDetail 的响应数据
+type GetTaskHostDetailResult struct {
+	Host       *GetTaskHostDetailResultHost       `json:"host,omitempty"`
+	Statistics *GetTaskHostDetailResultStatistics `json:"statistics,omitempty"`
+	Results    []ScanResult                       `json:"results,omitempty"`
+}
+
+// GetTaskHostDetailResultHost GetTaskHostDetailResult 的 host 字段
+type GetTaskHostDetailResultHost struct {
+	HostID    string `json:"host_id"`
+	Hostname  string `json:"hostname"`
+	IP        string `json:"ip"`
+	OSFamily  string `json:"os_family"`
+	OSVersion string `json:"os_version"`
+}
+
+// GetTaskHostDetailResultStatistics GetTaskHostDetailResult 的 statistics 字段
+type GetTaskHostDetailResultStatistics struct {
+	Total   int64 `json:"total"`
+	Passed  int64 `json:"passed"`
+	Failed  int64 `json:"failed"`
+	Warning int64 `json:"warning"`
+	Na      int64 `json:"na"`
+}
+
+// GetTopFailedRulesParams GetTopFailedRules 的查询参数
+type GetTopFailedRulesParams struct {
+	Limit string
+}
+
+func (p *GetTopFailedRulesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "limit", p.Limit)
+	return q
+}
+
+// GetTopFailedRules 获取 Top N 失败检查项
+//
+// GET /reports/top-failed-rules（权限 reports:read）
+func (c *Client) GetTopFailedRules(ctx context.Context, params *GetTopFailedRulesParams) ([]TopFailedRule, error) {
+	var out []TopFailedRule
+	err := c.do(ctx, http.MethodGet, "/reports/top-failed-rules", params.values(), nil, &out)
+	return out, err
+}
+
+// GetTopRiskHostsParams GetTopRiskHosts 的查询参数
+type GetTopRiskHostsParams struct {
+	Limit string
+}
+
+func (p *GetTopRiskHostsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "limit", p.Limit)
+	return q
+}
+
+// GetTopRiskHosts 获取 Top N 风险主机
+//
+// GET /reports/top-risk-hosts（权限 reports:read）
+func (c *Client) GetTopRiskHosts(ctx context.Context, params *GetTopRiskHostsParams) ([]TopRiskHost, error) {
+	var out []TopRiskHost
+	err := c.do(ctx, http.MethodGet, "/reports/top-risk-hosts", params.values(), nil, &out)
+	return out, err
+}
+
+// ListResultsParams ListResults 的查询参数
+type ListResultsParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize    string
+	HostID      string
+	RuleID      string
+	PolicyID    string
+	TaskID      string
+	Status      string
+	Severity    string
+	ContainerID string
+	Scope       string
+}
+
+func (p *ListResultsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "host_id", p.HostID)
+	setQuery(q, "rule_id", p.RuleID)
+	setQuery(q, "policy_id", p.PolicyID)
+	setQuery(q, "task_id", p.TaskID)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "severity", p.Severity)
+	setQuery(q, "container_id", p.ContainerID)
+	setQuery(q, "scope", p.Scope)
+	return q
+}
+
+// ListResults 获取检测结果列表
+//
+// GET /results（权限 tasks:read）
+func (c *Client) ListResults(ctx context.Context, params *ListResultsParams) (*ListResultsResult, error) {
+	var out ListResultsResult
+	if err := c.do(ctx, http.MethodGet, "/results", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListResultsResult ListResults 的响应数据
+type ListResultsResult struct {
+	Total int64        `json:"total"`
+	Items []ScanResult `json:"items,omitempty"`
+}
+
+// GetHostContainerResults 获取主机上各容器的基线检查汇总（容器视图）
+//
+// 容器结果由宿主机 Agent 进入容器检查产生，无需在容器内部署 Agent
+//
+// GET /results/host/{host_id}/containers（权限 tasks:read）
+func (c *Client) GetHostContainerResults(ctx context.Context, hostID string) (*GetHostContainerResultsResult, error) {
+	var out GetHostContainerResultsResult
+	if err := c.do(ctx, http.MethodGet, "/results/host/"+url.PathEscape(hostID)+"/containers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostContainerResultsResult GetHostContainerResults 的响应数据
+type GetHostContainerResultsResult struct {
+	HostID string                                   `json:"host_id"`
+	Total  int64                                    `json:"total"`
+	Items  []GetHostContainerResultsResultItemsItem `json:"items,omitempty"`
+}
+
+// GetHostContainerResultsResultItemsItem GetHostContainerResultsResult 的 items 字段元素
+type GetHostContainerResultsResultItemsItem struct {
+	ContainerID    string `json:"container_id"`
+	ContainerName  string `json:"container_name"`
+	ContainerImage string `json:"container_image"`
+	TotalRules     int64  `json:"total_rules"`
+	PassCount      int64  `json:"pass_count"`
+	FailCount      int64  `json:"fail_count"`
+	ErrorCount     int64  `json:"error_count"`
+	NaCount        int64  `json:"na_count"`
+	LastCheckedAt  string `json:"last_checked_at"`
+}
+
+// ExportHostBaselineResultsParams ExportHostBaselineResults 的查询参数
+type ExportHostBaselineResultsParams struct {
+	// 服务端默认 excel
+	Format string
+}
+
+func (p *ExportHostBaselineResultsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "format", p.Format)
+	return q
+}
+
+// ExportHostBaselineResults 导出主机基线检查结果
+//
+// GET /results/host/{host_id}/export（权限 tasks:read）
+func (c *Client) ExportHostBaselineResults(ctx context.Context, hostID string, params *ExportHostBaselineResultsParams) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/results/host/"+url.PathEscape(hostID)+"/export", params.values(), nil)
+}
+
+// GetHostBaselineScore 获取主机基线得分
+//
+// GET /results/host/{host_id}/score（权限 tasks:read）
+func (c *Client) GetHostBaselineScore(ctx context.Context, hostID string) (*GetHostBaselineScoreResult, error) {
+	var out GetHostBaselineScoreResult
+	if err := c.do(ctx, http.MethodGet, "/results/host/"+url.PathEscape(hostID)+"/score", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostBaselineScoreResult GetHostBaselineScore 的响应数据
+type GetHostBaselineScoreResult struct {
+	HostID        string  `json:"host_id"`
+	BaselineScore int64   `json:"baseline_score"`
+	PassRate      float64 `json:"pass_rate"`
+	TotalRules    int64   `json:"total_rules"`
+	PassCount     int64   `json:"pass_count"`
+	FailCount     int64   `json:"fail_count"`
+	ErrorCount    int64   `json:"error_count"`
+	NaCount       int64   `json:"na_count"`
+}
+
+// GetHostBaselineSummary 获取主机基线摘要（按严重级别统计）
+//
+// GET /results/host/{host_id}/summary（权限 tasks:read）
+func (c *Client) GetHostBaselineSummary(ctx context.Context, hostID string) (*GetHostBaselineSummaryResult, error) {
+	var out GetHostBaselineSummaryResult
+	if err := c.do(ctx, http.MethodGet, "/results/host/"+url.PathEscape(hostID)+"/summary", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHostBaselineSummaryResult GetHostBaselineSummary 的响应数据
+type GetHostBaselineSummaryResult struct {
+	HostID     string                                  `json:"host_id"`
+	BySeverity *GetHostBaselineSummaryResultBySeverity `json:"by_severity,omitempty"`
+	ByCategory map[string]map[string]interface{}       `json:"by_category,omitempty"`
+}
+
+// GetHostBaselineSummaryResultBySeverity GetHostBaselineSummaryResult 的 by_severity 字段
+type GetHostBaselineSummaryResultBySeverity struct {
+	Critical *GetHostBaselineSummaryResultBySeverityCritical `json:"critical,omitempty"`
+	High     *GetHostBaselineSummaryResultBySeverityHigh     `json:"high,omitempty"`
+	Medium   *GetHostBaselineSummaryResultBySeverityMedium   `json:"medium,omitempty"`
+	Low      *GetHostBaselineSummaryResultBySeverityLow      `json:"low,omitempty"`
+}
+
+// GetHostBaselineSummaryResultBySeverityCritical GetHostBaselineSummaryResultBySeverity 的 critical 字段
+type GetHostBaselineSummaryResultBySeverityCritical struct {
+	Pass  int64 `json:"pass"`
+	Fail  int64 `json:"fail"`
+	Error int64 `json:"error"`
+	Na    int64 `json:"na"`
+}
+
+// GetHostBaselineSummaryResultBySeverityHigh GetHostBaselineSummaryResultBySeverity 的 high 字段
+type GetHostBaselineSummaryResultBySeverityHigh struct {
+	Pass  int64 `json:"pass"`
+	Fail  int64 `json:"fail"`
+	Error int64 `json:"error"`
+	Na    int64 `json:"na"`
+}
+
+// GetHostBaselineSummaryResultBySeverityMedium GetHostBaselineSummaryResultBySeverity 的 medium 字段
+type GetHostBaselineSummaryResultBySeverityMedium struct {
+	Pass  int64 `json:"pass"`
+	Fail  int64 `json:"fail"`
+	Error int64 `json:"error"`
+	Na    int64 `json:"na"`
+}
+
+// GetHostBaselineSummaryResultBySeverityLow GetHostBaselineSummaryResultBySeverity 的 low 字段
+type GetHostBaselineSummaryResultBySeverityLow struct {
+	Pass  int64 `json:"pass"`
+	Fail  int64 `json:"fail"`
+	Error int64 `json:"error"`
+	Na    int64 `json:"na"`
+}
+
+// ImportOfflineResults 导入离线基线检查结果包（mxsec-agent --scan 生成）
+//
+// 以结果包声明的主机的密钥校验签名后，按一次已完成的扫描任务写入 scan_tasks、task_host_status 和 scan_results，
+// 并同步告警；主机不存在时以离线状态创建，在线主机拒绝导入，同一结果包只能导入一次
+//
+// POST /results/offline-import（权限 tasks:execute）
+func (c *Client) ImportOfflineResults(ctx context.Context, contentType string, body io.Reader) (*ImportOfflineResultsResult, error) {
+	var out ImportOfflineResultsResult
+	if err := c.doMultipart(ctx, http.MethodPost, "/results/offline-import", nil, contentType, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportOfflineResultsResult ImportOfflineResults 的响应数据
+type ImportOfflineResultsResult struct {
+	TaskID         string `json:"task_id"`
+	HostID         string `json:"host_id"`
+	Hostname       string `json:"hostname"`
+	ResultCount    int64  `json:"result_count"`
+	AlertsCreated  int64  `json:"alerts_created"`
+	AlertsResolved int64  `json:"alerts_resolved"`
+	// 格式 2006-01-02 15:04:05
+	CheckedAt string `json:"checked_at"`
+}
+
+// IssueHostKey 生成离线主机的签名密钥（由主密钥和 Agent ID 派生，只能签名该主机的结果包）
+//
+// POST /results/offline-keys（权限 tasks:execute）
+func (c *Client) IssueHostKey(ctx context.Context, body *IssueHostKeyRequest) (*IssueHostKeyResponse, error) {
+	var out IssueHostKeyResponse
+	if err := c.do(ctx, http.MethodPost, "/results/offline-keys", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetResult 获取检测结果详情
+//
+// GET /results/{result_id}（权限 tasks:read）
+func (c *Client) GetResult(ctx context.Context, resultID string) (*ScanResult, error) {
+	var out ScanResult
+	if err := c.do(ctx, http.MethodGet, "/results/"+url.PathEscape(resultID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRoles 获取角色列表（内置角色在前）
+//
+// GET /roles（权限 users:read）
+func (c *Client) ListRoles(ctx context.Context) (*ListRolesResult, error) {
+	var out ListRolesResult
+	if err := c.do(ctx, http.MethodGet, "/roles", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRolesResult ListRoles 的响应数据
+type ListRolesResult struct {
+	Total int64      `json:"total"`
+	Items []RoleItem `json:"items,omitempty"`
+}
+
+// CreateRole 创建自定义角色
+//
+// POST /roles（权限 users:manage）
+func (c *Client) CreateRole(ctx context.Context, body *CreateRoleRequest) (*Role, error) {
+	var out Role
+	if err := c.do(ctx, http.MethodPost, "/roles", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRole 更新自定义角色的描述、权限和业务线范围（内置角色不可修改）
+//
+// PUT /roles/{id}（权限 users:manage）
+func (c *Client) UpdateRole(ctx context.Context, id string, body *UpdateRoleRequest) (*Role, error) {
+	var out Role
+	if err := c.do(ctx, http.MethodPut, "/roles/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRole 删除自定义角色（仍有用户使用时不可删除）
+//
+// DELETE /roles/{id}（权限 users:manage）
+func (c *Client) DeleteRole(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/roles/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetRule 获取规则详情
+//
+// GET /rules/{rule_id}（权限 policies:read）
+func (c *Client) GetRule(ctx context.Context, ruleID string) (*Rule, error) {
+	var out Rule
+	if err := c.do(ctx, http.MethodGet, "/rules/"+url.PathEscape(ruleID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRule 更新规则
+//
+// PUT /rules/{rule_id}（权限 policies:manage）
+func (c *Client) UpdateRule(ctx context.Context, ruleID string, body *UpdateRuleRequest) (*Rule, error) {
+	var out Rule
+	if err := c.do(ctx, http.MethodPut, "/rules/"+url.PathEscape(ruleID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRule 删除规则
+//
+// DELETE /rules/{rule_id}（权限 policies:manage）
+func (c *Client) DeleteRule(ctx context.Context, ruleID string) error {
+	return c.do(ctx, http.MethodDelete, "/rules/"+url.PathEscape(ruleID), nil, nil, nil)
+}
+
+// GetAlertConfig 获取告警配置
+//
+// GET /system-config/alert（权限 system:read）
+func (c *Client) GetAlertConfig(ctx context.Context) (*AlertConfig, error) {
+	var out AlertConfig
+	if err := c.do(ctx, http.MethodGet, "/system-config/alert", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAlertConfig 更新告警配置
+//
+// PUT /system-config/alert（权限 system:manage）
+func (c *Client) UpdateAlertConfig(ctx context.Context, body *UpdateAlertConfigRequest) (*AlertConfig, error) {
+	var out AlertConfig
+	if err := c.do(ctx, http.MethodPut, "/system-config/alert", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetApprovalConfig 获取高危操作审批策略
+//
+// GET /system-config/approval（权限 system:read）
+func (c *Client) GetApprovalConfig(ctx context.Context) (*ApprovalConfig, error) {
+	var out ApprovalConfig
+	if err := c.do(ctx, http.MethodGet, "/system-config/approval", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateApprovalConfig 更新高危操作审批策略
+//
+// PUT /system-config/approval（权限 system:manage）
+func (c *Client) UpdateApprovalConfig(ctx context.Context, body *ApprovalConfig) (*ApprovalConfig, error) {
+	var out ApprovalConfig
+	if err := c.do(ctx, http.MethodPut, "/system-config/approval", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuthConfig 获取登录认证配置（OIDC/LDAP/目录组映射/会话与密码策略），密钥以占位符返回
+//
+// GET /system-config/auth（权限 system:read）
+func (c *Client) GetAuthConfig(ctx context.Context) (*AuthConfig, error) {
+	var out AuthConfig
+	if err := c.do(ctx, http.MethodGet, "/system-config/auth", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAuthConfig 更新登录认证配置
+//
+// PUT /system-config/auth（权限 system:manage）
+func (c *Client) UpdateAuthConfig(ctx context.Context, body *AuthConfig) (*AuthConfig, error) {
+	var out AuthConfig
+	if err := c.do(ctx, http.MethodPut, "/system-config/auth", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetKubernetesImageConfig 获取 Kubernetes 镜像配置
+//
+// GET /system-config/kubernetes-image（权限 system:read）
+func (c *Client) GetKubernetesImageConfig(ctx context.Context) (*KubernetesImageConfig, error) {
+	var out KubernetesImageConfig
+	if err := c.do(ctx, http.MethodGet, "/system-config/kubernetes-image", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateKubernetesImageConfig 更新 Kubernetes 镜像配置
+//
+// PUT /system-config/kubernetes-image（权限 system:manage）
+func (c *Client) UpdateKubernetesImageConfig(ctx context.Context, body *UpdateKubernetesImageConfigRequest) (*KubernetesImageConfig, error) {
+	var out KubernetesImageConfig
+	if err := c.do(ctx, http.MethodPut, "/system-config/kubernetes-image", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSiteConfig 获取站点配置
+//
+// GET /system-config/site
+func (c *Client) GetSiteConfig(ctx context.Context) (*SiteConfig, error) {
+	var out SiteConfig
+	if err := c.do(ctx, http.MethodGet, "/system-config/site", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSiteConfig 更新站点配置
+//
+// PUT /system-config/site（权限 system:manage）
+func (c *Client) UpdateSiteConfig(ctx context.Context, body *UpdateSiteConfigRequest) (*SiteConfig, error) {
+	var out SiteConfig
+	if err := c.do(ctx, http.MethodPut, "/system-config/site", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadLogo 上传 Logo
+//
+// POST /system-config/upload-logo（权限 system:manage）
+func (c *Client) UploadLogo(ctx context.Context, contentType string, body io.Reader) (*UploadLogoResult, error) {
+	var out UploadLogoResult
+	if err := c.doMultipart(ctx, http.MethodPost, "/system-config/upload-logo", nil, contentType, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadLogoResult UploadLogo 的响应数据
+type UploadLogoResult struct {
+	LogoURL string `json:"logo_url"`
+}
+
+// ListTasksParams ListTasks 的查询参数
+type ListTasksParams struct {
+	// 服务端默认 1
+	Page string
+	// 服务端默认 20
+	PageSize string
+	Status   string
+	PolicyID string
+}
+
+func (p *ListTasksParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "policy_id", p.PolicyID)
+	return q
+}
+
+// ListTasks 获取任务列表
+//
+// GET /tasks（权限 tasks:read）
+func (c *Client) ListTasks(ctx context.Context, params *ListTasksParams) (*ListTasksResult, error) {
+	var out ListTasksResult
+	if err := c.do(ctx, http.MethodGet, "/tasks", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTasksResult ListTasks 的响应数据
+type ListTasksResult struct {
+	Total int64          `json:"total"`
+	Items []TaskResponse `json:"items,omitempty"`
+}
+
+// CreateTask 创建扫描任务
+//
+// POST /tasks（权限 tasks:execute）
+func (c *Client) CreateTask(ctx context.Context, body *CreateTaskRequest) (*TaskResponse, error) {
+	var out TaskResponse
+	if err := c.do(ctx, http.MethodPost, "/tasks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTask 获取任务详情
+//
+// GET /tasks/{task_id}（权限 tasks:read）
+func (c *Client) GetTask(ctx context.Context, taskID string) (*TaskResponse, error) {
+	var out TaskResponse
+	if err := c.do(ctx, http.MethodGet, "/tasks/"+url.PathEscape(taskID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTask 删除任务
+//
+// DELETE /tasks/{task_id}（权限 tasks:execute）
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	return c.do(ctx, http.MethodDelete, "/tasks/"+url.PathEscape(taskID), nil, nil, nil)
+}
+
+// CancelTask 取消任务
+//
+// POST /tasks/{task_id}/cancel（权限 tasks:execute）
+func (c *Client) CancelTask(ctx context.Context, taskID string) (*TaskResponse, error) {
+	var out TaskResponse
+	if err := c.do(ctx, http.MethodPost, "/tasks/"+url.PathEscape(taskID)+"/cancel", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskHostStatus 获取任务的主机执行状态
+//
+// GET /tasks/{task_id}/host-status（权限 tasks:read）
+func (c *Client) GetTaskHostStatus(ctx context.Context, taskID string) (*GetTaskHostStatusResult, error) {
+	var out GetTaskHostStatusResult
+	if err := c.do(ctx, http.MethodGet, "/tasks/"+url.PathEscape(taskID)+"/host-status", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskHostStatusResult GetTaskHostStatus 的响应数据
+type GetTaskHostStatusResult struct {
+	TaskID string           `json:"task_id"`
+	Hosts  []TaskHostStatus `json:"hosts,omitempty"`
+}
+
+// RunTask 执行任务
+//
+// POST /tasks/{task_id}/run（权限 tasks:execute）
+func (c *Client) RunTask(ctx context.Context, taskID string) (*TaskResponse, error) {
+	var out TaskResponse
+	if err := c.do(ctx, http.MethodPost, "/tasks/"+url.PathEscape(taskID)+"/run", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UsersListUsersParams UsersListUsers 的查询参数
+type UsersListUsersParams struct {
+	Page     int64
+	PageSize int64
+	Username string
+	Role     string
+	Status   string
+	// local/oidc/ldap/service
+	Source string
+}
+
+func (p *UsersListUsersParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	setQuery(q, "page", p.Page)
+	setQuery(q, "page_size", p.PageSize)
+	setQuery(q, "username", p.Username)
+	setQuery(q, "role", p.Role)
+	setQuery(q, "status", p.Status)
+	setQuery(q, "source", p.Source)
+	return q
+}
+
+// UsersListUsers 获取用户列表
+//
+// GET /users（权限 users:read）
+func (c *Client) UsersListUsers(ctx context.Context, params *UsersListUsersParams) (*ListUsersResponse, error) {
+	var out ListUsersResponse
+	if err := c.do(ctx, http.MethodGet, "/users", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateUser 创建用户
+//
+// POST /users（权限 users:manage）
+func (c *Client) CreateUser(ctx context.Context, body *CreateUserRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodPost, "/users", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUser 获取用户详情
+//
+// GET /users/{id}（权限 users:read）
+func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateUser 更新用户
+//
+// PUT /users/{id}（权限 users:manage）
+func (c *Client) UpdateUser(ctx context.Context, id string, body *UpdateUserRequest) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodPut, "/users/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteUser 删除用户
+//
+// DELETE /users/{id}（权限 users:manage）
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// CreateServiceAccountToken 为服务账号创建 API Token，权限不能超出服务账号角色的权限
+//
+// POST /users/{id}/tokens（权限 users:manage）
+func (c *Client) CreateServiceAccountToken(ctx context.Context, id string, body *CreateAPITokenRequest) (*CreateAPITokenResponse, error) {
+	var out CreateAPITokenResponse
+	if err := c.do(ctx, http.MethodPost, "/users/"+url.PathEscape(id)+"/tokens", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResetTOTP 清除用户的两步验证（用户丢失认证器时由管理员重置），并吊销其全部会话
+//
+// DELETE /users/{id}/totp（权限 users:manage）
+func (c *Client) ResetTOTP(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/users/"+url.PathEscape(id)+"/totp", nil, nil, nil)
+}
@@ -0,0 +1,11 @@
+// Package openapi 内嵌 Manager API 的 OpenAPI 3 文档
+//
+// openapi.json 由 cmd/openapi-gen 从 router 和 api 包源码生成（make openapi），请勿手工修改
+package openapi
+
+import _ "embed"
+
+// Spec OpenAPI 文档（JSON）
+//
+//go:embed openapi.json
+var Spec []byte
@@ -15,9 +15,12 @@ import (
 	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
 	"github.com/imkerbos/mxsec-platform/internal/agent/config"
 	"github.com/imkerbos/mxsec-platform/internal/agent/connection"
+	"github.com/imkerbos/mxsec-platform/internal/agent/diagnostics"
+	"github.com/imkerbos/mxsec-platform/internal/agent/guard"
 	"github.com/imkerbos/mxsec-platform/internal/agent/heartbeat"
 	"github.com/imkerbos/mxsec-platform/internal/agent/id"
 	"github.com/imkerbos/mxsec-platform/internal/agent/logger"
+	"github.com/imkerbos/mxsec-platform/internal/agent/offlinescan"
 	"github.com/imkerbos/mxsec-platform/internal/agent/plugin"
 	"github.com/imkerbos/mxsec-platform/internal/agent/transport"
 	"github.com/imkerbos/mxsec-platform/internal/agent/updater"
@@ -29,18 +32,35 @@ var (
 	updateForce  = flag.Bool("force", false, "强制更新（即使版本相同，需配合 --update 使用）")
 	updateFile   = flag.String("file", "", "使用本地包文件更新（离线模式，需配合 --update 使用）")
 	updateServer = flag.String("server", "", "指定 Server HTTP 地址（如 http://10.0.0.1:8080，需配合 --update 使用）")
+	scan         = flag.Bool("scan", false, "离线执行基线检查并生成签名结果包（无法连接 Server 的主机使用）")
+	scanPolicy   = flag.String("policy", "", "策略 JSON 文件或目录（Manager 策略导出文件，需配合 --scan 使用）")
+	scanOutput   = flag.String("output", "", "结果包输出路径（需配合 --scan 使用，默认写入当前目录）")
+	scanKey      = flag.String("key", "", "本机签名密钥文件（需配合 --scan 使用，在 Manager 按本机 Agent ID 生成）")
+	printID      = flag.Bool("print-id", false, "输出本机 Agent ID 后退出（离线检查前在 Manager 按该 ID 生成签名密钥）")
 )
 
 // 构建时嵌入的变量（通过 -ldflags 设置）
 // Server 在部署时生成配置，编译时嵌入到 Agent 二进制
 // 示例: go build -ldflags "-X main.serverHost=10.0.0.1:6751 -X main.buildVersion=1.0.0" ./cmd/agent
+// 多 AgentCenter 部署时额外嵌入 discoveryURL（Manager HTTP 地址），serverHost 作为服务发现不可用时的兜底地址
 var (
 	serverHost   string // Server 地址（构建时嵌入，必须）
+	discoveryURL string // 服务发现地址（构建时嵌入，可选）
+	region       string // Agent 所在区域（构建时嵌入，可选，用于筛选接入点）
 	buildVersion string // 构建版本（构建时嵌入）
 	buildTime    string // 构建时间（构建时嵌入）
 )
 
 func main() {
+	// 插件沙箱启动器模式：设置 no_new_privs / 丢弃 capabilities 后 exec 插件（由插件管理器调用）
+	if len(os.Args) > 1 && os.Args[1] == plugin.SandboxExecArg {
+		if err := plugin.RunSandboxExec(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "plugin sandbox exec failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	flag.Parse()
 
 	if *version {
@@ -69,6 +89,36 @@ func main() {
 		return
 	}
 
+	if *printID {
+		agentID, err := offlinescan.AgentID(config.LoadDefaults().Local.IDFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(agentID)
+		return
+	}
+
+	// 离线检查模式：独立执行路径，不启动 Agent 服务
+	if *scan {
+		currentVer := buildVersion
+		if currentVer == "" {
+			currentVer = "dev"
+		}
+		opts := offlinescan.ScanOptions{
+			PolicyPath:   *scanPolicy,
+			OutputPath:   *scanOutput,
+			KeyFile:      *scanKey,
+			IDFile:       config.LoadDefaults().Local.IDFile,
+			AgentVersion: currentVer,
+		}
+		if err := offlinescan.RunScan(opts); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 1. 验证构建时嵌入的配置（必须）
 	if serverHost == "" {
 		panic("serverHost must be embedded at build time, use -ldflags \"-X main.serverHost=HOST:PORT\"")
@@ -77,6 +127,8 @@ func main() {
 	// 2. 加载默认配置（完全依赖构建时嵌入，不需要配置文件）
 	cfg := config.LoadDefaults()
 	cfg.Local.Server.AgentCenter.PrivateHost = serverHost
+	cfg.Local.Server.ServiceDiscovery.URL = discoveryURL
+	cfg.Local.Server.ServiceDiscovery.Region = region
 	// 设置构建时嵌入的版本
 	if buildVersion != "" {
 		cfg.BuildVersion = buildVersion
@@ -98,6 +150,7 @@ func main() {
 		zap.String("version", cfg.GetVersion()),
 		zap.String("product", cfg.GetProduct()),
 		zap.String("server", serverHost),
+		zap.String("discovery_url", discoveryURL),
 		zap.Bool("remote_config_loaded", cfg.Remote.Loaded),
 	)
 
@@ -109,7 +162,7 @@ func main() {
 	log.Info("Agent ID initialized", zap.String("agent_id", agentID))
 
 	// 5. 创建连接管理器
-	connMgr := connection.NewManager(cfg, log)
+	connMgr := connection.NewManager(cfg, log, agentID)
 
 	// 6. 创建传输管理器（用于心跳模块）
 	transportMgr, err := transport.NewManager(cfg, log, connMgr, agentID)
@@ -156,10 +209,14 @@ func main() {
 	defer cancel()
 
 	wg := &sync.WaitGroup{}
-	wg.Add(4)
+	wg.Add(6)
 
-	// 心跳模块（传递插件管理器引用）
-	go heartbeat.Startup(ctx, wg, cfg, log, transportMgr, agentID, pluginMgr)
+	// 资源保护模块（超出资源预算或主机负载过高时限流插件，达到熔断阈值时停止插件）
+	resourceGuard := guard.New(cfg.Local.Guard, log, pluginMgr)
+	go guard.Startup(ctx, wg, resourceGuard)
+
+	// 心跳模块（传递插件管理器和资源保护器引用）
+	go heartbeat.Startup(ctx, wg, cfg, log, transportMgr, agentID, pluginMgr, resourceGuard)
 
 	// 传输模块（使用已创建的传输管理器）
 	go transport.StartupWithManager(ctx, wg, transportMgr)
@@ -170,6 +227,13 @@ func main() {
 	// 自更新模块（监听来自 Server 的更新命令）
 	go updater.Startup(ctx, wg, log, transportMgr.GetAgentUpdateChannel(), cfg.GetVersion(), cfg.GetWorkDir())
 
+	// 诊断模块（监听来自 Server 的诊断命令，收集并上传诊断包）
+	diagCollector := diagnostics.NewCollector(cfg, log, connMgr, transportMgr, pluginMgr, agentID)
+	go diagnostics.Startup(ctx, wg, diagCollector)
+
+	// 接入点重新均衡（未配置服务发现时立即返回）
+	go connMgr.RunRebalance(ctx)
+
 	// 9. 信号处理
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, syscall.SIGTERM, syscall.SIGINT)
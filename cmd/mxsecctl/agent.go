@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+func agentGroup() *group {
+	return &group{
+		name:  "agent",
+		short: "Agent 管理",
+		commands: []*command{
+			{name: "update", short: "向主机推送 Agent 更新（不指定主机时推送给所有在线主机）", run: agentUpdate},
+		},
+	}
+}
+
+func agentUpdate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("agent", "update", "")
+	var hosts listFlag
+	fs.Var(&hosts, "host", "目标主机 ID（可重复或逗号分隔）")
+	force := fs.Bool("force", false, "强制更新（即使版本相同也更新）")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	result, err := a.client.PushAgentUpdate(ctx, &v1.PushAgentUpdateRequest{HostIds: hosts, Force: *force})
+	if err != nil {
+		return err
+	}
+	if err := a.print(result, func() *table {
+		t := newTable("VERSION", "TOTAL", "SUCCESS", "FAILED")
+		t.add(result.LatestVersion, result.Total, result.Success, result.Failed)
+		return t
+	}); err != nil {
+		return err
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d 台主机推送失败", result.Failed)
+	}
+	return nil
+}
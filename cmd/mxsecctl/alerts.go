@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+// alertsGroup 告警处置
+//
+// 平台没有独立的豁免（waiver）模型：接受某个检查项的风险时忽略对应告警，
+// 忽略只影响告警和通知，不改变检查结果和基线得分
+func alertsGroup() *group {
+	return &group{
+		name:  "alerts",
+		short: "告警查询和处置（忽略/解决）",
+		commands: []*command{
+			{name: "list", short: "查询告警列表", run: alertsList},
+			{name: "ignore", args: "<id...>", short: "忽略告警（接受风险，不再通知）", run: alertsIgnore},
+			{name: "resolve", args: "<id...>", short: "标记告警已解决", run: alertsResolve},
+		},
+	}
+}
+
+func alertsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("alerts", "list", "")
+	params := v1.ListAlertsParams{}
+	fs.StringVar(&params.Status, "status", "active", "状态：active, resolved, ignored，空表示全部")
+	fs.StringVar(&params.Severity, "severity", "", "严重级别：critical, high, medium, low")
+	fs.StringVar(&params.HostID, "host", "", "主机 ID")
+	fs.StringVar(&params.RuleID, "rule", "", "规则 ID")
+	fs.StringVar(&params.AlertType, "type", "", "告警类型：baseline, agent_offline")
+	fs.StringVar(&params.Keyword, "keyword", "", "搜索标题或描述")
+	page := fs.Int64("page", 1, "页码")
+	pageSize := fs.Int64("page-size", 50, "每页数量")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	params.Page, params.PageSize = *page, *pageSize
+
+	result, err := a.client.ListAlerts(ctx, &params)
+	if err != nil {
+		return err
+	}
+	return a.print(result, func() *table {
+		t := newTable("ID", "HOSTNAME", "RULE_ID", "SEVERITY", "STATUS", "TITLE", "LAST_SEEN_AT")
+		for _, al := range result.Items {
+			t.add(al.ID, al.Host.Hostname, al.RuleID, al.Severity, al.Status, al.Title, al.LastSeenAt)
+		}
+		return t
+	})
+}
+
+func alertsIgnore(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("alerts", "ignore", "<id...>")
+	pos, err := parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	ids, err := alertIDs(pos)
+	if err != nil {
+		return err
+	}
+	if err := a.client.BatchIgnoreAlerts(ctx, &v1.BatchAlertRequest{Ids: ids}); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "已忽略 %d 条告警\n", len(ids))
+	return nil
+}
+
+func alertsResolve(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("alerts", "resolve", "<id...>")
+	reason := fs.String("reason", "", "解决原因")
+	pos, err := parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	ids, err := alertIDs(pos)
+	if err != nil {
+		return err
+	}
+	if err := a.client.BatchResolveAlerts(ctx, &v1.BatchAlertRequest{Ids: ids, Reason: *reason}); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "已解决 %d 条告警\n", len(ids))
+	return nil
+}
+
+func alertIDs(args []string) ([]int64, error) {
+	ids := make([]int64, 0, len(args))
+	for _, s := range args {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, usagef("无效的告警 ID: %s", s)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
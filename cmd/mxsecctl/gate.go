@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+func gateGroup() *group {
+	return &group{
+		name:  "gate",
+		short: "CI 门禁检查，未通过时退出码为 3",
+		commands: []*command{
+			{name: "score", short: "检查业务线基线得分是否达到阈值", run: gateScore},
+		},
+	}
+}
+
+// scoreGateHost 得分低于阈值的主机
+type scoreGateHost struct {
+	HostID   string `json:"host_id"`
+	Hostname string `json:"hostname"`
+	Score    int64  `json:"score"`
+}
+
+// scoreGateResult 业务线得分检查结果
+type scoreGateResult struct {
+	BusinessLine string          `json:"business_line"`
+	Hosts        int             `json:"hosts"`
+	ScannedHosts int             `json:"scanned_hosts"`
+	Score        float64         `json:"score"`
+	Min          float64         `json:"min"`
+	Passed       bool            `json:"passed"`
+	LowHosts     []scoreGateHost `json:"low_hosts,omitempty"`
+}
+
+func gateScore(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("gate", "score", "")
+	var businessLines listFlag
+	fs.Var(&businessLines, "business-line", "业务线代码（必填，可重复或逗号分隔，每条业务线分别检查）")
+	minScore := fs.Float64("min", 0, "最低得分（0-100，必填）")
+	allowEmpty := fs.Bool("allow-empty", false, "业务线没有已扫描主机时视为通过")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if len(businessLines) == 0 || *minScore <= 0 || *minScore > 100 {
+		return usagef("-business-line 为必填参数，-min 取值范围为 (0, 100]")
+	}
+
+	results := make([]*scoreGateResult, 0, len(businessLines))
+	var failed []string
+	for _, bl := range businessLines {
+		r, err := a.businessLineScore(ctx, bl, *minScore)
+		if err != nil {
+			return err
+		}
+		if r.ScannedHosts == 0 && *allowEmpty {
+			r.Passed = true
+		}
+		if !r.Passed {
+			failed = append(failed, bl)
+		}
+		results = append(results, r)
+	}
+
+	if err := a.print(results, func() *table {
+		t := newTable("BUSINESS_LINE", "HOSTS", "SCANNED", "SCORE", "MIN", "RESULT")
+		for _, r := range results {
+			result := "PASS"
+			if !r.Passed {
+				result = "FAIL"
+			}
+			t.add(r.BusinessLine, r.Hosts, r.ScannedHosts, r.Score, r.Min, result)
+		}
+		return t
+	}); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		if a.output == "table" {
+			for _, r := range results {
+				for _, h := range r.LowHosts {
+					fmt.Fprintf(a.stderr, "  %s %s（%s）得分 %d\n", r.BusinessLine, h.Hostname, h.HostID, h.Score)
+				}
+			}
+		}
+		return failedf("业务线 %s 基线得分未达到 %.1f", strings.Join(failed, ", "), *minScore)
+	}
+	return nil
+}
+
+// businessLineScore 计算业务线得分：已扫描主机基线得分的平均值，没有检查结果的主机不计入
+func (a *app) businessLineScore(ctx context.Context, businessLine string, minScore float64) (*scoreGateResult, error) {
+	hosts, err := listAllHosts(ctx, a.client, v1.ListHostsParams{BusinessLine: businessLine})
+	if err != nil {
+		return nil, err
+	}
+	r := &scoreGateResult{BusinessLine: businessLine, Hosts: len(hosts), Min: minScore}
+	var total int64
+	for _, h := range hosts {
+		// 列表中得分为 0 的主机可能尚未扫描，查询得分明细区分
+		if h.BaselineScore == 0 {
+			score, err := a.client.GetHostBaselineScore(ctx, h.HostID)
+			if err != nil {
+				return nil, err
+			}
+			if score.TotalRules == 0 {
+				continue
+			}
+		}
+		r.ScannedHosts++
+		total += h.BaselineScore
+		if float64(h.BaselineScore) < minScore {
+			r.LowHosts = append(r.LowHosts, scoreGateHost{HostID: h.HostID, Hostname: h.Hostname, Score: h.BaselineScore})
+		}
+	}
+	if r.ScannedHosts > 0 {
+		r.Score = float64(total) / float64(r.ScannedHosts)
+		r.Passed = r.Score >= minScore
+	}
+	return r, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+// listPageSize 查询全部页时的每页数量
+const listPageSize = 100
+
+func hostsGroup() *group {
+	return &group{
+		name:  "hosts",
+		short: "主机查询",
+		commands: []*command{
+			{name: "list", short: "查询主机列表", run: hostsList},
+			{name: "get", args: "<host_id>", short: "查看主机详情", run: hostsGet},
+		},
+	}
+}
+
+func hostsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("hosts", "list", "")
+	params := v1.ListHostsParams{}
+	fs.StringVar(&params.Status, "status", "", "主机状态：online, offline")
+	fs.StringVar(&params.OSFamily, "os", "", "OS 系列（如 rocky, ubuntu）")
+	fs.StringVar(&params.BusinessLine, "business-line", "", "业务线代码（__unbound__ 表示未绑定业务线）")
+	fs.StringVar(&params.Search, "search", "", "按主机名或主机 ID 模糊搜索")
+	fs.StringVar(&params.RuntimeType, "runtime", "", "运行环境：vm, docker, k8s")
+	page := fs.Int("page", 1, "页码")
+	pageSize := fs.Int("page-size", 50, "每页数量")
+	all := fs.Bool("all", false, "查询全部页（忽略 -page 和 -page-size）")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var result *v1.ListHostsResult
+	if *all {
+		items, err := listAllHosts(ctx, a.client, params)
+		if err != nil {
+			return err
+		}
+		result = &v1.ListHostsResult{Total: int64(len(items)), Items: items}
+	} else {
+		params.Page = strconv.Itoa(*page)
+		params.PageSize = strconv.Itoa(*pageSize)
+		var err error
+		if result, err = a.client.ListHosts(ctx, &params); err != nil {
+			return err
+		}
+	}
+
+	return a.print(result, func() *table {
+		t := newTable("HOST_ID", "HOSTNAME", "IP", "OS", "STATUS", "BUSINESS_LINE", "AGENT", "SCORE")
+		for _, h := range result.Items {
+			t.add(h.HostID, h.Hostname, firstOf(h.IPv4), h.OSFamily+" "+h.OSVersion, h.Status, h.BusinessLine, h.AgentVersion, h.BaselineScore)
+		}
+		return t
+	})
+}
+
+func hostsGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("hosts", "get", "<host_id>")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	host, err := a.client.GetHost(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return a.print(host, func() *table {
+		t := newTable()
+		t.add("主机 ID", host.HostID)
+		t.add("主机名", host.Hostname)
+		t.add("状态", host.Status)
+		t.add("系统", host.OSFamily+" "+host.OSVersion)
+		t.add("内核", host.KernelVersion)
+		t.add("架构", host.Arch)
+		t.add("IPv4", host.IPv4)
+		t.add("业务线", host.BusinessLine)
+		t.add("标签", host.Tags)
+		t.add("Agent 版本", host.AgentVersion)
+		t.add("最后心跳", host.LastHeartbeat)
+		t.add("基线检查结果", len(host.BaselineResults))
+		return t
+	})
+}
+
+// listAllHosts 分页查询全部符合条件的主机
+func listAllHosts(ctx context.Context, client *v1.Client, params v1.ListHostsParams) ([]v1.HostListItem, error) {
+	var items []v1.HostListItem
+	params.PageSize = strconv.Itoa(listPageSize)
+	for page := 1; ; page++ {
+		params.Page = strconv.Itoa(page)
+		result, err := client.ListHosts(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, result.Items...)
+		if len(result.Items) < listPageSize || int64(len(items)) >= result.Total {
+			return items, nil
+		}
+	}
+}
+
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
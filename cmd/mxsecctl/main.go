@@ -0,0 +1,341 @@
+// Package main 是平台命令行客户端 mxsecctl
+//
+// 通过 Manager API（api/client/v1）操作平台，使用个人或服务账号 API Token 认证：
+//
+//	export MXSEC_SERVER=https://mxsec.example.com
+//	export MXSEC_TOKEN=mxs_xxx
+//	mxsecctl hosts list -business-line payment
+//	mxsecctl tasks create -name nightly -policy LINUX_BASELINE -all-hosts -run -wait
+//	mxsecctl gate score -business-line payment -min 80
+//
+// 退出码适合在 CI 中使用：0 成功，1 请求失败，2 用法错误，3 检查未通过（得分低于阈值、任务失败或等待超时），
+// 4 操作已提交审批
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+// 退出码
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitUsage   = 2
+	exitFailed  = 3
+	exitPending = 4
+)
+
+// buildVersion 构建版本（通过 -ldflags "-X main.buildVersion=1.0.0" 设置）
+var buildVersion string
+
+// command 子命令
+type command struct {
+	name  string
+	args  string // 位置参数说明
+	short string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+// group 命令组（如 hosts、tasks）
+type group struct {
+	name     string
+	short    string
+	commands []*command
+}
+
+// groups 返回全部命令组，按帮助信息中的顺序排列
+func groups() []*group {
+	return []*group{
+		hostsGroup(),
+		tasksGroup(),
+		resultsGroup(),
+		policiesGroup(),
+		agentGroup(),
+		usersGroup(),
+		alertsGroup(),
+		gateGroup(),
+	}
+}
+
+// app 命令执行上下文
+type app struct {
+	client *v1.Client
+	output string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// usageError 参数错误，退出码为 exitUsage；msg 为空表示 flag 包已输出错误信息
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	if e.msg == "" {
+		return "参数错误"
+	}
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// failedError 检查未通过，退出码为 exitFailed
+type failedError struct {
+	msg string
+}
+
+func (e *failedError) Error() string {
+	return e.msg
+}
+
+func failedf(format string, args ...interface{}) error {
+	return &failedError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run 解析参数并执行命令，返回退出码
+func run(ctx context.Context, argv []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("mxsecctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	server := fs.String("server", os.Getenv("MXSEC_SERVER"), "Manager 地址（默认读取环境变量 MXSEC_SERVER）")
+	token := fs.String("token", os.Getenv("MXSEC_TOKEN"), "API Token（默认读取环境变量 MXSEC_TOKEN）")
+	output := fs.String("o", "table", "输出格式：table, json, yaml")
+	timeout := fs.Duration("timeout", 60*time.Second, "单个请求超时时间")
+	fs.Usage = func() { printUsage(stderr, fs) }
+	if err := fs.Parse(argv); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	args := fs.Args()
+	if len(args) == 0 {
+		printUsage(stderr, fs)
+		return exitUsage
+	}
+	if args[0] == "version" {
+		fmt.Fprintf(stdout, "mxsecctl %s（API %s）\n", versionString(), v1.APIVersion)
+		return exitOK
+	}
+	if args[0] == "help" {
+		printUsage(stdout, fs)
+		return exitOK
+	}
+
+	g := findGroup(args[0])
+	if g == nil {
+		fmt.Fprintf(stderr, "未知命令: %s\n", args[0])
+		printUsage(stderr, fs)
+		return exitUsage
+	}
+	if len(args) < 2 || args[1] == "help" || args[1] == "-h" || args[1] == "--help" {
+		printGroupUsage(stderr, g)
+		if len(args) < 2 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	cmd := findCommand(g, args[1])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "未知命令: %s %s\n", g.name, args[1])
+		printGroupUsage(stderr, g)
+		return exitUsage
+	}
+
+	switch *output {
+	case "table", "json", "yaml":
+	default:
+		fmt.Fprintf(stderr, "不支持的输出格式: %s（可选 table, json, yaml）\n", *output)
+		return exitUsage
+	}
+	if *server == "" && !wantsHelp(args[2:]) {
+		fmt.Fprintln(stderr, "未指定 Manager 地址，请使用 -server 或设置环境变量 MXSEC_SERVER")
+		return exitUsage
+	}
+	if *token == "" && !wantsHelp(args[2:]) {
+		fmt.Fprintln(stderr, "未指定 API Token，请使用 -token 或设置环境变量 MXSEC_TOKEN")
+		return exitUsage
+	}
+
+	a := &app{
+		client: v1.New(*server, *token,
+			v1.WithHTTPClient(&http.Client{Timeout: *timeout}),
+			v1.WithUserAgent("mxsecctl/"+versionString()),
+		),
+		output: *output,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	err := cmd.run(ctx, a, args[2:])
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	var usage *usageError
+	if !errors.As(err, &usage) || usage.msg != "" {
+		fmt.Fprintf(stderr, "错误: %v\n", err)
+	}
+	return exitCode(err)
+}
+
+// exitCode 根据错误类型返回退出码
+func exitCode(err error) int {
+	var usage *usageError
+	var failed *failedError
+	var pending *v1.PendingApprovalError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.As(err, &failed):
+		return exitFailed
+	case errors.As(err, &pending):
+		return exitPending
+	default:
+		return exitError
+	}
+}
+
+// wantsHelp 判断子命令参数是否请求帮助（无需连接 Manager）
+func wantsHelp(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "-h", "-help", "--help":
+			return true
+		}
+	}
+	return false
+}
+
+func versionString() string {
+	if buildVersion == "" {
+		return "dev"
+	}
+	return buildVersion
+}
+
+func findGroup(name string) *group {
+	for _, g := range groups() {
+		if g.name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+func findCommand(g *group, name string) *command {
+	for _, c := range g.commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "mxsecctl - 安全平台命令行客户端")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "用法: mxsecctl [全局参数] <命令> <子命令> [参数]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "命令:")
+	for _, g := range groups() {
+		fmt.Fprintf(w, "  %-10s %s\n", g.name, g.short)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "version", "显示版本信息")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "全局参数:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "退出码: 0 成功, 1 请求失败, 2 用法错误, 3 检查未通过, 4 操作已提交审批")
+	fmt.Fprintln(w, "使用 \"mxsecctl <命令> help\" 查看子命令")
+}
+
+func printGroupUsage(w io.Writer, g *group) {
+	fmt.Fprintf(w, "%s - %s\n\n子命令:\n", g.name, g.short)
+	for _, c := range g.commands {
+		name := c.name
+		if c.args != "" {
+			name += " " + c.args
+		}
+		fmt.Fprintf(w, "  %-28s %s\n", name, c.short)
+	}
+	fmt.Fprintf(w, "\n使用 \"mxsecctl %s <子命令> -h\" 查看参数\n", g.name)
+}
+
+// flags 创建子命令参数解析器
+func (a *app) flags(g, cmd, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(g+" "+cmd, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "用法: %s\n\n参数:\n", strings.TrimSpace("mxsecctl "+g+" "+cmd+" [参数] "+args))
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse 解析子命令参数，返回位置参数；位置参数可以出现在选项之前
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) < minArgs || (maxArgs >= 0 && len(positional) > maxArgs) {
+		fs.Usage()
+		return nil, usagef("参数个数不正确")
+	}
+	return positional, nil
+}
+
+// listFlag 可重复或以逗号分隔的多值参数
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, status int, data interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "ok", "data": data})
+	}
+	mux.HandleFunc("/api/v1/hosts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		if bl := r.URL.Query().Get("business_line"); bl != "" && bl != "pay" {
+			reply(w, http.StatusOK, map[string]interface{}{"total": 0, "items": []interface{}{}})
+			return
+		}
+		reply(w, http.StatusOK, map[string]interface{}{
+			"total": 3,
+			"items": []map[string]interface{}{
+				{"host_id": "h1", "hostname": "pay-01", "status": "online", "business_line": "pay", "baseline_score": 90},
+				{"host_id": "h2", "hostname": "pay-02", "status": "online", "business_line": "pay", "baseline_score": 70},
+				{"host_id": "h3", "hostname": "pay-03", "status": "offline", "business_line": "pay", "baseline_score": 0},
+			},
+		})
+	})
+	mux.HandleFunc("/api/v1/results/host/h3/score", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, map[string]interface{}{"host_id": "h3", "baseline_score": 0, "total_rules": 0})
+	})
+	mux.HandleFunc("/api/v1/components/agent/push-update", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusAccepted, map[string]interface{}{"approval_id": 7, "status": "pending"})
+	})
+	return httptest.NewServer(mux)
+}
+
+func runCLI(srv *httptest.Server, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	argv := append([]string{"-server", srv.URL, "-token", "test-token"}, args...)
+	code := run(context.Background(), argv, strings.NewReader(""), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestGateScore 测试业务线得分门禁：未扫描主机不计入平均分
+func TestGateScore(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	code, out, stderr := runCLI(srv, "-o", "json", "gate", "score", "-business-line", "pay", "-min", "80")
+	if code != exitOK {
+		t.Fatalf("expected exit %d, got %d: %s", exitOK, code, stderr)
+	}
+	var results []scoreGateResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(results) != 1 || results[0].Hosts != 3 || results[0].ScannedHosts != 2 || results[0].Score != 80 {
+		t.Fatalf("unexpected result: %+v", results)
+	}
+
+	if code, _, _ := runCLI(srv, "gate", "score", "-business-line", "pay", "-min", "85"); code != exitFailed {
+		t.Fatalf("expected exit %d below threshold, got %d", exitFailed, code)
+	}
+	if code, _, _ := runCLI(srv, "gate", "score", "-business-line", "empty", "-min", "80"); code != exitFailed {
+		t.Fatalf("expected exit %d for business line without scanned hosts, got %d", exitFailed, code)
+	}
+	if code, _, _ := runCLI(srv, "gate", "score", "-business-line", "empty", "-min", "80", "-allow-empty"); code != exitOK {
+		t.Fatalf("expected exit %d with -allow-empty, got %d", exitOK, code)
+	}
+	if code, _, _ := runCLI(srv, "gate", "score", "-min", "80"); code != exitUsage {
+		t.Fatalf("expected exit %d without business line, got %d", exitUsage, code)
+	}
+}
+
+// TestOutputAndExitCodes 测试 YAML 输出和审批、用法错误的退出码
+func TestOutputAndExitCodes(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	code, out, stderr := runCLI(srv, "-o", "yaml", "hosts", "list", "-business-line", "pay")
+	if code != exitOK {
+		t.Fatalf("expected exit %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(out, "total: 3\n") || !strings.Contains(out, "host_id: h1\n") {
+		t.Fatalf("unexpected yaml output:\n%s", out)
+	}
+
+	code, _, stderr = runCLI(srv, "agent", "update", "-host", "h1")
+	if code != exitPending || !strings.Contains(stderr, "#7") {
+		t.Fatalf("expected exit %d with approval id, got %d: %s", exitPending, code, stderr)
+	}
+
+	if code, _, _ := runCLI(srv, "hosts", "unknown"); code != exitUsage {
+		t.Fatalf("expected exit %d for unknown command, got %d", exitUsage, code)
+	}
+	if code, _, _ := runCLI(srv, "-o", "xml", "hosts", "list"); code != exitUsage {
+		t.Fatalf("expected exit %d for unknown output format, got %d", exitUsage, code)
+	}
+}
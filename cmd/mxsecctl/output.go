@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// table 表格输出
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func newTable(header ...string) *table {
+	return &table{header: header}
+}
+
+// add 添加一行，单元格按 cell 规则格式化
+func (t *table) add(cols ...interface{}) {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = cell(c)
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *table) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(t.header) > 0 {
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// cell 格式化单元格：空值显示为 -，多值用逗号连接，换行替换为空格
+func cell(v interface{}) string {
+	var s string
+	switch x := v.(type) {
+	case nil:
+		s = ""
+	case string:
+		s = x
+	case *string:
+		if x != nil {
+			s = *x
+		}
+	case []string:
+		s = strings.Join(x, ",")
+	case bool:
+		if x {
+			s = "是"
+		} else {
+			s = "否"
+		}
+	case float64:
+		s = fmt.Sprintf("%.1f", x)
+	default:
+		s = fmt.Sprint(x)
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// print 按输出格式输出 v；table 格式使用 tbl 生成表格
+func (a *app) print(v interface{}, tbl func() *table) error {
+	switch a.output {
+	case "json":
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	case "yaml":
+		return writeYAML(a.stdout, v)
+	default:
+		return tbl().write(a.stdout)
+	}
+}
+
+// writeYAML 以 YAML 输出 v；先编码为 JSON，字段名和顺序与 JSON 输出一致
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle 清除 JSON 解析得到的流式和引号风格，按 YAML 默认风格输出
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+func policiesGroup() *group {
+	return &group{
+		name:  "policies",
+		short: "基线策略导入导出",
+		commands: []*command{
+			{name: "list", short: "查询策略列表", run: policiesList},
+			{name: "export", args: "[policy_id...]", short: "导出策略 JSON（不指定 ID 时导出全部）", run: policiesExport},
+			{name: "import", short: "从 JSON 文件导入策略", run: policiesImport},
+		},
+	}
+}
+
+func policiesList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("policies", "list", "")
+	params := v1.ListPoliciesParams{}
+	fs.StringVar(&params.OSFamily, "os", "", "OS 系列")
+	fs.StringVar(&params.Enabled, "enabled", "", "是否启用：true, false")
+	fs.StringVar(&params.GroupID, "group", "", "策略组 ID")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	result, err := a.client.ListPolicies(ctx, &params)
+	if err != nil {
+		return err
+	}
+	return a.print(result, func() *table {
+		t := newTable("ID", "NAME", "VERSION", "OS_FAMILY", "ENABLED", "RULES", "GROUP_ID")
+		for _, p := range result.Items {
+			t.add(p["id"], p["name"], p["version"], p["os_family"], p["enabled"], p["rule_count"], p["group_id"])
+		}
+		return t
+	})
+}
+
+func policiesExport(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("policies", "export", "[policy_id...]")
+	file := fs.String("f", "", "输出文件（默认输出到标准输出）")
+	ids, err := parse(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	// 导出文件固定为 JSON，可直接用于 policies import 和 Agent 离线扫描
+	var export interface{}
+	switch len(ids) {
+	case 0:
+		export, err = a.client.ExportAllPolicies(ctx)
+	case 1:
+		export, err = a.client.ExportPolicy(ctx, ids[0])
+	default:
+		export, err = a.client.BatchExport(ctx, &v1.BatchExportRequest{PolicyIds: ids})
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *file == "" {
+		_, err := a.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*file, data, 0o644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", *file, err)
+	}
+	fmt.Fprintf(a.stderr, "已导出到 %s\n", *file)
+	return nil
+}
+
+func policiesImport(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("policies", "import", "")
+	file := fs.String("f", "", "策略 JSON 文件（必填，单个策略或策略数组）")
+	params := v1.ImportPolicyParams{}
+	fs.StringVar(&params.GroupID, "group", "", "目标策略组 ID（必填）")
+	fs.StringVar(&params.Mode, "mode", "skip", "策略已存在时：skip 跳过, update 更新, replace 替换规则")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *file == "" || params.GroupID == "" {
+		return usagef("-f 和 -group 为必填参数")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", *file, err)
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", filepath.Base(*file))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(content); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	result, err := a.client.ImportPolicy(ctx, &params, mw.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	if err := a.print(result, func() *table {
+		t := newTable("TOTAL", "IMPORTED", "UPDATED", "SKIPPED", "ERRORS")
+		t.add(result.Total, result.Imported, result.Updated, result.Skipped, len(result.Errors))
+		return t
+	}); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		for _, e := range result.Errors {
+			fmt.Fprintf(a.stderr, "导入失败: %s\n", e)
+		}
+		return fmt.Errorf("%d 个策略导入失败", len(result.Errors))
+	}
+	return nil
+}
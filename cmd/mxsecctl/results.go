@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+func resultsGroup() *group {
+	return &group{
+		name:  "results",
+		short: "基线检查结果",
+		commands: []*command{
+			{name: "list", short: "查询检查结果", run: resultsList},
+			{name: "export", args: "<host_id>", short: "导出主机未通过的检查项（Excel 或 Markdown）", run: resultsExport},
+		},
+	}
+}
+
+func resultsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("results", "list", "")
+	params := v1.ListResultsParams{}
+	fs.StringVar(&params.HostID, "host", "", "主机 ID")
+	fs.StringVar(&params.TaskID, "task", "", "任务 ID")
+	fs.StringVar(&params.PolicyID, "policy", "", "策略 ID")
+	fs.StringVar(&params.RuleID, "rule", "", "规则 ID")
+	fs.StringVar(&params.Status, "status", "", "检查状态：pass, fail, error, na")
+	fs.StringVar(&params.Severity, "severity", "", "严重级别：critical, high, medium, low")
+	page := fs.Int("page", 1, "页码")
+	pageSize := fs.Int("page-size", 50, "每页数量")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	params.Page = strconv.Itoa(*page)
+	params.PageSize = strconv.Itoa(*pageSize)
+
+	result, err := a.client.ListResults(ctx, &params)
+	if err != nil {
+		return err
+	}
+	return a.print(result, func() *table {
+		t := newTable("HOSTNAME", "RULE_ID", "SEVERITY", "STATUS", "TITLE", "CHECKED_AT")
+		for _, r := range result.Items {
+			t.add(r.Hostname, r.RuleID, r.Severity, r.Status, r.Title, r.CheckedAt)
+		}
+		return t
+	})
+}
+
+func resultsExport(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("results", "export", "<host_id>")
+	format := fs.String("format", "excel", "导出格式：excel, markdown")
+	file := fs.String("f", "", "输出文件（默认 Excel 写入 <host_id>.xlsx，Markdown 输出到标准输出）")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	hostID := pos[0]
+	switch *format {
+	case "excel":
+		if *file == "" {
+			*file = hostID + ".xlsx"
+		}
+	case "markdown":
+	default:
+		return usagef("不支持的导出格式: %s（可选 excel, markdown）", *format)
+	}
+
+	data, err := a.client.ExportHostBaselineResults(ctx, hostID, &v1.ExportHostBaselineResultsParams{Format: *format})
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		_, err := a.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*file, data, 0o644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", *file, err)
+	}
+	fmt.Fprintf(a.stderr, "已导出到 %s\n", *file)
+	return nil
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+func tasksGroup() *group {
+	return &group{
+		name:  "tasks",
+		short: "基线扫描任务",
+		commands: []*command{
+			{name: "list", short: "查询任务列表", run: tasksList},
+			{name: "get", args: "<task_id>", short: "查看任务详情", run: tasksGet},
+			{name: "create", short: "创建扫描任务（可立即执行并等待完成）", run: tasksCreate},
+			{name: "run", args: "<task_id>", short: "执行任务", run: tasksRun},
+			{name: "wait", args: "<task_id>", short: "等待任务结束，任务未成功完成时退出码为 3", run: tasksWait},
+			{name: "status", args: "<task_id>", short: "查看各主机执行状态（-follow 持续输出状态变化）", run: tasksStatus},
+			{name: "cancel", args: "<task_id>", short: "取消任务", run: tasksCancel},
+		},
+	}
+}
+
+// waitOptions 等待任务结束的参数
+type waitOptions struct {
+	timeout  time.Duration
+	interval time.Duration
+}
+
+func (o *waitOptions) register(fs *flag.FlagSet) {
+	fs.DurationVar(&o.timeout, "wait-timeout", 30*time.Minute, "等待超时时间，0 表示不限制")
+	fs.DurationVar(&o.interval, "interval", 5*time.Second, "轮询间隔")
+}
+
+func tasksList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "list", "")
+	params := v1.ListTasksParams{}
+	fs.StringVar(&params.Status, "status", "", "任务状态：created, pending, running, completed, failed, cancelled")
+	fs.StringVar(&params.PolicyID, "policy", "", "策略 ID")
+	page := fs.Int("page", 1, "页码")
+	pageSize := fs.Int("page-size", 20, "每页数量")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	params.Page = strconv.Itoa(*page)
+	params.PageSize = strconv.Itoa(*pageSize)
+
+	result, err := a.client.ListTasks(ctx, &params)
+	if err != nil {
+		return err
+	}
+	return a.print(result, func() *table {
+		t := newTable("TASK_ID", "NAME", "STATUS", "HOSTS", "POLICIES", "CREATED_AT")
+		for _, task := range result.Items {
+			t.add(task.TaskID, task.Name, task.Status, taskProgress(&task), taskPolicies(&task), task.CreatedAt)
+		}
+		return t
+	})
+}
+
+func tasksGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "get", "<task_id>")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	task, err := a.client.GetTask(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return a.printTask(task)
+}
+
+func tasksCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "create", "")
+	name := fs.String("name", "", "任务名称（必填）")
+	var policies, rules, hosts, osFamily listFlag
+	fs.Var(&policies, "policy", "策略 ID（必填，可重复或逗号分隔）")
+	fs.Var(&rules, "rule", "只检查指定规则（可重复或逗号分隔）")
+	fs.Var(&hosts, "host", "目标主机 ID（可重复或逗号分隔）")
+	fs.Var(&osFamily, "os", "目标 OS 系列（可重复或逗号分隔）")
+	allHosts := fs.Bool("all-hosts", false, "以全部主机为目标")
+	runtime := fs.String("runtime", "", "只检查指定运行环境的主机：vm, docker, k8s")
+	runNow := fs.Bool("run", false, "创建后立即执行")
+	wait := fs.Bool("wait", false, "执行后等待任务结束（需配合 -run）")
+	var opts waitOptions
+	opts.register(fs)
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	if *name == "" || len(policies) == 0 {
+		return usagef("-name 和 -policy 为必填参数")
+	}
+	targets := map[string]interface{}{}
+	switch {
+	case len(hosts) > 0 && len(osFamily) == 0 && !*allHosts:
+		targets["type"] = "host_ids"
+		targets["host_ids"] = []string(hosts)
+	case len(osFamily) > 0 && len(hosts) == 0 && !*allHosts:
+		targets["type"] = "os_family"
+		targets["os_family"] = []string(osFamily)
+	case *allHosts && len(hosts) == 0 && len(osFamily) == 0:
+		targets["type"] = "all"
+	default:
+		return usagef("-host、-os 和 -all-hosts 必须且只能指定一种目标")
+	}
+	if *runtime != "" {
+		targets["runtime_type"] = *runtime
+	}
+	if *wait && !*runNow {
+		return usagef("-wait 需配合 -run 使用")
+	}
+
+	task, err := a.client.CreateTask(ctx, &v1.CreateTaskRequest{
+		Name:      *name,
+		Type:      "baseline_scan",
+		Targets:   targets,
+		PolicyIds: policies,
+		RuleIds:   rules,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "已创建任务 %s（匹配在线主机 %d 台）\n", task.TaskID, task.MatchedHostCount)
+	if !*runNow {
+		return a.printTask(task)
+	}
+	return a.runTask(ctx, task.TaskID, *wait, opts)
+}
+
+func tasksRun(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "run", "<task_id>")
+	wait := fs.Bool("wait", false, "等待任务结束")
+	var opts waitOptions
+	opts.register(fs)
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	return a.runTask(ctx, pos[0], *wait, opts)
+}
+
+func tasksWait(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "wait", "<task_id>")
+	var opts waitOptions
+	opts.register(fs)
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	task, err := a.waitTask(ctx, pos[0], opts)
+	if err != nil {
+		return err
+	}
+	if err := a.printTask(task); err != nil {
+		return err
+	}
+	return taskOutcome(task)
+}
+
+func tasksCancel(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "cancel", "<task_id>")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	task, err := a.client.CancelTask(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return a.printTask(task)
+}
+
+func tasksStatus(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("tasks", "status", "<task_id>")
+	follow := fs.Bool("follow", false, "持续输出主机状态变化直到任务结束（任务未成功完成时退出码为 3）")
+	interval := fs.Duration("interval", 5*time.Second, "-follow 的轮询间隔")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	taskID := pos[0]
+
+	if !*follow {
+		result, err := a.client.GetTaskHostStatus(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		return a.print(result, func() *table {
+			t := newTable("HOST_ID", "HOSTNAME", "IP", "BUSINESS_LINE", "STATUS", "COMPLETED_AT", "ERROR")
+			for _, h := range result.Hosts {
+				t.add(h.HostID, h.Hostname, h.IPAddress, h.BusinessLine, h.Status, h.CompletedAt, h.ErrorMessage)
+			}
+			return t
+		})
+	}
+
+	// 持续输出：table 每行一条状态变化，json 每行一个 JSON 对象
+	if a.output == "yaml" {
+		return usagef("-follow 只支持 table 和 json 输出")
+	}
+	if a.output == "table" {
+		fmt.Fprintf(a.stdout, "%-19s  %-36s  %-24s  %-10s  %s\n", "TIME", "HOST_ID", "HOSTNAME", "STATUS", "ERROR")
+	}
+	seen := make(map[string]string)
+	for {
+		task, err := a.client.GetTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		result, err := a.client.GetTaskHostStatus(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		for _, h := range result.Hosts {
+			if seen[h.HostID] == h.Status {
+				continue
+			}
+			seen[h.HostID] = h.Status
+			if a.output == "json" {
+				line, _ := json.Marshal(h)
+				fmt.Fprintln(a.stdout, string(line))
+				continue
+			}
+			fmt.Fprintf(a.stdout, "%-19s  %-36s  %-24s  %-10s  %s\n",
+				time.Now().Format("2006-01-02 15:04:05"), h.HostID, cell(h.Hostname), h.Status, cell(h.ErrorMessage))
+		}
+		if taskFinished(task.Status) {
+			fmt.Fprintf(a.stderr, "任务 %s 已结束，状态 %s\n", taskID, task.Status)
+			return taskOutcome(task)
+		}
+		if err := sleep(ctx, *interval); err != nil {
+			return err
+		}
+	}
+}
+
+// runTask 执行任务，wait 为 true 时等待任务结束并按结果返回
+func (a *app) runTask(ctx context.Context, taskID string, wait bool, opts waitOptions) error {
+	task, err := a.client.RunTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "任务 %s 已开始执行\n", taskID)
+	if !wait {
+		return a.printTask(task)
+	}
+	if task, err = a.waitTask(ctx, taskID, opts); err != nil {
+		return err
+	}
+	if err := a.printTask(task); err != nil {
+		return err
+	}
+	return taskOutcome(task)
+}
+
+// waitTask 轮询任务直到结束，进度输出到 stderr
+func (a *app) waitTask(ctx context.Context, taskID string, opts waitOptions) (*v1.TaskResponse, error) {
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+	last := ""
+	for {
+		task, err := a.client.GetTask(ctx, taskID)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, failedf("等待任务 %s 超时（%s）", taskID, opts.timeout)
+			}
+			return nil, err
+		}
+		progress := fmt.Sprintf("任务 %s 状态 %s，已完成 %s 台主机", taskID, task.Status, taskProgress(task))
+		if progress != last {
+			fmt.Fprintln(a.stderr, progress)
+			last = progress
+		}
+		if taskFinished(task.Status) {
+			return task, nil
+		}
+		if err := sleep(ctx, opts.interval); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, failedf("等待任务 %s 超时（%s）", taskID, opts.timeout)
+			}
+			return nil, err
+		}
+	}
+}
+
+func (a *app) printTask(task *v1.TaskResponse) error {
+	return a.print(task, func() *table {
+		t := newTable()
+		t.add("任务 ID", task.TaskID)
+		t.add("名称", task.Name)
+		t.add("状态", task.Status)
+		t.add("策略", taskPolicies(task))
+		t.add("目标类型", task.TargetType)
+		t.add("匹配主机", fmt.Sprintf("%d（共 %d）", task.MatchedHostCount, task.TotalHostCount))
+		t.add("完成进度", taskProgress(task))
+		t.add("失败原因", task.FailedReason)
+		t.add("创建时间", task.CreatedAt)
+		t.add("执行时间", task.ExecutedAt)
+		t.add("完成时间", task.CompletedAt)
+		return t
+	})
+}
+
+// taskFinished 判断任务是否已结束
+func taskFinished(status string) bool {
+	switch status {
+	case "completed", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+// taskOutcome 任务未成功完成时返回 failedError
+func taskOutcome(task *v1.TaskResponse) error {
+	if task.Status == "completed" {
+		return nil
+	}
+	if task.FailedReason != "" {
+		return failedf("任务 %s 未成功完成（%s）: %s", task.TaskID, task.Status, task.FailedReason)
+	}
+	return failedf("任务 %s 未成功完成（%s）", task.TaskID, task.Status)
+}
+
+func taskProgress(task *v1.TaskResponse) string {
+	return fmt.Sprintf("%d/%d", task.CompletedHostCount, task.DispatchedHostCount)
+}
+
+func taskPolicies(task *v1.TaskResponse) []string {
+	if len(task.PolicyIds) > 0 {
+		return task.PolicyIds
+	}
+	if task.PolicyID != "" {
+		return []string{task.PolicyID}
+	}
+	return nil
+}
+
+// sleep 等待 d 或 ctx 结束
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "github.com/imkerbos/mxsec-platform/api/client/v1"
+)
+
+func usersGroup() *group {
+	return &group{
+		name:  "users",
+		short: "用户管理",
+		commands: []*command{
+			{name: "list", short: "查询用户列表", run: usersList},
+			{name: "create", short: "创建用户或服务账号", run: usersCreate},
+			{name: "update", args: "<id|username>", short: "修改用户", run: usersUpdate},
+			{name: "delete", args: "<id|username>", short: "删除用户", run: usersDelete},
+		},
+	}
+}
+
+func usersList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("users", "list", "")
+	params := v1.UsersListUsersParams{}
+	fs.StringVar(&params.Username, "username", "", "用户名（模糊匹配）")
+	fs.StringVar(&params.Role, "role", "", "角色")
+	fs.StringVar(&params.Status, "status", "", "状态：active, inactive")
+	fs.StringVar(&params.Source, "source", "", "来源：local, oidc, ldap, service")
+	page := fs.Int64("page", 1, "页码")
+	pageSize := fs.Int64("page-size", 50, "每页数量")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	params.Page, params.PageSize = *page, *pageSize
+
+	result, err := a.client.UsersListUsers(ctx, &params)
+	if err != nil {
+		return err
+	}
+	return a.print(result, func() *table {
+		t := newTable("ID", "USERNAME", "ROLE", "STATUS", "SOURCE", "BUSINESS_LINES", "LAST_LOGIN")
+		for _, u := range result.Items {
+			t.add(u.ID, u.Username, u.Role, u.Status, u.Source, u.BusinessLines, u.LastLogin)
+		}
+		return t
+	})
+}
+
+func usersCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("users", "create", "")
+	req := v1.CreateUserRequest{}
+	var businessLines listFlag
+	fs.StringVar(&req.Username, "username", "", "用户名（必填）")
+	fs.StringVar(&req.Email, "email", "", "邮箱")
+	fs.StringVar(&req.Role, "role", "viewer", "角色")
+	fs.StringVar(&req.Status, "status", "active", "状态：active, inactive")
+	fs.Var(&businessLines, "business-line", "可访问的业务线代码（可重复或逗号分隔，默认不限制）")
+	fs.BoolVar(&req.ServiceAccount, "service-account", false, "创建服务账号（不能登录，只能通过 API Token 访问）")
+	passwordStdin := fs.Bool("password-stdin", false, "从标准输入读取密码（非服务账号必填）")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if req.Username == "" {
+		return usagef("-username 为必填参数")
+	}
+	if req.ServiceAccount == *passwordStdin {
+		return usagef("普通用户需指定 -password-stdin，服务账号不需要密码")
+	}
+	if *passwordStdin {
+		password, err := a.readPassword()
+		if err != nil {
+			return err
+		}
+		req.Password = password
+	}
+	req.BusinessLines = businessLines
+
+	user, err := a.client.CreateUser(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return a.printUser(user)
+}
+
+func usersUpdate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("users", "update", "<id|username>")
+	req := v1.UpdateUserRequest{}
+	var businessLines listFlag
+	fs.StringVar(&req.Email, "email", "", "邮箱")
+	fs.StringVar(&req.Role, "role", "", "角色")
+	fs.StringVar(&req.Status, "status", "", "状态：active, inactive")
+	fs.Var(&businessLines, "business-line", "可访问的业务线代码（可重复或逗号分隔，替换原有范围）")
+	passwordStdin := fs.Bool("password-stdin", false, "从标准输入读取新密码")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *passwordStdin {
+		if req.Password, err = a.readPassword(); err != nil {
+			return err
+		}
+	}
+	req.BusinessLines = businessLines
+
+	id, err := a.resolveUserID(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	user, err := a.client.UpdateUser(ctx, id, &req)
+	if err != nil {
+		return err
+	}
+	return a.printUser(user)
+}
+
+func usersDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("users", "delete", "<id|username>")
+	pos, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := a.resolveUserID(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	if err := a.client.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "已删除用户 %s\n", pos[0])
+	return nil
+}
+
+// resolveUserID 把用户 ID 或用户名解析为用户 ID
+func (a *app) resolveUserID(ctx context.Context, ref string) (string, error) {
+	if _, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return ref, nil
+	}
+	result, err := a.client.UsersListUsers(ctx, &v1.UsersListUsersParams{Username: ref, PageSize: 100})
+	if err != nil {
+		return "", err
+	}
+	for _, u := range result.Items {
+		if u.Username == ref {
+			return strconv.FormatInt(u.ID, 10), nil
+		}
+	}
+	return "", fmt.Errorf("用户 %s 不存在", ref)
+}
+
+// readPassword 从标准输入读取一行作为密码
+func (a *app) readPassword() (string, error) {
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		if err != nil {
+			return "", fmt.Errorf("读取密码失败: %w", err)
+		}
+		return "", usagef("密码不能为空")
+	}
+	return password, nil
+}
+
+func (a *app) printUser(user *v1.User) error {
+	return a.print(user, func() *table {
+		t := newTable()
+		t.add("ID", user.ID)
+		t.add("用户名", user.Username)
+		t.add("邮箱", user.Email)
+		t.add("角色", user.Role)
+		t.add("状态", user.Status)
+		t.add("来源", user.Source)
+		t.add("业务线", user.BusinessLines)
+		t.add("两步验证", user.TOTPEnabled)
+		return t
+	})
+}
@@ -0,0 +1,57 @@
+// Package main 是 OpenAPI 文档和 Go 客户端生成工具
+//
+// 从 Manager 的 router 和 api 包源码生成 api/openapi/openapi.json 和 api/client/v1/zz_generated.go：
+//
+//	go run ./cmd/openapi-gen
+//
+// 处理器请求/响应结构变化后需重新生成，apispec 的契约测试会检查提交的文件是否为最新
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/apispec"
+)
+
+var (
+	root  = flag.String("root", ".", "模块根目录")
+	check = flag.Bool("check", false, "只检查生成文件是否为最新，不写入")
+)
+
+func main() {
+	flag.Parse()
+
+	files, err := apispec.Generate(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "生成失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	stale := false
+	for _, f := range files {
+		path := filepath.Join(*root, f.Path)
+		if *check {
+			current, err := os.ReadFile(path)
+			if err != nil || string(current) != string(f.Content) {
+				fmt.Fprintf(os.Stderr, "%s 不是最新，请运行 make openapi\n", f.Path)
+				stale = true
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "创建目录失败: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "写入 %s 失败: %v\n", f.Path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("已生成 %s\n", f.Path)
+	}
+	if stale {
+		os.Exit(1)
+	}
+}
@@ -9,6 +9,8 @@ server:
   http:
     host: "0.0.0.0"
     port: 8080
+    # 只信任 Nginx（ui 容器）转发的客户端 IP
+    trusted_proxies: ["__UI_PROXY_IP__"]
 
 database:
   type: "mysql"
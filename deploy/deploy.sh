@@ -276,6 +276,7 @@ init_config() {
         -e "s|__LOG_MAX_AGE__|${LOG_MAX_AGE:-7}|g" \
         -e "s|__HEARTBEAT_INTERVAL__|${HEARTBEAT_INTERVAL:-60}|g" \
         -e "s|__PLUGINS_BASE_URL__|${PLUGINS_URL}|g" \
+        -e "s|__UI_PROXY_IP__|${UI_PROXY_IP:-172.28.0.10}|g" \
         "$SCRIPT_DIR/config/server.yaml"
 
     rm -f "$SCRIPT_DIR/config/server.yaml.bak"
@@ -28,6 +28,10 @@ type LocalConfig struct {
 	Server ServerConfig `mapstructure:"server"`
 	// TLS 配置（证书由 Server 下发，这里只存储路径）
 	TLS TLSConfig `mapstructure:"tls"`
+	// 数据传输配置（压缩和批量发送）
+	Transport TransportConfig `mapstructure:"transport"`
+	// 资源自我保护配置（Agent 及插件的资源预算、自适应限流和熔断）
+	Guard GuardConfig `mapstructure:"guard"`
 	// 日志配置（本地日志，避免日志系统本身出问题时无法记录）
 	Log LogConfig `mapstructure:"log"`
 }
@@ -55,8 +59,10 @@ type ServerConfig struct {
 }
 
 // ServiceDiscoveryConfig 是服务发现配置
+// 配置 URL 后 Agent 从 Manager 获取 AgentCenter 接入点列表，未配置时直接使用 AgentCenterConfig 中的地址
 type ServiceDiscoveryConfig struct {
-	URL string `mapstructure:"url"`
+	URL    string `mapstructure:"url"`    // Manager HTTP 地址，例如 http://10.0.0.1:8080
+	Region string `mapstructure:"region"` // Agent 所在区域（用于筛选接入点）
 }
 
 // AgentCenterConfig 是 AgentCenter 配置
@@ -72,6 +78,38 @@ type TLSConfig struct {
 	KeyFile  string `mapstructure:"key_file"`
 }
 
+// TransportConfig 是数据传输配置
+// 插件数据在 BatchInterval 时间窗口内合并为一个 PackagedData 发送，达到记录数或字节数上限时立即发送
+type TransportConfig struct {
+	Compression     string        `mapstructure:"compression"`       // gRPC 压缩算法：zstd / snappy / none（默认 zstd）
+	BatchInterval   time.Duration `mapstructure:"batch_interval"`    // 合并等待时间（默认 200ms，0 表示不合并）
+	BatchMaxRecords int           `mapstructure:"batch_max_records"` // 单个 PackagedData 最大记录数（默认 200）
+	BatchMaxBytes   int           `mapstructure:"batch_max_bytes"`   // 单个 PackagedData 最大字节数（默认 1MB）
+}
+
+// GuardConfig 是资源自我保护配置
+// Agent 及插件超出资源预算或主机负载过高时，暂停插件的周期性工作并指数退避；
+// 主机资源达到熔断阈值时停止全部插件，恢复后重新启动
+type GuardConfig struct {
+	Enabled        bool             `mapstructure:"enabled"`          // 是否启用（默认 true）
+	Interval       time.Duration    `mapstructure:"interval"`         // 采样间隔（默认 10s）
+	CPUBudget      float64          `mapstructure:"cpu_budget"`       // Agent 及插件 CPU 预算（单核百分比，默认 50，0 表示不限制）
+	MemoryBudgetMB int64            `mapstructure:"memory_budget_mb"` // Agent 及插件常驻内存预算（MB，默认 512，0 表示不限制）
+	HostCPUHigh    float64          `mapstructure:"host_cpu_high"`    // 主机 CPU 使用率高水位（%，默认 90，0 表示不检测）
+	HostMemHigh    float64          `mapstructure:"host_mem_high"`    // 主机内存使用率高水位（%，默认 90，0 表示不检测）
+	BackoffMin     time.Duration    `mapstructure:"backoff_min"`      // 首次限流时长（默认 30s）
+	BackoffMax     time.Duration    `mapstructure:"backoff_max"`      // 限流时长上限（默认 10m）
+	KillSwitch     KillSwitchConfig `mapstructure:"kill_switch"`      // 熔断配置
+}
+
+// KillSwitchConfig 是熔断配置：主机资源连续 Samples 次采样达到阈值时停止全部插件
+type KillSwitchConfig struct {
+	Enabled bool    `mapstructure:"enabled"`  // 是否启用（默认 true）
+	HostCPU float64 `mapstructure:"host_cpu"` // 主机 CPU 使用率阈值（%，默认 98，0 表示不检测）
+	HostMem float64 `mapstructure:"host_mem"` // 主机内存使用率阈值（%，默认 95，0 表示不检测）
+	Samples int     `mapstructure:"samples"`  // 连续采样次数（默认 3），恢复同样需要连续 Samples 次低于高水位
+}
+
 // LogConfig 是日志配置（已简化，不再需要，保留用于兼容）
 type LogConfig struct {
 	Level  string `mapstructure:"level"`
@@ -128,6 +166,28 @@ func LoadDefaults() *Config {
 				CertFile: "/var/lib/mxsec-agent/certs/client.crt",
 				KeyFile:  "/var/lib/mxsec-agent/certs/client.key",
 			},
+			Transport: TransportConfig{
+				Compression:     "zstd",
+				BatchInterval:   200 * time.Millisecond,
+				BatchMaxRecords: 200,
+				BatchMaxBytes:   1024 * 1024,
+			},
+			Guard: GuardConfig{
+				Enabled:        true,
+				Interval:       10 * time.Second,
+				CPUBudget:      50,
+				MemoryBudgetMB: 512,
+				HostCPUHigh:    90,
+				HostMemHigh:    90,
+				BackoffMin:     30 * time.Second,
+				BackoffMax:     10 * time.Minute,
+				KillSwitch: KillSwitchConfig{
+					Enabled: true,
+					HostCPU: 98,
+					HostMem: 95,
+					Samples: 3,
+				},
+			},
 			Log: LogConfig{
 				Level:  "info",
 				Format: "json",
@@ -206,7 +266,8 @@ func setDefaults() {
 	viper.SetDefault("local.id_file", "/var/lib/mxsec-agent/agent_id")
 
 	// Server 配置（通常通过构建时嵌入，这里只是默认值）
-	viper.SetDefault("local.server.service_discovery.url", "http://localhost:8088")
+	viper.SetDefault("local.server.service_discovery.url", "")
+	viper.SetDefault("local.server.service_discovery.region", "")
 	viper.SetDefault("local.server.agent_center.private_host", "")
 	viper.SetDefault("local.server.agent_center.public_host", "")
 
@@ -215,6 +276,26 @@ func setDefaults() {
 	viper.SetDefault("local.tls.cert_file", "/var/lib/mxsec-agent/certs/client.crt")
 	viper.SetDefault("local.tls.key_file", "/var/lib/mxsec-agent/certs/client.key")
 
+	// 传输默认配置（zstd 压缩，200ms 内的插件数据合并发送）
+	viper.SetDefault("local.transport.compression", "zstd")
+	viper.SetDefault("local.transport.batch_interval", "200ms")
+	viper.SetDefault("local.transport.batch_max_records", 200)
+	viper.SetDefault("local.transport.batch_max_bytes", 1024*1024)
+
+	// 资源自我保护默认配置（Agent 及插件最多占用半核 CPU、512MB 内存）
+	viper.SetDefault("local.guard.enabled", true)
+	viper.SetDefault("local.guard.interval", "10s")
+	viper.SetDefault("local.guard.cpu_budget", 50)
+	viper.SetDefault("local.guard.memory_budget_mb", 512)
+	viper.SetDefault("local.guard.host_cpu_high", 90)
+	viper.SetDefault("local.guard.host_mem_high", 90)
+	viper.SetDefault("local.guard.backoff_min", "30s")
+	viper.SetDefault("local.guard.backoff_max", "10m")
+	viper.SetDefault("local.guard.kill_switch.enabled", true)
+	viper.SetDefault("local.guard.kill_switch.host_cpu", 98)
+	viper.SetDefault("local.guard.kill_switch.host_mem", 95)
+	viper.SetDefault("local.guard.kill_switch.samples", 3)
+
 	// 日志默认配置（标准 Linux 日志目录，按天轮转，保留30天）
 	viper.SetDefault("local.log.level", "info")
 	viper.SetDefault("local.log.format", "json")
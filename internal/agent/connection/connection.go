@@ -7,8 +7,10 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"math/rand"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -24,19 +26,30 @@ import (
 type Manager struct {
 	cfg    *config.Config
 	logger *zap.Logger
-	conn   *grpc.ClientConn
+	disc   *discovery
+
+	mu        sync.Mutex
+	conn      *grpc.ClientConn
+	current   string // 当前连接的接入点地址
+	preferred string // 重新均衡时指定的下一个接入点
 }
 
 // NewManager 创建新的连接管理器
-func NewManager(cfg *config.Config, logger *zap.Logger) *Manager {
+func NewManager(cfg *config.Config, logger *zap.Logger, agentID string) *Manager {
+	sd := cfg.Local.Server.ServiceDiscovery
 	return &Manager{
 		cfg:    cfg,
 		logger: logger,
+		disc: newDiscovery(sd.URL, sd.Region, agentID, cfg.GetWorkDir(), cfg.Local.TLS.CAFile,
+			[]string{cfg.Local.Server.AgentCenter.PrivateHost, cfg.Local.Server.AgentCenter.PublicHost}, logger),
 	}
 }
 
 // GetConnection 获取 gRPC 连接（带 mTLS）
 func (m *Manager) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// 如果已有连接且有效，直接返回
 	if m.conn != nil {
 		state := m.conn.GetState()
@@ -45,10 +58,11 @@ func (m *Manager) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
 			m.logger.Debug("reusing existing connection", zap.String("state", state.String()))
 			return m.conn, nil
 		}
-		// 连接已断开，关闭旧连接
+		// 连接已断开，关闭旧连接，并让该接入点进入退避（优先切换到其他接入点）
 		m.logger.Info("closing stale connection", zap.String("state", state.String()))
 		m.conn.Close()
 		m.conn = nil
+		m.disc.markFailure(m.current)
 	}
 
 	// 获取 Server 地址（需要在加载 TLS 配置之前获取，以便设置 ServerName）
@@ -107,6 +121,7 @@ func (m *Manager) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
 		}),
 	)
 	if err != nil {
+		m.disc.markFailure(serverAddr)
 		if connectCtx.Err() == context.DeadlineExceeded {
 			m.logger.Error("connection timeout",
 				zap.String("server", serverAddr),
@@ -140,6 +155,8 @@ func (m *Manager) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
 	}
 
 	m.conn = conn
+	m.current = serverAddr
+	m.disc.markSuccess(serverAddr)
 	m.logger.Info("gRPC connection established successfully",
 		zap.String("server", serverAddr),
 		zap.String("state", conn.GetState().String()),
@@ -149,12 +166,79 @@ func (m *Manager) GetConnection(ctx context.Context) (*grpc.ClientConn, error) {
 
 // Close 关闭连接
 func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.conn != nil {
 		return m.conn.Close()
 	}
 	return nil
 }
 
+// ReportFailure 报告当前接入点不可用（例如连接建立后无法创建数据流）
+// 关闭当前连接，下次 GetConnection 时切换到其他接入点
+func (m *Manager) ReportFailure() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn == nil {
+		return
+	}
+	m.disc.markFailure(m.current)
+	m.conn.Close()
+	m.conn = nil
+}
+
+// RunRebalance 定期刷新接入点列表，当前接入点被排空或负载明显偏高时迁移到其他接入点
+// 未配置服务发现时直接返回
+func (m *Manager) RunRebalance(ctx context.Context) {
+	if !m.disc.enabled() {
+		return
+	}
+
+	for {
+		// 增加随机抖动，避免大量 Agent 同时请求服务发现和迁移
+		interval := m.disc.interval()
+		jitter := time.Duration(rand.Int63n(int64(interval)/5 + 1))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval + jitter):
+		}
+
+		if err := m.disc.refresh(ctx); err != nil {
+			m.logger.Warn("failed to refresh agentcenter endpoints, keeping cached list", zap.Error(err))
+			continue
+		}
+		m.rebalance()
+	}
+}
+
+// rebalance 根据最新的接入点列表判断是否迁移当前连接
+func (m *Manager) rebalance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conn == nil || m.current == "" {
+		return
+	}
+	reason := m.disc.shouldMigrate(m.current)
+	if reason == "" {
+		return
+	}
+	next, err := m.disc.pick(m.current)
+	if err != nil || next == m.current {
+		return
+	}
+
+	m.logger.Info("migrating to another agentcenter endpoint",
+		zap.String("from", m.current),
+		zap.String("to", next),
+		zap.String("reason", reason))
+	m.preferred = next
+	// 关闭连接后数据流随之断开，传输模块会重新调用 GetConnection 连接到新的接入点
+	m.conn.Close()
+	m.conn = nil
+}
+
 // loadTLSConfig 加载 TLS 配置并验证证书
 // 如果证书文件不存在（首次连接），返回不安全配置以允许首次连接获取证书
 // serverAddr 用于提取主机名并设置为 ServerName（用于 SNI）
@@ -311,22 +395,25 @@ func (m *Manager) loadTLSConfig(serverAddr string) (*tls.Config, error) {
 }
 
 // discoverServer 通过服务发现获取 Server 地址
-// 目前简化实现：直接使用配置中的地址
-// TODO: 实现完整的服务发现逻辑（HTTP 请求 ServiceDiscovery）
+// 配置了服务发现时从 Manager 获取加权接入点列表（失败时使用磁盘缓存），未配置时使用构建时嵌入的地址；
+// 按权重选择未处于失败退避期的接入点（调用方持有 mu）
 func (m *Manager) discoverServer(ctx context.Context) (string, error) {
-	// 简化实现：直接返回配置的地址
-	// 后续可以实现 HTTP 请求 ServiceDiscovery 获取可用 Server 列表
-	m.logger.Debug("discovering server address",
-		zap.String("private_host", m.cfg.Local.Server.AgentCenter.PrivateHost),
-		zap.String("public_host", m.cfg.Local.Server.AgentCenter.PublicHost),
-	)
-	addr := m.cfg.Local.Server.AgentCenter.PrivateHost
-	if addr == "" {
-		addr = m.cfg.Local.Server.AgentCenter.PublicHost
+	if m.preferred != "" {
+		addr := m.preferred
+		m.preferred = ""
+		return addr, nil
+	}
+
+	if m.disc.refreshDue() {
+		if err := m.disc.refresh(ctx); err != nil {
+			m.logger.Warn("failed to refresh agentcenter endpoints, using cached list", zap.Error(err))
+		}
 	}
-	if addr == "" {
+
+	addr, err := m.disc.pick("")
+	if err != nil {
 		m.logger.Error("no server address configured")
-		return "", fmt.Errorf("no server address configured")
+		return "", err
 	}
 
 	m.logger.Info("server address discovered", zap.String("address", addr))
@@ -0,0 +1,361 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultRefreshInterval 是 Server 未返回刷新间隔时的默认值
+	defaultRefreshInterval = 5 * time.Minute
+	// endpointBackoffBase 是接入点连接失败后的初始退避时间
+	endpointBackoffBase = 5 * time.Second
+	// endpointBackoffMax 是接入点连接失败后的最大退避时间
+	endpointBackoffMax = 5 * time.Minute
+	// endpointsCacheFile 是接入点列表的磁盘缓存文件名（位于工作目录）
+	endpointsCacheFile = "endpoints.json"
+)
+
+// Endpoint 是一个 AgentCenter 接入点
+type Endpoint struct {
+	Address string `json:"address"`
+	Weight  int    `json:"weight"`
+	Region  string `json:"region,omitempty"`
+}
+
+// endpointsCache 是接入点列表的磁盘缓存
+type endpointsCache struct {
+	Endpoints       []Endpoint `json:"endpoints"`
+	RefreshInterval int        `json:"refresh_interval"`
+	FetchedAt       time.Time  `json:"fetched_at"`
+}
+
+// endpointHealth 是接入点的连接失败状态
+type endpointHealth struct {
+	failures int
+	retryAt  time.Time
+}
+
+// discovery 维护 AgentCenter 接入点列表并按权重选择接入点
+// 列表来源优先级：服务发现 > 磁盘缓存（服务发现不可用时） > 构建时嵌入的静态地址
+type discovery struct {
+	url       string
+	region    string
+	agentID   string
+	cachePath string
+	static    []Endpoint
+	client    *http.Client
+	logger    *zap.Logger
+
+	mu              sync.Mutex
+	endpoints       []Endpoint
+	refreshInterval time.Duration
+	fetchedAt       time.Time
+	health          map[string]*endpointHealth
+	rand            *rand.Rand
+}
+
+// newDiscovery 创建服务发现，并加载磁盘缓存的接入点列表
+func newDiscovery(discoveryURL, region, agentID, workDir, caFile string, static []string, logger *zap.Logger) *discovery {
+	d := &discovery{
+		url:             strings.TrimSuffix(discoveryURL, "/"),
+		region:          region,
+		agentID:         agentID,
+		cachePath:       filepath.Join(workDir, endpointsCacheFile),
+		logger:          logger,
+		refreshInterval: defaultRefreshInterval,
+		health:          make(map[string]*endpointHealth),
+		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, addr := range static {
+		if addr != "" {
+			d.static = append(d.static, Endpoint{Address: addr, Weight: 1})
+		}
+	}
+
+	// Manager 使用自签名证书时通过 Agent 的 CA 校验
+	tlsConfig := &tls.Config{}
+	if caData, err := os.ReadFile(caFile); err == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(caData)
+		tlsConfig.RootCAs = pool
+	}
+	d.client = &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	if d.url != "" {
+		d.loadCache()
+	}
+	return d
+}
+
+// enabled 返回是否配置了服务发现
+func (d *discovery) enabled() bool {
+	return d.url != ""
+}
+
+// refreshDue 返回是否需要重新获取接入点列表
+func (d *discovery) refreshDue() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.enabled() && time.Since(d.fetchedAt) >= d.refreshInterval
+}
+
+// interval 返回接入点列表的刷新间隔
+func (d *discovery) interval() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.refreshInterval
+}
+
+// refresh 从服务发现获取接入点列表并写入磁盘缓存，失败时保留现有列表
+func (d *discovery) refresh(ctx context.Context) error {
+	if !d.enabled() {
+		return nil
+	}
+
+	query := url.Values{}
+	query.Set("host_id", d.agentID)
+	if d.region != "" {
+		query.Set("region", d.region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url+"/api/v1/agent/discovery?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create discovery request: %w", err)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request discovery: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("discovery returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Code    int            `json:"code"`
+		Message string         `json:"message"`
+		Data    endpointsCache `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode discovery response: %w", err)
+	}
+	if body.Code != 0 {
+		return fmt.Errorf("discovery failed: %s", body.Message)
+	}
+
+	body.Data.FetchedAt = time.Now()
+	d.apply(&body.Data)
+	d.saveCache(&body.Data)
+
+	d.logger.Info("agentcenter endpoints refreshed",
+		zap.Int("count", len(body.Data.Endpoints)),
+		zap.Int("refresh_interval", body.Data.RefreshInterval))
+	return nil
+}
+
+// apply 更新内存中的接入点列表
+func (d *discovery) apply(cache *endpointsCache) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	endpoints := make([]Endpoint, 0, len(cache.Endpoints))
+	for _, ep := range cache.Endpoints {
+		if ep.Address == "" {
+			continue
+		}
+		if ep.Weight <= 0 {
+			ep.Weight = 1
+		}
+		endpoints = append(endpoints, ep)
+	}
+	d.endpoints = endpoints
+	d.fetchedAt = cache.FetchedAt
+	if cache.RefreshInterval > 0 {
+		d.refreshInterval = time.Duration(cache.RefreshInterval) * time.Second
+	}
+}
+
+// loadCache 加载磁盘缓存的接入点列表（服务发现暂不可用时使用）
+func (d *discovery) loadCache() {
+	data, err := os.ReadFile(d.cachePath)
+	if err != nil {
+		return
+	}
+	var cache endpointsCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		d.logger.Warn("invalid endpoints cache, ignoring", zap.String("path", d.cachePath), zap.Error(err))
+		return
+	}
+	d.apply(&cache)
+	d.logger.Info("loaded cached agentcenter endpoints",
+		zap.Int("count", len(cache.Endpoints)),
+		zap.Time("fetched_at", cache.FetchedAt))
+}
+
+// saveCache 写入接入点列表缓存
+func (d *discovery) saveCache(cache *endpointsCache) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(d.cachePath), 0755); err != nil {
+		d.logger.Warn("failed to create endpoints cache dir", zap.Error(err))
+		return
+	}
+	tmp := d.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		d.logger.Warn("failed to write endpoints cache", zap.Error(err))
+		return
+	}
+	if err := os.Rename(tmp, d.cachePath); err != nil {
+		os.Remove(tmp)
+		d.logger.Warn("failed to commit endpoints cache", zap.Error(err))
+	}
+}
+
+// candidates 返回当前可用的接入点列表（调用方持有锁）
+func (d *discovery) candidates() []Endpoint {
+	if len(d.endpoints) > 0 {
+		return d.endpoints
+	}
+	return d.static
+}
+
+// pick 按权重选择一个未处于退避期的接入点（排除 exclude）
+// 所有接入点都在退避期时选择最早可重试的接入点
+func (d *discovery) pick(exclude string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	candidates := d.candidates()
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no server address configured")
+	}
+
+	now := time.Now()
+	var healthy []Endpoint
+	total := 0
+	for _, ep := range candidates {
+		if ep.Address == exclude && len(candidates) > 1 {
+			continue
+		}
+		if h, ok := d.health[ep.Address]; ok && now.Before(h.retryAt) {
+			continue
+		}
+		healthy = append(healthy, ep)
+		total += ep.Weight
+	}
+
+	if len(healthy) == 0 {
+		earliest := candidates[0].Address
+		for _, ep := range candidates {
+			if ep.Address == exclude && len(candidates) > 1 {
+				continue
+			}
+			if d.retryAt(ep.Address).Before(d.retryAt(earliest)) || earliest == exclude {
+				earliest = ep.Address
+			}
+		}
+		return earliest, nil
+	}
+
+	n := d.rand.Intn(total)
+	for _, ep := range healthy {
+		if n < ep.Weight {
+			return ep.Address, nil
+		}
+		n -= ep.Weight
+	}
+	return healthy[len(healthy)-1].Address, nil
+}
+
+// retryAt 返回接入点的可重试时间（调用方持有锁）
+func (d *discovery) retryAt(address string) time.Time {
+	if h, ok := d.health[address]; ok {
+		return h.retryAt
+	}
+	return time.Time{}
+}
+
+// markFailure 记录接入点连接失败，按失败次数指数退避
+func (d *discovery) markFailure(address string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, ok := d.health[address]
+	if !ok {
+		h = &endpointHealth{}
+		d.health[address] = h
+	}
+	h.failures++
+	backoff := endpointBackoffBase << uint(min(h.failures-1, 10))
+	if backoff > endpointBackoffMax {
+		backoff = endpointBackoffMax
+	}
+	h.retryAt = time.Now().Add(backoff)
+
+	d.logger.Warn("agentcenter endpoint marked unhealthy",
+		zap.String("address", address),
+		zap.Int("failures", h.failures),
+		zap.Duration("backoff", backoff))
+}
+
+// markSuccess 清除接入点的失败状态
+func (d *discovery) markSuccess(address string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.health, address)
+}
+
+// shouldMigrate 判断是否应从当前接入点迁移，返回迁移原因（空表示不迁移）
+// 当前接入点已不在列表中（被排空或删除）时立即迁移；
+// 当前接入点权重明显低于最高权重（负载较高）时按比例随机迁移，避免所有 Agent 同时切换
+func (d *discovery) shouldMigrate(current string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.endpoints) == 0 {
+		return ""
+	}
+
+	currentWeight, maxWeight := 0, 0
+	found := false
+	for _, ep := range d.endpoints {
+		if ep.Address == current {
+			currentWeight = ep.Weight
+			found = true
+		}
+		if ep.Weight > maxWeight {
+			maxWeight = ep.Weight
+		}
+	}
+	if !found {
+		return "endpoint no longer advertised"
+	}
+	if len(d.endpoints) > 1 && currentWeight*2 < maxWeight {
+		if d.rand.Float64() < 1-float64(currentWeight)/float64(maxWeight) {
+			return "endpoint overloaded"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestDiscoveryFailoverAndCache 测试接入点故障切换和磁盘缓存
+func TestDiscoveryFailoverAndCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("host_id") != "agent-1" {
+			t.Errorf("unexpected host_id %q", r.URL.Query().Get("host_id"))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{
+				"endpoints": []Endpoint{
+					{Address: "a:6751", Weight: 100},
+					{Address: "b:6751", Weight: 100},
+				},
+				"refresh_interval": 60,
+			},
+		})
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	d := newDiscovery(srv.URL, "", "agent-1", dir, "", []string{"static:6751"}, zap.NewNop())
+	if err := d.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// a 失败后只会选择 b
+	d.markFailure("a:6751")
+	for i := 0; i < 20; i++ {
+		if addr, _ := d.pick(""); addr != "b:6751" {
+			t.Fatalf("expected b:6751 while a is backing off, got %s", addr)
+		}
+	}
+
+	// 全部失败时选择最早可重试的接入点
+	d.markFailure("b:6751")
+	if addr, _ := d.pick(""); addr != "a:6751" {
+		t.Fatalf("expected earliest retry a:6751, got %s", addr)
+	}
+
+	// 排空后触发迁移
+	if reason := d.shouldMigrate("c:6751"); reason == "" {
+		t.Fatal("expected migration from unadvertised endpoint")
+	}
+
+	// 服务发现不可用时使用磁盘缓存
+	srv.Close()
+	cached := newDiscovery(srv.URL, "", "agent-1", dir, "", []string{"static:6751"}, zap.NewNop())
+	if cached.refresh(context.Background()) == nil {
+		t.Fatal("expected refresh error after server closed")
+	}
+	if addr, _ := cached.pick(""); addr == "static:6751" {
+		t.Fatal("expected cached endpoint, got static fallback")
+	}
+}
@@ -0,0 +1,281 @@
+// Package diagnostics 实现 Agent 诊断信息收集
+// 收到 Server 下发的诊断命令后，将配置、Agent/插件日志、任务状态、缓存统计和 goroutine 堆栈打包为 tar.gz，
+// 使用命令中的一次性令牌通过 FileExt 服务上传
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/agent/config"
+	"github.com/imkerbos/mxsec-platform/internal/agent/connection"
+	"github.com/imkerbos/mxsec-platform/internal/agent/plugin"
+	"github.com/imkerbos/mxsec-platform/internal/agent/transport"
+)
+
+const (
+	// defaultMaxLogBytes 是 Server 未指定时每个日志文件收集的最大字节数
+	defaultMaxLogBytes = 2 * 1024 * 1024
+	// uploadChunkSize 是上传时每个请求携带的数据大小
+	uploadChunkSize = 256 * 1024
+	// uploadTimeout 是上传诊断包的超时时间
+	uploadTimeout = 5 * time.Minute
+)
+
+// Collector 是诊断信息收集器
+type Collector struct {
+	cfg          *config.Config
+	logger       *zap.Logger
+	connMgr      *connection.Manager
+	transportMgr *transport.Manager
+	pluginMgr    *plugin.Manager
+	agentID      string
+	startTime    time.Time
+}
+
+// NewCollector 创建诊断信息收集器
+func NewCollector(cfg *config.Config, logger *zap.Logger, connMgr *connection.Manager, transportMgr *transport.Manager, pluginMgr *plugin.Manager, agentID string) *Collector {
+	return &Collector{
+		cfg:          cfg,
+		logger:       logger,
+		connMgr:      connMgr,
+		transportMgr: transportMgr,
+		pluginMgr:    pluginMgr,
+		agentID:      agentID,
+		startTime:    time.Now(),
+	}
+}
+
+// Startup 启动诊断模块（监听来自 Server 的诊断命令）
+func Startup(ctx context.Context, wg *sync.WaitGroup, c *Collector) {
+	defer wg.Done()
+
+	c.logger.Info("diagnostics module started")
+
+	ch := c.transportMgr.GetDiagnosticsChannel()
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("diagnostics module shutting down")
+			return
+		case req := <-ch:
+			if req == nil || req.Token == "" {
+				continue
+			}
+			c.handle(ctx, req)
+		}
+	}
+}
+
+// handle 收集并上传诊断包
+func (c *Collector) handle(ctx context.Context, req *grpc.DiagnosticsRequest) {
+	maxLogBytes := int64(req.MaxLogBytes)
+	if maxLogBytes <= 0 {
+		maxLogBytes = defaultMaxLogBytes
+	}
+
+	bundle, err := c.Collect(maxLogBytes)
+	if err != nil {
+		c.logger.Error("failed to collect diagnostics", zap.Error(err))
+		return
+	}
+
+	if err := c.upload(ctx, req.Token, bundle); err != nil {
+		c.logger.Error("failed to upload diagnostics bundle", zap.Int("size", len(bundle)), zap.Error(err))
+		return
+	}
+	c.logger.Info("diagnostics bundle uploaded", zap.Int("size", len(bundle)))
+}
+
+// Collect 收集诊断信息并打包为 tar.gz
+// 单项收集失败不会中断打包，错误记录在 errors.txt 中
+func (c *Collector) Collect(maxLogBytes int64) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	var errs []string
+	add := func(name string, data []byte) {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		if _, err := tw.Write(data); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	addJSON := func(name string, v interface{}) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		add(name, data)
+	}
+
+	addJSON("runtime.json", c.runtimeInfo(now))
+	addJSON("config.json", map[string]interface{}{
+		"local":         c.cfg.Local,
+		"remote":        c.cfg.Remote,
+		"build_version": c.cfg.BuildVersion,
+	})
+	addJSON("plugins.json", c.pluginMgr.GetAllPluginStats())
+	addJSON("tasks.json", c.pluginMgr.GetTrackedTasks())
+	addJSON("cache.json", c.transportMgr.GetStats())
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		errs = append(errs, fmt.Sprintf("goroutines.txt: %v", err))
+	} else {
+		add("goroutines.txt", goroutines.Bytes())
+	}
+
+	logFiles, err := c.logFiles()
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("logs: %v", err))
+	}
+	for name, path := range logFiles {
+		data, err := tailFile(path, maxLogBytes)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		add(name, data)
+	}
+
+	if len(errs) > 0 {
+		add("errors.txt", []byte(strings.Join(errs, "\n")+"\n"))
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// runtimeInfo 返回 Agent 运行时信息
+func (c *Collector) runtimeInfo(now time.Time) map[string]interface{} {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	hostname, _ := os.Hostname()
+
+	return map[string]interface{}{
+		"agent_id":       c.agentID,
+		"hostname":       hostname,
+		"version":        c.cfg.GetVersion(),
+		"go_version":     runtime.Version(),
+		"os":             runtime.GOOS,
+		"arch":           runtime.GOARCH,
+		"pid":            os.Getpid(),
+		"num_cpu":        runtime.NumCPU(),
+		"num_goroutine":  runtime.NumGoroutine(),
+		"heap_alloc":     mem.HeapAlloc,
+		"sys":            mem.Sys,
+		"num_gc":         mem.NumGC,
+		"start_time":     c.startTime,
+		"uptime_seconds": int64(now.Sub(c.startTime).Seconds()),
+		"collected_at":   now,
+	}
+}
+
+// logFiles 返回需要收集的日志文件（包内路径 -> 本地路径）
+func (c *Collector) logFiles() (map[string]string, error) {
+	agentLogFile := c.cfg.Local.Log.File
+	if agentLogFile == "" {
+		agentLogFile = "/var/log/mxsec-agent/agent.log" // 默认路径
+	}
+
+	files := map[string]string{
+		"logs/agent.log": agentLogFile,
+	}
+	pluginLogs, err := filepath.Glob(filepath.Join(filepath.Dir(agentLogFile), "plugins", "*.log"))
+	if err != nil {
+		return files, err
+	}
+	sort.Strings(pluginLogs)
+	for _, path := range pluginLogs {
+		files["logs/plugins/"+filepath.Base(path)] = path
+	}
+	return files, nil
+}
+
+// tailFile 读取文件末尾最多 maxBytes 字节
+func tailFile(path string, maxBytes int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxBytes {
+		if _, err := f.Seek(info.Size()-maxBytes, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(io.LimitReader(f, maxBytes))
+}
+
+// upload 通过 FileExt 服务分块上传诊断包
+func (c *Collector) upload(ctx context.Context, token string, bundle []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	conn, err := c.connMgr.GetConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	stream, err := grpc.NewFileExtClient(conn).Upload(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open upload stream: %w", err)
+	}
+
+	for offset := 0; offset == 0 || offset < len(bundle); offset += uploadChunkSize {
+		end := offset + uploadChunkSize
+		if end > len(bundle) {
+			end = len(bundle)
+		}
+		if err := stream.Send(&grpc.FileUploadRequest{Token: token, Data: bundle[offset:end]}); err != nil {
+			return fmt.Errorf("failed to send chunk: %w", err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return fmt.Errorf("failed to close upload stream: %w", err)
+	}
+	if resp.Status != grpc.FileUploadResponse_SUCCESS {
+		return fmt.Errorf("upload rejected by server: %s", resp.Message)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/agent/config"
+	"github.com/imkerbos/mxsec-platform/internal/agent/plugin"
+	"github.com/imkerbos/mxsec-platform/internal/agent/transport"
+)
+
+// TestTailFile 测试读取文件末尾，文件小于上限时返回全部内容
+func TestTailFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.log")
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		maxBytes int64
+		want     string
+		wantErr  bool
+	}{
+		{"smaller than limit", path, 64, "0123456789", false},
+		{"equal to limit", path, 10, "0123456789", false},
+		{"larger than limit", path, 4, "6789", false},
+		{"missing file", filepath.Join(dir, "missing.log"), 64, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tailFile(tt.path, tt.maxBytes)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCollect 测试诊断包包含运行时信息、配置、日志（按上限截断）和插件日志，缺失的日志记录在 errors.txt
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	logDir := filepath.Join(dir, "log")
+	if err := os.MkdirAll(filepath.Join(logDir, "plugins"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	agentLog := filepath.Join(logDir, "agent.log")
+	if err := os.WriteFile(agentLog, []byte(strings.Repeat("a", 100)+"tail"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "plugins", "baseline.log"), []byte("baseline"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestCollector(t, dir, agentLog)
+	bundle, err := c.Collect(4)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	files := readBundle(t, bundle)
+
+	for _, name := range []string{"runtime.json", "config.json", "plugins.json", "tasks.json", "cache.json", "goroutines.txt"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("bundle missing %s", name)
+		}
+	}
+	if got := files["logs/agent.log"]; got != "tail" {
+		t.Errorf("logs/agent.log = %q, want %q", got, "tail")
+	}
+	if got := files["logs/plugins/baseline.log"]; got != "line" {
+		t.Errorf("logs/plugins/baseline.log = %q, want %q", got, "line")
+	}
+	if !strings.Contains(files["runtime.json"], `"agent_id": "test-agent"`) {
+		t.Errorf("runtime.json missing agent_id: %s", files["runtime.json"])
+	}
+	if _, ok := files["errors.txt"]; ok {
+		t.Errorf("unexpected errors.txt: %s", files["errors.txt"])
+	}
+
+	// 日志文件缺失时仍然打包，错误记录在 errors.txt
+	c.cfg.Local.Log.File = filepath.Join(dir, "missing", "agent.log")
+	bundle, err = c.Collect(4)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	files = readBundle(t, bundle)
+	if _, ok := files["logs/agent.log"]; ok {
+		t.Error("missing log file should not be bundled")
+	}
+	if !strings.Contains(files["errors.txt"], "missing/agent.log") {
+		t.Errorf("errors.txt = %q, want missing log error", files["errors.txt"])
+	}
+}
+
+func newTestCollector(t *testing.T, workDir, logFile string) *Collector {
+	t.Helper()
+	cfg := config.LoadDefaults()
+	cfg.Remote.Loaded = true
+	cfg.Remote.WorkDir = workDir
+	cfg.Local.Log.File = logFile
+
+	logger := zap.NewNop()
+	transportMgr, err := transport.NewManager(cfg, logger, nil, "test-agent")
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	pluginMgr := plugin.NewManager(cfg, logger, transportMgr)
+	return NewCollector(cfg, logger, nil, transportMgr, pluginMgr, "test-agent")
+}
+
+// readBundle 解压诊断包，返回文件名到内容的映射
+func readBundle(t *testing.T, bundle []byte) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		t.Fatalf("invalid gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid tar: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
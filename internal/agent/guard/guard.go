@@ -0,0 +1,318 @@
+// Package guard 实现 Agent 资源自我保护
+// 周期性采样 Agent 及插件的 CPU/内存占用和主机负载：
+//   - 超出资源预算或主机负载超过高水位时，通知插件暂停周期性工作（限流），限流时长指数退避
+//   - 主机资源连续达到熔断阈值时停止全部插件（熔断），主机负载回落后重新启动
+//
+// 当前状态随心跳上报到 Server
+package guard
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/agent/config"
+	"github.com/imkerbos/mxsec-platform/internal/agent/resource"
+)
+
+// State 是资源保护状态
+type State string
+
+const (
+	StateNormal    State = "normal"    // 正常
+	StateThrottled State = "throttled" // 限流：插件暂停周期性工作
+	StateSuspended State = "suspended" // 熔断：全部插件已停止
+)
+
+// 触发限流/熔断的原因
+const (
+	ReasonAgentCPU    = "agent_cpu"    // Agent 及插件 CPU 超出预算
+	ReasonAgentMemory = "agent_memory" // Agent 及插件内存超出预算
+	ReasonHostCPU     = "host_cpu"     // 主机 CPU 使用率过高
+	ReasonHostMemory  = "host_memory"  // 主机内存使用率过高
+)
+
+// PluginController 是插件管理器接口（避免循环依赖）
+type PluginController interface {
+	SetThrottle(until time.Time, reason string)
+	Suspend(reason string)
+	Resume() error
+	PluginPIDs() []int
+}
+
+// Usage 是一次采样的资源占用
+type Usage struct {
+	AgentCPU   float64 `json:"agent_cpu"`    // Agent 及插件 CPU（单核百分比）
+	AgentMemMB float64 `json:"agent_mem_mb"` // Agent 及插件常驻内存（MB）
+	HostCPU    float64 `json:"host_cpu"`     // 主机 CPU 使用率（%）
+	HostMem    float64 `json:"host_mem"`     // 主机内存使用率（%）
+}
+
+// Status 是当前资源保护状态（随心跳上报）
+type Status struct {
+	State  State  `json:"state"`
+	Reason string `json:"reason,omitempty"` // 进入限流/熔断的原因
+	Since  int64  `json:"since"`            // 进入当前状态的时间（Unix 秒）
+	Until  int64  `json:"until,omitempty"`  // 限流截止时间（Unix 秒）
+	Usage  Usage  `json:"usage"`            // 最近一次采样
+}
+
+// Guard 是资源保护器
+type Guard struct {
+	cfg     config.GuardConfig
+	logger  *zap.Logger
+	plugins PluginController
+	monitor *resource.Monitor
+
+	mu     sync.RWMutex
+	status Status
+
+	backoff     time.Duration // 当前限流时长（指数退避）
+	killHits    int           // 连续达到熔断阈值的采样次数
+	recoverHits int           // 熔断后连续低于高水位的采样次数
+
+	lastTicks  map[int]uint64 // 上次采样各进程的累计 CPU 时间
+	lastSample time.Time
+}
+
+// New 创建资源保护器
+func New(cfg config.GuardConfig, logger *zap.Logger, plugins PluginController) *Guard {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.BackoffMin <= 0 {
+		cfg.BackoffMin = 30 * time.Second
+	}
+	if cfg.BackoffMax < cfg.BackoffMin {
+		cfg.BackoffMax = cfg.BackoffMin
+	}
+	if cfg.KillSwitch.Samples <= 0 {
+		cfg.KillSwitch.Samples = 1
+	}
+	return &Guard{
+		cfg:       cfg,
+		logger:    logger,
+		plugins:   plugins,
+		monitor:   resource.NewMonitor(logger),
+		status:    Status{State: StateNormal, Since: time.Now().Unix()},
+		lastTicks: make(map[int]uint64),
+	}
+}
+
+// Startup 启动资源保护模块
+func Startup(ctx context.Context, wg *sync.WaitGroup, g *Guard) {
+	defer wg.Done()
+
+	if !g.cfg.Enabled {
+		g.logger.Info("resource guard disabled")
+		return
+	}
+
+	g.logger.Info("resource guard started",
+		zap.Duration("interval", g.cfg.Interval),
+		zap.Float64("cpu_budget", g.cfg.CPUBudget),
+		zap.Int64("memory_budget_mb", g.cfg.MemoryBudgetMB))
+
+	// 首次采样只建立 CPU 基准
+	g.sample(time.Now())
+
+	ticker := time.NewTicker(g.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			g.logger.Info("resource guard shutting down")
+			return
+		case <-ticker.C:
+			now := time.Now()
+			g.evaluate(now, g.sample(now))
+		}
+	}
+}
+
+// Status 返回当前资源保护状态
+func (g *Guard) Status() Status {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.status
+}
+
+// sample 采样 Agent 及插件的资源占用和主机负载
+func (g *Guard) sample(now time.Time) Usage {
+	var usage Usage
+	if metrics, err := g.monitor.Collect(); err == nil {
+		usage.HostCPU = metrics.CPUUsage
+		usage.HostMem = metrics.MemUsage
+	}
+
+	elapsed := now.Sub(g.lastSample).Seconds()
+	pids := append([]int{os.Getpid()}, g.plugins.PluginPIDs()...)
+	ticks := make(map[int]uint64, len(pids))
+	var cpuTicks uint64
+	var rss uint64
+	for _, pid := range pids {
+		stat, err := resource.ReadProcStat(pid)
+		if err != nil {
+			continue
+		}
+		ticks[pid] = stat.CPUTicks
+		rss += stat.RSS
+		// 只统计两次采样都存在的进程（新启动的进程下次采样再计入）
+		if last, ok := g.lastTicks[pid]; ok && stat.CPUTicks >= last {
+			cpuTicks += stat.CPUTicks - last
+		}
+	}
+	if !g.lastSample.IsZero() && elapsed > 0 {
+		usage.AgentCPU = float64(cpuTicks) / resource.ClockTicks / elapsed * 100
+	}
+	usage.AgentMemMB = float64(rss) / 1024 / 1024
+
+	g.lastTicks = ticks
+	g.lastSample = now
+	return usage
+}
+
+// evaluate 根据采样结果更新限流/熔断状态
+// 停止/启动插件可能耗时数秒，在锁外执行，避免阻塞心跳读取状态
+func (g *Guard) evaluate(now time.Time, usage Usage) {
+	g.mu.Lock()
+	action := g.transition(now, usage)
+	g.mu.Unlock()
+
+	if action != nil {
+		action()
+	}
+}
+
+// transition 更新状态并返回需要对插件执行的操作（无操作时返回 nil），调用方需持有 g.mu
+func (g *Guard) transition(now time.Time, usage Usage) func() {
+	g.status.Usage = usage
+	hostReason := g.hostReason(usage)
+
+	// 熔断中：主机负载连续 Samples 次低于高水位后恢复插件（恢复后先保持限流，避免负载立即反弹）
+	if g.status.State == StateSuspended {
+		if hostReason == "" && g.killSwitchReason(usage) == "" {
+			g.recoverHits++
+		} else {
+			g.recoverHits = 0
+		}
+		if g.recoverHits < g.cfg.KillSwitch.Samples {
+			return nil
+		}
+		g.recoverHits = 0
+		g.backoff = g.cfg.BackoffMin
+		until := now.Add(g.backoff)
+		reason := g.status.Reason
+		g.setStatus(now, StateThrottled, reason, until)
+		g.logger.Info("host load recovered, resuming plugins", zap.Time("throttle_until", until))
+		return func() {
+			g.plugins.SetThrottle(until, reason)
+			if err := g.plugins.Resume(); err != nil {
+				g.logger.Error("failed to resume plugins", zap.Error(err))
+			}
+		}
+	}
+
+	// 熔断检测
+	if reason := g.killSwitchReason(usage); reason != "" {
+		g.killHits++
+		if g.killHits >= g.cfg.KillSwitch.Samples {
+			g.killHits = 0
+			g.setStatus(now, StateSuspended, reason, time.Time{})
+			g.logger.Warn("host resource kill switch triggered, stopping all plugins",
+				zap.String("reason", reason),
+				zap.Float64("host_cpu", usage.HostCPU),
+				zap.Float64("host_mem", usage.HostMem))
+			return func() { g.plugins.Suspend(reason) }
+		}
+	} else {
+		g.killHits = 0
+	}
+
+	reason := g.budgetReason(usage)
+	if reason == "" {
+		reason = hostReason
+	}
+
+	if reason != "" {
+		// 限流到期前（下次采样之前）仍超限则按指数退避延长
+		until := time.Unix(g.status.Until, 0)
+		if g.status.State == StateThrottled && now.Add(g.cfg.Interval).Before(until) {
+			return nil
+		}
+		g.backoff = min(max(g.backoff*2, g.cfg.BackoffMin), g.cfg.BackoffMax)
+		until = now.Add(g.backoff)
+		g.setStatus(now, StateThrottled, reason, until)
+		g.logger.Warn("resource limit exceeded, throttling plugins",
+			zap.String("reason", reason),
+			zap.Duration("backoff", g.backoff),
+			zap.Float64("agent_cpu", usage.AgentCPU),
+			zap.Float64("agent_mem_mb", usage.AgentMemMB),
+			zap.Float64("host_cpu", usage.HostCPU),
+			zap.Float64("host_mem", usage.HostMem))
+		return func() { g.plugins.SetThrottle(until, reason) }
+	}
+
+	// 未超限且限流已到期：解除限流并重置退避
+	if g.status.State == StateThrottled && !now.Before(time.Unix(g.status.Until, 0)) {
+		g.backoff = 0
+		g.setStatus(now, StateNormal, "", time.Time{})
+		g.logger.Info("resource usage back to normal, throttle lifted")
+		return func() { g.plugins.SetThrottle(time.Time{}, "") }
+	}
+	return nil
+}
+
+// setStatus 切换状态（状态不变时保留进入时间）
+func (g *Guard) setStatus(now time.Time, state State, reason string, until time.Time) {
+	if g.status.State != state {
+		g.status.Since = now.Unix()
+	}
+	g.status.State = state
+	g.status.Reason = reason
+	g.status.Until = 0
+	if !until.IsZero() {
+		g.status.Until = until.Unix()
+	}
+}
+
+// budgetReason 返回 Agent 及插件超出预算的原因，未超出时返回空
+func (g *Guard) budgetReason(usage Usage) string {
+	if g.cfg.CPUBudget > 0 && usage.AgentCPU > g.cfg.CPUBudget {
+		return ReasonAgentCPU
+	}
+	if g.cfg.MemoryBudgetMB > 0 && usage.AgentMemMB > float64(g.cfg.MemoryBudgetMB) {
+		return ReasonAgentMemory
+	}
+	return ""
+}
+
+// hostReason 返回主机负载超过高水位的原因，未超过时返回空
+func (g *Guard) hostReason(usage Usage) string {
+	if g.cfg.HostCPUHigh > 0 && usage.HostCPU >= g.cfg.HostCPUHigh {
+		return ReasonHostCPU
+	}
+	if g.cfg.HostMemHigh > 0 && usage.HostMem >= g.cfg.HostMemHigh {
+		return ReasonHostMemory
+	}
+	return ""
+}
+
+// killSwitchReason 返回达到熔断阈值的原因，未达到时返回空
+func (g *Guard) killSwitchReason(usage Usage) string {
+	ks := g.cfg.KillSwitch
+	if !ks.Enabled {
+		return ""
+	}
+	if ks.HostCPU > 0 && usage.HostCPU >= ks.HostCPU {
+		return ReasonHostCPU
+	}
+	if ks.HostMem > 0 && usage.HostMem >= ks.HostMem {
+		return ReasonHostMemory
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package guard
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/agent/config"
+)
+
+// fakePlugins 记录 Guard 对插件管理器的调用
+type fakePlugins struct {
+	throttleUntil time.Time
+	reason        string
+	suspended     bool
+	resumed       int
+}
+
+func (f *fakePlugins) SetThrottle(until time.Time, reason string) {
+	f.throttleUntil = until
+	f.reason = reason
+}
+func (f *fakePlugins) Suspend(string) { f.suspended = true }
+func (f *fakePlugins) Resume() error {
+	f.suspended = false
+	f.resumed++
+	return nil
+}
+func (f *fakePlugins) PluginPIDs() []int { return nil }
+
+func newTestGuard(plugins *fakePlugins) *Guard {
+	return New(config.GuardConfig{
+		Enabled:        true,
+		Interval:       10 * time.Second,
+		CPUBudget:      50,
+		MemoryBudgetMB: 512,
+		HostCPUHigh:    90,
+		HostMemHigh:    90,
+		BackoffMin:     30 * time.Second,
+		BackoffMax:     2 * time.Minute,
+		KillSwitch: config.KillSwitchConfig{
+			Enabled: true,
+			HostCPU: 98,
+			HostMem: 95,
+			Samples: 2,
+		},
+	}, zap.NewNop(), plugins)
+}
+
+// TestThrottleBackoff 测试超出预算时限流、持续超限时指数退避、恢复后解除限流
+func TestThrottleBackoff(t *testing.T) {
+	plugins := &fakePlugins{}
+	g := newTestGuard(plugins)
+	now := time.Unix(1700000000, 0)
+	over := Usage{AgentCPU: 80, HostCPU: 20, HostMem: 30}
+	idle := Usage{AgentCPU: 1, HostCPU: 20, HostMem: 30}
+
+	g.evaluate(now, over)
+	if s := g.Status(); s.State != StateThrottled || s.Reason != ReasonAgentCPU {
+		t.Fatalf("expected throttled by agent_cpu, got %+v", s)
+	}
+	if want := now.Add(30 * time.Second); !plugins.throttleUntil.Equal(want) {
+		t.Fatalf("throttle until = %v, want %v", plugins.throttleUntil, want)
+	}
+
+	// 限流窗口内（距到期超过一个采样间隔）不重复下发
+	g.evaluate(now.Add(10*time.Second), over)
+	if want := now.Add(30 * time.Second); !plugins.throttleUntil.Equal(want) {
+		t.Fatalf("throttle extended too early: %v", plugins.throttleUntil)
+	}
+
+	// 即将到期仍超限：退避翻倍，直到上限
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 2 * time.Minute} {
+		now = time.Unix(g.Status().Until, 0).Add(-5 * time.Second)
+		g.evaluate(now, over)
+		if got := plugins.throttleUntil.Sub(now); got != want {
+			t.Fatalf("round %d: backoff = %v, want %v", i, got, want)
+		}
+	}
+
+	// 未超限但限流未到期：保持限流
+	g.evaluate(now.Add(time.Second), idle)
+	if g.Status().State != StateThrottled {
+		t.Fatalf("throttle lifted before expiry")
+	}
+
+	// 到期后解除并重置退避
+	now = time.Unix(g.Status().Until, 0)
+	g.evaluate(now, idle)
+	if s := g.Status(); s.State != StateNormal || !plugins.throttleUntil.IsZero() {
+		t.Fatalf("expected normal after expiry, got %+v", s)
+	}
+	g.evaluate(now, Usage{AgentMemMB: 600})
+	if got := plugins.throttleUntil.Sub(now); got != 30*time.Second || plugins.reason != ReasonAgentMemory {
+		t.Fatalf("backoff not reset: %v %s", got, plugins.reason)
+	}
+}
+
+// TestKillSwitch 测试主机资源连续达到熔断阈值时停止插件，负载回落后恢复并保持限流
+func TestKillSwitch(t *testing.T) {
+	plugins := &fakePlugins{}
+	g := newTestGuard(plugins)
+	now := time.Unix(1700000000, 0)
+	critical := Usage{HostCPU: 20, HostMem: 97}
+	high := Usage{HostCPU: 92, HostMem: 50}
+	low := Usage{HostCPU: 20, HostMem: 50}
+
+	g.evaluate(now, critical)
+	if plugins.suspended {
+		t.Fatalf("suspended after a single sample")
+	}
+	if s := g.Status(); s.State != StateThrottled || s.Reason != ReasonHostMemory {
+		t.Fatalf("expected throttled by host_memory, got %+v", s)
+	}
+
+	g.evaluate(now.Add(10*time.Second), critical)
+	if !plugins.suspended || g.Status().State != StateSuspended {
+		t.Fatalf("expected suspended, got %+v", g.Status())
+	}
+
+	// 高于高水位不计入恢复
+	g.evaluate(now.Add(20*time.Second), low)
+	g.evaluate(now.Add(30*time.Second), high)
+	g.evaluate(now.Add(40*time.Second), low)
+	if plugins.resumed != 0 {
+		t.Fatalf("resumed before %d consecutive low samples", g.cfg.KillSwitch.Samples)
+	}
+
+	now = now.Add(50 * time.Second)
+	g.evaluate(now, low)
+	if plugins.resumed != 1 || plugins.suspended {
+		t.Fatalf("expected resumed, got %+v", plugins)
+	}
+	if s := g.Status(); s.State != StateThrottled || !plugins.throttleUntil.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("expected throttled after resume, got %+v", s)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
 	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
 	"github.com/imkerbos/mxsec-platform/internal/agent/config"
+	"github.com/imkerbos/mxsec-platform/internal/agent/guard"
 	"github.com/imkerbos/mxsec-platform/internal/agent/resource"
 	"github.com/imkerbos/mxsec-platform/internal/agent/transport"
 )
@@ -34,6 +35,7 @@ type Manager struct {
 	startTime   time.Time          // Agent 启动时间
 	pluginMgr   PluginStatusGetter // 插件管理器接口（用于获取插件状态）
 	resourceMon *resource.Monitor  // 资源监控器
+	guard       *guard.Guard       // 资源保护器（上报限流/熔断状态）
 }
 
 // PluginStatusGetter 是插件状态获取接口（避免循环依赖）
@@ -42,7 +44,7 @@ type PluginStatusGetter interface {
 }
 
 // NewManager 创建新的心跳管理器
-func NewManager(cfg *config.Config, logger *zap.Logger, transportMgr *transport.Manager, agentID string, pluginMgr PluginStatusGetter) *Manager {
+func NewManager(cfg *config.Config, logger *zap.Logger, transportMgr *transport.Manager, agentID string, pluginMgr PluginStatusGetter, resourceGuard *guard.Guard) *Manager {
 	return &Manager{
 		cfg:         cfg,
 		logger:      logger,
@@ -51,14 +53,15 @@ func NewManager(cfg *config.Config, logger *zap.Logger, transportMgr *transport.
 		startTime:   time.Now(),
 		pluginMgr:   pluginMgr,
 		resourceMon: resource.NewMonitor(logger),
+		guard:       resourceGuard,
 	}
 }
 
 // Startup 启动心跳模块
-func Startup(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, logger *zap.Logger, transportMgr *transport.Manager, agentID string, pluginMgr PluginStatusGetter) {
+func Startup(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, logger *zap.Logger, transportMgr *transport.Manager, agentID string, pluginMgr PluginStatusGetter, resourceGuard *guard.Guard) {
 	defer wg.Done()
 
-	mgr := NewManager(cfg, logger, transportMgr, agentID, pluginMgr)
+	mgr := NewManager(cfg, logger, transportMgr, agentID, pluginMgr, resourceGuard)
 
 	ticker := time.NewTicker(cfg.GetHeartbeatInterval())
 	defer ticker.Stop()
@@ -235,6 +238,15 @@ func (m *Manager) sendHeartbeat() {
 		}
 	}
 
+	// 添加资源保护状态（normal / throttled / suspended）
+	if m.guard != nil {
+		guardStatus := m.guard.Status()
+		record.Data.Fields["guard_state"] = string(guardStatus.State)
+		if guardStatusJSON, err := json.Marshal(guardStatus); err == nil {
+			record.Data.Fields["guard_status"] = string(guardStatusJSON)
+		}
+	}
+
 	// 序列化记录
 	recordData, err := proto.Marshal(record)
 	if err != nil {
@@ -348,6 +360,12 @@ func (m *Manager) collectHostInfo() *HostInfo {
 	return hostInfo
 }
 
+// CollectHostInfo 采集主机信息（供离线检查等不启动心跳模块的 CLI 模式使用）
+func CollectHostInfo(logger *zap.Logger) *HostInfo {
+	m := &Manager{logger: logger}
+	return m.collectHostInfo()
+}
+
 // readOSRelease 读取 /etc/os-release 文件获取 OS 信息
 func (m *Manager) readOSRelease() (osFamily, osVersion string) {
 	// 尝试读取 /etc/os-release（systemd 标准）
@@ -0,0 +1,231 @@
+// Package offlinescan 实现离线基线检查（mxsec-agent --scan）
+// 用于无法连接 AgentCenter 的主机：加载 Manager 导出的策略 JSON，在本地执行基线检查，
+// 输出签名结果包，由管理员拷贝到可访问 Manager 的环境后导入
+package offlinescan
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/agent/heartbeat"
+	"github.com/imkerbos/mxsec-platform/internal/agent/id"
+	"github.com/imkerbos/mxsec-platform/internal/offline"
+	"github.com/imkerbos/mxsec-platform/plugins/baseline/engine"
+)
+
+// ScanOptions 离线检查选项
+type ScanOptions struct {
+	PolicyPath   string // 策略 JSON 文件或目录（Manager 策略导出接口的输出）
+	OutputPath   string // 结果包输出路径（为空时写入当前目录）
+	KeyFile      string // 本机签名密钥文件（Manager 按本机 Agent ID 生成的主机密钥）
+	IDFile       string // Agent ID 文件
+	AgentVersion string // Agent 版本
+}
+
+// RunScan 执行离线基线检查并输出签名结果包
+func RunScan(opts ScanOptions) error {
+	if opts.PolicyPath == "" {
+		return fmt.Errorf("请使用 --policy 指定策略文件或目录")
+	}
+	if opts.KeyFile == "" {
+		return fmt.Errorf("请使用 --key 指定签名密钥文件")
+	}
+
+	keyData, err := os.ReadFile(opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("读取签名密钥失败: %w", err)
+	}
+	key := []byte(strings.TrimSpace(string(keyData)))
+	if len(key) < offline.MinKeyLength {
+		return fmt.Errorf("签名密钥长度不能小于 %d 字节", offline.MinKeyLength)
+	}
+
+	policies, err := LoadPolicies(opts.PolicyPath)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return fmt.Errorf("未找到启用的策略: %s", opts.PolicyPath)
+	}
+
+	// 检查过程中的日志只输出警告，避免干扰命令行输出
+	logger := zap.NewNop()
+	if l, err := zap.NewProduction(zap.IncreaseLevel(zap.WarnLevel)); err == nil {
+		logger = l
+		defer l.Sync()
+	}
+
+	agentID, err := AgentID(opts.IDFile)
+	if err != nil {
+		return err
+	}
+	hostInfo := heartbeat.CollectHostInfo(logger)
+	ipv4 := append(append([]string{}, hostInfo.IntranetIPv4...), hostInfo.ExtranetIPv4...)
+
+	fmt.Printf("离线基线检查\n")
+	fmt.Printf("  主机: %s (%s %s)\n", hostInfo.Hostname, hostInfo.OSFamily, hostInfo.OSVersion)
+	fmt.Printf("  Agent ID: %s\n", agentID)
+	fmt.Printf("  策略数: %d\n", len(policies))
+
+	startedAt := time.Now()
+	results := engine.NewEngine(logger).Execute(context.Background(), policies, hostInfo.OSFamily, hostInfo.OSVersion)
+	finishedAt := time.Now()
+	if len(results) == 0 {
+		return fmt.Errorf("没有适用于当前系统（%s %s）的检查规则", hostInfo.OSFamily, hostInfo.OSVersion)
+	}
+
+	bundleID, err := newBundleID()
+	if err != nil {
+		return fmt.Errorf("生成结果包 ID 失败: %w", err)
+	}
+
+	payload := &offline.Payload{
+		BundleID: bundleID,
+		Host: offline.Host{
+			AgentID:      agentID,
+			Hostname:     hostInfo.Hostname,
+			OSFamily:     hostInfo.OSFamily,
+			OSVersion:    hostInfo.OSVersion,
+			Kernel:       hostInfo.Kernel,
+			Arch:         hostInfo.Arch,
+			IPv4:         ipv4,
+			AgentVersion: opts.AgentVersion,
+		},
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+	}
+	for _, p := range policies {
+		payload.Policies = append(payload.Policies, offline.PolicyRef{ID: p.ID, Name: p.Name, Version: p.Version})
+	}
+
+	counts := make(map[engine.Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+		payload.Results = append(payload.Results, offline.Result{
+			RuleID:        r.RuleID,
+			PolicyID:      r.PolicyID,
+			Status:        string(r.Status),
+			Severity:      r.Severity,
+			Category:      r.Category,
+			Title:         r.Title,
+			Actual:        r.Actual,
+			Expected:      r.Expected,
+			FixSuggestion: r.FixSuggestion,
+			CheckedAt:     r.CheckedAt,
+		})
+	}
+
+	data, err := offline.Sign(payload, key)
+	if err != nil {
+		return err
+	}
+
+	output := opts.OutputPath
+	if output == "" {
+		output = fmt.Sprintf("mxsec-baseline-%s-%s.json", hostInfo.Hostname, startedAt.Format("20060102150405"))
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("写入结果包失败: %w", err)
+	}
+
+	fmt.Printf("  检查项: %d（通过 %d，失败 %d，错误 %d，不适用 %d）\n", len(results),
+		counts[engine.StatusPass], counts[engine.StatusFail], counts[engine.StatusError], counts[engine.StatusNA])
+	fmt.Printf("  耗时: %s\n", finishedAt.Sub(startedAt).Round(time.Millisecond))
+	fmt.Printf("结果包已生成: %s\n", output)
+	fmt.Printf("请将结果包拷贝到可访问 Manager 的环境，在「任务执行」页面导入\n")
+	return nil
+}
+
+// AgentID 返回本机 Agent ID（ID 文件不存在时生成），签名密钥与该 ID 绑定
+func AgentID(idFile string) (string, error) {
+	agentID, err := id.InitID(idFile)
+	if err != nil {
+		return "", fmt.Errorf("读取 Agent ID 失败: %w", err)
+	}
+	return agentID, nil
+}
+
+// LoadPolicies 加载策略文件或目录（目录下所有 .json 文件）
+// 支持 Manager 策略导出接口的完整响应（{"code":0,"data":...}）、单个策略和策略数组，未启用的策略被忽略
+func LoadPolicies(path string) ([]*engine.Policy, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("策略路径不存在: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var policies []*engine.Policy
+	seen := make(map[string]bool)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("读取策略文件失败: %w", err)
+		}
+		loaded, err := parsePolicies(data)
+		if err != nil {
+			return nil, fmt.Errorf("解析策略文件 %s 失败: %w", file, err)
+		}
+		for _, p := range loaded {
+			if !p.Enabled || seen[p.ID] {
+				continue
+			}
+			seen[p.ID] = true
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+// parsePolicies 解析策略 JSON
+func parsePolicies(data []byte) ([]*engine.Policy, error) {
+	var envelope struct {
+		Code *int            `json:"code"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err == nil && envelope.Code != nil {
+		if *envelope.Code != 0 {
+			return nil, fmt.Errorf("导出响应 code=%d", *envelope.Code)
+		}
+		data = envelope.Data
+	}
+
+	var policies []*engine.Policy
+	if err := json.Unmarshal(data, &policies); err == nil {
+		return policies, nil
+	}
+	var policy engine.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+	if policy.ID == "" {
+		return nil, fmt.Errorf("缺少策略 ID")
+	}
+	return []*engine.Policy{&policy}, nil
+}
+
+// newBundleID 生成随机结果包 ID
+func newBundleID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
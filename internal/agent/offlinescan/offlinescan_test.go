@@ -0,0 +1,68 @@
+package offlinescan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadPolicies 测试加载导出响应、策略数组、单个策略和目录，忽略未启用和重复的策略
+func TestLoadPolicies(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	envelope := write("export.json", `{"code":0,"message":"ok","data":[
+		{"id":"LINUX_SSH","name":"SSH","enabled":true,"rules":[{"rule_id":"SSH_001"}]},
+		{"id":"LINUX_DISABLED","name":"停用","enabled":false}
+	]}`)
+	array := write("array.json", `[{"id":"LINUX_SSH","name":"SSH 重复","enabled":true},{"id":"LINUX_PAM","name":"PAM","enabled":true}]`)
+	single := write("single.json", `{"id":"LINUX_SYSCTL","name":"内核参数","enabled":true}`)
+	failed := write("failed.json.bad", `{"code":403,"message":"forbidden"}`)
+	noID := write("noid.json.bad", `{"name":"无 ID","enabled":true}`)
+	write("readme.txt", "not a policy")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr string
+	}{
+		{"export envelope", envelope, []string{"LINUX_SSH"}, ""},
+		{"policy array", array, []string{"LINUX_SSH", "LINUX_PAM"}, ""},
+		{"single policy", single, []string{"LINUX_SYSCTL"}, ""},
+		// 目录按文件名排序：array.json 先于 export.json，重复的 LINUX_SSH 保留先加载的
+		{"directory", dir, []string{"LINUX_SSH", "LINUX_PAM", "LINUX_SYSCTL"}, ""},
+		{"export error code", failed, nil, "code=403"},
+		{"missing id", noID, nil, "缺少策略 ID"},
+		{"missing path", filepath.Join(dir, "missing"), nil, "策略路径不存在"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := LoadPolicies(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadPolicies: %v", err)
+			}
+			var got []string
+			for _, p := range policies {
+				got = append(got, p.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+)
+
+// cgroupRoot 是 cgroup v2 统一层级的挂载点
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cpuPeriod 是 cpu.max 的周期（微秒）
+const cpuPeriod = 100000
+
+// cgroupControllers 是插件 cgroup 需要启用的控制器
+var cgroupControllers = []string{"cpu", "memory", "io", "pids"}
+
+// CgroupManager 为每个插件创建独立的 cgroup v2 并设置资源限制
+//
+// 插件 cgroup 创建在 Agent 所在 cgroup 之下（systemd 单元需配置 Delegate=yes）：
+//
+//	<agent cgroup>/agent           Agent 进程（cgroup v2 要求有子 cgroup 的节点不能直接包含进程）
+//	<agent cgroup>/plugin-<name>   插件进程
+//
+// Agent 位于根 cgroup 时（如容器中未使用 systemd），插件 cgroup 创建在 /sys/fs/cgroup/mxsec-agent 下。
+// 系统不是纯 cgroup v2 时资源限制不可用，插件照常启动
+type CgroupManager struct {
+	logger      *zap.Logger
+	once        sync.Once
+	base        string          // 插件 cgroup 的父目录，为空表示不可用
+	controllers map[string]bool // 父目录已启用的控制器
+}
+
+// PluginLimitHits 是插件触发资源限制的累计次数（来自 cgroup 事件计数，插件重启后清零）
+type PluginLimitHits struct {
+	MemoryMax        int64 `json:"memory_max"`         // 内存达到上限被回收的次数（memory.events max）
+	OOMKill          int64 `json:"oom_kill"`           // cgroup 内 OOM Kill 次数
+	CPUThrottled     int64 `json:"cpu_throttled"`      // CPU 被限流的周期数
+	CPUThrottledUsec int64 `json:"cpu_throttled_usec"` // CPU 被限流的总时长（微秒）
+	PidsMax          int64 `json:"pids_max"`           // 达到进程数上限导致 fork 失败的次数
+}
+
+// NewCgroupManager 创建 cgroup 管理器（首次使用时初始化）
+func NewCgroupManager(logger *zap.Logger) *CgroupManager {
+	return &CgroupManager{logger: logger}
+}
+
+// init 检测 cgroup v2 并准备插件 cgroup 的父目录
+func (c *CgroupManager) init() {
+	var st unix.Statfs_t
+	if err := unix.Statfs(cgroupRoot, &st); err != nil || st.Type != unix.CGROUP2_SUPER_MAGIC {
+		c.logger.Warn("cgroup v2 unified hierarchy not available, plugin resource limits disabled")
+		return
+	}
+
+	own, err := ownCgroup()
+	if err != nil {
+		c.logger.Warn("failed to read agent cgroup, plugin resource limits disabled", zap.Error(err))
+		return
+	}
+
+	base := filepath.Join(cgroupRoot, own)
+	if own == "/" {
+		base = filepath.Join(cgroupRoot, "mxsec-agent")
+		if err := os.MkdirAll(base, 0755); err != nil {
+			c.logger.Warn("failed to create plugin cgroup root", zap.String("path", base), zap.Error(err))
+			return
+		}
+		c.enableControllers(cgroupRoot)
+	} else if err := moveProcsToLeaf(base); err != nil {
+		c.logger.Warn("failed to move agent into leaf cgroup, plugin resource limits disabled",
+			zap.String("cgroup", base), zap.Error(err))
+		return
+	}
+
+	c.controllers = c.enableControllers(base)
+	c.base = base
+	c.logger.Info("plugin cgroup initialized",
+		zap.String("path", base),
+		zap.Strings("controllers", enabledNames(c.controllers)))
+}
+
+// enableControllers 在 dir 的 cgroup.subtree_control 中启用可用的控制器，返回已启用的控制器
+func (c *CgroupManager) enableControllers(dir string) map[string]bool {
+	available := make(map[string]bool)
+	if data, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers")); err == nil {
+		for _, name := range strings.Fields(string(data)) {
+			available[name] = true
+		}
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range cgroupControllers {
+		if !available[name] {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("+"+name), 0644); err != nil {
+			c.logger.Warn("failed to enable cgroup controller",
+				zap.String("path", dir), zap.String("controller", name), zap.Error(err))
+			continue
+		}
+		enabled[name] = true
+	}
+	return enabled
+}
+
+// Prepare 创建（或复用）插件 cgroup 并设置资源限制，返回 cgroup 目录的文件描述符，
+// 用于 SysProcAttr.CgroupFD 让插件进程启动时直接进入该 cgroup。cgroup 不可用时返回 nil
+func (c *CgroupManager) Prepare(name string, limits *grpc.PluginLimits) (*os.File, error) {
+	c.once.Do(c.init)
+	if c.base == "" {
+		return nil, nil
+	}
+
+	dir := c.pluginDir(name)
+	if err := os.Mkdir(dir, 0755); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("failed to create plugin cgroup: %w", err)
+	}
+	if err := c.Apply(name, limits); err != nil {
+		c.logger.Warn("failed to apply plugin resource limits", zap.String("plugin", name), zap.Error(err))
+	}
+	return os.Open(dir)
+}
+
+// AddProcess 将进程移入插件 cgroup（内核不支持启动时直接进入 cgroup 时使用）
+func (c *CgroupManager) AddProcess(name string, pid int) error {
+	if c.base == "" {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(c.pluginDir(name), "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644)
+}
+
+// Apply 设置插件 cgroup 的资源限制（插件运行中也可调用，立即生效）
+func (c *CgroupManager) Apply(name string, limits *grpc.PluginLimits) error {
+	c.once.Do(c.init)
+	if c.base == "" {
+		return nil
+	}
+
+	dir := c.pluginDir(name)
+	values := []struct {
+		controller string
+		file       string
+		value      string
+	}{
+		{"cpu", "cpu.max", cpuMax(limits.GetCpuPercent())},
+		{"memory", "memory.max", maxOrValue(limits.GetMemoryMaxMb() * 1024 * 1024)},
+		{"io", "io.weight", ioWeight(limits.GetIoWeight())},
+		{"pids", "pids.max", maxOrValue(int64(limits.GetPidsMax()))},
+	}
+
+	var errs []error
+	for _, v := range values {
+		if !c.controllers[v.controller] {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, v.file), []byte(v.value), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%s: %w", v.file, v.value, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Remove 删除插件 cgroup（插件进程已退出后调用）
+func (c *CgroupManager) Remove(name string) {
+	if c.base == "" {
+		return
+	}
+	if err := unix.Rmdir(c.pluginDir(name)); err != nil && !errors.Is(err, unix.ENOENT) {
+		c.logger.Debug("failed to remove plugin cgroup", zap.String("plugin", name), zap.Error(err))
+	}
+}
+
+// LimitHits 读取插件触发资源限制的次数，cgroup 不可用时返回 nil
+func (c *CgroupManager) LimitHits(name string) *PluginLimitHits {
+	if c.base == "" {
+		return nil
+	}
+
+	dir := c.pluginDir(name)
+	memory := readKeyValues(filepath.Join(dir, "memory.events"))
+	cpu := readKeyValues(filepath.Join(dir, "cpu.stat"))
+	pids := readKeyValues(filepath.Join(dir, "pids.events"))
+	return &PluginLimitHits{
+		MemoryMax:        memory["max"],
+		OOMKill:          memory["oom_kill"],
+		CPUThrottled:     cpu["nr_throttled"],
+		CPUThrottledUsec: cpu["throttled_usec"],
+		PidsMax:          pids["max"],
+	}
+}
+
+// pluginDir 返回插件 cgroup 目录
+func (c *CgroupManager) pluginDir(name string) string {
+	return filepath.Join(c.base, "plugin-"+name)
+}
+
+// ownCgroup 读取当前进程所在的 cgroup v2 路径（/proc/self/cgroup 中 "0::" 开头的行）
+func ownCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("cgroup v2 entry not found in /proc/self/cgroup")
+}
+
+// moveProcsToLeaf 将 base 中的进程移动到 base/agent，使 base 可以启用子树控制器
+func moveProcsToLeaf(base string) error {
+	leaf := filepath.Join(base, "agent")
+	if err := os.Mkdir(leaf, 0755); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(base, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	for _, pid := range strings.Fields(string(data)) {
+		if err := os.WriteFile(filepath.Join(leaf, "cgroup.procs"), []byte(pid), 0644); err != nil {
+			// 进程可能已退出
+			if errors.Is(err, unix.ESRCH) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// readKeyValues 读取 "key value" 格式的 cgroup 统计文件
+func readKeyValues(path string) map[string]int64 {
+	result := make(map[string]int64)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			result[fields[0]] = v
+		}
+	}
+	return result
+}
+
+// cpuMax 将 CPU 百分比转换为 cpu.max 的值
+func cpuMax(percent int32) string {
+	if percent <= 0 {
+		return fmt.Sprintf("max %d", cpuPeriod)
+	}
+	return fmt.Sprintf("%d %d", int64(percent)*cpuPeriod/100, cpuPeriod)
+}
+
+// ioWeight 返回 io.weight 的值（0 时恢复默认权重 100）
+func ioWeight(weight int32) string {
+	if weight <= 0 {
+		weight = 100
+	}
+	return fmt.Sprintf("default %d", weight)
+}
+
+// maxOrValue 返回限制值，0 表示不限制
+func maxOrValue(v int64) string {
+	if v <= 0 {
+		return "max"
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// enabledNames 返回已启用的控制器名称
+func enabledNames(controllers map[string]bool) []string {
+	names := make([]string, 0, len(controllers))
+	for _, name := range cgroupControllers {
+		if controllers[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
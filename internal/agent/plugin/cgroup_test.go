@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCgroupValues 测试资源限制转换为 cgroup v2 控制文件的值，0 表示不限制或默认值
+func TestCgroupValues(t *testing.T) {
+	cpuTests := []struct {
+		percent int32
+		want    string
+	}{
+		{0, "max 100000"},
+		{-1, "max 100000"},
+		{50, "50000 100000"},
+		{100, "100000 100000"},
+		{250, "250000 100000"},
+	}
+	for _, tt := range cpuTests {
+		if got := cpuMax(tt.percent); got != tt.want {
+			t.Errorf("cpuMax(%d) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+
+	ioTests := []struct {
+		weight int32
+		want   string
+	}{
+		{0, "default 100"},
+		{-5, "default 100"},
+		{1, "default 1"},
+		{500, "default 500"},
+		{10000, "default 10000"},
+	}
+	for _, tt := range ioTests {
+		if got := ioWeight(tt.weight); got != tt.want {
+			t.Errorf("ioWeight(%d) = %q, want %q", tt.weight, got, tt.want)
+		}
+	}
+
+	maxTests := []struct {
+		v    int64
+		want string
+	}{
+		{0, "max"},
+		{-1, "max"},
+		{64, "64"},
+		{512 * 1024 * 1024, "536870912"},
+	}
+	for _, tt := range maxTests {
+		if got := maxOrValue(tt.v); got != tt.want {
+			t.Errorf("maxOrValue(%d) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+// TestReadKeyValues 测试解析 cgroup 统计文件，跳过格式不符的行
+func TestReadKeyValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.events")
+	content := "low 0\nhigh 3\nmax 12\noom 1\noom_kill 2\nbroken\nbad value\nextra 1 2\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readKeyValues(path)
+	want := map[string]int64{"low": 0, "high": 3, "max": 12, "oom": 1, "oom_kill": 2}
+	if len(got) != len(want) {
+		t.Fatalf("readKeyValues() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %d, want %d", k, got[k], v)
+		}
+	}
+
+	if got := readKeyValues(filepath.Join(t.TempDir(), "missing")); len(got) != 0 {
+		t.Errorf("missing file: got %v, want empty map", got)
+	}
+}
@@ -38,6 +38,11 @@ type Manager struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 	taskTracker *TaskTracker // 任务追踪器
+	cgroups     *CgroupManager // 插件 cgroup 管理器（资源限制）
+
+	throttle         string         // 当前限流状态（DataTypeThrottle 任务数据），为空表示未限流
+	suspended        bool           // 是否已熔断（全部插件停止）
+	suspendedConfigs []*grpc.Config // 熔断期间保存的插件配置，恢复时按此重新启动
 }
 
 // Plugin 表示一个插件实例
@@ -53,6 +58,8 @@ type Plugin struct {
 	startTime time.Time      // 启动时间
 	lastPong  time.Time      // 最后一次收到插件 pong 的时间（用于健康检查）
 	pingCh    chan struct{}   // 通知 sendTask 发送 ping
+	configCh  chan string     // 通知 sendTask 下发插件配置（Config.detail）
+	throttleCh chan string    // 通知 sendTask 下发限流状态
 	stopCh    chan struct{}   // 停止信号
 	logger    *zap.Logger
 }
@@ -74,6 +81,15 @@ const (
 	DataTypeHeartbeatPong int32 = 9001
 )
 
+// DataTypePluginConfig 是下发插件配置（Config.detail）的任务类型
+const DataTypePluginConfig int32 = 9002
+
+// DataTypeThrottle 是下发限流状态的任务类型（插件 SDK 自动拦截）
+const DataTypeThrottle int32 = 9003
+
+// DataTypeAssetCollectComplete 是资产按需采集完成信号（由 collector 插件上报）
+const DataTypeAssetCollectComplete int32 = 5099
+
 // NewManager 创建新的插件管理器
 func NewManager(cfg *config.Config, logger *zap.Logger, transportMgr *transport.Manager) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -92,6 +108,7 @@ func NewManager(cfg *config.Config, logger *zap.Logger, transportMgr *transport.
 		ctx:         ctx,
 		cancel:      cancel,
 		taskTracker: taskTracker,
+		cgroups:     NewCgroupManager(logger),
 	}
 }
 
@@ -138,6 +155,14 @@ func (m *Manager) SyncPlugins(ctx context.Context, configs []*grpc.Config) error
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// 熔断期间只保存最新配置，恢复时再启动
+	if m.suspended {
+		m.logger.Info("plugins suspended by resource guard, config saved for resume",
+			zap.Int("plugin_count", len(configs)))
+		m.suspendedConfigs = configs
+		return nil
+	}
+
 	// 构建当前配置的插件名称集合
 	configMap := make(map[string]*grpc.Config)
 	for _, cfg := range configs {
@@ -186,6 +211,33 @@ func (m *Manager) SyncPlugins(ctx context.Context, configs []*grpc.Config) error
 				}
 			}
 
+			detailChanged := plugin.Config.Detail != cfg.Detail
+
+			// 加固选项只能在启动时设置，变化时重启插件
+			if !needsUpdate && !proto.Equal(plugin.Config.GetSandbox(), cfg.GetSandbox()) {
+				m.logger.Info("plugin sandbox changed, restarting", zap.String("name", cfg.Name))
+				needsUpdate = true
+			}
+
+			if !needsUpdate && !proto.Equal(plugin.Config.GetLimits(), cfg.GetLimits()) {
+				// 资源限制变化：直接更新 cgroup，无需重启插件
+				m.logger.Info("plugin resource limits changed, applying without restart",
+					zap.String("name", cfg.Name))
+				if err := m.cgroups.Apply(cfg.Name, cfg.GetLimits()); err != nil {
+					m.logger.Warn("failed to apply plugin resource limits",
+						zap.String("name", cfg.Name), zap.Error(err))
+				}
+				plugin.Config = cfg
+			}
+
+			if !needsUpdate && detailChanged {
+				// 仅配置详情变化：不重启插件，直接下发新配置
+				m.logger.Info("plugin config detail changed, applying without restart",
+					zap.String("name", cfg.Name))
+				plugin.Config = cfg
+				plugin.pushConfig(cfg.Detail)
+			}
+
 			if needsUpdate {
 				m.logger.Info("updating plugin", zap.String("name", cfg.Name),
 					zap.String("old_version", plugin.Config.Version),
@@ -288,20 +340,57 @@ func (m *Manager) loadPlugin(ctx context.Context, cfg *grpc.Config) (*Plugin, er
 		return nil, fmt.Errorf("failed to create plugin log rotator: %w", err)
 	}
 
-	// 6. 启动插件进程
-	cmd := exec.CommandContext(ctx, execPath)
-	cmd.Dir = workDir
-	cmd.ExtraFiles = []*os.File{tx_r, rx_w} // 文件描述符 3 (tx_r), 4 (rx_w)
-	cmd.Stdout = logWriter
-	cmd.Stderr = logWriter
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true, // 创建新的进程组
+	// 6. 启动插件进程（配置了加固选项时由 Agent 自身作为启动器）
+	name, args, err := sandboxCommand(execPath, cfg.GetSandbox())
+	if err != nil {
+		logWriter.Close()
+		rx_r.Close()
+		rx_w.Close()
+		tx_r.Close()
+		tx_w.Close()
+		return nil, fmt.Errorf("invalid plugin sandbox: %w", err)
+	}
+	newCmd := func(cgroupFD *os.File) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = workDir
+		cmd.ExtraFiles = []*os.File{tx_r, rx_w} // 文件描述符 3 (tx_r), 4 (rx_w)
+		cmd.Stdout = logWriter
+		cmd.Stderr = logWriter
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Setpgid: true, // 创建新的进程组
+		}
+		// 插件进程启动时直接进入独立的 cgroup，启动阶段也受资源限制
+		if cgroupFD != nil {
+			cmd.SysProcAttr.UseCgroupFD = true
+			cmd.SysProcAttr.CgroupFD = int(cgroupFD.Fd())
+		}
+		cmd.Env = pluginEnv(cfg.GetSandbox())
+		return cmd
 	}
 
-	// 设置环境变量（可选）
-	cmd.Env = os.Environ()
-
-	if err := cmd.Start(); err != nil {
+	cgroupFD, err := m.cgroups.Prepare(cfg.Name, cfg.GetLimits())
+	if err != nil {
+		m.logger.Warn("failed to prepare plugin cgroup, starting without resource limits",
+			zap.String("plugin", cfg.Name), zap.Error(err))
+	}
+	cmd := newCmd(cgroupFD)
+	err = cmd.Start()
+	if err != nil && cgroupFD != nil {
+		// 内核不支持 clone3（CLONE_INTO_CGROUP 需要 5.7+）时，先启动再移入 cgroup
+		m.logger.Debug("failed to start plugin into cgroup, retrying without CgroupFD",
+			zap.String("plugin", cfg.Name), zap.Error(err))
+		cmd = newCmd(nil)
+		if err = cmd.Start(); err == nil {
+			if err := m.cgroups.AddProcess(cfg.Name, cmd.Process.Pid); err != nil {
+				m.logger.Warn("failed to move plugin into cgroup",
+					zap.String("plugin", cfg.Name), zap.Error(err))
+			}
+		}
+	}
+	if cgroupFD != nil {
+		cgroupFD.Close()
+	}
+	if err != nil {
 		logWriter.Close()
 		rx_r.Close()
 		rx_w.Close()
@@ -327,6 +416,8 @@ func (m *Manager) loadPlugin(ctx context.Context, cfg *grpc.Config) (*Plugin, er
 		startTime: now,
 		lastPong:  now, // 初始化为启动时间，避免立即判定超时
 		pingCh:    make(chan struct{}, 1),
+		configCh:  make(chan string, 1),
+		throttleCh: make(chan string, 1),
 		stopCh:    make(chan struct{}),
 		logger:    m.logger.With(zap.String("plugin", cfg.Name)),
 	}
@@ -348,6 +439,16 @@ func (m *Manager) loadPlugin(ctx context.Context, cfg *grpc.Config) (*Plugin, er
 
 	plugin.logger.Info("plugin loaded successfully", zap.String("version", cfg.Version))
 
+	// 下发初始配置
+	if cfg.Detail != "" {
+		plugin.pushConfig(cfg.Detail)
+	}
+
+	// 限流期间启动的插件同样需要知道限流状态
+	if m.throttle != "" {
+		pushLatest(plugin.throttleCh, m.throttle)
+	}
+
 	// 9. 重新分发未完成的任务（如果有任务追踪器）
 	if m.taskTracker != nil {
 		go m.retryPendingTasks(plugin)
@@ -598,6 +699,9 @@ func (m *Manager) waitProcess(plugin *Plugin) {
 		plugin.logWriter.Close()
 	}
 
+	// 删除插件 cgroup（仍有残留子进程时删除失败，下次启动复用）
+	m.cgroups.Remove(plugin.Config.Name)
+
 	// 通知停止
 	close(plugin.stopCh)
 
@@ -779,6 +883,19 @@ func (m *Manager) receiveData(plugin *Plugin) {
 				continue
 			}
 
+			// 资产按需采集完成信号：按 token 标记任务完成（一个采集任务对应一个 token）
+			if m.taskTracker != nil && record.DataType == DataTypeAssetCollectComplete {
+				if record.Data != nil && record.Data.Fields != nil {
+					if token := record.Data.Fields["token"]; token != "" {
+						if err := m.taskTracker.MarkCompleted(token); err != nil {
+							plugin.logger.Warn("failed to mark collect task as completed",
+								zap.String("token", token),
+								zap.Error(err))
+						}
+					}
+				}
+			}
+
 			// 检查是否是任务完成信号（DataType 8001 或 8004）
 			if m.taskTracker != nil && (record.DataType == 8001 || record.DataType == 8004) {
 				// 从 payload 中提取 task_id
@@ -846,6 +963,35 @@ func (m *Manager) sendTask(plugin *Plugin) {
 				plugin.logger.Error("failed to flush ping data", zap.Error(err))
 				continue
 			}
+		case detail := <-plugin.configCh:
+			// 下发插件配置（轻量 Task，无 token，不经过 taskTracker）
+			configTask := &bridge.Task{DataType: DataTypePluginConfig, Data: detail}
+			configData, err := proto.Marshal(configTask)
+			if err != nil {
+				plugin.logger.Error("failed to marshal config task", zap.Error(err))
+				continue
+			}
+			configLen := uint32(len(configData))
+			if err := binary.Write(writer, binary.LittleEndian, configLen); err != nil {
+				plugin.logger.Error("failed to write config size", zap.Error(err))
+				continue
+			}
+			if _, err := writer.Write(configData); err != nil {
+				plugin.logger.Error("failed to write config data", zap.Error(err))
+				continue
+			}
+			if err := writer.Flush(); err != nil {
+				plugin.logger.Error("failed to flush config data", zap.Error(err))
+				continue
+			}
+			plugin.logger.Info("plugin config sent to plugin")
+		case state := <-plugin.throttleCh:
+			// 下发限流状态（轻量 Task，无 token，不经过 taskTracker）
+			if err := writeTask(writer, &bridge.Task{DataType: DataTypeThrottle, Data: state}); err != nil {
+				plugin.logger.Error("failed to send throttle state", zap.Error(err))
+				continue
+			}
+			plugin.logger.Debug("throttle state sent to plugin", zap.String("state", state))
 		case task, ok := <-taskCh:
 			if !ok {
 				// 通道已关闭
@@ -905,8 +1051,8 @@ func (m *Manager) sendTask(plugin *Plugin) {
 				continue
 			}
 
-			// 标记任务已分发
-			if m.taskTracker != nil {
+			// 标记任务已分发（无 Token 的任务不追踪）
+			if m.taskTracker != nil && task.Token != "" {
 				if err := m.taskTracker.MarkDispatched(task.Token); err != nil {
 					plugin.logger.Warn("failed to mark task as dispatched", zap.Error(err))
 				}
@@ -1002,17 +1148,30 @@ func (m *Manager) GetAllPluginStats() map[string]interface{} {
 	stats := make(map[string]interface{})
 	for name, plugin := range m.plugins {
 		plugin.mu.RLock()
-		stats[name] = map[string]interface{}{
+		stat := map[string]interface{}{
 			"status":     string(plugin.status),
 			"version":    plugin.Config.Version,
 			"start_time": plugin.startTime.Unix(),
 		}
 		plugin.mu.RUnlock()
+		// 资源限制触发次数（cgroup v2 可用时）
+		if hits := m.cgroups.LimitHits(name); hits != nil {
+			stat["limit_hits"] = hits
+		}
+		stats[name] = stat
 	}
 
 	return stats
 }
 
+// GetTrackedTasks 获取任务追踪器中的任务（用于诊断信息）
+func (m *Manager) GetTrackedTasks() []TrackedTask {
+	if m.taskTracker == nil {
+		return nil
+	}
+	return m.taskTracker.Snapshot()
+}
+
 // retryPendingTasks 重新分发未完成的任务
 func (m *Manager) retryPendingTasks(plugin *Plugin) {
 	// 等待插件完全启动
@@ -1042,3 +1201,24 @@ func (m *Manager) retryPendingTasks(plugin *Plugin) {
 		}
 	}
 }
+
+// pushConfig 将插件配置放入待下发通道，只保留最新一份配置
+func (p *Plugin) pushConfig(detail string) {
+	pushLatest(p.configCh, detail)
+}
+
+// pushLatest 将值放入容量为 1 的通道，丢弃尚未消费的旧值
+func pushLatest(ch chan string, v string) {
+	for {
+		select {
+		case ch <- v:
+			return
+		default:
+			// 丢弃尚未下发的旧值
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
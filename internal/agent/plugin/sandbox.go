@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/capability"
+)
+
+// SandboxExecArg 是 Agent 以沙箱启动器模式运行时的第一个参数
+//
+// Go 无法在 fork 与 exec 之间执行代码，设置 no_new_privs 和丢弃 capabilities 需要由 Agent 重新执行自身：
+//
+//	mxsec-agent __plugin-exec [--no-new-privs] [--drop-caps CAP_A,CAP_B] -- <插件路径>
+//
+// 启动器在当前线程上完成设置后 exec 插件，插件继承这些限制
+const SandboxExecArg = "__plugin-exec"
+
+// defaultPath 是最小环境变量模式下的 PATH（Agent 环境中没有 PATH 时使用）
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// minimalEnvKeys 是最小环境变量模式下保留的环境变量
+var minimalEnvKeys = []string{"PATH", "LANG", "LC_ALL", "TZ"}
+
+// needsSandboxExec 判断插件是否需要通过沙箱启动器启动
+func needsSandboxExec(sandbox *grpc.PluginSandbox) bool {
+	return sandbox.GetNoNewPrivs() || len(sandbox.GetDropCapabilities()) > 0
+}
+
+// sandboxCommand 返回启动插件的程序和参数（需要加固时由 Agent 自身作为启动器）
+func sandboxCommand(execPath string, sandbox *grpc.PluginSandbox) (string, []string, error) {
+	if !needsSandboxExec(sandbox) {
+		return execPath, nil, nil
+	}
+	if err := capability.Validate(sandbox.GetDropCapabilities()); err != nil {
+		return "", nil, err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to locate agent executable: %w", err)
+	}
+	args := []string{SandboxExecArg}
+	if sandbox.GetNoNewPrivs() {
+		args = append(args, "--no-new-privs")
+	}
+	if caps := sandbox.GetDropCapabilities(); len(caps) > 0 {
+		args = append(args, "--drop-caps", strings.Join(caps, ","))
+	}
+	args = append(args, "--", execPath)
+	return self, args, nil
+}
+
+// pluginEnv 返回插件进程的环境变量
+func pluginEnv(sandbox *grpc.PluginSandbox) []string {
+	if !sandbox.GetMinimalEnv() {
+		return os.Environ()
+	}
+	env := make([]string, 0, len(minimalEnvKeys))
+	for _, key := range minimalEnvKeys {
+		if v, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+v)
+		} else if key == "PATH" {
+			env = append(env, "PATH="+defaultPath)
+		}
+	}
+	return env
+}
+
+// RunSandboxExec 是沙箱启动器入口（args 为 SandboxExecArg 之后的参数），成功时不返回
+func RunSandboxExec(args []string) error {
+	fs := flag.NewFlagSet(SandboxExecArg, flag.ContinueOnError)
+	noNewPrivs := fs.Bool("no-new-privs", false, "set no_new_privs")
+	dropCaps := fs.String("drop-caps", "", "capabilities to drop")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("plugin path is required")
+	}
+	caps, err := capability.Parse(strings.Split(*dropCaps, ","))
+	if err != nil {
+		return err
+	}
+
+	// capabilities 和 no_new_privs 都是线程属性，设置与 exec 必须在同一线程上完成
+	runtime.LockOSThread()
+
+	if len(caps) > 0 {
+		if err := dropCapabilities(caps); err != nil {
+			return err
+		}
+	}
+	if *noNewPrivs {
+		if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("failed to set no_new_privs: %w", err)
+		}
+	}
+
+	path := fs.Arg(0)
+	return syscall.Exec(path, fs.Args(), os.Environ())
+}
+
+// dropCapabilities 从 bounding、inheritable、permitted、effective 集合中移除 capabilities
+// root 执行程序时 permitted 集合由 bounding 集合决定，因此必须先从 bounding 集合移除
+func dropCapabilities(caps []int) error {
+	for _, c := range caps {
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(c), 0, 0, 0); err != nil && err != unix.EINVAL {
+			return fmt.Errorf("failed to drop capability %d from bounding set: %w", c, err)
+		}
+	}
+
+	hdr := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	if err := unix.Capget(&hdr, &data[0]); err != nil {
+		return fmt.Errorf("failed to get capabilities: %w", err)
+	}
+	for _, c := range caps {
+		mask := uint32(1) << (uint(c) % 32)
+		i := c / 32
+		data[i].Effective &^= mask
+		data[i].Permitted &^= mask
+		data[i].Inheritable &^= mask
+	}
+	if err := unix.Capset(&hdr, &data[0]); err != nil {
+		return fmt.Errorf("failed to set capabilities: %w", err)
+	}
+	return nil
+}
@@ -64,7 +64,12 @@ func NewTaskTracker(workDir string, logger *zap.Logger) (*TaskTracker, error) {
 }
 
 // TrackTask tracks a new task
+// Tasks without a token (e.g. snapshot acks) are fire-and-forget and not tracked.
 func (t *TaskTracker) TrackTask(task *grpc.Task, pluginName string) error {
+	if task.Token == "" {
+		return nil
+	}
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -191,6 +196,18 @@ func (t *TaskTracker) GetPendingTasks(pluginName string) []*grpc.Task {
 	return pending
 }
 
+// Snapshot returns a copy of all tracked tasks (used for diagnostics)
+func (t *TaskTracker) Snapshot() []TrackedTask {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tasks := make([]TrackedTask, 0, len(t.tasks))
+	for _, tracked := range t.tasks {
+		tasks = append(tasks, *tracked)
+	}
+	return tasks
+}
+
 // saveTask saves a task to disk
 func (t *TaskTracker) saveTask(tracked *TrackedTask) error {
 	data, err := json.Marshal(tracked)
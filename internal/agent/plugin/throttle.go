@@ -0,0 +1,120 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+)
+
+// throttleState 是 DataTypeThrottle 任务的数据（与插件 SDK 保持一致）
+type throttleState struct {
+	Until  int64  `json:"until"`  // 限流截止时间（Unix 秒），0 表示解除限流
+	Reason string `json:"reason"` // 限流原因
+}
+
+// SetThrottle 通知所有插件暂停周期性工作直到 until，until 为零值表示解除限流
+// 之后启动的插件也会收到当前限流状态
+func (m *Manager) SetThrottle(until time.Time, reason string) {
+	state := throttleState{Reason: reason}
+	if !until.IsZero() {
+		state.Until = until.Unix()
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		m.logger.Error("failed to marshal throttle state", zap.Error(err))
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if until.IsZero() {
+		m.throttle = ""
+	} else {
+		m.throttle = string(data)
+	}
+	for _, plugin := range m.plugins {
+		pushLatest(plugin.throttleCh, string(data))
+	}
+}
+
+// Suspend 停止全部插件并保存当前配置（资源熔断），Resume 时按保存的配置重新启动
+func (m *Manager) Suspend(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.suspended {
+		return
+	}
+
+	configs := make([]*grpc.Config, 0, len(m.plugins))
+	for name, plugin := range m.plugins {
+		configs = append(configs, plugin.Config)
+		m.logger.Warn("stopping plugin by resource guard",
+			zap.String("name", name),
+			zap.String("reason", reason))
+		if err := m.stopPlugin(plugin); err != nil {
+			m.logger.Error("failed to stop plugin", zap.String("name", name), zap.Error(err))
+		}
+	}
+
+	m.plugins = make(map[string]*Plugin)
+	m.suspended = true
+	m.suspendedConfigs = configs
+}
+
+// Resume 解除熔断，按熔断期间保存的最新配置重新启动插件
+func (m *Manager) Resume() error {
+	m.mu.Lock()
+	if !m.suspended {
+		m.mu.Unlock()
+		return nil
+	}
+	configs := m.suspendedConfigs
+	m.suspended = false
+	m.suspendedConfigs = nil
+	m.mu.Unlock()
+
+	m.logger.Info("resuming plugins after resource guard recovery", zap.Int("plugin_count", len(configs)))
+	return m.SyncPlugins(m.ctx, configs)
+}
+
+// PluginPIDs 返回运行中插件的进程 ID（用于统计 Agent 及插件的资源占用）
+func (m *Manager) PluginPIDs() []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pids := make([]int, 0, len(m.plugins))
+	for _, plugin := range m.plugins {
+		plugin.mu.RLock()
+		running := plugin.status == StatusRunning
+		plugin.mu.RUnlock()
+		if running && plugin.cmd != nil && plugin.cmd.Process != nil {
+			pids = append(pids, plugin.cmd.Process.Pid)
+		}
+	}
+	return pids
+}
+
+// writeTask 向插件管道写入一个任务（4 字节长度 + protobuf）
+func writeTask(writer *bufio.Writer, task *bridge.Task) error {
+	data, err := proto.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	if err := binary.Write(writer, binary.LittleEndian, uint32(len(data))); err != nil {
+		return fmt.Errorf("failed to write task size: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write task data: %w", err)
+	}
+	return writer.Flush()
+}
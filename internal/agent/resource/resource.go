@@ -274,3 +274,41 @@ func (m *Monitor) collectNetwork() (uint64, uint64, error) {
 
 	return bytesSent, bytesRecv, nil
 }
+
+// ClockTicks 是 /proc/<pid>/stat 中 CPU 时间的单位（USER_HZ，Linux 上固定为 100）
+const ClockTicks = 100
+
+// ProcStat 是单个进程的资源占用
+type ProcStat struct {
+	CPUTicks uint64 // 累计 CPU 时间（用户态 + 内核态，含已回收的子进程，单位 ClockTicks）
+	RSS      uint64 // 常驻内存（字节）
+}
+
+// ReadProcStat 读取进程的资源占用（/proc/<pid>/stat）
+func ReadProcStat(pid int) (*ProcStat, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+
+	// 第 2 个字段是带括号的进程名（可能包含空格），从最后一个 ')' 之后开始解析
+	line := string(data)
+	idx := strings.LastIndexByte(line, ')')
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid /proc/%d/stat format", pid)
+	}
+	// fields[0] 是第 3 个字段（state），第 N 个字段为 fields[N-3]
+	fields := strings.Fields(line[idx+1:])
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("invalid /proc/%d/stat format", pid)
+	}
+
+	var stat ProcStat
+	for _, i := range []int{11, 12, 13, 14} { // utime, stime, cutime, cstime
+		v, _ := strconv.ParseUint(fields[i], 10, 64)
+		stat.CPUTicks += v
+	}
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+	stat.RSS = rssPages * uint64(os.Getpagesize())
+	return &stat, nil
+}
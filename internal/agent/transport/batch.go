@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/compress"
+)
+
+// batcher 将插件记录在时间窗口内合并为一个 PackagedData，减少小包数量并提高压缩率
+type batcher struct {
+	interval   time.Duration
+	maxRecords int
+	maxBytes   int
+	flushFn    func(records []*grpc.EncodedRecord, seqs []uint64)
+
+	mu      sync.Mutex
+	records []*grpc.EncodedRecord
+	seqs    []uint64 // 批次中事件类记录的 outbox 序列号
+	bytes   int
+	timer   *time.Timer
+}
+
+// newBatcher 创建 batcher，interval <= 0 时每条记录单独发送
+func newBatcher(interval time.Duration, maxRecords, maxBytes int, flushFn func([]*grpc.EncodedRecord, []uint64)) *batcher {
+	if maxRecords <= 0 {
+		maxRecords = 200
+	}
+	if maxBytes <= 0 {
+		maxBytes = 1024 * 1024
+	}
+	return &batcher{
+		interval:   interval,
+		maxRecords: maxRecords,
+		maxBytes:   maxBytes,
+		flushFn:    flushFn,
+	}
+}
+
+// add 加入一条记录（seq 为 0 表示非事件类记录），达到记录数或字节数上限时立即发送
+func (b *batcher) add(record *grpc.EncodedRecord, seq uint64) {
+	if b.interval <= 0 {
+		var seqs []uint64
+		if seq > 0 {
+			seqs = []uint64{seq}
+		}
+		b.flushFn([]*grpc.EncodedRecord{record}, seqs)
+		return
+	}
+
+	b.mu.Lock()
+	b.records = append(b.records, record)
+	if seq > 0 {
+		b.seqs = append(b.seqs, seq)
+	}
+	b.bytes += len(record.Data)
+
+	if len(b.records) >= b.maxRecords || b.bytes >= b.maxBytes {
+		records, seqs := b.takeLocked()
+		b.mu.Unlock()
+		b.flushFn(records, seqs)
+		return
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, b.flush)
+	}
+	b.mu.Unlock()
+}
+
+// flush 发送当前批次（定时器到期时调用）
+func (b *batcher) flush() {
+	b.mu.Lock()
+	records, seqs := b.takeLocked()
+	b.mu.Unlock()
+	if len(records) > 0 {
+		b.flushFn(records, seqs)
+	}
+}
+
+// takeLocked 取出当前批次并重置（调用方持有锁）
+func (b *batcher) takeLocked() ([]*grpc.EncodedRecord, []uint64) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	records, seqs := b.records, b.seqs
+	b.records, b.seqs, b.bytes = nil, nil, 0
+	return records, seqs
+}
+
+// enqueueBatch 将合并后的记录放入发送缓冲区
+func (m *Manager) enqueueBatch(records []*grpc.EncodedRecord, seqs []uint64) {
+	data := &grpc.PackagedData{
+		Records: records,
+		AgentId: m.agentID,
+	}
+
+	select {
+	case m.sendBuffer <- data:
+		for _, seq := range seqs {
+			m.outbox.MarkSent(seq)
+		}
+	default:
+		if len(seqs) > 0 {
+			// 事件类记录已在 outbox 中，由重发循环补发
+			m.logger.Warn("send buffer full, event records will be resent from outbox",
+				zap.Int("record_count", len(records)),
+				zap.Int("event_count", len(seqs)))
+			return
+		}
+		// 缓冲区满，丢弃（资产数据是状态快照，下次采集会重新上报）
+		m.logger.Warn("send buffer full, dropping plugin data (will re-collect next cycle)",
+			zap.Int("record_count", len(records)))
+	}
+}
+
+// streamCallOptions 返回建立 Transfer 流的调用选项（压缩算法）
+func (m *Manager) streamCallOptions() []grpclib.CallOption {
+	name := m.cfg.Local.Transport.Compression
+	if name == "" || name == compress.None || m.compressionOff.Load() {
+		return nil
+	}
+	if !compress.Supported(name) {
+		m.logger.Warn("unsupported compression, sending uncompressed", zap.String("compression", name))
+		return nil
+	}
+	return []grpclib.CallOption{grpclib.UseCompressor(name)}
+}
+
+// checkCompressionRejected 检查 Server 是否因不支持压缩算法拒绝了数据流（旧版本 Server），
+// 是则后续连接不再压缩
+func (m *Manager) checkCompressionRejected(err error) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unimplemented || !strings.Contains(st.Message(), "grpc-encoding") {
+		return
+	}
+	if m.compressionOff.CompareAndSwap(false, true) {
+		m.logger.Warn("server does not support compression, falling back to uncompressed stream",
+			zap.String("compression", m.cfg.Local.Transport.Compression),
+			zap.String("reason", st.Message()))
+	}
+}
+
+// throttle 记录 Server 要求的暂停上报时长（入库队列饱和时下发）
+func (m *Manager) throttle(d time.Duration) {
+	m.throttleUntil.Store(time.Now().Add(d).UnixNano())
+	m.logger.Warn("server ingest queue saturated, pausing plugin data upload",
+		zap.Duration("duration", d),
+	)
+}
+
+// waitThrottle 在 Server 要求的暂停时间内等待，context 取消或控制类数据发送失败时返回 false
+// 暂停期间插件数据在 sendBuffer 中积压，事件类记录由发件箱保证不丢失；控制类数据照常通过 sendControl 发送
+func (m *Manager) waitThrottle(ctx context.Context, sendControl func(*grpc.PackagedData) bool) bool {
+	wait := time.Until(time.Unix(0, m.throttleUntil.Load()))
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case data := <-m.controlBuffer:
+			if !sendControl(data) {
+				return false
+			}
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// controlDataTypes 是控制类数据的数据类型，不受背压暂停影响，避免 Server 误判离线
+var controlDataTypes = map[int32]bool{
+	1000: true, // Agent 心跳
+	9001: true, // 插件心跳 pong
+}
+
+// isControlData 判断数据包是否为控制类数据
+func isControlData(data *grpc.PackagedData) bool {
+	return len(data.Records) > 0 && controlDataTypes[data.Records[0].DataType]
+}
@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+)
+
+// TestBatcherFlushBySizeAndTimer 测试达到记录数上限立即发送、未达上限时按时间窗口发送
+func TestBatcherFlushBySizeAndTimer(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]*grpc.EncodedRecord
+	var allSeqs []uint64
+	done := make(chan struct{}, 10)
+	b := newBatcher(50*time.Millisecond, 3, 1024, func(records []*grpc.EncodedRecord, seqs []uint64) {
+		mu.Lock()
+		batches = append(batches, records)
+		allSeqs = append(allSeqs, seqs...)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	for i := 0; i < 4; i++ {
+		var seq uint64
+		if i%2 == 0 {
+			seq = uint64(i + 1)
+		}
+		b.add(&grpc.EncodedRecord{DataType: 5050, Data: []byte("x")}, seq)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("batch not flushed")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 1 {
+		t.Fatalf("unexpected batches: %d", len(batches))
+	}
+	if len(allSeqs) != 2 || allSeqs[0] != 1 || allSeqs[1] != 3 {
+		t.Fatalf("unexpected seqs: %v", allSeqs)
+	}
+}
+
+// TestWaitThrottleSendsControlData 测试背压暂停期间心跳照常发送，插件数据等待暂停结束
+func TestWaitThrottleSendsControlData(t *testing.T) {
+	m := &Manager{logger: zap.NewNop(), controlBuffer: make(chan *grpc.PackagedData, 4)}
+	m.throttle(time.Minute)
+
+	heartbeat := &grpc.PackagedData{Records: []*grpc.EncodedRecord{{DataType: 1000}}}
+	m.controlBuffer <- heartbeat
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := make(chan *grpc.PackagedData, 4)
+	result := make(chan bool, 1)
+	go func() {
+		result <- m.waitThrottle(ctx, func(data *grpc.PackagedData) bool {
+			sent <- data
+			return true
+		})
+	}()
+
+	select {
+	case data := <-sent:
+		if data != heartbeat {
+			t.Fatal("unexpected control data sent")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat not sent during throttle")
+	}
+	select {
+	case <-result:
+		t.Fatal("waitThrottle returned before throttle expired")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if <-result {
+		t.Fatal("waitThrottle should return false after context canceled")
+	}
+}
+
+// TestIsControlData 测试心跳和插件 pong 属于控制类数据
+func TestIsControlData(t *testing.T) {
+	tests := []struct {
+		dataType int32
+		want     bool
+	}{
+		{1000, true},
+		{9001, true},
+		{6001, false},
+		{5050, false},
+	}
+	for _, tt := range tests {
+		data := &grpc.PackagedData{Records: []*grpc.EncodedRecord{{DataType: tt.dataType}}}
+		if got := isControlData(data); got != tt.want {
+			t.Errorf("isControlData(%d) = %v, want %v", tt.dataType, got, tt.want)
+		}
+	}
+	if isControlData(&grpc.PackagedData{}) {
+		t.Error("empty data should not be control data")
+	}
+}
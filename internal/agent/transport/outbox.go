@@ -0,0 +1,301 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+)
+
+// eventDataTypes 是事件类记录的数据类型
+// 事件类记录（检查结果、修复结果、FIM 事件、任务完成信号）丢失后无法通过下一次上报恢复，
+// 需要写入 outbox 持久化，直到 Server 确认；其余记录（心跳、资产等）是状态快照，丢失后下次上报即可覆盖。
+var eventDataTypes = map[int32]bool{
+	8000: true, // 基线检查结果
+	8001: true, // 基线任务完成信号
+	8003: true, // 基线修复结果
+	8004: true, // 修复任务完成信号
+	6001: true, // FIM 事件
+	6002: true, // FIM 任务完成信号
+	5099: true, // 资产按需采集完成信号
+}
+
+// IsEventDataType 判断数据类型是否为事件类记录（需要可靠投递）
+func IsEventDataType(dataType int32) bool {
+	return eventDataTypes[dataType]
+}
+
+const (
+	outboxFileSuffix = ".rec"
+	outboxSeqFile    = "seq"
+)
+
+// outboxEntry 是 outbox 中的一条待确认记录
+type outboxEntry struct {
+	seq       uint64
+	size      int64
+	createdAt time.Time
+	sentAt    time.Time // 最近一次发送时间，零值表示待发送
+}
+
+// Outbox 是事件类记录的持久化发件箱
+// 每条记录分配单调递增的序列号并写入独立文件，Server 确认（Command.ack_seqs）后删除；
+// 未确认的记录在重连或超时后重新发送，Server 按 (agent_id, seq) 去重。
+type Outbox struct {
+	dir     string
+	maxSize int64         // 最大占用空间（字节），超出时丢弃最旧的记录
+	maxAge  time.Duration // 最长保留时间
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	nextSeq uint64
+	entries map[uint64]*outboxEntry
+	size    int64
+}
+
+// NewOutbox 打开（或创建）outbox 目录并加载未确认的记录
+func NewOutbox(dir string, maxSize int64, maxAge time.Duration, logger *zap.Logger) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox dir: %w", err)
+	}
+
+	o := &Outbox{
+		dir:     dir,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		logger:  logger,
+		entries: make(map[uint64]*outboxEntry),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox dir: %w", err)
+	}
+
+	var maxSeq uint64
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, outboxFileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, outboxFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		o.entries[seq] = &outboxEntry{seq: seq, size: info.Size(), createdAt: info.ModTime()}
+		o.size += info.Size()
+		if seq > maxSeq {
+			maxSeq = seq
+		}
+	}
+
+	// 序列号取 max(已有记录, 上次分配, 当前纳秒时间戳)，保证重装 Agent 后也不会与历史序列号重复
+	o.nextSeq = maxSeq + 1
+	if data, err := os.ReadFile(filepath.Join(dir, outboxSeqFile)); err == nil {
+		if last, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err == nil && last+1 > o.nextSeq {
+			o.nextSeq = last + 1
+		}
+	}
+	if now := uint64(time.Now().UnixNano()); now > o.nextSeq {
+		o.nextSeq = now
+	}
+
+	if len(o.entries) > 0 {
+		logger.Info("loaded unacknowledged records from outbox",
+			zap.Int("count", len(o.entries)),
+			zap.Int64("size", o.size))
+	}
+
+	return o, nil
+}
+
+// Put 为记录分配序列号并持久化，返回分配的序列号
+func (o *Outbox) Put(record *grpc.EncodedRecord) (uint64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	seq := o.nextSeq
+	o.nextSeq++
+	record.Seq = seq
+
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	// 先写临时文件再重命名，避免进程崩溃留下不完整的记录
+	path := o.path(seq)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write outbox record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to commit outbox record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(o.dir, outboxSeqFile), []byte(strconv.FormatUint(seq, 10)), 0600); err != nil {
+		o.logger.Warn("failed to persist outbox sequence", zap.Error(err))
+	}
+
+	o.entries[seq] = &outboxEntry{seq: seq, size: int64(len(data)), createdAt: time.Now()}
+	o.size += int64(len(data))
+	o.enforceLimits()
+
+	return seq, nil
+}
+
+// MarkSent 标记记录已发送
+func (o *Outbox) MarkSent(seqs ...uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	for _, seq := range seqs {
+		if entry, ok := o.entries[seq]; ok {
+			entry.sentAt = now
+		}
+	}
+}
+
+// MarkUnsent 标记记录待发送（发送失败或连接断开时调用）
+func (o *Outbox) MarkUnsent(seqs ...uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, seq := range seqs {
+		if entry, ok := o.entries[seq]; ok {
+			entry.sentAt = time.Time{}
+		}
+	}
+}
+
+// ResetSent 将所有记录标记为待发送（重连后调用）
+func (o *Outbox) ResetSent() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, entry := range o.entries {
+		entry.sentAt = time.Time{}
+	}
+}
+
+// Due 返回待发送或发送后超过 resendAfter 仍未确认的记录（按序列号升序，最多 limit 条），并标记为已发送
+func (o *Outbox) Due(resendAfter time.Duration, limit int) []*grpc.EncodedRecord {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.enforceLimits()
+
+	now := time.Now()
+	var seqs []uint64
+	for seq, entry := range o.entries {
+		if entry.sentAt.IsZero() || now.Sub(entry.sentAt) >= resendAfter {
+			seqs = append(seqs, seq)
+		}
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	if len(seqs) > limit {
+		seqs = seqs[:limit]
+	}
+
+	records := make([]*grpc.EncodedRecord, 0, len(seqs))
+	for _, seq := range seqs {
+		data, err := os.ReadFile(o.path(seq))
+		if err != nil {
+			o.logger.Warn("failed to read outbox record, dropping", zap.Uint64("seq", seq), zap.Error(err))
+			o.remove(seq)
+			continue
+		}
+		record := &grpc.EncodedRecord{}
+		if err := proto.Unmarshal(data, record); err != nil {
+			o.logger.Warn("corrupted outbox record, dropping", zap.Uint64("seq", seq), zap.Error(err))
+			o.remove(seq)
+			continue
+		}
+		o.entries[seq].sentAt = now
+		records = append(records, record)
+	}
+	return records
+}
+
+// Ack 删除 Server 已确认的记录
+func (o *Outbox) Ack(seqs []uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, seq := range seqs {
+		o.remove(seq)
+	}
+}
+
+// Len 返回未确认的记录数
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// enforceLimits 丢弃过期记录，并在超出空间限制时丢弃最旧的记录（调用方持有锁）
+func (o *Outbox) enforceLimits() {
+	now := time.Now()
+	expired := 0
+	for seq, entry := range o.entries {
+		if now.Sub(entry.createdAt) > o.maxAge {
+			o.remove(seq)
+			expired++
+		}
+	}
+
+	dropped := 0
+	if o.size > o.maxSize {
+		seqs := make([]uint64, 0, len(o.entries))
+		for seq := range o.entries {
+			seqs = append(seqs, seq)
+		}
+		sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+		for _, seq := range seqs {
+			if o.size <= o.maxSize {
+				break
+			}
+			o.remove(seq)
+			dropped++
+		}
+	}
+
+	if expired > 0 || dropped > 0 {
+		o.logger.Warn("outbox records discarded before acknowledgement",
+			zap.Int("expired", expired),
+			zap.Int("dropped_for_size", dropped))
+	}
+}
+
+// remove 删除记录文件（调用方持有锁）
+func (o *Outbox) remove(seq uint64) {
+	entry, ok := o.entries[seq]
+	if !ok {
+		return
+	}
+	if err := os.Remove(o.path(seq)); err != nil && !os.IsNotExist(err) {
+		o.logger.Warn("failed to remove outbox record", zap.Uint64("seq", seq), zap.Error(err))
+	}
+	o.size -= entry.size
+	delete(o.entries, seq)
+}
+
+// path 返回记录文件路径
+func (o *Outbox) path(seq uint64) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d%s", seq, outboxFileSuffix))
+}
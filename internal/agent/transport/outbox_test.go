@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/grpc"
+)
+
+// TestOutboxPersistAndAck 测试 outbox 持久化、重发和确认
+func TestOutboxPersistAndAck(t *testing.T) {
+	dir := t.TempDir()
+	o, err := NewOutbox(dir, 1024*1024, time.Hour, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+
+	seq1, err := o.Put(&grpc.EncodedRecord{DataType: 8000, Data: []byte("a")})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	seq2, _ := o.Put(&grpc.EncodedRecord{DataType: 6001, Data: []byte("b")})
+	if seq2 <= seq1 {
+		t.Fatalf("sequence not increasing: %d then %d", seq1, seq2)
+	}
+
+	// 已发送且未超时的记录不会重复返回
+	o.MarkSent(seq1)
+	due := o.Due(time.Minute, 10)
+	if len(due) != 1 || due[0].Seq != seq2 {
+		t.Fatalf("expected only seq %d due, got %v", seq2, due)
+	}
+
+	// 重新打开后未确认的记录仍在，且新序列号不会与旧记录重复
+	o.Ack([]uint64{seq1})
+	reopened, err := NewOutbox(dir, 1024*1024, time.Hour, zap.NewNop())
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if reopened.Len() != 1 {
+		t.Fatalf("expected 1 pending record after reopen, got %d", reopened.Len())
+	}
+	due = reopened.Due(time.Minute, 10)
+	if len(due) != 1 || due[0].Seq != seq2 || string(due[0].Data) != "b" {
+		t.Fatalf("unexpected record after reopen: %v", due)
+	}
+	seq3, _ := reopened.Put(&grpc.EncodedRecord{DataType: 8003})
+	if seq3 <= seq2 {
+		t.Fatalf("sequence reused after reopen: %d <= %d", seq3, seq2)
+	}
+}
+
+// TestOutboxSizeLimit 测试超出空间限制时丢弃最旧的记录
+func TestOutboxSizeLimit(t *testing.T) {
+	o, err := NewOutbox(t.TempDir(), 64, time.Hour, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+
+	first, _ := o.Put(&grpc.EncodedRecord{DataType: 6001, Data: make([]byte, 40)})
+	o.Put(&grpc.EncodedRecord{DataType: 6001, Data: make([]byte, 40)})
+
+	if o.Len() != 1 {
+		t.Fatalf("expected oldest record dropped, got %d records", o.Len())
+	}
+	for _, r := range o.Due(0, 10) {
+		if r.Seq == first {
+			t.Fatal("oldest record should have been dropped")
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,6 +20,13 @@ import (
 	"github.com/imkerbos/mxsec-platform/internal/agent/connection"
 )
 
+const (
+	// outboxResendAfter 是事件类记录发送后等待 Server 确认的时间，超时后重发
+	outboxResendAfter = 60 * time.Second
+	// outboxBatchSize 是单个 PackagedData 中重发的最大记录数
+	outboxBatchSize = 100
+)
+
 // Manager 是传输管理器
 type Manager struct {
 	cfg            *config.Config
@@ -26,13 +34,20 @@ type Manager struct {
 	connMgr        *connection.Manager
 	agentID        string
 	sendBuffer     chan *grpc.PackagedData
+	controlBuffer  chan *grpc.PackagedData                          // 控制类数据（心跳），优先发送且不受背压暂停影响
 	pluginConfigCh chan []*grpc.Config                              // 插件配置通道
 	agentUpdateCh  chan *grpc.AgentUpdate                           // Agent 更新通道
+	diagnosticsCh  chan *grpc.DiagnosticsRequest                    // 诊断信息收集通道
 	taskCh         chan *grpc.Task                                  // 任务通道（兼容旧代码）
 	taskChannels   map[string]chan *grpc.Task                       // 按插件名称分发的任务通道
 	taskChMu       sync.RWMutex                                     // 任务通道锁
 	onConfigUpdate func(*grpc.AgentConfig, *grpc.CertificateBundle) // 配置更新回调 (agentConfig, certBundle)
 	cacheMgr       *cache.Manager                                   // 缓存管理器
+	outbox         *Outbox                                          // 事件类记录发件箱（可靠投递）
+	serverAcks     atomic.Bool                                      // Server 是否支持记录确认（收到过 ack_seqs）
+	batcher        *batcher                                         // 插件记录合并发送
+	compressionOff atomic.Bool                                      // Server 不支持配置的压缩算法（回退为不压缩）
+	throttleUntil  atomic.Int64                                     // Server 要求暂停上报插件数据的截止时间（UnixNano）
 	mu             sync.RWMutex
 	isConnected    bool // 连接状态
 	connectedMu    sync.RWMutex
@@ -47,19 +62,31 @@ func NewManager(cfg *config.Config, logger *zap.Logger, connMgr *connection.Mana
 		return nil, fmt.Errorf("failed to create cache manager: %w", err)
 	}
 
-	return &Manager{
+	// 创建事件类记录发件箱
+	outbox, err := NewOutbox(cfg.GetWorkDir()+"/outbox", 50*1024*1024, 7*24*time.Hour, logger) // 50MB, 7天
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox: %w", err)
+	}
+
+	m := &Manager{
 		cfg:            cfg,
 		logger:         logger,
 		connMgr:        connMgr,
 		agentID:        agentID,
 		sendBuffer:     make(chan *grpc.PackagedData, 2048),
+		controlBuffer:  make(chan *grpc.PackagedData, 16),
 		pluginConfigCh: make(chan []*grpc.Config, 10),
 		agentUpdateCh:  make(chan *grpc.AgentUpdate, 10),
+		diagnosticsCh:  make(chan *grpc.DiagnosticsRequest, 1),
 		taskCh:         make(chan *grpc.Task, 100),
 		taskChannels:   make(map[string]chan *grpc.Task),
 		cacheMgr:       cacheMgr,
+		outbox:         outbox,
 		isConnected:    false,
-	}, nil
+	}
+	m.batcher = newBatcher(cfg.Local.Transport.BatchInterval, cfg.Local.Transport.BatchMaxRecords,
+		cfg.Local.Transport.BatchMaxBytes, m.enqueueBatch)
+	return m, nil
 }
 
 // SetConfigUpdateCallback 设置配置更新回调
@@ -88,6 +115,9 @@ func StartupWithManager(ctx context.Context, wg *sync.WaitGroup, mgr *Manager) {
 	// 启动缓存重试循环
 	go mgr.retryCachedData(ctx)
 
+	// 启动 outbox 重发循环（未确认的事件类记录）
+	go mgr.retryOutbox(ctx)
+
 	mgr.logger.Info("transport module starting, attempting to connect...")
 
 	// 指数退避重试配置
@@ -132,9 +162,11 @@ func StartupWithManager(ctx context.Context, wg *sync.WaitGroup, mgr *Manager) {
 			// 创建 gRPC 客户端
 			mgr.logger.Debug("creating gRPC Transfer client")
 			client := grpc.NewTransferClient(conn)
-			stream, err := client.Transfer(ctx)
+			stream, err := client.Transfer(ctx, mgr.streamCallOptions()...)
 			if err != nil {
 				mgr.setConnected(false)
+				// 接入点可用但无法建立数据流，切换到其他接入点
+				mgr.connMgr.ReportFailure()
 				retryCount++
 				mgr.logger.Error("failed to create stream",
 					zap.Error(err),
@@ -208,30 +240,49 @@ func (m *Manager) sendData(ctx context.Context, wg *sync.WaitGroup, stream grpc.
 		case <-ctx.Done():
 			m.logger.Debug("sendData goroutine stopping (context canceled)")
 			return
+		case data := <-m.controlBuffer:
+			if !m.sendPackaged(stream, data, sendTimeout) {
+				return
+			}
 		case data := <-m.sendBuffer:
-			m.logger.Debug("sending data to server",
-				zap.String("agent_id", data.AgentId),
-				zap.String("hostname", data.Hostname),
-				zap.Int("record_count", len(data.Records)),
-			)
-			if err := m.sendWithTimeout(stream, data, sendTimeout); err != nil {
-				m.logger.Error("failed to send data, dropping stale buffer data",
-					zap.Error(err),
-					zap.String("error_type", fmt.Sprintf("%T", err)),
-					zap.Int("record_count", len(data.Records)),
-				)
-				// 发送失败，丢弃 buffer 中的旧数据（状态快照类数据重连后会发最新的）
-				m.drainSendBuffer()
+			// 背压暂停期间仍发送控制类数据，避免心跳排在被暂停的插件数据之后
+			sendControl := func(data *grpc.PackagedData) bool {
+				return m.sendPackaged(stream, data, sendTimeout)
+			}
+			if !isControlData(data) && !m.waitThrottle(ctx, sendControl) {
+				return
+			}
+			if !m.sendPackaged(stream, data, sendTimeout) {
 				return
 			}
-			m.logger.Debug("data sent successfully",
-				zap.Int("record_count", len(data.Records)),
-				zap.String("agent_id", data.AgentId),
-			)
 		}
 	}
 }
 
+// sendPackaged 发送一个数据包，失败时丢弃缓冲区中的旧数据并返回 false（由调用方结束发送循环等待重连）
+func (m *Manager) sendPackaged(stream grpc.Transfer_TransferClient, data *grpc.PackagedData, timeout time.Duration) bool {
+	m.logger.Debug("sending data to server",
+		zap.String("agent_id", data.AgentId),
+		zap.String("hostname", data.Hostname),
+		zap.Int("record_count", len(data.Records)),
+	)
+	if err := m.sendWithTimeout(stream, data, timeout); err != nil {
+		m.logger.Error("failed to send data, dropping stale buffer data",
+			zap.Error(err),
+			zap.String("error_type", fmt.Sprintf("%T", err)),
+			zap.Int("record_count", len(data.Records)),
+		)
+		// 发送失败，丢弃 buffer 中的旧数据（状态快照类数据重连后会发最新的）
+		m.drainSendBuffer()
+		return false
+	}
+	m.logger.Debug("data sent successfully",
+		zap.Int("record_count", len(data.Records)),
+		zap.String("agent_id", data.AgentId),
+	)
+	return true
+}
+
 // drainSendBuffer 清空 sendBuffer 中的剩余数据（状态快照类数据无需缓存，重连后会发最新的）
 func (m *Manager) drainSendBuffer() {
 	drained := 0
@@ -239,6 +290,8 @@ func (m *Manager) drainSendBuffer() {
 		select {
 		case <-m.sendBuffer:
 			drained++
+		case <-m.controlBuffer:
+			drained++
 		default:
 			if drained > 0 {
 				m.logger.Debug("drained stale data from send buffer", zap.Int("count", drained))
@@ -263,6 +316,7 @@ func (m *Manager) receiveCommands(ctx context.Context, wg *sync.WaitGroup, strea
 			m.logger.Debug("waiting to receive command from server...")
 			cmd, err := stream.Recv()
 			if err != nil {
+				m.checkCompressionRejected(err)
 				if err != context.Canceled {
 					m.logger.Error("failed to receive command",
 						zap.Error(err),
@@ -275,12 +329,24 @@ func (m *Manager) receiveCommands(ctx context.Context, wg *sync.WaitGroup, strea
 			}
 
 			m.logger.Debug("received command from server",
+				zap.Int("ack_count", len(cmd.AckSeqs)),
 				zap.Int("task_count", len(cmd.Tasks)),
 				zap.Int("config_count", len(cmd.Configs)),
 				zap.Bool("has_agent_config", cmd.AgentConfig != nil),
 				zap.Bool("has_certificate_bundle", cmd.CertificateBundle != nil),
 			)
 
+			// 处理事件类记录确认
+			if len(cmd.AckSeqs) > 0 {
+				m.serverAcks.Store(true)
+				m.outbox.Ack(cmd.AckSeqs)
+			}
+
+			// 处理 Server 背压通知（入库队列饱和）
+			if cmd.ThrottleMs > 0 {
+				m.throttle(time.Duration(cmd.ThrottleMs) * time.Millisecond)
+			}
+
 			// 处理 Agent 配置更新
 			if cmd.AgentConfig != nil {
 				m.logger.Info("received agent config update from server",
@@ -343,6 +409,17 @@ func (m *Manager) receiveCommands(ctx context.Context, wg *sync.WaitGroup, strea
 				}
 			}
 
+			// 处理诊断信息收集命令
+			if cmd.Diagnostics != nil {
+				m.logger.Info("received diagnostics command from server",
+					zap.Uint32("max_log_bytes", cmd.Diagnostics.MaxLogBytes))
+				select {
+				case m.diagnosticsCh <- cmd.Diagnostics:
+				default:
+					m.logger.Warn("diagnostics channel full, dropping diagnostics command")
+				}
+			}
+
 			// 处理 Agent 重启命令
 			if cmd.AgentRestart {
 				m.logger.Info("received agent restart command from server")
@@ -405,14 +482,14 @@ func (m *Manager) SendHeartbeat(data *grpc.PackagedData) error {
 		return nil
 	}
 
-	// 连接已建立，尝试发送到缓冲区
+	// 连接已建立，放入控制类数据缓冲区（优先于插件数据发送）
 	select {
-	case m.sendBuffer <- data:
+	case m.controlBuffer <- data:
 		return nil
 	default:
 		// 缓冲区满，丢弃最旧的一条，保留最新心跳（心跳时效性强，新的比旧的有价值）
 		select {
-		case <-m.sendBuffer:
+		case <-m.controlBuffer:
 			m.logger.Warn("send buffer full, dropped oldest data to make room for new heartbeat",
 				zap.String("agent_id", data.AgentId),
 			)
@@ -420,7 +497,7 @@ func (m *Manager) SendHeartbeat(data *grpc.PackagedData) error {
 		}
 		// 再次尝试放入
 		select {
-		case m.sendBuffer <- data:
+		case m.controlBuffer <- data:
 			return nil
 		default:
 			m.logger.Warn("send buffer still full after drop, discarding heartbeat",
@@ -441,6 +518,39 @@ func (m *Manager) GetAgentUpdateChannel() <-chan *grpc.AgentUpdate {
 	return m.agentUpdateCh
 }
 
+// GetDiagnosticsChannel 获取诊断信息收集通道
+func (m *Manager) GetDiagnosticsChannel() <-chan *grpc.DiagnosticsRequest {
+	return m.diagnosticsCh
+}
+
+// Stats 是传输模块的缓存和缓冲区统计（用于诊断信息）
+type Stats struct {
+	Connected       bool  `json:"connected"`
+	SendBufferLen   int   `json:"send_buffer_len"`
+	SendBufferCap   int   `json:"send_buffer_cap"`
+	CacheCount      int   `json:"cache_count"`
+	CacheSizeBytes  int64 `json:"cache_size_bytes"`
+	OutboxLen       int   `json:"outbox_len"`
+	ServerAcks      bool  `json:"server_acks"`
+	CompressionOff  bool  `json:"compression_off"`
+	ThrottleUntil   int64 `json:"throttle_until_unix_nano,omitempty"`
+}
+
+// GetStats 获取传输模块统计
+func (m *Manager) GetStats() Stats {
+	return Stats{
+		Connected:       m.IsConnected(),
+		SendBufferLen:   len(m.sendBuffer),
+		SendBufferCap:   cap(m.sendBuffer),
+		CacheCount:      m.cacheMgr.Count(),
+		CacheSizeBytes:  m.cacheMgr.Size(),
+		OutboxLen:       m.outbox.Len(),
+		ServerAcks:      m.serverAcks.Load(),
+		CompressionOff:  m.compressionOff.Load(),
+		ThrottleUntil:   m.throttleUntil.Load(),
+	}
+}
+
 // SendPluginData 发送插件数据到 Server
 func (m *Manager) SendPluginData(pluginName string, record *bridge.Record) error {
 	// 序列化 Record
@@ -449,27 +559,26 @@ func (m *Manager) SendPluginData(pluginName string, record *bridge.Record) error
 		return fmt.Errorf("failed to marshal plugin record: %w", err)
 	}
 
-	// 构建 PackagedData
-	packagedData := &grpc.PackagedData{
-		Records: []*grpc.EncodedRecord{
-			{
-				DataType:  record.DataType,
-				Timestamp: record.Timestamp,
-				Data:      recordData,
-			},
-		},
-		AgentId: m.agentID,
+	encoded := &grpc.EncodedRecord{
+		DataType:  record.DataType,
+		Timestamp: record.Timestamp,
+		Data:      recordData,
 	}
 
-	// 发送到缓冲区
-	select {
-	case m.sendBuffer <- packagedData:
-		return nil
-	default:
-		// 缓冲区满，丢弃（资产数据是状态快照，下次采集会重新上报）
-		m.logger.Warn("send buffer full, dropping plugin data (will re-collect next cycle)", zap.String("plugin", pluginName))
-		return nil
+	// 事件类记录先写入 outbox，确保在发送缓冲区满或连接断开时不丢失
+	var seq uint64
+	if IsEventDataType(record.DataType) {
+		if seq, err = m.outbox.Put(encoded); err != nil {
+			m.logger.Error("failed to persist event record to outbox, sending without acknowledgement",
+				zap.String("plugin", pluginName),
+				zap.Int32("data_type", record.DataType),
+				zap.Error(err))
+		}
 	}
+
+	// 合并到批次中发送（事件类记录在批次进入发送缓冲区后标记为已发送）
+	m.batcher.add(encoded, seq)
+	return nil
 }
 
 // sendCachedData 连接建立后清空旧缓存（心跳和资产数据都是状态快照，旧数据无价值，重放会导致旧版本号覆盖 DB）
@@ -496,9 +605,65 @@ func (m *Manager) sendCachedData(ctx context.Context, stream grpc.Transfer_Trans
 		)
 	}
 
+	// 重发 outbox 中未确认的事件类记录（Server 会按序列号去重）
+	if !m.serverAcks.Load() {
+		return nil
+	}
+	m.outbox.ResetSent()
+	resent := 0
+	for {
+		records := m.outbox.Due(outboxResendAfter, outboxBatchSize)
+		if len(records) == 0 {
+			break
+		}
+		data := &grpc.PackagedData{Records: records, AgentId: m.agentID}
+		if err := m.sendWithTimeout(stream, data, 30*time.Second); err != nil {
+			m.outbox.ResetSent()
+			return fmt.Errorf("failed to resend outbox records: %w", err)
+		}
+		resent += len(records)
+	}
+	if resent > 0 {
+		m.logger.Info("resent unacknowledged event records after reconnect", zap.Int("count", resent))
+	}
+
 	return nil
 }
 
+// retryOutbox 定期重发超时未确认的事件类记录
+func (m *Manager) retryOutbox(ctx context.Context) {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// 旧版本 Server 不会确认记录，此时不重发，避免重复上报
+			if !m.IsConnected() || !m.serverAcks.Load() {
+				continue
+			}
+
+			records := m.outbox.Due(outboxResendAfter, outboxBatchSize)
+			if len(records) == 0 {
+				continue
+			}
+
+			select {
+			case m.sendBuffer <- &grpc.PackagedData{Records: records, AgentId: m.agentID}:
+				m.logger.Info("resending unacknowledged event records", zap.Int("count", len(records)))
+			default:
+				seqs := make([]uint64, 0, len(records))
+				for _, r := range records {
+					seqs = append(seqs, r.Seq)
+				}
+				m.outbox.MarkUnsent(seqs...)
+			}
+		}
+	}
+}
+
 // retryCachedData 定期清理残留缓存（正常情况下 sendCachedData 已清空，这里做兜底）
 func (m *Manager) retryCachedData(ctx context.Context) {
 	ticker := time.NewTicker(60 * time.Second)
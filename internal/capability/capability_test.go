@@ -0,0 +1,65 @@
+package capability
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// TestParse 测试名称大小写不敏感、可省略 CAP_ 前缀，未知名称返回错误
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    []string
+		want    []int
+		wantErr bool
+	}{
+		{"empty", nil, nil, false},
+		{"full name", []string{"CAP_NET_RAW"}, []int{unix.CAP_NET_RAW}, false},
+		{"without prefix", []string{"sys_admin"}, []int{unix.CAP_SYS_ADMIN}, false},
+		{"trim and skip blank", []string{" net_admin ", "", "CAP_KILL"}, []int{unix.CAP_NET_ADMIN, unix.CAP_KILL}, false},
+		{"unknown", []string{"CAP_NET_RAW", "CAP_FLY"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Parse() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseAll 测试 ALL 返回从 0 开始连续的全部 capability
+func TestParseAll(t *testing.T) {
+	got, err := Parse([]string{"CAP_NET_RAW", "all"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(got) <= unix.CAP_SYS_ADMIN {
+		t.Fatalf("Parse(ALL) returned %d capabilities", len(got))
+	}
+	for i, c := range got {
+		if c != i {
+			t.Fatalf("Parse(ALL)[%d] = %d", i, c)
+		}
+	}
+}
+
+// TestValidate 测试校验结果与解析一致
+func TestValidate(t *testing.T) {
+	if err := Validate([]string{"net_raw", "ALL"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := Validate([]string{"NOT_A_CAP"}); err == nil {
+		t.Error("Validate() should reject unknown capability")
+	}
+}
@@ -0,0 +1,26 @@
+// Package compress 注册 Agent 与 AgentCenter 之间 Transfer 通道使用的 gRPC 压缩算法（snappy、zstd）
+// Agent 和 AgentCenter 均需导入本包，导入即注册
+package compress
+
+import (
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	// Zstd 压缩率高，适合资产、事件等大批量数据（默认）
+	Zstd = "zstd"
+	// Snappy 压缩速度快、CPU 开销低
+	Snappy = "snappy"
+	// None 不压缩
+	None = "none"
+)
+
+func init() {
+	encoding.RegisterCompressor(newZstdCompressor())
+	encoding.RegisterCompressor(newSnappyCompressor())
+}
+
+// Supported 返回压缩算法是否已注册
+func Supported(name string) bool {
+	return encoding.GetCompressor(name) != nil
+}
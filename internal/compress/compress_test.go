@@ -0,0 +1,47 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// TestCompressorsRoundTrip 测试压缩算法的压缩/解压往返以及 writer/reader 复用
+func TestCompressorsRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"pid":"1234","cmdline":"/usr/sbin/sshd -D"}`), 200)
+
+	for _, name := range []string{Zstd, Snappy} {
+		c := encoding.GetCompressor(name)
+		if c == nil {
+			t.Fatalf("%s compressor not registered", name)
+		}
+		for i := 0; i < 3; i++ {
+			var buf bytes.Buffer
+			w, err := c.Compress(&buf)
+			if err != nil {
+				t.Fatalf("%s Compress: %v", name, err)
+			}
+			w.Write(payload)
+			if err := w.Close(); err != nil {
+				t.Fatalf("%s Close: %v", name, err)
+			}
+			if buf.Len() >= len(payload) {
+				t.Fatalf("%s did not compress: %d >= %d", name, buf.Len(), len(payload))
+			}
+
+			r, err := c.Decompress(&buf)
+			if err != nil {
+				t.Fatalf("%s Decompress: %v", name, err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("%s ReadAll: %v", name, err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Fatalf("%s round trip mismatch", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package compress
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+// snappyCompressor 实现 encoding.Compressor（snappy 帧格式），writer 和 reader 通过 sync.Pool 复用
+type snappyCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func newSnappyCompressor() *snappyCompressor {
+	return &snappyCompressor{}
+}
+
+// Name 返回压缩算法名称（对应 grpc-encoding 头）
+func (c *snappyCompressor) Name() string {
+	return Snappy
+}
+
+// Compress 返回写入 w 的压缩 writer，Close 时刷新缓冲区
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	sw, ok := c.writers.Get().(*snappy.Writer)
+	if ok {
+		sw.Reset(w)
+	} else {
+		sw = snappy.NewBufferedWriter(w)
+	}
+	return &snappyWriter{Writer: sw, pool: &c.writers}, nil
+}
+
+// Decompress 返回从 r 读取的解压 reader
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	sr, ok := c.readers.Get().(*snappy.Reader)
+	if ok {
+		sr.Reset(r)
+	} else {
+		sr = snappy.NewReader(r)
+	}
+	return &snappyReader{Reader: sr, pool: &c.readers}, nil
+}
+
+// snappyWriter 在 Close 后将 writer 归还到 pool
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+func (w *snappyWriter) Close() error {
+	err := w.Writer.Close()
+	w.pool.Put(w.Writer)
+	return err
+}
+
+// snappyReader 读取到 EOF 后将 reader 归还到 pool
+type snappyReader struct {
+	*snappy.Reader
+	pool *sync.Pool
+}
+
+func (r *snappyReader) Read(p []byte) (int, error) {
+	if r.Reader == nil {
+		return 0, io.EOF
+	}
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Reader)
+		r.Reader = nil
+	}
+	return n, err
+}
@@ -0,0 +1,152 @@
+// Package offline 定义离线基线检查结果包的格式与签名
+// 无法连接 AgentCenter 的主机使用 mxsec-agent --scan 在本地执行基线检查，生成签名结果包，
+// 由管理员拷贝后通过 Manager 的导入接口写入检查结果。Agent 和 Manager 均使用本包
+//
+// 每台主机使用独立的签名密钥：主机密钥由 Manager 的主密钥和 Agent ID 派生（HostKey），
+// 结果包只能以自身 Agent ID 的密钥通过校验，持有某台主机密钥无法伪造其他主机的结果
+package offline
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// Format 是结果包格式版本
+	Format = "mxsec-offline-baseline/v1"
+	// Algorithm 是签名算法
+	Algorithm = "hmac-sha256"
+	// MinKeyLength 是签名密钥的最小长度
+	MinKeyLength = 16
+	// hostKeyContext 是派生主机密钥的上下文前缀
+	hostKeyContext = "mxsec-offline-host-key/v1:"
+)
+
+// ErrInvalidSignature 表示结果包签名校验失败
+var ErrInvalidSignature = errors.New("结果包签名校验失败")
+
+// Host 是执行检查的主机信息
+type Host struct {
+	AgentID      string   `json:"agent_id"`
+	Hostname     string   `json:"hostname"`
+	OSFamily     string   `json:"os_family"`
+	OSVersion    string   `json:"os_version"`
+	Kernel       string   `json:"kernel,omitempty"`
+	Arch         string   `json:"arch,omitempty"`
+	IPv4         []string `json:"ipv4,omitempty"`
+	AgentVersion string   `json:"agent_version,omitempty"`
+}
+
+// PolicyRef 是执行的策略
+type PolicyRef struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Result 是单条检查结果（字段与基线插件上报的检查结果一致）
+type Result struct {
+	RuleID        string    `json:"rule_id"`
+	PolicyID      string    `json:"policy_id"`
+	Status        string    `json:"status"`
+	Severity      string    `json:"severity"`
+	Category      string    `json:"category"`
+	Title         string    `json:"title"`
+	Actual        string    `json:"actual"`
+	Expected      string    `json:"expected"`
+	FixSuggestion string    `json:"fix_suggestion"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// Payload 是结果包的签名内容
+type Payload struct {
+	Format     string      `json:"format"`
+	BundleID   string      `json:"bundle_id"` // 随机 ID，Manager 据此拒绝重复导入
+	Host       Host        `json:"host"`
+	Policies   []PolicyRef `json:"policies"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt time.Time   `json:"finished_at"`
+	Results    []Result    `json:"results"`
+}
+
+// Bundle 是结果包文件（JSON）
+// Signature 是对 Payload 紧凑 JSON 字节的 HMAC-SHA256（校验时先去除空白，文件被重新缩进不影响校验）
+type Bundle struct {
+	Payload   json.RawMessage `json:"payload"`
+	Algorithm string          `json:"algorithm"`
+	Signature string          `json:"signature"`
+}
+
+// Sign 序列化并签名结果包，key 为本机 Agent ID 的主机密钥（HostKey）
+func Sign(payload *Payload, key []byte) ([]byte, error) {
+	if len(key) < MinKeyLength {
+		return nil, fmt.Errorf("签名密钥长度不能小于 %d 字节", MinKeyLength)
+	}
+	payload.Format = Format
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化结果失败: %w", err)
+	}
+	bundle := Bundle{
+		Payload:   raw,
+		Algorithm: Algorithm,
+		Signature: hex.EncodeToString(mac(raw, key)),
+	}
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+// HostKey 由主密钥派生指定 Agent ID 的签名密钥（十六进制字符串，即离线主机 --key 文件的内容）
+func HostKey(masterKey []byte, agentID string) string {
+	return hex.EncodeToString(mac([]byte(hostKeyContext+agentID), masterKey))
+}
+
+// Verify 校验结果包签名并解析内容
+// hostKey 返回结果包声明的 Agent ID 对应的签名密钥，签名与该密钥不符时返回 ErrInvalidSignature
+func Verify(data []byte, hostKey func(agentID string) []byte) (*Payload, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("结果包格式错误: %w", err)
+	}
+	if bundle.Algorithm != Algorithm {
+		return nil, fmt.Errorf("不支持的签名算法: %q", bundle.Algorithm)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, bundle.Payload); err != nil {
+		return nil, fmt.Errorf("结果包内容格式错误: %w", err)
+	}
+
+	// 先解析出 Agent ID 以确定签名密钥，签名校验通过前不使用其他内容
+	var payload Payload
+	if err := json.Unmarshal(bundle.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("结果包内容格式错误: %w", err)
+	}
+	if payload.Host.AgentID == "" {
+		return nil, fmt.Errorf("结果包缺少 agent_id")
+	}
+	signature, err := hex.DecodeString(bundle.Signature)
+	if err != nil || !hmac.Equal(signature, mac(compact.Bytes(), hostKey(payload.Host.AgentID))) {
+		return nil, ErrInvalidSignature
+	}
+
+	if payload.Format != Format {
+		return nil, fmt.Errorf("不支持的结果包格式: %q", payload.Format)
+	}
+	if payload.BundleID == "" {
+		return nil, fmt.Errorf("结果包缺少 bundle_id")
+	}
+	return &payload, nil
+}
+
+// mac 计算 HMAC-SHA256
+func mac(data, key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,89 @@
+package offline
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSignVerify 测试结果包签名往返以及篡改、密钥错误时校验失败
+func TestSignVerify(t *testing.T) {
+	master := []byte("0123456789abcdef0123")
+	hostKey := func(agentID string) []byte { return []byte(HostKey(master, agentID)) }
+	key := hostKey("agent-1")
+	payload := &Payload{
+		BundleID: "b1",
+		Host:     Host{AgentID: "agent-1", Hostname: "db-01", OSFamily: "rocky", OSVersion: "9.3"},
+		Policies: []PolicyRef{{ID: "LINUX_SSH_BASELINE", Name: "SSH"}},
+		Results: []Result{
+			{RuleID: "LINUX_SSH_001", PolicyID: "LINUX_SSH_BASELINE", Status: "fail", CheckedAt: time.Now().UTC()},
+		},
+	}
+
+	data, err := Sign(payload, key)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	got, err := Verify(data, hostKey)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got.Host.AgentID != "agent-1" || len(got.Results) != 1 || got.Results[0].Status != "fail" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	otherMaster := func(agentID string) []byte { return []byte(HostKey([]byte("another-key-0123456789"), agentID)) }
+	if _, err := Verify(data, otherMaster); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("wrong key: got %v, want ErrInvalidSignature", err)
+	}
+
+	tampered := bytes.Replace(data, []byte(`"fail"`), []byte(`"pass"`), 1)
+	if bytes.Equal(tampered, data) {
+		t.Fatal("test payload not tampered")
+	}
+	if _, err := Verify(tampered, hostKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered: got %v, want ErrInvalidSignature", err)
+	}
+
+	if _, err := Sign(payload, []byte("short")); err == nil {
+		t.Fatal("Sign with short key should fail")
+	}
+}
+
+// TestHostKeyBinding 测试主机密钥与 Agent ID 绑定：持有一台主机的密钥无法伪造其他主机的结果
+func TestHostKeyBinding(t *testing.T) {
+	master := []byte("0123456789abcdef0123")
+	hostKey := func(agentID string) []byte { return []byte(HostKey(master, agentID)) }
+
+	if HostKey(master, "agent-1") == HostKey(master, "agent-2") {
+		t.Fatal("host keys of different agents should differ")
+	}
+	if HostKey(master, "agent-1") != HostKey(master, "agent-1") {
+		t.Fatal("host key should be deterministic")
+	}
+
+	// agent-1 使用自己的密钥签名声明为 agent-2 的结果包
+	forged := &Payload{
+		BundleID: "b2",
+		Host:     Host{AgentID: "agent-2", Hostname: "web-01"},
+		Results:  []Result{{RuleID: "LINUX_SSH_001", Status: "pass"}},
+	}
+	data, err := Sign(forged, hostKey("agent-1"))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := Verify(data, hostKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("forged bundle: got %v, want ErrInvalidSignature", err)
+	}
+
+	// 使用主密钥直接签名也无法通过校验
+	data, err = Sign(forged, master)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := Verify(data, hostKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("master-key bundle: got %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,354 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"gorm.io/gorm"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+const (
+	// leaderLeaseName 是调度器 leader 租约名称
+	leaderLeaseName = "agentcenter-scheduler"
+	// registryTimeout 是单次注册表操作的超时时间
+	registryTimeout = 5 * time.Second
+	// forwardTimeout 是转发命令的超时时间
+	forwardTimeout = 5 * time.Second
+	// secretMetadataKey 是实例间 RPC 携带共享密钥的 metadata 键
+	secretMetadataKey = "x-mxsec-cluster-secret"
+)
+
+// ErrAgentNotConnected 表示 Agent 没有连接到集群中的任何实例
+var ErrAgentNotConnected = errors.New("agent 未连接")
+
+// leaderJob 是只在 leader 实例上运行的后台任务
+type leaderJob struct {
+	name string
+	fn   func(ctx context.Context)
+}
+
+// Node 是集群中的本实例
+// 未启用集群时 Node 始终是 leader，所有注册表操作均为空操作，行为与单实例部署一致
+type Node struct {
+	cfg      config.ClusterConfig
+	registry Registry
+	logger   *zap.Logger
+
+	leader      atomic.Bool
+	jobsMu      sync.Mutex
+	jobs        []leaderJob
+	jobsCtx     context.Context
+	cancelJobs  context.CancelFunc
+	leaderGroup sync.WaitGroup
+
+	clientsMu sync.Mutex
+	clients   map[string]*grpc.ClientConn // 按 RPC 地址缓存到其他实例的连接
+
+	rpcServer   *grpc.Server
+	serverCreds credentials.TransportCredentials // 内部 RPC 服务端 TLS 凭证
+	clientCreds credentials.TransportCredentials // 连接其他实例的 TLS 凭证
+
+	started atomic.Bool
+	stopped chan struct{} // Start 完成注销后关闭
+}
+
+// NewNode 根据配置创建本实例节点
+func NewNode(cfg config.ClusterConfig, db *gorm.DB, logger *zap.Logger) (*Node, error) {
+	n := &Node{
+		cfg:     cfg,
+		logger:  logger,
+		clients: make(map[string]*grpc.ClientConn),
+		stopped: make(chan struct{}),
+	}
+	if !cfg.Enabled {
+		return n, nil
+	}
+
+	registry, err := NewRegistry(cfg, db, logger)
+	if err != nil {
+		return nil, err
+	}
+	n.registry = registry
+
+	n.serverCreds, n.clientCreds, err = transportCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Secret == "" && cfg.TLS.CACert == "" {
+		return nil, fmt.Errorf("集群内部 RPC 未配置认证，请配置 cluster.secret 或 cluster.tls.ca_cert")
+	}
+	return n, nil
+}
+
+// Enabled 返回是否启用了集群模式
+func (n *Node) Enabled() bool {
+	return n != nil && n.registry != nil
+}
+
+// ID 返回本实例 ID
+func (n *Node) ID() string {
+	return n.cfg.InstanceID
+}
+
+// IsLeader 返回本实例当前是否为 leader
+func (n *Node) IsLeader() bool {
+	return n.leader.Load()
+}
+
+// RunAsLeader 注册只在 leader 实例上运行的后台任务
+// 本实例成为 leader 时启动，失去 leader 身份时取消 fn 的 ctx；fn 应在 ctx 取消后返回
+func (n *Node) RunAsLeader(name string, fn func(ctx context.Context)) {
+	n.jobsMu.Lock()
+	defer n.jobsMu.Unlock()
+
+	job := leaderJob{name: name, fn: fn}
+	n.jobs = append(n.jobs, job)
+	if n.jobsCtx != nil {
+		n.startJob(n.jobsCtx, job)
+	}
+}
+
+// Start 启动实例心跳和 leader 选举，阻塞直到 ctx 取消
+func (n *Node) Start(ctx context.Context) {
+	n.started.Store(true)
+	defer close(n.stopped)
+
+	if !n.Enabled() {
+		n.becomeLeader(ctx)
+		<-ctx.Done()
+		n.stepDown()
+		return
+	}
+
+	n.logger.Info("集群模式已启用",
+		zap.String("instance_id", n.ID()),
+		zap.String("advertise_address", n.cfg.AdvertiseAddress),
+		zap.String("registry", n.cfg.Registry))
+
+	// 清理本实例上次运行遗留的 Agent 归属（进程崩溃未正常注销）
+	n.withRegistry(func(rctx context.Context) error {
+		return n.registry.Deregister(rctx, n.ID())
+	}, "清理实例遗留归属失败")
+
+	ticker := time.NewTicker(n.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	n.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			n.stepDown()
+			// 使用独立的 context：ctx 已取消，仍需完成注销
+			n.withRegistry(func(rctx context.Context) error {
+				if err := n.registry.ReleaseLease(rctx, leaderLeaseName, n.ID()); err != nil {
+					return err
+				}
+				return n.registry.Deregister(rctx, n.ID())
+			}, "注销集群实例失败")
+			n.logger.Info("已退出集群", zap.String("instance_id", n.ID()))
+			return
+		case <-ticker.C:
+			n.tick(ctx)
+		}
+	}
+}
+
+// tick 刷新实例心跳并获取或续约 leader 租约
+func (n *Node) tick(ctx context.Context) {
+	n.withRegistry(func(rctx context.Context) error {
+		return n.registry.Heartbeat(rctx, Instance{ID: n.ID(), RPCAddress: n.cfg.AdvertiseAddress})
+	}, "刷新集群实例心跳失败")
+
+	rctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	acquired, err := n.registry.AcquireLease(rctx, leaderLeaseName, n.ID(), n.cfg.InstanceTTL)
+	cancel()
+	if err != nil {
+		// 无法确认租约时主动让出，避免与新 leader 同时调度
+		n.logger.Warn("续约 leader 租约失败", zap.Error(err))
+		acquired = false
+	}
+
+	if acquired {
+		n.becomeLeader(ctx)
+	} else {
+		n.stepDown()
+	}
+}
+
+// becomeLeader 成为 leader 并启动所有 leader 任务
+func (n *Node) becomeLeader(ctx context.Context) {
+	if !n.leader.CompareAndSwap(false, true) {
+		return
+	}
+	n.logger.Info("本实例成为调度 leader", zap.String("instance_id", n.ID()))
+
+	n.jobsMu.Lock()
+	defer n.jobsMu.Unlock()
+	n.jobsCtx, n.cancelJobs = context.WithCancel(ctx)
+	for _, job := range n.jobs {
+		n.startJob(n.jobsCtx, job)
+	}
+}
+
+// stepDown 放弃 leader 身份并等待所有 leader 任务退出
+func (n *Node) stepDown() {
+	if !n.leader.CompareAndSwap(true, false) {
+		return
+	}
+	n.logger.Info("本实例不再是调度 leader", zap.String("instance_id", n.ID()))
+
+	n.jobsMu.Lock()
+	n.cancelJobs()
+	n.jobsCtx, n.cancelJobs = nil, nil
+	n.jobsMu.Unlock()
+	n.leaderGroup.Wait()
+}
+
+// startJob 启动单个 leader 任务（调用方持有 jobsMu）
+func (n *Node) startJob(ctx context.Context, job leaderJob) {
+	n.leaderGroup.Add(1)
+	go func() {
+		defer n.leaderGroup.Done()
+		n.logger.Debug("启动 leader 任务", zap.String("job", job.name))
+		job.fn(ctx)
+	}()
+}
+
+// BindAgent 记录 Agent 连接到本实例
+func (n *Node) BindAgent(agentID string) {
+	if !n.Enabled() {
+		return
+	}
+	n.withRegistry(func(ctx context.Context) error {
+		return n.registry.BindAgent(ctx, agentID, n.ID())
+	}, "记录 Agent 连接归属失败", zap.String("agent_id", agentID))
+}
+
+// UnbindAgent 删除 Agent 到本实例的归属
+func (n *Node) UnbindAgent(agentID string) {
+	if !n.Enabled() {
+		return
+	}
+	n.withRegistry(func(ctx context.Context) error {
+		return n.registry.UnbindAgent(ctx, agentID, n.ID())
+	}, "删除 Agent 连接归属失败", zap.String("agent_id", agentID))
+}
+
+// ConnectedElsewhere 返回 Agent 当前是否连接在其他存活实例上（用于判断断连是否为迁移）
+func (n *Node) ConnectedElsewhere(agentID string) bool {
+	if !n.Enabled() {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	owner, err := n.registry.AgentOwner(ctx, agentID)
+	if err != nil || owner == "" || owner == n.ID() {
+		return false
+	}
+	inst, err := n.registry.Instance(ctx, owner)
+	return err == nil && inst != nil
+}
+
+// Forward 将命令转发给持有 Agent 连接的实例
+func (n *Node) Forward(agentID string, cmd *grpcProto.Command) error {
+	if !n.Enabled() {
+		return fmt.Errorf("%w: %s", ErrAgentNotConnected, agentID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), forwardTimeout)
+	defer cancel()
+
+	owner, err := n.registry.AgentOwner(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("查询 Agent 连接归属失败: %w", err)
+	}
+	if owner == "" || owner == n.ID() {
+		return fmt.Errorf("%w: %s", ErrAgentNotConnected, agentID)
+	}
+	inst, err := n.registry.Instance(ctx, owner)
+	if err != nil {
+		return fmt.Errorf("查询集群实例失败: %w", err)
+	}
+	if inst == nil {
+		// 归属实例已下线，Agent 尚未重连到其他实例
+		return fmt.Errorf("%w: %s", ErrAgentNotConnected, agentID)
+	}
+
+	conn, err := n.client(inst.RPCAddress)
+	if err != nil {
+		return fmt.Errorf("连接集群实例 %s 失败: %w", inst.ID, err)
+	}
+	if n.cfg.Secret != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, secretMetadataKey, n.cfg.Secret)
+	}
+	resp, err := grpcProto.NewClusterClient(conn).ForwardCommand(ctx, &grpcProto.ForwardCommandRequest{
+		AgentId: agentID,
+		Command: cmd,
+	})
+	if err != nil {
+		return fmt.Errorf("转发命令到实例 %s 失败: %w", inst.ID, err)
+	}
+	if !resp.Delivered {
+		return fmt.Errorf("实例 %s 下发命令失败: %s", inst.ID, resp.Error)
+	}
+	return nil
+}
+
+// client 返回到指定地址的 RPC 连接（连接复用）
+func (n *Node) client(address string) (*grpc.ClientConn, error) {
+	n.clientsMu.Lock()
+	defer n.clientsMu.Unlock()
+
+	if conn, ok := n.clients[address]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(n.clientCreds))
+	if err != nil {
+		return nil, err
+	}
+	n.clients[address] = conn
+	return conn, nil
+}
+
+// Close 等待实例完成注销，停止内部 RPC 服务并关闭到其他实例的连接
+// 调用前应先取消传给 Start 的 ctx
+func (n *Node) Close() {
+	if n.started.Load() {
+		select {
+		case <-n.stopped:
+		case <-time.After(2 * registryTimeout):
+			n.logger.Warn("等待集群实例注销超时")
+		}
+	}
+	if n.rpcServer != nil {
+		n.rpcServer.GracefulStop()
+	}
+
+	n.clientsMu.Lock()
+	defer n.clientsMu.Unlock()
+	for address, conn := range n.clients {
+		conn.Close()
+		delete(n.clients, address)
+	}
+}
+
+// withRegistry 执行带超时的注册表操作，失败时记录日志
+func (n *Node) withRegistry(fn func(ctx context.Context) error, msg string, fields ...zap.Field) {
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+	if err := fn(ctx); err != nil {
+		n.logger.Warn(msg, append(fields, zap.Error(err))...)
+	}
+}
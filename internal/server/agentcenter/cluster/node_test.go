@@ -0,0 +1,224 @@
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+func TestStandaloneNodeRunsLeaderJobs(t *testing.T) {
+	node, err := NewNode(config.ClusterConfig{InstanceID: "test"}, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewNode failed: %v", err)
+	}
+	if node.Enabled() {
+		t.Fatal("standalone node should not be enabled")
+	}
+
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	node.RunAsLeader("job", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go node.Start(ctx)
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("leader job not started")
+	}
+	if !node.IsLeader() {
+		t.Fatal("standalone node should always be leader")
+	}
+
+	cancel()
+	node.Close()
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("leader job not stopped after Close")
+	}
+	if node.IsLeader() {
+		t.Fatal("node should step down after Start returns")
+	}
+}
+
+func TestStandaloneNodeForward(t *testing.T) {
+	node, _ := NewNode(config.ClusterConfig{}, nil, zap.NewNop())
+	err := node.Forward("agent-1", &grpcProto.Command{})
+	if !errors.Is(err, ErrAgentNotConnected) {
+		t.Fatalf("expected ErrAgentNotConnected, got %v", err)
+	}
+}
+
+// writeTestCerts 生成测试 CA 和由其签发的实例证书（SAN 为 127.0.0.1），返回文件路径
+func writeTestCerts(t *testing.T) (caFile, certFile, keyFile string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cluster-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "agentcenter"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	write := func(name, typ string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: data}), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	return write("ca.pem", "CERTIFICATE", caDER), write("cert.pem", "CERTIFICATE", der), write("key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+// startTestRPC 使用节点的凭证和认证拦截器启动集群 RPC 服务，返回监听地址
+func startTestRPC(t *testing.T, n *Node, delivered chan<- string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(n.serverCreds), grpc.UnaryInterceptor(n.authInterceptor))
+	grpcProto.RegisterClusterServer(server, &rpcService{
+		deliver: func(agentID string, cmd *grpcProto.Command) error {
+			delivered <- agentID
+			return nil
+		},
+		logger: zap.NewNop(),
+	})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func forwardTo(t *testing.T, address string, creds credentials.TransportCredentials, secret string) error {
+	t.Helper()
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if secret != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, secretMetadataKey, secret)
+	}
+	_, err = grpcProto.NewClusterClient(conn).ForwardCommand(ctx, &grpcProto.ForwardCommandRequest{
+		AgentId: "agent-1",
+		Command: &grpcProto.Command{},
+	})
+	return err
+}
+
+// TestClusterRPCAuthentication 测试内部 RPC 的 TLS 和共享密钥认证
+func TestClusterRPCAuthentication(t *testing.T) {
+	caFile, certFile, keyFile := writeTestCerts(t)
+	const secret = "0123456789abcdef"
+
+	tests := []struct {
+		name      string
+		cfg       config.ClusterConfig
+		secret    string
+		plaintext bool
+		noCert    bool
+		wantCode  codes.Code
+	}{
+		{"secret and mtls", config.ClusterConfig{Secret: secret, TLS: config.ClusterTLS{CACert: caFile}}, secret, false, false, codes.OK},
+		{"mtls only", config.ClusterConfig{TLS: config.ClusterTLS{CACert: caFile}}, "", false, false, codes.OK},
+		{"wrong secret", config.ClusterConfig{Secret: secret, TLS: config.ClusterTLS{CACert: caFile}}, "wrong-secret-0000", false, false, codes.Unauthenticated},
+		{"missing secret", config.ClusterConfig{Secret: secret, TLS: config.ClusterTLS{CACert: caFile}}, "", false, false, codes.Unauthenticated},
+		{"plaintext client", config.ClusterConfig{Secret: secret, TLS: config.ClusterTLS{CACert: caFile}}, secret, true, false, codes.Unavailable},
+		{"client without certificate", config.ClusterConfig{Secret: secret, TLS: config.ClusterTLS{CACert: caFile}}, secret, false, true, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.TLS.Cert, tt.cfg.TLS.Key = certFile, keyFile
+			n := &Node{cfg: tt.cfg}
+			var err error
+			n.serverCreds, n.clientCreds, err = transportCredentials(tt.cfg)
+			if err != nil {
+				t.Fatalf("transportCredentials: %v", err)
+			}
+			delivered := make(chan string, 1)
+			address := startTestRPC(t, n, delivered)
+
+			creds := n.clientCreds
+			if tt.plaintext {
+				creds = insecure.NewCredentials()
+			}
+			if tt.noCert {
+				pool := x509.NewCertPool()
+				caPEM, _ := os.ReadFile(caFile)
+				pool.AppendCertsFromPEM(caPEM)
+				creds = credentials.NewTLS(&tls.Config{RootCAs: pool})
+			}
+
+			err = forwardTo(t, address, creds, tt.secret)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("ForwardCommand code = %v, want %v (err: %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK {
+				if id := <-delivered; id != "agent-1" {
+					t.Fatalf("delivered agent %q", id)
+				}
+			} else if len(delivered) != 0 {
+				t.Fatal("command delivered despite failed authentication")
+			}
+		})
+	}
+}
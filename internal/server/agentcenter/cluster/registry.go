@@ -0,0 +1,54 @@
+// Package cluster 提供 AgentCenter 多实例部署支持
+// 包括 Agent 连接归属注册表、实例间命令转发和 leader 选举
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+// Instance 是一个存活的 AgentCenter 实例
+type Instance struct {
+	ID         string
+	RPCAddress string
+}
+
+// Registry 是集群共享注册表，记录存活实例、Agent 连接归属和 leader 租约
+type Registry interface {
+	// Heartbeat 刷新实例心跳（实例不存在时创建）
+	Heartbeat(ctx context.Context, inst Instance) error
+	// Deregister 删除实例及其持有的 Agent 归属（实例退出时调用）
+	Deregister(ctx context.Context, instanceID string) error
+	// Instance 返回存活实例，实例不存在或心跳超时时返回 nil
+	Instance(ctx context.Context, instanceID string) (*Instance, error)
+
+	// BindAgent 记录 Agent 连接到指定实例（覆盖旧归属）
+	BindAgent(ctx context.Context, agentID, instanceID string) error
+	// UnbindAgent 删除 Agent 归属（仅当归属仍为指定实例时）
+	UnbindAgent(ctx context.Context, agentID, instanceID string) error
+	// AgentOwner 返回持有 Agent 连接的实例 ID，没有归属时返回空字符串
+	AgentOwner(ctx context.Context, agentID string) (string, error)
+
+	// AcquireLease 获取或续约租约，返回本次调用后 holder 是否持有租约
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLease 释放租约（仅当持有者为 holder 时）
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// NewRegistry 根据配置创建注册表
+func NewRegistry(cfg config.ClusterConfig, db *gorm.DB, logger *zap.Logger) (Registry, error) {
+	switch cfg.Registry {
+	case "", "db":
+		return NewDBRegistry(db, cfg.InstanceTTL), nil
+	case "redis":
+		return NewRedisRegistry(cfg.Redis, cfg.InstanceTTL, logger)
+	default:
+		return nil, fmt.Errorf("不支持的集群注册表类型: %s", cfg.Registry)
+	}
+}
@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// DBRegistry 是基于数据库的注册表（默认实现，无需额外组件）
+// 各实例需要保持时钟同步，心跳和租约的过期判断使用实例本地时间
+type DBRegistry struct {
+	db          *gorm.DB
+	instanceTTL time.Duration
+}
+
+// NewDBRegistry 创建基于数据库的注册表
+func NewDBRegistry(db *gorm.DB, instanceTTL time.Duration) *DBRegistry {
+	return &DBRegistry{db: db, instanceTTL: instanceTTL}
+}
+
+// Heartbeat 刷新实例心跳
+func (r *DBRegistry) Heartbeat(ctx context.Context, inst Instance) error {
+	record := &model.ClusterInstance{
+		InstanceID:  inst.ID,
+		RPCAddress:  inst.RPCAddress,
+		HeartbeatAt: model.ToLocalTime(time.Now()),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(record).Error
+}
+
+// Deregister 删除实例及其持有的 Agent 归属
+func (r *DBRegistry) Deregister(ctx context.Context, instanceID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("instance_id = ?", instanceID).Delete(&model.AgentRoute{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("instance_id = ?", instanceID).Delete(&model.ClusterInstance{}).Error
+	})
+}
+
+// Instance 返回存活实例
+func (r *DBRegistry) Instance(ctx context.Context, instanceID string) (*Instance, error) {
+	var record model.ClusterInstance
+	err := r.db.WithContext(ctx).
+		Where("instance_id = ? AND heartbeat_at >= ?", instanceID, time.Now().Add(-r.instanceTTL)).
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Instance{ID: record.InstanceID, RPCAddress: record.RPCAddress}, nil
+}
+
+// BindAgent 记录 Agent 连接归属
+func (r *DBRegistry) BindAgent(ctx context.Context, agentID, instanceID string) error {
+	route := &model.AgentRoute{
+		AgentID:     agentID,
+		InstanceID:  instanceID,
+		ConnectedAt: model.ToLocalTime(time.Now()),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(route).Error
+}
+
+// UnbindAgent 删除 Agent 归属（仅当归属仍为指定实例时）
+func (r *DBRegistry) UnbindAgent(ctx context.Context, agentID, instanceID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ? AND instance_id = ?", agentID, instanceID).
+		Delete(&model.AgentRoute{}).Error
+}
+
+// AgentOwner 返回持有 Agent 连接的实例 ID
+func (r *DBRegistry) AgentOwner(ctx context.Context, agentID string) (string, error) {
+	var route model.AgentRoute
+	err := r.db.WithContext(ctx).Where("agent_id = ?", agentID).First(&route).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return route.InstanceID, nil
+}
+
+// AcquireLease 获取或续约租约
+// 先尝试条件更新（本实例持有或已过期），失败再尝试插入；两者都未生效时以当前持有者为准
+func (r *DBRegistry) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	db := r.db.WithContext(ctx)
+	now := time.Now()
+
+	result := db.Model(&model.ClusterLease{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, now).
+		Updates(map[string]interface{}{
+			"holder":     holder,
+			"expires_at": model.ToLocalTime(now.Add(ttl)),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	lease := &model.ClusterLease{
+		Name:      name,
+		Holder:    holder,
+		ExpiresAt: model.ToLocalTime(now.Add(ttl)),
+	}
+	result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(lease)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// MySQL 默认只统计实际变化的行，续约时间与原值相同时 RowsAffected 为 0，需要再确认持有者
+	var current model.ClusterLease
+	if err := db.Where("name = ?", name).First(&current).Error; err != nil {
+		return false, err
+	}
+	return current.Holder == holder && time.Time(current.ExpiresAt).After(now), nil
+}
+
+// ReleaseLease 释放租约
+func (r *DBRegistry) ReleaseLease(ctx context.Context, name, holder string) error {
+	return r.db.WithContext(ctx).
+		Where("name = ? AND holder = ?", name, holder).
+		Delete(&model.ClusterLease{}).Error
+}
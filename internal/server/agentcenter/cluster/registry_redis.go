@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+// Redis 键前缀
+const (
+	redisInstanceKeyPrefix = "mxsec:cluster:instance:"
+	redisAgentKeyPrefix    = "mxsec:cluster:agent:"
+	redisLeaseKeyPrefix    = "mxsec:cluster:lease:"
+)
+
+// compareAndDelete 仅当键值等于期望值时删除
+var compareAndDelete = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// acquireLease 键不存在时设置，持有者为自己时续约
+var acquireLease = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if not holder then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0`)
+
+// RedisRegistry 是基于 Redis 的注册表
+// 实例心跳使用键过期实现，Agent 归属不设过期时间（归属实例下线后通过实例存活判断失效）
+type RedisRegistry struct {
+	client      *redis.Client
+	instanceTTL time.Duration
+}
+
+// NewRedisRegistry 创建基于 Redis 的注册表
+func NewRedisRegistry(cfg config.RedisConfig, instanceTTL time.Duration, logger *zap.Logger) (*RedisRegistry, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+
+	logger.Info("集群注册表使用 Redis", zap.String("addr", cfg.Addr), zap.Int("db", cfg.DB))
+	return &RedisRegistry{client: client, instanceTTL: instanceTTL}, nil
+}
+
+// Heartbeat 刷新实例心跳
+func (r *RedisRegistry) Heartbeat(ctx context.Context, inst Instance) error {
+	return r.client.Set(ctx, redisInstanceKeyPrefix+inst.ID, inst.RPCAddress, r.instanceTTL).Err()
+}
+
+// Deregister 删除实例（Agent 归属随实例下线自动失效）
+func (r *RedisRegistry) Deregister(ctx context.Context, instanceID string) error {
+	return r.client.Del(ctx, redisInstanceKeyPrefix+instanceID).Err()
+}
+
+// Instance 返回存活实例
+func (r *RedisRegistry) Instance(ctx context.Context, instanceID string) (*Instance, error) {
+	addr, err := r.client.Get(ctx, redisInstanceKeyPrefix+instanceID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Instance{ID: instanceID, RPCAddress: addr}, nil
+}
+
+// BindAgent 记录 Agent 连接归属
+func (r *RedisRegistry) BindAgent(ctx context.Context, agentID, instanceID string) error {
+	return r.client.Set(ctx, redisAgentKeyPrefix+agentID, instanceID, 0).Err()
+}
+
+// UnbindAgent 删除 Agent 归属（仅当归属仍为指定实例时）
+func (r *RedisRegistry) UnbindAgent(ctx context.Context, agentID, instanceID string) error {
+	return compareAndDelete.Run(ctx, r.client, []string{redisAgentKeyPrefix + agentID}, instanceID).Err()
+}
+
+// AgentOwner 返回持有 Agent 连接的实例 ID
+func (r *RedisRegistry) AgentOwner(ctx context.Context, agentID string) (string, error) {
+	owner, err := r.client.Get(ctx, redisAgentKeyPrefix+agentID).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return owner, err
+}
+
+// AcquireLease 获取或续约租约
+func (r *RedisRegistry) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	acquired, err := acquireLease.Run(ctx, r.client, []string{redisLeaseKeyPrefix + name}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+// ReleaseLease 释放租约
+func (r *RedisRegistry) ReleaseLease(ctx context.Context, name, holder string) error {
+	return compareAndDelete.Run(ctx, r.client, []string{redisLeaseKeyPrefix + name}, holder).Err()
+}
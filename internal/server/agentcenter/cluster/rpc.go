@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+)
+
+// DeliverFunc 将命令放入本实例持有的 Agent 连接的发送队列
+type DeliverFunc func(agentID string, cmd *grpcProto.Command) error
+
+// rpcService 实现 Cluster gRPC 服务，接收其他实例转发的命令
+type rpcService struct {
+	grpcProto.UnimplementedClusterServer
+	deliver DeliverFunc
+	logger  *zap.Logger
+}
+
+// ForwardCommand 将转发的命令下发给本实例持有的 Agent 连接
+func (s *rpcService) ForwardCommand(ctx context.Context, req *grpcProto.ForwardCommandRequest) (*grpcProto.ForwardCommandResponse, error) {
+	if req.AgentId == "" || req.Command == nil {
+		return nil, status.Error(codes.InvalidArgument, "agent_id 和 command 不能为空")
+	}
+	if err := s.deliver(req.AgentId, req.Command); err != nil {
+		s.logger.Debug("转发的命令下发失败",
+			zap.String("agent_id", req.AgentId),
+			zap.Error(err))
+		return &grpcProto.ForwardCommandResponse{Delivered: false, Error: err.Error()}, nil
+	}
+	return &grpcProto.ForwardCommandResponse{Delivered: true}, nil
+}
+
+// StartRPC 启动内部 RPC 服务（后台运行），未启用集群时不做任何事
+func (n *Node) StartRPC(deliver DeliverFunc) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", n.cfg.RPCAddress())
+	if err != nil {
+		return fmt.Errorf("监听集群 RPC 端口失败: %w", err)
+	}
+
+	n.rpcServer = grpc.NewServer(grpc.Creds(n.serverCreds), grpc.UnaryInterceptor(n.authInterceptor))
+	grpcProto.RegisterClusterServer(n.rpcServer, &rpcService{deliver: deliver, logger: n.logger})
+
+	go func() {
+		if err := n.rpcServer.Serve(listener); err != nil {
+			n.logger.Error("集群 RPC 服务异常退出", zap.Error(err))
+		}
+	}()
+
+	n.logger.Info("集群 RPC 服务已启动",
+		zap.String("address", n.cfg.RPCAddress()),
+		zap.Bool("mtls", n.cfg.TLS.CACert != ""),
+		zap.Bool("secret", n.cfg.Secret != ""))
+	return nil
+}
+
+// authInterceptor 校验实例间共享密钥（客户端证书已在 TLS 握手时校验）
+func (n *Node) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !n.validSecret(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "集群密钥无效")
+	}
+	return handler(ctx, req)
+}
+
+// validSecret 以常量时间比较请求携带的共享密钥；只配置 mTLS 时不要求密钥
+func (n *Node) validSecret(ctx context.Context) bool {
+	if n.cfg.Secret == "" {
+		return n.cfg.TLS.CACert != ""
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(secretMetadataKey)
+	if len(values) != 1 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(values[0]), []byte(n.cfg.Secret)) == 1
+}
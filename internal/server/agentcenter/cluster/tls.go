@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+// transportCredentials 根据集群 TLS 配置创建内部 RPC 的服务端和客户端凭证
+// 配置 CA 时服务端要求并校验客户端证书（mTLS），客户端使用 CA 校验服务端证书
+func transportCredentials(cfg config.ClusterConfig) (server, client credentials.TransportCredentials, err error) {
+	cert, err := tls.LoadX509KeyPair(cfg.TLS.Cert, cfg.TLS.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载集群 TLS 证书失败: %w", err)
+	}
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	clientConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		ServerName:   cfg.TLS.ServerName, // 为空时 gRPC 使用目标地址中的主机名
+	}
+
+	if cfg.TLS.CACert != "" {
+		caCert, err := os.ReadFile(cfg.TLS.CACert)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取集群 CA 证书失败: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, nil, fmt.Errorf("解析集群 CA 证书失败")
+		}
+		serverConfig.ClientCAs = pool
+		serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		clientConfig.RootCAs = pool
+	}
+
+	return credentials.NewTLS(serverConfig), credentials.NewTLS(clientConfig), nil
+}
@@ -0,0 +1,139 @@
+// Package fileext 实现 FileExt 文件上传服务
+// Agent 使用 Server 下发的一次性令牌分块上传文件（目前用于诊断包），令牌在上传成功后失效
+package fileext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// MaxUploadSize 是单个上传文件的最大大小
+const MaxUploadSize = 32 * 1024 * 1024
+
+// Service 是 FileExt 服务实现
+type Service struct {
+	grpcProto.UnimplementedFileExtServer
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewService 创建 FileExt 服务实例
+func NewService(db *gorm.DB, logger *zap.Logger) *Service {
+	return &Service{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Upload 接收分块上传的文件
+// 第一个请求必须携带令牌，后续请求的令牌为空或与第一个一致
+func (s *Service) Upload(stream grpc.ClientStreamingServer[grpcProto.FileUploadRequest, grpcProto.FileUploadResponse]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return status.Errorf(codes.InvalidArgument, "上传内容为空")
+		}
+		return err
+	}
+	token := first.Token
+	if token == "" {
+		return stream.SendAndClose(failed("缺少上传令牌"))
+	}
+
+	var record model.AgentDiagnostics
+	err = s.db.Omit("content").
+		Where("token = ? AND status = ?", token, model.AgentDiagnosticsStatusCollecting).
+		First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		s.logger.Warn("文件上传令牌无效或已使用")
+		return stream.SendAndClose(failed("上传令牌无效或已使用"))
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "查询上传令牌失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	for req := first; ; {
+		if buf.Len()+len(req.Data) > MaxUploadSize {
+			s.fail(&record, fmt.Sprintf("诊断包超过大小限制（%d MB）", MaxUploadSize/1024/1024))
+			return stream.SendAndClose(failed("文件超过大小限制"))
+		}
+		buf.Write(req.Data)
+
+		req, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.Token != "" && req.Token != token {
+			return stream.SendAndClose(failed("上传令牌不一致"))
+		}
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	completedAt := model.ToLocalTime(time.Now())
+	// 只更新 collecting 状态的记录，保证令牌只能使用一次
+	result := s.db.Model(&model.AgentDiagnostics{}).
+		Where("id = ? AND status = ?", record.ID, model.AgentDiagnosticsStatusCollecting).
+		Updates(map[string]interface{}{
+			"status":       model.AgentDiagnosticsStatusCompleted,
+			"content":      buf.Bytes(),
+			"file_size":    buf.Len(),
+			"sha256":       hex.EncodeToString(sum[:]),
+			"message":      "",
+			"completed_at": &completedAt,
+		})
+	if result.Error != nil {
+		s.logger.Error("保存诊断包失败",
+			zap.Uint("record_id", record.ID),
+			zap.String("host_id", record.HostID),
+			zap.Error(result.Error),
+		)
+		return status.Errorf(codes.Internal, "保存文件失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return stream.SendAndClose(failed("上传令牌无效或已使用"))
+	}
+
+	s.logger.Info("诊断包已上传",
+		zap.Uint("record_id", record.ID),
+		zap.String("host_id", record.HostID),
+		zap.Int("size", buf.Len()),
+	)
+	return stream.SendAndClose(&grpcProto.FileUploadResponse{Status: grpcProto.FileUploadResponse_SUCCESS})
+}
+
+// fail 将记录标记为失败
+func (s *Service) fail(record *model.AgentDiagnostics, message string) {
+	completedAt := model.ToLocalTime(time.Now())
+	s.db.Model(&model.AgentDiagnostics{}).
+		Where("id = ? AND status = ?", record.ID, model.AgentDiagnosticsStatusCollecting).
+		Updates(map[string]interface{}{
+			"status":       model.AgentDiagnosticsStatusFailed,
+			"message":      message,
+			"completed_at": &completedAt,
+		})
+}
+
+// failed 构造失败响应
+func failed(message string) *grpcProto.FileUploadResponse {
+	return &grpcProto.FileUploadResponse{
+		Status:  grpcProto.FileUploadResponse_FAILED,
+		Message: message,
+	}
+}
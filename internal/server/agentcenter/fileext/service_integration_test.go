@@ -0,0 +1,150 @@
+//go:build integration
+// +build integration
+
+package fileext
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// fakeUploadStream 按顺序返回预置的上传请求，并记录最终响应
+type fakeUploadStream struct {
+	grpc.ServerStream
+	reqs []*grpcProto.FileUploadRequest
+	resp *grpcProto.FileUploadResponse
+}
+
+func (s *fakeUploadStream) Recv() (*grpcProto.FileUploadRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *fakeUploadStream) SendAndClose(resp *grpcProto.FileUploadResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func (s *fakeUploadStream) Context() context.Context {
+	return context.Background()
+}
+
+// TestUploadToken 测试只有 collecting 状态记录的令牌可以上传，且令牌只能使用一次
+func TestUploadToken(t *testing.T) {
+	db := testdb.Open(t, &model.AgentDiagnostics{})
+	records := []model.AgentDiagnostics{
+		{HostID: "host-1", Token: "collecting-token", Status: model.AgentDiagnosticsStatusCollecting},
+		{HostID: "host-2", Token: "pending-token", Status: model.AgentDiagnosticsStatusPending},
+	}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(db, zap.NewNop())
+
+	upload := func(chunks ...*grpcProto.FileUploadRequest) *grpcProto.FileUploadResponse {
+		t.Helper()
+		stream := &fakeUploadStream{reqs: chunks}
+		if err := svc.Upload(stream); err != nil {
+			t.Fatalf("Upload returned error: %v", err)
+		}
+		return stream.resp
+	}
+
+	tests := []struct {
+		name   string
+		chunks []*grpcProto.FileUploadRequest
+		want   grpcProto.FileUploadResponse_StatusCode
+	}{
+		{"missing token", []*grpcProto.FileUploadRequest{{Data: []byte("x")}}, grpcProto.FileUploadResponse_FAILED},
+		{"unknown token", []*grpcProto.FileUploadRequest{{Token: "unknown", Data: []byte("x")}}, grpcProto.FileUploadResponse_FAILED},
+		{"record not collecting", []*grpcProto.FileUploadRequest{{Token: "pending-token", Data: []byte("x")}}, grpcProto.FileUploadResponse_FAILED},
+		{"mismatched token", []*grpcProto.FileUploadRequest{
+			{Token: "collecting-token", Data: []byte("a")},
+			{Token: "other-token", Data: []byte("b")},
+		}, grpcProto.FileUploadResponse_FAILED},
+		{"valid token", []*grpcProto.FileUploadRequest{
+			{Token: "collecting-token", Data: []byte("bundle-")},
+			{Data: []byte("data")},
+		}, grpcProto.FileUploadResponse_SUCCESS},
+		{"token reused", []*grpcProto.FileUploadRequest{{Token: "collecting-token", Data: []byte("x")}}, grpcProto.FileUploadResponse_FAILED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := upload(tt.chunks...); resp.Status != tt.want {
+				t.Errorf("status = %v (%s), want %v", resp.Status, resp.Message, tt.want)
+			}
+		})
+	}
+
+	var got model.AgentDiagnostics
+	if err := db.First(&got, records[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.AgentDiagnosticsStatusCompleted || string(got.Content) != "bundle-data" || got.FileSize != int64(len("bundle-data")) {
+		t.Errorf("record = status %s content %q size %d, want completed bundle-data", got.Status, got.Content, got.FileSize)
+	}
+	var pending model.AgentDiagnostics
+	if err := db.First(&pending, records[1].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != model.AgentDiagnosticsStatusPending {
+		t.Errorf("pending record status = %s, want pending", pending.Status)
+	}
+}
+
+// TestUploadSizeLimit 测试超过大小限制的上传被拒绝并将记录标记为失败，包括只有一个超大分块的情况
+func TestUploadSizeLimit(t *testing.T) {
+	db := testdb.Open(t, &model.AgentDiagnostics{})
+	records := []model.AgentDiagnostics{
+		{HostID: "host-1", Token: "first-chunk-token", Status: model.AgentDiagnosticsStatusCollecting},
+		{HostID: "host-2", Token: "later-chunk-token", Status: model.AgentDiagnosticsStatusCollecting},
+	}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(db, zap.NewNop())
+
+	tests := []struct {
+		name   string
+		record model.AgentDiagnostics
+		chunks []*grpcProto.FileUploadRequest
+	}{
+		{"oversized first chunk", records[0], []*grpcProto.FileUploadRequest{
+			{Token: "first-chunk-token", Data: make([]byte, MaxUploadSize+1)},
+		}},
+		{"oversized total", records[1], []*grpcProto.FileUploadRequest{
+			{Token: "later-chunk-token", Data: make([]byte, MaxUploadSize)},
+			{Data: []byte("x")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fakeUploadStream{reqs: tt.chunks}
+			if err := svc.Upload(stream); err != nil {
+				t.Fatalf("Upload returned error: %v", err)
+			}
+			if stream.resp.Status != grpcProto.FileUploadResponse_FAILED {
+				t.Errorf("status = %v (%s), want FAILED", stream.resp.Status, stream.resp.Message)
+			}
+			var got model.AgentDiagnostics
+			if err := db.Omit("content").First(&got, tt.record.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != model.AgentDiagnosticsStatusFailed {
+				t.Errorf("record status = %s, want failed", got.Status)
+			}
+		})
+	}
+}
@@ -96,6 +96,10 @@ func (s *AgentRestartScheduler) processRestartRecord(ctx context.Context, record
 		targetHostIDs := []string(record.TargetHosts)
 		query = query.Where("host_id IN ?", targetHostIDs)
 	}
+	if len(record.BusinessLines) > 0 {
+		// 发起人受业务线范围限制
+		query = query.Where("business_line IN ?", []string(record.BusinessLines))
+	}
 	if err := query.Find(&hosts).Error; err != nil {
 		s.logger.Error("查询目标主机失败", zap.Error(err))
 		s.db.Model(record).Updates(map[string]interface{}{
@@ -181,6 +185,10 @@ func (s *AgentRestartScheduler) verifyRestart(record *model.AgentRestartRecord)
 		targetHostIDs := []string(record.TargetHosts)
 		query = query.Where("host_id IN ?", targetHostIDs)
 	}
+	if len(record.BusinessLines) > 0 {
+		// 发起人受业务线范围限制
+		query = query.Where("business_line IN ?", []string(record.BusinessLines))
+	}
 	if err := query.Find(&hosts).Error; err != nil {
 		s.logger.Error("查询目标主机失败", zap.Error(err))
 		return
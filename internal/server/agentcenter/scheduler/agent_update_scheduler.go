@@ -13,16 +13,17 @@ import (
 	"gorm.io/gorm"
 
 	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
-	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/transfer"
 	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/maintenance"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
 // AgentUpdateScheduler Agent 更新调度器
 // 定期检查是否有新版本的 Agent，并推送给需要更新的 Agent
+// 维护窗口未开启或变更冻结中的主机不推送：推送记录中的主机标记为 waiting_window，窗口开启后再推送
 type AgentUpdateScheduler struct {
 	db              *gorm.DB
-	transferService *transfer.Service
+	transferService commandSender
 	cfg             *config.Config
 	logger          *zap.Logger
 	lastCheckTime   time.Time
@@ -30,7 +31,7 @@ type AgentUpdateScheduler struct {
 }
 
 // NewAgentUpdateScheduler 创建 Agent 更新调度器
-func NewAgentUpdateScheduler(db *gorm.DB, transferService *transfer.Service, cfg *config.Config, logger *zap.Logger) *AgentUpdateScheduler {
+func NewAgentUpdateScheduler(db *gorm.DB, transferService commandSender, cfg *config.Config, logger *zap.Logger) *AgentUpdateScheduler {
 	return &AgentUpdateScheduler{
 		db:              db,
 		transferService: transferService,
@@ -145,6 +146,15 @@ func (s *AgentUpdateScheduler) checkAndPushUpdates(ctx context.Context) {
 
 	s.logger.Debug("开始检查 Agent 更新")
 
+	gate, err := maintenance.Load(s.db, time.Now())
+	if err != nil {
+		s.logger.Error("加载维护窗口失败", zap.Error(err))
+		return
+	}
+
+	// 推送窗口已开启的等待主机
+	s.processWaitingPushHosts(gate)
+
 	// 优先处理 pending 状态的推送记录（手动触发的推送）
 	var pendingRecords []model.ComponentPushRecord
 	if err := s.db.Where("component_name = ? AND status = ?", "agent", model.ComponentPushStatusPending).
@@ -161,17 +171,25 @@ func (s *AgentUpdateScheduler) checkAndPushUpdates(ctx context.Context) {
 				zap.Uint("record_id", record.ID),
 				zap.String("version", record.Version),
 				zap.Int("total_count", record.TotalCount))
-			s.processPushRecord(ctx, &record)
+			// 失败已记录在推送记录中
+			s.processPushRecord(ctx, &record, gate)
 		}
 		return // 处理完 pending 记录后返回，下次再检查自动更新
 	}
 
 	// 如果没有 pending 记录，执行自动更新检查
-	s.autoCheckAndPushUpdates(ctx)
+	s.autoCheckAndPushUpdates(ctx, gate)
+}
+
+// pushResult 是一次推送记录处理的主机结果
+type pushResult struct {
+	successCount   int
+	failedHostIDs  []string
+	waitingHostIDs []string // 等待维护窗口的主机
 }
 
-// processPushRecord 处理单个推送记录
-func (s *AgentUpdateScheduler) processPushRecord(ctx context.Context, pushRecord *model.ComponentPushRecord) {
+// processPushRecord 处理单个推送记录，查询版本或主机失败时将记录标记为失败并返回错误
+func (s *AgentUpdateScheduler) processPushRecord(ctx context.Context, pushRecord *model.ComponentPushRecord, gate *maintenance.Gate) (*pushResult, error) {
 	// 更新状态为 pushing
 	s.db.Model(pushRecord).Update("status", model.ComponentPushStatusPushing)
 
@@ -183,7 +201,7 @@ func (s *AgentUpdateScheduler) processPushRecord(ctx context.Context, pushRecord
 			"status":  model.ComponentPushStatusFailed,
 			"message": "查询版本失败: " + err.Error(),
 		})
-		return
+		return nil, fmt.Errorf("查询版本失败: %w", err)
 	}
 
 	// 查询目标主机
@@ -200,12 +218,13 @@ func (s *AgentUpdateScheduler) processPushRecord(ctx context.Context, pushRecord
 			"status":  model.ComponentPushStatusFailed,
 			"message": "查询主机失败: " + err.Error(),
 		})
-		return
+		return nil, fmt.Errorf("查询主机失败: %w", err)
 	}
 
 	successCount := 0
 	failedCount := 0
 	var failedHostIDs []string
+	var waitingHosts []model.ComponentPushHost
 
 	for _, host := range hosts {
 		// 检查是否需要更新（考虑 force 标志）
@@ -214,65 +233,51 @@ func (s *AgentUpdateScheduler) processPushRecord(ctx context.Context, pushRecord
 			continue
 		}
 
-		// 根据主机的架构和 OS 查找对应的包
-		pkgType := s.detectPackageType(host.OSFamily)
-		arch := host.Arch
-		if arch == "" {
-			arch = "amd64"
-		}
-
-		var pkg model.ComponentPackage
-		if err := s.db.Where("version_id = ? AND pkg_type = ? AND arch = ? AND enabled = ?",
-			latestVersion.ID, pkgType, arch, true).First(&pkg).Error; err != nil {
-			s.logger.Debug("未找到对应的 Agent 包",
-				zap.String("host_id", host.HostID),
-				zap.String("pkg_type", string(pkgType)),
-				zap.String("arch", arch),
-				zap.Error(err))
-			failedCount++
-			failedHostIDs = append(failedHostIDs, host.HostID)
+		// 维护窗口未开启或变更冻结中，等待窗口开启（紧急放行的推送除外）
+		if reason := pushHostWaitReason(pushRecord, gate, &host); reason != "" {
+			waitingHosts = append(waitingHosts, model.ComponentPushHost{
+				RecordID: pushRecord.ID,
+				HostID:   host.HostID,
+				Hostname: host.Hostname,
+				Status:   model.ComponentPushHostStatusWaiting,
+				Message:  reason,
+			})
 			continue
 		}
 
-		// 构建完整下载 URL
-		downloadURL := s.buildDownloadURL(pkgType, arch)
-
-		// 构建更新命令
-		agentUpdate := &grpcProto.AgentUpdate{
-			Version:     latestVersion.Version,
-			DownloadUrl: downloadURL,
-			Sha256:      pkg.SHA256,
-			PkgType:     string(pkg.PkgType),
-			Arch:        pkg.Arch,
-			Force:       pushRecord.Force, // 使用推送记录中的 force 标志
-		}
-
-		cmd := &grpcProto.Command{
-			AgentUpdate: agentUpdate,
-		}
-
-		// 发送更新命令
-		if err := s.transferService.SendCommand(host.HostID, cmd); err != nil {
-			s.logger.Warn("推送 Agent 更新失败",
-				zap.String("host_id", host.HostID),
-				zap.String("version", latestVersion.Version),
-				zap.Error(err))
+		if err := s.pushToHost(&host, &latestVersion, pushRecord.Force); err != nil {
 			failedCount++
 			failedHostIDs = append(failedHostIDs, host.HostID)
 			continue
 		}
-
-		s.logger.Info("已推送 Agent 更新",
-			zap.String("host_id", host.HostID),
-			zap.String("old_version", host.AgentVersion),
-			zap.String("new_version", latestVersion.Version),
-			zap.Bool("force", pushRecord.Force),
-			zap.String("download_url", downloadURL))
-
 		successCount++
 	}
 
-	// 更新推送记录的最终状态
+	result := &pushResult{successCount: successCount, failedHostIDs: failedHostIDs, waitingHostIDs: pushHostIDs(waitingHosts)}
+	if len(waitingHosts) > 0 {
+		if err := s.db.Create(&waitingHosts).Error; err != nil {
+			s.logger.Error("创建等待维护窗口的主机记录失败", zap.Uint("record_id", pushRecord.ID), zap.Error(err))
+		}
+		s.db.Model(pushRecord).Updates(map[string]interface{}{
+			"success_count": successCount,
+			"failed_count":  failedCount,
+			"failed_hosts":  model.StringArray(failedHostIDs),
+			"message":       fmt.Sprintf("已推送 %d 台，失败 %d 台，%d 台等待维护窗口", successCount, failedCount, len(waitingHosts)),
+		})
+		s.logger.Info("推送记录部分主机等待维护窗口",
+			zap.Uint("record_id", pushRecord.ID),
+			zap.Int("success_count", successCount),
+			zap.Int("failed_count", failedCount),
+			zap.Int("waiting_count", len(waitingHosts)))
+		return result, nil
+	}
+
+	s.finishPushRecord(pushRecord, successCount, failedCount, failedHostIDs)
+	return result, nil
+}
+
+// finishPushRecord 更新推送记录的最终状态
+func (s *AgentUpdateScheduler) finishPushRecord(pushRecord *model.ComponentPushRecord, successCount, failedCount int, failedHostIDs []string) {
 	now := model.ToLocalTime(time.Now())
 	updates := map[string]interface{}{
 		"success_count": successCount,
@@ -300,9 +305,200 @@ func (s *AgentUpdateScheduler) processPushRecord(ctx context.Context, pushRecord
 		zap.Int("failed_count", failedCount))
 }
 
+// pushToHost 向单台主机推送 Agent 更新命令
+func (s *AgentUpdateScheduler) pushToHost(host *model.Host, version *model.ComponentVersion, force bool) error {
+	// 根据主机的架构和 OS 查找对应的包
+	pkgType := s.detectPackageType(host.OSFamily)
+	arch := host.Arch
+	if arch == "" {
+		arch = "amd64"
+	}
+
+	var pkg model.ComponentPackage
+	if err := s.db.Where("version_id = ? AND pkg_type = ? AND arch = ? AND enabled = ?",
+		version.ID, pkgType, arch, true).First(&pkg).Error; err != nil {
+		s.logger.Debug("未找到对应的 Agent 包",
+			zap.String("host_id", host.HostID),
+			zap.String("pkg_type", string(pkgType)),
+			zap.String("arch", arch),
+			zap.Error(err))
+		return fmt.Errorf("未找到对应的 Agent 包（%s/%s）", pkgType, arch)
+	}
+
+	// 构建完整下载 URL
+	downloadURL := s.buildDownloadURL(pkgType, arch)
+
+	// 构建更新命令
+	cmd := &grpcProto.Command{
+		AgentUpdate: &grpcProto.AgentUpdate{
+			Version:     version.Version,
+			DownloadUrl: downloadURL,
+			Sha256:      pkg.SHA256,
+			PkgType:     string(pkg.PkgType),
+			Arch:        pkg.Arch,
+			Force:       force, // 使用推送记录中的 force 标志
+		},
+	}
+
+	// 发送更新命令
+	if err := s.transferService.SendCommand(host.HostID, cmd); err != nil {
+		s.logger.Warn("推送 Agent 更新失败",
+			zap.String("host_id", host.HostID),
+			zap.String("version", version.Version),
+			zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("已推送 Agent 更新",
+		zap.String("host_id", host.HostID),
+		zap.String("old_version", host.AgentVersion),
+		zap.String("new_version", version.Version),
+		zap.Bool("force", force),
+		zap.String("download_url", downloadURL))
+	return nil
+}
+
+// processWaitingPushHosts 推送等待维护窗口、且窗口已开启（或已紧急放行）的主机，记录下没有等待主机后结束推送
+func (s *AgentUpdateScheduler) processWaitingPushHosts(gate *maintenance.Gate) {
+	var waiting []model.ComponentPushHost
+	if err := s.db.Where("status = ?", model.ComponentPushHostStatusWaiting).Order("record_id").Find(&waiting).Error; err != nil {
+		s.logger.Error("查询等待维护窗口的推送主机失败", zap.Error(err))
+		return
+	}
+
+	byRecord := make(map[uint][]model.ComponentPushHost)
+	for _, w := range waiting {
+		byRecord[w.RecordID] = append(byRecord[w.RecordID], w)
+	}
+
+	for recordID, rows := range byRecord {
+		var record model.ComponentPushRecord
+		if err := s.db.First(&record, recordID).Error; err != nil {
+			s.logger.Error("查询推送记录失败", zap.Uint("record_id", recordID), zap.Error(err))
+			continue
+		}
+		if record.Status != model.ComponentPushStatusPushing {
+			s.finishWaitingPushHosts(rows, model.ComponentPushHostStatusFailed, "推送记录已结束")
+			continue
+		}
+
+		var version model.ComponentVersion
+		if err := s.db.Where("component_id = ? AND version = ?", record.ComponentID, record.Version).First(&version).Error; err != nil {
+			s.logger.Error("查询版本失败", zap.Uint("record_id", recordID), zap.Error(err))
+			s.finishWaitingPushHosts(rows, model.ComponentPushHostStatusFailed, "查询版本失败: "+err.Error())
+			s.finishPushRecordIfDone(&record, 0, len(rows), pushHostIDs(rows))
+			continue
+		}
+
+		hostIDs := pushHostIDs(rows)
+		var hosts []model.Host
+		if err := s.db.Where("host_id IN ?", hostIDs).Find(&hosts).Error; err != nil {
+			s.logger.Error("查询主机失败", zap.Error(err))
+			continue
+		}
+		hostMap := make(map[string]*model.Host, len(hosts))
+		for i := range hosts {
+			hostMap[hosts[i].HostID] = &hosts[i]
+		}
+
+		successCount := 0
+		var failedHostIDs []string
+		for _, row := range rows {
+			host, ok := hostMap[row.HostID]
+			if !ok {
+				s.finishWaitingPushHosts([]model.ComponentPushHost{row}, model.ComponentPushHostStatusFailed, "主机已删除")
+				failedHostIDs = append(failedHostIDs, row.HostID)
+				continue
+			}
+			if reason := pushHostWaitReason(&record, gate, host); reason != "" {
+				if reason != row.Message {
+					s.db.Model(&row).Update("message", reason)
+				}
+				continue
+			}
+			if host.Status != model.HostStatusOnline {
+				s.finishWaitingPushHosts([]model.ComponentPushHost{row}, model.ComponentPushHostStatusFailed, "维护窗口开启时主机不在线")
+				failedHostIDs = append(failedHostIDs, row.HostID)
+				continue
+			}
+			if err := s.pushToHost(host, &version, record.Force); err != nil {
+				s.finishWaitingPushHosts([]model.ComponentPushHost{row}, model.ComponentPushHostStatusFailed, err.Error())
+				failedHostIDs = append(failedHostIDs, row.HostID)
+				continue
+			}
+			s.finishWaitingPushHosts([]model.ComponentPushHost{row}, model.ComponentPushHostStatusSuccess, "维护窗口内已推送")
+			successCount++
+		}
+
+		s.finishPushRecordIfDone(&record, successCount, len(failedHostIDs), failedHostIDs)
+	}
+}
+
+// finishPushRecordIfDone 累加推送结果，记录下没有等待主机时结束推送记录
+func (s *AgentUpdateScheduler) finishPushRecordIfDone(record *model.ComponentPushRecord, successCount, failedCount int, failedHostIDs []string) {
+	record.SuccessCount += successCount
+	record.FailedCount += failedCount
+	record.FailedHosts = append(record.FailedHosts, failedHostIDs...)
+
+	var remaining int64
+	s.db.Model(&model.ComponentPushHost{}).
+		Where("record_id = ? AND status = ?", record.ID, model.ComponentPushHostStatusWaiting).
+		Count(&remaining)
+	if remaining == 0 {
+		s.finishPushRecord(record, record.SuccessCount, record.FailedCount, record.FailedHosts)
+		return
+	}
+	if successCount == 0 && failedCount == 0 {
+		return
+	}
+	s.db.Model(record).Updates(map[string]interface{}{
+		"success_count": record.SuccessCount,
+		"failed_count":  record.FailedCount,
+		"failed_hosts":  record.FailedHosts,
+		"message":       fmt.Sprintf("已推送 %d 台，失败 %d 台，%d 台等待维护窗口", record.SuccessCount, record.FailedCount, remaining),
+	})
+}
+
+// finishWaitingPushHosts 更新等待主机的推送结果
+func (s *AgentUpdateScheduler) finishWaitingPushHosts(rows []model.ComponentPushHost, status model.ComponentPushHostStatus, message string) {
+	now := model.Now()
+	s.db.Model(&model.ComponentPushHost{}).
+		Where("id IN ?", pushHostRowIDs(rows)).
+		Updates(map[string]interface{}{
+			"status":    status,
+			"message":   message,
+			"pushed_at": &now,
+		})
+}
+
+// pushHostWaitReason 返回主机需要等待维护窗口的原因，可以立即推送时返回空字符串
+func pushHostWaitReason(record *model.ComponentPushRecord, gate *maintenance.Gate, host *model.Host) string {
+	if gate == nil || record.Overridden() {
+		return ""
+	}
+	return gate.Check(host)
+}
+
+func pushHostIDs(rows []model.ComponentPushHost) []string {
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.HostID)
+	}
+	return ids
+}
+
+func pushHostRowIDs(rows []model.ComponentPushHost) []uint {
+	ids := make([]uint, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
 // autoCheckAndPushUpdates 自动检查并推送 Agent 更新（原有逻辑）
+// 维护窗口未开启的主机本次跳过，窗口开启后的检查中再推送
 // 注意：调用方 checkAndPushUpdates 已持有 s.mu 锁，此处不可重复加锁
-func (s *AgentUpdateScheduler) autoCheckAndPushUpdates(ctx context.Context) {
+func (s *AgentUpdateScheduler) autoCheckAndPushUpdates(ctx context.Context, gate *maintenance.Gate) {
 	// 查找 agent 组件的最新版本
 	var agentComponent model.Component
 	if err := s.db.Where("name = ? AND category = ?", "agent", model.ComponentCategoryAgent).First(&agentComponent).Error; err != nil {
@@ -344,6 +540,12 @@ func (s *AgentUpdateScheduler) autoCheckAndPushUpdates(ctx context.Context) {
 	for _, host := range hosts {
 		// 如果主机没有版本信息，或者版本不同，则推送更新
 		if host.AgentVersion == "" || host.AgentVersion != latestVersion.Version {
+			if reason := gate.Check(&host); reason != "" {
+				s.logger.Debug("主机不在维护窗口内，跳过自动更新",
+					zap.String("host_id", host.HostID),
+					zap.String("reason", reason))
+				continue
+			}
 			targetHostIDs = append(targetHostIDs, host.HostID)
 
 			// 根据主机的架构和 OS 查找对应的包
@@ -410,9 +612,10 @@ func (s *AgentUpdateScheduler) autoCheckAndPushUpdates(ctx context.Context) {
 			zap.Int("failed_count", len(failedHostIDs)),
 			zap.String("latest_version", latestVersion.Version))
 
-		// 更新推送记录（查找最近的 pending 记录）
+		// 更新推送记录（查找最近的 pending 记录，跳过有主机等待维护窗口的记录）
 		var pushRecord model.ComponentPushRecord
 		if err := s.db.Where("component_name = ? AND version = ? AND status IN ?", "agent", latestVersion.Version, []model.ComponentPushStatus{model.ComponentPushStatusPending, model.ComponentPushStatusPushing}).
+			Where("id NOT IN (?)", s.db.Model(&model.ComponentPushHost{}).Select("record_id").Where("status = ?", model.ComponentPushHostStatusWaiting)).
 			Order("created_at DESC").First(&pushRecord).Error; err == nil {
 			// 更新推送记录
 			successCount := updatedCount
@@ -452,116 +655,70 @@ func (s *AgentUpdateScheduler) detectPackageType(osFamily string) model.PackageT
 	}
 }
 
-// TriggerUpdate 手动触发 Agent 更新（供 API 调用）
-func (s *AgentUpdateScheduler) TriggerUpdate(ctx context.Context, hostIDs []string) (int, []string, error) {
+// TriggerUpdate 手动触发 Agent 更新（供 API 调用），强制推送最新版本
+// 与 API 创建的推送一样生成推送记录：不在维护窗口内的主机记录为 waiting_window，窗口开启后由调度器推送
+// 返回成功数、失败的主机和等待维护窗口的主机
+func (s *AgentUpdateScheduler) TriggerUpdate(ctx context.Context, hostIDs []string, operator string) (int, []string, []string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// 查找 agent 组件的最新版本
 	var agentComponent model.Component
 	if err := s.db.Where("name = ? AND category = ?", "agent", model.ComponentCategoryAgent).First(&agentComponent).Error; err != nil {
-		return 0, nil, fmt.Errorf("查询 Agent 组件失败: %w", err)
+		return 0, nil, nil, fmt.Errorf("查询 Agent 组件失败: %w", err)
 	}
 
 	var latestVersion model.ComponentVersion
 	if err := s.db.Where("component_id = ? AND is_latest = ?", agentComponent.ID, true).First(&latestVersion).Error; err != nil {
-		return 0, nil, fmt.Errorf("查询 Agent 最新版本失败: %w", err)
+		return 0, nil, nil, fmt.Errorf("查询 Agent 最新版本失败: %w", err)
 	}
 
-	// 查询指定的主机
-	var hosts []model.Host
-	query := s.db.Where("status = ?", model.HostStatusOnline)
+	// 统计在线的目标主机
+	var hostCount int64
+	query := s.db.Model(&model.Host{}).Where("status = ?", model.HostStatusOnline)
 	if len(hostIDs) > 0 {
 		query = query.Where("host_id IN ?", hostIDs)
 	}
-	if err := query.Find(&hosts).Error; err != nil {
-		return 0, nil, fmt.Errorf("查询主机失败: %w", err)
+	if err := query.Count(&hostCount).Error; err != nil {
+		return 0, nil, nil, fmt.Errorf("查询主机失败: %w", err)
 	}
-
-	if len(hosts) == 0 {
-		return 0, nil, nil
+	if hostCount == 0 {
+		return 0, nil, nil, nil
 	}
 
-	successCount := 0
-	var failedAgents []string
-
-	for _, host := range hosts {
-		// 根据主机的架构和 OS 查找对应的包
-		pkgType := s.detectPackageType(host.OSFamily)
-		arch := host.Arch
-		if arch == "" {
-			arch = "amd64"
-		}
-
-		// 查找对应的包
-		var pkg model.ComponentPackage
-		if err := s.db.Where("version_id = ? AND pkg_type = ? AND arch = ? AND enabled = ?",
-			latestVersion.ID, pkgType, arch, true).First(&pkg).Error; err != nil {
-			failedAgents = append(failedAgents, host.HostID)
-			s.logger.Warn("未找到对应的 Agent 包",
-				zap.String("host_id", host.HostID),
-				zap.String("pkg_type", string(pkgType)),
-				zap.String("arch", arch))
-			continue
-		}
-
-		// 构建完整下载 URL
-		downloadURL := s.buildDownloadURL(pkgType, arch)
-
-		// 构建更新命令
-		agentUpdate := &grpcProto.AgentUpdate{
-			Version:     latestVersion.Version,
-			DownloadUrl: downloadURL,
-			Sha256:      pkg.SHA256,
-			PkgType:     string(pkg.PkgType),
-			Arch:        pkg.Arch,
-			Force:       true, // 手动推送时强制更新
-		}
-
-		cmd := &grpcProto.Command{
-			AgentUpdate: agentUpdate,
-		}
-
-		// 发送更新命令
-		if err := s.transferService.SendCommand(host.HostID, cmd); err != nil {
-			failedAgents = append(failedAgents, host.HostID)
-			s.logger.Warn("推送 Agent 更新失败",
-				zap.String("host_id", host.HostID),
-				zap.Error(err))
-			continue
-		}
-
-		successCount++
-		s.logger.Info("已手动推送 Agent 更新",
-			zap.String("host_id", host.HostID),
-			zap.String("version", latestVersion.Version),
-			zap.String("download_url", downloadURL))
+	gate, err := maintenance.Load(s.db, time.Now())
+	if err != nil {
+		return 0, nil, nil, err
 	}
 
-	// 更新推送记录（查找最近的 pending 记录）
-	if successCount > 0 || len(failedAgents) > 0 {
-		var pushRecord model.ComponentPushRecord
-		if err := s.db.Where("component_name = ? AND version = ? AND status = ?", "agent", latestVersion.Version, model.ComponentPushStatusPending).
-			Order("created_at DESC").First(&pushRecord).Error; err == nil {
-			// 更新推送记录
-			updates := map[string]interface{}{
-				"status":        model.ComponentPushStatusPushing,
-				"success_count": successCount,
-				"failed_count":  len(failedAgents),
-				"failed_hosts":  model.StringArray(failedAgents),
-			}
-			if successCount+len(failedAgents) >= pushRecord.TotalCount {
-				// 推送完成
-				now := model.ToLocalTime(time.Now())
-				updates["status"] = model.ComponentPushStatusSuccess
-				updates["completed_at"] = &now
-				if len(failedAgents) > 0 {
-					updates["status"] = model.ComponentPushStatusFailed
-				}
-			}
-			s.db.Model(&pushRecord).Updates(updates)
-		}
+	targetType := "all"
+	if len(hostIDs) > 0 {
+		targetType = "selected"
+	}
+	record := model.ComponentPushRecord{
+		ComponentID:   agentComponent.ID,
+		ComponentName: "agent",
+		Version:       latestVersion.Version,
+		TargetType:    targetType,
+		TargetHosts:   model.StringArray(hostIDs),
+		Status:        model.ComponentPushStatusPending,
+		TotalCount:    int(hostCount),
+		Force:         true, // 手动推送时强制更新
+		Message:       "手动触发推送",
+		CreatedBy:     operator,
+	}
+	if err := s.db.Create(&record).Error; err != nil {
+		return 0, nil, nil, fmt.Errorf("创建推送记录失败: %w", err)
 	}
 
-	return successCount, failedAgents, nil
+	s.logger.Info("手动触发 Agent 更新",
+		zap.Uint("record_id", record.ID),
+		zap.String("version", latestVersion.Version),
+		zap.Int64("host_count", hostCount))
+
+	result, err := s.processPushRecord(ctx, &record, gate)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return result.successCount, result.failedHostIDs, result.waitingHostIDs, nil
 }
@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// recordingSender 记录收到命令的 Agent
+type recordingSender struct {
+	agentIDs []string
+}
+
+func (s *recordingSender) SendCommand(agentID string, cmd *grpcProto.Command) error {
+	s.agentIDs = append(s.agentIDs, agentID)
+	return nil
+}
+
+// TestTriggerUpdateWaitingWindow 测试手动触发更新时维护窗口外的主机记录为 waiting_window 并返回给调用方
+func TestTriggerUpdateWaitingWindow(t *testing.T) {
+	db := testdb.Open(t, &model.Host{}, &model.Component{}, &model.ComponentVersion{}, &model.ComponentPackage{},
+		&model.ComponentPushRecord{}, &model.ComponentPushHost{}, &model.MaintenanceWindow{}, &model.ChangeFreeze{},
+		&model.SystemConfig{})
+
+	component := model.Component{Name: "agent", Category: model.ComponentCategoryAgent}
+	if err := db.Create(&component).Error; err != nil {
+		t.Fatal(err)
+	}
+	version := model.ComponentVersion{ComponentID: component.ID, Version: "1.2.0", IsLatest: true}
+	if err := db.Create(&version).Error; err != nil {
+		t.Fatal(err)
+	}
+	pkg := model.ComponentPackage{VersionID: version.ID, Arch: "amd64", PkgType: model.PackageTypeBinary,
+		FilePath: "/tmp/agent", FileName: "agent", Enabled: true}
+	if err := db.Create(&pkg).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"host-open", "host-closed"} {
+		host := model.Host{HostID: id, Hostname: id, Status: model.HostStatusOnline, Arch: "amd64", AgentVersion: "1.2.0"}
+		if err := db.Create(&host).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// host-closed 所属窗口只在每年 1 月 1 日 00:00 开启 1 分钟
+	window := model.MaintenanceWindow{Name: "年度窗口", Cron: "0 0 1 1 *", DurationMinutes: 1, Timezone: "UTC",
+		Scope: model.MaintenanceScopeHosts, ScopeValues: model.StringArray{"host-closed"}, Enabled: true}
+	if err := db.Create(&window).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &recordingSender{}
+	s := NewAgentUpdateScheduler(db, sender, &config.Config{}, zap.NewNop())
+	success, failed, waiting, err := s.TriggerUpdate(context.Background(), nil, "admin")
+	if err != nil {
+		t.Fatalf("TriggerUpdate failed: %v", err)
+	}
+	if success != 1 || len(failed) != 0 {
+		t.Errorf("success = %d, failed = %v, want 1 and none", success, failed)
+	}
+	if len(waiting) != 1 || waiting[0] != "host-closed" {
+		t.Errorf("waiting = %v, want [host-closed]", waiting)
+	}
+	if len(sender.agentIDs) != 1 || sender.agentIDs[0] != "host-open" {
+		t.Errorf("sent to %v, want [host-open]", sender.agentIDs)
+	}
+
+	var record model.ComponentPushRecord
+	if err := db.Where("component_name = ?", "agent").First(&record).Error; err != nil {
+		t.Fatalf("push record not created: %v", err)
+	}
+	if record.Status != model.ComponentPushStatusPushing || !record.Force || record.CreatedBy != "admin" {
+		t.Errorf("record status = %s, force = %v, created_by = %q", record.Status, record.Force, record.CreatedBy)
+	}
+	var rows []model.ComponentPushHost
+	if err := db.Where("record_id = ?", record.ID).Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].HostID != "host-closed" || rows[0].Status != model.ComponentPushHostStatusWaiting {
+		t.Fatalf("push hosts = %+v, want host-closed waiting_window", rows)
+	}
+	if rows[0].Message == "" {
+		t.Error("waiting row should record the reason")
+	}
+}
@@ -2,6 +2,7 @@
 package scheduler
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -14,7 +15,7 @@ import (
 
 // StartAlertScheduler 启动定期告警调度器
 // 每分钟检查一次，根据告警配置的间隔发送待通知的告警
-func StartAlertScheduler(db *gorm.DB, logger *zap.Logger) {
+func StartAlertScheduler(ctx context.Context, db *gorm.DB, logger *zap.Logger) {
 	ticker := time.NewTicker(1 * time.Minute) // 每分钟检查一次
 	defer ticker.Stop()
 
@@ -24,8 +25,14 @@ func StartAlertScheduler(db *gorm.DB, logger *zap.Logger) {
 	processPeriodicAlerts(db, logger)
 
 	// 定时执行
-	for range ticker.C {
-		processPeriodicAlerts(db, logger)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("定期告警调度器已停止")
+			return
+		case <-ticker.C:
+			processPeriodicAlerts(db, logger)
+		}
 	}
 }
 
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/transfer"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// assetRefreshTimeout 是按需采集下发后等待主机上报完成信号的最长时间
+const assetRefreshTimeout = 10 * time.Minute
+
+// AssetRefreshScheduler 资产按需采集调度器
+// 定期检查 DB 中的 pending 采集任务，向 collector 插件下发采集任务，并处理超时
+type AssetRefreshScheduler struct {
+	db              *gorm.DB
+	transferService *transfer.Service
+	logger          *zap.Logger
+	mu              sync.Mutex
+}
+
+// NewAssetRefreshScheduler 创建资产按需采集调度器
+func NewAssetRefreshScheduler(db *gorm.DB, transferService *transfer.Service, logger *zap.Logger) *AssetRefreshScheduler {
+	return &AssetRefreshScheduler{
+		db:              db,
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// Start 启动资产按需采集调度器
+func (s *AssetRefreshScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("资产采集调度器已启动", zap.Duration("interval", 5*time.Second))
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("资产采集调度器已停止")
+			return
+		case <-ticker.C:
+			s.checkAndDispatch()
+		}
+	}
+}
+
+// checkAndDispatch 下发 pending 任务并检查超时
+func (s *AssetRefreshScheduler) checkAndDispatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pendingTasks []model.AssetRefreshTask
+	if err := s.db.Where("status = ?", model.AssetRefreshStatusPending).
+		Order("created_at ASC").Find(&pendingTasks).Error; err != nil {
+		s.logger.Error("查询 pending 采集任务失败", zap.Error(err))
+		return
+	}
+
+	for i := range pendingTasks {
+		s.dispatchTask(&pendingTasks[i])
+	}
+
+	s.checkTimeout()
+}
+
+// dispatchTask 向任务中的每台主机下发采集任务（一台主机一条命令，包含所有采集器）
+func (s *AssetRefreshScheduler) dispatchTask(task *model.AssetRefreshTask) {
+	now := model.ToLocalTime(time.Now())
+	s.db.Model(task).Updates(map[string]interface{}{
+		"status":        model.AssetRefreshStatusRunning,
+		"dispatched_at": &now,
+	})
+
+	var rows []model.AssetRefreshHost
+	if err := s.db.Where("task_id = ? AND status = ?", task.ID, model.AssetRefreshHostPending).
+		Find(&rows).Error; err != nil {
+		s.logger.Error("查询采集任务明细失败", zap.Uint("task_id", task.ID), zap.Error(err))
+		return
+	}
+
+	byHost := make(map[string][]model.AssetRefreshHost)
+	for _, row := range rows {
+		byHost[row.HostID] = append(byHost[row.HostID], row)
+	}
+
+	failedHosts := 0
+	for hostID, hostRows := range byHost {
+		cmd := &grpcProto.Command{}
+		for _, row := range hostRows {
+			data, _ := json.Marshal(map[string]string{
+				"type":    row.Handler,
+				"task_id": fmt.Sprintf("%d", task.ID),
+			})
+			cmd.Tasks = append(cmd.Tasks, &grpcProto.Task{
+				DataType:   engine.GetDataType(row.Handler),
+				ObjectName: "collector", // 插件名称
+				Data:       string(data),
+				Token:      fmt.Sprintf("asset-refresh-%d-%s", task.ID, row.Handler),
+			})
+		}
+
+		hostQuery := s.db.Model(&model.AssetRefreshHost{}).
+			Where("task_id = ? AND host_id = ? AND status = ?", task.ID, hostID, model.AssetRefreshHostPending)
+
+		if err := s.transferService.SendCommand(hostID, cmd); err != nil {
+			s.logger.Warn("下发采集任务失败",
+				zap.Uint("task_id", task.ID),
+				zap.String("host_id", hostID),
+				zap.Error(err))
+			failedHosts++
+			hostQuery.Updates(map[string]interface{}{
+				"status":        model.AssetRefreshHostFailed,
+				"error_message": "下发失败: " + err.Error(),
+				"completed_at":  &now,
+			})
+			continue
+		}
+
+		hostQuery.Updates(map[string]interface{}{
+			"status":        model.AssetRefreshHostDispatched,
+			"dispatched_at": &now,
+		})
+	}
+
+	s.logger.Info("采集任务下发完成",
+		zap.Uint("task_id", task.ID),
+		zap.Int("host_count", len(byHost)),
+		zap.Int("failed_hosts", failedHosts))
+
+	if err := service.RefreshAssetTaskStatus(s.db, task.ID); err != nil {
+		s.logger.Error("更新采集任务状态失败", zap.Uint("task_id", task.ID), zap.Error(err))
+	}
+}
+
+// checkTimeout 将超时未上报的主机明细标记为 timeout，并更新对应任务状态
+func (s *AssetRefreshScheduler) checkTimeout() {
+	deadline := model.ToLocalTime(time.Now().Add(-assetRefreshTimeout))
+
+	var taskIDs []uint
+	if err := s.db.Model(&model.AssetRefreshHost{}).
+		Where("status = ? AND dispatched_at < ?", model.AssetRefreshHostDispatched, deadline).
+		Distinct("task_id").Pluck("task_id", &taskIDs).Error; err != nil {
+		s.logger.Error("查询超时采集任务失败", zap.Error(err))
+		return
+	}
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	now := model.ToLocalTime(time.Now())
+	s.db.Model(&model.AssetRefreshHost{}).
+		Where("status = ? AND dispatched_at < ?", model.AssetRefreshHostDispatched, deadline).
+		Updates(map[string]interface{}{
+			"status":        model.AssetRefreshHostTimeout,
+			"error_message": "等待主机上报超时",
+			"completed_at":  &now,
+		})
+
+	for _, taskID := range taskIDs {
+		if err := service.RefreshAssetTaskStatus(s.db, taskID); err != nil {
+			s.logger.Error("更新采集任务状态失败", zap.Uint("task_id", taskID), zap.Error(err))
+		}
+	}
+}
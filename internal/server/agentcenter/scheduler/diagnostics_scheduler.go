@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+const (
+	// diagnosticsTimeout 是下发诊断命令后等待 Agent 上传的最长时间
+	diagnosticsTimeout = 10 * time.Minute
+	// diagnosticsMaxLogBytes 是每个日志文件收集的最大字节数
+	diagnosticsMaxLogBytes = 2 * 1024 * 1024
+)
+
+// DiagnosticsScheduler Agent 诊断信息收集调度器
+// 定期检查 DB 中的 pending 诊断记录，下发带一次性上传令牌的诊断命令，并将超时未上传的记录标记为失败
+type DiagnosticsScheduler struct {
+	db              *gorm.DB
+	transferService commandSender
+	logger          *zap.Logger
+	mu              sync.Mutex
+}
+
+// commandSender 向 Agent 下发命令（由 transfer.Service 实现）
+type commandSender interface {
+	SendCommand(agentID string, cmd *grpcProto.Command) error
+}
+
+// NewDiagnosticsScheduler 创建诊断信息收集调度器
+func NewDiagnosticsScheduler(db *gorm.DB, transferService commandSender, logger *zap.Logger) *DiagnosticsScheduler {
+	return &DiagnosticsScheduler{
+		db:              db,
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// Start 启动诊断信息收集调度器
+func (s *DiagnosticsScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("诊断信息收集调度器已启动", zap.Duration("interval", 5*time.Second))
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("诊断信息收集调度器已停止")
+			return
+		case <-ticker.C:
+			s.checkAndDispatch()
+		}
+	}
+}
+
+// checkAndDispatch 下发 pending 记录并处理超时记录
+func (s *DiagnosticsScheduler) checkAndDispatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pendingRecords []model.AgentDiagnostics
+	if err := s.db.Omit("content").Where("status = ?", model.AgentDiagnosticsStatusPending).
+		Order("created_at ASC").Find(&pendingRecords).Error; err != nil {
+		s.logger.Error("查询 pending 诊断记录失败", zap.Error(err))
+		return
+	}
+	for i := range pendingRecords {
+		s.dispatch(&pendingRecords[i])
+	}
+
+	// 超时未上传的记录标记为失败
+	deadline := model.ToLocalTime(time.Now().Add(-diagnosticsTimeout))
+	completedAt := model.ToLocalTime(time.Now())
+	result := s.db.Model(&model.AgentDiagnostics{}).
+		Where("status = ? AND pushed_at < ?", model.AgentDiagnosticsStatusCollecting, deadline).
+		Updates(map[string]interface{}{
+			"status":       model.AgentDiagnosticsStatusFailed,
+			"message":      "等待 Agent 上传诊断包超时",
+			"completed_at": &completedAt,
+		})
+	if result.Error != nil {
+		s.logger.Error("更新超时诊断记录失败", zap.Error(result.Error))
+	} else if result.RowsAffected > 0 {
+		s.logger.Warn("诊断信息收集超时", zap.Int64("count", result.RowsAffected))
+	}
+}
+
+// dispatch 向目标主机下发诊断命令
+// 先将记录置为 collecting 再下发命令，否则响应快的 Agent 上传时记录仍为 pending，令牌校验会拒绝上传；
+// 下发失败时再将记录标记为失败
+func (s *DiagnosticsScheduler) dispatch(record *model.AgentDiagnostics) {
+	now := model.ToLocalTime(time.Now())
+	result := s.db.Model(&model.AgentDiagnostics{}).
+		Where("id = ? AND status = ?", record.ID, model.AgentDiagnosticsStatusPending).
+		Updates(map[string]interface{}{
+			"status":    model.AgentDiagnosticsStatusCollecting,
+			"pushed_at": &now,
+			"message":   "命令已下发，等待 Agent 上传诊断包",
+		})
+	if result.Error != nil {
+		s.logger.Error("更新诊断记录状态失败", zap.Uint("record_id", record.ID), zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		// 记录已被取消或处理
+		return
+	}
+
+	cmd := &grpcProto.Command{
+		Diagnostics: &grpcProto.DiagnosticsRequest{
+			Token:       record.Token,
+			MaxLogBytes: diagnosticsMaxLogBytes,
+		},
+	}
+	if err := s.transferService.SendCommand(record.HostID, cmd); err != nil {
+		s.logger.Warn("下发诊断命令失败",
+			zap.Uint("record_id", record.ID),
+			zap.String("host_id", record.HostID),
+			zap.Error(err))
+		if err := s.db.Model(&model.AgentDiagnostics{}).
+			Where("id = ? AND status = ?", record.ID, model.AgentDiagnosticsStatusCollecting).
+			Updates(map[string]interface{}{
+				"status":       model.AgentDiagnosticsStatusFailed,
+				"message":      "命令下发失败: " + err.Error(),
+				"completed_at": &now,
+			}).Error; err != nil {
+			s.logger.Error("更新诊断记录状态失败", zap.Uint("record_id", record.ID), zap.Error(err))
+		}
+		return
+	}
+
+	s.logger.Info("诊断命令已下发",
+		zap.Uint("record_id", record.ID),
+		zap.String("host_id", record.HostID))
+}
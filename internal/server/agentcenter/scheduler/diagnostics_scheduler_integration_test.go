@@ -0,0 +1,80 @@
+//go:build integration
+// +build integration
+
+package scheduler
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// fakeSender 记录下发命令时诊断记录的状态
+type fakeSender struct {
+	db       *gorm.DB
+	err      error
+	statuses []model.AgentDiagnosticsStatus
+}
+
+func (s *fakeSender) SendCommand(agentID string, cmd *grpcProto.Command) error {
+	var record model.AgentDiagnostics
+	if err := s.db.Omit("content").Where("token = ?", cmd.Diagnostics.Token).First(&record).Error; err != nil {
+		return err
+	}
+	s.statuses = append(s.statuses, record.Status)
+	return s.err
+}
+
+// TestDiagnosticsDispatch 测试下发命令前记录已置为 collecting（Agent 可立即上传），下发失败时标记为失败
+func TestDiagnosticsDispatch(t *testing.T) {
+	db := testdb.Open(t, &model.AgentDiagnostics{})
+
+	tests := []struct {
+		name       string
+		sendErr    error
+		wantStatus model.AgentDiagnosticsStatus
+	}{
+		{"sent", nil, model.AgentDiagnosticsStatusCollecting},
+		{"send failed", errors.New("agent offline"), model.AgentDiagnosticsStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := model.AgentDiagnostics{HostID: "host-1", Token: "token-" + tt.name, Status: model.AgentDiagnosticsStatusPending}
+			if err := db.Create(&record).Error; err != nil {
+				t.Fatal(err)
+			}
+			sender := &fakeSender{db: db, err: tt.sendErr}
+			s := NewDiagnosticsScheduler(db, sender, zap.NewNop())
+			s.dispatch(&record)
+
+			if len(sender.statuses) != 1 || sender.statuses[0] != model.AgentDiagnosticsStatusCollecting {
+				t.Fatalf("status at send time = %v, want [collecting]", sender.statuses)
+			}
+			var got model.AgentDiagnostics
+			if err := db.Omit("content").First(&got, record.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.PushedAt == nil {
+				t.Error("pushed_at not set")
+			}
+			if tt.sendErr != nil && got.CompletedAt == nil {
+				t.Error("completed_at not set for failed dispatch")
+			}
+
+			// 已处理的记录不会重复下发
+			s.dispatch(&record)
+			if len(sender.statuses) != 1 {
+				t.Errorf("record dispatched again: %v", sender.statuses)
+			}
+		})
+	}
+}
@@ -3,28 +3,41 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
-	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/transfer"
+	"github.com/imkerbos/mxsec-platform/internal/server/maintenance"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
 // PluginUpdateScheduler 插件更新调度器
 // 定期检查 plugin_configs 表是否有更新，如果有则广播到所有在线 Agent
+// 维护窗口未开启或变更冻结中的 Agent 暂缓发送，记入 plugin_config_deferrals，窗口开启或紧急放行后的检查中补发。
+// 每个 AgentCenter 实例各自运行本调度器，只补发自己持有连接的 Agent。
+// Agent 重连时的配置同步不受维护窗口限制：Agent 重启后没有插件配置，服务端只保存最新配置，
+// 不下发则插件无法启动；同步后删除该主机的暂缓记录
 type PluginUpdateScheduler struct {
 	db              *gorm.DB
-	transferService *transfer.Service
+	transferService pluginConfigSender
 	logger          *zap.Logger
 	lastCheckTime   time.Time
 	mu              sync.Mutex
 }
 
+// pluginConfigSender 向本实例持有连接的 Agent 发送插件配置（由 transfer.Service 实现）
+type pluginConfigSender interface {
+	GetOnlineAgentIDs() []string
+	SendPluginConfigs(ctx context.Context, agentIDs []string) (int, []string, error)
+}
+
 // NewPluginUpdateScheduler 创建插件更新调度器
-func NewPluginUpdateScheduler(db *gorm.DB, transferService *transfer.Service, logger *zap.Logger) *PluginUpdateScheduler {
+func NewPluginUpdateScheduler(db *gorm.DB, transferService pluginConfigSender, logger *zap.Logger) *PluginUpdateScheduler {
 	return &PluginUpdateScheduler{
 		db:              db,
 		transferService: transferService,
@@ -74,7 +87,7 @@ func (s *PluginUpdateScheduler) checkAndBroadcast(ctx context.Context) {
 			zap.Time("last_check", s.lastCheckTime),
 			zap.Time("latest_update", latestUpdate))
 
-		successCount, failedAgents, err := s.transferService.BroadcastPluginConfigs(ctx)
+		successCount, failedAgents, err := s.broadcast(ctx, s.transferService.GetOnlineAgentIDs())
 		if err != nil {
 			s.logger.Error("广播插件配置失败", zap.Error(err))
 		} else {
@@ -85,7 +98,118 @@ func (s *PluginUpdateScheduler) checkAndBroadcast(ctx context.Context) {
 
 		// 更新检查时间
 		s.lastCheckTime = time.Now()
+		return
+	}
+
+	s.sendDeferred(ctx)
+}
+
+// sendDeferred 向维护窗口已开启（或已紧急放行）的暂缓 Agent 补发插件配置
+// 只处理本实例持有连接的 Agent；已断开的 Agent 重连时会同步配置并删除暂缓记录
+func (s *PluginUpdateScheduler) sendDeferred(ctx context.Context) {
+	// 主机已删除的暂缓记录不再补发
+	if err := s.db.Where("host_id NOT IN (?)", s.db.Model(&model.Host{}).Select("host_id")).
+		Delete(&model.PluginConfigDeferral{}).Error; err != nil {
+		s.logger.Error("清理已删除主机的插件配置暂缓记录失败", zap.Error(err))
+	}
+
+	online := s.transferService.GetOnlineAgentIDs()
+	if len(online) == 0 {
+		return
+	}
+	var agentIDs []string
+	if err := s.db.Model(&model.PluginConfigDeferral{}).
+		Where("host_id IN ?", online).Pluck("host_id", &agentIDs).Error; err != nil {
+		s.logger.Error("查询插件配置暂缓记录失败", zap.Error(err))
+		return
+	}
+	if len(agentIDs) == 0 {
+		return
+	}
+
+	successCount, failedAgents, err := s.broadcast(ctx, agentIDs)
+	if err != nil {
+		s.logger.Error("补发插件配置失败", zap.Error(err))
+		return
+	}
+	if successCount > 0 || len(failedAgents) > 0 {
+		s.logger.Info("维护窗口开启，已补发插件配置",
+			zap.Int("success_count", successCount),
+			zap.Strings("failed_agents", failedAgents))
+	}
+}
+
+// broadcast 向维护窗口允许变更（或已紧急放行）的 Agent 发送插件配置，其余 Agent 记入暂缓表
+// 发送成功的 Agent 删除暂缓记录，发送失败的保留记录等待下次补发
+func (s *PluginUpdateScheduler) broadcast(ctx context.Context, agentIDs []string) (int, []string, error) {
+	if len(agentIDs) == 0 {
+		return 0, nil, nil
 	}
+	gate, err := maintenance.Load(s.db, time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	allowed := agentIDs
+	if gate.Restricted() {
+		var hosts []model.Host
+		if err := s.db.Where("host_id IN ?", agentIDs).Find(&hosts).Error; err != nil {
+			return 0, nil, err
+		}
+		hostMap := make(map[string]*model.Host, len(hosts))
+		for i := range hosts {
+			hostMap[hosts[i].HostID] = &hosts[i]
+		}
+		var overridden []string
+		if err := s.db.Model(&model.PluginConfigDeferral{}).
+			Where("host_id IN ? AND emergency_reason <> ''", agentIDs).
+			Pluck("host_id", &overridden).Error; err != nil {
+			return 0, nil, err
+		}
+
+		allowed = make([]string, 0, len(agentIDs))
+		var deferrals []model.PluginConfigDeferral
+		for _, agentID := range agentIDs {
+			if host, ok := hostMap[agentID]; ok && !slices.Contains(overridden, agentID) {
+				if reason := gate.Check(host); reason != "" {
+					deferrals = append(deferrals, model.PluginConfigDeferral{HostID: agentID, Reason: reason})
+					s.logger.Debug("主机不在维护窗口内，暂缓发送插件配置",
+						zap.String("agent_id", agentID),
+						zap.String("reason", reason))
+					continue
+				}
+			}
+			allowed = append(allowed, agentID)
+		}
+		if len(deferrals) > 0 {
+			if err := s.db.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "host_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"reason", "updated_at"}),
+			}).Create(&deferrals).Error; err != nil {
+				return 0, nil, fmt.Errorf("记录插件配置暂缓主机失败: %w", err)
+			}
+		}
+	}
+
+	if len(allowed) == 0 {
+		return 0, nil, nil
+	}
+	successCount, failedAgents, err := s.transferService.SendPluginConfigs(ctx, allowed)
+	if err != nil {
+		return 0, nil, err
+	}
+	sent := make([]string, 0, len(allowed))
+	for _, agentID := range allowed {
+		if !slices.Contains(failedAgents, agentID) {
+			sent = append(sent, agentID)
+		}
+	}
+	if len(sent) > 0 {
+		if err := s.db.Where("host_id IN ?", sent).Delete(&model.PluginConfigDeferral{}).Error; err != nil {
+			s.logger.Error("删除插件配置暂缓记录失败", zap.Error(err))
+		}
+	}
+	return successCount, failedAgents, nil
 }
 
 // TriggerBroadcast 手动触发广播（供 API 调用）
@@ -95,7 +219,7 @@ func (s *PluginUpdateScheduler) TriggerBroadcast(ctx context.Context) (int, []st
 
 	s.logger.Info("手动触发插件配置广播")
 
-	successCount, failedAgents, err := s.transferService.BroadcastPluginConfigs(ctx)
+	successCount, failedAgents, err := s.broadcast(ctx, s.transferService.GetOnlineAgentIDs())
 	if err != nil {
 		return 0, nil, err
 	}
@@ -0,0 +1,121 @@
+//go:build integration
+// +build integration
+
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// fakePluginSender 模拟本实例持有连接的 Agent
+type fakePluginSender struct {
+	online []string
+	sent   []string
+}
+
+func (s *fakePluginSender) GetOnlineAgentIDs() []string {
+	return s.online
+}
+
+func (s *fakePluginSender) SendPluginConfigs(ctx context.Context, agentIDs []string) (int, []string, error) {
+	var failed []string
+	for _, id := range agentIDs {
+		if slices.Contains(s.online, id) {
+			s.sent = append(s.sent, id)
+		} else {
+			failed = append(failed, id)
+		}
+	}
+	return len(agentIDs) - len(failed), failed, nil
+}
+
+// TestPluginConfigDeferral 测试变更冻结期间插件配置暂缓记录持久化，紧急放行后补发并删除，
+// 其他实例持有连接的 Agent 不受影响
+func TestPluginConfigDeferral(t *testing.T) {
+	db := testdb.Open(t, &model.Host{}, &model.PluginConfigDeferral{}, &model.MaintenanceWindow{}, &model.ChangeFreeze{})
+
+	for _, id := range []string{"host-1", "host-2", "host-remote"} {
+		if err := db.Create(&model.Host{HostID: id, Hostname: id, Status: model.HostStatusOnline}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	freeze := model.ChangeFreeze{Name: "封网", StartAt: model.ToLocalTime(now.Add(-time.Hour)),
+		EndAt: model.ToLocalTime(now.Add(time.Hour)), Enabled: true}
+	if err := db.Create(&freeze).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 其他实例记录的暂缓主机
+	if err := db.Create(&model.PluginConfigDeferral{HostID: "host-remote", Reason: "变更冻结中"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &fakePluginSender{online: []string{"host-1", "host-2"}}
+	s := NewPluginUpdateScheduler(db, sender, zap.NewNop())
+	ctx := context.Background()
+
+	success, _, err := s.broadcast(ctx, sender.online)
+	if err != nil {
+		t.Fatalf("broadcast failed: %v", err)
+	}
+	if success != 0 || len(sender.sent) != 0 {
+		t.Fatalf("frozen broadcast sent to %v", sender.sent)
+	}
+	assertDeferrals(t, db, "host-1", "host-2", "host-remote")
+
+	// 调度器重建（进程重启）后暂缓记录仍在，冻结期间补发不发送
+	s = NewPluginUpdateScheduler(db, sender, zap.NewNop())
+	s.sendDeferred(ctx)
+	if len(sender.sent) != 0 {
+		t.Fatalf("deferred sent during freeze: %v", sender.sent)
+	}
+
+	// 紧急放行 host-1 后立即补发
+	if err := db.Model(&model.PluginConfigDeferral{}).Where("host_id = ?", "host-1").
+		Update("emergency_reason", "紧急修复").Error; err != nil {
+		t.Fatal(err)
+	}
+	s.sendDeferred(ctx)
+	if !slices.Equal(sender.sent, []string{"host-1"}) {
+		t.Fatalf("sent = %v, want [host-1]", sender.sent)
+	}
+	assertDeferrals(t, db, "host-2", "host-remote")
+
+	// 冻结解除后补发本实例的 Agent，其他实例的记录保留
+	if err := db.Model(&freeze).Update("enabled", false).Error; err != nil {
+		t.Fatal(err)
+	}
+	s.sendDeferred(ctx)
+	if !slices.Equal(sender.sent, []string{"host-1", "host-2"}) {
+		t.Fatalf("sent = %v, want [host-1 host-2]", sender.sent)
+	}
+	assertDeferrals(t, db, "host-remote")
+
+	// 主机删除后记录被清理
+	if err := db.Where("host_id = ?", "host-remote").Delete(&model.Host{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	s.sendDeferred(ctx)
+	assertDeferrals(t, db)
+}
+
+// assertDeferrals 校验暂缓记录的主机（按 host_id 排序）
+func assertDeferrals(t *testing.T, db *gorm.DB, want ...string) {
+	t.Helper()
+	var got []string
+	if err := db.Model(&model.PluginConfigDeferral{}).Order("host_id").Pluck("host_id", &got).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("deferrals = %v, want %v", got, want)
+	}
+}
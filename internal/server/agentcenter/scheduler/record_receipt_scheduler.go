@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// recordReceiptRetention 是记录回执的保留时间，与 Agent outbox 的最长保留时间一致，
+// 超过该时间的记录不会再被 Agent 重发
+const recordReceiptRetention = 7 * 24 * time.Hour
+
+// StartRecordReceiptCleanup 启动记录回执清理任务（每小时清理一次过期回执）
+func StartRecordReceiptCleanup(ctx context.Context, db *gorm.DB, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	logger.Info("记录回执清理任务已启动", zap.Duration("retention", recordReceiptRetention))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deadline := model.ToLocalTime(time.Now().Add(-recordReceiptRetention))
+			result := db.Where("received_at < ?", deadline).Delete(&model.RecordReceipt{})
+			if result.Error != nil {
+				logger.Error("清理记录回执失败", zap.Error(result.Error))
+				continue
+			}
+			if result.RowsAffected > 0 {
+				logger.Debug("已清理过期记录回执", zap.Int64("count", result.RowsAffected))
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package scheduler
 
 import (
+	"context"
 	"time"
 
 	"go.uber.org/zap"
@@ -14,7 +15,7 @@ import (
 const taskDispatchInterval = 5 * time.Second
 
 // StartTaskScheduler 启动任务调度器（定期分发待执行任务）
-func StartTaskScheduler(taskService *service.TaskService, transferService *transfer.Service, logger *zap.Logger) {
+func StartTaskScheduler(ctx context.Context, taskService *service.TaskService, transferService *transfer.Service, logger *zap.Logger) {
 	ticker := time.NewTicker(taskDispatchInterval)
 	defer ticker.Stop()
 
@@ -24,8 +25,14 @@ func StartTaskScheduler(taskService *service.TaskService, transferService *trans
 	dispatchAllPendingTasks(taskService, transferService, logger)
 
 	// 定时执行
-	for range ticker.C {
-		dispatchAllPendingTasks(taskService, transferService, logger)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("任务调度器已停止")
+			return
+		case <-ticker.C:
+			dispatchAllPendingTasks(taskService, transferService, logger)
+		}
 	}
 }
 
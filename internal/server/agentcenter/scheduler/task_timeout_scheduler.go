@@ -2,6 +2,7 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -13,7 +14,7 @@ import (
 
 // StartTaskTimeoutScheduler 启动任务超时调度器
 // 每分钟检查一次 pending 和 running 状态的任务是否超时
-func StartTaskTimeoutScheduler(db *gorm.DB, logger *zap.Logger) {
+func StartTaskTimeoutScheduler(ctx context.Context, db *gorm.DB, logger *zap.Logger) {
 	ticker := time.NewTicker(1 * time.Minute) // 每分钟检查一次
 	defer ticker.Stop()
 
@@ -23,8 +24,14 @@ func StartTaskTimeoutScheduler(db *gorm.DB, logger *zap.Logger) {
 	checkTimeoutTasks(db, logger)
 
 	// 定时执行
-	for range ticker.C {
-		checkTimeoutTasks(db, logger)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("任务超时调度器已停止")
+			return
+		case <-ticker.C:
+			checkTimeoutTasks(db, logger)
+		}
 	}
 }
 
@@ -193,6 +200,7 @@ const fixTaskTimeoutMinutes = 15
 
 // checkFixTasksTimeout 检查修复任务超时
 // 如果修复任务在 running 状态超过 15 分钟，根据已有结果决定最终状态
+// 有主机等待维护窗口的任务不超时；窗口开启后下发的主机从最后一次下发时间开始计时
 func checkFixTasksTimeout(db *gorm.DB, logger *zap.Logger) {
 	var tasks []model.FixTask
 	if err := db.Where("status IN ?", []string{
@@ -215,6 +223,22 @@ func checkFixTasksTimeout(db *gorm.DB, logger *zap.Logger) {
 			continue // 未超时
 		}
 
+		var waitingCount int64
+		db.Model(&model.FixTaskHostStatus{}).
+			Where("task_id = ? AND status = ?", task.TaskID, model.FixTaskHostStatusWaiting).
+			Count(&waitingCount)
+		if waitingCount > 0 {
+			continue // 等待维护窗口
+		}
+		var lastDispatched *time.Time
+		db.Model(&model.FixTaskHostStatus{}).
+			Where("task_id = ?", task.TaskID).
+			Select("MAX(dispatched_at)").
+			Scan(&lastDispatched)
+		if lastDispatched != nil && lastDispatched.After(deadline) {
+			continue // 窗口开启后下发的主机未超时
+		}
+
 		completedAt := model.Now()
 
 		if task.SuccessCount+task.FailedCount > 0 {
@@ -13,6 +13,8 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 
+	// 注册 Transfer 通道的 snappy / zstd 压缩算法（Agent 按配置选择）
+	_ "github.com/imkerbos/mxsec-platform/internal/compress"
 	"github.com/imkerbos/mxsec-platform/internal/server/config"
 )
 
@@ -77,6 +79,7 @@ func CreateGRPCServer(cfg *config.Config, logger *zap.Logger) (*grpc.Server, err
 	opts = append(opts,
 		grpc.KeepaliveParams(keepaliveParams),
 		grpc.KeepaliveEnforcementPolicy(keepaliveEnforcementPolicy),
+		grpc.StatsHandler(&transferStatsHandler{}),
 	)
 
 	logger.Info("gRPC Server keepalive 配置",
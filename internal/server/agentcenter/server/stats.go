@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/stats"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/metrics"
+)
+
+// compressionKey 是 RPC context 中保存压缩算法的键
+type compressionKey struct{}
+
+// transferStatsHandler 统计 Agent 上报消息压缩前后的字节数
+type transferStatsHandler struct{}
+
+// TagRPC 为每个 RPC 附加压缩算法占位（收到请求头后填充）
+func (h *transferStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, compressionKey{}, new(atomic.Value))
+}
+
+// HandleRPC 记录请求头中的压缩算法和每条入站消息的字节数
+func (h *transferStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	holder, _ := ctx.Value(compressionKey{}).(*atomic.Value)
+	if holder == nil {
+		return
+	}
+	switch st := s.(type) {
+	case *stats.InHeader:
+		holder.Store(st.Compression)
+	case *stats.InPayload:
+		compression, _ := holder.Load().(string)
+		metrics.RecordTransferPayload(compression, st.CompressedLength, st.Length)
+	}
+}
+
+// TagConn 不做处理
+func (h *transferStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn 不做处理
+func (h *transferStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// AlertSyncResult 是一批检查结果同步告警的结果
+type AlertSyncResult struct {
+	Created  []*model.Alert // 新建的告警
+	Resolved []*model.Alert // 自动解决的告警
+	Updated  int            // 刷新或重新激活的告警数
+}
+
+// SyncBaselineAlerts 根据一批检查结果创建、更新或恢复告警（在线上报与离线导入共用）
+// fail 结果创建新告警或刷新已有告警（已解决/忽略的重新激活），pass 结果自动解决活跃告警，
+// resolveReason 记录为自动解决原因。写入在 tx 中执行，不发送通知，由调用方在事务提交后处理
+func SyncBaselineAlerts(tx *gorm.DB, results []*model.ScanResult, batchSize int, resolveReason string) (*AlertSyncResult, error) {
+	sync := &AlertSyncResult{}
+	resultIDs := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Status == model.ResultStatusFail || r.Status == model.ResultStatusPass {
+			resultIDs = append(resultIDs, r.ResultID)
+		}
+	}
+	if len(resultIDs) == 0 {
+		return sync, nil
+	}
+
+	var alerts []model.Alert
+	if err := tx.Where("result_id IN ?", resultIDs).Find(&alerts).Error; err != nil {
+		return nil, fmt.Errorf("查询告警失败: %w", err)
+	}
+	alertByResult := make(map[string]*model.Alert, len(alerts))
+	for i := range alerts {
+		alertByResult[alerts[i].ResultID] = &alerts[i]
+	}
+
+	now := model.Now()
+	var seenIDs, reactivateIDs, resolveIDs []uint
+	for _, r := range results {
+		alert := alertByResult[r.ResultID]
+		switch r.Status {
+		case model.ResultStatusFail:
+			if alert == nil {
+				sync.Created = append(sync.Created, &model.Alert{
+					ResultID:      r.ResultID,
+					HostID:        r.HostID,
+					RuleID:        r.RuleID,
+					PolicyID:      r.PolicyID,
+					Severity:      r.Severity,
+					Category:      r.Category,
+					Title:         r.Title,
+					Actual:        r.Actual,
+					Expected:      r.Expected,
+					FixSuggestion: r.FixSuggestion,
+					Status:        model.AlertStatusActive,
+					FirstSeenAt:   now,
+					LastSeenAt:    now,
+				})
+			} else if alert.Status == model.AlertStatusActive {
+				seenIDs = append(seenIDs, alert.ID)
+			} else {
+				reactivateIDs = append(reactivateIDs, alert.ID)
+			}
+		case model.ResultStatusPass:
+			if alert != nil && alert.Status == model.AlertStatusActive {
+				resolveIDs = append(resolveIDs, alert.ID)
+				sync.Resolved = append(sync.Resolved, alert)
+			}
+		}
+	}
+	sync.Updated = len(seenIDs) + len(reactivateIDs)
+
+	if len(sync.Created) > 0 {
+		if err := tx.Omit(clause.Associations).CreateInBatches(sync.Created, batchSize).Error; err != nil {
+			return nil, fmt.Errorf("创建告警失败: %w", err)
+		}
+	}
+	if len(seenIDs) > 0 {
+		if err := tx.Model(&model.Alert{}).Where("id IN ?", seenIDs).
+			Update("last_seen_at", now).Error; err != nil {
+			return nil, fmt.Errorf("更新告警失败: %w", err)
+		}
+	}
+	if len(reactivateIDs) > 0 {
+		// 已被解决或忽略的告警重新激活
+		if err := tx.Model(&model.Alert{}).Where("id IN ?", reactivateIDs).Updates(map[string]interface{}{
+			"status":         model.AlertStatusActive,
+			"last_seen_at":   now,
+			"resolved_at":    nil,
+			"resolved_by":    "",
+			"resolve_reason": "",
+		}).Error; err != nil {
+			return nil, fmt.Errorf("更新告警失败: %w", err)
+		}
+	}
+	if len(resolveIDs) > 0 {
+		if err := tx.Model(&model.Alert{}).Where("id IN ?", resolveIDs).Updates(map[string]interface{}{
+			"status":         model.AlertStatusResolved,
+			"resolved_at":    &now,
+			"resolved_by":    "system", // 系统自动解决
+			"resolve_reason": resolveReason,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("更新告警状态失败: %w", err)
+		}
+	}
+	return sync, nil
+}
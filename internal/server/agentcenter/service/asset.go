@@ -36,13 +36,19 @@ type AssetService struct {
 	db        *gorm.DB
 	logger    *zap.Logger
 	hostLocks sync.Map // per-host 互斥锁，避免同一主机并发写入导致 MySQL 锁竞争
+
+	// 分块快照重组（key: host_id|data_type|snapshot_id）
+	assemblies map[string]*snapshotAssembly
+	asmMu      sync.Mutex
+	ackSender  SnapshotAckSender
 }
 
 // NewAssetService 创建资产服务
 func NewAssetService(db *gorm.DB, logger *zap.Logger) *AssetService {
 	return &AssetService{
-		db:     db,
-		logger: logger,
+		db:         db,
+		logger:     logger,
+		assemblies: make(map[string]*snapshotAssembly),
 	}
 }
 
@@ -71,30 +77,39 @@ func (s *AssetService) HandleAssetData(hostID string, dataType int32, data []byt
 		return fmt.Errorf("missing data field in payload")
 	}
 
-	// 根据 data_type 路由到不同的处理器
+	// 分块快照：收齐所有分块后再应用
+	if _, ok := bridgeRecord.Data.Fields["snapshot_id"]; ok {
+		return s.handleSnapshotChunk(hostID, dataType, bridgeRecord.Data.Fields)
+	}
+
+	return s.applyAssetData(s.db, hostID, dataType, jsonData)
+}
+
+// applyAssetData 根据 data_type 将资产数据写入对应的表（upsert），db 可以是事务
+func (s *AssetService) applyAssetData(db *gorm.DB, hostID string, dataType int32, jsonData string) error {
 	switch dataType {
 	case 5050: // 进程数据
-		return s.handleProcessData(hostID, jsonData)
+		return s.handleProcessData(db, hostID, jsonData)
 	case 5051: // 端口数据
-		return s.handlePortData(hostID, jsonData)
+		return s.handlePortData(db, hostID, jsonData)
 	case 5052: // 账户数据
-		return s.handleUserData(hostID, jsonData)
+		return s.handleUserData(db, hostID, jsonData)
 	case 5053: // 软件包数据
-		return s.handleSoftwareData(hostID, jsonData)
+		return s.handleSoftwareData(db, hostID, jsonData)
 	case 5054: // 容器数据
-		return s.handleContainerData(hostID, jsonData)
+		return s.handleContainerData(db, hostID, jsonData)
 	case 5055: // 应用数据
-		return s.handleAppData(hostID, jsonData)
+		return s.handleAppData(db, hostID, jsonData)
 	case 5056: // 网络接口数据
-		return s.handleNetInterfaceData(hostID, jsonData)
+		return s.handleNetInterfaceData(db, hostID, jsonData)
 	case 5057: // 磁盘数据
-		return s.handleVolumeData(hostID, jsonData)
+		return s.handleVolumeData(db, hostID, jsonData)
 	case 5058: // 内核模块数据
-		return s.handleKmodData(hostID, jsonData)
+		return s.handleKmodData(db, hostID, jsonData)
 	case 5059: // 系统服务数据
-		return s.handleServiceData(hostID, jsonData)
+		return s.handleServiceData(db, hostID, jsonData)
 	case 5060: // 定时任务数据
-		return s.handleCronData(hostID, jsonData)
+		return s.handleCronData(db, hostID, jsonData)
 	default:
 		s.logger.Warn("unknown asset data type",
 			zap.Int32("data_type", dataType),
@@ -104,7 +119,7 @@ func (s *AssetService) HandleAssetData(hostID string, dataType int32, data []byt
 }
 
 // handleProcessData 处理进程数据
-func (s *AssetService) handleProcessData(hostID, jsonData string) error {
+func (s *AssetService) handleProcessData(db *gorm.DB, hostID, jsonData string) error {
 	// 解析 JSON 数据（可能是数组）
 	var assets []engine.ProcessAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
@@ -116,31 +131,47 @@ func (s *AssetService) handleProcessData(hostID, jsonData string) error {
 		assets = []engine.ProcessAsset{asset}
 	}
 
+	rows := make([]*model.Process, 0, len(assets))
 	for _, asset := range assets {
 		process := &model.Process{
-			ID:          shortHash(hostID, asset.PID),
-			HostID:      hostID,
-			PID:         asset.PID,
-			PPID:        asset.PPID,
-			Cmdline:     asset.Cmdline,
-			Exe:         asset.Exe,
-			ExeHash:     asset.ExeHash,
-			ContainerID: asset.ContainerID,
-			UID:         asset.UID,
-			GID:         asset.GID,
-			Username:    asset.Username,
-			Groupname:   asset.Groupname,
-			CollectedAt: model.ToLocalTime(asset.CollectedAt),
+			ID:           shortHash(hostID, asset.PID),
+			HostID:       hostID,
+			PID:          asset.PID,
+			PPID:         asset.PPID,
+			Cmdline:      asset.Cmdline,
+			Exe:          asset.Exe,
+			ExeHash:      asset.ExeHash,
+			ContainerID:  asset.ContainerID,
+			UID:          asset.UID,
+			GID:          asset.GID,
+			Username:     asset.Username,
+			Groupname:    asset.Groupname,
+			Cwd:          asset.Cwd,
+			ExeInode:     asset.ExeInode,
+			ExeDev:       asset.ExeDev,
+			ExeDeleted:   asset.ExeDeleted,
+			ExeMemfd:     asset.ExeMemfd,
+			CapInh:       asset.CapInh,
+			CapPrm:       asset.CapPrm,
+			CapEff:       asset.CapEff,
+			CapBnd:       asset.CapBnd,
+			CapAmb:       asset.CapAmb,
+			Namespaces:   model.StringMap(asset.Namespaces),
+			PackageName:  asset.PackageName,
+			ExeIntegrity: asset.ExeIntegrity,
+			Suspicious:   model.StringArray(asset.Suspicious),
+			CollectedAt:  model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(process).Error; err != nil {
-			s.logger.Warn("failed to upsert process",
-				zap.String("host_id", hostID),
-				zap.String("pid", asset.PID),
-				zap.Error(err))
-			continue
+		if process.Suspicious == nil {
+			process.Suspicious = model.StringArray{}
 		}
+		if !asset.StartTime.IsZero() {
+			startTime := model.ToLocalTime(asset.StartTime)
+			process.StartTime = &startTime
+		}
+		rows = append(rows, process)
 	}
+	upsertAssetRows(s, db, hostID, "process", rows)
 
 	s.logger.Debug("processed process data",
 		zap.String("host_id", hostID),
@@ -150,7 +181,7 @@ func (s *AssetService) handleProcessData(hostID, jsonData string) error {
 }
 
 // handlePortData 处理端口数据
-func (s *AssetService) handlePortData(hostID, jsonData string) error {
+func (s *AssetService) handlePortData(db *gorm.DB, hostID, jsonData string) error {
 	// 解析 JSON 数据（可能是数组）
 	var assets []engine.PortAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
@@ -165,6 +196,7 @@ func (s *AssetService) handlePortData(hostID, jsonData string) error {
 	// 直接 UPSERT，不再 DELETE+INSERT
 	// ID 由 shortHash(hostID, protocol, port) 确定性生成，OnConflict 天然去重
 	// 避免 DELETE 产生的 gap lock 导致并发 Lock wait timeout
+	rows := make([]*model.Port, 0, len(assets))
 	for _, asset := range assets {
 		port := &model.Port{
 			ID:          shortHash(hostID, asset.Protocol, fmt.Sprintf("%d", asset.Port)),
@@ -177,16 +209,9 @@ func (s *AssetService) handlePortData(hostID, jsonData string) error {
 			ContainerID: asset.ContainerID,
 			CollectedAt: model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(port).Error; err != nil {
-			s.logger.Warn("failed to upsert port",
-				zap.String("host_id", hostID),
-				zap.String("protocol", asset.Protocol),
-				zap.Int("port", asset.Port),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, port)
 	}
+	upsertAssetRows(s, db, hostID, "port", rows)
 
 	s.logger.Debug("processed port data",
 		zap.String("host_id", hostID),
@@ -196,7 +221,7 @@ func (s *AssetService) handlePortData(hostID, jsonData string) error {
 }
 
 // handleUserData 处理账户数据
-func (s *AssetService) handleUserData(hostID, jsonData string) error {
+func (s *AssetService) handleUserData(db *gorm.DB, hostID, jsonData string) error {
 	// 解析 JSON 数据（可能是数组）
 	var assets []engine.UserAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
@@ -209,6 +234,7 @@ func (s *AssetService) handleUserData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT，不再 DELETE+INSERT
+	rows := make([]*model.AssetUser, 0, len(assets))
 	for _, asset := range assets {
 		user := &model.AssetUser{
 			ID:          shortHash(hostID, asset.Username),
@@ -223,15 +249,9 @@ func (s *AssetService) handleUserData(hostID, jsonData string) error {
 			HasPassword: asset.HasPassword,
 			CollectedAt: model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(user).Error; err != nil {
-			s.logger.Warn("failed to upsert user",
-				zap.String("host_id", hostID),
-				zap.String("username", asset.Username),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, user)
 	}
+	upsertAssetRows(s, db, hostID, "user", rows)
 
 	s.logger.Debug("processed user data",
 		zap.String("host_id", hostID),
@@ -241,7 +261,7 @@ func (s *AssetService) handleUserData(hostID, jsonData string) error {
 }
 
 // handleSoftwareData 处理软件包数据
-func (s *AssetService) handleSoftwareData(hostID, jsonData string) error {
+func (s *AssetService) handleSoftwareData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.SoftwareAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.SoftwareAsset
@@ -252,6 +272,7 @@ func (s *AssetService) handleSoftwareData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.Software, 0, len(assets))
 	for _, asset := range assets {
 		software := &model.Software{
 			ID:           shortHash(hostID, asset.PackageType, asset.Name),
@@ -264,15 +285,9 @@ func (s *AssetService) handleSoftwareData(hostID, jsonData string) error {
 			InstallTime:  asset.InstallTime,
 			CollectedAt:  model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(software).Error; err != nil {
-			s.logger.Warn("failed to upsert software",
-				zap.String("host_id", hostID),
-				zap.String("name", asset.Name),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, software)
 	}
+	upsertAssetRows(s, db, hostID, "software", rows)
 
 	s.logger.Debug("processed software data",
 		zap.String("host_id", hostID),
@@ -282,7 +297,7 @@ func (s *AssetService) handleSoftwareData(hostID, jsonData string) error {
 }
 
 // handleContainerData 处理容器数据
-func (s *AssetService) handleContainerData(hostID, jsonData string) error {
+func (s *AssetService) handleContainerData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.ContainerAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.ContainerAsset
@@ -293,6 +308,7 @@ func (s *AssetService) handleContainerData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.Container, 0, len(assets))
 	for _, asset := range assets {
 		container := &model.Container{
 			ID:            shortHash(hostID, asset.ContainerID),
@@ -305,16 +321,21 @@ func (s *AssetService) handleContainerData(hostID, jsonData string) error {
 			Status:        asset.Status,
 			CreatedAt:     asset.CreatedAt,
 			CollectedAt:   model.ToLocalTime(asset.CollectedAt),
-		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(container).Error; err != nil {
-			s.logger.Warn("failed to upsert container",
-				zap.String("host_id", hostID),
-				zap.String("container_id", asset.ContainerID),
-				zap.Error(err))
-			continue
-		}
+			PID:           asset.PID,
+			PodName:       asset.PodName,
+			PodNamespace:  asset.PodNamespace,
+			PodUID:        asset.PodUID,
+			Labels:        model.StringMap(asset.Labels),
+			Privileged:    asset.Privileged,
+			HostNetwork:   asset.HostNetwork,
+			HostPID:       asset.HostPID,
+			HostIPC:       asset.HostIPC,
+			Capabilities:  model.StringArray(asset.Capabilities),
+			HostMounts:    model.StringArray(asset.HostMounts),
+		}
+		rows = append(rows, container)
 	}
+	upsertAssetRows(s, db, hostID, "container", rows)
 
 	s.logger.Debug("processed container data",
 		zap.String("host_id", hostID),
@@ -324,7 +345,7 @@ func (s *AssetService) handleContainerData(hostID, jsonData string) error {
 }
 
 // handleAppData 处理应用数据
-func (s *AssetService) handleAppData(hostID, jsonData string) error {
+func (s *AssetService) handleAppData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.AppAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.AppAsset
@@ -335,6 +356,7 @@ func (s *AssetService) handleAppData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.App, 0, len(assets))
 	for _, asset := range assets {
 		app := &model.App{
 			ID:          shortHash(hostID, asset.AppType, asset.AppName),
@@ -348,16 +370,9 @@ func (s *AssetService) handleAppData(hostID, jsonData string) error {
 			DataPath:    asset.DataPath,
 			CollectedAt: model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(app).Error; err != nil {
-			s.logger.Warn("failed to upsert app",
-				zap.String("host_id", hostID),
-				zap.String("app_type", asset.AppType),
-				zap.String("app_name", asset.AppName),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, app)
 	}
+	upsertAssetRows(s, db, hostID, "app", rows)
 
 	s.logger.Debug("processed app data",
 		zap.String("host_id", hostID),
@@ -367,7 +382,7 @@ func (s *AssetService) handleAppData(hostID, jsonData string) error {
 }
 
 // handleNetInterfaceData 处理网络接口数据
-func (s *AssetService) handleNetInterfaceData(hostID, jsonData string) error {
+func (s *AssetService) handleNetInterfaceData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.NetInterfaceAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.NetInterfaceAsset
@@ -378,6 +393,7 @@ func (s *AssetService) handleNetInterfaceData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.NetInterface, 0, len(assets))
 	for _, asset := range assets {
 		netInterface := &model.NetInterface{
 			ID:            shortHash(hostID, asset.InterfaceName),
@@ -390,15 +406,9 @@ func (s *AssetService) handleNetInterfaceData(hostID, jsonData string) error {
 			State:         asset.State,
 			CollectedAt:   model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(netInterface).Error; err != nil {
-			s.logger.Warn("failed to upsert network interface",
-				zap.String("host_id", hostID),
-				zap.String("interface_name", asset.InterfaceName),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, netInterface)
 	}
+	upsertAssetRows(s, db, hostID, "network interface", rows)
 
 	s.logger.Debug("processed network interface data",
 		zap.String("host_id", hostID),
@@ -408,7 +418,7 @@ func (s *AssetService) handleNetInterfaceData(hostID, jsonData string) error {
 }
 
 // handleVolumeData 处理磁盘数据
-func (s *AssetService) handleVolumeData(hostID, jsonData string) error {
+func (s *AssetService) handleVolumeData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.VolumeAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.VolumeAsset
@@ -419,6 +429,7 @@ func (s *AssetService) handleVolumeData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.Volume, 0, len(assets))
 	for _, asset := range assets {
 		volume := &model.Volume{
 			ID:            shortHash(hostID, asset.MountPoint),
@@ -432,15 +443,9 @@ func (s *AssetService) handleVolumeData(hostID, jsonData string) error {
 			UsagePercent:  asset.UsagePercent,
 			CollectedAt:   model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(volume).Error; err != nil {
-			s.logger.Warn("failed to upsert volume",
-				zap.String("host_id", hostID),
-				zap.String("mount_point", asset.MountPoint),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, volume)
 	}
+	upsertAssetRows(s, db, hostID, "volume", rows)
 
 	s.logger.Debug("processed volume data",
 		zap.String("host_id", hostID),
@@ -450,7 +455,7 @@ func (s *AssetService) handleVolumeData(hostID, jsonData string) error {
 }
 
 // handleKmodData 处理内核模块数据
-func (s *AssetService) handleKmodData(hostID, jsonData string) error {
+func (s *AssetService) handleKmodData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.KmodAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.KmodAsset
@@ -461,6 +466,7 @@ func (s *AssetService) handleKmodData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.Kmod, 0, len(assets))
 	for _, asset := range assets {
 		kmod := &model.Kmod{
 			ID:          shortHash(hostID, asset.ModuleName),
@@ -471,15 +477,9 @@ func (s *AssetService) handleKmodData(hostID, jsonData string) error {
 			State:       asset.State,
 			CollectedAt: model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(kmod).Error; err != nil {
-			s.logger.Warn("failed to upsert kernel module",
-				zap.String("host_id", hostID),
-				zap.String("module_name", asset.ModuleName),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, kmod)
 	}
+	upsertAssetRows(s, db, hostID, "kernel module", rows)
 
 	s.logger.Debug("processed kernel module data",
 		zap.String("host_id", hostID),
@@ -489,7 +489,7 @@ func (s *AssetService) handleKmodData(hostID, jsonData string) error {
 }
 
 // handleServiceData 处理系统服务数据
-func (s *AssetService) handleServiceData(hostID, jsonData string) error {
+func (s *AssetService) handleServiceData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.ServiceAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.ServiceAsset
@@ -500,6 +500,7 @@ func (s *AssetService) handleServiceData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.Service, 0, len(assets))
 	for _, asset := range assets {
 		svc := &model.Service{
 			ID:          shortHash(hostID, asset.ServiceName),
@@ -511,15 +512,9 @@ func (s *AssetService) handleServiceData(hostID, jsonData string) error {
 			Description: asset.Description,
 			CollectedAt: model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(svc).Error; err != nil {
-			s.logger.Warn("failed to upsert service",
-				zap.String("host_id", hostID),
-				zap.String("service_name", asset.ServiceName),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, svc)
 	}
+	upsertAssetRows(s, db, hostID, "service", rows)
 
 	s.logger.Debug("processed service data",
 		zap.String("host_id", hostID),
@@ -529,7 +524,7 @@ func (s *AssetService) handleServiceData(hostID, jsonData string) error {
 }
 
 // handleCronData 处理定时任务数据
-func (s *AssetService) handleCronData(hostID, jsonData string) error {
+func (s *AssetService) handleCronData(db *gorm.DB, hostID, jsonData string) error {
 	var assets []engine.CronAsset
 	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
 		var asset engine.CronAsset
@@ -540,6 +535,7 @@ func (s *AssetService) handleCronData(hostID, jsonData string) error {
 	}
 
 	// 直接 UPSERT
+	rows := make([]*model.Cron, 0, len(assets))
 	for _, asset := range assets {
 		cron := &model.Cron{
 			// 使用哈希 ID 避免 {hostID}-{user}-{schedule} 超过 varchar(128)
@@ -552,16 +548,9 @@ func (s *AssetService) handleCronData(hostID, jsonData string) error {
 			Enabled:     asset.Enabled,
 			CollectedAt: model.ToLocalTime(asset.CollectedAt),
 		}
-
-		if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(cron).Error; err != nil {
-			s.logger.Warn("failed to upsert cron",
-				zap.String("host_id", hostID),
-				zap.String("user", asset.User),
-				zap.String("schedule", asset.Schedule),
-				zap.Error(err))
-			continue
-		}
+		rows = append(rows, cron)
 	}
+	upsertAssetRows(s, db, hostID, "cron", rows)
 
 	s.logger.Debug("processed cron data",
 		zap.String("host_id", hostID),
@@ -569,3 +558,30 @@ func (s *AssetService) handleCronData(hostID, jsonData string) error {
 
 	return nil
 }
+
+// assetUpsertBatchSize 是资产表批量 upsert 的单批行数
+const assetUpsertBatchSize = 200
+
+// upsertAssetRows 批量 upsert 资产行；批量写入失败时逐行写入，跳过个别异常行
+func upsertAssetRows[T any](s *AssetService, db *gorm.DB, hostID, kind string, rows []*T) {
+	if len(rows) == 0 {
+		return
+	}
+	err := db.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows, assetUpsertBatchSize).Error
+	if err == nil {
+		return
+	}
+
+	s.logger.Warn("batch upsert failed, retrying row by row",
+		zap.String("host_id", hostID),
+		zap.String("kind", kind),
+		zap.Int("count", len(rows)),
+		zap.Error(err))
+	for _, row := range rows {
+		if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(row).Error; err != nil {
+			s.logger.Warn("failed to upsert "+kind,
+				zap.String("host_id", hostID),
+				zap.Error(err))
+		}
+	}
+}
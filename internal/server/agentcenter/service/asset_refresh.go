@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// HandleCollectComplete 处理按需采集完成信号（DataType 5099）
+func (s *AssetService) HandleCollectComplete(hostID string, data []byte) error {
+	record := &bridge.Record{}
+	if err := proto.Unmarshal(data, record); err != nil {
+		return fmt.Errorf("failed to unmarshal collect complete record: %w", err)
+	}
+	if record.Data == nil {
+		return fmt.Errorf("collect complete record data is empty")
+	}
+	fields := record.Data.Fields
+
+	taskID, err := strconv.ParseUint(fields["task_id"], 10, 64)
+	if err != nil {
+		s.logger.Warn("collect complete record has invalid task_id",
+			zap.String("host_id", hostID),
+			zap.String("task_id", fields["task_id"]))
+		return nil
+	}
+	handler := fields["type"]
+	count, _ := strconv.Atoi(fields["count"])
+
+	status := model.AssetRefreshHostCompleted
+	if fields["status"] == "failed" {
+		status = model.AssetRefreshHostFailed
+	}
+
+	now := model.ToLocalTime(time.Now())
+	result := s.db.Model(&model.AssetRefreshHost{}).
+		Where("task_id = ? AND host_id = ? AND handler = ? AND status = ?",
+			taskID, hostID, handler, model.AssetRefreshHostDispatched).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"count":         count,
+			"error_message": fields["error_message"],
+			"completed_at":  &now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update asset refresh host: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// 已超时或重复上报
+		return nil
+	}
+
+	s.logger.Debug("asset collect task completed",
+		zap.String("host_id", hostID),
+		zap.Uint64("task_id", taskID),
+		zap.String("handler", handler),
+		zap.String("status", status),
+		zap.Int("count", count))
+
+	return RefreshAssetTaskStatus(s.db, uint(taskID))
+}
+
+// RefreshAssetTaskStatus 根据主机明细重新统计采集任务的完成/失败数量，全部结束时设置最终状态
+func RefreshAssetTaskStatus(db *gorm.DB, taskID uint) error {
+	var counts []struct {
+		Status string
+		Count  int
+	}
+	if err := db.Model(&model.AssetRefreshHost{}).
+		Select("status, COUNT(*) AS count").
+		Where("task_id = ?", taskID).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	completed, failed, unfinished := 0, 0, 0
+	for _, c := range counts {
+		switch c.Status {
+		case model.AssetRefreshHostCompleted:
+			completed += c.Count
+		case model.AssetRefreshHostFailed, model.AssetRefreshHostTimeout:
+			failed += c.Count
+		default:
+			unfinished += c.Count
+		}
+	}
+
+	updates := map[string]interface{}{
+		"completed_count": completed,
+		"failed_count":    failed,
+	}
+	if unfinished == 0 {
+		now := model.ToLocalTime(time.Now())
+		updates["completed_at"] = &now
+		switch {
+		case failed == 0:
+			updates["status"] = model.AssetRefreshStatusCompleted
+		case completed == 0:
+			updates["status"] = model.AssetRefreshStatusFailed
+		default:
+			updates["status"] = model.AssetRefreshStatusPartial
+		}
+	}
+
+	return db.Model(&model.AssetRefreshTask{}).Where("id = ?", taskID).Updates(updates).Error
+}
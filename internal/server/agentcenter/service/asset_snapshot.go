@@ -0,0 +1,269 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// snapshotAssemblyTTL 是未收齐的分块快照的最长保留时间，超时后丢弃
+const snapshotAssemblyTTL = 10 * time.Minute
+
+// SnapshotAckSender 向主机下发快照确认命令
+type SnapshotAckSender func(hostID string, cmd *grpcProto.Command) error
+
+// snapshotAssembly 是正在重组的分块快照
+type snapshotAssembly struct {
+	mode      string
+	baseID    string
+	total     int
+	chunks    map[int]string
+	deleted   string
+	ended     bool
+	updatedAt time.Time
+}
+
+// SetSnapshotAckSender 设置快照确认的下发方式（由 Transfer 服务注入）
+func (s *AssetService) SetSnapshotAckSender(sender SnapshotAckSender) {
+	s.ackSender = sender
+}
+
+// handleSnapshotChunk 缓存快照分块，收齐后应用整个快照
+// 调用方已持有主机锁
+func (s *AssetService) handleSnapshotChunk(hostID string, dataType int32, fields map[string]string) error {
+	snapshotID := fields["snapshot_id"]
+	seq, err := strconv.Atoi(fields["seq"])
+	if err != nil {
+		return fmt.Errorf("invalid snapshot seq: %q", fields["seq"])
+	}
+	total, err := strconv.Atoi(fields["total"])
+	if err != nil || total <= 0 || seq < 0 || seq >= total {
+		return fmt.Errorf("invalid snapshot chunk %q/%q", fields["seq"], fields["total"])
+	}
+
+	key := fmt.Sprintf("%s|%d|%s", hostID, dataType, snapshotID)
+	now := time.Now()
+
+	s.asmMu.Lock()
+	// 清理超时未收齐的快照（分块丢失或 Agent 重启）
+	for k, asm := range s.assemblies {
+		if now.Sub(asm.updatedAt) > snapshotAssemblyTTL {
+			delete(s.assemblies, k)
+		}
+	}
+	asm, ok := s.assemblies[key]
+	if !ok {
+		asm = &snapshotAssembly{
+			mode:   fields["mode"],
+			baseID: fields["base_snapshot_id"],
+			total:  total,
+			chunks: make(map[int]string, total),
+		}
+		s.assemblies[key] = asm
+	}
+	asm.chunks[seq] = fields["data"]
+	asm.updatedAt = now
+	if fields["end"] == "true" {
+		asm.ended = true
+		asm.deleted = fields["deleted"]
+	}
+	complete := asm.ended && len(asm.chunks) == asm.total
+	if complete {
+		delete(s.assemblies, key)
+	}
+	s.asmMu.Unlock()
+
+	if !complete {
+		return nil
+	}
+
+	if err := s.applySnapshot(hostID, dataType, snapshotID, asm); err != nil {
+		s.logger.Warn("failed to apply asset snapshot, requesting resync",
+			zap.String("host_id", hostID),
+			zap.Int32("data_type", dataType),
+			zap.String("snapshot_id", snapshotID),
+			zap.Error(err))
+		s.sendSnapshotAck(hostID, dataType, snapshotID, engine.SnapshotAckResync)
+		return err
+	}
+
+	s.sendSnapshotAck(hostID, dataType, snapshotID, engine.SnapshotAckOK)
+	return nil
+}
+
+// applySnapshot 在一个事务中应用收齐的快照：写入新增/变化的资产，删除已不存在的资产，并记录快照基准
+func (s *AssetService) applySnapshot(hostID string, dataType int32, snapshotID string, asm *snapshotAssembly) error {
+	table := assetTableModel(dataType)
+	if table == nil {
+		return fmt.Errorf("unknown asset data type: %d", dataType)
+	}
+
+	// 在事务中应用：任一步骤失败时回滚，不会留下部分替换的资产集合，Agent 收到 resync 后重新上报全量快照
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 增量快照必须基于 Server 最近一次应用的快照，否则无法得到正确结果
+		if asm.mode == engine.SnapshotModeDelta {
+			var last model.AssetSnapshot
+			err := tx.Where("host_id = ? AND data_type = ?", hostID, dataType).First(&last).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to query last snapshot: %w", err)
+			}
+			if last.SnapshotID != asm.baseID {
+				return fmt.Errorf("delta base %q does not match last applied snapshot %q", asm.baseID, last.SnapshotID)
+			}
+		}
+
+		var keepIDs []string
+		for seq := 0; seq < asm.total; seq++ {
+			chunk := asm.chunks[seq]
+			if err := s.applyAssetData(tx, hostID, dataType, chunk); err != nil {
+				return fmt.Errorf("failed to apply chunk %d: %w", seq, err)
+			}
+			if asm.mode == engine.SnapshotModeFull {
+				keys, err := engine.DecodeAssetKeys(dataType, []byte(chunk))
+				if err != nil {
+					return fmt.Errorf("failed to decode chunk %d keys: %w", seq, err)
+				}
+				keepIDs = append(keepIDs, assetIDs(hostID, keys)...)
+			}
+		}
+
+		switch asm.mode {
+		case engine.SnapshotModeFull:
+			// 全量快照：删除快照中不存在的资产
+			query := tx.Where("host_id = ?", hostID)
+			if len(keepIDs) > 0 {
+				query = query.Where("id NOT IN ?", keepIDs)
+			}
+			if err := query.Delete(table).Error; err != nil {
+				return fmt.Errorf("failed to delete stale assets: %w", err)
+			}
+		case engine.SnapshotModeDelta:
+			// 增量快照：删除 Agent 报告已消失的资产
+			var deleted [][]string
+			if asm.deleted != "" {
+				if err := json.Unmarshal([]byte(asm.deleted), &deleted); err != nil {
+					return fmt.Errorf("failed to unmarshal deleted keys: %w", err)
+				}
+			}
+			if len(deleted) > 0 {
+				if err := tx.Where("host_id = ? AND id IN ?", hostID, assetIDs(hostID, deleted)).
+					Delete(table).Error; err != nil {
+					return fmt.Errorf("failed to delete removed assets: %w", err)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown snapshot mode: %q", asm.mode)
+		}
+
+		now := model.ToLocalTime(time.Now())
+
+		// 未变化的资产不会随增量上报，统一刷新采集时间
+		if err := tx.Model(table).Where("host_id = ?", hostID).
+			Update("collected_at", now).Error; err != nil {
+			s.logger.Warn("failed to touch asset collected_at",
+				zap.String("host_id", hostID),
+				zap.Int32("data_type", dataType),
+				zap.Error(err))
+		}
+
+		snapshot := &model.AssetSnapshot{
+			HostID:     hostID,
+			DataType:   dataType,
+			SnapshotID: snapshotID,
+			Mode:       asm.mode,
+			AppliedAt:  now,
+		}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(snapshot).Error; err != nil {
+			return fmt.Errorf("failed to save snapshot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("asset snapshot applied",
+		zap.String("host_id", hostID),
+		zap.Int32("data_type", dataType),
+		zap.String("snapshot_id", snapshotID),
+		zap.String("mode", asm.mode),
+		zap.Int("chunks", asm.total))
+
+	return nil
+}
+
+// sendSnapshotAck 向 collector 插件下发快照确认
+func (s *AssetService) sendSnapshotAck(hostID string, dataType int32, snapshotID, status string) {
+	if s.ackSender == nil {
+		return
+	}
+
+	data, _ := json.Marshal(engine.SnapshotAck{
+		Type:       engine.GetCollectType(dataType),
+		SnapshotID: snapshotID,
+		Status:     status,
+	})
+	cmd := &grpcProto.Command{
+		Tasks: []*grpcProto.Task{{
+			DataType:   engine.DataTypeSnapshotAck,
+			ObjectName: "collector", // 插件名称
+			Data:       string(data),
+		}},
+	}
+	if err := s.ackSender(hostID, cmd); err != nil {
+		s.logger.Warn("failed to send snapshot ack",
+			zap.String("host_id", hostID),
+			zap.String("snapshot_id", snapshotID),
+			zap.String("status", status),
+			zap.Error(err))
+	}
+}
+
+// assetIDs 根据资产唯一键计算资产表主键（与各 handleXxxData 的 shortHash 保持一致）
+func assetIDs(hostID string, keys [][]string) []string {
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, shortHash(append([]string{hostID}, key...)...))
+	}
+	return ids
+}
+
+// assetTableModel 返回 data_type 对应的资产表模型
+func assetTableModel(dataType int32) interface{} {
+	switch dataType {
+	case 5050:
+		return &model.Process{}
+	case 5051:
+		return &model.Port{}
+	case 5052:
+		return &model.AssetUser{}
+	case 5053:
+		return &model.Software{}
+	case 5054:
+		return &model.Container{}
+	case 5055:
+		return &model.App{}
+	case 5056:
+		return &model.NetInterface{}
+	case 5057:
+		return &model.Volume{}
+	case 5058:
+		return &model.Kmod{}
+	case 5059:
+		return &model.Service{}
+	case 5060:
+		return &model.Cron{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+//go:build integration
+// +build integration
+
+package service
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// TestApplySnapshotAtomic 测试快照在事务中应用：中途失败时回滚，资产集合和快照基准保持不变
+func TestApplySnapshotAtomic(t *testing.T) {
+	db := testdb.Open(t, &model.Software{}, &model.AssetSnapshot{})
+	s := NewAssetService(db, zap.NewNop())
+
+	fullSnapshot := func(name string) *snapshotAssembly {
+		return &snapshotAssembly{
+			mode:   engine.SnapshotModeFull,
+			total:  1,
+			chunks: map[int]string{0: `[{"name":"` + name + `","version":"1.0","package_type":"rpm","collected_at":"2026-01-01T00:00:00Z"}]`},
+			ended:  true,
+		}
+	}
+	softwareNames := func() []string {
+		var rows []model.Software
+		if err := db.Find(&rows).Error; err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, 0, len(rows))
+		for _, r := range rows {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	if err := s.applySnapshot("host-1", 5053, "snap-1", fullSnapshot("openssl")); err != nil {
+		t.Fatalf("applySnapshot failed: %v", err)
+	}
+	if got := softwareNames(); len(got) != 1 || got[0] != "openssl" {
+		t.Fatalf("software = %v, want [openssl]", got)
+	}
+
+	// 增量快照写入新资产后解析删除列表失败，已写入的资产应回滚
+	delta := fullSnapshot("bash")
+	delta.mode = engine.SnapshotModeDelta
+	delta.baseID = "snap-1"
+	delta.deleted = "not json"
+	if err := s.applySnapshot("host-1", 5053, "snap-2", delta); err == nil {
+		t.Fatal("expected applySnapshot to fail on invalid deleted keys")
+	}
+	if got := softwareNames(); len(got) != 1 || got[0] != "openssl" {
+		t.Errorf("software after failed snapshot = %v, want [openssl]", got)
+	}
+	var last model.AssetSnapshot
+	if err := db.Where("host_id = ? AND data_type = ?", "host-1", 5053).First(&last).Error; err != nil {
+		t.Fatal(err)
+	}
+	if last.SnapshotID != "snap-1" {
+		t.Errorf("last snapshot = %q, want snap-1", last.SnapshotID)
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
 	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/maintenance"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -77,8 +79,12 @@ func (s *TaskService) dispatchTask(task *model.ScanTask, transferService interfa
 		if runtimeType == model.RuntimeTypeVM {
 			// 虚拟机：runtime_type = 'vm' 或为空（兼容旧数据）
 			baseQuery = baseQuery.Where("(runtime_type = ? OR runtime_type = '' OR runtime_type IS NULL)", model.RuntimeTypeVM)
+		} else if runtimeType == model.RuntimeTypeDocker {
+			// Docker：容器内 Agent 的主机，以及运行着容器的宿主机（由宿主机 Agent 进入容器检查）
+			baseQuery = baseQuery.Where("(runtime_type = ? OR host_id IN (?))",
+				runtimeType, s.db.Model(&model.Container{}).Distinct("host_id"))
 		} else {
-			// Docker 或 K8s：精确匹配
+			// K8s：精确匹配
 			baseQuery = baseQuery.Where("runtime_type = ?", runtimeType)
 		}
 		s.logger.Debug("按运行时类型筛选主机",
@@ -87,6 +93,11 @@ func (s *TaskService) dispatchTask(task *model.ScanTask, transferService interfa
 		)
 	}
 
+	// 任务创建人受业务线范围限制时，只下发到这些业务线的主机
+	if len(task.TargetConfig.BusinessLines) > 0 {
+		baseQuery = baseQuery.Where("business_line IN ?", task.TargetConfig.BusinessLines)
+	}
+
 	switch task.TargetType {
 	case model.TargetTypeAll:
 		// 查询所有在线主机（已按 runtime_type 筛选）
@@ -152,6 +163,11 @@ func (s *TaskService) dispatchTask(task *model.ScanTask, transferService interfa
 	var matchedHosts []model.Host
 	var skippedHosts []string
 	for _, host := range hosts {
+		// 仅做容器检查的宿主机不按宿主机 OS 过滤（容器 OS 由插件在容器内探测）
+		if scanHost, _ := scanModeForHost(task, &host); !scanHost {
+			matchedHosts = append(matchedHosts, host)
+			continue
+		}
 		if s.matchPolicyOS(firstPolicy, &host) {
 			matchedHosts = append(matchedHosts, host)
 		} else {
@@ -352,8 +368,13 @@ func (s *TaskService) sendTaskToHostMultiPolicy(
 		SendCommand(agentID string, cmd *grpcProto.Command) error
 	},
 ) error {
+	scanHost, scanContainers := scanModeForHost(task, host)
+
 	// 构建多策略数据
-	policiesData := s.buildMultiPoliciesData(policies, host)
+	policiesData := "[]"
+	if scanHost {
+		policiesData = s.buildMultiPoliciesData(policies, host)
+	}
 
 	// 构建任务数据（JSON）
 	taskData := map[string]interface{}{
@@ -363,6 +384,12 @@ func (s *TaskService) sendTaskToHostMultiPolicy(
 		"os_family":  host.OSFamily,
 		"os_version": host.OSVersion,
 	}
+	if scanContainers {
+		taskData["container_policies"] = s.buildContainerPoliciesData(policies, host)
+		if len(task.TargetConfig.ContainerIDs) > 0 {
+			taskData["container_ids"] = task.TargetConfig.ContainerIDs
+		}
+	}
 
 	taskDataJSON, err := json.Marshal(taskData)
 	if err != nil {
@@ -396,22 +423,43 @@ func (s *TaskService) sendTaskToHostMultiPolicy(
 	return nil
 }
 
+// scanModeForHost 判断任务在主机上的检查范围：是否检查宿主机本身、是否进入容器检查
+// Docker 运行时任务在普通宿主机上只做容器内检查，无需在每个容器内部署 Agent
+func scanModeForHost(task *model.ScanTask, host *model.Host) (scanHost bool, scanContainers bool) {
+	hostIsContainer := host.RuntimeType == model.RuntimeTypeDocker || host.RuntimeType == model.RuntimeTypeK8s
+	if task.TargetConfig.RuntimeType == model.RuntimeTypeDocker && !hostIsContainer {
+		return false, true
+	}
+	return true, task.TargetConfig.ScanContainers && !hostIsContainer
+}
+
 // buildMultiPoliciesData 构建多策略数据
 func (s *TaskService) buildMultiPoliciesData(policies []*model.Policy, host *model.Host) string {
+	return s.buildRuntimePoliciesData(policies, host, host.RuntimeType, true)
+}
+
+// buildContainerPoliciesData 构建容器内检查的多策略数据
+// 按 Docker 运行时过滤规则，OS 匹配由插件根据容器内 /etc/os-release 完成
+func (s *TaskService) buildContainerPoliciesData(policies []*model.Policy, host *model.Host) string {
+	return s.buildRuntimePoliciesData(policies, host, model.RuntimeTypeDocker, false)
+}
+
+// buildRuntimePoliciesData 按运行时类型构建多策略数据，matchHostOS 为 true 时按主机 OS 过滤策略
+func (s *TaskService) buildRuntimePoliciesData(policies []*model.Policy, host *model.Host, runtimeType model.RuntimeType, matchHostOS bool) string {
 	policiesArray := make([]map[string]interface{}, 0, len(policies))
 
 	for _, policy := range policies {
 		// 检查策略是否匹配主机OS
-		if !s.matchPolicyOS(policy, host) {
+		if matchHostOS && !s.matchPolicyOS(policy, host) {
 			continue
 		}
 
-		// 检查策略是否匹配主机运行时类型
-		if !policy.MatchesRuntimeType(host.RuntimeType) {
-			s.logger.Debug("策略不适用于主机运行时类型",
+		// 检查策略是否匹配运行时类型
+		if !policy.MatchesRuntimeType(runtimeType) {
+			s.logger.Debug("策略不适用于运行时类型",
 				zap.String("policy_id", policy.ID),
 				zap.String("host_id", host.HostID),
-				zap.String("host_runtime_type", string(host.RuntimeType)),
+				zap.String("runtime_type", string(runtimeType)),
 				zap.Strings("policy_runtime_types", policy.RuntimeTypes))
 			continue
 		}
@@ -423,8 +471,8 @@ func (s *TaskService) buildMultiPoliciesData(policies []*model.Policy, host *mod
 			if !rule.Enabled {
 				continue
 			}
-			// 检查规则是否匹配主机运行时类型
-			if !rule.MatchesRuntimeType(host.RuntimeType) {
+			// 检查规则是否匹配运行时类型
+			if !rule.MatchesRuntimeType(runtimeType) {
 				skippedRules = append(skippedRules, rule.RuleID)
 				continue
 			}
@@ -442,10 +490,10 @@ func (s *TaskService) buildMultiPoliciesData(policies []*model.Policy, host *mod
 
 		// 记录被运行时类型过滤的规则
 		if len(skippedRules) > 0 {
-			s.logger.Debug("部分规则不适用于主机运行时类型，已跳过",
+			s.logger.Debug("部分规则不适用于运行时类型，已跳过",
 				zap.String("policy_id", policy.ID),
 				zap.String("host_id", host.HostID),
-				zap.String("host_runtime_type", string(host.RuntimeType)),
+				zap.String("runtime_type", string(runtimeType)),
 				zap.Int("skipped_count", len(skippedRules)))
 		}
 
@@ -682,7 +730,8 @@ func compareVersionNumbers(v1, v2 string) int {
 }
 
 // DispatchFixTask 下发修复任务到 Agent
-func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService interface {
+// 维护窗口未开启或变更冻结中的主机记录为 waiting_window，窗口开启后由 DispatchWaitingFixHosts 下发
+func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, gate *maintenance.Gate, transferService interface {
 	SendCommand(agentID string, cmd *grpcProto.Command) error
 }) error {
 	// 1. 查询目标主机（只查询在线主机）
@@ -699,77 +748,27 @@ func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService in
 			zap.Int("requested_hosts", len(fixTask.HostIDs)),
 		)
 		s.db.Model(fixTask).Updates(map[string]interface{}{
-			"status":        model.FixTaskStatusFailed,
-			"completed_at":  model.Now(),
+			"status":       model.FixTaskStatusFailed,
+			"completed_at": model.Now(),
 		})
 		return fmt.Errorf("没有在线主机")
 	}
 
-	// 2. 查询规则信息
-	var rules []model.Rule
-	// 将 StringArray 转换为 []string 以便 GORM 查询
-	ruleIDs := []string(fixTask.RuleIDs)
-	if err := s.db.Where("rule_id IN ?", ruleIDs).Find(&rules).Error; err != nil {
-		return fmt.Errorf("查询规则失败: %w", err)
-	}
-
-	if len(rules) == 0 {
-		s.logger.Warn("没有找到规则，修复任务失败",
-			zap.String("task_id", fixTask.TaskID),
-		)
-		s.db.Model(fixTask).Updates(map[string]interface{}{
-			"status":        model.FixTaskStatusFailed,
-			"completed_at":  model.Now(),
-		})
-		return fmt.Errorf("没有找到规则")
-	}
-
-	// 3. 按策略组织规则
-	policyRulesMap := make(map[string][]*model.Rule)
-	for i := range rules {
-		rule := &rules[i]
-		policyRulesMap[rule.PolicyID] = append(policyRulesMap[rule.PolicyID], rule)
-	}
-
-	// 4. 查询所有相关策略
-	policyIDs := make([]string, 0, len(policyRulesMap))
-	for policyID := range policyRulesMap {
-		policyIDs = append(policyIDs, policyID)
-	}
-
-	policyService := NewPolicyService(s.db, s.logger)
-	var policies []*model.Policy
-	for _, policyID := range policyIDs {
-		policy, err := policyService.GetPolicy(policyID)
-		if err != nil {
-			s.logger.Error("查询策略失败",
-				zap.String("task_id", fixTask.TaskID),
-				zap.String("policy_id", policyID),
-				zap.Error(err),
-			)
-			continue
-		}
-		// 只保留需要修复的规则
-		var filteredRules []model.Rule
-		for _, rule := range policyRulesMap[policyID] {
-			filteredRules = append(filteredRules, *rule)
-		}
-		policy.Rules = filteredRules
-		policies = append(policies, policy)
-	}
-
-	if len(policies) == 0 {
-		s.logger.Warn("没有有效的策略，修复任务失败",
+	// 2. 查询规则和策略
+	policies, ruleCount, err := s.loadFixPolicies(fixTask)
+	if err != nil {
+		s.logger.Warn("修复任务失败",
 			zap.String("task_id", fixTask.TaskID),
+			zap.Error(err),
 		)
 		s.db.Model(fixTask).Updates(map[string]interface{}{
-			"status":        model.FixTaskStatusFailed,
-			"completed_at":  model.Now(),
+			"status":       model.FixTaskStatusFailed,
+			"completed_at": model.Now(),
 		})
-		return fmt.Errorf("没有有效的策略")
+		return err
 	}
 
-	// 5. 更新任务状态为 running
+	// 3. 更新任务状态为 running
 	if err := s.db.Model(fixTask).Update("status", model.FixTaskStatusRunning).Error; err != nil {
 		return fmt.Errorf("更新任务状态失败: %w", err)
 	}
@@ -777,22 +776,16 @@ func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService in
 	s.logger.Info("准备下发修复任务",
 		zap.String("task_id", fixTask.TaskID),
 		zap.Int("host_count", len(hosts)),
-		zap.Int("rule_count", len(rules)),
+		zap.Int("rule_count", ruleCount),
 		zap.Int("policy_count", len(policies)),
 	)
 
-	// 6. 为每个主机下发修复任务
+	// 4. 为每个主机下发修复任务
 	successCount := 0
-	for _, host := range hosts {
-		// 过滤匹配主机 OS 的策略
-		var matchedPolicies []*model.Policy
-		for _, policy := range policies {
-			if s.matchPolicyOS(policy, &host) {
-				matchedPolicies = append(matchedPolicies, policy)
-			}
-		}
-
-		if len(matchedPolicies) == 0 {
+	waitingCount := 0
+	for i := range hosts {
+		host := &hosts[i]
+		if !s.matchAnyPolicyOS(policies, host) {
 			s.logger.Debug("主机不匹配任何策略 OS 要求，跳过",
 				zap.String("task_id", fixTask.TaskID),
 				zap.String("host_id", host.HostID),
@@ -800,44 +793,14 @@ func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService in
 			continue
 		}
 
-		// 构建策略数据
-		policiesData := s.buildMultiPoliciesData(matchedPolicies, &host)
-
-		// 构建任务数据（JSON）
-		taskData := map[string]interface{}{
-			"task_id":      fmt.Sprintf("%s-%s", fixTask.TaskID, host.HostID), // 子任务ID
-			"fix_task_id":  fixTask.TaskID,                                     // 修复任务ID
-			"policies":     policiesData,
-			"rule_ids":     fixTask.RuleIDs,
-			"os_family":    host.OSFamily,
-			"os_version":   host.OSVersion,
-		}
-
-		taskDataJSON, err := json.Marshal(taskData)
-		if err != nil {
-			s.logger.Error("序列化任务数据失败",
-				zap.String("task_id", fixTask.TaskID),
-				zap.String("host_id", host.HostID),
-				zap.Error(err),
-			)
+		// 维护窗口未开启或变更冻结中，等待窗口开启（紧急放行的任务除外）
+		if reason := fixHostWaitReason(fixTask, gate, host); reason != "" {
+			s.createFixHostStatus(fixTask, host, model.FixTaskHostStatusWaiting, reason)
+			waitingCount++
 			continue
 		}
 
-		// 构建 Task
-		grpcTask := &grpcProto.Task{
-			DataType:   8002,       // 基线修复任务
-			ObjectName: "baseline", // 插件名称
-			Data:       string(taskDataJSON),
-			Token:      fixTask.TaskID, // 使用 fix_task_id 作为 token
-		}
-
-		// 构建 Command
-		cmd := &grpcProto.Command{
-			Tasks: []*grpcProto.Task{grpcTask},
-		}
-
-		// 发送命令
-		if err := transferService.SendCommand(host.HostID, cmd); err != nil {
+		if err := s.sendFixTaskToHost(fixTask, policies, host, transferService); err != nil {
 			s.logger.Error("下发修复任务到主机失败",
 				zap.String("task_id", fixTask.TaskID),
 				zap.String("host_id", host.HostID),
@@ -845,30 +808,7 @@ func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService in
 			)
 			continue
 		}
-
-		// 创建主机状态记录
-		now := model.Now()
-		hostStatus := &model.FixTaskHostStatus{
-			TaskID:       fixTask.TaskID,
-			HostID:       host.HostID,
-			Hostname:     host.Hostname,
-			IPAddress:    getHostIPAddress(&host),
-			BusinessLine: host.BusinessLine,
-			OSFamily:     host.OSFamily,
-			OSVersion:    host.OSVersion,
-			RuntimeType:  string(host.RuntimeType),
-			Status:       model.FixTaskHostStatusDispatched,
-			DispatchedAt: &now,
-		}
-
-		if err := s.db.Create(hostStatus).Error; err != nil {
-			s.logger.Error("创建主机状态记录失败",
-				zap.String("task_id", fixTask.TaskID),
-				zap.String("host_id", host.HostID),
-				zap.Error(err),
-			)
-			// 不影响任务下发，继续
-		}
+		s.createFixHostStatus(fixTask, host, model.FixTaskHostStatusDispatched, "")
 
 		successCount++
 		s.logger.Debug("修复任务已下发",
@@ -877,15 +817,15 @@ func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService in
 		)
 	}
 
-	// 7. 检查是否成功下发到任何主机
-	if successCount == 0 {
+	// 5. 检查是否成功下发到任何主机
+	if successCount == 0 && waitingCount == 0 {
 		s.logger.Warn("修复任务下发失败，没有成功下发到任何主机",
 			zap.String("task_id", fixTask.TaskID),
 			zap.Int("matched_hosts", len(hosts)),
 		)
 		s.db.Model(fixTask).Updates(map[string]interface{}{
-			"status":        model.FixTaskStatusFailed,
-			"completed_at":  model.Now(),
+			"status":       model.FixTaskStatusFailed,
+			"completed_at": model.Now(),
 		})
 		return fmt.Errorf("没有成功下发到任何主机")
 	}
@@ -894,13 +834,231 @@ func (s *TaskService) DispatchFixTask(fixTask *model.FixTask, transferService in
 		zap.String("task_id", fixTask.TaskID),
 		zap.Int("total_hosts", len(hosts)),
 		zap.Int("success_count", successCount),
+		zap.Int("waiting_count", waitingCount),
 	)
 
 	return nil
 }
 
-// DispatchPendingFixTasks 分发待执行的修复任务
-// 查询 fix_tasks 表中状态为 pending 的任务并下发
+// DispatchWaitingFixHosts 下发执行中修复任务里等待维护窗口、且窗口已开启（或已紧急放行）的主机
+func (s *TaskService) DispatchWaitingFixHosts(fixTask *model.FixTask, gate *maintenance.Gate, transferService interface {
+	SendCommand(agentID string, cmd *grpcProto.Command) error
+}) error {
+	var waiting []model.FixTaskHostStatus
+	if err := s.db.Where("task_id = ? AND status = ?", fixTask.TaskID, model.FixTaskHostStatusWaiting).Find(&waiting).Error; err != nil {
+		return fmt.Errorf("查询等待中的主机失败: %w", err)
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	hostIDs := make([]string, 0, len(waiting))
+	for _, w := range waiting {
+		hostIDs = append(hostIDs, w.HostID)
+	}
+	var hosts []model.Host
+	if err := s.db.Where("host_id IN ?", hostIDs).Find(&hosts).Error; err != nil {
+		return fmt.Errorf("查询主机失败: %w", err)
+	}
+	hostMap := make(map[string]*model.Host, len(hosts))
+	for i := range hosts {
+		hostMap[hosts[i].HostID] = &hosts[i]
+	}
+
+	var policies []*model.Policy
+	for _, w := range waiting {
+		host, ok := hostMap[w.HostID]
+		if !ok {
+			s.finishWaitingFixHost(&w, model.FixTaskHostStatusFailed, "主机已删除")
+			continue
+		}
+		reason := fixHostWaitReason(fixTask, gate, host)
+		if reason != "" {
+			if reason != w.WaitingReason {
+				s.db.Model(&w).Update("waiting_reason", reason)
+			}
+			continue
+		}
+		if host.Status != model.HostStatusOnline {
+			s.finishWaitingFixHost(&w, model.FixTaskHostStatusFailed, "维护窗口开启时主机不在线")
+			continue
+		}
+
+		if policies == nil {
+			var err error
+			if policies, _, err = s.loadFixPolicies(fixTask); err != nil {
+				return err
+			}
+		}
+		if err := s.sendFixTaskToHost(fixTask, policies, host, transferService); err != nil {
+			s.logger.Error("下发修复任务到主机失败",
+				zap.String("task_id", fixTask.TaskID),
+				zap.String("host_id", host.HostID),
+				zap.Error(err),
+			)
+			s.finishWaitingFixHost(&w, model.FixTaskHostStatusFailed, "下发失败: "+err.Error())
+			continue
+		}
+
+		now := model.Now()
+		s.db.Model(&w).Updates(map[string]interface{}{
+			"status":         model.FixTaskHostStatusDispatched,
+			"waiting_reason": "",
+			"dispatched_at":  &now,
+		})
+		s.logger.Info("维护窗口已开启，修复任务已下发",
+			zap.String("task_id", fixTask.TaskID),
+			zap.String("host_id", host.HostID),
+			zap.Bool("emergency", fixTask.Overridden()),
+		)
+	}
+	return nil
+}
+
+// finishWaitingFixHost 结束等待中的主机（主机已删除、离线或下发失败）
+func (s *TaskService) finishWaitingFixHost(hostStatus *model.FixTaskHostStatus, status, message string) {
+	now := model.Now()
+	s.db.Model(hostStatus).Updates(map[string]interface{}{
+		"status":         status,
+		"waiting_reason": "",
+		"error_message":  message,
+		"completed_at":   &now,
+	})
+}
+
+// fixHostWaitReason 返回主机需要等待维护窗口的原因，可以立即下发时返回空字符串
+func fixHostWaitReason(fixTask *model.FixTask, gate *maintenance.Gate, host *model.Host) string {
+	if gate == nil || fixTask.Overridden() {
+		return ""
+	}
+	return gate.Check(host)
+}
+
+// loadFixPolicies 查询修复任务的规则并按策略组织，返回策略列表和规则数
+// 任务有规则快照时使用快照中的规则（创建时的修复命令），否则查询规则的当前内容
+func (s *TaskService) loadFixPolicies(fixTask *model.FixTask) ([]*model.Policy, int, error) {
+	var rules []model.Rule
+	if len(fixTask.RuleSnapshot) > 0 {
+		for _, snapshot := range fixTask.RuleSnapshot {
+			rules = append(rules, snapshot.Rule())
+		}
+	} else {
+		// 将 StringArray 转换为 []string 以便 GORM 查询
+		ruleIDs := []string(fixTask.RuleIDs)
+		if err := s.db.Where("rule_id IN ?", ruleIDs).Find(&rules).Error; err != nil {
+			return nil, 0, fmt.Errorf("查询规则失败: %w", err)
+		}
+	}
+	if len(rules) == 0 {
+		return nil, 0, fmt.Errorf("没有找到规则")
+	}
+
+	// 按策略组织规则
+	policyRulesMap := make(map[string][]model.Rule)
+	for _, rule := range rules {
+		policyRulesMap[rule.PolicyID] = append(policyRulesMap[rule.PolicyID], rule)
+	}
+
+	policyService := NewPolicyService(s.db, s.logger)
+	var policies []*model.Policy
+	for policyID, policyRules := range policyRulesMap {
+		policy, err := policyService.GetPolicy(policyID)
+		if err != nil {
+			s.logger.Error("查询策略失败",
+				zap.String("task_id", fixTask.TaskID),
+				zap.String("policy_id", policyID),
+				zap.Error(err),
+			)
+			continue
+		}
+		// 只保留需要修复的规则
+		policy.Rules = policyRules
+		policies = append(policies, policy)
+	}
+	if len(policies) == 0 {
+		return nil, 0, fmt.Errorf("没有有效的策略")
+	}
+	return policies, len(rules), nil
+}
+
+// matchAnyPolicyOS 判断主机是否匹配任一策略的 OS 要求
+func (s *TaskService) matchAnyPolicyOS(policies []*model.Policy, host *model.Host) bool {
+	for _, policy := range policies {
+		if s.matchPolicyOS(policy, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendFixTaskToHost 向单台主机下发修复任务（只包含匹配主机 OS 的策略）
+func (s *TaskService) sendFixTaskToHost(fixTask *model.FixTask, policies []*model.Policy, host *model.Host, transferService interface {
+	SendCommand(agentID string, cmd *grpcProto.Command) error
+}) error {
+	// 过滤匹配主机 OS 的策略
+	var matchedPolicies []*model.Policy
+	for _, policy := range policies {
+		if s.matchPolicyOS(policy, host) {
+			matchedPolicies = append(matchedPolicies, policy)
+		}
+	}
+
+	// 构建任务数据（JSON）
+	taskData := map[string]interface{}{
+		"task_id":     fmt.Sprintf("%s-%s", fixTask.TaskID, host.HostID), // 子任务ID
+		"fix_task_id": fixTask.TaskID,                                    // 修复任务ID
+		"policies":    s.buildMultiPoliciesData(matchedPolicies, host),
+		"rule_ids":    fixTask.RuleIDs,
+		"os_family":   host.OSFamily,
+		"os_version":  host.OSVersion,
+	}
+	taskDataJSON, err := json.Marshal(taskData)
+	if err != nil {
+		return fmt.Errorf("序列化任务数据失败: %w", err)
+	}
+
+	cmd := &grpcProto.Command{
+		Tasks: []*grpcProto.Task{{
+			DataType:   8002,       // 基线修复任务
+			ObjectName: "baseline", // 插件名称
+			Data:       string(taskDataJSON),
+			Token:      fixTask.TaskID, // 使用 fix_task_id 作为 token
+		}},
+	}
+	return transferService.SendCommand(host.HostID, cmd)
+}
+
+// createFixHostStatus 创建修复任务主机状态记录（已下发或等待维护窗口）
+func (s *TaskService) createFixHostStatus(fixTask *model.FixTask, host *model.Host, status, waitingReason string) {
+	hostStatus := &model.FixTaskHostStatus{
+		TaskID:        fixTask.TaskID,
+		HostID:        host.HostID,
+		Hostname:      host.Hostname,
+		IPAddress:     getHostIPAddress(host),
+		BusinessLine:  host.BusinessLine,
+		OSFamily:      host.OSFamily,
+		OSVersion:     host.OSVersion,
+		RuntimeType:   string(host.RuntimeType),
+		Status:        status,
+		WaitingReason: waitingReason,
+	}
+	if status == model.FixTaskHostStatusDispatched {
+		now := model.Now()
+		hostStatus.DispatchedAt = &now
+	}
+
+	if err := s.db.Create(hostStatus).Error; err != nil {
+		s.logger.Error("创建主机状态记录失败",
+			zap.String("task_id", fixTask.TaskID),
+			zap.String("host_id", host.HostID),
+			zap.Error(err),
+		)
+		// 不影响任务下发，继续
+	}
+}
+
+// DispatchPendingFixTasks 分发修复任务
+// 下发 fix_tasks 表中状态为 pending 的任务，并为执行中的任务下发窗口已开启的等待主机
 func (s *TaskService) DispatchPendingFixTasks(transferService interface {
 	SendCommand(agentID string, cmd *grpcProto.Command) error
 }) error {
@@ -910,15 +1068,30 @@ func (s *TaskService) DispatchPendingFixTasks(transferService interface {
 		return fmt.Errorf("查询待执行修复任务失败: %w", err)
 	}
 
-	if len(tasks) == 0 {
+	// 查询有主机等待维护窗口的执行中任务
+	var waitingTasks []model.FixTask
+	if err := s.db.Where("status = ? AND task_id IN (?)", model.FixTaskStatusRunning,
+		s.db.Model(&model.FixTaskHostStatus{}).Select("task_id").Where("status = ?", model.FixTaskHostStatusWaiting),
+	).Find(&waitingTasks).Error; err != nil {
+		return fmt.Errorf("查询等待维护窗口的修复任务失败: %w", err)
+	}
+
+	if len(tasks) == 0 && len(waitingTasks) == 0 {
 		return nil // 没有待执行任务
 	}
 
-	s.logger.Info("发现待执行修复任务", zap.Int("count", len(tasks)))
+	gate, err := maintenance.Load(s.db, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) > 0 {
+		s.logger.Info("发现待执行修复任务", zap.Int("count", len(tasks)))
+	}
 
 	// 处理每个任务
 	for _, task := range tasks {
-		if err := s.DispatchFixTask(&task, transferService); err != nil {
+		if err := s.DispatchFixTask(&task, gate, transferService); err != nil {
 			s.logger.Error("分发修复任务失败",
 				zap.String("task_id", task.TaskID),
 				zap.Error(err),
@@ -928,6 +1101,15 @@ func (s *TaskService) DispatchPendingFixTasks(transferService interface {
 		}
 	}
 
+	for _, task := range waitingTasks {
+		if err := s.DispatchWaitingFixHosts(&task, gate, transferService); err != nil {
+			s.logger.Error("下发等待维护窗口的修复任务失败",
+				zap.String("task_id", task.TaskID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	return nil
 }
 
@@ -1042,6 +1224,9 @@ func (s *TaskService) matchFIMTargetHosts(task *model.FIMTask) ([]model.Host, er
 	var hosts []model.Host
 	// FIM 仅适用于 VM（物理机/虚拟机），容器环境不支持 AIDE
 	baseQuery := s.db.Where("status = ? AND runtime_type = ?", model.HostStatusOnline, model.RuntimeTypeVM)
+	if len(task.TargetConfig.BusinessLines) > 0 {
+		baseQuery = baseQuery.Where("business_line IN ?", task.TargetConfig.BusinessLines)
+	}
 
 	switch task.TargetType {
 	case "all":
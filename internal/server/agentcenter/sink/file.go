@@ -0,0 +1,181 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+// fileFlushInterval 是 NDJSON 文件缓冲区的刷新间隔
+const fileFlushInterval = time.Second
+
+func init() {
+	Register("file", newFileProducer)
+}
+
+// fileProducer 将记录按行写入 NDJSON 文件，文件超过 MaxSizeMB 后轮转为 <path>.<时间戳>，保留 MaxBackups 个
+type fileProducer struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	logger     *zap.Logger
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	size   int64
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newFileProducer 创建 NDJSON 文件输出
+func newFileProducer(cfg config.SinkOutputConfig, logger *zap.Logger) (Producer, error) {
+	if cfg.File.Path == "" {
+		return nil, fmt.Errorf("file.path 不能为空")
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.File.Path), 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	p := &fileProducer{
+		path:       cfg.File.Path,
+		maxSize:    int64(cfg.File.MaxSizeMB) * 1024 * 1024,
+		maxBackups: cfg.File.MaxBackups,
+		logger:     logger,
+		done:       make(chan struct{}),
+	}
+	if err := p.open(); err != nil {
+		return nil, err
+	}
+
+	p.wg.Add(1)
+	go p.flushLoop()
+	return p, nil
+}
+
+// Publish 写入一行 JSON（主题对文件输出无意义，忽略）
+func (p *fileProducer) Publish(ctx context.Context, msg *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return fmt.Errorf("文件已关闭")
+	}
+	if p.maxSize > 0 && p.size > 0 && p.size+int64(len(msg.Value))+1 > p.maxSize {
+		if err := p.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := p.writer.Write(msg.Value)
+	if err == nil {
+		err = p.writer.WriteByte('\n')
+		n++
+	}
+	p.size += int64(n)
+	return err
+}
+
+// Close 刷新缓冲并关闭文件
+func (p *fileProducer) Close() error {
+	close(p.done)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.file == nil {
+		return nil
+	}
+	err := p.writer.Flush()
+	if cerr := p.file.Close(); err == nil {
+		err = cerr
+	}
+	p.file = nil
+	return err
+}
+
+// open 以追加方式打开文件
+func (p *fileProducer) open() error {
+	f, err := os.OpenFile(p.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("读取文件信息失败: %w", err)
+	}
+	p.file = f
+	p.writer = bufio.NewWriterSize(f, 64*1024)
+	p.size = info.Size()
+	return nil
+}
+
+// rotate 轮转文件（调用方持有锁）
+func (p *fileProducer) rotate() error {
+	if err := p.writer.Flush(); err != nil {
+		return err
+	}
+	if err := p.file.Close(); err != nil {
+		return err
+	}
+	p.file = nil
+
+	backup := fmt.Sprintf("%s.%s", p.path, time.Now().Format("20060102-150405.000"))
+	if err := os.Rename(p.path, backup); err != nil {
+		return fmt.Errorf("轮转文件失败: %w", err)
+	}
+	p.removeOldBackups()
+	return p.open()
+}
+
+// removeOldBackups 删除超出保留数量的轮转文件
+func (p *fileProducer) removeOldBackups() {
+	matches, err := filepath.Glob(p.path + ".*")
+	if err != nil || len(matches) <= p.maxBackups {
+		return
+	}
+	// 时间戳后缀按字典序即按时间排序
+	sort.Strings(matches)
+	for _, old := range matches[:len(matches)-p.maxBackups] {
+		if !strings.HasPrefix(filepath.Base(old), filepath.Base(p.path)+".") {
+			continue
+		}
+		if err := os.Remove(old); err != nil {
+			p.logger.Warn("删除过期外发文件失败", zap.String("file", old), zap.Error(err))
+		}
+	}
+}
+
+// flushLoop 定期刷新缓冲区，保证下游能及时读取到数据
+func (p *fileProducer) flushLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(fileFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			if p.file != nil {
+				if err := p.writer.Flush(); err != nil {
+					p.logger.Warn("刷新外发文件失败", zap.String("file", p.path), zap.Error(err))
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
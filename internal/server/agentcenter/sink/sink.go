@@ -0,0 +1,347 @@
+// Package sink 提供 Agent 数据外发功能
+// AgentCenter 将解码后的 Agent 记录（JSON，附带主机信息）异步投递到外部系统（NDJSON 文件、消息总线），
+// 供 SIEM、数据湖等下游系统消费
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/metrics"
+)
+
+// publishTimeout 是单条记录投递到一个输出的超时时间
+const publishTimeout = 5 * time.Second
+
+// kinds 是支持外发的数据类型及其名称
+var kinds = map[int32]string{
+	1000: "heartbeat",
+	8000: "baseline_result",
+	8003: "fix_result",
+	6001: "fim_event",
+	5050: "asset_process",
+	5051: "asset_port",
+	5052: "asset_user",
+	5053: "asset_software",
+	5054: "asset_container",
+	5055: "asset_app",
+	5056: "asset_net_interface",
+	5057: "asset_volume",
+	5058: "asset_kmod",
+	5059: "asset_service",
+	5060: "asset_cron",
+}
+
+// Kind 返回数据类型名称（不支持外发的类型返回空字符串）
+func Kind(dataType int32) string {
+	return kinds[dataType]
+}
+
+// Host 是记录所属主机的信息
+type Host struct {
+	AgentID  string   `json:"agent_id"`
+	Hostname string   `json:"hostname"`
+	IPv4     []string `json:"ipv4,omitempty"`
+	IPv6     []string `json:"ipv6,omitempty"`
+	Version  string   `json:"agent_version,omitempty"`
+}
+
+// Event 是外发的记录（序列化为一行 JSON）
+type Event struct {
+	Kind       string            `json:"kind"`
+	DataType   int32             `json:"data_type"`
+	Seq        uint64            `json:"seq,omitempty"` // 事件类记录序列号（Agent 重发时不变，可用于下游去重）
+	Timestamp  time.Time         `json:"timestamp"`     // Agent 采集时间
+	ReceivedAt time.Time         `json:"received_at"`   // AgentCenter 接收时间
+	Host       Host              `json:"host"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	Data       json.RawMessage   `json:"data,omitempty"` // 资产等记录的 data 字段（JSON）
+}
+
+// Message 是投递给 Producer 的消息
+type Message struct {
+	Topic    string // 主题（由输出配置的 topic 模板生成）
+	Key      string // 分区键（Agent ID，保证同一主机的记录有序）
+	Value    []byte // Event 的 JSON 编码
+	DataType int32
+}
+
+// Producer 是外发输出接口，Kafka、NATS 等消息总线适配器实现该接口并通过 Register 注册
+type Producer interface {
+	// Publish 投递一条消息，返回错误时该消息被丢弃
+	Publish(ctx context.Context, msg *Message) error
+	// Close 刷新缓冲并释放资源
+	Close() error
+}
+
+// Factory 根据输出配置创建 Producer
+type Factory func(cfg config.SinkOutputConfig, logger *zap.Logger) (Producer, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register 注册输出类型（适配器在 init 中调用）
+func Register(typ string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[typ] = factory
+}
+
+// output 是一个已启用的输出
+type output struct {
+	name      string
+	topic     string
+	dataTypes map[int32]bool
+	producer  Producer
+}
+
+// item 是待投递的原始记录（解码在投递协程中进行，不占用接收循环）
+type item struct {
+	host       Host
+	record     *grpcProto.EncodedRecord
+	receivedAt time.Time
+}
+
+// Dispatcher 将记录投递到所有订阅了该数据类型的输出
+// 未配置输出时 NewDispatcher 返回 nil，nil Dispatcher 的方法均为空操作
+type Dispatcher struct {
+	logger  *zap.Logger
+	outputs []*output
+	wanted  map[int32]bool
+	ch      chan *item
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewDispatcher 根据配置创建 Dispatcher 并启动投递协程
+func NewDispatcher(cfg config.SinkConfig, logger *zap.Logger) (*Dispatcher, error) {
+	d := &Dispatcher{
+		logger: logger,
+		wanted: make(map[int32]bool),
+		ch:     make(chan *item, cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	for _, outCfg := range cfg.Outputs {
+		if !outCfg.Enabled {
+			continue
+		}
+		out, err := newOutput(outCfg, logger)
+		if err != nil {
+			d.closeOutputs()
+			return nil, err
+		}
+		d.outputs = append(d.outputs, out)
+		for dataType := range out.dataTypes {
+			d.wanted[dataType] = true
+		}
+		logger.Info("数据外发输出已启用",
+			zap.String("name", out.name),
+			zap.String("type", outCfg.Type),
+			zap.Int("data_type_count", len(out.dataTypes)),
+		)
+	}
+	if len(d.outputs) == 0 {
+		return nil, nil
+	}
+
+	d.wg.Add(1)
+	go d.run()
+	return d, nil
+}
+
+// newOutput 创建单个输出
+func newOutput(cfg config.SinkOutputConfig, logger *zap.Logger) (*output, error) {
+	name := cfg.Name
+	if name == "" {
+		name = cfg.Type
+	}
+
+	factoriesMu.RLock()
+	factory, ok := factories[cfg.Type]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("数据外发输出 %s 的类型 %q 未注册", name, cfg.Type)
+	}
+
+	dataTypes := make(map[int32]bool)
+	if len(cfg.DataTypes) == 0 {
+		for dataType := range kinds {
+			dataTypes[dataType] = true
+		}
+	}
+	for _, dataType := range cfg.DataTypes {
+		if _, ok := kinds[dataType]; !ok {
+			return nil, fmt.Errorf("数据外发输出 %s 不支持数据类型 %d", name, dataType)
+		}
+		dataTypes[dataType] = true
+	}
+
+	producer, err := factory(cfg, logger.With(zap.String("sink", name)))
+	if err != nil {
+		return nil, fmt.Errorf("创建数据外发输出 %s 失败: %w", name, err)
+	}
+
+	return &output{
+		name:      name,
+		topic:     cfg.Topic,
+		dataTypes: dataTypes,
+		producer:  producer,
+	}, nil
+}
+
+// Publish 将记录放入投递缓冲区（不阻塞，缓冲区满时丢弃）
+func (d *Dispatcher) Publish(host Host, record *grpcProto.EncodedRecord) {
+	if d == nil || !d.wanted[record.DataType] {
+		return
+	}
+
+	select {
+	case d.ch <- &item{host: host, record: record, receivedAt: time.Now()}:
+	default:
+		metrics.RecordSinkRecord("dispatcher", "dropped")
+	}
+}
+
+// run 是投递协程
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case it := <-d.ch:
+			d.dispatch(it)
+		case <-d.done:
+			// 关闭前投递缓冲区中剩余的记录
+			for {
+				select {
+				case it := <-d.ch:
+					d.dispatch(it)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// dispatch 解码记录并投递到订阅了该数据类型的输出
+func (d *Dispatcher) dispatch(it *item) {
+	event, err := decode(it)
+	if err != nil {
+		d.logger.Debug("解码外发记录失败",
+			zap.String("agent_id", it.host.AgentID),
+			zap.Int32("data_type", it.record.DataType),
+			zap.Error(err),
+		)
+		metrics.RecordSinkRecord("dispatcher", "invalid")
+		return
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		metrics.RecordSinkRecord("dispatcher", "invalid")
+		return
+	}
+
+	for _, out := range d.outputs {
+		if !out.dataTypes[event.DataType] {
+			continue
+		}
+		msg := &Message{
+			Topic:    topicFor(out.topic, event),
+			Key:      event.Host.AgentID,
+			Value:    value,
+			DataType: event.DataType,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		err := out.producer.Publish(ctx, msg)
+		cancel()
+		if err != nil {
+			d.logger.Warn("数据外发失败",
+				zap.String("sink", out.name),
+				zap.Int32("data_type", event.DataType),
+				zap.Error(err),
+			)
+			metrics.RecordSinkRecord(out.name, "failed")
+			continue
+		}
+		metrics.RecordSinkRecord(out.name, "sent")
+	}
+}
+
+// Close 投递剩余记录并关闭所有输出
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	close(d.done)
+	d.wg.Wait()
+	d.closeOutputs()
+}
+
+// closeOutputs 关闭所有输出
+func (d *Dispatcher) closeOutputs() {
+	for _, out := range d.outputs {
+		if err := out.producer.Close(); err != nil {
+			d.logger.Warn("关闭数据外发输出失败", zap.String("sink", out.name), zap.Error(err))
+		}
+	}
+}
+
+// decode 将 EncodedRecord 解码为 Event
+func decode(it *item) (*Event, error) {
+	bridgeRecord := &bridge.Record{}
+	if err := proto.Unmarshal(it.record.Data, bridgeRecord); err != nil {
+		return nil, fmt.Errorf("解析 Record 失败: %w", err)
+	}
+
+	event := &Event{
+		Kind:       Kind(it.record.DataType),
+		DataType:   it.record.DataType,
+		Seq:        it.record.Seq,
+		Timestamp:  time.Unix(0, it.record.Timestamp),
+		ReceivedAt: it.receivedAt,
+		Host:       it.host,
+	}
+	if it.record.Timestamp == 0 {
+		event.Timestamp = it.receivedAt
+	}
+
+	if bridgeRecord.Data != nil && len(bridgeRecord.Data.Fields) > 0 {
+		fields := make(map[string]string, len(bridgeRecord.Data.Fields))
+		for k, v := range bridgeRecord.Data.Fields {
+			fields[k] = v
+		}
+		// 资产数据的 data 字段是 JSON，原样嵌入而不是作为字符串转义
+		if data, ok := fields["data"]; ok && json.Valid([]byte(data)) {
+			event.Data = json.RawMessage(data)
+			delete(fields, "data")
+		}
+		event.Fields = fields
+	}
+	return event, nil
+}
+
+// topicFor 根据主题模板生成主题
+func topicFor(template string, event *Event) string {
+	if template == "" {
+		template = "mxsec.{kind}"
+	}
+	return strings.NewReplacer(
+		"{kind}", event.Kind,
+		"{data_type}", strconv.Itoa(int(event.DataType)),
+	).Replace(template)
+}
@@ -0,0 +1,105 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+func encodeRecord(t *testing.T, dataType int32, fields map[string]string) *grpcProto.EncodedRecord {
+	t.Helper()
+	data, err := proto.Marshal(&bridge.Record{DataType: dataType, Data: &bridge.Payload{Fields: fields}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return &grpcProto.EncodedRecord{DataType: dataType, Timestamp: 1700000000000000000, Data: data}
+}
+
+// TestFileSinkDispatch 测试按数据类型过滤投递到 NDJSON 文件
+func TestFileSinkDispatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.ndjson")
+	d, err := NewDispatcher(config.SinkConfig{
+		BufferSize: 16,
+		Outputs: []config.SinkOutputConfig{{
+			Name:      "siem",
+			Type:      "file",
+			Enabled:   true,
+			DataTypes: []int32{8000, 5050},
+			File:      config.FileSinkConfig{Path: path, MaxSizeMB: 1, MaxBackups: 2},
+		}},
+	}, zap.NewNop())
+	if err != nil || d == nil {
+		t.Fatalf("NewDispatcher: %v", err)
+	}
+
+	host := Host{AgentID: "agent-1", Hostname: "web-01"}
+	d.Publish(host, encodeRecord(t, 8000, map[string]string{"rule_id": "R1", "status": "fail"}))
+	d.Publish(host, encodeRecord(t, 6001, map[string]string{"event_id": "E1"})) // 未订阅
+	d.Publish(host, encodeRecord(t, 5050, map[string]string{"data": `[{"pid":1}]`}))
+	d.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Kind != "baseline_result" || events[0].Fields["rule_id"] != "R1" || events[0].Host.Hostname != "web-01" {
+		t.Errorf("unexpected baseline event: %+v", events[0])
+	}
+	if events[1].Kind != "asset_process" || string(events[1].Data) != `[{"pid":1}]` {
+		t.Errorf("unexpected asset event: %+v", events[1])
+	}
+}
+
+// TestFileSinkRotate 测试文件按大小轮转并限制保留数量
+func TestFileSinkRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.ndjson")
+	p, err := newFileProducer(config.SinkOutputConfig{
+		File: config.FileSinkConfig{Path: path, MaxSizeMB: 1, MaxBackups: 1},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("newFileProducer: %v", err)
+	}
+	fp := p.(*fileProducer)
+	fp.maxSize = 100
+
+	line := make([]byte, 60)
+	for i := range line {
+		line[i] = 'x'
+	}
+	for i := 0; i < 4; i++ {
+		if err := p.Publish(t.Context(), &Message{Value: line}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, got %d", len(backups))
+	}
+}
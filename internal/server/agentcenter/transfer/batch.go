@@ -0,0 +1,203 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// scanResultUpdateColumns 是检查结果已存在时更新的列（与 handleBaselineResult 的更新字段一致）
+var scanResultUpdateColumns = []string{
+	"status", "actual", "expected", "checked_at", "severity", "fix_suggestion",
+	"task_id", "hostname", "policy_name", "container_name", "container_image",
+}
+
+// saveBaselineResults 批量保存同一 Agent 的基线检查结果（DataType 8000）
+// 每个主机（或容器）的每个规则只保留一条最新结果：已存在的结果复用原 result_id 覆盖更新
+func (s *Service) saveBaselineResults(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord) error {
+	// 解析并按 rule_id + container_id 去重（同一批次中后到的结果覆盖先到的）
+	results := make([]*model.ScanResult, 0, len(records))
+	index := make(map[string]int, len(records))
+	for _, record := range records {
+		scanResult, err := parseBaselineResult(record, conn)
+		if err != nil {
+			s.logger.Error("处理记录失败",
+				zap.Error(err),
+				zap.String("agent_id", conn.AgentID),
+				zap.Int32("data_type", record.DataType),
+			)
+			continue
+		}
+		key := scanResult.RuleID + "/" + scanResult.ContainerID
+		if i, ok := index[key]; ok {
+			results[i] = scanResult
+			continue
+		}
+		index[key] = len(results)
+		results = append(results, scanResult)
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	policyIDs := make([]string, 0)
+	ruleIDs := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.PolicyID != "" {
+			policyIDs = append(policyIDs, r.PolicyID)
+		}
+		ruleIDs = append(ruleIDs, r.RuleID)
+	}
+
+	// 获取策略名称（用于冗余存储，避免策略删除后数据丢失）
+	if len(policyIDs) > 0 {
+		var policies []model.Policy
+		if err := s.db.Select("id", "name").Where("id IN ?", policyIDs).Find(&policies).Error; err == nil {
+			names := make(map[string]string, len(policies))
+			for _, p := range policies {
+				names[p.ID] = p.Name
+			}
+			for _, r := range results {
+				r.PolicyName = names[r.PolicyID]
+			}
+		}
+	}
+
+	// 复用已存在结果的 result_id
+	var existing []model.ScanResult
+	err := s.db.Select("result_id", "rule_id", "container_id").
+		Where("host_id = ? AND rule_id IN ?", conn.AgentID, ruleIDs).
+		Find(&existing).Error
+	if err != nil {
+		return fmt.Errorf("查询检测结果失败: %w", err)
+	}
+	for _, e := range existing {
+		if i, ok := index[e.RuleID+"/"+e.ContainerID]; ok {
+			results[i].ResultID = e.ResultID
+		}
+	}
+
+	err = s.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "result_id"}},
+		DoUpdates: clause.AssignmentColumns(scanResultUpdateColumns),
+	}).CreateInBatches(results, s.cfg.Ingest.BatchSize).Error
+	if err != nil {
+		return fmt.Errorf("保存检测结果失败: %w", err)
+	}
+
+	s.logger.Debug("检测结果已批量保存",
+		zap.String("agent_id", conn.AgentID),
+		zap.Int("count", len(results)),
+	)
+
+	// 告警处理失败不影响检测结果保存
+	if err := s.syncBaselineAlerts(results, conn); err != nil {
+		s.logger.Warn("批量更新告警失败",
+			zap.String("agent_id", conn.AgentID),
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
+
+// syncBaselineAlerts 根据一批检查结果创建、更新或恢复告警，事务提交后发送告警和恢复通知
+func (s *Service) syncBaselineAlerts(results []*model.ScanResult, conn *Connection) error {
+	var sync *service.AlertSyncResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		sync, err = service.SyncBaselineAlerts(tx, results, s.cfg.Ingest.BatchSize, "检测通过，问题已修复")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 事务提交后发送告警通知和恢复通知
+	for _, alert := range sync.Created {
+		s.sendAlertNotification(alert, conn)
+	}
+	for _, alert := range sync.Resolved {
+		go s.sendAlertResolvedNotification(alert, conn)
+	}
+
+	s.logger.Debug("告警已批量更新",
+		zap.String("agent_id", conn.AgentID),
+		zap.Int("created", len(sync.Created)),
+		zap.Int("updated", sync.Updated),
+		zap.Int("resolved", len(sync.Resolved)),
+	)
+	return nil
+}
+
+// saveFIMEvents 批量保存同一 Agent 的 FIM 事件（DataType 6001）
+// event_id 已存在的事件是重发记录，跳过写入和计数，保证幂等
+func (s *Service) saveFIMEvents(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord) error {
+	events := make([]*model.FIMEvent, 0, len(records))
+	eventIDs := make([]string, 0, len(records))
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		fimEvent, err := s.parseFIMEvent(record, conn)
+		if err != nil {
+			s.logger.Error("处理记录失败",
+				zap.Error(err),
+				zap.String("agent_id", conn.AgentID),
+				zap.Int32("data_type", record.DataType),
+			)
+			continue
+		}
+		if fimEvent == nil || seen[fimEvent.EventID] {
+			continue
+		}
+		seen[fimEvent.EventID] = true
+		events = append(events, fimEvent)
+		eventIDs = append(eventIDs, fimEvent.EventID)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	var existing []string
+	if err := s.db.Model(&model.FIMEvent{}).Where("event_id IN ?", eventIDs).Pluck("event_id", &existing).Error; err != nil {
+		return fmt.Errorf("查询 FIM 事件失败: %w", err)
+	}
+	for _, id := range existing {
+		delete(seen, id)
+	}
+	fresh := make([]*model.FIMEvent, 0, len(seen))
+	taskCounts := make(map[string]int)
+	for _, e := range events {
+		if seen[e.EventID] {
+			fresh = append(fresh, e)
+			taskCounts[e.TaskID]++
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(fresh, s.cfg.Ingest.BatchSize).Error; err != nil {
+		return fmt.Errorf("保存 FIM 事件失败: %w", err)
+	}
+
+	// 递增任务的 total_events 计数
+	for taskID, count := range taskCounts {
+		s.db.Model(&model.FIMTask{}).
+			Where("task_id = ?", taskID).
+			Update("total_events", gorm.Expr("total_events + ?", count))
+	}
+
+	s.logger.Debug("FIM 事件已批量保存",
+		zap.String("host_id", conn.AgentID),
+		zap.Int("count", len(fresh)),
+		zap.Int("duplicate_count", len(existing)),
+	)
+	return nil
+}
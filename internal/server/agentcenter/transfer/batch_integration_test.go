@@ -0,0 +1,195 @@
+//go:build integration
+// +build integration
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+func newFIMTestService(t *testing.T) *Service {
+	t.Helper()
+	db := testdb.Open(t, &model.RecordReceipt{}, &model.FIMEvent{}, &model.FIMTask{})
+	if err := db.Create(&model.FIMTask{TaskID: "task-1", Status: "running"}).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	return &Service{
+		db:     db,
+		logger: zap.NewNop(),
+		cfg:    &config.Config{Ingest: config.IngestConfig{BatchSize: 100}},
+	}
+}
+
+func fimRecord(t *testing.T, seq uint64, eventID string) *grpcProto.EncodedRecord {
+	t.Helper()
+	data, err := proto.Marshal(&bridge.Record{Data: &bridge.Payload{Fields: map[string]string{
+		"event_id":    eventID,
+		"task_id":     "task-1",
+		"file_path":   "/etc/passwd",
+		"change_type": "changed",
+		"severity":    "high",
+	}}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return &grpcProto.EncodedRecord{DataType: 6001, Seq: seq, Data: data}
+}
+
+func savedEventIDs(t *testing.T, s *Service) []string {
+	t.Helper()
+	var ids []string
+	if err := s.db.Model(&model.FIMEvent{}).Pluck("event_id", &ids).Error; err != nil {
+		t.Fatalf("query events: %v", err)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedAcks(conn *Connection) []uint64 {
+	acks := conn.takeAcks()
+	sort.Slice(acks, func(i, j int) bool { return acks[i] < acks[j] })
+	return acks
+}
+
+// TestSaveFIMEventsSkipsMalformed 测试批量写入跳过无法解析的记录，同批其余事件正常保存并计数
+func TestSaveFIMEventsSkipsMalformed(t *testing.T) {
+	s := newFIMTestService(t)
+	conn := &Connection{AgentID: "agent-1"}
+	records := []*grpcProto.EncodedRecord{
+		fimRecord(t, 1, "evt-1"),
+		{DataType: 6001, Seq: 2, Data: []byte{0xff, 0xff}},
+		fimRecord(t, 3, "evt-3"),
+		fimRecord(t, 4, "evt-1"), // 同批重复的事件只保存一次
+	}
+
+	if err := s.saveFIMEvents(context.Background(), conn, records); err != nil {
+		t.Fatalf("saveFIMEvents: %v", err)
+	}
+	if ids := savedEventIDs(t, s); len(ids) != 2 || ids[0] != "evt-1" || ids[1] != "evt-3" {
+		t.Fatalf("saved events = %v, want [evt-1 evt-3]", ids)
+	}
+	var task model.FIMTask
+	s.db.First(&task, "task_id = ?", "task-1")
+	if task.TotalEvents != 2 {
+		t.Errorf("total_events = %d, want 2", task.TotalEvents)
+	}
+}
+
+// TestProcessRecordBatchFallback 测试批量处理失败时逐条处理，单条记录出错不影响同批其余记录，
+// 只确认处理成功的记录，失败记录的回执被释放以便重发后重新处理
+func TestProcessRecordBatchFallback(t *testing.T) {
+	s := newFIMTestService(t)
+	conn := &Connection{AgentID: "agent-1"}
+	records := []*grpcProto.EncodedRecord{
+		fimRecord(t, 1, "evt-1"),
+		{DataType: 6001, Seq: 2, Data: []byte{0xff, 0xff}},
+		fimRecord(t, 3, "evt-3"),
+	}
+	failing := func(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord) error {
+		return errors.New("batch insert failed")
+	}
+
+	s.processRecordBatch(context.Background(), conn, records, failing)
+
+	if ids := savedEventIDs(t, s); len(ids) != 2 || ids[0] != "evt-1" || ids[1] != "evt-3" {
+		t.Fatalf("saved events = %v, want [evt-1 evt-3]", ids)
+	}
+	if acks := sortedAcks(conn); len(acks) != 2 || acks[0] != 1 || acks[1] != 3 {
+		t.Fatalf("acks = %v, want [1 3]", acks)
+	}
+	var seqs []uint64
+	s.db.Model(&model.RecordReceipt{}).Where("host_id = ?", "agent-1").Order("seq").Pluck("seq", &seqs)
+	if len(seqs) != 2 || seqs[0] != 1 || seqs[1] != 3 {
+		t.Fatalf("receipts = %v, want [1 3]", seqs)
+	}
+}
+
+// TestProcessRecordFailure 测试单条处理失败时不确认并释放回执，Agent 重发后重新处理
+func TestProcessRecordFailure(t *testing.T) {
+	s := newFIMTestService(t)
+	conn := &Connection{AgentID: "agent-1"}
+
+	if err := s.processRecord(context.Background(), &grpcProto.EncodedRecord{DataType: 6001, Seq: 5, Data: []byte{0xff, 0xff}}, conn); err == nil {
+		t.Fatal("processRecord() should fail for malformed data")
+	}
+	if acks := conn.takeAcks(); len(acks) != 0 {
+		t.Fatalf("acks after failure = %v, want none", acks)
+	}
+	var count int64
+	s.db.Model(&model.RecordReceipt{}).Where("host_id = ? AND seq = ?", "agent-1", 5).Count(&count)
+	if count != 0 {
+		t.Fatalf("receipt kept after failure")
+	}
+
+	if err := s.processRecord(context.Background(), fimRecord(t, 5, "evt-5"), conn); err != nil {
+		t.Fatalf("processRecord retry: %v", err)
+	}
+	if acks := conn.takeAcks(); len(acks) != 1 || acks[0] != 5 {
+		t.Fatalf("acks after retry = %v, want [5]", acks)
+	}
+	if ids := savedEventIDs(t, s); len(ids) != 1 || ids[0] != "evt-5" {
+		t.Fatalf("saved events = %v, want [evt-5]", ids)
+	}
+}
+
+// TestProcessRecordBatchDedup 测试重发的重叠批次中已有回执的记录不再交给批量处理函数，但仍被确认
+func TestProcessRecordBatchDedup(t *testing.T) {
+	s := newFIMTestService(t)
+	conn := &Connection{AgentID: "agent-1"}
+	var handled [][]uint64
+	handler := func(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord) error {
+		seqs := make([]uint64, 0, len(records))
+		for _, record := range records {
+			seqs = append(seqs, record.Seq)
+		}
+		handled = append(handled, seqs)
+		return s.saveFIMEvents(ctx, conn, records)
+	}
+
+	s.processRecordBatch(context.Background(), conn, []*grpcProto.EncodedRecord{
+		fimRecord(t, 1, "evt-1"), fimRecord(t, 2, "evt-2"),
+	}, handler)
+	if acks := sortedAcks(conn); len(acks) != 2 {
+		t.Fatalf("first batch acks = %v, want [1 2]", acks)
+	}
+
+	// 重连后 Agent 重发未确认的记录，与新记录一起到达
+	s.processRecordBatch(context.Background(), conn, []*grpcProto.EncodedRecord{
+		fimRecord(t, 2, "evt-2"), fimRecord(t, 3, "evt-3"),
+	}, handler)
+	if len(handled) != 2 || len(handled[1]) != 1 || handled[1][0] != 3 {
+		t.Fatalf("handled batches = %v, want second batch [3]", handled)
+	}
+	if acks := sortedAcks(conn); len(acks) != 2 || acks[0] != 2 || acks[1] != 3 {
+		t.Fatalf("second batch acks = %v, want [2 3]", acks)
+	}
+
+	// 整批都是重复记录时不调用批量处理函数
+	s.processRecordBatch(context.Background(), conn, []*grpcProto.EncodedRecord{
+		fimRecord(t, 1, "evt-1"), fimRecord(t, 3, "evt-3"),
+	}, handler)
+	if len(handled) != 2 {
+		t.Fatalf("handler called for duplicate-only batch: %v", handled)
+	}
+	if acks := sortedAcks(conn); len(acks) != 2 {
+		t.Fatalf("duplicate batch acks = %v, want [1 3]", acks)
+	}
+
+	var task model.FIMTask
+	s.db.First(&task, "task_id = ?", "task-1")
+	if task.TotalEvents != 3 {
+		t.Errorf("total_events = %d, want 3", task.TotalEvents)
+	}
+}
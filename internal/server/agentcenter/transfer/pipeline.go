@@ -0,0 +1,298 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/metrics"
+)
+
+// 入库队列名称（同时作为指标的 queue 标签）
+const (
+	queueBaseline = "baseline"
+	queueFIM      = "fim"
+	queueAsset    = "asset"
+	queueDefault  = "default"
+)
+
+// queueDepthInterval 是队列深度指标的采样间隔
+const queueDepthInterval = 5 * time.Second
+
+// errPipelineClosed 表示入库流水线已关闭
+var errPipelineClosed = errors.New("入库流水线已关闭")
+
+// batchHandler 批量处理同一 Agent 的同类记录，仅在数据库写入失败时返回错误
+type batchHandler func(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord) error
+
+// ingestItem 是入库队列中的一条记录
+type ingestItem struct {
+	record     *grpcProto.EncodedRecord
+	conn       *Connection
+	enqueuedAt time.Time
+}
+
+// ingestQueue 是一类数据的有界队列
+// 队列按 Agent ID 分片，每个分片由一个工作协程顺序处理，保证同一 Agent 的记录按上报顺序入库
+// （例如任务完成信号总是在该任务的检查结果之后处理）
+type ingestQueue struct {
+	name      string
+	shards    []chan *ingestItem
+	batchType int32        // 可批量写入的数据类型（0 表示不批量）
+	batch     batchHandler // 批量处理函数
+}
+
+// ingestPipeline 是 AgentCenter 的入库流水线
+type ingestPipeline struct {
+	svc    *Service
+	cfg    config.IngestConfig
+	logger *zap.Logger
+	queues map[string]*ingestQueue
+
+	ctx  context.Context // 入库处理使用的 context（不随单个 Agent 连接断开而取消）
+	done chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// newIngestPipeline 创建入库流水线并启动工作协程
+func newIngestPipeline(svc *Service, cfg config.IngestConfig) *ingestPipeline {
+	p := &ingestPipeline{
+		svc:    svc,
+		cfg:    cfg,
+		logger: svc.logger,
+		queues: make(map[string]*ingestQueue),
+		ctx:    context.Background(),
+		done:   make(chan struct{}),
+	}
+
+	p.addQueue(queueBaseline, 8000, svc.saveBaselineResults)
+	p.addQueue(queueFIM, 6001, svc.saveFIMEvents)
+	p.addQueue(queueAsset, 0, nil)
+	p.addQueue(queueDefault, 0, nil)
+
+	p.wg.Add(1)
+	go p.sampleDepth()
+
+	svc.logger.Info("入库流水线已启动",
+		zap.Int("workers", cfg.Workers),
+		zap.Int("queue_size", cfg.QueueSize),
+		zap.Int("batch_size", cfg.BatchSize),
+		zap.Duration("batch_wait", cfg.BatchWait),
+	)
+	return p
+}
+
+// addQueue 创建队列并为每个分片启动工作协程
+func (p *ingestPipeline) addQueue(name string, batchType int32, batch batchHandler) {
+	q := &ingestQueue{
+		name:      name,
+		shards:    make([]chan *ingestItem, p.cfg.Workers),
+		batchType: batchType,
+		batch:     batch,
+	}
+	for i := range q.shards {
+		q.shards[i] = make(chan *ingestItem, p.cfg.QueueSize)
+		p.wg.Add(1)
+		go p.runShard(q, q.shards[i])
+	}
+	p.queues[name] = q
+}
+
+// queueFor 根据数据类型选择队列
+func (p *ingestPipeline) queueFor(dataType int32) *ingestQueue {
+	switch {
+	case dataType == 8000 || dataType == 8001 || dataType == 8003 || dataType == 8004:
+		return p.queues[queueBaseline]
+	case dataType == 6001 || dataType == 6002:
+		return p.queues[queueFIM]
+	case (dataType >= 5050 && dataType <= 5060) || dataType == 5099:
+		return p.queues[queueAsset]
+	default:
+		return p.queues[queueDefault]
+	}
+}
+
+// enqueue 将记录放入对应队列
+// 队列已满时阻塞（Recv 循环随之停止读取，由 gRPC 流控反压 Agent），直到有空位或连接断开；
+// 队列使用率超过高水位时额外通知 Agent 暂停上报
+func (p *ingestPipeline) enqueue(ctx context.Context, record *grpcProto.EncodedRecord, conn *Connection) error {
+	q := p.queueFor(record.DataType)
+	shard := q.shards[shardIndex(conn.AgentID, len(q.shards))]
+
+	if float64(len(shard)) >= float64(cap(shard))*p.cfg.HighWatermark {
+		p.throttle(q, conn)
+	}
+
+	item := &ingestItem{record: record, conn: conn, enqueuedAt: time.Now()}
+	select {
+	case shard <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return errPipelineClosed
+	}
+}
+
+// throttle 通知 Agent 暂停上报插件数据（同一连接在暂停时长内只通知一次）
+func (p *ingestPipeline) throttle(q *ingestQueue, conn *Connection) {
+	now := time.Now().UnixNano()
+	last := conn.lastThrottle.Load()
+	if now-last < int64(p.cfg.ThrottleTime) || !conn.lastThrottle.CompareAndSwap(last, now) {
+		return
+	}
+
+	cmd := &grpcProto.Command{ThrottleMs: uint32(p.cfg.ThrottleTime.Milliseconds())}
+	select {
+	case conn.sendCh <- cmd:
+		metrics.RecordIngestThrottle(q.name)
+		p.logger.Warn("入库队列接近饱和，通知 Agent 暂停上报",
+			zap.String("queue", q.name),
+			zap.String("agent_id", conn.AgentID),
+			zap.Duration("throttle", p.cfg.ThrottleTime),
+		)
+	default:
+		// 发送队列已满时放弃本次通知，阻塞入队本身即可反压
+	}
+}
+
+// runShard 是分片工作协程：可批量的记录累积到 BatchSize 或等待 BatchWait 后批量写入，
+// 其他记录到达时先写入已累积的批次，再单条处理，保证同一 Agent 的处理顺序
+func (p *ingestPipeline) runShard(q *ingestQueue, ch chan *ingestItem) {
+	defer p.wg.Done()
+
+	var pending []*ingestItem
+	timer := time.NewTimer(p.cfg.BatchWait)
+	timer.Stop()
+
+	flush := func() {
+		if len(pending) > 0 {
+			p.processBatch(q, pending)
+			pending = nil
+		}
+		timer.Stop()
+	}
+	handle := func(item *ingestItem) {
+		if q.batch != nil && item.record.DataType == q.batchType {
+			pending = append(pending, item)
+			if len(pending) == 1 {
+				timer.Reset(p.cfg.BatchWait)
+			}
+			if len(pending) >= p.cfg.BatchSize {
+				flush()
+			}
+			return
+		}
+		flush()
+		p.processOne(q, item)
+	}
+
+	for {
+		select {
+		case item := <-ch:
+			handle(item)
+		case <-timer.C:
+			flush()
+		case <-p.done:
+			// 关闭时处理完队列中剩余的记录
+			for {
+				select {
+				case item := <-ch:
+					handle(item)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// processOne 单条处理记录
+func (p *ingestPipeline) processOne(q *ingestQueue, item *ingestItem) {
+	if err := p.svc.processRecord(p.ctx, item.record, item.conn); err != nil {
+		p.logger.Error("处理记录失败",
+			zap.Error(err),
+			zap.String("agent_id", item.conn.AgentID),
+			zap.Int32("data_type", item.record.DataType),
+		)
+	}
+	metrics.RecordIngestLatency(q.name, time.Since(item.enqueuedAt).Seconds())
+}
+
+// processBatch 按 Agent 分组批量处理记录（分组内保持上报顺序）
+func (p *ingestPipeline) processBatch(q *ingestQueue, items []*ingestItem) {
+	metrics.RecordIngestBatch(q.name, len(items))
+
+	var conns []*Connection
+	groups := make(map[*Connection][]*grpcProto.EncodedRecord)
+	for _, item := range items {
+		if _, ok := groups[item.conn]; !ok {
+			conns = append(conns, item.conn)
+		}
+		groups[item.conn] = append(groups[item.conn], item.record)
+	}
+	for _, conn := range conns {
+		p.svc.processRecordBatch(p.ctx, conn, groups[conn], q.batch)
+	}
+
+	for _, item := range items {
+		metrics.RecordIngestLatency(q.name, time.Since(item.enqueuedAt).Seconds())
+	}
+}
+
+// sampleDepth 定期采样各队列深度
+func (p *ingestPipeline) sampleDepth() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(queueDepthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			for name, q := range p.queues {
+				depth := 0
+				for _, shard := range q.shards {
+					depth += len(shard)
+				}
+				metrics.RecordIngestQueueDepth(name, depth)
+			}
+		}
+	}
+}
+
+// close 停止接收新记录，等待队列中已有记录处理完成（最多等待 timeout）
+// 超时未处理的记录没有确认，Agent 重连后会重发
+func (p *ingestPipeline) close(timeout time.Duration) {
+	p.once.Do(func() { close(p.done) })
+
+	finished := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		p.logger.Info("入库流水线已关闭")
+	case <-time.After(timeout):
+		p.logger.Warn("入库流水线关闭超时，剩余记录将由 Agent 重发", zap.Duration("timeout", timeout))
+	}
+}
+
+// shardIndex 计算 Agent 所属分片
+func shardIndex(agentID string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(agentID))
+	return int(h.Sum32() % uint32(n))
+}
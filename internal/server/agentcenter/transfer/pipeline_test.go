@@ -0,0 +1,134 @@
+package transfer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+// newTestPipeline 创建只有 FIM 队列（6001 批量写入）和默认队列的入库流水线，批量处理函数记录每批的记录数
+func newTestPipeline(t *testing.T, cfg config.IngestConfig) (*ingestPipeline, <-chan int) {
+	t.Helper()
+	batches := make(chan int, 16)
+	svc := &Service{logger: zap.NewNop()}
+	p := &ingestPipeline{
+		svc:    svc,
+		cfg:    cfg,
+		logger: svc.logger,
+		queues: make(map[string]*ingestQueue),
+		ctx:    context.Background(),
+		done:   make(chan struct{}),
+	}
+	p.addQueue(queueFIM, 6001, func(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord) error {
+		batches <- len(records)
+		return nil
+	})
+	p.addQueue(queueDefault, 0, nil)
+	t.Cleanup(func() { p.close(time.Second) })
+	return p, batches
+}
+
+func enqueueFIM(t *testing.T, p *ingestPipeline, conn *Connection, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := p.enqueue(context.Background(), &grpcProto.EncodedRecord{DataType: 6001}, conn); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+}
+
+func waitBatch(t *testing.T, batches <-chan int) int {
+	t.Helper()
+	select {
+	case n := <-batches:
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for batch")
+		return 0
+	}
+}
+
+// TestPipelineFlushOnSize 测试累积到 BatchSize 时立即批量写入，不足一批的记录在关闭时写入
+func TestPipelineFlushOnSize(t *testing.T) {
+	p, batches := newTestPipeline(t, config.IngestConfig{
+		Workers: 1, QueueSize: 16, BatchSize: 3, BatchWait: time.Hour, HighWatermark: 1,
+	})
+	enqueueFIM(t, p, &Connection{AgentID: "agent-1"}, 7)
+
+	for i := 0; i < 2; i++ {
+		if n := waitBatch(t, batches); n != 3 {
+			t.Fatalf("batch %d size = %d, want 3", i, n)
+		}
+	}
+	select {
+	case n := <-batches:
+		t.Fatalf("unexpected batch of %d before BatchWait", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.close(time.Second)
+	if n := waitBatch(t, batches); n != 1 {
+		t.Fatalf("final batch size = %d, want 1", n)
+	}
+}
+
+// TestPipelineFlushOnInterval 测试不足 BatchSize 的记录在 BatchWait 后批量写入
+func TestPipelineFlushOnInterval(t *testing.T) {
+	const wait = 30 * time.Millisecond
+	p, batches := newTestPipeline(t, config.IngestConfig{
+		Workers: 1, QueueSize: 16, BatchSize: 100, BatchWait: wait, HighWatermark: 1,
+	})
+	start := time.Now()
+	enqueueFIM(t, p, &Connection{AgentID: "agent-1"}, 2)
+
+	if n := waitBatch(t, batches); n != 2 {
+		t.Fatalf("batch size = %d, want 2", n)
+	}
+	if elapsed := time.Since(start); elapsed < wait {
+		t.Errorf("batch flushed after %v, want at least %v", elapsed, wait)
+	}
+}
+
+// TestPipelineFlushBeforeOther 测试不可批量的记录到达时先写入已累积的批次，保证同一 Agent 的处理顺序
+func TestPipelineFlushBeforeOther(t *testing.T) {
+	p, batches := newTestPipeline(t, config.IngestConfig{
+		Workers: 1, QueueSize: 16, BatchSize: 100, BatchWait: time.Hour, HighWatermark: 1,
+	})
+	conn := &Connection{AgentID: "agent-1"}
+	enqueueFIM(t, p, conn, 2)
+
+	// 直接放入同一分片一条不可批量的记录（心跳记录单条处理时不访问数据库）
+	q := p.queues[queueFIM]
+	item := &ingestItem{record: &grpcProto.EncodedRecord{DataType: 1000}, conn: conn, enqueuedAt: time.Now()}
+	q.shards[shardIndex(conn.AgentID, len(q.shards))] <- item
+
+	if n := waitBatch(t, batches); n != 2 {
+		t.Fatalf("batch size = %d, want 2", n)
+	}
+}
+
+// TestPipelineThrottle 测试队列超过高水位时通知 Agent 暂停上报，暂停时长内只通知一次
+func TestPipelineThrottle(t *testing.T) {
+	svc := &Service{logger: zap.NewNop()}
+	p := &ingestPipeline{
+		svc:    svc,
+		cfg:    config.IngestConfig{Workers: 1, QueueSize: 4, HighWatermark: 0.5, ThrottleTime: time.Minute},
+		logger: svc.logger,
+	}
+	q := &ingestQueue{name: queueDefault, shards: []chan *ingestItem{make(chan *ingestItem, 4)}}
+	conn := &Connection{AgentID: "agent-1", sendCh: make(chan *grpcProto.Command, 4)}
+
+	p.throttle(q, conn)
+	p.throttle(q, conn)
+	if len(conn.sendCh) != 1 {
+		t.Fatalf("sent %d throttle commands, want 1", len(conn.sendCh))
+	}
+	if cmd := <-conn.sendCh; cmd.ThrottleMs != uint32(time.Minute.Milliseconds()) {
+		t.Errorf("ThrottleMs = %d, want %d", cmd.ThrottleMs, time.Minute.Milliseconds())
+	}
+}
@@ -0,0 +1,32 @@
+package transfer
+
+import (
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// TestPluginLimitsToProto 测试未设置任何限制时不下发，设置任一项时完整转换
+func TestPluginLimitsToProto(t *testing.T) {
+	if got := pluginLimitsToProto(model.PluginLimits{}); got != nil {
+		t.Fatalf("empty limits = %v, want nil", got)
+	}
+
+	tests := []model.PluginLimits{
+		{CPUPercent: 50},
+		{MemoryMaxMB: 128},
+		{IOWeight: 200},
+		{PidsMax: 16},
+		{CPUPercent: 200, MemoryMaxMB: 512, IOWeight: 1000, PidsMax: 128},
+	}
+	for _, l := range tests {
+		got := pluginLimitsToProto(l)
+		if got == nil {
+			t.Fatalf("pluginLimitsToProto(%+v) = nil", l)
+		}
+		if got.CpuPercent != l.CPUPercent || got.MemoryMaxMb != l.MemoryMaxMB ||
+			got.IoWeight != l.IOWeight || got.PidsMax != l.PidsMax {
+			t.Errorf("pluginLimitsToProto(%+v) = %v", l, got)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// ackFlushInterval 是批量下发记录确认的间隔
+const ackFlushInterval = time.Second
+
+// processRecord 处理单条记录；带序列号的事件类记录按 (agent_id, seq) 去重，处理成功后确认
+// 先写入回执认领记录再处理，重连后并发到达的重发记录只有认领成功的一方处理；处理失败时释放认领，由 Agent 重发
+func (s *Service) processRecord(ctx context.Context, record *grpcProto.EncodedRecord, conn *Connection) error {
+	if record.Seq == 0 {
+		return s.handleEncodedRecord(ctx, record, conn)
+	}
+
+	claimID := uuid.New().String()
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.RecordReceipt{
+		HostID:     conn.AgentID,
+		Seq:        record.Seq,
+		DataType:   record.DataType,
+		ClaimID:    claimID,
+		ReceivedAt: model.ToLocalTime(time.Now()),
+	})
+	if result.Error != nil {
+		// 无法认领时不处理也不确认，由 Agent 稍后重发
+		return result.Error
+	}
+	if isDuplicateRecord(result) {
+		s.logger.Debug("跳过重复记录",
+			zap.String("agent_id", conn.AgentID),
+			zap.Uint64("seq", record.Seq),
+			zap.Int32("data_type", record.DataType),
+		)
+		conn.ack(record.Seq)
+		return nil
+	}
+
+	if err := s.handleEncodedRecord(ctx, record, conn); err != nil {
+		s.releaseClaims(conn, claimID, []uint64{record.Seq})
+		return err
+	}
+	conn.ack(record.Seq)
+	return nil
+}
+
+// processRecordBatch 批量处理同一 Agent 的同类记录
+// 先批量写入回执认领记录，已有回执的重发记录直接确认；批量写入失败时回退为逐条处理，
+// 只确认处理成功的记录，处理失败的记录释放认领，由 Agent 重发
+func (s *Service) processRecordBatch(ctx context.Context, conn *Connection, records []*grpcProto.EncodedRecord, handler batchHandler) {
+	claimID := uuid.New().String()
+	fresh, claimed, err := s.claimRecords(conn, claimID, records)
+	if err != nil {
+		// 无法认领时不处理也不确认，由 Agent 稍后重发
+		s.logger.Warn("写入记录回执失败，等待 Agent 重发",
+			zap.String("agent_id", conn.AgentID),
+			zap.Int("record_count", len(records)),
+			zap.Error(err),
+		)
+		return
+	}
+	if len(fresh) == 0 {
+		return
+	}
+
+	if err := handler(ctx, conn, fresh); err != nil {
+		s.logger.Warn("批量入库失败，回退为逐条处理",
+			zap.String("agent_id", conn.AgentID),
+			zap.Int("record_count", len(fresh)),
+			zap.Error(err),
+		)
+		// 记录已认领，逐条处理时不再检查回执
+		var failed []uint64
+		for _, record := range fresh {
+			if err := s.handleEncodedRecord(ctx, record, conn); err != nil {
+				s.logger.Error("处理记录失败",
+					zap.Error(err),
+					zap.String("agent_id", conn.AgentID),
+					zap.Int32("data_type", record.DataType),
+				)
+				if record.Seq != 0 {
+					failed = append(failed, record.Seq)
+				}
+				continue
+			}
+			if record.Seq != 0 {
+				conn.ack(record.Seq)
+			}
+		}
+		if len(failed) > 0 {
+			s.releaseClaims(conn, claimID, failed)
+		}
+		return
+	}
+	for _, seq := range claimed {
+		conn.ack(seq)
+	}
+}
+
+// releaseClaims 删除本次认领的回执，使 Agent 重发的记录能被重新处理
+// 删除失败时回执保留，重发的记录会被当作重复记录确认
+func (s *Service) releaseClaims(conn *Connection, claimID string, seqs []uint64) {
+	if err := s.db.Where("host_id = ? AND seq IN ? AND claim_id = ?", conn.AgentID, seqs, claimID).
+		Delete(&model.RecordReceipt{}).Error; err != nil {
+		s.logger.Error("释放记录回执失败",
+			zap.Error(err),
+			zap.String("agent_id", conn.AgentID),
+			zap.Int("record_count", len(seqs)),
+		)
+	}
+}
+
+// claimRecords 为一批记录写入回执（已存在的不覆盖），返回需要处理的记录和本次认领的序列号
+// 回执带有本次认领的随机标识，据此区分本次写入的回执与已处理（或被并发处理）的记录，后者直接确认
+func (s *Service) claimRecords(conn *Connection, claimID string, records []*grpcProto.EncodedRecord) ([]*grpcProto.EncodedRecord, []uint64, error) {
+	now := model.ToLocalTime(time.Now())
+	receipts := make([]*model.RecordReceipt, 0, len(records))
+	seqs := make([]uint64, 0, len(records))
+	for _, record := range records {
+		if record.Seq == 0 {
+			continue
+		}
+		receipts = append(receipts, &model.RecordReceipt{
+			HostID:     conn.AgentID,
+			Seq:        record.Seq,
+			DataType:   record.DataType,
+			ClaimID:    claimID,
+			ReceivedAt: now,
+		})
+		seqs = append(seqs, record.Seq)
+	}
+	if len(receipts) == 0 {
+		return records, nil, nil
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(receipts, len(receipts)).Error; err != nil {
+		return nil, nil, err
+	}
+	var claimed []uint64
+	if err := s.db.Model(&model.RecordReceipt{}).
+		Where("host_id = ? AND seq IN ? AND claim_id = ?", conn.AgentID, seqs, claimID).
+		Pluck("seq", &claimed).Error; err != nil {
+		// 回执已写入但无法确认归属：不处理也不确认，重发的记录会被当作重复记录确认
+		return nil, nil, err
+	}
+
+	mine := make(map[uint64]bool, len(claimed))
+	for _, seq := range claimed {
+		mine[seq] = true
+	}
+	fresh := make([]*grpcProto.EncodedRecord, 0, len(records))
+	duplicates := 0
+	for _, record := range records {
+		switch {
+		case record.Seq == 0 || mine[record.Seq]:
+			fresh = append(fresh, record)
+		default:
+			duplicates++
+			conn.ack(record.Seq)
+		}
+	}
+	if duplicates > 0 {
+		s.logger.Debug("跳过重复记录",
+			zap.String("agent_id", conn.AgentID),
+			zap.Int("duplicate_count", duplicates),
+		)
+	}
+	return fresh, claimed, nil
+}
+
+// ack 记录待确认的序列号
+func (c *Connection) ack(seq uint64) {
+	c.ackMu.Lock()
+	c.pendingAcks = append(c.pendingAcks, seq)
+	c.ackMu.Unlock()
+}
+
+// takeAcks 取出所有待确认的序列号
+func (c *Connection) takeAcks() []uint64 {
+	c.ackMu.Lock()
+	defer c.ackMu.Unlock()
+	seqs := c.pendingAcks
+	c.pendingAcks = nil
+	return seqs
+}
+
+// flushAcks 批量下发记录确认（在 sendLoop 中调用，与其他命令共用同一个 stream）
+func (s *Service) flushAcks(conn *Connection) error {
+	seqs := conn.takeAcks()
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	if err := conn.stream.Send(&grpcProto.Command{AckSeqs: seqs}); err != nil {
+		if conn.ctx.Err() != nil || status.Code(err) == codes.Canceled || status.Code(err) == codes.Unavailable {
+			s.logger.Debug("Agent 连接已关闭，确认下发中止",
+				zap.String("agent_id", conn.AgentID),
+				zap.String("reason", err.Error()),
+			)
+		} else {
+			s.logger.Error("下发记录确认失败",
+				zap.Error(err),
+				zap.String("agent_id", conn.AgentID),
+				zap.Int("ack_count", len(seqs)),
+			)
+		}
+		return err
+	}
+
+	s.logger.Debug("记录确认已下发",
+		zap.String("agent_id", conn.AgentID),
+		zap.Int("ack_count", len(seqs)),
+	)
+	return nil
+}
+
+// isDuplicateRecord 判断 Create 是否因记录已存在而未插入（OnConflict DoNothing）
+func isDuplicateRecord(result *gorm.DB) bool {
+	return result.Error == nil && result.RowsAffected == 0
+}
@@ -0,0 +1,102 @@
+//go:build integration
+// +build integration
+
+package transfer
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestClaimRecords 测试重连后重复到达的重叠批次中每条记录只被认领一次
+func TestClaimRecords(t *testing.T) {
+	db := testdb.Open(t, &model.RecordReceipt{})
+	s := &Service{db: db, logger: zap.NewNop()}
+
+	records := func(from, to uint64) []*grpcProto.EncodedRecord {
+		var out []*grpcProto.EncodedRecord
+		for seq := from; seq <= to; seq++ {
+			out = append(out, &grpcProto.EncodedRecord{DataType: 6001, Seq: seq})
+		}
+		return out
+	}
+
+	const workers = 4
+	claimed := make([][]uint64, workers)
+	conns := make([]*Connection, workers)
+	for i := 0; i < workers; i++ {
+		conns[i] = &Connection{AgentID: "agent-1"}
+		// 各连接的批次互相重叠：1-20、6-25、11-30、16-35
+		batch := records(uint64(1+5*i), uint64(20+5*i))
+		fresh, seqs, err := s.claimRecords(conns[i], uuid.New().String(), batch)
+		if err != nil {
+			t.Fatalf("claimRecords: %v", err)
+		}
+		if len(fresh) != len(seqs) {
+			t.Fatalf("fresh %d records but claimed %d", len(fresh), len(seqs))
+		}
+		claimed[i] = seqs
+	}
+
+	owner := make(map[uint64]int)
+	for i, seqs := range claimed {
+		for _, seq := range seqs {
+			if prev, ok := owner[seq]; ok {
+				t.Fatalf("seq %d claimed by both connection %d and %d", seq, prev, i)
+			}
+			owner[seq] = i
+		}
+	}
+	if len(owner) != 35 {
+		t.Fatalf("expected 35 claimed records, got %d", len(owner))
+	}
+
+	// 未认领的重复记录直接确认，认领的记录由调用方处理后确认
+	for i, conn := range conns {
+		acks := conn.takeAcks()
+		all := append(acks, claimed[i]...)
+		sort.Slice(all, func(a, b int) bool { return all[a] < all[b] })
+		if len(all) != 20 || all[0] != uint64(1+5*i) || all[19] != uint64(20+5*i) {
+			t.Fatalf("connection %d: acked %v + claimed %v does not cover its batch", i, acks, claimed[i])
+		}
+	}
+}
+
+// TestProcessRecordDuplicate 测试单条处理先认领回执，重复记录跳过处理并确认
+func TestProcessRecordDuplicate(t *testing.T) {
+	db := testdb.Open(t, &model.RecordReceipt{})
+	s := &Service{db: db, logger: zap.NewNop()}
+	record := &grpcProto.EncodedRecord{DataType: 1000, Seq: 7}
+
+	var wg sync.WaitGroup
+	conns := []*Connection{{AgentID: "agent-1"}, {AgentID: "agent-1"}, {AgentID: "agent-1"}}
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *Connection) {
+			defer wg.Done()
+			if err := s.processRecord(t.Context(), record, conn); err != nil {
+				t.Errorf("processRecord: %v", err)
+			}
+		}(conn)
+	}
+	wg.Wait()
+
+	var count int64
+	db.Model(&model.RecordReceipt{}).Where("host_id = ? AND seq = ?", "agent-1", 7).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 receipt, got %d", count)
+	}
+	for i, conn := range conns {
+		if acks := conn.takeAcks(); len(acks) != 1 || acks[0] != 7 {
+			t.Fatalf("connection %d acks = %v, want [7]", i, acks)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package transfer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -19,29 +20,37 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
 	grpcProto "github.com/imkerbos/mxsec-platform/api/proto/grpc"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/cluster"
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/sink"
 	"github.com/imkerbos/mxsec-platform/internal/server/config"
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
+	"github.com/imkerbos/mxsec-platform/internal/server/metrics"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
 // Connection 表示一个 Agent 连接
 type Connection struct {
-	AgentID   string
-	Hostname  string
-	IPv4      []string
-	IPv6      []string
-	Version   string
-	LastSeen  time.Time
-	stream    grpc.BidiStreamingServer[grpcProto.PackagedData, grpcProto.Command]
-	ctx       context.Context
-	cancel    context.CancelFunc
-	sendCh    chan *grpcProto.Command
-	workerSem chan struct{} // 限制异步 record 处理的并发数
-	mu        sync.RWMutex
+	AgentID  string
+	Hostname string
+	IPv4     []string
+	IPv6     []string
+	Version  string
+	LastSeen time.Time
+	stream   grpc.BidiStreamingServer[grpcProto.PackagedData, grpcProto.Command]
+	ctx      context.Context
+	cancel   context.CancelFunc
+	sendCh   chan *grpcProto.Command
+	mu       sync.RWMutex
+
+	ackMu       sync.Mutex
+	pendingAcks []uint64 // 待确认的事件类记录序列号（由 sendLoop 批量下发）
+
+	lastThrottle atomic.Int64 // 最近一次下发背压通知的时间（UnixNano）
 }
 
 // Service 是 Transfer 服务实现
@@ -58,6 +67,15 @@ type Service struct {
 	connections map[string]*Connection
 	connMu      sync.RWMutex
 
+	// 入库流水线：上报记录按数据类型排队，批量写入数据库
+	pipeline *ingestPipeline
+
+	// 数据外发：解码后的记录异步投递到 NDJSON 文件或消息总线（未配置时为 nil）
+	sink *sink.Dispatcher
+
+	// 集群节点：多实例部署时记录连接归属并转发命令（单实例部署时为 nil）
+	cluster *cluster.Node
+
 	// 优雅关闭标志：Server 自身重启时跳过离线通知，避免假告警
 	shutdownFlag atomic.Bool
 }
@@ -117,7 +135,7 @@ func NewService(db *gorm.DB, logger *zap.Logger, cfg *config.Config) *Service {
 		)
 	}
 
-	return &Service{
+	svc := &Service{
 		db:               db,
 		logger:           logger,
 		cfg:              cfg,
@@ -126,6 +144,23 @@ func NewService(db *gorm.DB, logger *zap.Logger, cfg *config.Config) *Service {
 		prometheusClient: prometheusClient,
 		connections:      make(map[string]*Connection),
 	}
+
+	// 资产快照确认通过命令通道下发给 collector 插件
+	assetService.SetSnapshotAckSender(svc.SendCommand)
+
+	svc.pipeline = newIngestPipeline(svc, cfg.Ingest)
+
+	return svc
+}
+
+// SetCluster 设置集群节点（多实例部署时由初始化流程注入）
+func (s *Service) SetCluster(node *cluster.Node) {
+	s.cluster = node
+}
+
+// SetSink 设置数据外发 Dispatcher（由初始化流程注入）
+func (s *Service) SetSink(d *sink.Dispatcher) {
+	s.sink = d
 }
 
 // Transfer 实现双向流 RPC
@@ -157,21 +192,21 @@ func (s *Service) Transfer(stream grpc.BidiStreamingServer[grpcProto.PackagedDat
 
 	// 创建连接对象
 	conn := &Connection{
-		AgentID:   agentID,
-		Hostname:  firstData.Hostname,
-		IPv4:      append(firstData.IntranetIpv4, firstData.ExtranetIpv4...),
-		IPv6:      append(firstData.IntranetIpv6, firstData.ExtranetIpv6...),
-		Version:   firstData.Version,
-		LastSeen:  time.Now(),
-		stream:    stream,
-		ctx:       ctx,
-		cancel:    cancel,
-		sendCh:    make(chan *grpcProto.Command, 10),
-		workerSem: make(chan struct{}, 10),
+		AgentID:  agentID,
+		Hostname: firstData.Hostname,
+		IPv4:     append(firstData.IntranetIpv4, firstData.ExtranetIpv4...),
+		IPv6:     append(firstData.IntranetIpv6, firstData.ExtranetIpv6...),
+		Version:  firstData.Version,
+		LastSeen: time.Now(),
+		stream:   stream,
+		ctx:      ctx,
+		cancel:   cancel,
+		sendCh:   make(chan *grpcProto.Command, 10),
 	}
 
 	// 注册连接
 	s.registerConnection(agentID, conn)
+	s.cluster.BindAgent(agentID)
 	defer s.unregisterConnection(agentID, conn)
 
 	// 检查并发送 Agent 上线恢复通知（如果之前离线）
@@ -266,22 +301,26 @@ func (s *Service) handlePackagedData(ctx context.Context, data *grpcProto.Packag
 		s.logger.Error("处理心跳失败", zap.Error(err), zap.String("agent_id", conn.AgentID))
 	}
 
-	// 异步处理 EncodedRecord 列表（避免重 DB 操作阻塞 Recv 循环导致 agent 超时断连）
+	// 外发记录附带的主机信息
+	var host sink.Host
+	if s.sink != nil {
+		conn.mu.RLock()
+		host = sink.Host{
+			AgentID:  conn.AgentID,
+			Hostname: conn.Hostname,
+			IPv4:     conn.IPv4,
+			IPv6:     conn.IPv6,
+			Version:  conn.Version,
+		}
+		conn.mu.RUnlock()
+	}
+
+	// EncodedRecord 放入入库队列异步处理（避免重 DB 操作阻塞 Recv 循环导致 agent 超时断连）
 	for _, record := range data.Records {
-		select {
-		case conn.workerSem <- struct{}{}:
-			go func() {
-				defer func() { <-conn.workerSem }()
-				if err := s.handleEncodedRecord(ctx, record, conn); err != nil {
-					s.logger.Error("处理记录失败",
-						zap.Error(err),
-						zap.String("agent_id", conn.AgentID),
-						zap.Int32("data_type", record.DataType),
-					)
-				}
-			}()
-		case <-ctx.Done():
-			return ctx.Err()
+		metrics.RecordTransferRecord(record.DataType, len(record.Data))
+		s.sink.Publish(host, record)
+		if err := s.pipeline.enqueue(ctx, record, conn); err != nil {
+			return err
 		}
 	}
 
@@ -302,6 +341,7 @@ func (s *Service) handleHeartbeat(ctx context.Context, data *grpcProto.PackagedD
 	var runtimeType model.RuntimeType = model.RuntimeTypeVM // 默认为 VM
 	var podName, podNamespace, podUID string
 	var hasHeartbeatData bool // 是否包含心跳数据
+	var guardState, guardStatus string // Agent 资源保护状态
 	if len(data.Records) > 0 {
 		for _, record := range data.Records {
 			if record.DataType == 1000 { // 心跳数据类型
@@ -410,6 +450,11 @@ func (s *Service) handleHeartbeat(ctx context.Context, data *grpcProto.PackagedD
 								zap.String("agent_id", conn.AgentID),
 								zap.String("business_line", businessLine))
 						}
+						// 解析资源保护状态（限流/熔断）
+						if gs, ok := fields["guard_state"]; ok && gs != "" {
+							guardState = gs
+							guardStatus = fields["guard_status"]
+						}
 						// 解析并存储插件状态
 						if pluginStatsStr, ok := fields["plugin_stats"]; ok && pluginStatsStr != "" {
 							if err := s.storeHostPlugins(ctx, conn.AgentID, pluginStatsStr); err != nil {
@@ -535,6 +580,9 @@ func (s *Service) handleHeartbeat(ctx context.Context, data *grpcProto.PackagedD
 		AgentStartTime: model.ToLocalTimePtr(agentStartTime),
 		// 业务线（如果 Agent 提供了，则使用；否则保持现有值）
 		BusinessLine: businessLine,
+		// 资源保护状态
+		GuardState:  guardState,
+		GuardStatus: guardStatus,
 	}
 
 	// 使用 Save 方法（如果不存在则创建，存在则更新）
@@ -578,6 +626,8 @@ func (s *Service) handleHeartbeat(ctx context.Context, data *grpcProto.PackagedD
 			updates["pod_uid"] = podUID
 			updates["system_boot_time"] = systemBootTime
 			updates["agent_start_time"] = agentStartTime
+			updates["guard_state"] = guardState
+			updates["guard_status"] = guardStatus
 		}
 		// 如果 Agent 提供了业务线，则更新（仅在首次设置或 Agent 明确提供时更新）
 		if businessLine != "" {
@@ -636,11 +686,12 @@ func (s *Service) handleHeartbeat(ctx context.Context, data *grpcProto.PackagedD
 // storeHostPlugins 存储主机插件状态
 func (s *Service) storeHostPlugins(ctx context.Context, hostID string, pluginStatsJSON string) error {
 	// 解析插件状态 JSON
-	// 格式: {"baseline": {"status": "running", "version": "1.0.3", "start_time": 1234567890}, ...}
+	// 格式: {"baseline": {"status": "running", "version": "1.0.3", "start_time": 1234567890, "limit_hits": {...}}, ...}
 	var pluginStats map[string]struct {
-		Status    string `json:"status"`
-		Version   string `json:"version"`
-		StartTime int64  `json:"start_time"`
+		Status    string                 `json:"status"`
+		Version   string                 `json:"version"`
+		StartTime int64                  `json:"start_time"`
+		LimitHits *model.PluginLimitHits `json:"limit_hits"`
 	}
 
 	if err := json.Unmarshal([]byte(pluginStatsJSON), &pluginStats); err != nil {
@@ -684,6 +735,7 @@ func (s *Service) storeHostPlugins(ctx context.Context, hostID string, pluginSta
 			Version:   stats.Version,
 			Status:    status,
 			StartTime: startTime,
+			LimitHits: stats.LimitHits,
 		}
 
 		// 查找现有记录（排除软删除的记录）
@@ -695,6 +747,13 @@ func (s *Service) storeHostPlugins(ctx context.Context, hostID string, pluginSta
 				"version":    stats.Version,
 				"status":     status,
 				"start_time": startTime,
+				"limit_hits": stats.LimitHits,
+			}
+			if stats.LimitHits != nil && existing.LimitHits != nil && stats.LimitHits.OOMKill > existing.LimitHits.OOMKill {
+				s.logger.Warn("插件触发内存上限被 OOM Kill",
+					zap.String("host_id", hostID),
+					zap.String("plugin_name", name),
+					zap.Int64("oom_kill", stats.LimitHits.OOMKill))
 			}
 			if err := s.db.Model(&existing).Updates(updates).Error; err != nil {
 				s.logger.Error("更新插件状态失败",
@@ -883,6 +942,9 @@ func (s *Service) handleEncodedRecord(ctx context.Context, record *grpcProto.Enc
 		// 资产数据
 		return s.assetService.HandleAssetData(conn.AgentID, record.DataType, record.Data)
 
+	case 5099: // 资产按需采集完成信号
+		return s.assetService.HandleCollectComplete(conn.AgentID, record.Data)
+
 	default:
 		s.logger.Debug("未知数据类型",
 			zap.String("agent_id", conn.AgentID),
@@ -894,15 +956,102 @@ func (s *Service) handleEncodedRecord(ctx context.Context, record *grpcProto.Enc
 
 // handleBaselineResult 处理基线检查结果
 func (s *Service) handleBaselineResult(ctx context.Context, record *grpcProto.EncodedRecord, conn *Connection) error {
+	scanResult, err := parseBaselineResult(record, conn)
+	if err != nil {
+		return err
+	}
+	resultID := scanResult.ResultID
+	hostID := scanResult.HostID
+	ruleID := scanResult.RuleID
+	containerID := scanResult.ContainerID
+	resultStatus := scanResult.Status
+
+	// 获取策略名称（用于冗余存储，避免策略删除后数据丢失）
+	if scanResult.PolicyID != "" {
+		var policy model.Policy
+		if err := s.db.Select("name").Where("id = ?", scanResult.PolicyID).First(&policy).Error; err == nil {
+			scanResult.PolicyName = policy.Name
+		}
+	}
+
+	// 保存到数据库（使用 UPSERT 去重：基于 host_id + rule_id + container_id 唯一约束）
+	// 每个主机（或主机上的每个容器）的每个规则只保留一条最新结果，新检查会覆盖旧结果
+	var existingResult model.ScanResult
+	err = s.db.Where("host_id = ? AND rule_id = ? AND container_id = ?", hostID, ruleID, containerID).First(&existingResult).Error
+
+	if err == gorm.ErrRecordNotFound {
+		// 不存在，创建新记录
+		if err := s.db.Create(scanResult).Error; err != nil {
+			return fmt.Errorf("保存检测结果失败: %w", err)
+		}
+		s.logger.Debug("检测结果已保存",
+			zap.String("agent_id", conn.AgentID),
+			zap.String("result_id", resultID),
+			zap.String("rule_id", ruleID),
+			zap.String("status", string(resultStatus)),
+		)
+	} else if err == nil {
+		// 已存在，更新记录
+		existingResult.Status = scanResult.Status
+		existingResult.Actual = scanResult.Actual
+		existingResult.Expected = scanResult.Expected
+		existingResult.CheckedAt = scanResult.CheckedAt
+		existingResult.Severity = scanResult.Severity
+		existingResult.FixSuggestion = scanResult.FixSuggestion
+		existingResult.TaskID = scanResult.TaskID // 更新为最新任务ID
+		existingResult.Hostname = scanResult.Hostname
+		existingResult.PolicyName = scanResult.PolicyName
+		existingResult.ContainerName = scanResult.ContainerName
+		existingResult.ContainerImage = scanResult.ContainerImage
+
+		if err := s.db.Save(&existingResult).Error; err != nil {
+			return fmt.Errorf("更新检测结果失败: %w", err)
+		}
+		// 使用已存在的 result_id 继续后续处理
+		scanResult = &existingResult
+		s.logger.Debug("检测结果已更新",
+			zap.String("agent_id", conn.AgentID),
+			zap.String("result_id", existingResult.ResultID),
+			zap.String("rule_id", ruleID),
+			zap.String("status", string(resultStatus)),
+		)
+	} else {
+		return fmt.Errorf("查询检测结果失败: %w", err)
+	}
+
+	// 如果检测结果为 fail，创建或更新告警
+	if resultStatus == model.ResultStatusFail {
+		if err := s.createOrUpdateAlert(scanResult, conn); err != nil {
+			s.logger.Warn("创建或更新告警失败",
+				zap.String("result_id", resultID),
+				zap.Error(err),
+			)
+			// 不中断流程，告警创建失败不影响检测结果保存
+		}
+	} else if resultStatus == model.ResultStatusPass {
+		// 如果检测结果为 pass，检查是否有活跃告警需要恢复
+		if err := s.resolveAlertIfExists(scanResult, conn); err != nil {
+			s.logger.Warn("解决告警失败",
+				zap.String("result_id", resultID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+// parseBaselineResult 将基线检查结果记录解析为 ScanResult（不含策略名称）
+func parseBaselineResult(record *grpcProto.EncodedRecord, conn *Connection) (*model.ScanResult, error) {
 	// 解析 EncodedRecord.data 为 bridge.Record
 	bridgeRecord := &bridge.Record{}
 	if err := proto.Unmarshal(record.Data, bridgeRecord); err != nil {
-		return fmt.Errorf("解析 Record 失败: %w", err)
+		return nil, fmt.Errorf("解析 Record 失败: %w", err)
 	}
 
 	// 从 Payload 中提取字段
 	if bridgeRecord.Data == nil {
-		return fmt.Errorf("Record.Data 为空")
+		return nil, fmt.Errorf("Record.Data 为空")
 	}
 	fields := bridgeRecord.Data.Fields
 
@@ -927,6 +1076,7 @@ func (s *Service) handleBaselineResult(ctx context.Context, record *grpcProto.En
 	actual := fields["actual"]
 	expected := fields["expected"]
 	fixSuggestion := fields["fix_suggestion"]
+	containerID := fields["container_id"] // 容器内检查结果（宿主机检查为空）
 
 	// 解析时间戳
 	timestamp := time.Unix(0, record.Timestamp)
@@ -949,22 +1099,12 @@ func (s *Service) handleBaselineResult(ctx context.Context, record *grpcProto.En
 		resultStatus = model.ResultStatusError
 	}
 
-	// 获取策略名称（用于冗余存储，避免策略删除后数据丢失）
-	var policyName string
-	if policyID != "" {
-		var policy model.Policy
-		if err := s.db.Select("name").Where("id = ?", policyID).First(&policy).Error; err == nil {
-			policyName = policy.Name
-		}
-	}
-
 	// 创建 ScanResult
 	scanResult := &model.ScanResult{
 		ResultID:      resultID,
 		HostID:        hostID,
 		Hostname:      conn.Hostname, // 冗余存储主机名
 		PolicyID:      policyID,
-		PolicyName:    policyName, // 冗余存储策略名
 		RuleID:        ruleID,
 		TaskID:        taskID,
 		Status:        resultStatus,
@@ -975,71 +1115,13 @@ func (s *Service) handleBaselineResult(ctx context.Context, record *grpcProto.En
 		Expected:      expected,
 		FixSuggestion: fixSuggestion,
 		CheckedAt:     model.ToLocalTime(timestamp),
-	}
-
-	// 保存到数据库（使用 UPSERT 去重：基于 host_id + rule_id 唯一约束）
-	// 每个主机的每个规则只保留一条最新结果，新检查会覆盖旧结果
-	var existingResult model.ScanResult
-	err := s.db.Where("host_id = ? AND rule_id = ?", hostID, ruleID).First(&existingResult).Error
-
-	if err == gorm.ErrRecordNotFound {
-		// 不存在，创建新记录
-		if err := s.db.Create(scanResult).Error; err != nil {
-			return fmt.Errorf("保存检测结果失败: %w", err)
-		}
-		s.logger.Debug("检测结果已保存",
-			zap.String("agent_id", conn.AgentID),
-			zap.String("result_id", resultID),
-			zap.String("rule_id", ruleID),
-			zap.String("status", string(resultStatus)),
-		)
-	} else if err == nil {
-		// 已存在，更新记录
-		existingResult.Status = scanResult.Status
-		existingResult.Actual = scanResult.Actual
-		existingResult.Expected = scanResult.Expected
-		existingResult.CheckedAt = scanResult.CheckedAt
-		existingResult.Severity = scanResult.Severity
-		existingResult.FixSuggestion = scanResult.FixSuggestion
-		existingResult.TaskID = scanResult.TaskID // 更新为最新任务ID
-		existingResult.Hostname = scanResult.Hostname
-		existingResult.PolicyName = scanResult.PolicyName
-
-		if err := s.db.Save(&existingResult).Error; err != nil {
-			return fmt.Errorf("更新检测结果失败: %w", err)
-		}
-		// 使用已存在的 result_id 继续后续处理
-		scanResult = &existingResult
-		s.logger.Debug("检测结果已更新",
-			zap.String("agent_id", conn.AgentID),
-			zap.String("result_id", existingResult.ResultID),
-			zap.String("rule_id", ruleID),
-			zap.String("status", string(resultStatus)),
-		)
-	} else {
-		return fmt.Errorf("查询检测结果失败: %w", err)
-	}
 
-	// 如果检测结果为 fail，创建或更新告警
-	if resultStatus == model.ResultStatusFail {
-		if err := s.createOrUpdateAlert(scanResult, conn); err != nil {
-			s.logger.Warn("创建或更新告警失败",
-				zap.String("result_id", resultID),
-				zap.Error(err),
-			)
-			// 不中断流程，告警创建失败不影响检测结果保存
-		}
-	} else if resultStatus == model.ResultStatusPass {
-		// 如果检测结果为 pass，检查是否有活跃告警需要恢复
-		if err := s.resolveAlertIfExists(scanResult, conn); err != nil {
-			s.logger.Warn("解决告警失败",
-				zap.String("result_id", resultID),
-				zap.Error(err),
-			)
-		}
+		ContainerID:    containerID,
+		ContainerName:  fields["container_name"],
+		ContainerImage: fields["container_image"],
 	}
 
-	return nil
+	return scanResult, nil
 }
 
 // createOrUpdateAlert 创建或更新告警
@@ -1137,55 +1219,58 @@ func (s *Service) resolveAlertIfExists(scanResult *model.ScanResult, conn *Conne
 	)
 
 	// 发送告警恢复通知（异步，不阻塞）
-	go func() {
-		// 查询主机信息
-		var host model.Host
-		if err := s.db.First(&host, "host_id = ?", existingAlert.HostID).Error; err != nil {
-			s.logger.Warn("查询主机信息失败", zap.String("host_id", existingAlert.HostID), zap.Error(err))
-			return
-		}
+	go s.sendAlertResolvedNotification(&existingAlert, conn)
 
-		// 查询规则信息
-		var rule model.Rule
-		if err := s.db.First(&rule, "rule_id = ?", existingAlert.RuleID).Error; err != nil {
-			s.logger.Warn("查询规则信息失败", zap.String("rule_id", existingAlert.RuleID), zap.Error(err))
-		}
+	return nil
+}
 
-		// 获取主机 IP
-		hostIP := ""
-		if len(host.IPv4) > 0 {
-			hostIP = strings.Join(host.IPv4, ",")
-		} else if len(conn.IPv4) > 0 {
-			hostIP = strings.Join(conn.IPv4, ",")
-		}
+// sendAlertResolvedNotification 发送告警恢复通知
+func (s *Service) sendAlertResolvedNotification(alert *model.Alert, conn *Connection) {
+	// 查询主机信息
+	var host model.Host
+	if err := s.db.First(&host, "host_id = ?", alert.HostID).Error; err != nil {
+		s.logger.Warn("查询主机信息失败", zap.String("host_id", alert.HostID), zap.Error(err))
+		return
+	}
 
-		// 构建恢复数据
-		resolvedData := &biz.AlertResolvedData{
-			HostID:      existingAlert.HostID,
-			Hostname:    host.Hostname,
-			IP:          hostIP,
-			OSFamily:    host.OSFamily,
-			OSVersion:   host.OSVersion,
-			RuleID:      existingAlert.RuleID,
-			RuleName:    rule.Title,
-			Category:    existingAlert.Category,
-			Severity:    existingAlert.Severity,
-			Title:       existingAlert.Title,
-			FirstSeenAt: existingAlert.FirstSeenAt.Time(),
-			ResolvedAt:  time.Now(),
-			ResultID:    existingAlert.ResultID,
-		}
+	// 查询规则信息
+	var rule model.Rule
+	if err := s.db.First(&rule, "rule_id = ?", alert.RuleID).Error; err != nil {
+		s.logger.Warn("查询规则信息失败", zap.String("rule_id", alert.RuleID), zap.Error(err))
+	}
 
-		notificationService := biz.NewNotificationService(s.db, s.logger)
-		if err := notificationService.SendAlertResolvedNotification(resolvedData); err != nil {
-			s.logger.Warn("发送告警恢复通知失败",
-				zap.Uint("alert_id", existingAlert.ID),
-				zap.Error(err),
-			)
-		}
-	}()
+	// 获取主机 IP
+	hostIP := ""
+	if len(host.IPv4) > 0 {
+		hostIP = strings.Join(host.IPv4, ",")
+	} else if len(conn.IPv4) > 0 {
+		hostIP = strings.Join(conn.IPv4, ",")
+	}
 
-	return nil
+	// 构建恢复数据
+	resolvedData := &biz.AlertResolvedData{
+		HostID:      alert.HostID,
+		Hostname:    host.Hostname,
+		IP:          hostIP,
+		OSFamily:    host.OSFamily,
+		OSVersion:   host.OSVersion,
+		RuleID:      alert.RuleID,
+		RuleName:    rule.Title,
+		Category:    alert.Category,
+		Severity:    alert.Severity,
+		Title:       alert.Title,
+		FirstSeenAt: alert.FirstSeenAt.Time(),
+		ResolvedAt:  time.Now(),
+		ResultID:    alert.ResultID,
+	}
+
+	notificationService := biz.NewNotificationService(s.db, s.logger)
+	if err := notificationService.SendAlertResolvedNotification(resolvedData); err != nil {
+		s.logger.Warn("发送告警恢复通知失败",
+			zap.Uint("alert_id", alert.ID),
+			zap.Error(err),
+		)
+	}
 }
 
 // handleTaskCompletion 处理任务完成信号
@@ -1457,11 +1542,18 @@ func (s *Service) sendAlertNotification(alert *model.Alert, conn *Connection) {
 func (s *Service) sendLoop(conn *Connection) {
 	s.logger.Debug("sendLoop goroutine started", zap.String("agent_id", conn.AgentID))
 
+	ackTicker := time.NewTicker(ackFlushInterval)
+	defer ackTicker.Stop()
+
 	for {
 		select {
 		case <-conn.ctx.Done():
 			s.logger.Debug("sendLoop goroutine stopping (context canceled)", zap.String("agent_id", conn.AgentID))
 			return
+		case <-ackTicker.C:
+			if err := s.flushAcks(conn); err != nil {
+				return
+			}
 		case cmd := <-conn.sendCh:
 			hasCertBundle := cmd.CertificateBundle != nil
 			hasAgentConfig := cmd.AgentConfig != nil
@@ -1606,6 +1698,13 @@ func (s *Service) unregisterConnection(agentID string, connToRemove *Connection)
 		return
 	}
 
+	// 集群模式：删除本实例的归属，若 Agent 已重连到其他实例则不视为离线
+	s.cluster.UnbindAgent(agentID)
+	if s.cluster.ConnectedElsewhere(agentID) {
+		s.logger.Info("Agent 已迁移到其他实例，跳过离线处理", zap.String("agent_id", agentID))
+		return
+	}
+
 	// 查询主机信息用于发送离线通知
 	var host model.Host
 	if err := s.db.First(&host, "host_id = ?", agentID).Error; err != nil {
@@ -1790,6 +1889,31 @@ func (s *Service) sendCertificateBundleIfNeeded(ctx context.Context, conn *Conne
 	}
 }
 
+// pluginLimitsToProto 转换插件资源限制，未设置任何限制时返回 nil
+func pluginLimitsToProto(l model.PluginLimits) *grpcProto.PluginLimits {
+	if l == (model.PluginLimits{}) {
+		return nil
+	}
+	return &grpcProto.PluginLimits{
+		CpuPercent:  l.CPUPercent,
+		MemoryMaxMb: l.MemoryMaxMB,
+		IoWeight:    l.IOWeight,
+		PidsMax:     l.PidsMax,
+	}
+}
+
+// pluginSandboxToProto 转换插件加固选项，未启用任何选项时返回 nil
+func pluginSandboxToProto(sb model.PluginSandbox) *grpcProto.PluginSandbox {
+	if !sb.NoNewPrivs && !sb.MinimalEnv && len(sb.DropCapabilities) == 0 {
+		return nil
+	}
+	return &grpcProto.PluginSandbox{
+		NoNewPrivs:       sb.NoNewPrivs,
+		DropCapabilities: []string(sb.DropCapabilities),
+		MinimalEnv:       sb.MinimalEnv,
+	}
+}
+
 // buildPluginDownloadURLs 构建插件下载URL（处理相对路径）
 // 优先从系统配置读取后端地址，确保与 Agent 更新使用相同的 URL
 func (s *Service) buildPluginDownloadURLs(originalURLs []string, pluginName string) []string {
@@ -1870,6 +1994,8 @@ func (s *Service) buildPluginDownloadURLs(originalURLs []string, pluginName stri
 }
 
 // sendPluginConfigsIfNeeded 下发插件配置给 Agent
+// 重连同步不受维护窗口限制：Agent 重启后没有插件配置，服务端只保存最新配置，不下发则插件无法启动。
+// 同步的已是最新配置，因此同时删除该主机等待维护窗口的插件配置暂缓记录
 func (s *Service) sendPluginConfigsIfNeeded(ctx context.Context, conn *Connection) error {
 	// 从数据库查询启用的插件配置
 	var pluginConfigs []model.PluginConfig
@@ -1896,6 +2022,8 @@ func (s *Service) sendPluginConfigsIfNeeded(ctx context.Context, conn *Connectio
 			Signature:    pc.Signature,
 			DownloadUrls: downloadURLs,
 			Detail:       pc.Detail,
+			Limits:       pluginLimitsToProto(pc.Limits),
+			Sandbox:      pluginSandboxToProto(pc.Sandbox),
 		}
 		configs = append(configs, config)
 	}
@@ -1917,6 +2045,9 @@ func (s *Service) sendPluginConfigsIfNeeded(ctx context.Context, conn *Connectio
 			zap.String("agent_id", conn.AgentID),
 			zap.Int("plugin_count", len(configs)),
 		)
+		if err := s.db.Where("host_id = ?", conn.AgentID).Delete(&model.PluginConfigDeferral{}).Error; err != nil {
+			s.logger.Warn("删除插件配置暂缓记录失败", zap.String("agent_id", conn.AgentID), zap.Error(err))
+		}
 		return nil
 	case <-conn.ctx.Done():
 		return fmt.Errorf("连接已关闭: %s", conn.AgentID)
@@ -1928,13 +2059,23 @@ func (s *Service) sendPluginConfigsIfNeeded(ctx context.Context, conn *Connectio
 }
 
 // SendCommand 向指定 Agent 发送命令（供其他模块调用）
+// Agent 不在本实例时，集群模式下转发给持有连接的实例
 func (s *Service) SendCommand(agentID string, cmd *grpcProto.Command) error {
+	err := s.DeliverLocal(agentID, cmd)
+	if errors.Is(err, cluster.ErrAgentNotConnected) && s.cluster.Enabled() {
+		return s.cluster.Forward(agentID, cmd)
+	}
+	return err
+}
+
+// DeliverLocal 向本实例持有的 Agent 连接发送命令
+func (s *Service) DeliverLocal(agentID string, cmd *grpcProto.Command) error {
 	s.connMu.RLock()
 	conn, ok := s.connections[agentID]
 	s.connMu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("agent 未连接: %s", agentID)
+		return fmt.Errorf("%w: %s", cluster.ErrAgentNotConnected, agentID)
 	}
 
 	select {
@@ -1947,9 +2088,16 @@ func (s *Service) SendCommand(agentID string, cmd *grpcProto.Command) error {
 	}
 }
 
-// BroadcastPluginConfigs 向所有在线 Agent 广播插件配置（用于推送更新）
+// BroadcastPluginConfigs 向本实例的所有在线 Agent 广播插件配置（用于推送更新）
+// 集群模式下每个实例各自运行插件更新调度器，只广播到自己持有的连接
 // 返回成功发送的 Agent 数量和失败的 Agent 列表
 func (s *Service) BroadcastPluginConfigs(ctx context.Context) (int, []string, error) {
+	return s.SendPluginConfigs(ctx, s.GetOnlineAgentIDs())
+}
+
+// SendPluginConfigs 向本实例持有连接的指定 Agent 发送插件配置（维护窗口只允许部分主机更新时使用）
+// 连接不在本实例的 Agent 计为失败
+func (s *Service) SendPluginConfigs(ctx context.Context, agentIDs []string) (int, []string, error) {
 	// 从数据库查询启用的插件配置
 	var pluginConfigs []model.PluginConfig
 	if err := s.db.Where("enabled = ?", true).Find(&pluginConfigs).Error; err != nil {
@@ -1975,6 +2123,8 @@ func (s *Service) BroadcastPluginConfigs(ctx context.Context) (int, []string, er
 			Signature:    pc.Signature,
 			DownloadUrls: downloadURLs,
 			Detail:       pc.Detail,
+			Limits:       pluginLimitsToProto(pc.Limits),
+			Sandbox:      pluginSandboxToProto(pc.Sandbox),
 		}
 		configs = append(configs, config)
 	}
@@ -1984,26 +2134,31 @@ func (s *Service) BroadcastPluginConfigs(ctx context.Context) (int, []string, er
 		Configs: configs,
 	}
 
-	// 获取所有在线连接
+	// 获取目标 Agent 的连接
+	successCount := 0
+	var failedAgents []string
+
 	s.connMu.RLock()
-	connections := make([]*Connection, 0, len(s.connections))
-	for _, conn := range s.connections {
-		connections = append(connections, conn)
+	connections := make([]*Connection, 0, len(agentIDs))
+	for _, agentID := range agentIDs {
+		if conn, ok := s.connections[agentID]; ok {
+			connections = append(connections, conn)
+		} else {
+			failedAgents = append(failedAgents, agentID)
+		}
 	}
 	s.connMu.RUnlock()
 
 	if len(connections) == 0 {
 		s.logger.Info("没有在线的 Agent，跳过广播")
-		return 0, nil, nil
+		return 0, failedAgents, nil
 	}
 
-	s.logger.Info("开始广播插件配置到所有在线 Agent",
+	s.logger.Info("开始广播插件配置到在线 Agent",
 		zap.Int("agent_count", len(connections)),
 		zap.Int("plugin_count", len(configs)))
 
 	// 向每个连接发送配置
-	successCount := 0
-	var failedAgents []string
 
 	for _, conn := range connections {
 		select {
@@ -2035,14 +2190,20 @@ func (s *Service) GracefulShutdown() {
 	s.logger.Info("Transfer 服务进入优雅关闭，后续断连不发送离线通知")
 }
 
-// GetOnlineAgentCount 获取在线 Agent 数量
+// Close 关闭入库流水线和数据外发，等待已接收的记录入库和外发（应在 gRPC Server 停止后、数据库关闭前调用）
+func (s *Service) Close() {
+	s.pipeline.close(10 * time.Second)
+	s.sink.Close()
+}
+
+// GetOnlineAgentCount 获取本实例的在线 Agent 数量
 func (s *Service) GetOnlineAgentCount() int {
 	s.connMu.RLock()
 	defer s.connMu.RUnlock()
 	return len(s.connections)
 }
 
-// GetOnlineAgentIDs 获取所有在线 Agent ID 列表
+// GetOnlineAgentIDs 获取本实例的在线 Agent ID 列表
 func (s *Service) GetOnlineAgentIDs() []string {
 	s.connMu.RLock()
 	defer s.connMu.RUnlock()
@@ -2120,9 +2281,17 @@ func (s *Service) handleFixResult(ctx context.Context, record *grpcProto.Encoded
 		FixedAt:  model.ToLocalTime(timestamp),
 	}
 
-	// 保存到数据库
-	if err := s.db.Create(fixResult).Error; err != nil {
-		return fmt.Errorf("保存修复结果失败: %w", err)
+	// 保存到数据库（result_id 已存在说明是重发的记录，跳过后续统计，保证幂等）
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(fixResult)
+	if result.Error != nil {
+		return fmt.Errorf("保存修复结果失败: %w", result.Error)
+	}
+	if isDuplicateRecord(result) {
+		s.logger.Debug("修复结果已存在，跳过",
+			zap.String("agent_id", conn.AgentID),
+			zap.String("result_id", resultID),
+		)
+		return nil
 	}
 
 	s.logger.Debug("修复结果已保存",
@@ -2335,13 +2504,49 @@ func (s *Service) handleFixTaskComplete(ctx context.Context, record *grpcProto.E
 
 // handleFIMEvent 处理 FIM 事件（DataType 6001）
 func (s *Service) handleFIMEvent(ctx context.Context, record *grpcProto.EncodedRecord, conn *Connection) error {
+	fimEvent, err := s.parseFIMEvent(record, conn)
+	if err != nil || fimEvent == nil {
+		return err
+	}
+
+	// event_id 已存在说明是重发的记录，跳过计数，保证幂等
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(fimEvent)
+	if result.Error != nil {
+		return fmt.Errorf("保存 FIM 事件失败: %w", result.Error)
+	}
+	if isDuplicateRecord(result) {
+		s.logger.Debug("FIM 事件已存在，跳过",
+			zap.String("event_id", fimEvent.EventID),
+			zap.String("host_id", conn.AgentID),
+		)
+		return nil
+	}
+
+	// 递增任务的 total_events 计数
+	s.db.Model(&model.FIMTask{}).
+		Where("task_id = ?", fimEvent.TaskID).
+		Update("total_events", gorm.Expr("total_events + 1"))
+
+	s.logger.Debug("FIM 事件已保存",
+		zap.String("event_id", fimEvent.EventID),
+		zap.String("host_id", conn.AgentID),
+		zap.String("file_path", fimEvent.FilePath),
+		zap.String("change_type", fimEvent.ChangeType),
+		zap.String("severity", fimEvent.Severity),
+	)
+
+	return nil
+}
+
+// parseFIMEvent 将 FIM 事件记录解析为 FIMEvent（缺少必要字段时返回 nil）
+func (s *Service) parseFIMEvent(record *grpcProto.EncodedRecord, conn *Connection) (*model.FIMEvent, error) {
 	bridgeRecord := &bridge.Record{}
 	if err := proto.Unmarshal(record.Data, bridgeRecord); err != nil {
-		return fmt.Errorf("解析 FIM 事件失败: %w", err)
+		return nil, fmt.Errorf("解析 FIM 事件失败: %w", err)
 	}
 
 	if bridgeRecord.Data == nil {
-		return fmt.Errorf("FIM 事件 Record.Data 为空")
+		return nil, fmt.Errorf("FIM 事件 Record.Data 为空")
 	}
 	fields := bridgeRecord.Data.Fields
 
@@ -2360,7 +2565,7 @@ func (s *Service) handleFIMEvent(ctx context.Context, record *grpcProto.EncodedR
 			zap.String("event_id", eventID),
 			zap.String("task_id", taskID),
 		)
-		return nil
+		return nil, nil
 	}
 
 	// 解析 change_detail JSON
@@ -2390,24 +2595,7 @@ func (s *Service) handleFIMEvent(ctx context.Context, record *grpcProto.EncodedR
 		DetectedAt:   detectedAt,
 	}
 
-	if err := s.db.Create(fimEvent).Error; err != nil {
-		return fmt.Errorf("保存 FIM 事件失败: %w", err)
-	}
-
-	// 递增任务的 total_events 计数
-	s.db.Model(&model.FIMTask{}).
-		Where("task_id = ?", taskID).
-		Update("total_events", gorm.Expr("total_events + 1"))
-
-	s.logger.Debug("FIM 事件已保存",
-		zap.String("event_id", eventID),
-		zap.String("host_id", conn.AgentID),
-		zap.String("file_path", filePath),
-		zap.String("change_type", changeType),
-		zap.String("severity", severity),
-	)
-
-	return nil
+	return fimEvent, nil
 }
 
 // handleFIMTaskCompletion 处理 FIM 任务完成信号（DataType 6002）
@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -14,13 +15,108 @@ import (
 
 // Config 是 Server 配置结构
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	MTLS     MTLSConfig     `mapstructure:"mtls"`
-	Log      LogConfig      `mapstructure:"log"`
-	Agent    AgentConfig    `mapstructure:"agent"`
-	Metrics  MetricsConfig  `mapstructure:"metrics"`
-	Plugins  PluginsConfig  `mapstructure:"plugins"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	MTLS        MTLSConfig        `mapstructure:"mtls"`
+	Log         LogConfig         `mapstructure:"log"`
+	Agent       AgentConfig       `mapstructure:"agent"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Plugins     PluginsConfig     `mapstructure:"plugins"`
+	Cluster     ClusterConfig     `mapstructure:"cluster"`
+	Ingest      IngestConfig      `mapstructure:"ingest"`
+	Sink        SinkConfig        `mapstructure:"sink"`
+	OfflineScan OfflineScanConfig `mapstructure:"offline_scan"`
+	Audit       AuditConfig       `mapstructure:"audit"`
+}
+
+// AuditConfig 是操作审计日志配置
+type AuditConfig struct {
+	HashChain bool `mapstructure:"hash_chain"` // 开启哈希链：每条记录包含上一条记录的哈希，可通过校验接口发现篡改或删除
+}
+
+// OfflineScanConfig 是离线基线检查结果导入配置
+// 无法连接 AgentCenter 的主机使用 mxsec-agent --scan 生成签名（HMAC-SHA256）结果包，签名密钥是由 SigningKey
+// 和主机 Agent ID 派生的主机密钥，Manager 导入时按结果包中的 Agent ID 校验；未配置 SigningKey 时导入接口不可用
+type OfflineScanConfig struct {
+	SigningKey    string `mapstructure:"signing_key"`     // 主密钥（至少 16 字节，只保存在 Manager，不分发到离线主机）
+	MaxBundleSize int    `mapstructure:"max_bundle_size"` // 结果包最大大小（MB，默认 20）
+}
+
+// SinkConfig 是 Agent 数据外发配置
+// AgentCenter 将解码后的记录（JSON，附带主机信息）异步投递到配置的输出（NDJSON 文件、Kafka、NATS 等），
+// 供 SIEM / 数据湖消费；外发失败或缓冲区满时丢弃记录，不影响入库
+type SinkConfig struct {
+	BufferSize int                `mapstructure:"buffer_size"` // 待投递记录缓冲区大小（默认 10000）
+	Outputs    []SinkOutputConfig `mapstructure:"outputs"`
+}
+
+// SinkOutputConfig 是单个外发输出配置
+type SinkOutputConfig struct {
+	Name      string            `mapstructure:"name"`       // 输出名称（用于日志和指标）
+	Type      string            `mapstructure:"type"`       // 输出类型：file，或已注册的消息总线适配器（如 kafka、nats）
+	Enabled   bool              `mapstructure:"enabled"`    // 是否启用
+	DataTypes []int32           `mapstructure:"data_types"` // 投递的数据类型（为空时投递全部支持的类型）
+	Topic     string            `mapstructure:"topic"`      // 主题，支持 {kind} 和 {data_type} 占位符（默认 mxsec.{kind}）
+	File      FileSinkConfig    `mapstructure:"file"`       // file 类型配置
+	Options   map[string]string `mapstructure:"options"`    // 消息总线适配器参数（如 brokers、subject 前缀）
+}
+
+// FileSinkConfig 是 NDJSON 文件输出配置（按大小轮转）
+type FileSinkConfig struct {
+	Path       string `mapstructure:"path"`        // 文件路径，例如 /var/log/mxsec-platform/records.ndjson
+	MaxSizeMB  int    `mapstructure:"max_size_mb"` // 单个文件最大大小（MB，默认 100）
+	MaxBackups int    `mapstructure:"max_backups"` // 保留的轮转文件数（默认 10）
+}
+
+// IngestConfig 是 AgentCenter 入库流水线配置
+// 上报记录按数据类型进入有界队列（按 Agent 分片，保证同一 Agent 的记录顺序处理），
+// 检查结果和 FIM 事件批量写入数据库；队列使用率超过 HighWatermark 时通知 Agent 暂停上报
+type IngestConfig struct {
+	Workers       int           `mapstructure:"workers"`        // 每种队列的分片（工作协程）数（默认 4）
+	QueueSize     int           `mapstructure:"queue_size"`     // 每个分片的队列容量（默认 1024）
+	BatchSize     int           `mapstructure:"batch_size"`     // 批量写入最大条数（默认 200）
+	BatchWait     time.Duration `mapstructure:"batch_wait"`     // 批量写入最长等待时间（默认 200ms）
+	HighWatermark float64       `mapstructure:"high_watermark"` // 触发背压的队列使用率（默认 0.8）
+	ThrottleTime  time.Duration `mapstructure:"throttle_time"`  // 背压时 Agent 暂停上报的时长（默认 2 秒）
+}
+
+// ClusterConfig 是 AgentCenter 多实例部署配置
+// 启用后各实例通过共享注册表记录 Agent 连接归属，命令经内部 RPC 转发到持有连接的实例，
+// 定时调度器只在选举出的 leader 实例上运行
+type ClusterConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	InstanceID        string        `mapstructure:"instance_id"`        // 实例 ID（默认主机名）
+	RPCHost           string        `mapstructure:"rpc_host"`           // 内部 RPC 监听地址
+	RPCPort           int           `mapstructure:"rpc_port"`           // 内部 RPC 监听端口（默认 6752）
+	AdvertiseAddress  string        `mapstructure:"advertise_address"`  // 其他实例访问本实例内部 RPC 的地址（默认 主机名:rpc_port）
+	Secret            string        `mapstructure:"secret"`             // 实例间 RPC 共享密钥（与 tls.ca_cert 至少配置一项）
+	TLS               ClusterTLS    `mapstructure:"tls"`                // 实例间 RPC 的 TLS 证书（启用集群时必填）
+	Registry          string        `mapstructure:"registry"`           // 注册表类型：db / redis（默认 db）
+	Redis             RedisConfig   `mapstructure:"redis"`              // registry 为 redis 时使用
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"` // 实例心跳与 leader 续约间隔（默认 10 秒）
+	InstanceTTL       time.Duration `mapstructure:"instance_ttl"`       // 实例心跳超时时间，超时视为下线（默认 30 秒）
+}
+
+// RPCAddress 返回内部 RPC 监听地址
+func (c ClusterConfig) RPCAddress() string {
+	return fmt.Sprintf("%s:%d", c.RPCHost, c.RPCPort)
+}
+
+// ClusterTLS 是集群内部 RPC 的 TLS 配置
+// 各实例使用同一套证书同时作为服务端和客户端；配置 CACert 后双向校验证书（mTLS），
+// CACert 应为集群专用 CA，不能与签发 Agent 证书的 CA 相同
+type ClusterTLS struct {
+	CACert     string `mapstructure:"ca_cert"`     // 校验对端证书的 CA（为空时使用系统根证书，且不校验客户端证书）
+	Cert       string `mapstructure:"cert"`        // 本实例证书
+	Key        string `mapstructure:"key"`         // 本实例私钥
+	ServerName string `mapstructure:"server_name"` // 校验服务端证书时使用的名称（为空时使用对端 advertise_address 中的主机名）
+}
+
+// RedisConfig 是 Redis 连接配置
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
 }
 
 // PluginsConfig 是插件配置
@@ -55,6 +151,9 @@ func (c GRPCConfig) Address() string {
 type HTTPConfig struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
+	// TrustedProxies 是可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才使用 X-Forwarded-For / X-Real-IP
+	// 作为客户端 IP（登录锁定、审计日志、会话记录）；为空时不信任任何代理，直接使用连接的来源地址
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // Address 返回 HTTP 服务地址
@@ -142,6 +241,9 @@ type AgentConfig struct {
 
 // MetricsConfig 是监控指标配置
 type MetricsConfig struct {
+	// AgentCenter 暴露 Prometheus /metrics 的 HTTP 监听地址（例如 0.0.0.0:6753，为空则不暴露）
+	// Manager 的指标通过 HTTP 服务的 /metrics 暴露，不受此配置影响
+	ListenAddress string `mapstructure:"listen_address"`
 	// MySQL 存储配置
 	MySQL MySQLMetricsConfig `mapstructure:"mysql"`
 	// Prometheus 配置
@@ -315,6 +417,73 @@ func setDefaults(cfg *Config, logFileSet bool) {
 		cfg.Plugins.Dir = "/workspace/dist/plugins" // Docker 开发环境默认路径
 	}
 	// BaseURL 为空时，表示使用 file:// 协议（开发环境）
+
+	// Cluster 默认配置
+	if cfg.Cluster.InstanceID == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			cfg.Cluster.InstanceID = hostname
+		}
+	}
+	if cfg.Cluster.RPCHost == "" {
+		cfg.Cluster.RPCHost = "0.0.0.0"
+	}
+	if cfg.Cluster.RPCPort == 0 {
+		cfg.Cluster.RPCPort = 6752
+	}
+	if cfg.Cluster.AdvertiseAddress == "" {
+		cfg.Cluster.AdvertiseAddress = fmt.Sprintf("%s:%d", cfg.Cluster.InstanceID, cfg.Cluster.RPCPort)
+	}
+	if cfg.Cluster.Registry == "" {
+		cfg.Cluster.Registry = "db"
+	}
+	if cfg.Cluster.HeartbeatInterval == 0 {
+		cfg.Cluster.HeartbeatInterval = 10 * time.Second
+	}
+	if cfg.Cluster.InstanceTTL == 0 {
+		cfg.Cluster.InstanceTTL = 30 * time.Second
+	}
+
+	// Sink 默认配置
+	if cfg.Sink.BufferSize <= 0 {
+		cfg.Sink.BufferSize = 10000
+	}
+	for i := range cfg.Sink.Outputs {
+		out := &cfg.Sink.Outputs[i]
+		if out.Topic == "" {
+			out.Topic = "mxsec.{kind}"
+		}
+		if out.File.MaxSizeMB <= 0 {
+			out.File.MaxSizeMB = 100
+		}
+		if out.File.MaxBackups <= 0 {
+			out.File.MaxBackups = 10
+		}
+	}
+
+	// Ingest 默认配置
+	if cfg.Ingest.Workers <= 0 {
+		cfg.Ingest.Workers = 4
+	}
+	if cfg.Ingest.QueueSize <= 0 {
+		cfg.Ingest.QueueSize = 1024
+	}
+	if cfg.Ingest.BatchSize <= 0 {
+		cfg.Ingest.BatchSize = 200
+	}
+	if cfg.Ingest.BatchWait <= 0 {
+		cfg.Ingest.BatchWait = 200 * time.Millisecond
+	}
+	if cfg.Ingest.HighWatermark <= 0 || cfg.Ingest.HighWatermark > 1 {
+		cfg.Ingest.HighWatermark = 0.8
+	}
+	if cfg.Ingest.ThrottleTime <= 0 {
+		cfg.Ingest.ThrottleTime = 2 * time.Second
+	}
+
+	// 离线检查结果导入默认配置
+	if cfg.OfflineScan.MaxBundleSize <= 0 {
+		cfg.OfflineScan.MaxBundleSize = 20
+	}
 }
 
 // Validate 验证配置
@@ -336,6 +505,15 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// 验证可信代理
+	for _, proxy := range c.Server.HTTP.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("server.http.trusted_proxies 中的地址无效: %s", proxy)
+			}
+		}
+	}
+
 	// 验证 Prometheus 配置
 	if c.Metrics.Prometheus.Enabled {
 		if c.Metrics.Prometheus.RemoteWriteURL == "" && c.Metrics.Prometheus.PushgatewayURL == "" {
@@ -343,6 +521,51 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// 验证集群配置
+	if c.Cluster.Enabled {
+		if c.Cluster.InstanceID == "" {
+			return fmt.Errorf("集群模式已启用但无法确定 instance_id，请在配置中指定")
+		}
+		switch c.Cluster.Registry {
+		case "db":
+		case "redis":
+			if c.Cluster.Redis.Addr == "" {
+				return fmt.Errorf("集群注册表类型为 redis 但未配置 cluster.redis.addr")
+			}
+		default:
+			return fmt.Errorf("不支持的集群注册表类型: %s（可选 db、redis）", c.Cluster.Registry)
+		}
+		if c.Cluster.InstanceTTL <= c.Cluster.HeartbeatInterval {
+			return fmt.Errorf("cluster.instance_ttl 必须大于 cluster.heartbeat_interval")
+		}
+		// 内部 RPC 可以向任意 Agent 下发命令，必须加密并认证
+		if c.Cluster.TLS.Cert == "" || c.Cluster.TLS.Key == "" {
+			return fmt.Errorf("集群模式已启用但未配置 cluster.tls.cert 和 cluster.tls.key")
+		}
+		if c.Cluster.Secret == "" && c.Cluster.TLS.CACert == "" {
+			return fmt.Errorf("集群模式已启用但未配置认证，请配置 cluster.secret 或 cluster.tls.ca_cert（mTLS）")
+		}
+		if c.Cluster.Secret != "" && len(c.Cluster.Secret) < 16 {
+			return fmt.Errorf("cluster.secret 长度不能小于 16 字节")
+		}
+		if c.Cluster.TLS.CACert != "" && c.Cluster.TLS.CACert == c.MTLS.CACert {
+			return fmt.Errorf("cluster.tls.ca_cert 不能与签发 Agent 证书的 mtls.ca_cert 相同")
+		}
+		for _, file := range []string{c.Cluster.TLS.CACert, c.Cluster.TLS.Cert, c.Cluster.TLS.Key} {
+			if file == "" {
+				continue
+			}
+			if _, err := os.Stat(file); os.IsNotExist(err) {
+				return fmt.Errorf("集群 TLS 证书文件不存在: %s", file)
+			}
+		}
+	}
+
+	// 验证离线检查签名密钥
+	if c.OfflineScan.SigningKey != "" && len(c.OfflineScan.SigningKey) < 16 {
+		return fmt.Errorf("offline_scan.signing_key 长度不能小于 16 字节")
+	}
+
 	// 验证日志目录（仅在配置了日志文件时）
 	// 如果 Log.File 为空字符串，表示不写文件，只输出到控制台，不需要创建目录
 	if c.Log.File != "" {
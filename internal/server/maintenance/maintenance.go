@@ -0,0 +1,164 @@
+// Package maintenance 提供维护窗口和变更冻结的判断
+// AgentCenter 在下发修复任务和 Agent/插件更新前检查主机当前是否允许变更：
+// 全局变更冻结期间不允许；主机属于一个或多个维护窗口时，只在任一窗口开启期间允许；不属于任何窗口时不受限制
+package maintenance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// MaxDurationMinutes 维护窗口最长持续时间（7 天）
+const MaxDurationMinutes = 7 * 24 * 60
+
+// Validate 校验维护窗口的 cron、时长、时区和范围，返回解析后的 cron
+func Validate(w *model.MaintenanceWindow) (*Schedule, error) {
+	sched, err := ParseCron(w.Cron)
+	if err != nil {
+		return nil, err
+	}
+	if w.DurationMinutes <= 0 || w.DurationMinutes > MaxDurationMinutes {
+		return nil, fmt.Errorf("窗口时长需在 1-%d 分钟之间", MaxDurationMinutes)
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return nil, fmt.Errorf("无效的时区: %s", w.Timezone)
+	}
+	switch w.Scope {
+	case model.MaintenanceScopeGlobal:
+	case model.MaintenanceScopeBusinessLine, model.MaintenanceScopeHostTags, model.MaintenanceScopeHosts:
+		if len(w.ScopeValues) == 0 {
+			return nil, fmt.Errorf("范围为 %s 时需要指定范围值", w.Scope)
+		}
+	default:
+		return nil, fmt.Errorf("无效的范围: %s", w.Scope)
+	}
+	return sched, nil
+}
+
+// OpenAt 判断窗口在 now 时是否开启，并返回当前窗口的结束时间（开启时）或下次开启时间（未开启时）
+// 窗口在每次 cron 匹配时开启，持续 DurationMinutes 分钟
+func OpenAt(w *model.MaintenanceWindow, sched *Schedule, now time.Time) (bool, time.Time) {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+	// 最近一次开始时间落在 (now-duration, now] 内即为开启
+	start := sched.Next(now.Add(-duration))
+	if start.IsZero() {
+		return false, time.Time{}
+	}
+	if !start.After(now) {
+		return true, start.Add(duration)
+	}
+	return false, start
+}
+
+// Gate 某一时刻的变更冻结和维护窗口状态，在一个调度周期内复用
+type Gate struct {
+	now     time.Time
+	freeze  *model.ChangeFreeze
+	windows []gateWindow
+}
+
+type gateWindow struct {
+	window model.MaintenanceWindow
+	open   bool
+	next   time.Time // 未开启时的下次开启时间
+}
+
+// Load 加载 now 时生效的变更冻结和所有启用的维护窗口；cron 无效的窗口视为始终关闭
+func Load(db *gorm.DB, now time.Time) (*Gate, error) {
+	g := &Gate{now: now}
+
+	var freezes []model.ChangeFreeze
+	if err := db.Where("enabled = ? AND start_at <= ? AND end_at > ?", true, now, now).
+		Order("end_at DESC").Limit(1).Find(&freezes).Error; err != nil {
+		return nil, fmt.Errorf("查询变更冻结失败: %w", err)
+	}
+	if len(freezes) > 0 {
+		g.freeze = &freezes[0]
+	}
+
+	var windows []model.MaintenanceWindow
+	if err := db.Where("enabled = ?", true).Find(&windows).Error; err != nil {
+		return nil, fmt.Errorf("查询维护窗口失败: %w", err)
+	}
+	for _, w := range windows {
+		gw := gateWindow{window: w}
+		if sched, err := ParseCron(w.Cron); err == nil {
+			gw.open, gw.next = OpenAt(&w, sched, now)
+			if gw.open {
+				gw.next = time.Time{}
+			}
+		}
+		g.windows = append(g.windows, gw)
+	}
+	return g, nil
+}
+
+// Frozen 返回当前生效的变更冻结，没有时返回 nil
+func (g *Gate) Frozen() *model.ChangeFreeze {
+	return g.freeze
+}
+
+// Restricted 是否存在任何限制（变更冻结或启用的维护窗口），没有限制时调用方可跳过逐台检查
+func (g *Gate) Restricted() bool {
+	return g.freeze != nil || len(g.windows) > 0
+}
+
+// Check 判断主机当前能否变更，允许时返回空字符串，否则返回等待原因
+func (g *Gate) Check(host *model.Host) string {
+	if g.freeze != nil {
+		return fmt.Sprintf("变更冻结中（%s），%s 结束", g.freeze.Name, g.freeze.EndAt.Time().Format("2006-01-02 15:04"))
+	}
+
+	var names []string
+	var next time.Time
+	for i := range g.windows {
+		gw := &g.windows[i]
+		if !appliesTo(&gw.window, host) {
+			continue
+		}
+		if gw.open {
+			return ""
+		}
+		names = append(names, gw.window.Name)
+		if !gw.next.IsZero() && (next.IsZero() || gw.next.Before(next)) {
+			next = gw.next
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	reason := "等待维护窗口（" + strings.Join(names, "、") + "）"
+	if !next.IsZero() {
+		reason += "，下次开启 " + next.In(g.now.Location()).Format("2006-01-02 15:04")
+	}
+	return reason
+}
+
+// appliesTo 判断维护窗口是否适用于主机
+func appliesTo(w *model.MaintenanceWindow, host *model.Host) bool {
+	switch w.Scope {
+	case model.MaintenanceScopeGlobal:
+		return true
+	case model.MaintenanceScopeBusinessLine:
+		return host.BusinessLine != "" && slices.Contains(w.ScopeValues, host.BusinessLine)
+	case model.MaintenanceScopeHostTags:
+		return slices.ContainsFunc(host.Tags, func(tag string) bool {
+			return slices.Contains(w.ScopeValues, tag)
+		})
+	case model.MaintenanceScopeHosts:
+		return slices.Contains(w.ScopeValues, host.HostID)
+	default:
+		return false
+	}
+}
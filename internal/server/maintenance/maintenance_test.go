@@ -0,0 +1,105 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "0 2 * * 6", "*/15 1-5 * * 1-5", "30 22 1,15 * *", "0 0 * * 7"} {
+		if _, err := ParseCron(expr); err != nil {
+			t.Errorf("ParseCron(%q) error = %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "0 2 * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected error", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// 每周六 02:00（2026-10-18 为周日）
+		{"0 2 * * 6", time.Date(2026, 10, 18, 10, 0, 0, 0, loc), time.Date(2026, 10, 24, 2, 0, 0, 0, loc)},
+		// 不含起始分钟本身
+		{"0 2 * * 6", time.Date(2026, 10, 24, 2, 0, 0, 0, loc), time.Date(2026, 10, 31, 2, 0, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2026, 10, 18, 10, 7, 30, 0, loc), time.Date(2026, 10, 18, 10, 15, 0, 0, loc)},
+		// 跨年
+		{"0 0 1 1 *", time.Date(2026, 10, 18, 0, 0, 0, 0, loc), time.Date(2027, 1, 1, 0, 0, 0, 0, loc)},
+		// 周日写作 7
+		{"0 3 * * 7", time.Date(2026, 10, 18, 4, 0, 0, 0, loc), time.Date(2026, 10, 25, 3, 0, 0, 0, loc)},
+		// 日和周都受限时满足任一即可（10-20 为周二）
+		{"0 0 20 * 1", time.Date(2026, 10, 18, 12, 0, 0, 0, loc), time.Date(2026, 10, 19, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		sched, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := sched.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%v) = %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+
+	sched, _ := ParseCron("0 0 30 2 *")
+	if got := sched.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, loc)); !got.IsZero() {
+		t.Errorf("2 月 30 日不应匹配，得到 %v", got)
+	}
+}
+
+func TestOpenAt(t *testing.T) {
+	// 每天 02:00-04:00（上海时间）
+	w := &model.MaintenanceWindow{Cron: "0 2 * * *", DurationMinutes: 120, Timezone: "Asia/Shanghai"}
+	sched, err := ParseCron(w.Cron)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+
+	open, end := OpenAt(w, sched, time.Date(2026, 10, 18, 3, 0, 0, 0, shanghai))
+	if !open || !end.Equal(time.Date(2026, 10, 18, 4, 0, 0, 0, shanghai)) {
+		t.Errorf("03:00 应在窗口内并于 04:00 结束，得到 open=%v end=%v", open, end)
+	}
+	// 同一时刻的 UTC 表示（前一天 19:00 UTC）
+	if open, _ := OpenAt(w, sched, time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)); !open {
+		t.Error("按窗口时区计算时 19:00 UTC 应在窗口内")
+	}
+	open, next := OpenAt(w, sched, time.Date(2026, 10, 18, 4, 0, 0, 0, shanghai))
+	if open || !next.Equal(time.Date(2026, 10, 19, 2, 0, 0, 0, shanghai)) {
+		t.Errorf("04:00 窗口已结束，下次开启应为次日 02:00，得到 open=%v next=%v", open, next)
+	}
+}
+
+func TestGateCheck(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	open := gateWindow{window: model.MaintenanceWindow{Name: "支付窗口", Scope: model.MaintenanceScopeBusinessLine, ScopeValues: model.StringArray{"payment"}}, open: true}
+	closed := gateWindow{
+		window: model.MaintenanceWindow{Name: "DB 窗口", Scope: model.MaintenanceScopeHostTags, ScopeValues: model.StringArray{"db"}},
+		next:   now.Add(2 * time.Hour),
+	}
+	g := &Gate{now: now, windows: []gateWindow{open, closed}}
+
+	if reason := g.Check(&model.Host{HostID: "h1", BusinessLine: "web"}); reason != "" {
+		t.Errorf("不属于任何窗口的主机不受限制，得到 %q", reason)
+	}
+	if reason := g.Check(&model.Host{HostID: "h2", Tags: model.StringArray{"db"}}); !strings.Contains(reason, "DB 窗口") {
+		t.Errorf("窗口未开启时应等待，得到 %q", reason)
+	}
+	if reason := g.Check(&model.Host{HostID: "h3", BusinessLine: "payment", Tags: model.StringArray{"db"}}); reason != "" {
+		t.Errorf("任一窗口开启即允许，得到 %q", reason)
+	}
+
+	g.freeze = &model.ChangeFreeze{Name: "双十一", EndAt: model.ToLocalTime(now.Add(24 * time.Hour))}
+	if reason := g.Check(&model.Host{HostID: "h1"}); !strings.Contains(reason, "变更冻结") {
+		t.Errorf("变更冻结期间所有主机都应等待，得到 %q", reason)
+	}
+}
@@ -0,0 +1,353 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// discoveryRefreshInterval 是 Agent 重新获取接入点列表的建议间隔（秒）
+const discoveryRefreshInterval = 300
+
+// AgentCenterEndpointsHandler 是 AgentCenter 接入点管理与 Agent 服务发现 API 处理器
+type AgentCenterEndpointsHandler struct {
+	db     *gorm.DB
+	logger *zap.Logger
+	cfg    *config.Config
+}
+
+// NewAgentCenterEndpointsHandler 创建接入点处理器
+func NewAgentCenterEndpointsHandler(db *gorm.DB, logger *zap.Logger, cfg *config.Config) *AgentCenterEndpointsHandler {
+	return &AgentCenterEndpointsHandler{
+		db:     db,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// AgentCenterEndpointItem 接入点列表项（包含当前连接数）
+type AgentCenterEndpointItem struct {
+	model.AgentCenterEndpoint
+	Connections int64 `json:"connections"` // 当前连接数（仅集群模式且配置了 instance_id 时有效）
+	Alive       *bool `json:"alive"`       // 实例是否存活（未配置 instance_id 时为空）
+}
+
+// DiscoveredEndpoint 服务发现返回的接入点
+type DiscoveredEndpoint struct {
+	Address string `json:"address"`
+	Weight  int    `json:"weight"`
+	Region  string `json:"region,omitempty"`
+}
+
+// ListEndpoints 获取接入点列表
+// GET /api/v1/agentcenter-endpoints
+func (h *AgentCenterEndpointsHandler) ListEndpoints(c *gin.Context) {
+	var endpoints []model.AgentCenterEndpoint
+	query := h.db.Model(&model.AgentCenterEndpoint{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id ASC").Find(&endpoints).Error; err != nil {
+		h.logger.Error("查询接入点列表失败", zap.Error(err))
+		InternalError(c, "查询接入点列表失败")
+		return
+	}
+
+	loads, alive := h.instanceLoads()
+	items := make([]AgentCenterEndpointItem, 0, len(endpoints))
+	for _, ep := range endpoints {
+		item := AgentCenterEndpointItem{AgentCenterEndpoint: ep}
+		if ep.InstanceID != "" && alive != nil {
+			isAlive := alive[ep.InstanceID]
+			item.Alive = &isAlive
+			item.Connections = loads[ep.InstanceID]
+		}
+		items = append(items, item)
+	}
+
+	SuccessPaginated(c, int64(len(items)), items)
+}
+
+// AgentCenterEndpointRequest 创建/更新接入点请求
+type AgentCenterEndpointRequest struct {
+	Address      string `json:"address" binding:"required"`
+	InstanceID   string `json:"instance_id"`
+	Region       string `json:"region"`
+	BusinessLine string `json:"business_line"`
+	Weight       *int   `json:"weight"`
+	MaxAgents    int    `json:"max_agents"`
+	Status       string `json:"status"`
+	Description  string `json:"description"`
+}
+
+// apply 校验请求并写入接入点，返回错误信息
+func (r *AgentCenterEndpointRequest) apply(ep *model.AgentCenterEndpoint) string {
+	r.Address = strings.TrimSpace(r.Address)
+	if !strings.Contains(r.Address, ":") {
+		return "接入点地址格式应为 host:port"
+	}
+	weight := 100
+	if r.Weight != nil {
+		weight = *r.Weight
+	}
+	if weight <= 0 {
+		return "权重必须大于 0"
+	}
+	if r.MaxAgents < 0 {
+		return "最大连接数不能为负数"
+	}
+	status := model.AgentCenterEndpointStatus(r.Status)
+	switch status {
+	case "":
+		status = model.AgentCenterEndpointActive
+	case model.AgentCenterEndpointActive, model.AgentCenterEndpointDraining, model.AgentCenterEndpointDisabled:
+	default:
+		return "无效的状态（可选 active、draining、disabled）"
+	}
+
+	ep.Address = r.Address
+	ep.InstanceID = r.InstanceID
+	ep.Region = r.Region
+	ep.BusinessLine = r.BusinessLine
+	ep.Weight = weight
+	ep.MaxAgents = r.MaxAgents
+	ep.Status = status
+	ep.Description = r.Description
+	return ""
+}
+
+// CreateEndpoint 创建接入点
+// POST /api/v1/agentcenter-endpoints
+func (h *AgentCenterEndpointsHandler) CreateEndpoint(c *gin.Context) {
+	var req AgentCenterEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	var ep model.AgentCenterEndpoint
+	if msg := req.apply(&ep); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+
+	var count int64
+	h.db.Model(&model.AgentCenterEndpoint{}).Where("address = ?", ep.Address).Count(&count)
+	if count > 0 {
+		Conflict(c, "接入点地址已存在")
+		return
+	}
+
+	if err := h.db.Create(&ep).Error; err != nil {
+		h.logger.Error("创建接入点失败", zap.Error(err))
+		InternalError(c, "创建接入点失败")
+		return
+	}
+
+	h.logger.Info("接入点已创建", zap.String("address", ep.Address), zap.String("status", string(ep.Status)))
+	Created(c, ep)
+}
+
+// UpdateEndpoint 更新接入点（排空时将 status 设置为 draining）
+// PUT /api/v1/agentcenter-endpoints/:id
+func (h *AgentCenterEndpointsHandler) UpdateEndpoint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的接入点ID")
+		return
+	}
+
+	var req AgentCenterEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	var ep model.AgentCenterEndpoint
+	if err := h.db.First(&ep, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "接入点不存在")
+			return
+		}
+		h.logger.Error("查询接入点失败", zap.Error(err))
+		InternalError(c, "查询接入点失败")
+		return
+	}
+
+	if msg := req.apply(&ep); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+
+	var count int64
+	h.db.Model(&model.AgentCenterEndpoint{}).Where("address = ? AND id != ?", ep.Address, ep.ID).Count(&count)
+	if count > 0 {
+		Conflict(c, "接入点地址已存在")
+		return
+	}
+
+	if err := h.db.Save(&ep).Error; err != nil {
+		h.logger.Error("更新接入点失败", zap.Error(err))
+		InternalError(c, "更新接入点失败")
+		return
+	}
+
+	h.logger.Info("接入点已更新", zap.String("address", ep.Address), zap.String("status", string(ep.Status)))
+	Success(c, ep)
+}
+
+// DeleteEndpoint 删除接入点
+// DELETE /api/v1/agentcenter-endpoints/:id
+func (h *AgentCenterEndpointsHandler) DeleteEndpoint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的接入点ID")
+		return
+	}
+
+	result := h.db.Delete(&model.AgentCenterEndpoint{}, id)
+	if result.Error != nil {
+		h.logger.Error("删除接入点失败", zap.Error(result.Error))
+		InternalError(c, "删除接入点失败")
+		return
+	}
+	if result.RowsAffected == 0 {
+		NotFound(c, "接入点不存在")
+		return
+	}
+
+	SuccessMessage(c, "接入点已删除")
+}
+
+// Discover Agent 服务发现：返回适用于该 Agent 的加权接入点列表
+// GET /api/v1/agent/discovery?host_id=xxx&region=xxx
+//
+// 只返回 active 状态的接入点；同时存在通用接入点和与 Agent 区域/业务线匹配的专属接入点时只返回最匹配的一组；
+// 集群模式下按连接注册表中的连接数降低负载较高接入点的权重，并排除实例已下线或已满的接入点
+func (h *AgentCenterEndpointsHandler) Discover(c *gin.Context) {
+	region := c.Query("region")
+	businessLine := ""
+	if hostID := c.Query("host_id"); hostID != "" {
+		var host model.Host
+		if err := h.db.Select("business_line").Where("host_id = ?", hostID).First(&host).Error; err == nil {
+			businessLine = host.BusinessLine
+		}
+	}
+
+	var endpoints []model.AgentCenterEndpoint
+	if err := h.db.Where("status = ?", model.AgentCenterEndpointActive).Find(&endpoints).Error; err != nil {
+		h.logger.Error("查询接入点失败", zap.Error(err))
+		InternalError(c, "查询接入点失败")
+		return
+	}
+
+	// 按区域、业务线匹配程度筛选：专属接入点优先于通用接入点
+	bestScore := -1
+	var matched []model.AgentCenterEndpoint
+	for _, ep := range endpoints {
+		score, ok := matchEndpoint(ep, region, businessLine)
+		if !ok {
+			continue
+		}
+		if score > bestScore {
+			bestScore = score
+			matched = matched[:0]
+		}
+		if score == bestScore {
+			matched = append(matched, ep)
+		}
+	}
+
+	loads, alive := h.instanceLoads()
+	result := make([]DiscoveredEndpoint, 0, len(matched))
+	var full []DiscoveredEndpoint
+	for _, ep := range matched {
+		weight := ep.Weight
+		if ep.InstanceID != "" && alive != nil {
+			if !alive[ep.InstanceID] {
+				continue
+			}
+			if ep.MaxAgents > 0 {
+				load := float64(loads[ep.InstanceID]) / float64(ep.MaxAgents)
+				if load >= 1 {
+					full = append(full, DiscoveredEndpoint{Address: ep.Address, Weight: 1, Region: ep.Region})
+					continue
+				}
+				weight = int(math.Max(1, math.Round(float64(ep.Weight)*(1-load))))
+			}
+		}
+		result = append(result, DiscoveredEndpoint{Address: ep.Address, Weight: weight, Region: ep.Region})
+	}
+	// 所有接入点都已满时仍返回，避免 Agent 无处可连
+	if len(result) == 0 {
+		result = full
+	}
+
+	Success(c, gin.H{
+		"endpoints":        result,
+		"refresh_interval": discoveryRefreshInterval,
+	})
+}
+
+// matchEndpoint 判断接入点是否适用于指定区域和业务线，返回匹配分数（专属匹配越多分数越高）
+func matchEndpoint(ep model.AgentCenterEndpoint, region, businessLine string) (int, bool) {
+	score := 0
+	if ep.Region != "" {
+		if ep.Region != region {
+			return 0, false
+		}
+		score++
+	}
+	if ep.BusinessLine != "" {
+		if ep.BusinessLine != businessLine {
+			return 0, false
+		}
+		score++
+	}
+	return score, true
+}
+
+// instanceLoads 从集群连接注册表统计每个实例的连接数和存活状态
+// 未启用集群模式时返回 nil（无法判断负载）
+func (h *AgentCenterEndpointsHandler) instanceLoads() (map[string]int64, map[string]bool) {
+	if !h.cfg.Cluster.Enabled || h.cfg.Cluster.Registry != "db" {
+		return nil, nil
+	}
+
+	var instances []model.ClusterInstance
+	if err := h.db.Where("heartbeat_at >= ?", time.Now().Add(-h.cfg.Cluster.InstanceTTL)).
+		Find(&instances).Error; err != nil {
+		h.logger.Warn("查询集群实例失败", zap.Error(err))
+		return nil, nil
+	}
+	alive := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		alive[inst.InstanceID] = true
+	}
+
+	var rows []struct {
+		InstanceID string
+		Count      int64
+	}
+	if err := h.db.Model(&model.AgentRoute{}).
+		Select("instance_id, COUNT(*) AS count").
+		Group("instance_id").
+		Scan(&rows).Error; err != nil {
+		h.logger.Warn("统计实例连接数失败", zap.Error(err))
+		return nil, alive
+	}
+	loads := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		loads[row.InstanceID] = row.Count
+	}
+	return loads, alive
+}
@@ -63,7 +63,7 @@ func (h *AlertsHandler) ListAlerts(c *gin.Context) {
 		req.PageSize = 20
 	}
 
-	query := h.db.Model(&model.Alert{}).Preload("Host").Preload("Rule")
+	query := scopeByHost(c, h.db, h.db.Model(&model.Alert{}), "host_id").Preload("Host").Preload("Rule")
 
 	// 过滤条件
 	if req.Status != "" {
@@ -149,7 +149,7 @@ func (h *AlertsHandler) GetAlert(c *gin.Context) {
 	}
 
 	var alert model.Alert
-	if err := h.db.Preload("Host").Preload("Rule").First(&alert, id).Error; err != nil {
+	if err := scopeByHost(c, h.db, h.db.Preload("Host").Preload("Rule"), "host_id").First(&alert, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"code":    404,
@@ -198,7 +198,7 @@ func (h *AlertsHandler) ResolveAlert(c *gin.Context) {
 	}
 
 	var alert model.Alert
-	if err := h.db.First(&alert, id).Error; err != nil {
+	if err := scopeByHost(c, h.db, h.db, "host_id").First(&alert, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"code":    404,
@@ -250,7 +250,7 @@ func (h *AlertsHandler) IgnoreAlert(c *gin.Context) {
 	}
 
 	var alert model.Alert
-	if err := h.db.First(&alert, id).Error; err != nil {
+	if err := scopeByHost(c, h.db, h.db, "host_id").First(&alert, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"code":    404,
@@ -297,18 +297,21 @@ func (h *AlertsHandler) GetAlertStatistics(c *gin.Context) {
 		Medium   int64 `json:"medium"`
 		Low      int64 `json:"low"`
 	}
+	alerts := func() *gorm.DB {
+		return scopeByHost(c, h.db, h.db.Model(&model.Alert{}), "host_id")
+	}
 
 	// 总数统计
-	h.db.Model(&model.Alert{}).Count(&stats.Total)
-	h.db.Model(&model.Alert{}).Where("status = ?", model.AlertStatusActive).Count(&stats.Active)
-	h.db.Model(&model.Alert{}).Where("status = ?", model.AlertStatusResolved).Count(&stats.Resolved)
-	h.db.Model(&model.Alert{}).Where("status = ?", model.AlertStatusIgnored).Count(&stats.Ignored)
+	alerts().Count(&stats.Total)
+	alerts().Where("status = ?", model.AlertStatusActive).Count(&stats.Active)
+	alerts().Where("status = ?", model.AlertStatusResolved).Count(&stats.Resolved)
+	alerts().Where("status = ?", model.AlertStatusIgnored).Count(&stats.Ignored)
 
 	// 严重级别统计（只统计活跃告警）
-	h.db.Model(&model.Alert{}).Where("status = ? AND severity = ?", model.AlertStatusActive, "critical").Count(&stats.Critical)
-	h.db.Model(&model.Alert{}).Where("status = ? AND severity = ?", model.AlertStatusActive, "high").Count(&stats.High)
-	h.db.Model(&model.Alert{}).Where("status = ? AND severity = ?", model.AlertStatusActive, "medium").Count(&stats.Medium)
-	h.db.Model(&model.Alert{}).Where("status = ? AND severity = ?", model.AlertStatusActive, "low").Count(&stats.Low)
+	alerts().Where("status = ? AND severity = ?", model.AlertStatusActive, "critical").Count(&stats.Critical)
+	alerts().Where("status = ? AND severity = ?", model.AlertStatusActive, "high").Count(&stats.High)
+	alerts().Where("status = ? AND severity = ?", model.AlertStatusActive, "medium").Count(&stats.Medium)
+	alerts().Where("status = ? AND severity = ?", model.AlertStatusActive, "low").Count(&stats.Low)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -346,7 +349,7 @@ func (h *AlertsHandler) BatchResolveAlerts(c *gin.Context) {
 		updates["resolve_reason"] = req.Reason
 	}
 
-	result := h.db.Model(&model.Alert{}).
+	result := scopeByHost(c, h.db, h.db.Model(&model.Alert{}), "host_id").
 		Where("id IN ? AND status = ?", req.IDs, model.AlertStatusActive).
 		Updates(updates)
 
@@ -375,7 +378,7 @@ func (h *AlertsHandler) BatchIgnoreAlerts(c *gin.Context) {
 	}
 
 	now := time.Now()
-	result := h.db.Model(&model.Alert{}).
+	result := scopeByHost(c, h.db, h.db.Model(&model.Alert{}), "host_id").
 		Where("id IN ? AND status = ?", req.IDs, model.AlertStatusActive).
 		Updates(map[string]interface{}{
 			"status":     model.AlertStatusIgnored,
@@ -406,7 +409,7 @@ func (h *AlertsHandler) BatchDeleteAlerts(c *gin.Context) {
 		return
 	}
 
-	result := h.db.Where("id IN ?", req.IDs).Delete(&model.Alert{})
+	result := scopeByHost(c, h.db, h.db.Where("id IN ?", req.IDs), "host_id").Delete(&model.Alert{})
 
 	if result.Error != nil {
 		h.logger.Error("批量删除告警失败", zap.Error(result.Error))
@@ -0,0 +1,274 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/apitoken"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// APITokensHandler 是 API Token 处理器（个人访问令牌和服务账号令牌）
+type APITokensHandler struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewAPITokensHandler 创建 API Token 处理器
+func NewAPITokensHandler(db *gorm.DB, logger *zap.Logger) *APITokensHandler {
+	return &APITokensHandler{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateAPITokenRequest 创建 API Token 请求
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Permissions   []string `json:"permissions"`                                        // 限定的权限，必须是所属用户权限的子集，为空表示继承所属用户的全部权限
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"` // 有效天数，为 0 表示永不过期
+}
+
+// CreateAPITokenResponse 创建 API Token 响应，Token 明文只返回这一次
+type CreateAPITokenResponse struct {
+	model.APIToken
+	Token string `json:"token"`
+}
+
+// ListMyTokens 获取当前用户的个人访问令牌
+// GET /api/v1/auth/tokens
+func (h *APITokensHandler) ListMyTokens(c *gin.Context) {
+	var tokens []model.APIToken
+	if err := h.db.Where("owner = ?", c.GetString("username")).Order("id DESC").Find(&tokens).Error; err != nil {
+		h.logger.Error("查询 API Token 失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	SuccessPaginated(c, int64(len(tokens)), tokens)
+}
+
+// CreateMyToken 为当前用户创建个人访问令牌，权限不能超出当前用户的权限
+// POST /api/v1/auth/tokens
+func (h *APITokensHandler) CreateMyToken(c *gin.Context) {
+	// 禁止用 Token 签发新 Token，避免泄露的 Token 被用来续期或扩散
+	if c.GetUint(apitoken.ContextKey) != 0 {
+		Forbidden(c, "不能使用 API Token 创建 Token，请登录后操作")
+		return
+	}
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	perms, _ := c.Get(rbac.ContextKey)
+	userPerms, _ := perms.([]rbac.Permission)
+	h.create(c, c.GetString("username"), userPerms, &req)
+}
+
+// RevokeMyToken 吊销当前用户的个人访问令牌
+// DELETE /api/v1/auth/tokens/:id
+func (h *APITokensHandler) RevokeMyToken(c *gin.Context) {
+	h.revoke(c, h.db.Where("owner = ?", c.GetString("username")))
+}
+
+// ListTokensRequest API Token 列表请求
+type ListTokensRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Owner    string `form:"owner"`
+	Status   string `form:"status" binding:"omitempty,oneof=active revoked expired"`
+}
+
+// ListTokens 获取所有用户和服务账号的 API Token（不含明文和哈希）
+// GET /api/v1/api-tokens
+func (h *APITokensHandler) ListTokens(c *gin.Context) {
+	var req ListTokensRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, "请求参数错误")
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	query := h.db.Model(&model.APIToken{})
+	if req.Owner != "" {
+		query = query.Where("owner = ?", req.Owner)
+	}
+	now := time.Now()
+	switch req.Status {
+	case "active":
+		query = query.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", now)
+	case "revoked":
+		query = query.Where("revoked_at IS NOT NULL")
+	case "expired":
+		query = query.Where("revoked_at IS NULL AND expires_at <= ?", now)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.logger.Error("查询 API Token 总数失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	var tokens []model.APIToken
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(req.PageSize).Find(&tokens).Error; err != nil {
+		h.logger.Error("查询 API Token 失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	SuccessPaginated(c, total, tokens)
+}
+
+// RevokeToken 吊销任意用户或服务账号的 API Token
+// DELETE /api/v1/api-tokens/:id
+func (h *APITokensHandler) RevokeToken(c *gin.Context) {
+	h.revoke(c, h.db)
+}
+
+// CreateServiceAccountToken 为服务账号创建 API Token，权限不能超出服务账号角色的权限
+// POST /api/v1/users/:id/tokens
+func (h *APITokensHandler) CreateServiceAccountToken(c *gin.Context) {
+	if c.GetUint(apitoken.ContextKey) != 0 {
+		Forbidden(c, "不能使用 API Token 创建 Token，请登录后操作")
+		return
+	}
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	var user model.User
+	if err := h.db.First(&user, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			NotFound(c, "用户不存在")
+			return
+		}
+		h.logger.Error("查询用户失败", zap.Error(err))
+		InternalError(c, "查询用户失败")
+		return
+	}
+	if user.Source != model.UserSourceService {
+		BadRequest(c, "只能为服务账号创建 Token，个人用户请在个人中心创建")
+		return
+	}
+	if user.Status != model.UserStatusActive {
+		BadRequest(c, "服务账号已被禁用")
+		return
+	}
+	// 受业务线范围限制的管理员只能为其范围内的服务账号签发 Token
+	if !scopeOf(c).ContainsAll(user.BusinessLines) {
+		Forbidden(c, "无权管理业务线范围外的服务账号")
+		return
+	}
+
+	perms, err := rbac.Resolve(h.db, string(user.Role))
+	if err != nil {
+		h.logger.Error("解析服务账号权限失败", zap.String("username", user.Username), zap.Error(err))
+		InternalError(c, "解析服务账号权限失败")
+		return
+	}
+	// Token 继承服务账号的权限，操作者不能为权限高于自身的服务账号签发 Token
+	if missing := rbac.Missing(currentPermissions(c), perms); len(missing) > 0 {
+		h.logger.Warn("拒绝为权限高于自身的服务账号签发 Token",
+			zap.String("username", user.Username),
+			zap.Any("missing", missing),
+			zap.String("operator", c.GetString("username")))
+		Forbidden(c, "不能为权限高于自身的服务账号签发 Token，缺少权限: "+string(missing[0]))
+		return
+	}
+	h.create(c, user.Username, perms, &req)
+}
+
+// create 为 owner 签发 Token，限定的权限必须是 ownerPerms 的子集
+func (h *APITokensHandler) create(c *gin.Context, owner string, ownerPerms []rbac.Permission, req *CreateAPITokenRequest) {
+	scoped := slices.Compact(slices.Sorted(slices.Values(req.Permissions)))
+	for _, p := range scoped {
+		if !rbac.IsValid(rbac.Permission(p)) {
+			BadRequest(c, "无效的权限: "+p)
+			return
+		}
+		if !rbac.Has(ownerPerms, rbac.Permission(p)) {
+			BadRequest(c, "Token 权限不能超出所属用户的权限: "+p)
+			return
+		}
+	}
+
+	plaintext, prefix, hash, err := apitoken.Generate()
+	if err != nil {
+		h.logger.Error("生成 API Token 失败", zap.Error(err))
+		InternalError(c, "生成 Token 失败")
+		return
+	}
+	token := model.APIToken{
+		Name:        req.Name,
+		Owner:       owner,
+		Prefix:      prefix,
+		TokenHash:   hash,
+		Permissions: model.StringArray(scoped),
+		CreatedBy:   c.GetString("username"),
+	}
+	if token.Permissions == nil {
+		token.Permissions = model.StringArray{}
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := model.ToLocalTime(time.Now().AddDate(0, 0, req.ExpiresInDays))
+		token.ExpiresAt = &expiresAt
+	}
+	if err := h.db.Create(&token).Error; err != nil {
+		h.logger.Error("创建 API Token 失败", zap.Error(err))
+		InternalError(c, "创建 Token 失败")
+		return
+	}
+
+	audit.AddTargets(c, owner, strconv.FormatUint(uint64(token.ID), 10))
+	audit.SetChange(c, nil, token)
+	h.logger.Info("创建 API Token",
+		zap.String("owner", owner),
+		zap.String("prefix", prefix),
+		zap.String("created_by", token.CreatedBy))
+	Created(c, CreateAPITokenResponse{APIToken: token, Token: plaintext})
+}
+
+// revoke 吊销 query 范围内指定 ID 的 Token
+func (h *APITokensHandler) revoke(c *gin.Context, query *gorm.DB) {
+	var token model.APIToken
+	if err := query.First(&token, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			NotFound(c, "Token 不存在")
+			return
+		}
+		h.logger.Error("查询 API Token 失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	if token.RevokedAt != nil {
+		SuccessMessage(c, "Token 已吊销")
+		return
+	}
+
+	now := model.Now()
+	if err := h.db.Model(&token).Update("revoked_at", &now).Error; err != nil {
+		h.logger.Error("吊销 API Token 失败", zap.Error(err))
+		InternalError(c, "吊销失败")
+		return
+	}
+	audit.AddTargets(c, token.Owner)
+	audit.SetField(c, "revoked_at", nil, now)
+	h.logger.Info("吊销 API Token", zap.Uint("id", token.ID), zap.String("owner", token.Owner))
+	SuccessMessage(c, "Token 已吊销")
+}
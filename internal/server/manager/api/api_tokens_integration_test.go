@@ -0,0 +1,65 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestServiceAccountTokenEscalation 测试只有 users:manage 权限的用户不能为权限高于自身的服务账号签发 Token
+func TestServiceAccountTokenEscalation(t *testing.T) {
+	db := testdb.Open(t, &model.User{}, &model.Role{}, &model.APIToken{})
+	accounts := []model.User{
+		{Username: "svc-admin", Role: model.UserRole(rbac.RoleAdmin), Source: model.UserSourceService, Status: model.UserStatusActive},
+		{Username: "svc-viewer", Role: model.UserRole(rbac.RoleViewer), Source: model.UserSourceService, Status: model.UserStatusActive},
+	}
+	if err := db.Create(&accounts).Error; err != nil {
+		t.Fatal(err)
+	}
+	h := NewAPITokensHandler(db, zap.NewNop())
+	viewer, err := rbac.Resolve(db, rbac.RoleViewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := append([]rbac.Permission{rbac.UsersRead, rbac.UsersManage}, viewer...)
+
+	create := func(account model.User) int {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/api/v1/users/:id/tokens", func(c *gin.Context) {
+			c.Set("username", "manager")
+			c.Set(rbac.ContextKey, manager)
+			c.Next()
+		}, h.CreateServiceAccountToken)
+		data, _ := json.Marshal(map[string]any{"name": "ci"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/tokens", account.ID), bytes.NewReader(data)))
+		return w.Code
+	}
+
+	if code := create(accounts[0]); code != http.StatusForbidden {
+		t.Fatalf("token for admin service account: status = %d, want 403", code)
+	}
+	if code := create(accounts[1]); code != http.StatusOK && code != http.StatusCreated {
+		t.Fatalf("token for viewer service account: status = %d, want success", code)
+	}
+
+	var owners []string
+	db.Model(&model.APIToken{}).Pluck("owner", &owners)
+	if len(owners) != 1 || owners[0] != "svc-viewer" {
+		t.Errorf("token owners = %v, want [svc-viewer]", owners)
+	}
+}
@@ -0,0 +1,95 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// approvalSeverities 修复任务审批规则可选的严重级别
+var approvalSeverities = []string{"critical", "high", "medium", "low"}
+
+// GetApprovalConfig 获取高危操作审批策略
+// GET /api/v1/system-config/approval
+func (h *SystemConfigHandler) GetApprovalConfig(c *gin.Context) {
+	cfg, err := approval.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("查询审批策略失败", zap.Error(err))
+		InternalError(c, "查询配置失败")
+		return
+	}
+	Success(c, cfg)
+}
+
+// UpdateApprovalConfig 更新高危操作审批策略
+// PUT /api/v1/system-config/approval
+func (h *SystemConfigHandler) UpdateApprovalConfig(c *gin.Context) {
+	var req model.ApprovalConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	if req.FixTask.HostThreshold < 0 {
+		BadRequest(c, "修复任务主机数阈值不能为负数")
+		return
+	}
+	req.FixTask.Severities = slices.Compact(slices.Sorted(slices.Values(req.FixTask.Severities)))
+	if req.FixTask.Severities == nil {
+		req.FixTask.Severities = []string{}
+	}
+	for _, s := range req.FixTask.Severities {
+		if !slices.Contains(approvalSeverities, s) {
+			BadRequest(c, "无效的严重级别: "+s)
+			return
+		}
+	}
+
+	before, err := approval.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("查询审批策略失败", zap.Error(err))
+		InternalError(c, "查询配置失败")
+		return
+	}
+	valueJSON, err := json.Marshal(req)
+	if err != nil {
+		h.logger.Error("序列化审批策略失败", zap.Error(err))
+		InternalError(c, "序列化配置失败")
+		return
+	}
+
+	var existing model.SystemConfig
+	err = h.db.Where("`key` = ? AND category = ?", approval.ConfigKey, approval.ConfigCategory).First(&existing).Error
+	switch {
+	case err == nil:
+		err = h.db.Model(&existing).Update("value", string(valueJSON)).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = h.db.Create(&model.SystemConfig{
+			Key:         approval.ConfigKey,
+			Category:    approval.ConfigCategory,
+			Value:       string(valueJSON),
+			Description: "高危操作审批策略（修复任务、Agent 推送、删除主机、删除策略）",
+		}).Error
+	}
+	if err != nil {
+		h.logger.Error("保存审批策略失败", zap.Error(err))
+		InternalError(c, "更新配置失败")
+		return
+	}
+
+	audit.SetChange(c, before, req)
+	h.logger.Info("审批策略更新成功",
+		zap.Bool("fix_task", req.FixTask.Enabled),
+		zap.Bool("agent_push", req.AgentPush),
+		zap.Bool("host_delete", req.HostDelete),
+		zap.Bool("policy_delete", req.PolicyDelete))
+	SuccessWithMessage(c, "配置更新成功", req)
+}
@@ -0,0 +1,445 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/apitoken"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// 高危操作审批
+//
+// 命中审批策略的操作不会立即执行：修复任务和 Agent 推送以 pending_approval 状态创建，
+// 删除主机和删除策略只创建审批单，接口返回 202。审批人必须是申请人以外、拥有该操作所需权限
+// 且业务线范围覆盖目标主机的用户，批准后操作才会生效。
+
+// ApprovalsHandler 审批处理器
+type ApprovalsHandler struct {
+	db         *gorm.DB
+	logger     *zap.Logger
+	scoreCache *biz.BaselineScoreCache
+}
+
+// NewApprovalsHandler 创建审批处理器
+func NewApprovalsHandler(db *gorm.DB, logger *zap.Logger, scoreCache *biz.BaselineScoreCache) *ApprovalsHandler {
+	return &ApprovalsHandler{
+		db:         db,
+		logger:     logger,
+		scoreCache: scoreCache,
+	}
+}
+
+// approvalAction 一类需要审批的操作
+type approvalAction struct {
+	// hostTargets 为 true 时 Targets 为主机 ID，审批人的业务线范围需覆盖这些主机
+	hostTargets bool
+	// execute 批准后执行操作
+	execute func(h *ApprovalsHandler, req *model.ApprovalRequest) error
+	// abort 驳回或撤销后更新关联资源的状态，可为空
+	abort func(h *ApprovalsHandler, req *model.ApprovalRequest, to model.ApprovalStatus) error
+}
+
+// approvalActions 支持审批的操作
+var approvalActions = map[string]approvalAction{
+	approval.ActionFixCreate: {
+		hostTargets: true,
+		execute: func(h *ApprovalsHandler, req *model.ApprovalRequest) error {
+			result := h.db.Model(&model.FixTask{}).
+				Where("task_id = ? AND status = ?", req.ResourceID, model.FixTaskStatusPendingApproval).
+				Update("status", model.FixTaskStatusPending)
+			if result.Error == nil && result.RowsAffected == 0 {
+				return errors.New("修复任务已被取消或删除")
+			}
+			return result.Error
+		},
+		abort: func(h *ApprovalsHandler, req *model.ApprovalRequest, to model.ApprovalStatus) error {
+			status := model.FixTaskStatusFailed
+			if to == model.ApprovalStatusRejected {
+				status = model.FixTaskStatusRejected
+			}
+			return h.db.Model(&model.FixTask{}).
+				Where("task_id = ? AND status = ?", req.ResourceID, model.FixTaskStatusPendingApproval).
+				Updates(map[string]interface{}{"status": status, "completed_at": model.Now()}).Error
+		},
+	},
+	approval.ActionAgentPush: {
+		hostTargets: true,
+		execute: func(h *ApprovalsHandler, req *model.ApprovalRequest) error {
+			result := h.db.Model(&model.ComponentPushRecord{}).
+				Where("id = ? AND status = ?", req.ResourceID, model.ComponentPushStatusPendingApproval).
+				Update("status", model.ComponentPushStatusPending)
+			if result.Error == nil && result.RowsAffected == 0 {
+				return errors.New("推送记录已不是待审批状态")
+			}
+			return result.Error
+		},
+		abort: func(h *ApprovalsHandler, req *model.ApprovalRequest, to model.ApprovalStatus) error {
+			status := model.ComponentPushStatusCancelled
+			if to == model.ApprovalStatusRejected {
+				status = model.ComponentPushStatusRejected
+			}
+			now := model.Now()
+			return h.db.Model(&model.ComponentPushRecord{}).
+				Where("id = ? AND status = ?", req.ResourceID, model.ComponentPushStatusPendingApproval).
+				Updates(map[string]interface{}{"status": status, "completed_at": &now}).Error
+		},
+	},
+	approval.ActionHostDelete: {
+		hostTargets: true,
+		execute: func(h *ApprovalsHandler, req *model.ApprovalRequest) error {
+			var host model.Host
+			if err := h.db.Where("host_id = ?", req.ResourceID).First(&host).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return errors.New("主机不存在")
+				}
+				return err
+			}
+			return deleteHost(h.db, h.scoreCache, &host)
+		},
+	},
+	approval.ActionPolicyDelete: {
+		execute: func(h *ApprovalsHandler, req *model.ApprovalRequest) error {
+			return deletePolicies(h.db, req.Targets)
+		},
+	},
+}
+
+// loadApprovalConfig 读取审批策略，失败时返回错误响应并返回 false（读取失败时不放行高危操作）
+func loadApprovalConfig(c *gin.Context, db *gorm.DB, logger *zap.Logger) (model.ApprovalConfig, bool) {
+	cfg, err := approval.LoadConfig(db)
+	if err != nil {
+		logger.Error("查询审批策略失败", zap.Error(err))
+		InternalError(c, "查询审批策略失败")
+		return cfg, false
+	}
+	return cfg, true
+}
+
+// submitApproval 为被拦截的操作创建审批单并返回 202，失败时返回错误响应
+func submitApproval(c *gin.Context, db *gorm.DB, logger *zap.Logger, req *model.ApprovalRequest) {
+	req.RequestedBy = c.GetString("username")
+	if err := approval.Submit(db, req); err != nil {
+		if errors.Is(err, approval.ErrDuplicate) {
+			Conflict(c, err.Error())
+			return
+		}
+		logger.Error("创建审批单失败", zap.String("action", req.Action), zap.Error(err))
+		InternalError(c, "提交审批失败")
+		return
+	}
+	approvalSubmitted(c, db, logger, req, gin.H{})
+}
+
+// approvalSubmitted 审批单已创建：记录审计、异步通知审批人并返回 202
+// data 为操作本身的响应数据，会附加 approval_id 和 status
+func approvalSubmitted(c *gin.Context, db *gorm.DB, logger *zap.Logger, req *model.ApprovalRequest, data gin.H) {
+	logger.Info("高危操作已提交审批",
+		zap.Uint("approval_id", req.ID),
+		zap.String("action", req.Action),
+		zap.String("resource_id", req.ResourceID),
+		zap.String("requested_by", req.RequestedBy))
+	audit.AddTargets(c, req.Targets...)
+	audit.SetField(c, "approval_id", nil, req.ID)
+	go notifyApproval(db, logger, req, biz.ApprovalEventSubmitted)
+
+	data["approval_id"] = req.ID
+	data["status"] = req.Status
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    0,
+		"message": "操作需要审批，已提交审批申请：" + req.Reason,
+		"data":    data,
+	})
+}
+
+// notifyApproval 发送审批通知（在 goroutine 中调用，不影响接口响应）
+func notifyApproval(db *gorm.DB, logger *zap.Logger, req *model.ApprovalRequest, event biz.ApprovalEvent) {
+	snapshot := *req
+	if err := biz.NewNotificationService(db, logger).SendApprovalNotification(&snapshot, event); err != nil {
+		logger.Warn("发送审批通知失败", zap.Uint("approval_id", req.ID), zap.Error(err))
+	}
+}
+
+// ListApprovalsRequest 审批单列表请求
+type ListApprovalsRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	View     string `form:"view" binding:"omitempty,oneof=todo mine"` // todo: 待我审批；mine: 我的申请；为空时返回全部可见的审批单
+	Status   string `form:"status"`
+	Action   string `form:"action"`
+}
+
+// ApprovalItem 审批单（附带当前用户能否审批）
+type ApprovalItem struct {
+	model.ApprovalRequest
+	CanReview bool `json:"can_review"`
+}
+
+// ListApprovals 获取审批单列表：当前用户提交的申请，以及拥有相应权限可审批的申请
+// GET /api/v1/approvals
+func (h *ApprovalsHandler) ListApprovals(c *gin.Context) {
+	var req ListApprovalsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, "请求参数错误")
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	username := c.GetString("username")
+	perms := currentPermissions(c)
+	query := h.db.Model(&model.ApprovalRequest{})
+	switch req.View {
+	case "mine":
+		query = query.Where("requested_by = ?", username)
+	case "todo":
+		query = h.scopeReviewable(c, query.Where("status = ? AND requested_by <> ? AND permission IN ?",
+			model.ApprovalStatusPending, username, perms))
+	default:
+		query = query.Where(h.db.Where("requested_by = ?", username).
+			Or(h.scopeReviewable(c, h.db.Where("permission IN ?", perms))))
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.logger.Error("查询审批单总数失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	var requests []model.ApprovalRequest
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(req.PageSize).Find(&requests).Error; err != nil {
+		h.logger.Error("查询审批单失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+
+	items := make([]ApprovalItem, 0, len(requests))
+	for _, r := range requests {
+		items = append(items, ApprovalItem{ApprovalRequest: r, CanReview: h.reviewError(c, &r) == ""})
+	}
+	SuccessPaginated(c, total, items)
+}
+
+// GetApproval 获取审批单详情
+// GET /api/v1/approvals/:id
+func (h *ApprovalsHandler) GetApproval(c *gin.Context) {
+	req, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if req.RequestedBy != c.GetString("username") &&
+		(!rbac.Has(currentPermissions(c), rbac.Permission(req.Permission)) || !scopeOf(c).ContainsAll(req.BusinessLines)) {
+		NotFound(c, "审批单不存在")
+		return
+	}
+	Success(c, ApprovalItem{ApprovalRequest: *req, CanReview: h.reviewError(c, req) == ""})
+}
+
+// ReviewApprovalRequest 审批请求，请求体可省略
+type ReviewApprovalRequest struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// ApproveApproval 批准并执行操作
+// POST /api/v1/approvals/:id/approve
+func (h *ApprovalsHandler) ApproveApproval(c *gin.Context) {
+	var body ReviewApprovalRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	req, ok := h.loadReviewable(c)
+	if !ok {
+		return
+	}
+	action, ok := approvalActions[req.Action]
+	if !ok {
+		BadRequest(c, "不支持的审批操作: "+req.Action)
+		return
+	}
+
+	if !h.decide(c, req, model.ApprovalStatusApproved, body.Comment) {
+		return
+	}
+	if err := action.execute(h, req); err != nil {
+		h.logger.Error("审批通过后执行操作失败",
+			zap.Uint("approval_id", req.ID), zap.String("action", req.Action), zap.Error(err))
+		if markErr := approval.MarkFailed(h.db, req, err); markErr != nil {
+			h.logger.Error("更新审批单状态失败", zap.Uint("approval_id", req.ID), zap.Error(markErr))
+		}
+		go notifyApproval(h.db, h.logger, req, biz.ApprovalEventFailed)
+		InternalError(c, "已批准，但执行失败: "+err.Error())
+		return
+	}
+
+	h.logger.Info("审批通过",
+		zap.Uint("approval_id", req.ID),
+		zap.String("action", req.Action),
+		zap.String("reviewed_by", req.ReviewedBy))
+	go notifyApproval(h.db, h.logger, req, biz.ApprovalEventApproved)
+	SuccessWithMessage(c, "已批准，操作已执行", req)
+}
+
+// RejectApproval 驳回申请
+// POST /api/v1/approvals/:id/reject
+func (h *ApprovalsHandler) RejectApproval(c *gin.Context) {
+	var body ReviewApprovalRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	req, ok := h.loadReviewable(c)
+	if !ok {
+		return
+	}
+	if !h.decide(c, req, model.ApprovalStatusRejected, body.Comment) {
+		return
+	}
+	h.abort(req)
+	go notifyApproval(h.db, h.logger, req, biz.ApprovalEventRejected)
+	SuccessWithMessage(c, "已驳回", req)
+}
+
+// CancelApproval 申请人撤销待审批的申请
+// POST /api/v1/approvals/:id/cancel
+func (h *ApprovalsHandler) CancelApproval(c *gin.Context) {
+	req, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if req.RequestedBy != c.GetString("username") {
+		Forbidden(c, "只能撤销自己提交的申请")
+		return
+	}
+	if !h.decide(c, req, model.ApprovalStatusCancelled, "") {
+		return
+	}
+	h.abort(req)
+	SuccessWithMessage(c, "申请已撤销", req)
+}
+
+// load 按路径参数 id 查询审批单，失败时返回错误响应
+func (h *ApprovalsHandler) load(c *gin.Context) (*model.ApprovalRequest, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		BadRequest(c, "无效的审批单 ID")
+		return nil, false
+	}
+	var req model.ApprovalRequest
+	if err := h.db.First(&req, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "审批单不存在")
+			return nil, false
+		}
+		h.logger.Error("查询审批单失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return nil, false
+	}
+	audit.AddTargets(c, strconv.FormatUint(id, 10))
+	return &req, true
+}
+
+// loadReviewable 查询审批单并校验当前用户可以审批，失败时返回错误响应
+func (h *ApprovalsHandler) loadReviewable(c *gin.Context) (*model.ApprovalRequest, bool) {
+	if c.GetUint(apitoken.ContextKey) != 0 {
+		Forbidden(c, "不能使用 API Token 审批，请登录后操作")
+		return nil, false
+	}
+	req, ok := h.load(c)
+	if !ok {
+		return nil, false
+	}
+	if msg := h.reviewError(c, req); msg != "" {
+		Forbidden(c, msg)
+		return nil, false
+	}
+	if approvalActions[req.Action].hostTargets && !requireHostsInScope(c, h.db, req.Targets) {
+		return nil, false
+	}
+	return req, true
+}
+
+// reviewError 返回当前用户不能审批的原因，可以审批时返回空字符串（不校验目标主机是否仍在范围内）
+func (h *ApprovalsHandler) reviewError(c *gin.Context, req *model.ApprovalRequest) string {
+	switch {
+	case req.Status != model.ApprovalStatusPending:
+		return "审批单已处理"
+	case req.RequestedBy == c.GetString("username"):
+		return "不能审批自己提交的申请"
+	case !rbac.Has(currentPermissions(c), rbac.Permission(req.Permission)):
+		return fmt.Sprintf("审批该操作需要 %s 权限", req.Permission)
+	case !scopeOf(c).ContainsAll(req.BusinessLines):
+		return "无权审批业务线范围外的操作"
+	}
+	return ""
+}
+
+// decide 更新审批单状态，已被他人处理时返回 409
+func (h *ApprovalsHandler) decide(c *gin.Context, req *model.ApprovalRequest, to model.ApprovalStatus, comment string) bool {
+	from := req.Status
+	if err := approval.Decide(h.db, req, to, c.GetString("username"), comment); err != nil {
+		if errors.Is(err, approval.ErrNotPending) {
+			Conflict(c, err.Error())
+			return false
+		}
+		h.logger.Error("更新审批单状态失败", zap.Uint("approval_id", req.ID), zap.Error(err))
+		InternalError(c, "操作失败")
+		return false
+	}
+	audit.AddTargets(c, req.Targets...)
+	audit.SetField(c, "status", from, to)
+	return true
+}
+
+// abort 驳回或撤销后更新关联资源（修复任务、推送记录）的状态
+func (h *ApprovalsHandler) abort(req *model.ApprovalRequest) {
+	action := approvalActions[req.Action]
+	if action.abort == nil {
+		return
+	}
+	if err := action.abort(h, req, req.Status); err != nil {
+		h.logger.Error("更新审批关联资源失败",
+			zap.Uint("approval_id", req.ID), zap.String("action", req.Action), zap.Error(err))
+	}
+}
+
+// scopeReviewable 将审批单查询限制在当前用户业务线范围覆盖的记录
+func (h *ApprovalsHandler) scopeReviewable(c *gin.Context, query *gorm.DB) *gorm.DB {
+	scope := scopeOf(c)
+	if scope.Unrestricted() {
+		return query
+	}
+	lines, _ := json.Marshal(scope.BusinessLines)
+	return query.Where("JSON_CONTAINS(?, COALESCE(business_lines, JSON_ARRAY()))", string(lines))
+}
+
+// currentPermissions 返回认证中间件设置的当前用户权限
+func currentPermissions(c *gin.Context) []rbac.Permission {
+	perms, _ := c.Get(rbac.ContextKey)
+	granted, _ := perms.([]rbac.Permission)
+	return granted
+}
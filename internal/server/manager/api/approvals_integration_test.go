@@ -0,0 +1,92 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestApproveFixTask 测试申请人不能审批自己的修复任务，其他有权限的用户批准后任务转为 pending
+func TestApproveFixTask(t *testing.T) {
+	db := testdb.Open(t, &model.FixTask{}, &model.ApprovalRequest{})
+	h := NewApprovalsHandler(db, zap.NewNop(), nil)
+
+	task := &model.FixTask{
+		TaskID:    "fix-1",
+		HostIDs:   model.StringArray{"host-1"},
+		RuleIDs:   model.StringArray{"LINUX_SSH_001"},
+		Status:    model.FixTaskStatusPendingApproval,
+		CreatedBy: "alice",
+		CreatedAt: model.Now(),
+	}
+	req := &model.ApprovalRequest{
+		Action:      approval.ActionFixCreate,
+		ResourceID:  task.TaskID,
+		Targets:     task.HostIDs,
+		Permission:  string(rbac.FixExecute),
+		RequestedBy: "alice",
+	}
+	if err := db.Create(task).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := approval.Submit(db, req); err != nil {
+		t.Fatal(err)
+	}
+
+	approve := func(username string, perms ...rbac.Permission) (int, string) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/api/v1/approvals/:id/approve", func(c *gin.Context) {
+			c.Set("username", username)
+			c.Set(rbac.ContextKey, perms)
+			c.Next()
+		}, h.ApproveApproval)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/approvals/%d/approve", req.ID), nil))
+		var resp struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Message
+	}
+	taskStatus := func() model.FixTaskStatus {
+		var got model.FixTask
+		if err := db.First(&got, "task_id = ?", task.TaskID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return got.Status
+	}
+
+	if code, msg := approve("alice", rbac.FixExecute); code != http.StatusForbidden || msg != "不能审批自己提交的申请" {
+		t.Fatalf("self approval: status = %d, message = %q, want 403", code, msg)
+	}
+	if code, _ := approve("bob"); code != http.StatusForbidden {
+		t.Fatalf("approval without permission: status = %d, want 403", code)
+	}
+	if got := taskStatus(); got != model.FixTaskStatusPendingApproval {
+		t.Fatalf("task status after rejected approvals = %s, want pending_approval", got)
+	}
+
+	if code, msg := approve("bob", rbac.FixExecute); code != http.StatusOK {
+		t.Fatalf("approval: status = %d, message = %q, want 200", code, msg)
+	}
+	if got := taskStatus(); got != model.FixTaskStatusPending {
+		t.Fatalf("task status after approval = %s, want pending", got)
+	}
+	if code, _ := approve("carol", rbac.FixExecute); code != http.StatusForbidden {
+		t.Fatalf("second approval: status = %d, want 403", code)
+	}
+}
@@ -0,0 +1,182 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// RefreshAssetsRequest 单台主机资产按需采集请求
+type RefreshAssetsRequest struct {
+	Handlers []string `json:"handlers"` // 采集器名称（process、port 等），为空表示全部
+}
+
+// BatchRefreshAssetsRequest 批量资产按需采集请求
+type BatchRefreshAssetsRequest struct {
+	HostIDs  []string `json:"host_ids" binding:"required,min=1"`
+	Handlers []string `json:"handlers"` // 采集器名称，为空表示全部
+}
+
+// RefreshHostAssets 触发单台主机资产按需采集
+// POST /api/v1/hosts/:host_id/assets/refresh
+func (h *AssetsHandler) RefreshHostAssets(c *gin.Context) {
+	hostID := c.Param("host_id")
+
+	var req RefreshAssetsRequest
+	// 允许空请求体（采集全部）
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			BadRequest(c, "请求参数错误: "+err.Error())
+			return
+		}
+	}
+
+	var host model.Host
+	if err := h.db.Select("host_id", "status").Where("host_id = ?", hostID).First(&host).Error; err != nil {
+		NotFound(c, "主机不存在")
+		return
+	}
+	if host.Status != model.HostStatusOnline {
+		BadRequest(c, "主机不在线")
+		return
+	}
+
+	h.createRefreshTask(c, []string{hostID}, req.Handlers)
+}
+
+// BatchRefreshAssets 批量触发资产按需采集
+// POST /api/v1/hosts/assets/refresh
+func (h *AssetsHandler) BatchRefreshAssets(c *gin.Context) {
+	var req BatchRefreshAssetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	if !requireHostsInScope(c, h.db, req.HostIDs) {
+		return
+	}
+
+	// 只对在线主机下发
+	var hostIDs []string
+	if err := h.db.Model(&model.Host{}).
+		Where("host_id IN ? AND status = ?", req.HostIDs, model.HostStatusOnline).
+		Pluck("host_id", &hostIDs).Error; err != nil {
+		h.logger.Error("查询在线主机失败", zap.Error(err))
+		InternalError(c, "查询在线主机失败")
+		return
+	}
+	if len(hostIDs) == 0 {
+		BadRequest(c, "没有在线的目标主机")
+		return
+	}
+
+	h.createRefreshTask(c, hostIDs, req.Handlers)
+}
+
+// GetAssetRefreshTask 查询资产按需采集任务进度
+// GET /api/v1/hosts/assets/refresh/:task_id
+func (h *AssetsHandler) GetAssetRefreshTask(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("task_id"), 10, 64)
+	if err != nil {
+		BadRequest(c, "无效的任务 ID")
+		return
+	}
+
+	var task model.AssetRefreshTask
+	if err := h.db.First(&task, taskID).Error; err != nil {
+		NotFound(c, "任务不存在")
+		return
+	}
+
+	var hosts []model.AssetRefreshHost
+	if err := h.db.Where("task_id = ?", task.ID).Order("host_id, handler").Find(&hosts).Error; err != nil {
+		h.logger.Error("查询采集任务明细失败", zap.Uint("task_id", task.ID), zap.Error(err))
+		InternalError(c, "查询采集任务明细失败")
+		return
+	}
+	hostIDs := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		hostIDs = append(hostIDs, host.HostID)
+	}
+	if !requireHostsInScope(c, h.db, hostIDs) {
+		return
+	}
+
+	Success(c, gin.H{
+		"task":  task,
+		"hosts": hosts,
+	})
+}
+
+// createRefreshTask 创建按需采集任务及主机明细，由 AgentCenter 的调度器下发
+func (h *AssetsHandler) createRefreshTask(c *gin.Context, hostIDs, handlers []string) {
+	if len(handlers) == 0 {
+		handlers = engine.CollectTypes
+	}
+	for _, name := range handlers {
+		if engine.GetDataType(name) == 0 {
+			BadRequest(c, fmt.Sprintf("未知的采集器: %s", name))
+			return
+		}
+	}
+
+	task := model.AssetRefreshTask{
+		TargetHosts: model.StringArray(hostIDs),
+		Handlers:    model.StringArray(handlers),
+		Status:      model.AssetRefreshStatusPending,
+		TotalCount:  len(hostIDs) * len(handlers),
+		CreatedBy:   currentUsername(c),
+	}
+	if err := h.db.Create(&task).Error; err != nil {
+		h.logger.Error("创建采集任务失败", zap.Error(err))
+		InternalError(c, "创建采集任务失败")
+		return
+	}
+
+	rows := make([]model.AssetRefreshHost, 0, task.TotalCount)
+	for _, hostID := range hostIDs {
+		for _, name := range handlers {
+			rows = append(rows, model.AssetRefreshHost{
+				TaskID:  task.ID,
+				HostID:  hostID,
+				Handler: name,
+				Status:  model.AssetRefreshHostPending,
+			})
+		}
+	}
+	if err := h.db.CreateInBatches(&rows, 500).Error; err != nil {
+		h.logger.Error("创建采集任务明细失败", zap.Uint("task_id", task.ID), zap.Error(err))
+		h.db.Model(&task).Updates(map[string]interface{}{
+			"status":  model.AssetRefreshStatusFailed,
+			"message": "创建采集任务明细失败",
+		})
+		InternalError(c, "创建采集任务失败")
+		return
+	}
+
+	h.logger.Info("创建资产采集任务",
+		zap.Uint("task_id", task.ID),
+		zap.Int("host_count", len(hostIDs)),
+		zap.Strings("handlers", handlers))
+
+	SuccessWithMessage(c, "采集任务已提交", gin.H{
+		"task_id":     task.ID,
+		"host_count":  len(hostIDs),
+		"handlers":    handlers,
+		"total_count": task.TotalCount,
+	})
+}
+
+// currentUsername 获取当前登录用户名
+func currentUsername(c *gin.Context) string {
+	if username, exists := c.Get("username"); exists {
+		return fmt.Sprintf("%v", username)
+	}
+	return ""
+}
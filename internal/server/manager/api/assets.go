@@ -3,6 +3,7 @@ package api
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -10,6 +11,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
 )
 
 // AssetsHandler 是资产数据 API 处理器
@@ -31,14 +33,22 @@ func NewAssetsHandler(db *gorm.DB, logger *zap.Logger) *AssetsHandler {
 func (h *AssetsHandler) ListProcesses(c *gin.Context) {
 	// 解析查询参数
 	hostID := c.Query("host_id")
+	exeIntegrity := c.Query("exe_integrity")
+	suspicious := c.Query("suspicious")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
 	// 构建查询
-	query := h.db.Model(&model.Process{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Process{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
+	if exeIntegrity != "" {
+		query = query.Where("exe_integrity = ?", exeIntegrity)
+	}
+	if suspicious == "true" {
+		query = query.Where("JSON_LENGTH(suspicious) > 0")
+	}
 
 	// 获取总数
 	var total int64
@@ -66,6 +76,77 @@ func (h *AssetsHandler) ListProcesses(c *gin.Context) {
 	})
 }
 
+// ProcessTreeNode 进程树节点
+type ProcessTreeNode struct {
+	model.Process
+	Children []*ProcessTreeNode `json:"children"`
+}
+
+// GetProcessTree 获取主机进程树
+// GET /api/v1/hosts/:host_id/process-tree
+func (h *AssetsHandler) GetProcessTree(c *gin.Context) {
+	hostID := c.Param("host_id")
+
+	var processes []model.Process
+	if err := h.db.Where("host_id = ?", hostID).Find(&processes).Error; err != nil {
+		h.logger.Error("failed to query processes", zap.String("host_id", hostID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "message": "查询失败"})
+		return
+	}
+
+	roots, suspicious, modified := buildProcessTree(processes)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":            len(processes),
+			"suspicious_count": suspicious,
+			"modified_count":   modified,
+			"roots":            roots,
+		},
+	})
+}
+
+// buildProcessTree 按 PPID 构建进程树，父进程不存在（如 PID 1、内核线程的父进程 0）时作为根节点
+func buildProcessTree(processes []model.Process) ([]*ProcessTreeNode, int, int) {
+	nodes := make(map[string]*ProcessTreeNode, len(processes))
+	for i := range processes {
+		nodes[processes[i].PID] = &ProcessTreeNode{Process: processes[i], Children: []*ProcessTreeNode{}}
+	}
+
+	roots := []*ProcessTreeNode{}
+	suspicious, modified := 0, 0
+	for i := range processes {
+		node := nodes[processes[i].PID]
+		if len(node.Suspicious) > 0 {
+			suspicious++
+		}
+		if node.ExeIntegrity == engine.ExeIntegrityModified {
+			modified++
+		}
+		if parent, ok := nodes[node.PPID]; ok && node.PPID != node.PID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	sortProcessNodes(roots)
+	return roots, suspicious, modified
+}
+
+// sortProcessNodes 按 PID 升序排列子节点
+func sortProcessNodes(nodes []*ProcessTreeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, _ := strconv.Atoi(nodes[i].PID)
+		b, _ := strconv.Atoi(nodes[j].PID)
+		return a < b
+	})
+	for _, node := range nodes {
+		sortProcessNodes(node.Children)
+	}
+}
+
 // ListPorts 获取端口列表
 // GET /api/v1/assets/ports
 func (h *AssetsHandler) ListPorts(c *gin.Context) {
@@ -76,7 +157,7 @@ func (h *AssetsHandler) ListPorts(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
 	// 构建查询
-	query := h.db.Model(&model.Port{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Port{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -119,7 +200,7 @@ func (h *AssetsHandler) ListUsers(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
 	// 构建查询
-	query := h.db.Model(&model.AssetUser{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.AssetUser{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -158,7 +239,7 @@ func (h *AssetsHandler) ListSoftware(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.Software{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Software{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -196,10 +277,12 @@ func (h *AssetsHandler) ListContainers(c *gin.Context) {
 	hostID := c.Query("host_id")
 	runtime := c.Query("runtime")
 	status := c.Query("status")
+	podNamespace := c.Query("pod_namespace")
+	privileged := c.Query("privileged")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.Container{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Container{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -209,6 +292,12 @@ func (h *AssetsHandler) ListContainers(c *gin.Context) {
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if podNamespace != "" {
+		query = query.Where("pod_namespace = ?", podNamespace)
+	}
+	if privileged != "" {
+		query = query.Where("privileged = ?", privileged == "true")
+	}
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -242,7 +331,7 @@ func (h *AssetsHandler) ListApps(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.App{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.App{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -281,7 +370,7 @@ func (h *AssetsHandler) ListNetInterfaces(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.NetInterface{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.NetInterface{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -317,7 +406,7 @@ func (h *AssetsHandler) ListVolumes(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.Volume{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Volume{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -353,7 +442,7 @@ func (h *AssetsHandler) ListKmods(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.Kmod{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Kmod{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -391,7 +480,7 @@ func (h *AssetsHandler) ListServices(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.Service{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Service{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
@@ -435,7 +524,7 @@ func (h *AssetsHandler) ListCrons(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	query := h.db.Model(&model.Cron{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.Cron{}), "host_id")
 	if hostID != "" {
 		query = query.Where("host_id = ?", hostID)
 	}
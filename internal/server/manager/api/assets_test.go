@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// TestBuildProcessTree 测试按 PPID 构建进程树：父进程不存在或指向自身时作为根节点，子节点按 PID 数值排序
+func TestBuildProcessTree(t *testing.T) {
+	processes := []model.Process{
+		{PID: "100", PPID: "1"},
+		{PID: "2", PPID: "0"},
+		{PID: "1", PPID: "0"},
+		{PID: "20", PPID: "1", Suspicious: model.StringArray{engine.SuspiciousDeletedExe}},
+		{PID: "101", PPID: "100", ExeIntegrity: engine.ExeIntegrityModified, Suspicious: model.StringArray{engine.SuspiciousModifiedBinary}},
+		{PID: "500", PPID: "499"},
+		{PID: "7", PPID: "7"},
+	}
+
+	roots, suspicious, modified := buildProcessTree(processes)
+	if suspicious != 2 || modified != 1 {
+		t.Errorf("counts = (%d suspicious, %d modified), want (2, 1)", suspicious, modified)
+	}
+
+	pids := func(nodes []*ProcessTreeNode) []string {
+		out := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			out = append(out, n.PID)
+		}
+		return out
+	}
+	assertPIDs := func(name string, nodes []*ProcessTreeNode, want ...string) {
+		t.Helper()
+		got := pids(nodes)
+		if len(got) != len(want) {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%s = %v, want %v", name, got, want)
+			}
+		}
+	}
+
+	assertPIDs("roots", roots, "1", "2", "7", "500")
+	assertPIDs("children of 1", roots[0].Children, "20", "100")
+	assertPIDs("children of 100", roots[0].Children[1].Children, "101")
+	assertPIDs("children of 7", roots[2].Children)
+	if roots[2].Children == nil {
+		t.Error("leaf children should be an empty array, not null")
+	}
+
+	if roots, _, _ := buildProcessTree(nil); roots == nil || len(roots) != 0 {
+		t.Errorf("empty tree = %v, want empty array", roots)
+	}
+}
@@ -0,0 +1,253 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// maxAuditExportRows 单次导出审计日志的最大行数
+const maxAuditExportRows = 100000
+
+// AuditLogsHandler 是审计日志 API 处理器（只读）
+type AuditLogsHandler struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewAuditLogsHandler 创建审计日志处理器
+func NewAuditLogsHandler(db *gorm.DB, logger *zap.Logger) *AuditLogsHandler {
+	return &AuditLogsHandler{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListAuditLogsRequest 审计日志查询请求
+type ListAuditLogsRequest struct {
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Actor     string `form:"actor"`
+	Action    string `form:"action"`   // 操作前缀匹配，如 policy 匹配 policy.create/policy.update
+	Resource  string `form:"resource"` // 资源类型，如 policy
+	Target    string `form:"target"`   // 目标 ID
+	Result    string `form:"result" binding:"omitempty,oneof=success failure"`
+	SourceIP  string `form:"source_ip"`
+	TokenID   uint   `form:"token_id"`   // 通过指定 API Token 发起的操作
+	StartTime string `form:"start_time"` // 支持 2006-01-02、2006-01-02 15:04:05 和 RFC3339
+	EndTime   string `form:"end_time"`
+}
+
+// ListAuditLogs 查询审计日志
+// GET /api/v1/audit-logs
+func (h *AuditLogsHandler) ListAuditLogs(c *gin.Context) {
+	var req ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, "请求参数错误")
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	query, err := h.filter(&req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.logger.Error("查询审计日志总数失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+
+	var logs []model.AuditLog
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(req.PageSize).Find(&logs).Error; err != nil {
+		h.logger.Error("查询审计日志失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+
+	SuccessPaginated(c, total, logs)
+}
+
+// ExportAuditLogs 按查询条件导出审计日志（CSV，UTF-8 BOM 便于 Excel 打开）
+// GET /api/v1/audit-logs/export
+func (h *AuditLogsHandler) ExportAuditLogs(c *gin.Context) {
+	var req ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, "请求参数错误")
+		return
+	}
+	query, err := h.filter(&req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.logger.Error("查询审计日志总数失败", zap.Error(err))
+		InternalError(c, "导出失败")
+		return
+	}
+	if total > maxAuditExportRows {
+		BadRequest(c, fmt.Sprintf("导出记录数 %d 超过上限 %d，请缩小查询范围", total, maxAuditExportRows))
+		return
+	}
+
+	filename := fmt.Sprintf("audit_logs_%s.csv", time.Now().Format("20060102_150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Status(http.StatusOK)
+
+	_, _ = c.Writer.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"ID", "时间", "操作人", "角色", "来源 IP", "操作", "目标", "方法", "路径", "状态码", "结果", "失败原因", "变更", "Token ID", "哈希"})
+
+	var batch []model.AuditLog
+	err = query.Order("id ASC").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for _, log := range batch {
+			changes := ""
+			if len(log.Changes) > 0 {
+				data, _ := json.Marshal(log.Changes)
+				changes = string(data)
+			}
+			tokenID := ""
+			if log.TokenID != 0 {
+				tokenID = strconv.FormatUint(uint64(log.TokenID), 10)
+			}
+			_ = w.Write(csvRow(
+				strconv.FormatUint(uint64(log.ID), 10),
+				log.CreatedAt.String(),
+				log.Actor,
+				log.ActorRole,
+				log.SourceIP,
+				log.Action,
+				strings.Join(log.TargetIDs, ";"),
+				log.Method,
+				log.Path,
+				strconv.Itoa(log.StatusCode),
+				string(log.Result),
+				log.Message,
+				changes,
+				tokenID,
+				log.Hash,
+			))
+		}
+		w.Flush()
+		return w.Error()
+	}).Error
+	if err != nil {
+		// 响应头已发出，只能记录错误
+		h.logger.Error("导出审计日志失败", zap.Error(err))
+	}
+}
+
+// csvRow 构造 CSV 行，对每个单元格做公式注入防护
+func csvRow(cells ...string) []string {
+	for i, cell := range cells {
+		cells[i] = csvCell(cell)
+	}
+	return cells
+}
+
+// csvCell 以 =、+、-、@、制表符或回车开头的单元格前加单引号，
+// 避免操作人、目标、失败原因等用户可控内容在 Excel 中被当作公式执行
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// VerifyAuditLogs 校验审计日志哈希链
+// GET /api/v1/audit-logs/verify
+func (h *AuditLogsHandler) VerifyAuditLogs(c *gin.Context) {
+	result, err := audit.Verify(h.db)
+	if err != nil {
+		h.logger.Error("校验审计日志哈希链失败", zap.Error(err))
+		InternalError(c, "校验失败")
+		return
+	}
+	if !result.Valid {
+		h.logger.Warn("审计日志哈希链校验未通过",
+			zap.Uint("broken_id", result.BrokenID),
+			zap.String("reason", result.Reason))
+	}
+	Success(c, result)
+}
+
+// filter 根据查询条件构建审计日志查询
+func (h *AuditLogsHandler) filter(req *ListAuditLogsRequest) (*gorm.DB, error) {
+	query := h.db.Model(&model.AuditLog{})
+	if req.Actor != "" {
+		query = query.Where("actor = ?", req.Actor)
+	}
+	if req.Action != "" {
+		query = query.Where("action LIKE ?", req.Action+"%")
+	}
+	if req.Resource != "" {
+		query = query.Where("resource = ?", req.Resource)
+	}
+	if req.Target != "" {
+		target, _ := json.Marshal(req.Target)
+		query = query.Where("JSON_CONTAINS(target_ids, ?)", string(target))
+	}
+	if req.Result != "" {
+		query = query.Where("result = ?", req.Result)
+	}
+	if req.SourceIP != "" {
+		query = query.Where("source_ip = ?", req.SourceIP)
+	}
+	if req.TokenID != 0 {
+		query = query.Where("token_id = ?", req.TokenID)
+	}
+	if req.StartTime != "" {
+		t, err := parseAuditTime(req.StartTime, false)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 start_time 参数")
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if req.EndTime != "" {
+		t, err := parseAuditTime(req.EndTime, true)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 end_time 参数")
+		}
+		query = query.Where("created_at <= ?", t)
+	}
+	return query, nil
+}
+
+// parseAuditTime 解析时间参数，仅指定日期时 end 为 true 表示当天结束
+func parseAuditTime(s string, end bool) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		if end {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(model.TimeFormat, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
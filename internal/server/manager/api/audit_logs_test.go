@@ -0,0 +1,32 @@
+package api
+
+import "testing"
+
+// TestCSVCell 测试以公式字符开头的单元格被转义，其他内容保持不变
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"admin", "admin"},
+		{"192.168.1.1", "192.168.1.1"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+cmd|' /C calc'!A0", "'+1+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.in); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	row := csvRow("1", "=cmd", "@x")
+	if row[0] != "1" || row[1] != "'=cmd" || row[2] != "'@x" {
+		t.Errorf("csvRow() = %q", row)
+	}
+}
@@ -2,7 +2,10 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +14,13 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/apitoken"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/passwd"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/session"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/sso"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/totp"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -34,12 +44,16 @@ func NewAuthHandler(db *gorm.DB, logger *zap.Logger, secret []byte) *AuthHandler
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Provider string `json:"provider" binding:"omitempty,oneof=local ldap"` // 认证方式，默认 local
+	TOTPCode string `json:"totp_code"`                                     // 已启用两步验证的本地账号需提交动态验证码
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  struct {
+	Token        string `json:"token"`         // 访问令牌（JWT）
+	RefreshToken string `json:"refresh_token"` // 刷新令牌，每次刷新后轮换
+	ExpiresIn    int    `json:"expires_in"`    // 访问令牌有效期（秒）
+	User         struct {
 		Username string `json:"username"`
 		Role     string `json:"role"`
 	} `json:"user"`
@@ -47,12 +61,13 @@ type LoginResponse struct {
 
 // Claims JWT Claims
 type Claims struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID uint   `json:"sid"` // 登录会话 ID，会话吊销后访问令牌立即失效
 	jwt.RegisteredClaims
 }
 
-// Login 用户登录
+// Login 用户登录（本地账号密码或 LDAP）
 // POST /api/v1/auth/login
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -63,15 +78,39 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		})
 		return
 	}
+	audit.SetActor(c, req.Username)
+
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("读取认证配置失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "登录失败",
+		})
+		return
+	}
+	if !h.checkLockout(c, &cfg, req.Username) {
+		return
+	}
+	if req.Provider == "ldap" {
+		h.ldapLogin(c, &cfg, &req)
+		return
+	}
+
+	// 关闭本地登录后仅应急账号可使用本地密码登录
+	if !cfg.LocalLoginEnabled && !sso.IsBreakGlass(&cfg, req.Username) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code":    403,
+			"message": "本地账号登录已关闭，请使用单点登录",
+		})
+		return
+	}
 
 	// 从数据库查询用户
 	var user model.User
 	if err := h.db.Where("username = ? AND status = ?", req.Username, model.UserStatusActive).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    401,
-				"message": "用户名或密码错误",
-			})
+			h.loginFailed(c, &cfg, req.Username, "用户名或密码错误")
 			return
 		}
 		h.logger.Error("查询用户失败", zap.Error(err))
@@ -82,15 +121,31 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    401,
-			"message": "用户名或密码错误",
-		})
+	// 验证密码（SSO 自动创建的用户和服务账号没有本地密码）
+	if (user.Source != "" && user.Source != model.UserSourceLocal) ||
+		bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		h.loginFailed(c, &cfg, req.Username, "用户名或密码错误")
 		return
 	}
 
+	// 两步验证：密码正确但未提交验证码时提示前端输入，不计入失败次数
+	if user.TOTPEnabled {
+		if req.TOTPCode == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": "请输入动态验证码",
+				"data":    gin.H{"totp_required": true},
+			})
+			return
+		}
+		counter, ok := totp.Verify(user.TOTPSecret, req.TOTPCode, time.Now(), user.TOTPLastCounter)
+		if !ok {
+			h.loginFailed(c, &cfg, req.Username, "动态验证码错误")
+			return
+		}
+		user.TOTPLastCounter = counter
+	}
+
 	// 更新最后登录时间
 	now := model.Now()
 	user.LastLogin = &now
@@ -98,21 +153,159 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		h.logger.Warn("更新最后登录时间失败", zap.Error(err))
 	}
 
-	// 生成 JWT Token
+	h.loginSuccess(c, &cfg, &user)
+}
+
+// checkLockout 检查用户和来源 IP 是否因登录失败次数过多被锁定，锁定时返回错误响应并返回 false
+func (h *AuthHandler) checkLockout(c *gin.Context, cfg *model.AuthConfig, username string) bool {
+	err := session.CheckLockout(h.db, cfg.Lockout, username, c.ClientIP())
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, session.ErrLocked) {
+		h.logger.Warn("登录被锁定", zap.String("username", username), zap.String("ip", c.ClientIP()))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"code":    429,
+			"message": err.Error(),
+		})
+		return false
+	}
+	h.logger.Error("查询登录失败次数失败", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    500,
+		"message": "登录失败",
+	})
+	return false
+}
+
+// loginFailed 记录一次登录失败（用于锁定暴力破解）并返回 401
+func (h *AuthHandler) loginFailed(c *gin.Context, cfg *model.AuthConfig, username, message string) {
+	if err := session.RecordFailure(h.db, cfg.Lockout, username, c.ClientIP()); err != nil {
+		h.logger.Warn("记录登录失败失败", zap.Error(err))
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"code":    401,
+		"message": message,
+	})
+}
+
+// ldapLogin 使用 LDAP 校验账号密码，按目录组映射角色并自动创建用户
+func (h *AuthHandler) ldapLogin(c *gin.Context, cfg *model.AuthConfig, req *LoginRequest) {
+	if !cfg.LDAP.Enabled {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "LDAP 登录未启用",
+		})
+		return
+	}
+
+	identity, err := sso.NewLDAPAuthenticator(cfg.LDAP).Authenticate(req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, sso.ErrInvalidCredentials) {
+			h.loginFailed(c, cfg, req.Username, "用户名或密码错误")
+			return
+		}
+		h.logger.Error("LDAP 认证失败", zap.String("username", req.Username), zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{
+			"code":    502,
+			"message": "LDAP 认证失败",
+		})
+		return
+	}
+	audit.SetActor(c, identity.Username)
+
+	user, err := h.provision(cfg, identity)
+	if err != nil {
+		status, message := provisionError(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": message,
+		})
+		return
+	}
+	h.loginSuccess(c, cfg, user)
+}
+
+// provision 按目录组映射角色和业务线，创建或更新 SSO 用户
+func (h *AuthHandler) provision(cfg *model.AuthConfig, identity *sso.Identity) (*model.User, error) {
+	role, lines, ok := sso.MapGroups(cfg, identity.Groups)
+	if !ok {
+		h.logger.Warn("SSO 用户目录组未匹配任何映射",
+			zap.String("username", identity.Username),
+			zap.Strings("groups", identity.Groups))
+		return nil, sso.ErrNoMatchingGroup
+	}
+	user, err := sso.Provision(h.db, identity, role, lines)
+	if err != nil {
+		if !errors.Is(err, sso.ErrLocalAccountConflict) && !errors.Is(err, sso.ErrUserDisabled) {
+			h.logger.Error("创建 SSO 用户失败", zap.String("username", identity.Username), zap.Error(err))
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// provisionError 将用户创建错误转换为 HTTP 状态码和提示信息
+func provisionError(err error) (int, string) {
+	switch {
+	case errors.Is(err, sso.ErrNoMatchingGroup), errors.Is(err, sso.ErrLocalAccountConflict), errors.Is(err, sso.ErrUserDisabled):
+		return http.StatusForbidden, err.Error()
+	default:
+		return http.StatusInternalServerError, "登录失败"
+	}
+}
+
+// issueToken 为用户签发绑定会话的访问令牌（JWT）
+func (h *AuthHandler) issueToken(user *model.User, sessionID uint, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		Username: user.Username,
-		Role:     string(user.Role),
+		Username:  user.Username,
+		Role:      string(user.Role),
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(h.secret)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(h.secret)
+// newLoginResponse 签发访问令牌并组装登录响应
+func (h *AuthHandler) newLoginResponse(cfg *model.AuthConfig, user *model.User, sessionID uint, refresh string) (*LoginResponse, error) {
+	ttl := time.Duration(cfg.Session.AccessTokenMinutes) * time.Minute
+	token, err := h.issueToken(user, sessionID, ttl)
 	if err != nil {
-		h.logger.Error("生成Token失败", zap.Error(err))
+		return nil, err
+	}
+	resp := &LoginResponse{
+		Token:        token,
+		RefreshToken: refresh,
+		ExpiresIn:    int(ttl.Seconds()),
+	}
+	resp.User.Username = user.Username
+	resp.User.Role = string(user.Role)
+	return resp, nil
+}
+
+// startSession 创建登录会话，清除失败计数，签发访问令牌和刷新令牌
+func (h *AuthHandler) startSession(c *gin.Context, cfg *model.AuthConfig, user *model.User) (*LoginResponse, error) {
+	ttl := time.Duration(cfg.Session.RefreshTokenHours) * time.Hour
+	sess, refresh, err := session.Create(h.db, user.Username, ttl, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		return nil, err
+	}
+	if err := session.ResetFailures(h.db, user.Username); err != nil {
+		h.logger.Warn("清除登录失败记录失败", zap.String("username", user.Username), zap.Error(err))
+	}
+	return h.newLoginResponse(cfg, user, sess.ID, refresh)
+}
+
+// loginSuccess 创建会话并返回登录响应
+func (h *AuthHandler) loginSuccess(c *gin.Context, cfg *model.AuthConfig, user *model.User) {
+	resp, err := h.startSession(c, cfg, user)
+	if err != nil {
+		h.logger.Error("创建登录会话失败", zap.String("username", user.Username), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    500,
 			"message": "登录失败",
@@ -122,80 +315,154 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
-		"data": LoginResponse{
-			Token: tokenString,
-			User: struct {
-				Username string `json:"username"`
-				Role     string `json:"role"`
-			}{
-				Username: user.Username,
-				Role:     string(user.Role),
-			},
-		},
+		"data": resp,
 	})
 }
 
-// Logout 用户登出
-// POST /api/v1/auth/logout
-func (h *AuthHandler) Logout(c *gin.Context) {
-	// JWT 是无状态的，登出主要是客户端删除 token
-	// 可以在这里实现 token 黑名单（如果需要）
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "登出成功",
-	})
+// RefreshRequest 刷新访问令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
-// GetCurrentUser 获取当前用户信息
-// GET /api/v1/auth/me
-func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
-	// 从 JWT Token 中提取用户信息
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    401,
-			"message": "未授权",
+// Refresh 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+// POST /api/v1/auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "请求参数错误",
 		})
 		return
 	}
 
-	// 移除 "Bearer " 前缀
-	if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-		tokenString = tokenString[7:]
-	}
-
-	// 解析 Token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return h.secret, nil
-	})
-
-	if err != nil || !token.Valid {
+	sess, refresh, err := session.Rotate(h.db, req.RefreshToken, c.ClientIP())
+	if err != nil {
+		message := "登录已过期"
+		switch {
+		case errors.Is(err, session.ErrReused):
+			h.logger.Warn("检测到刷新令牌重放，已吊销会话", zap.String("ip", c.ClientIP()))
+			message = err.Error()
+		case errors.Is(err, session.ErrInvalid), errors.Is(err, session.ErrExpired), errors.Is(err, session.ErrRevoked):
+		default:
+			h.logger.Error("刷新登录会话失败", zap.Error(err))
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"code":    401,
-			"message": "Token无效",
+			"message": message,
 		})
 		return
 	}
 
-	claims, ok := token.Claims.(*Claims)
-	if !ok {
+	// 用户被删除或禁用时吊销会话（禁用时已吊销，这里兜底）
+	var user model.User
+	if err := h.db.Where("username = ? AND status = ?", sess.Username, model.UserStatusActive).First(&user).Error; err != nil {
+		if err := session.Revoke(h.db, sess.ID); err != nil {
+			h.logger.Warn("吊销登录会话失败", zap.Uint("session_id", sess.ID), zap.Error(err))
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"code":    401,
-			"message": "Token解析失败",
+			"message": "用户不存在或已被禁用",
 		})
 		return
 	}
 
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Warn("读取认证配置失败，使用默认会话配置", zap.Error(err))
+	}
+	resp, err := h.newLoginResponse(&cfg, &user, sess.ID, refresh)
+	if err != nil {
+		h.logger.Error("生成Token失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "刷新失败",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": resp,
+	})
+}
+
+// LogoutRequest 登出请求，访问令牌已过期时通过刷新令牌定位会话
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout 用户登出，吊销当前会话（访问令牌和刷新令牌同时失效）
+// POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	// 请求体可选，旧版前端登出时不提交刷新令牌
+	var req LogoutRequest
+	if err := c.ShouldBindJSON(&req); err == nil && req.RefreshToken != "" {
+		username, err := session.RevokeByRefreshToken(h.db, req.RefreshToken)
+		if err == nil {
+			audit.SetActor(c, username)
+		} else if !errors.Is(err, session.ErrInvalid) {
+			h.logger.Error("吊销登录会话失败", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "登出失败",
+			})
+			return
+		}
+	}
+	if claims, err := h.parseToken(c.GetHeader("Authorization")); err == nil {
+		audit.SetActor(c, claims.Username)
+		if claims.SessionID != 0 {
+			if err := session.Revoke(h.db, claims.SessionID); err != nil {
+				h.logger.Error("吊销登录会话失败", zap.Uint("session_id", claims.SessionID), zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "登出失败",
+				})
+				return
+			}
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "登出成功",
+	})
+}
+
+// GetCurrentUser 获取当前用户信息、有效权限、业务线范围（前端据此隐藏无权限的操作）和两步验证状态
+// GET /api/v1/auth/me
+func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
+	perms, _ := c.Get(rbac.ContextKey)
+
+	var user model.User
+	if err := h.db.Where("username = ?", c.GetString("username")).First(&user).Error; err != nil {
+		h.logger.Error("查询用户失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "查询用户失败",
+		})
+		return
+	}
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Warn("读取认证配置失败", zap.Error(err))
+	}
+	local := user.Source == "" || user.Source == model.UserSourceLocal
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
 		"data": gin.H{
-			"username": claims.Username,
-			"role":     claims.Role,
+			"username":       c.GetString("username"),
+			"role":           c.GetString("role"),
+			"source":         user.Source,
+			"permissions":    perms,
+			"business_lines": scopeOf(c).BusinessLines,
+			"totp_enabled":   user.TOTPEnabled,
+			"totp_available": local && (user.TOTPEnabled || slices.Contains(cfg.TOTPRoles, string(user.Role))),
 		},
 	})
 }
 
-// AuthMiddleware JWT 认证中间件
+// AuthMiddleware 认证中间件，支持 JWT 和 API Token（以 mxs_ 开头）
 func (h *AuthHandler) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
@@ -208,50 +475,146 @@ func (h *AuthHandler) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 移除 "Bearer " 前缀
-		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-			tokenString = tokenString[7:]
+		var username string
+		var apiToken *model.APIToken
+		if apitoken.IsToken(tokenString) {
+			token, err := apitoken.Authenticate(h.db, tokenString)
+			if err != nil {
+				message := "Token无效"
+				if errors.Is(err, apitoken.ErrRevoked) || errors.Is(err, apitoken.ErrExpired) {
+					message = err.Error()
+				} else if !errors.Is(err, apitoken.ErrInvalid) {
+					h.logger.Error("查询 API Token 失败", zap.Error(err))
+				}
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    401,
+					"message": message,
+				})
+				c.Abort()
+				return
+			}
+			apiToken, username = token, token.Owner
+			// Token 的每次调用都写入审计日志（包括认证后被拒绝的请求）
+			audit.SetToken(c, token.ID)
+			audit.SetActor(c, token.Owner)
+		} else {
+			claims, err := h.parseToken(tokenString)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    401,
+					"message": "Token无效",
+				})
+				c.Abort()
+				return
+			}
+			// 会话在登出、修改密码或禁用用户时吊销，访问令牌随之失效
+			if err := session.Validate(h.db, claims.SessionID, claims.Username); err != nil {
+				if errors.Is(err, session.ErrInvalid) || errors.Is(err, session.ErrExpired) || errors.Is(err, session.ErrRevoked) {
+					c.JSON(http.StatusUnauthorized, gin.H{
+						"code":    401,
+						"message": err.Error(),
+					})
+				} else {
+					h.logger.Error("查询登录会话失败", zap.Error(err))
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"code":    500,
+						"message": "查询登录会话失败",
+					})
+				}
+				c.Abort()
+				return
+			}
+			username = claims.Username
+			c.Set(session.ContextKey, claims.SessionID)
 		}
 
-		// 解析 Token
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return h.secret, nil
-		})
-
-		if err != nil || !token.Valid {
+		// 角色以数据库为准，修改角色或禁用用户后立即生效（无需等待 Token 过期）
+		var user model.User
+		if err := h.db.Where("username = ?", username).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    401,
+					"message": "用户不存在",
+				})
+			} else {
+				h.logger.Error("查询用户失败", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "查询用户失败",
+				})
+			}
+			c.Abort()
+			return
+		}
+		if user.Status != model.UserStatusActive {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"code":    401,
-				"message": "Token无效",
+				"message": "用户已被禁用",
 			})
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(*Claims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    401,
-				"message": "Token解析失败",
+		perms, err := rbac.Resolve(h.db, string(user.Role))
+		if err != nil {
+			h.logger.Warn("解析用户权限失败",
+				zap.String("username", user.Username),
+				zap.String("role", string(user.Role)),
+				zap.Error(err))
+		}
+		if apiToken != nil {
+			perms = apitoken.Restrict(perms, apiToken.Permissions)
+			if err := apitoken.Touch(h.db, apiToken, c.ClientIP()); err != nil {
+				h.logger.Warn("更新 API Token 使用时间失败", zap.Uint("token_id", apiToken.ID), zap.Error(err))
+			}
+			c.Set(apitoken.ContextKey, apiToken.ID)
+		}
+
+		// 业务线范围解析失败时拒绝请求，避免越权访问其他业务线的数据
+		scope, err := rbac.ResolveScope(h.db, &user)
+		if err != nil {
+			h.logger.Error("解析用户业务线范围失败", zap.String("username", user.Username), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "解析用户权限失败",
 			})
 			c.Abort()
 			return
 		}
 
 		// 将用户信息存储到上下文
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
+		c.Set("username", user.Username)
+		c.Set("role", string(user.Role))
+		c.Set(rbac.ContextKey, perms)
+		c.Set(rbac.ScopeContextKey, scope)
 
 		c.Next()
 	}
 }
 
+// parseToken 解析 Authorization 头中的 Token（可带 "Bearer " 前缀）并校验签名和有效期
+func (h *AuthHandler) parseToken(tokenString string) (*Claims, error) {
+	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return h.secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
 // ChangePasswordRequest 修改密码请求
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required,min=6"` // 还需符合认证配置中的密码策略
 }
 
-// ChangePassword 修改当前用户密码
+// ChangePassword 修改当前用户密码，成功后吊销该用户的其他会话
 // POST /api/v1/auth/change-password
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	// 从上下文获取用户名
@@ -291,8 +654,8 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	// 验证旧密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
+	// 验证旧密码（SSO 用户和服务账号没有本地密码）
+	if user.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)) != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    400,
 			"message": "旧密码错误",
@@ -300,6 +663,19 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("读取认证配置失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "修改密码失败",
+		})
+		return
+	}
+	if !checkPassword(c, h.db, h.logger, cfg.PasswordPolicy, &user, req.NewPassword) {
+		return
+	}
+
 	// 加密新密码
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -311,9 +687,18 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	// 更新密码
+	// 更新密码、记录历史密码并吊销其他会话（当前会话保留）
+	oldHash := user.Password
 	user.Password = string(hashedPassword)
-	if err := h.db.Save(&user).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if err := passwd.Record(tx, user.ID, oldHash, cfg.PasswordPolicy.HistoryCount); err != nil {
+			return err
+		}
+		return session.RevokeUser(tx, user.Username, c.GetUint(session.ContextKey))
+	}); err != nil {
 		h.logger.Error("更新密码失败", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    500,
@@ -327,3 +712,33 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		"message": "密码修改成功",
 	})
 }
+
+// checkPassword 按密码策略和历史密码校验新密码，不通过时返回错误响应并返回 false
+func checkPassword(c *gin.Context, db *gorm.DB, logger *zap.Logger, policy model.PasswordPolicy, user *model.User, password string) bool {
+	if err := passwd.Validate(policy, password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return false
+	}
+	if user == nil {
+		return true
+	}
+	if err := passwd.CheckHistory(db, user, password, policy.HistoryCount); err != nil {
+		if errors.Is(err, passwd.ErrReused) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": err.Error(),
+			})
+			return false
+		}
+		logger.Error("查询历史密码失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "校验密码失败",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,246 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/sso"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// maskedSecret 返回给前端的密钥占位符，更新时提交占位符或空值表示保持原值
+const maskedSecret = "******"
+
+// GetAuthConfig 获取登录认证配置（OIDC/LDAP/目录组映射/会话与密码策略），密钥以占位符返回
+// GET /api/v1/system-config/auth
+func (h *SystemConfigHandler) GetAuthConfig(c *gin.Context) {
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("查询认证配置失败", zap.Error(err))
+		InternalError(c, "查询配置失败")
+		return
+	}
+	Success(c, maskAuthSecrets(cfg))
+}
+
+// UpdateAuthConfig 更新登录认证配置
+// PUT /api/v1/system-config/auth
+func (h *SystemConfigHandler) UpdateAuthConfig(c *gin.Context) {
+	var req model.AuthConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	before, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("查询认证配置失败", zap.Error(err))
+		InternalError(c, "查询配置失败")
+		return
+	}
+	if req.OIDC.ClientSecret == "" || req.OIDC.ClientSecret == maskedSecret {
+		req.OIDC.ClientSecret = before.OIDC.ClientSecret
+	}
+	if req.LDAP.BindPassword == "" || req.LDAP.BindPassword == maskedSecret {
+		req.LDAP.BindPassword = before.LDAP.BindPassword
+	}
+	req.BreakGlassUsers = slices.Compact(slices.Sorted(slices.Values(req.BreakGlassUsers)))
+	if req.BreakGlassUsers == nil {
+		req.BreakGlassUsers = []string{}
+	}
+	if req.GroupMappings == nil {
+		req.GroupMappings = []model.GroupMapping{}
+	}
+	req.TOTPRoles = slices.Compact(slices.Sorted(slices.Values(req.TOTPRoles)))
+	if req.TOTPRoles == nil {
+		req.TOTPRoles = []string{}
+	}
+
+	if msg := validateAuthConfig(&req); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+	if !h.checkAuthConfigRefs(c, &req) {
+		return
+	}
+
+	valueJSON, err := json.Marshal(req)
+	if err != nil {
+		h.logger.Error("序列化认证配置失败", zap.Error(err))
+		InternalError(c, "序列化配置失败")
+		return
+	}
+
+	var existing model.SystemConfig
+	err = h.db.Where("`key` = ? AND category = ?", sso.ConfigKey, sso.ConfigCategory).First(&existing).Error
+	switch {
+	case err == nil:
+		err = h.db.Model(&existing).Update("value", string(valueJSON)).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = h.db.Create(&model.SystemConfig{
+			Key:         sso.ConfigKey,
+			Category:    sso.ConfigCategory,
+			Value:       string(valueJSON),
+			Description: "登录认证配置（本地登录、OIDC、LDAP、目录组映射、会话与密码策略）",
+		}).Error
+	}
+	if err != nil {
+		h.logger.Error("保存认证配置失败", zap.Error(err))
+		InternalError(c, "更新配置失败")
+		return
+	}
+
+	audit.SetChange(c, before, req)
+	h.logger.Info("认证配置更新成功",
+		zap.Bool("local_login_enabled", req.LocalLoginEnabled),
+		zap.Bool("oidc_enabled", req.OIDC.Enabled),
+		zap.Bool("ldap_enabled", req.LDAP.Enabled),
+		zap.Int("group_mappings", len(req.GroupMappings)))
+	SuccessWithMessage(c, "配置更新成功", maskAuthSecrets(req))
+}
+
+// validateAuthConfig 校验认证配置的必填项，返回错误提示，校验通过返回空字符串
+func validateAuthConfig(cfg *model.AuthConfig) string {
+	if cfg.OIDC.Enabled {
+		if cfg.OIDC.Issuer == "" || cfg.OIDC.ClientID == "" || cfg.OIDC.RedirectURL == "" {
+			return "启用 OIDC 时 issuer、client_id 和 redirect_url 不能为空"
+		}
+		if cfg.OIDC.UsernameClaim == "" {
+			return "OIDC 用户名 claim 不能为空"
+		}
+	}
+	if cfg.LDAP.Enabled {
+		if cfg.LDAP.URL == "" || cfg.LDAP.BaseDN == "" {
+			return "启用 LDAP 时 url 和 base_dn 不能为空"
+		}
+		if !strings.Contains(cfg.LDAP.UserFilter, "{username}") {
+			return "LDAP 用户过滤器必须包含 {username}"
+		}
+		if cfg.LDAP.UsernameAttr == "" {
+			return "LDAP 用户名属性不能为空"
+		}
+		if cfg.LDAP.GroupBaseDN != "" && (cfg.LDAP.GroupFilter == "" || cfg.LDAP.GroupNameAttr == "") {
+			return "配置组搜索目录时组过滤器和组名属性不能为空"
+		}
+	}
+	if !cfg.LocalLoginEnabled {
+		if !cfg.OIDC.Enabled && !cfg.LDAP.Enabled {
+			return "关闭本地登录前需启用 OIDC 或 LDAP"
+		}
+		if len(cfg.BreakGlassUsers) == 0 {
+			return "关闭本地登录时至少需要保留一个应急账号"
+		}
+	}
+	for _, m := range cfg.GroupMappings {
+		if m.Group == "" || m.Role == "" {
+			return "目录组映射的组名和角色不能为空"
+		}
+	}
+
+	s := cfg.Session
+	if s.AccessTokenMinutes < 1 || s.AccessTokenMinutes > 1440 {
+		return "访问令牌有效期应为 1-1440 分钟"
+	}
+	if s.RefreshTokenHours < 1 || s.RefreshTokenHours > 8760 {
+		return "会话有效期应为 1-8760 小时"
+	}
+	if s.RefreshTokenHours*60 < s.AccessTokenMinutes {
+		return "会话有效期不能短于访问令牌有效期"
+	}
+	l := cfg.Lockout
+	if l.MaxUserFailures < 0 || l.MaxIPFailures < 0 || l.LockMinutes < 0 || l.LockMinutes > 1440 {
+		return "登录锁定配置无效：次数不能为负数，锁定时长应为 0-1440 分钟"
+	}
+	p := cfg.PasswordPolicy
+	if p.MinLength < 6 || p.MinLength > 128 {
+		return "密码最小长度应为 6-128"
+	}
+	if p.MinClasses < 1 || p.MinClasses > 4 {
+		return "密码字符类型数应为 1-4"
+	}
+	if p.HistoryCount < 0 || p.HistoryCount > 24 {
+		return "历史密码数量应为 0-24"
+	}
+	return ""
+}
+
+// checkAuthConfigRefs 校验配置引用的角色、业务线、应急账号和 OIDC 发现文档，失败时返回错误响应并返回 false
+func (h *SystemConfigHandler) checkAuthConfigRefs(c *gin.Context, cfg *model.AuthConfig) bool {
+	roles := make([]string, 0, len(cfg.GroupMappings)+len(cfg.TOTPRoles)+1)
+	if cfg.DefaultRole != "" {
+		roles = append(roles, cfg.DefaultRole)
+	}
+	for _, m := range cfg.GroupMappings {
+		roles = append(roles, m.Role)
+	}
+	roles = append(roles, cfg.TOTPRoles...)
+	for _, role := range roles {
+		exists, err := rbac.RoleExists(h.db, role)
+		if err != nil {
+			h.logger.Error("查询角色失败", zap.Error(err))
+			InternalError(c, "查询角色失败")
+			return false
+		}
+		if !exists {
+			BadRequest(c, "角色不存在: "+role)
+			return false
+		}
+	}
+
+	for i := range cfg.GroupMappings {
+		lines, ok := checkBusinessLines(c, h.db, cfg.GroupMappings[i].BusinessLines)
+		if !ok {
+			return false
+		}
+		cfg.GroupMappings[i].BusinessLines = lines
+	}
+
+	// 应急账号必须是可用的本地账号，否则关闭本地登录后将无法登录
+	if len(cfg.BreakGlassUsers) > 0 {
+		var count int64
+		if err := h.db.Model(&model.User{}).
+			Where("username IN ? AND status = ? AND (source = ? OR source = '' OR source IS NULL)",
+				cfg.BreakGlassUsers, model.UserStatusActive, model.UserSourceLocal).
+			Count(&count).Error; err != nil {
+			h.logger.Error("查询应急账号失败", zap.Error(err))
+			InternalError(c, "查询用户失败")
+			return false
+		}
+		if int(count) != len(cfg.BreakGlassUsers) {
+			BadRequest(c, "应急账号必须是已启用的本地账号")
+			return false
+		}
+	}
+
+	if cfg.OIDC.Enabled {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+		if _, err := sso.NewOIDCAuthenticator(ctx, cfg.OIDC); err != nil {
+			BadRequest(c, err.Error())
+			return false
+		}
+	}
+	return true
+}
+
+// maskAuthSecrets 将认证配置中的密钥替换为占位符
+func maskAuthSecrets(cfg model.AuthConfig) model.AuthConfig {
+	if cfg.OIDC.ClientSecret != "" {
+		cfg.OIDC.ClientSecret = maskedSecret
+	}
+	if cfg.LDAP.BindPassword != "" {
+		cfg.LDAP.BindPassword = maskedSecret
+	}
+	return cfg
+}
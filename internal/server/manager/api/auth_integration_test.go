@@ -0,0 +1,81 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestLoginLockoutSpoofedForwardedFor 测试不信任代理时伪造 X-Forwarded-For 不能绕过来源 IP 的登录失败锁定
+func TestLoginLockoutSpoofedForwardedFor(t *testing.T) {
+	const remoteIP = "198.51.100.7"
+	maxIPFailures := model.DefaultAuthConfig().Lockout.MaxIPFailures
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantIP         func(i int) string
+		wantLocked     bool
+	}{
+		{"no trusted proxies", nil, func(int) string { return remoteIP }, true},
+		{"trusted proxy", []string{remoteIP}, forwardedIP, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.Open(t, &model.User{}, &model.SystemConfig{}, &model.LoginFailure{})
+			h := NewAuthHandler(db, zap.NewNop(), []byte("test-secret"))
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.trustedProxies); err != nil {
+				t.Fatal(err)
+			}
+			router.POST("/api/v1/auth/login", h.Login)
+			login := func(i int) int {
+				// 每次使用不同的用户名，避免触发按用户的锁定
+				body := fmt.Sprintf(`{"username":"user-%d","password":"wrong"}`, i)
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("X-Forwarded-For", forwardedIP(i))
+				req.RemoteAddr = remoteIP + ":40000"
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w.Code
+			}
+
+			for i := 0; i < maxIPFailures; i++ {
+				if code := login(i); code != http.StatusUnauthorized {
+					t.Fatalf("attempt %d: status = %d, want 401", i, code)
+				}
+				var failure model.LoginFailure
+				if err := db.Where("username = ?", fmt.Sprintf("user-%d", i)).First(&failure).Error; err != nil {
+					t.Fatal(err)
+				}
+				if failure.IP != tt.wantIP(i) {
+					t.Fatalf("attempt %d: recorded ip = %s, want %s", i, failure.IP, tt.wantIP(i))
+				}
+			}
+
+			code := login(maxIPFailures)
+			if locked := code == http.StatusTooManyRequests; locked != tt.wantLocked {
+				t.Fatalf("attempt after %d failures: status = %d, want locked = %v", maxIPFailures, code, tt.wantLocked)
+			}
+		})
+	}
+}
+
+// forwardedIP 返回第 i 次请求伪造的来源 IP
+func forwardedIP(i int) string {
+	return fmt.Sprintf("203.0.113.%d", i+1)
+}
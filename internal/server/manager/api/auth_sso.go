@@ -0,0 +1,202 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/sso"
+)
+
+const (
+	// oidcStateCookie 保存 OIDC 登录状态（state、nonce、PKCE verifier）的 Cookie
+	oidcStateCookie = "mxsec_oidc_state"
+	// oidcStateTTL OIDC 登录状态有效期（用户需在此时间内完成 IdP 登录）
+	oidcStateTTL = 10 * time.Minute
+	// oidcCookiePath Cookie 仅在 OIDC 回调路径下发送
+	oidcCookiePath = "/api/v1/auth/oidc"
+	// loginPagePath 回调完成后重定向的前端登录页，Token 或错误信息放在 URL 片段中（不会发送到服务端和写入日志）
+	loginPagePath = "/login"
+)
+
+// oidcState OIDC 登录状态，签名后存入 HttpOnly Cookie，回调时校验以防 CSRF 和授权码注入
+type oidcState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+// GetAuthProviders 返回登录页可用的认证方式（无需认证）
+// GET /api/v1/auth/providers
+func (h *AuthHandler) GetAuthProviders(c *gin.Context) {
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("读取认证配置失败", zap.Error(err))
+		InternalError(c, "读取认证配置失败")
+		return
+	}
+	Success(c, gin.H{
+		"local_login_enabled": cfg.LocalLoginEnabled,
+		"ldap_enabled":        cfg.LDAP.Enabled,
+		"oidc_enabled":        cfg.OIDC.Enabled,
+		"oidc_display_name":   cfg.OIDC.DisplayName,
+	})
+}
+
+// OIDCLogin 发起 OIDC 授权码登录：生成 state/nonce/PKCE verifier 存入签名 Cookie 并重定向到 IdP
+// GET /api/v1/auth/oidc/login
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("读取认证配置失败", zap.Error(err))
+		h.redirectLoginError(c, "读取认证配置失败")
+		return
+	}
+	if !cfg.OIDC.Enabled {
+		h.redirectLoginError(c, "OIDC 登录未启用")
+		return
+	}
+	authenticator, err := sso.NewOIDCAuthenticator(c.Request.Context(), cfg.OIDC)
+	if err != nil {
+		h.logger.Error("初始化 OIDC 认证失败", zap.String("issuer", cfg.OIDC.Issuer), zap.Error(err))
+		h.redirectLoginError(c, "连接身份提供商失败")
+		return
+	}
+
+	stateValue, err := randomString()
+	if err != nil {
+		h.logger.Error("生成 OIDC 登录状态失败", zap.Error(err))
+		h.redirectLoginError(c, "登录失败")
+		return
+	}
+	nonce, err := randomString()
+	if err != nil {
+		h.logger.Error("生成 OIDC 登录状态失败", zap.Error(err))
+		h.redirectLoginError(c, "登录失败")
+		return
+	}
+	state := oidcState{
+		State:    stateValue,
+		Nonce:    nonce,
+		Verifier: oauth2.GenerateVerifier(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(oidcStateTTL)),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, state).SignedString(h.stateKey())
+	if err != nil {
+		h.logger.Error("生成 OIDC 登录状态失败", zap.Error(err))
+		h.redirectLoginError(c, "登录失败")
+		return
+	}
+	h.setStateCookie(c, signed, int(oidcStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authenticator.AuthURL(state.State, state.Nonce, state.Verifier))
+}
+
+// OIDCCallback IdP 登录回调：校验 state，用授权码换取并校验 ID Token，按组映射角色后签发平台 Token
+// GET /api/v1/auth/oidc/callback
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	cookie, _ := c.Cookie(oidcStateCookie)
+	h.setStateCookie(c, "", -1) // 登录状态只能使用一次
+
+	if e := c.Query("error"); e != "" {
+		h.redirectLoginError(c, "身份提供商返回错误: "+e)
+		return
+	}
+
+	var state oidcState
+	_, err := jwt.ParseWithClaims(cookie, &state, func(token *jwt.Token) (interface{}, error) {
+		return h.stateKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || state.State == "" || c.Query("state") != state.State {
+		h.redirectLoginError(c, "登录状态无效或已过期，请重新登录")
+		return
+	}
+
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("读取认证配置失败", zap.Error(err))
+		h.redirectLoginError(c, "读取认证配置失败")
+		return
+	}
+	if !cfg.OIDC.Enabled {
+		h.redirectLoginError(c, "OIDC 登录未启用")
+		return
+	}
+	authenticator, err := sso.NewOIDCAuthenticator(c.Request.Context(), cfg.OIDC)
+	if err != nil {
+		h.logger.Error("初始化 OIDC 认证失败", zap.String("issuer", cfg.OIDC.Issuer), zap.Error(err))
+		h.redirectLoginError(c, "连接身份提供商失败")
+		return
+	}
+	identity, err := authenticator.Exchange(c.Request.Context(), c.Query("code"), state.Verifier, state.Nonce)
+	if err != nil {
+		h.logger.Warn("OIDC 登录失败", zap.Error(err))
+		h.redirectLoginError(c, "身份校验失败")
+		return
+	}
+	audit.SetActor(c, identity.Username)
+
+	user, err := h.provision(&cfg, identity)
+	if err != nil {
+		_, message := provisionError(err)
+		h.redirectLoginError(c, message)
+		return
+	}
+	resp, err := h.startSession(c, &cfg, user)
+	if err != nil {
+		h.logger.Error("创建登录会话失败", zap.String("username", user.Username), zap.Error(err))
+		h.redirectLoginError(c, "登录失败")
+		return
+	}
+	fragment := url.Values{}
+	fragment.Set("token", resp.Token)
+	fragment.Set("refresh_token", resp.RefreshToken)
+	c.Redirect(http.StatusFound, loginPagePath+"#"+fragment.Encode())
+}
+
+// redirectLoginError 重定向回登录页并携带错误信息，同时在审计日志中记录失败原因
+func (h *AuthHandler) redirectLoginError(c *gin.Context, message string) {
+	audit.SetFailure(c, message)
+	c.Redirect(http.StatusFound, loginPagePath+"#error="+url.QueryEscape(message))
+}
+
+// setStateCookie 写入或清除 OIDC 登录状态 Cookie（SameSite=Lax 以便 IdP 顶层跳转回调时携带）
+func (h *AuthHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     oidcCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// stateKey 派生 OIDC 登录状态的签名密钥，与登录 Token 的密钥区分，避免状态 Cookie 被当作 Token 使用
+func (h *AuthHandler) stateKey() []byte {
+	sum := sha256.Sum256(append([]byte("oidc-state:"), h.secret...))
+	return sum[:]
+}
+
+// randomString 生成 URL 安全的随机字符串
+func randomString() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
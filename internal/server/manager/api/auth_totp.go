@@ -0,0 +1,183 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/apitoken"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/sso"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/totp"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// totpIssuer 认证器 App 中显示的发行方名称
+const totpIssuer = "MxSec"
+
+// TOTPSetupResponse 绑定两步验证响应，密钥仅在绑定时返回
+type TOTPSetupResponse struct {
+	Secret string `json:"secret"` // Base32 密钥，用于手动录入
+	URI    string `json:"uri"`    // otpauth:// 地址，可生成二维码供认证器 App 扫描
+}
+
+// TOTPCodeRequest 提交动态验证码请求
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTOTPRequest 停用两步验证请求
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// SetupTOTP 为当前用户生成新的 TOTP 密钥，调用 EnableTOTP 验证通过后才启用
+// POST /api/v1/auth/totp/setup
+func (h *AuthHandler) SetupTOTP(c *gin.Context) {
+	user, ok := h.totpUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		BadRequest(c, "两步验证已启用，如需更换请先停用")
+		return
+	}
+	cfg, err := sso.LoadConfig(h.db)
+	if err != nil {
+		h.logger.Error("读取认证配置失败", zap.Error(err))
+		InternalError(c, "读取认证配置失败")
+		return
+	}
+	if !slices.Contains(cfg.TOTPRoles, string(user.Role)) {
+		Forbidden(c, "当前角色未开放两步验证")
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		h.logger.Error("生成 TOTP 密钥失败", zap.Error(err))
+		InternalError(c, "生成密钥失败")
+		return
+	}
+	if err := h.db.Model(user).Update("totp_secret", secret).Error; err != nil {
+		h.logger.Error("保存 TOTP 密钥失败", zap.Error(err))
+		InternalError(c, "生成密钥失败")
+		return
+	}
+	Success(c, TOTPSetupResponse{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, user.Username, secret),
+	})
+}
+
+// EnableTOTP 校验认证器 App 生成的验证码并启用两步验证
+// POST /api/v1/auth/totp/enable
+func (h *AuthHandler) EnableTOTP(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误")
+		return
+	}
+	user, ok := h.totpUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		BadRequest(c, "两步验证已启用")
+		return
+	}
+	if user.TOTPSecret == "" {
+		BadRequest(c, "请先生成两步验证密钥")
+		return
+	}
+	counter, ok := totp.Verify(user.TOTPSecret, req.Code, time.Now(), user.TOTPLastCounter)
+	if !ok {
+		BadRequest(c, "动态验证码错误")
+		return
+	}
+	if err := h.db.Model(user).Updates(map[string]interface{}{
+		"totp_enabled":      true,
+		"totp_last_counter": counter,
+	}).Error; err != nil {
+		h.logger.Error("启用两步验证失败", zap.Error(err))
+		InternalError(c, "启用失败")
+		return
+	}
+	audit.AddTargets(c, user.Username)
+	audit.SetField(c, "totp_enabled", false, true)
+	h.logger.Info("启用两步验证", zap.String("username", user.Username))
+	SuccessMessage(c, "两步验证已启用")
+}
+
+// DisableTOTP 校验密码和验证码后停用当前用户的两步验证
+// POST /api/v1/auth/totp/disable
+func (h *AuthHandler) DisableTOTP(c *gin.Context) {
+	var req DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误")
+		return
+	}
+	user, ok := h.totpUser(c)
+	if !ok {
+		return
+	}
+	if !user.TOTPEnabled {
+		BadRequest(c, "两步验证未启用")
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		BadRequest(c, "密码错误")
+		return
+	}
+	if _, ok := totp.Verify(user.TOTPSecret, req.Code, time.Now(), user.TOTPLastCounter); !ok {
+		BadRequest(c, "动态验证码错误")
+		return
+	}
+	if err := clearTOTP(h.db, user); err != nil {
+		h.logger.Error("停用两步验证失败", zap.Error(err))
+		InternalError(c, "停用失败")
+		return
+	}
+	audit.AddTargets(c, user.Username)
+	audit.SetField(c, "totp_enabled", true, false)
+	h.logger.Info("停用两步验证", zap.String("username", user.Username))
+	SuccessMessage(c, "两步验证已停用")
+}
+
+// totpUser 查询当前登录的本地用户，不能通过 API Token 或非本地账号管理两步验证，失败时返回错误响应
+func (h *AuthHandler) totpUser(c *gin.Context) (*model.User, bool) {
+	if c.GetUint(apitoken.ContextKey) != 0 {
+		Forbidden(c, "不能使用 API Token 管理两步验证，请登录后操作")
+		return nil, false
+	}
+	var user model.User
+	if err := h.db.Where("username = ?", c.GetString("username")).First(&user).Error; err != nil {
+		h.logger.Error("查询用户失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "查询用户失败",
+		})
+		return nil, false
+	}
+	if user.Source != "" && user.Source != model.UserSourceLocal {
+		BadRequest(c, "仅本地账号支持两步验证，SSO 账号请在身份提供商配置")
+		return nil, false
+	}
+	return &user, true
+}
+
+// clearTOTP 清除用户的两步验证密钥和状态
+func clearTOTP(db *gorm.DB, user *model.User) error {
+	return db.Model(user).Updates(map[string]interface{}{
+		"totp_secret":       "",
+		"totp_enabled":      false,
+		"totp_last_counter": 0,
+	}).Error
+}
@@ -43,6 +43,10 @@ func (h *BusinessLinesHandler) ListBusinessLines(c *gin.Context) {
 
 	// 构建查询
 	query := h.db.Model(&model.BusinessLine{})
+	if scope := scopeOf(c); !scope.Unrestricted() {
+		// 只返回当前用户业务线范围内的业务线
+		query = query.Where("code IN ?", scope.BusinessLines)
+	}
 
 	// 过滤条件
 	if enabled != "" {
@@ -131,6 +135,10 @@ func (h *BusinessLinesHandler) GetBusinessLine(c *gin.Context) {
 		})
 		return
 	}
+	if !scopeOf(c).Contains(businessLine.Code) {
+		Forbidden(c, "无权访问业务线范围外的业务线")
+		return
+	}
 
 	// 计算主机数量
 	var hostCount int64
@@ -165,6 +173,12 @@ func (h *BusinessLinesHandler) CreateBusinessLine(c *gin.Context) {
 		return
 	}
 
+	// 受业务线范围限制的用户不能创建新业务线
+	if !scopeOf(c).Unrestricted() {
+		Forbidden(c, "受业务线范围限制的用户不能创建业务线")
+		return
+	}
+
 	// 检查代码是否已存在
 	var existing model.BusinessLine
 	if err := h.db.Where("code = ?", req.Code).First(&existing).Error; err == nil {
@@ -258,6 +272,10 @@ func (h *BusinessLinesHandler) UpdateBusinessLine(c *gin.Context) {
 		})
 		return
 	}
+	if !scopeOf(c).Contains(businessLine.Code) {
+		Forbidden(c, "无权访问业务线范围外的业务线")
+		return
+	}
 
 	// 如果更新名称，检查是否与其他业务线冲突
 	if req.Name != "" && req.Name != businessLine.Name {
@@ -335,6 +353,10 @@ func (h *BusinessLinesHandler) DeleteBusinessLine(c *gin.Context) {
 		})
 		return
 	}
+	if !scopeOf(c).Contains(businessLine.Code) {
+		Forbidden(c, "无权访问业务线范围外的业务线")
+		return
+	}
 
 	// 检查是否有主机关联
 	var hostCount int64
@@ -4,6 +4,7 @@ package api
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,8 +18,13 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/imkerbos/mxsec-platform/internal/capability"
 	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
 )
 
 // ComponentsHandler 组件管理 API 处理器
@@ -402,6 +408,8 @@ func (h *ComponentsHandler) ReleaseVersion(c *gin.Context) {
 		zap.String("version", req.Version),
 		zap.String("created_by", username),
 	)
+	audit.AddTargets(c, strconv.FormatUint(uint64(version.ID), 10))
+	audit.SetChange(c, nil, version)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -1461,8 +1469,8 @@ func (h *ComponentsHandler) syncPluginConfigForVersion(version *model.ComponentV
 			"version":       version.Version,
 			"sha256":        pkg.SHA256,
 			"download_urls": model.StringArray{downloadURL},
-			"detail":        fmt.Sprintf(`{"updated_at": "%s"}`, time.Now().Format(time.RFC3339)),
 			"description":   fmt.Sprintf("%s 插件 v%s", componentName, version.Version),
+			// 不覆盖 detail：保留管理员设置的插件运行配置（如 collector 采集间隔）
 		}
 		if err := h.db.Model(&pluginConfig).Updates(updates).Error; err != nil {
 			h.logger.Error("更新插件配置失败",
@@ -1542,9 +1550,13 @@ func (h *ComponentsHandler) PushAgentUpdate(c *gin.Context) {
 		return
 	}
 
-	// 查询需要更新的主机
+	if !requireHostsInScope(c, h.db, req.HostIDs) {
+		return
+	}
+
+	// 查询需要更新的主机（限制在当前用户的业务线范围内）
 	var hosts []model.Host
-	query := h.db.Where("status = ?", model.HostStatusOnline)
+	query := scopeHosts(c, h.db.Where("status = ?", model.HostStatusOnline), "business_line")
 	if len(req.HostIDs) > 0 {
 		query = query.Where("host_id IN ?", req.HostIDs)
 	}
@@ -1571,9 +1583,9 @@ func (h *ComponentsHandler) PushAgentUpdate(c *gin.Context) {
 		return
 	}
 
-	// 确定目标主机列表
+	// 确定目标主机列表（受业务线范围限制时按选定主机下发）
 	targetType := "all"
-	if len(req.HostIDs) > 0 {
+	if len(req.HostIDs) > 0 || !scopeOf(c).Unrestricted() {
 		targetType = "selected"
 	}
 
@@ -1606,7 +1618,41 @@ func (h *ComponentsHandler) PushAgentUpdate(c *gin.Context) {
 		CreatedBy:     h.getCurrentUser(c),
 	}
 
-	if err := h.db.Create(&pushRecord).Error; err != nil {
+	// 命中审批策略时推送记录以 pending_approval 状态创建，批准后 AgentCenter 才会推送
+	approvalCfg, ok := loadApprovalConfig(c, h.db, h.logger)
+	if !ok {
+		return
+	}
+	var approvalReq *model.ApprovalRequest
+	if approvalCfg.AgentPush {
+		businessLines, err := hostBusinessLines(h.db, targetHostIDs)
+		if err != nil {
+			h.logger.Error("查询主机业务线失败", zap.Error(err))
+			InternalError(c, "查询主机失败")
+			return
+		}
+		pushRecord.Status = model.ComponentPushStatusPendingApproval
+		approvalReq = &model.ApprovalRequest{
+			Action:        approval.ActionAgentPush,
+			Summary:       fmt.Sprintf("推送 Agent %s 到 %d 台主机（需要更新: %d，强制: %v）", latestVersion.Version, len(targetHostIDs), needUpdateCount, req.Force),
+			Reason:        "Agent 更新推送需要审批",
+			Targets:       model.StringArray(targetHostIDs),
+			BusinessLines: businessLines,
+			Permission:    string(rbac.ComponentsRelease),
+			RequestedBy:   c.GetString("username"),
+		}
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&pushRecord).Error; err != nil {
+			return err
+		}
+		if approvalReq == nil {
+			return nil
+		}
+		approvalReq.ResourceID = strconv.FormatUint(uint64(pushRecord.ID), 10)
+		return approval.Submit(tx, approvalReq)
+	}); err != nil {
 		h.logger.Error("创建推送记录失败", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    500,
@@ -1625,6 +1671,18 @@ func (h *ComponentsHandler) PushAgentUpdate(c *gin.Context) {
 		zap.Int("need_update", needUpdateCount),
 		zap.String("latest_version", latestVersion.Version),
 		zap.Bool("force", req.Force))
+	audit.AddTargets(c, targetHostIDs...)
+	audit.SetChange(c, nil, gin.H{"version": latestVersion.Version, "force": req.Force, "record_id": pushRecord.ID})
+
+	if approvalReq != nil {
+		approvalSubmitted(c, h.db, h.logger, approvalReq, gin.H{
+			"record_id":      pushRecord.ID,
+			"total":          len(hosts),
+			"need_update":    needUpdateCount,
+			"latest_version": latestVersion.Version,
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -1708,6 +1766,9 @@ func (h *ComponentsHandler) ListPushRecords(c *gin.Context) {
 		if record.CompletedAt != nil {
 			item["completed_at"] = record.CompletedAt.Time().Format("2006-01-02 15:04:05")
 		}
+		if record.Overridden() {
+			item["emergency_reason"] = record.EmergencyReason
+		}
 		response = append(response, item)
 	}
 
@@ -1782,6 +1843,11 @@ func (h *ComponentsHandler) GetPushRecord(c *gin.Context) {
 	if record.CompletedAt != nil {
 		response["completed_at"] = record.CompletedAt.Time().Format("2006-01-02 15:04:05")
 	}
+	if record.Overridden() {
+		response["emergency_reason"] = record.EmergencyReason
+		response["emergency_by"] = record.EmergencyBy
+		response["emergency_at"] = record.EmergencyAt.Time().Format("2006-01-02 15:04:05")
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -1789,6 +1855,166 @@ func (h *ComponentsHandler) GetPushRecord(c *gin.Context) {
 	})
 }
 
+// EmergencyOverridePushRecord 紧急放行 Agent 推送：等待维护窗口的主机在下一个调度周期立即推送
+// POST /api/v1/components/push-records/:id/emergency
+func (h *ComponentsHandler) EmergencyOverridePushRecord(c *gin.Context) {
+	recordID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的记录 ID")
+		return
+	}
+	var req EmergencyOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请填写紧急放行原因")
+		return
+	}
+
+	var record model.ComponentPushRecord
+	if err := h.db.First(&record, recordID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "推送记录不存在")
+			return
+		}
+		h.logger.Error("查询推送记录失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+
+	if record.Status != model.ComponentPushStatusPending && record.Status != model.ComponentPushStatusPushing {
+		BadRequest(c, fmt.Sprintf("推送状态为 %s，无法紧急放行", record.Status))
+		return
+	}
+	if record.Overridden() {
+		Conflict(c, "推送已紧急放行")
+		return
+	}
+
+	var waitingHostIDs []string
+	h.db.Model(&model.ComponentPushHost{}).
+		Where("record_id = ? AND status = ?", record.ID, model.ComponentPushHostStatusWaiting).
+		Pluck("host_id", &waitingHostIDs)
+	if !requireHostsInScope(c, h.db, append(waitingHostIDs, record.TargetHosts...)) {
+		return
+	}
+
+	now := model.Now()
+	operator := h.getCurrentUser(c)
+	if err := h.db.Model(&record).Updates(map[string]interface{}{
+		"emergency_reason": req.Reason,
+		"emergency_by":     operator,
+		"emergency_at":     &now,
+	}).Error; err != nil {
+		h.logger.Error("紧急放行推送失败", zap.Error(err))
+		InternalError(c, "紧急放行失败")
+		return
+	}
+
+	audit.AddTargets(c, strconv.FormatUint(uint64(record.ID), 10), record.ComponentName+"@"+record.Version)
+	audit.SetField(c, "emergency_reason", "", req.Reason)
+	h.logger.Warn("Agent 推送已紧急放行",
+		zap.Uint("record_id", record.ID),
+		zap.String("operator", operator),
+		zap.String("reason", req.Reason),
+		zap.Int("waiting_count", len(waitingHostIDs)))
+	SuccessMessage(c, "已紧急放行，等待维护窗口的主机将在下一个调度周期推送")
+}
+
+// PluginConfigDeferralItem 等待维护窗口的插件配置下发主机
+type PluginConfigDeferralItem struct {
+	model.PluginConfigDeferral
+	Hostname string `json:"hostname"`
+}
+
+// ListPluginConfigDeferrals 获取等待维护窗口的插件配置下发主机
+// GET /api/v1/components/plugins/deferrals
+func (h *ComponentsHandler) ListPluginConfigDeferrals(c *gin.Context) {
+	var deferrals []model.PluginConfigDeferral
+	query := scopeByHost(c, h.db, h.db.Model(&model.PluginConfigDeferral{}), "host_id")
+	if err := query.Order("created_at ASC").Find(&deferrals).Error; err != nil {
+		h.logger.Error("查询插件配置暂缓记录失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+
+	hostIDs := make([]string, 0, len(deferrals))
+	for _, d := range deferrals {
+		hostIDs = append(hostIDs, d.HostID)
+	}
+	var hosts []model.Host
+	if len(hostIDs) > 0 {
+		h.db.Select("host_id", "hostname").Where("host_id IN ?", hostIDs).Find(&hosts)
+	}
+	hostnames := make(map[string]string, len(hosts))
+	for _, host := range hosts {
+		hostnames[host.HostID] = host.Hostname
+	}
+
+	items := make([]PluginConfigDeferralItem, 0, len(deferrals))
+	for _, d := range deferrals {
+		items = append(items, PluginConfigDeferralItem{PluginConfigDeferral: d, Hostname: hostnames[d.HostID]})
+	}
+	SuccessPaginated(c, int64(len(items)), items)
+}
+
+// PluginDeferralOverrideRequest 插件配置紧急放行请求
+type PluginDeferralOverrideRequest struct {
+	Reason  string   `json:"reason" binding:"required"` // 放行原因（记录到暂缓记录和审计日志）
+	HostIDs []string `json:"host_ids"`                  // 放行的主机，为空时放行当前用户范围内所有等待的主机
+}
+
+// EmergencyOverridePluginDeferrals 紧急放行等待维护窗口的插件配置：下一个调度周期立即补发
+// POST /api/v1/components/plugins/deferrals/emergency
+func (h *ComponentsHandler) EmergencyOverridePluginDeferrals(c *gin.Context) {
+	var req PluginDeferralOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请填写紧急放行原因")
+		return
+	}
+	if len(req.HostIDs) > 0 && !requireHostsInScope(c, h.db, req.HostIDs) {
+		return
+	}
+
+	query := scopeByHost(c, h.db, h.db.Model(&model.PluginConfigDeferral{}), "host_id").
+		Where("emergency_reason = ''")
+	if len(req.HostIDs) > 0 {
+		query = query.Where("host_id IN ?", req.HostIDs)
+	}
+	var hostIDs []string
+	if err := query.Pluck("host_id", &hostIDs).Error; err != nil {
+		h.logger.Error("查询插件配置暂缓记录失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	if len(hostIDs) == 0 {
+		NotFound(c, "没有等待维护窗口的插件配置")
+		return
+	}
+
+	now := model.Now()
+	operator := h.getCurrentUser(c)
+	if err := h.db.Model(&model.PluginConfigDeferral{}).
+		Where("host_id IN ? AND emergency_reason = ''", hostIDs).
+		Updates(map[string]interface{}{
+			"emergency_reason": req.Reason,
+			"emergency_by":     operator,
+			"emergency_at":     &now,
+		}).Error; err != nil {
+		h.logger.Error("紧急放行插件配置失败", zap.Error(err))
+		InternalError(c, "紧急放行失败")
+		return
+	}
+
+	audit.AddTargets(c, hostIDs...)
+	audit.SetField(c, "emergency_reason", "", req.Reason)
+	h.logger.Warn("插件配置下发已紧急放行",
+		zap.String("operator", operator),
+		zap.String("reason", req.Reason),
+		zap.Int("host_count", len(hostIDs)))
+	SuccessWithMessage(c, "已紧急放行，等待维护窗口的主机将在下一个调度周期下发插件配置", gin.H{
+		"host_count": len(hostIDs),
+	})
+}
+
 // SyncAllPluginsToLatest 同步所有插件配置到最新版本
 // POST /api/v1/components/plugins/sync-latest
 func (h *ComponentsHandler) SyncAllPluginsToLatest(c *gin.Context) {
@@ -2005,6 +2231,198 @@ func (h *ComponentsHandler) BroadcastPluginConfigs(c *gin.Context) {
 	})
 }
 
+// UpdatePluginDetailRequest 更新插件运行配置请求
+type UpdatePluginDetailRequest struct {
+	Detail json.RawMessage `json:"detail" binding:"required"` // 插件配置详情（JSON 对象）
+}
+
+// GetPluginDetail 获取插件运行配置（grpc.Config.detail）
+// GET /api/v1/components/plugins/:name/config
+func (h *ComponentsHandler) GetPluginDetail(c *gin.Context) {
+	var pluginConfig model.PluginConfig
+	if err := h.db.Where("name = ?", c.Param("name")).First(&pluginConfig).Error; err != nil {
+		NotFound(c, "插件配置不存在")
+		return
+	}
+
+	detail := json.RawMessage("{}")
+	if pluginConfig.Detail != "" && json.Valid([]byte(pluginConfig.Detail)) {
+		detail = json.RawMessage(pluginConfig.Detail)
+	}
+
+	Success(c, gin.H{
+		"name":    pluginConfig.Name,
+		"version": pluginConfig.Version,
+		"detail":  detail,
+	})
+}
+
+// UpdatePluginDetail 更新插件运行配置，插件不重启即可生效
+// PUT /api/v1/components/plugins/:name/config
+func (h *ComponentsHandler) UpdatePluginDetail(c *gin.Context) {
+	name := c.Param("name")
+
+	var req UpdatePluginDetailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(req.Detail, &obj); err != nil {
+		BadRequest(c, "detail 必须是 JSON 对象")
+		return
+	}
+
+	if name == string(model.PluginTypeCollector) {
+		if err := validateCollectorDetail(req.Detail); err != nil {
+			BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	var pluginConfig model.PluginConfig
+	if err := h.db.Where("name = ?", name).First(&pluginConfig).Error; err != nil {
+		NotFound(c, "插件配置不存在")
+		return
+	}
+
+	// 更新 updated_at 会让 PluginUpdateScheduler 在 30 秒内广播到所有在线 Agent
+	if err := h.db.Model(&pluginConfig).Updates(map[string]interface{}{
+		"detail":     string(req.Detail),
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		h.logger.Error("更新插件配置失败", zap.String("name", name), zap.Error(err))
+		InternalError(c, "更新插件配置失败")
+		return
+	}
+
+	h.logger.Info("插件运行配置已更新",
+		zap.String("name", name),
+		zap.String("updated_by", h.getCurrentUser(c)))
+
+	SuccessMessage(c, "配置已更新，将在30秒内推送到所有在线Agent")
+}
+
+// UpdatePluginLimitsRequest 更新插件资源限制与加固选项请求
+type UpdatePluginLimitsRequest struct {
+	Limits  model.PluginLimits  `json:"limits"`
+	Sandbox model.PluginSandbox `json:"sandbox"`
+}
+
+// GetPluginLimits 获取插件资源限制与加固选项
+// GET /api/v1/components/plugins/:name/limits
+func (h *ComponentsHandler) GetPluginLimits(c *gin.Context) {
+	var pluginConfig model.PluginConfig
+	if err := h.db.Where("name = ?", c.Param("name")).First(&pluginConfig).Error; err != nil {
+		NotFound(c, "插件配置不存在")
+		return
+	}
+
+	if pluginConfig.Sandbox.DropCapabilities == nil {
+		pluginConfig.Sandbox.DropCapabilities = model.StringArray{}
+	}
+	Success(c, gin.H{
+		"name":    pluginConfig.Name,
+		"version": pluginConfig.Version,
+		"limits":  pluginConfig.Limits,
+		"sandbox": pluginConfig.Sandbox,
+	})
+}
+
+// UpdatePluginLimits 更新插件资源限制与加固选项
+// 资源限制由 Agent 直接更新插件 cgroup，无需重启；加固选项变化时 Agent 重启插件
+// PUT /api/v1/components/plugins/:name/limits
+func (h *ComponentsHandler) UpdatePluginLimits(c *gin.Context) {
+	name := c.Param("name")
+
+	var req UpdatePluginLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	if err := validatePluginLimits(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	var pluginConfig model.PluginConfig
+	if err := h.db.Where("name = ?", name).First(&pluginConfig).Error; err != nil {
+		NotFound(c, "插件配置不存在")
+		return
+	}
+	audit.SetChange(c,
+		gin.H{"limits": pluginConfig.Limits, "sandbox": pluginConfig.Sandbox},
+		gin.H{"limits": req.Limits, "sandbox": req.Sandbox})
+
+	// 更新 updated_at 会让 PluginUpdateScheduler 在 30 秒内广播到所有在线 Agent
+	if err := h.db.Model(&pluginConfig).Updates(map[string]interface{}{
+		"limits":     req.Limits,
+		"sandbox":    req.Sandbox,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		h.logger.Error("更新插件资源限制失败", zap.String("name", name), zap.Error(err))
+		InternalError(c, "更新插件资源限制失败")
+		return
+	}
+
+	h.logger.Info("插件资源限制已更新",
+		zap.String("name", name),
+		zap.Any("limits", req.Limits),
+		zap.Any("sandbox", req.Sandbox),
+		zap.String("updated_by", h.getCurrentUser(c)))
+
+	SuccessMessage(c, "配置已更新，将在30秒内推送到所有在线Agent")
+}
+
+// validatePluginLimits 校验插件资源限制与加固选项（0 表示不限制）
+func validatePluginLimits(req *UpdatePluginLimitsRequest) error {
+	l := req.Limits
+	if l.CPUPercent < 0 || l.CPUPercent > 12800 {
+		return fmt.Errorf("cpu_percent 必须在 0-12800 之间")
+	}
+	if l.MemoryMaxMB < 0 || (l.MemoryMaxMB > 0 && l.MemoryMaxMB < 32) {
+		return fmt.Errorf("memory_max_mb 不能小于 32")
+	}
+	if l.IOWeight < 0 || l.IOWeight > 10000 {
+		return fmt.Errorf("io_weight 必须在 0-10000 之间（0 表示默认权重 100）")
+	}
+	if l.PidsMax < 0 || (l.PidsMax > 0 && l.PidsMax < 8) {
+		return fmt.Errorf("pids_max 不能小于 8")
+	}
+
+	caps := make(model.StringArray, 0, len(req.Sandbox.DropCapabilities))
+	for _, name := range req.Sandbox.DropCapabilities {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name != "" {
+			caps = append(caps, name)
+		}
+	}
+	if err := capability.Validate(caps); err != nil {
+		return fmt.Errorf("drop_capabilities 无效: %w", err)
+	}
+	req.Sandbox.DropCapabilities = caps
+	return nil
+}
+
+// validateCollectorDetail 校验 collector 插件配置：采集器名称必须存在，间隔不得小于最小值
+func validateCollectorDetail(detail []byte) error {
+	var cfg engine.PluginConfig
+	if err := json.Unmarshal(detail, &cfg); err != nil {
+		return fmt.Errorf("collector 配置格式错误: %w", err)
+	}
+	minSeconds := int(engine.MinInterval / time.Second)
+	for name, setting := range cfg.Handlers {
+		if engine.GetDataType(name) == 0 {
+			return fmt.Errorf("未知的采集器: %s", name)
+		}
+		if setting.Interval < 0 || (setting.Interval > 0 && setting.Interval < minSeconds) {
+			return fmt.Errorf("采集器 %s 的间隔不能小于 %d 秒", name, minSeconds)
+		}
+	}
+	return nil
+}
+
 // pluginConfigsToNames 提取插件配置的名称和版本
 func pluginConfigsToNames(configs []model.PluginConfig) []map[string]string {
 	result := make([]map[string]string, len(configs))
@@ -36,23 +36,23 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 	// 1. 资产概览
 	// 统计物理主机（非容器）
 	var hostCount int64
-	h.db.Model(&model.Host{}).Where("is_container = ?", false).Count(&hostCount)
+	h.hosts(c).Where("is_container = ?", false).Count(&hostCount)
 
 	// 统计容器
 	var containerCount int64
-	h.db.Model(&model.Host{}).Where("is_container = ?", true).Count(&containerCount)
+	h.hosts(c).Where("is_container = ?", true).Count(&containerCount)
 
 	var onlineHostCount int64
-	h.db.Model(&model.Host{}).Where("status = ? AND is_container = ?", "online", false).Count(&onlineHostCount)
+	h.hosts(c).Where("status = ? AND is_container = ?", "online", false).Count(&onlineHostCount)
 
 	var onlineContainerCount int64
-	h.db.Model(&model.Host{}).Where("status = ? AND is_container = ?", "online", true).Count(&onlineContainerCount)
+	h.hosts(c).Where("status = ? AND is_container = ?", "online", true).Count(&onlineContainerCount)
 
 	var offlineHostCount int64
-	h.db.Model(&model.Host{}).Where("status = ? AND is_container = ?", "offline", false).Count(&offlineHostCount)
+	h.hosts(c).Where("status = ? AND is_container = ?", "offline", false).Count(&offlineHostCount)
 
 	var offlineContainerCount int64
-	h.db.Model(&model.Host{}).Where("status = ? AND is_container = ?", "offline", true).Count(&offlineContainerCount)
+	h.hosts(c).Where("status = ? AND is_container = ?", "offline", true).Count(&offlineContainerCount)
 
 	stats["hosts"] = hostCount
 	stats["clusters"] = 0 // TODO: 后续实现集群统计
@@ -61,7 +61,7 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 	stats["offlineAgents"] = offlineHostCount + offlineContainerCount
 
 	// 计算Agent数量变化（较昨日）
-	onlineChange, offlineChange := h.calculateAgentChanges()
+	onlineChange, offlineChange := h.calculateAgentChanges(c)
 	stats["onlineAgentsChange"] = onlineChange
 	stats["offlineAgentsChange"] = offlineChange
 
@@ -77,19 +77,19 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 	// 查询最近7天的基线检查结果
 	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
 	var baselineFailCount int64
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Where("status = ? AND checked_at >= ?", "fail", sevenDaysAgo).
 		Count(&baselineFailCount)
 
 	stats["baselineFailCount"] = baselineFailCount
 
 	// 计算基线加固百分比（优化：使用SQL聚合查询）
-	baselineHardeningPercent, baselineHostPercent := h.calculateBaselinePercentages()
+	baselineHardeningPercent, baselineHostPercent := h.calculateBaselinePercentages(c)
 	stats["baselineHardeningPercent"] = baselineHardeningPercent
 	stats["baselineHostPercent"] = baselineHostPercent
 
 	// 5. 基线风险 Top 3
-	baselineRisks := h.getBaselineRisksTop3()
+	baselineRisks := h.getBaselineRisksTop3(c)
 	stats["baselineRisks"] = baselineRisks
 
 	// 6. Agent 资源使用统计（暂时返回0，后续从心跳数据中获取）
@@ -123,7 +123,7 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 // calculateAgentChanges 计算Agent数量变化（较昨日）
 // 简化实现：由于没有历史快照数据，使用24小时前的心跳时间来判断
 // 如果主机在24-48小时前有心跳，认为24小时前是在线的
-func (h *DashboardHandler) calculateAgentChanges() (int, int) {
+func (h *DashboardHandler) calculateAgentChanges(c *gin.Context) (int, int) {
 	now := time.Now()
 	oneDayAgo := now.AddDate(0, 0, -1)
 	twoDaysAgo := now.AddDate(0, 0, -2)
@@ -131,21 +131,21 @@ func (h *DashboardHandler) calculateAgentChanges() (int, int) {
 	// 查询24小时前在线的主机（通过last_heartbeat判断）
 	// 如果last_heartbeat在24-48小时前之间，说明24小时前可能是在线的
 	var yesterdayOnlineCount int64
-	h.db.Model(&model.Host{}).
+	h.hosts(c).
 		Where("last_heartbeat >= ? AND last_heartbeat < ?", twoDaysAgo, oneDayAgo).
 		Count(&yesterdayOnlineCount)
 
 	// 查询当前在线的主机数量
 	var currentOnlineCount int64
-	h.db.Model(&model.Host{}).Where("status = ?", "online").Count(&currentOnlineCount)
+	h.hosts(c).Where("status = ?", "online").Count(&currentOnlineCount)
 
 	// 查询当前离线的主机数量
 	var currentOfflineCount int64
-	h.db.Model(&model.Host{}).Where("status = ?", "offline").Count(&currentOfflineCount)
+	h.hosts(c).Where("status = ?", "offline").Count(&currentOfflineCount)
 
 	// 查询24小时前的主机总数（创建时间在24小时前）
 	var yesterdayTotalCount int64
-	h.db.Model(&model.Host{}).
+	h.hosts(c).
 		Where("created_at <= ?", oneDayAgo).
 		Count(&yesterdayTotalCount)
 
@@ -169,9 +169,9 @@ func (h *DashboardHandler) calculateAgentChanges() (int, int) {
 }
 
 // calculateBaselinePercentages 计算基线合规率和存在高危基线问题的主机百分比
-func (h *DashboardHandler) calculateBaselinePercentages() (float64, float64) {
+func (h *DashboardHandler) calculateBaselinePercentages(c *gin.Context) (float64, float64) {
 	var totalHosts int64
-	h.db.Model(&model.Host{}).Count(&totalHosts)
+	h.hosts(c).Count(&totalHosts)
 
 	if totalHosts == 0 {
 		return 100.0, 0.0 // 没有主机时，合规率为100%，高危主机为0%
@@ -179,7 +179,7 @@ func (h *DashboardHandler) calculateBaselinePercentages() (float64, float64) {
 
 	// 统计有基线检查结果的主机（作为检查过的主机）
 	var hostsWithResults int64
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Distinct("host_id").
 		Count(&hostsWithResults)
 
@@ -189,13 +189,13 @@ func (h *DashboardHandler) calculateBaselinePercentages() (float64, float64) {
 
 	// 统计检查通过的结果数
 	var passCount int64
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Where("status = ?", "pass").
 		Count(&passCount)
 
 	// 统计检查失败的结果数
 	var failCount int64
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Where("status = ?", "fail").
 		Count(&failCount)
 
@@ -210,7 +210,7 @@ func (h *DashboardHandler) calculateBaselinePercentages() (float64, float64) {
 
 	// 存在高危基线的主机百分比（查询有high或critical级别失败结果的主机）
 	var hostsWithHighRiskBaseline int64
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Where("status = ? AND severity IN (?)", "fail", []string{"high", "critical"}).
 		Distinct("host_id").
 		Count(&hostsWithHighRiskBaseline)
@@ -229,7 +229,7 @@ func (h *DashboardHandler) calculateBaselinePercentages() (float64, float64) {
 }
 
 // getBaselineRisksTop3 获取基线风险 Top 3（优化：使用更好的排序算法）
-func (h *DashboardHandler) getBaselineRisksTop3() []gin.H {
+func (h *DashboardHandler) getBaselineRisksTop3(c *gin.Context) []gin.H {
 	// 查询所有策略，统计每个策略的风险数量
 	type PolicyRisk struct {
 		PolicyID string
@@ -250,15 +250,15 @@ func (h *DashboardHandler) getBaselineRisksTop3() []gin.H {
 		var criticalCount, mediumCount, lowCount int64
 
 		// 查询该策略下失败的基线检查结果，按严重程度统计
-		h.db.Model(&model.ScanResult{}).
+		h.results(c).
 			Where("policy_id = ? AND status = ? AND severity = ?", policy.ID, "fail", "critical").
 			Count(&criticalCount)
 
-		h.db.Model(&model.ScanResult{}).
+		h.results(c).
 			Where("policy_id = ? AND status = ? AND severity = ?", policy.ID, "fail", "medium").
 			Count(&mediumCount)
 
-		h.db.Model(&model.ScanResult{}).
+		h.results(c).
 			Where("policy_id = ? AND status = ? AND severity = ?", policy.ID, "fail", "low").
 			Count(&lowCount)
 
@@ -296,6 +296,16 @@ func (h *DashboardHandler) getBaselineRisksTop3() []gin.H {
 	return top3
 }
 
+// hosts 返回限制在当前用户业务线范围内的主机查询
+func (h *DashboardHandler) hosts(c *gin.Context) *gorm.DB {
+	return scopeHosts(c, h.db.Model(&model.Host{}), "business_line")
+}
+
+// results 返回限制在当前用户业务线范围内的检查结果查询
+func (h *DashboardHandler) results(c *gin.Context) *gorm.DB {
+	return scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id")
+}
+
 // checkDatabaseStatus 检查数据库连接状态
 func (h *DashboardHandler) checkDatabaseStatus() string {
 	if h.db == nil {
@@ -0,0 +1,142 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// errDiagnosticsRunning 表示主机已有进行中的诊断信息收集
+var errDiagnosticsRunning = errors.New("diagnostics collection in progress")
+
+// CollectDiagnostics 触发 Agent 诊断信息收集
+// AgentCenter 调度器下发带一次性上传令牌的诊断命令，Agent 打包配置、日志、任务状态等后通过 FileExt 上传
+// POST /api/v1/hosts/:host_id/diagnostics
+func (h *HostsHandler) CollectDiagnostics(c *gin.Context) {
+	hostID := c.Param("host_id")
+
+	var host model.Host
+	if err := h.db.Select("host_id", "hostname", "status").Where("host_id = ?", hostID).First(&host).Error; err != nil {
+		NotFound(c, "主机不存在")
+		return
+	}
+	if host.Status != model.HostStatusOnline {
+		BadRequest(c, "主机不在线")
+		return
+	}
+
+	token, err := newUploadToken()
+	if err != nil {
+		h.logger.Error("生成上传令牌失败", zap.Error(err))
+		InternalError(c, "生成上传令牌失败")
+		return
+	}
+
+	record := model.AgentDiagnostics{
+		HostID:    host.HostID,
+		Hostname:  host.Hostname,
+		Token:     token,
+		Status:    model.AgentDiagnosticsStatusPending,
+		CreatedBy: currentUsername(c),
+	}
+	// 同一主机同时只允许一个进行中的收集；锁定主机记录，避免并发请求同时通过检查
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("host_id").Where("host_id = ?", hostID).First(&model.Host{}).Error; err != nil {
+			return err
+		}
+		var running int64
+		if err := tx.Model(&model.AgentDiagnostics{}).
+			Where("host_id = ? AND status IN ?", hostID, []model.AgentDiagnosticsStatus{
+				model.AgentDiagnosticsStatusPending,
+				model.AgentDiagnosticsStatusCollecting,
+			}).Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return errDiagnosticsRunning
+		}
+		return tx.Create(&record).Error
+	})
+	if errors.Is(err, errDiagnosticsRunning) {
+		Conflict(c, "该主机已有进行中的诊断信息收集")
+		return
+	}
+	if err != nil {
+		h.logger.Error("创建诊断记录失败", zap.String("host_id", hostID), zap.Error(err))
+		InternalError(c, "创建诊断记录失败")
+		return
+	}
+
+	h.logger.Info("创建 Agent 诊断信息收集记录",
+		zap.Uint("record_id", record.ID),
+		zap.String("host_id", hostID),
+		zap.String("created_by", record.CreatedBy),
+	)
+
+	SuccessWithMessage(c, "诊断信息收集已提交", record)
+}
+
+// ListDiagnostics 获取主机的诊断信息收集记录（不含诊断包内容）
+// GET /api/v1/hosts/:host_id/diagnostics
+func (h *HostsHandler) ListDiagnostics(c *gin.Context) {
+	hostID := c.Param("host_id")
+
+	var records []model.AgentDiagnostics
+	if err := h.db.Omit("content").Where("host_id = ?", hostID).
+		Order("created_at DESC").Limit(20).Find(&records).Error; err != nil {
+		h.logger.Error("查询诊断记录失败", zap.String("host_id", hostID), zap.Error(err))
+		InternalError(c, "查询诊断记录失败")
+		return
+	}
+
+	Success(c, records)
+}
+
+// DownloadDiagnostics 下载诊断包（tar.gz）
+// GET /api/v1/diagnostics/:id/download
+func (h *HostsHandler) DownloadDiagnostics(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		BadRequest(c, "无效的记录 ID")
+		return
+	}
+
+	var record model.AgentDiagnostics
+	if err := h.db.Where("id = ?", id).First(&record).Error; err != nil {
+		NotFound(c, "诊断记录不存在")
+		return
+	}
+	if !requireHostInScope(c, h.db, record.HostID) {
+		return
+	}
+	if record.Status != model.AgentDiagnosticsStatusCompleted {
+		BadRequest(c, "诊断包尚未上传完成")
+		return
+	}
+
+	filename := fmt.Sprintf("diagnostics_%s_%s.tar.gz", record.Hostname, record.CreatedAt.Time().Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("X-Content-SHA256", record.SHA256)
+	c.Data(http.StatusOK, "application/gzip", record.Content)
+}
+
+// newUploadToken 生成一次性上传令牌
+func newUploadToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
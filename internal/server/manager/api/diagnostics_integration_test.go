@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestCollectDiagnosticsInProgress 测试同一主机已有进行中的收集时拒绝新的收集请求
+func TestCollectDiagnosticsInProgress(t *testing.T) {
+	db := testdb.Open(t, &model.Host{}, &model.AgentDiagnostics{})
+	if err := db.Create(&model.Host{HostID: "host-1", Hostname: "web-1", Status: model.HostStatusOnline}).Error; err != nil {
+		t.Fatal(err)
+	}
+	handler := NewHostsHandler(db, zap.NewNop(), nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/hosts/:host_id/diagnostics", handler.CollectDiagnostics)
+	collect := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/hosts/host-1/diagnostics", nil))
+		return w.Code
+	}
+
+	if code := collect(); code != http.StatusOK {
+		t.Fatalf("first collect: status = %d, want 200", code)
+	}
+	if code := collect(); code != http.StatusConflict {
+		t.Fatalf("second collect: status = %d, want 409", code)
+	}
+
+	// 上一次收集结束后可以再次发起
+	if err := db.Model(&model.AgentDiagnostics{}).Where("host_id = ?", "host-1").
+		Update("status", model.AgentDiagnosticsStatusCompleted).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := collect(); code != http.StatusOK {
+		t.Fatalf("collect after completion: status = %d, want 200", code)
+	}
+	var count int64
+	db.Model(&model.AgentDiagnostics{}).Where("host_id = ?", "host-1").Count(&count)
+	if count != 2 {
+		t.Errorf("diagnostics records = %d, want 2", count)
+	}
+}
@@ -32,7 +32,7 @@ func (h *FIMEventsHandler) ListFIMEvents(c *gin.Context) {
 		pageSize = 20
 	}
 
-	query := h.db.Model(&model.FIMEvent{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.FIMEvent{}), "host_id")
 
 	// 筛选条件
 	if hostID := c.Query("host_id"); hostID != "" {
@@ -86,7 +86,7 @@ func (h *FIMEventsHandler) GetFIMEvent(c *gin.Context) {
 	eventID := c.Param("id")
 
 	var event model.FIMEvent
-	if err := h.db.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+	if err := scopeByHost(c, h.db, h.db.Where("event_id = ?", eventID), "host_id").First(&event).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			NotFound(c, "事件不存在")
 			return
@@ -141,20 +141,23 @@ func (h *FIMEventsHandler) GetFIMEventStats(c *gin.Context) {
 	stats := FIMEventStats{
 		ByCategory: make(map[string]int64),
 	}
+	events := func() *gorm.DB {
+		return scopeByHost(c, h.db, h.db.Model(&model.FIMEvent{}), "host_id")
+	}
 
 	// 总数
-	h.db.Model(&model.FIMEvent{}).Count(&stats.Total)
+	events().Count(&stats.Total)
 
 	// 按严重等级统计
-	h.db.Model(&model.FIMEvent{}).Where("severity = ?", "critical").Count(&stats.Critical)
-	h.db.Model(&model.FIMEvent{}).Where("severity = ?", "high").Count(&stats.High)
-	h.db.Model(&model.FIMEvent{}).Where("severity = ?", "medium").Count(&stats.Medium)
-	h.db.Model(&model.FIMEvent{}).Where("severity = ?", "low").Count(&stats.Low)
+	events().Where("severity = ?", "critical").Count(&stats.Critical)
+	events().Where("severity = ?", "high").Count(&stats.High)
+	events().Where("severity = ?", "medium").Count(&stats.Medium)
+	events().Where("severity = ?", "low").Count(&stats.Low)
 
 	// 按变更类型统计
-	h.db.Model(&model.FIMEvent{}).Where("change_type = ?", "added").Count(&stats.Added)
-	h.db.Model(&model.FIMEvent{}).Where("change_type = ?", "removed").Count(&stats.Removed)
-	h.db.Model(&model.FIMEvent{}).Where("change_type = ?", "changed").Count(&stats.Changed)
+	events().Where("change_type = ?", "added").Count(&stats.Added)
+	events().Where("change_type = ?", "removed").Count(&stats.Removed)
+	events().Where("change_type = ?", "changed").Count(&stats.Changed)
 
 	// 按分类统计
 	type CategoryCount struct {
@@ -162,7 +165,7 @@ func (h *FIMEventsHandler) GetFIMEventStats(c *gin.Context) {
 		Count    int64  `json:"count"`
 	}
 	var categoryCounts []CategoryCount
-	h.db.Model(&model.FIMEvent{}).
+	events().
 		Select("category, COUNT(*) as count").
 		Where("category IS NOT NULL AND category != ''").
 		Group("category").
@@ -172,7 +175,7 @@ func (h *FIMEventsHandler) GetFIMEventStats(c *gin.Context) {
 	}
 
 	// Top 10 主机
-	h.db.Model(&model.FIMEvent{}).
+	events().
 		Select("host_id, hostname, COUNT(*) as count").
 		Group("host_id, hostname").
 		Order("count DESC").
@@ -180,7 +183,7 @@ func (h *FIMEventsHandler) GetFIMEventStats(c *gin.Context) {
 		Find(&stats.TopHosts)
 
 	// 趋势数据
-	h.db.Model(&model.FIMEvent{}).
+	events().
 		Select("DATE(detected_at) as date, COUNT(*) as count").
 		Where("detected_at >= DATE_SUB(NOW(), INTERVAL ? DAY)", days).
 		Group("DATE(detected_at)").
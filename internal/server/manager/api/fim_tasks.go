@@ -41,7 +41,7 @@ func (h *FIMTasksHandler) ListFIMTasks(c *gin.Context) {
 		pageSize = 20
 	}
 
-	query := h.db.Model(&model.FIMTask{})
+	query := scopeByBusinessLines(c, h.db.Model(&model.FIMTask{}), "target_config", "$.business_lines")
 
 	// 筛选条件
 	if policyID := c.Query("policy_id"); policyID != "" {
@@ -83,6 +83,9 @@ func (h *FIMTasksHandler) GetFIMTask(c *gin.Context) {
 		InternalError(c, "查询失败")
 		return
 	}
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
 
 	// 查询主机状态
 	var hostStatuses []model.FIMTaskHostStatus
@@ -120,6 +123,11 @@ func (h *FIMTasksHandler) CreateFIMTask(c *gin.Context) {
 		targetType = policy.TargetType
 		targetConfig = policy.TargetConfig
 	}
+	// 受业务线范围限制的用户只能对范围内的主机创建任务，任务记录其业务线范围
+	if !requireHostsInScope(c, h.db, targetConfig.HostIDs) {
+		return
+	}
+	targetConfig.BusinessLines = scopeOf(c).BusinessLines
 
 	task := model.FIMTask{
 		TaskID:       uuid.New().String(),
@@ -156,6 +164,9 @@ func (h *FIMTasksHandler) RunFIMTask(c *gin.Context) {
 		InternalError(c, "查询失败")
 		return
 	}
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
 
 	if task.Status != "pending" {
 		BadRequest(c, "任务当前状态不允许执行")
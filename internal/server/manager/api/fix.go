@@ -10,6 +10,9 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -65,8 +68,8 @@ func (h *FixHandler) GetFixableItems(c *gin.Context) {
 		pageSize = 20
 	}
 
-	// 构建查询
-	query := h.db.Model(&model.ScanResult{}).
+	// 构建查询（限制在当前用户的业务线范围内）
+	query := scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 		Where("scan_results.status IN ?", []string{"fail", "error"})
 
 	// 主机筛选
@@ -215,8 +218,8 @@ func (h *FixHandler) CreateFixTask(c *gin.Context) {
 			zap.String("business_line", req.BusinessLine),
 			zap.Strings("severities", req.Severities))
 
-		// 构建查询
-		query := h.db.Model(&model.ScanResult{}).
+		// 构建查询（限制在当前用户的业务线范围内）
+		query := scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 			Where("scan_results.status IN ?", []string{"fail", "error"})
 
 		// 严重级别筛选
@@ -339,6 +342,39 @@ func (h *FixHandler) CreateFixTask(c *gin.Context) {
 			Count(&actualCount)
 	}
 
+	// 受业务线范围限制的用户只能修复范围内的主机
+	if !requireHostsInScope(c, h.db, hostIDs) {
+		return
+	}
+	// 任务记录目标主机所属业务线，用于按业务线范围查看任务
+	businessLines, err := hostBusinessLines(h.db, hostIDs)
+	if err != nil {
+		h.logger.Error("查询主机业务线失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+
+	// 任务保存规则快照，下发时使用创建（审批）时的修复命令
+	var rules []model.Rule
+	if err := h.db.Where("rule_id IN ?", ruleIDs).Find(&rules).Error; err != nil {
+		h.logger.Error("查询规则失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	ruleSnapshot := make(model.FixRuleSnapshots, 0, len(rules))
+	ruleSeverities := make([]string, 0, len(rules))
+	for i := range rules {
+		ruleSnapshot = append(ruleSnapshot, model.NewFixRuleSnapshot(&rules[i]))
+		ruleSeverities = append(ruleSeverities, rules[i].Severity)
+	}
+
+	// 命中审批策略的修复任务以 pending_approval 状态创建，批准后才会下发
+	approvalCfg, ok := loadApprovalConfig(c, h.db, h.logger)
+	if !ok {
+		return
+	}
+	approvalReason := approval.FixTaskReason(approvalCfg.FixTask, len(hostIDs), ruleSeverities)
+
 	// 创建任务
 	taskID := uuid.New().String()
 
@@ -349,20 +385,45 @@ func (h *FixHandler) CreateFixTask(c *gin.Context) {
 	}
 
 	task := &model.FixTask{
-		TaskID:       taskID,
-		HostIDs:      hostIDs,
-		RuleIDs:      ruleIDs,
-		Severities:   req.Severities,
-		Status:       model.FixTaskStatusPending,
-		TotalCount:   totalCount,
-		SuccessCount: 0,
-		FailedCount:  0,
-		Progress:     0,
-		CreatedBy:    c.GetString("user_id"),
-		CreatedAt:    model.Now(),
-	}
-
-	if err := h.db.Create(task).Error; err != nil {
+		TaskID:        taskID,
+		HostIDs:       hostIDs,
+		RuleIDs:       ruleIDs,
+		Severities:    req.Severities,
+		BusinessLines: businessLines,
+		Status:        model.FixTaskStatusPending,
+		TotalCount:    totalCount,
+		SuccessCount:  0,
+		FailedCount:   0,
+		Progress:      0,
+		CreatedBy:     c.GetString("username"),
+		CreatedAt:     model.Now(),
+		RuleSnapshot:  ruleSnapshot,
+	}
+
+	var approvalReq *model.ApprovalRequest
+	if approvalReason != "" {
+		task.Status = model.FixTaskStatusPendingApproval
+		approvalReq = &model.ApprovalRequest{
+			Action:        approval.ActionFixCreate,
+			ResourceID:    taskID,
+			Summary:       fmt.Sprintf("修复 %d 台主机上的 %d 条规则（共 %d 项）", len(hostIDs), len(ruleIDs), totalCount),
+			Reason:        approvalReason,
+			Targets:       hostIDs,
+			BusinessLines: businessLines,
+			Permission:    string(rbac.FixExecute),
+			RequestedBy:   task.CreatedBy,
+		}
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		if approvalReq != nil {
+			return approval.Submit(tx, approvalReq)
+		}
+		return nil
+	}); err != nil {
 		h.logger.Error("创建修复任务失败", zap.Error(err))
 		InternalError(c, "创建任务失败")
 		return
@@ -376,6 +437,14 @@ func (h *FixHandler) CreateFixTask(c *gin.Context) {
 		zap.Int("host_count", len(hostIDs)),
 		zap.Int("rule_count", len(ruleIDs)),
 		zap.Int("total_count", totalCount))
+	audit.AddTargets(c, taskID)
+	audit.AddTargets(c, hostIDs...)
+	audit.SetChange(c, nil, task)
+
+	if approvalReq != nil {
+		approvalSubmitted(c, h.db, h.logger, approvalReq, gin.H{"task_id": taskID})
+		return
+	}
 
 	Success(c, gin.H{
 		"task_id": taskID,
@@ -400,6 +469,9 @@ func (h *FixHandler) GetFixTask(c *gin.Context) {
 		InternalError(c, "查询失败")
 		return
 	}
+	if !requireBusinessLinesInScope(c, task.BusinessLines) {
+		return
+	}
 
 	Success(c, task)
 }
@@ -417,7 +489,7 @@ func (h *FixHandler) ListFixTasks(c *gin.Context) {
 		pageSize = 20
 	}
 
-	query := h.db.Model(&model.FixTask{})
+	query := scopeByBusinessLines(c, h.db.Model(&model.FixTask{}), "business_lines", "$")
 
 	// 状态筛选
 	if status != "" {
@@ -475,6 +547,10 @@ func (h *FixHandler) GetFixResults(c *gin.Context) {
 		pageSize = 20
 	}
 
+	if !h.requireFixTaskInScope(c, taskID) {
+		return
+	}
+
 	query := h.db.Model(&model.FixResult{}).Where("task_id = ?", taskID)
 
 	// 状态筛选
@@ -563,9 +639,12 @@ func (h *FixHandler) CancelFixTask(c *gin.Context) {
 		InternalError(c, "查询失败")
 		return
 	}
+	if !requireBusinessLinesInScope(c, task.BusinessLines) {
+		return
+	}
 
-	// 只能取消待执行或执行中的任务
-	if task.Status != model.FixTaskStatusPending && task.Status != model.FixTaskStatusRunning {
+	// 只能取消待审批、待执行或执行中的任务
+	if task.Status != model.FixTaskStatusPendingApproval && task.Status != model.FixTaskStatusPending && task.Status != model.FixTaskStatusRunning {
 		BadRequest(c, fmt.Sprintf("任务状态为 %s，无法取消", task.Status))
 		return
 	}
@@ -581,11 +660,81 @@ func (h *FixHandler) CancelFixTask(c *gin.Context) {
 		InternalError(c, "取消任务失败")
 		return
 	}
+	if task.Status == model.FixTaskStatusPendingApproval {
+		h.cancelApproval(taskID, c.GetString("username"))
+	}
+	// 等待维护窗口的主机不再下发
+	h.db.Model(&model.FixTaskHostStatus{}).
+		Where("task_id = ? AND status = ?", taskID, model.FixTaskHostStatusWaiting).
+		Updates(map[string]interface{}{
+			"status":        model.FixTaskHostStatusFailed,
+			"error_message": "任务已取消",
+			"completed_at":  model.Now(),
+		})
 
 	h.logger.Info("取消修复任务成功", zap.String("task_id", taskID))
 	Success(c, nil)
 }
 
+// EmergencyOverrideRequest 紧急放行请求
+type EmergencyOverrideRequest struct {
+	Reason string `json:"reason" binding:"required"` // 放行原因（记录到任务和审计日志）
+}
+
+// EmergencyOverrideFixTask 紧急放行修复任务：不再等待维护窗口和变更冻结，下一个调度周期立即下发
+// POST /api/v1/fix-tasks/:task_id/emergency
+func (h *FixHandler) EmergencyOverrideFixTask(c *gin.Context) {
+	taskID := c.Param("task_id")
+	var req EmergencyOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请填写紧急放行原因")
+		return
+	}
+
+	var task model.FixTask
+	if err := h.db.Where("task_id = ?", taskID).First(&task).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "任务不存在")
+			return
+		}
+		h.logger.Error("查询修复任务失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return
+	}
+	if !requireBusinessLinesInScope(c, task.BusinessLines) {
+		return
+	}
+
+	if task.Status != model.FixTaskStatusPending && task.Status != model.FixTaskStatusRunning {
+		BadRequest(c, fmt.Sprintf("任务状态为 %s，无法紧急放行", task.Status))
+		return
+	}
+	if task.Overridden() {
+		Conflict(c, "任务已紧急放行")
+		return
+	}
+
+	now := model.Now()
+	operator := c.GetString("username")
+	if err := h.db.Model(&task).Updates(map[string]interface{}{
+		"emergency_reason": req.Reason,
+		"emergency_by":     operator,
+		"emergency_at":     &now,
+	}).Error; err != nil {
+		h.logger.Error("紧急放行修复任务失败", zap.Error(err))
+		InternalError(c, "紧急放行失败")
+		return
+	}
+
+	audit.AddTargets(c, taskID)
+	audit.SetField(c, "emergency_reason", "", req.Reason)
+	h.logger.Warn("修复任务已紧急放行",
+		zap.String("task_id", taskID),
+		zap.String("operator", operator),
+		zap.String("reason", req.Reason))
+	SuccessMessage(c, "已紧急放行，等待维护窗口的主机将在下一个调度周期下发")
+}
+
 // DeleteFixTask 删除修复任务
 func (h *FixHandler) DeleteFixTask(c *gin.Context) {
 	taskID := c.Param("task_id")
@@ -604,6 +753,9 @@ func (h *FixHandler) DeleteFixTask(c *gin.Context) {
 		InternalError(c, "查询失败")
 		return
 	}
+	if !requireBusinessLinesInScope(c, task.BusinessLines) {
+		return
+	}
 
 	// 只能删除已完成或失败的任务
 	if task.Status == model.FixTaskStatusRunning {
@@ -628,6 +780,10 @@ func (h *FixHandler) DeleteFixTask(c *gin.Context) {
 		return
 	}
 
+	if task.Status == model.FixTaskStatusPendingApproval {
+		h.cancelApproval(taskID, c.GetString("username"))
+	}
+
 	h.logger.Info("删除修复任务成功", zap.String("task_id", taskID))
 	Success(c, nil)
 }
@@ -651,6 +807,10 @@ func (h *FixHandler) GetFixTaskHostStatus(c *gin.Context) {
 		pageSize = 20
 	}
 
+	if !h.requireFixTaskInScope(c, taskID) {
+		return
+	}
+
 	query := h.db.Model(&model.FixTaskHostStatus{}).Where("task_id = ?", taskID)
 
 	// 状态筛选
@@ -682,3 +842,28 @@ func (h *FixHandler) GetFixTaskHostStatus(c *gin.Context) {
 		"total": total,
 	})
 }
+
+// requireFixTaskInScope 校验修复任务在当前用户的业务线范围内（不限制范围时不查询）
+func (h *FixHandler) requireFixTaskInScope(c *gin.Context, taskID string) bool {
+	if scopeOf(c).Unrestricted() {
+		return true
+	}
+	var task model.FixTask
+	if err := h.db.Select("business_lines").Where("task_id = ?", taskID).First(&task).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "任务不存在")
+			return false
+		}
+		h.logger.Error("查询修复任务失败", zap.Error(err))
+		InternalError(c, "查询失败")
+		return false
+	}
+	return requireBusinessLinesInScope(c, task.BusinessLines)
+}
+
+// cancelApproval 撤销修复任务待审批的申请（任务在审批前被取消或删除）
+func (h *FixHandler) cancelApproval(taskID, operator string) {
+	if err := approval.CancelByResource(h.db, approval.ActionFixCreate, taskID, operator); err != nil {
+		h.logger.Error("撤销修复任务审批失败", zap.String("task_id", taskID), zap.Error(err))
+	}
+}
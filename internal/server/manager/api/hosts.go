@@ -2,6 +2,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,7 +11,10 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -52,8 +56,8 @@ func (h *HostsHandler) ListHosts(c *gin.Context) {
 	isContainerStr := c.Query("is_container") // 容器/主机类型筛选（废弃，使用 runtime_type）
 	runtimeType := c.Query("runtime_type")    // 运行环境类型筛选：vm/docker/k8s
 
-	// 构建查询
-	query := h.db.Model(&model.Host{})
+	// 构建查询（限制在当前用户的业务线范围内）
+	query := scopeHosts(c, h.db.Model(&model.Host{}), "business_line")
 
 	// 过滤条件
 	if osFamily != "" {
@@ -163,10 +167,10 @@ func (h *HostsHandler) GetHostStatusDistribution(c *gin.Context) {
 	var distribution HostStatusDistribution
 
 	// 运行中（在线）
-	h.db.Model(&model.Host{}).Where("status = ?", "online").Count(&distribution.Running)
+	scopeHosts(c, h.db.Model(&model.Host{}), "business_line").Where("status = ?", "online").Count(&distribution.Running)
 
 	// 离线
-	h.db.Model(&model.Host{}).Where("status = ?", "offline").Count(&distribution.Offline)
+	scopeHosts(c, h.db.Model(&model.Host{}), "business_line").Where("status = ?", "offline").Count(&distribution.Offline)
 
 	// 运行异常（暂时用离线超过一定时间的主机表示，后续可扩展）
 	// TODO: 实现运行异常的逻辑
@@ -187,7 +191,7 @@ func (h *HostsHandler) GetHostRiskDistribution(c *gin.Context) {
 
 	// 统计存在严重(critical)风险基线的主机数
 	var hostsWithCritical []string
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id").
 		Select("DISTINCT host_id").
 		Where("status = ? AND severity = ?", "fail", "critical").
 		Pluck("host_id", &hostsWithCritical)
@@ -195,7 +199,7 @@ func (h *HostsHandler) GetHostRiskDistribution(c *gin.Context) {
 
 	// 统计存在高危(high)风险基线的主机数
 	var hostsWithHigh []string
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id").
 		Select("DISTINCT host_id").
 		Where("status = ? AND severity = ?", "fail", "high").
 		Pluck("host_id", &hostsWithHigh)
@@ -203,7 +207,7 @@ func (h *HostsHandler) GetHostRiskDistribution(c *gin.Context) {
 
 	// 统计存在中危(medium)风险基线的主机数
 	var hostsWithMedium []string
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id").
 		Select("DISTINCT host_id").
 		Where("status = ? AND severity = ?", "fail", "medium").
 		Pluck("host_id", &hostsWithMedium)
@@ -211,7 +215,7 @@ func (h *HostsHandler) GetHostRiskDistribution(c *gin.Context) {
 
 	// 统计存在低危(low)风险基线的主机数
 	var hostsWithLow []string
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id").
 		Select("DISTINCT host_id").
 		Where("status = ? AND severity = ?", "fail", "low").
 		Pluck("host_id", &hostsWithLow)
@@ -337,6 +341,11 @@ func (h *HostsHandler) GetHost(c *gin.Context) {
 	if host.BusinessLine != "" {
 		responseData["business_line"] = host.BusinessLine
 	}
+	// 资源保护状态（限流/熔断）
+	if host.GuardState != "" {
+		responseData["guard_state"] = host.GuardState
+		responseData["guard_status"] = host.GuardStatus
+	}
 	// 时间字段：始终返回，即使为空也返回 nil（让前端处理显示）
 	responseData["system_boot_time"] = host.SystemBootTime
 	responseData["agent_start_time"] = host.AgentStartTime
@@ -597,6 +606,15 @@ func (h *HostsHandler) UpdateHostBusinessLine(c *gin.Context) {
 		return
 	}
 
+	// 受业务线范围限制的用户只能将主机分配到范围内的业务线
+	if !scopeOf(c).Contains(req.BusinessLine) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code":    403,
+			"message": "无权将主机分配到业务线范围外",
+		})
+		return
+	}
+
 	// 如果指定了业务线，验证业务线是否存在（使用 code 查询）
 	if req.BusinessLine != "" {
 		var businessLine model.BusinessLine
@@ -641,14 +659,15 @@ func (h *HostsHandler) UpdateHostBusinessLine(c *gin.Context) {
 
 // HostPluginResponse 主机插件响应
 type HostPluginResponse struct {
-	ID            uint   `json:"id"`
-	Name          string `json:"name"`
-	Version       string `json:"version"`
-	Status        string `json:"status"`
-	StartTime     string `json:"start_time,omitempty"`
-	UpdatedAt     string `json:"updated_at"`
-	LatestVersion string `json:"latest_version"`
-	NeedUpdate    bool   `json:"need_update"`
+	ID            uint                   `json:"id"`
+	Name          string                 `json:"name"`
+	Version       string                 `json:"version"`
+	Status        string                 `json:"status"`
+	StartTime     string                 `json:"start_time,omitempty"`
+	UpdatedAt     string                 `json:"updated_at"`
+	LatestVersion string                 `json:"latest_version"`
+	NeedUpdate    bool                   `json:"need_update"`
+	LimitHits     *model.PluginLimitHits `json:"limit_hits,omitempty"` // 资源限制触发次数
 }
 
 // GetHostPlugins 获取主机插件列表
@@ -706,6 +725,7 @@ func (h *HostsHandler) GetHostPlugins(c *gin.Context) {
 			UpdatedAt:     hp.UpdatedAt.Time().Format("2006-01-02 15:04:05"),
 			LatestVersion: latestVersion,
 			NeedUpdate:    needUpdate,
+			LimitHits:     hp.LimitHits,
 		}
 		if hp.StartTime != nil {
 			item.StartTime = hp.StartTime.Time().Format("2006-01-02 15:04:05")
@@ -753,84 +773,115 @@ func (h *HostsHandler) DeleteHost(c *gin.Context) {
 		return
 	}
 
-	// 使用事务删除主机及其所有关联数据
-	err := h.db.Transaction(func(tx *gorm.DB) error {
+	// 命中审批策略时只创建审批单，批准后再删除
+	cfg, ok := loadApprovalConfig(c, h.db, h.logger)
+	if !ok {
+		return
+	}
+	if cfg.HostDelete {
+		lines := model.StringArray{}
+		if host.BusinessLine != "" {
+			lines = append(lines, host.BusinessLine)
+		}
+		submitApproval(c, h.db, h.logger, &model.ApprovalRequest{
+			Action:        approval.ActionHostDelete,
+			ResourceID:    hostID,
+			Summary:       fmt.Sprintf("删除主机 %s（%s）", host.Hostname, hostID),
+			Reason:        "删除主机需要审批",
+			Targets:       model.StringArray{hostID},
+			BusinessLines: lines,
+			Permission:    string(rbac.HostsManage),
+		})
+		return
+	}
+
+	if err := deleteHost(h.db, h.scoreCache, &host); err != nil {
+		h.logger.Error("删除主机失败", zap.String("host_id", hostID), zap.Error(err))
+		InternalError(c, "删除主机失败")
+		return
+	}
+
+	h.logger.Info("主机已删除", zap.String("host_id", hostID), zap.String("hostname", host.Hostname))
+	audit.SetChange(c, gin.H{
+		"hostname":      host.Hostname,
+		"ipv4":          host.IPv4,
+		"os_family":     host.OSFamily,
+		"os_version":    host.OSVersion,
+		"business_line": host.BusinessLine,
+	}, nil)
+	SuccessMessage(c, "主机删除成功")
+}
+
+// deleteHost 在事务中删除主机及其所有关联数据（检查结果、告警、监控、插件、资产），并清除基线得分缓存
+func deleteHost(db *gorm.DB, scoreCache *biz.BaselineScoreCache, host *model.Host) error {
+	return db.Transaction(func(tx *gorm.DB) error {
 		// 1. 删除扫描结果
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.ScanResult{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.ScanResult{}).Error; err != nil {
 			return err
 		}
 
 		// 2. 删除告警
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Alert{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Alert{}).Error; err != nil {
 			return err
 		}
 
 		// 3. 删除主机监控数据
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.HostMetric{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.HostMetric{}).Error; err != nil {
 			return err
 		}
 
 		// 4. 删除主机插件信息
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.HostPlugin{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.HostPlugin{}).Error; err != nil {
 			return err
 		}
 
 		// 5. 删除资产数据（进程、端口、软件、容器等）
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Process{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Process{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Port{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Port{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Software{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Software{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Container{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Container{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.AssetUser{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.AssetUser{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Cron{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Cron{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Service{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Service{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.NetInterface{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.NetInterface{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Volume{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Volume{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.Kmod{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.Kmod{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&model.App{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", host.HostID).Delete(&model.App{}).Error; err != nil {
 			return err
 		}
 
 		// 6. 清除基线得分缓存
-		if h.scoreCache != nil {
-			h.scoreCache.InvalidateHostScore(hostID)
+		if scoreCache != nil {
+			scoreCache.InvalidateHostScore(host.HostID)
 		}
 
 		// 7. 最后删除主机记录
-		if err := tx.Delete(&host).Error; err != nil {
+		if err := tx.Delete(host).Error; err != nil {
 			return err
 		}
 
 		return nil
 	})
-
-	if err != nil {
-		h.logger.Error("删除主机失败", zap.String("host_id", hostID), zap.Error(err))
-		InternalError(c, "删除主机失败")
-		return
-	}
-
-	h.logger.Info("主机已删除", zap.String("host_id", hostID), zap.String("hostname", host.Hostname))
-	SuccessMessage(c, "主机删除成功")
 }
 
 // RestartAgentRequest Agent 重启请求
@@ -847,8 +898,12 @@ func (h *HostsHandler) RestartAgent(c *gin.Context) {
 		return
 	}
 
-	// 查询目标在线主机数量
-	query := h.db.Model(&model.Host{}).Where("status = ?", model.HostStatusOnline)
+	if !requireHostsInScope(c, h.db, req.HostIDs) {
+		return
+	}
+
+	// 查询目标在线主机数量（限制在当前用户的业务线范围内）
+	query := scopeHosts(c, h.db.Model(&model.Host{}), "business_line").Where("status = ?", model.HostStatusOnline)
 	if len(req.HostIDs) > 0 {
 		query = query.Where("host_id IN ?", req.HostIDs)
 	}
@@ -873,10 +928,11 @@ func (h *HostsHandler) RestartAgent(c *gin.Context) {
 
 	// 创建重启记录
 	record := model.AgentRestartRecord{
-		TargetType:  targetType,
-		TargetHosts: model.StringArray(req.HostIDs),
-		Status:      model.AgentRestartStatusPending,
-		TotalCount:  int(totalCount),
+		TargetType:    targetType,
+		TargetHosts:   model.StringArray(req.HostIDs),
+		Status:        model.AgentRestartStatusPending,
+		TotalCount:    int(totalCount),
+		BusinessLines: model.StringArray(scopeOf(c).BusinessLines),
 	}
 	if err := h.db.Create(&record).Error; err != nil {
 		h.logger.Error("创建重启记录失败", zap.Error(err))
@@ -884,6 +940,7 @@ func (h *HostsHandler) RestartAgent(c *gin.Context) {
 		return
 	}
 
+	audit.AddTargets(c, req.HostIDs...)
 	h.logger.Info("创建 Agent 重启记录",
 		zap.Uint("record_id", record.ID),
 		zap.String("target_type", targetType),
@@ -900,7 +957,8 @@ func (h *HostsHandler) RestartAgent(c *gin.Context) {
 // GET /api/v1/hosts/restart-records
 func (h *HostsHandler) GetRestartRecords(c *gin.Context) {
 	var records []model.AgentRestartRecord
-	if err := h.db.Order("created_at DESC").Limit(20).Find(&records).Error; err != nil {
+	query := scopeByBusinessLines(c, h.db.Model(&model.AgentRestartRecord{}), "business_lines", "$")
+	if err := query.Order("created_at DESC").Limit(20).Find(&records).Error; err != nil {
 		h.logger.Error("查询重启记录失败", zap.Error(err))
 		InternalError(c, "查询重启记录失败")
 		return
@@ -68,9 +68,9 @@ type InspectionOverviewResponse struct {
 // GetOverview 获取巡检概览
 // GET /api/v1/inspection/overview
 func (h *InspectionHandler) GetOverview(c *gin.Context) {
-	// 1. 查询所有主机
+	// 1. 查询所有主机（限制在当前用户的业务线范围内）
 	var hosts []model.Host
-	if err := h.db.Order("status ASC, hostname ASC").Find(&hosts).Error; err != nil {
+	if err := scopeHosts(c, h.db, "business_line").Order("status ASC, hostname ASC").Find(&hosts).Error; err != nil {
 		h.logger.Error("查询主机列表失败", zap.Error(err))
 		InternalError(c, "查询主机列表失败")
 		return
@@ -78,7 +78,7 @@ func (h *InspectionHandler) GetOverview(c *gin.Context) {
 
 	// 2. 查询所有主机插件状态
 	var hostPlugins []model.HostPlugin
-	if err := h.db.Find(&hostPlugins).Error; err != nil {
+	if err := scopeByHost(c, h.db, h.db, "host_id").Find(&hostPlugins).Error; err != nil {
 		h.logger.Error("查询主机插件状态失败", zap.Error(err))
 		InternalError(c, "查询主机插件状态失败")
 		return
@@ -7,10 +7,8 @@ package api
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
@@ -23,43 +21,13 @@ import (
 
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
 )
 
 // setupTestDB 创建测试数据库（使用 MySQL 环境）
 func setupTestDB(t *testing.T) *gorm.DB {
 	// 从环境变量读取测试数据库配置，如果没有则使用默认值
-	testDBHost := os.Getenv("TEST_DB_HOST")
-	if testDBHost == "" {
-		// 检查是否在容器中运行（检查 /.dockerenv 文件）
-		if _, err := os.Stat("/.dockerenv"); err == nil {
-			// 在容器中，使用 host.docker.internal 访问宿主机 MySQL（macOS/Windows）
-			// 对于 Linux，可以使用环境变量指定为 172.17.0.1
-			testDBHost = "host.docker.internal"
-		} else {
-			// 在本地，使用 127.0.0.1
-			testDBHost = "127.0.0.1"
-		}
-	}
-	testDBPort := os.Getenv("TEST_DB_PORT")
-	if testDBPort == "" {
-		testDBPort = "3306"
-	}
-	testDBUser := os.Getenv("TEST_DB_USER")
-	if testDBUser == "" {
-		testDBUser = "mxsec_user"
-	}
-	testDBPassword := os.Getenv("TEST_DB_PASSWORD")
-	if testDBPassword == "" {
-		testDBPassword = "mxsec_password"
-	}
-	testDBName := os.Getenv("TEST_DB_NAME")
-	if testDBName == "" {
-		testDBName = "mxsec_test"
-	}
-
-	// 构建 MySQL DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		testDBUser, testDBPassword, testDBHost, testDBPort, testDBName)
+	dsn := testdb.DSN()
 
 	// 连接数据库
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
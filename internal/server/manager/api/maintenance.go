@@ -0,0 +1,382 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/maintenance"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// MaintenanceHandler 是维护窗口和变更冻结 API 处理器
+type MaintenanceHandler struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewMaintenanceHandler 创建维护窗口处理器
+func NewMaintenanceHandler(db *gorm.DB, logger *zap.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// MaintenanceWindowItem 维护窗口列表项（包含当前开启状态）
+type MaintenanceWindowItem struct {
+	model.MaintenanceWindow
+	Open      bool       `json:"open"`                 // 当前是否开启
+	EndsAt    *time.Time `json:"ends_at,omitempty"`    // 开启时：本次窗口结束时间
+	NextStart *time.Time `json:"next_start,omitempty"` // 未开启时：下次开启时间
+}
+
+// ChangeFreezeItem 变更冻结列表项
+type ChangeFreezeItem struct {
+	model.ChangeFreeze
+	Active bool `json:"active"` // 当前是否生效
+}
+
+// ListMaintenanceWindows 获取维护窗口列表
+// GET /api/v1/maintenance-windows
+func (h *MaintenanceHandler) ListMaintenanceWindows(c *gin.Context) {
+	var windows []model.MaintenanceWindow
+	if err := h.db.Order("id ASC").Find(&windows).Error; err != nil {
+		h.logger.Error("查询维护窗口列表失败", zap.Error(err))
+		InternalError(c, "查询维护窗口列表失败")
+		return
+	}
+
+	now := time.Now()
+	items := make([]MaintenanceWindowItem, 0, len(windows))
+	for _, w := range windows {
+		items = append(items, windowItem(w, now))
+	}
+	SuccessPaginated(c, int64(len(items)), items)
+}
+
+// windowItem 计算维护窗口在 now 时的开启状态
+func windowItem(w model.MaintenanceWindow, now time.Time) MaintenanceWindowItem {
+	item := MaintenanceWindowItem{MaintenanceWindow: w}
+	sched, err := maintenance.ParseCron(w.Cron)
+	if err != nil {
+		return item
+	}
+	open, t := maintenance.OpenAt(&w, sched, now)
+	if t.IsZero() {
+		return item
+	}
+	item.Open = open
+	if open {
+		item.EndsAt = &t
+	} else {
+		item.NextStart = &t
+	}
+	return item
+}
+
+// MaintenanceWindowRequest 创建/更新维护窗口请求
+type MaintenanceWindowRequest struct {
+	Name            string                 `json:"name" binding:"required"`
+	Description     string                 `json:"description"`
+	Cron            string                 `json:"cron" binding:"required"`             // 窗口开始时间（5 段 cron）
+	DurationMinutes int                    `json:"duration_minutes" binding:"required"` // 窗口持续时长（分钟）
+	Timezone        string                 `json:"timezone"`                            // 默认 UTC
+	Scope           model.MaintenanceScope `json:"scope" binding:"required"`
+	ScopeValues     []string               `json:"scope_values"`
+	Enabled         bool                   `json:"enabled"`
+}
+
+// apply 校验请求并写入维护窗口，返回错误信息
+func (r *MaintenanceWindowRequest) apply(w *model.MaintenanceWindow) string {
+	w.Name = strings.TrimSpace(r.Name)
+	w.Description = r.Description
+	w.Cron = strings.TrimSpace(r.Cron)
+	w.DurationMinutes = r.DurationMinutes
+	w.Timezone = r.Timezone
+	if w.Timezone == "" {
+		w.Timezone = "UTC"
+	}
+	w.Scope = r.Scope
+	w.ScopeValues = nil
+	if r.Scope != model.MaintenanceScopeGlobal {
+		for _, v := range r.ScopeValues {
+			if v = strings.TrimSpace(v); v != "" {
+				w.ScopeValues = append(w.ScopeValues, v)
+			}
+		}
+	}
+	w.Enabled = r.Enabled
+
+	if w.Name == "" {
+		return "名称不能为空"
+	}
+	if _, err := maintenance.Validate(w); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// CreateMaintenanceWindow 创建维护窗口
+// POST /api/v1/maintenance-windows
+func (h *MaintenanceHandler) CreateMaintenanceWindow(c *gin.Context) {
+	var req MaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	w := model.MaintenanceWindow{CreatedBy: currentUsername(c)}
+	if msg := req.apply(&w); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+
+	if err := h.db.Create(&w).Error; err != nil {
+		h.logger.Error("创建维护窗口失败", zap.Error(err))
+		InternalError(c, "创建维护窗口失败")
+		return
+	}
+
+	audit.AddTargets(c, w.Name)
+	audit.SetChange(c, nil, w)
+	h.logger.Info("维护窗口已创建", zap.Uint("id", w.ID), zap.String("name", w.Name), zap.String("cron", w.Cron))
+	Created(c, windowItem(w, time.Now()))
+}
+
+// UpdateMaintenanceWindow 更新维护窗口
+// PUT /api/v1/maintenance-windows/:id
+func (h *MaintenanceHandler) UpdateMaintenanceWindow(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的维护窗口ID")
+		return
+	}
+
+	var req MaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	var w model.MaintenanceWindow
+	if err := h.db.First(&w, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "维护窗口不存在")
+			return
+		}
+		h.logger.Error("查询维护窗口失败", zap.Error(err))
+		InternalError(c, "查询维护窗口失败")
+		return
+	}
+
+	before := w
+	if msg := req.apply(&w); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+
+	if err := h.db.Save(&w).Error; err != nil {
+		h.logger.Error("更新维护窗口失败", zap.Error(err))
+		InternalError(c, "更新维护窗口失败")
+		return
+	}
+
+	audit.AddTargets(c, w.Name)
+	audit.SetChange(c, before, w)
+	h.logger.Info("维护窗口已更新", zap.Uint("id", w.ID), zap.String("name", w.Name))
+	Success(c, windowItem(w, time.Now()))
+}
+
+// DeleteMaintenanceWindow 删除维护窗口
+// DELETE /api/v1/maintenance-windows/:id
+func (h *MaintenanceHandler) DeleteMaintenanceWindow(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的维护窗口ID")
+		return
+	}
+
+	var w model.MaintenanceWindow
+	if err := h.db.First(&w, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "维护窗口不存在")
+			return
+		}
+		h.logger.Error("查询维护窗口失败", zap.Error(err))
+		InternalError(c, "查询维护窗口失败")
+		return
+	}
+
+	if err := h.db.Delete(&w).Error; err != nil {
+		h.logger.Error("删除维护窗口失败", zap.Error(err))
+		InternalError(c, "删除维护窗口失败")
+		return
+	}
+
+	audit.AddTargets(c, w.Name)
+	audit.SetChange(c, w, nil)
+	h.logger.Info("维护窗口已删除", zap.Uint("id", w.ID), zap.String("name", w.Name))
+	SuccessMessage(c, "维护窗口已删除")
+}
+
+// ListChangeFreezes 获取变更冻结列表
+// GET /api/v1/change-freezes
+func (h *MaintenanceHandler) ListChangeFreezes(c *gin.Context) {
+	var freezes []model.ChangeFreeze
+	if err := h.db.Order("start_at DESC").Find(&freezes).Error; err != nil {
+		h.logger.Error("查询变更冻结列表失败", zap.Error(err))
+		InternalError(c, "查询变更冻结列表失败")
+		return
+	}
+
+	now := time.Now()
+	items := make([]ChangeFreezeItem, 0, len(freezes))
+	for _, f := range freezes {
+		items = append(items, freezeItem(f, now))
+	}
+	SuccessPaginated(c, int64(len(items)), items)
+}
+
+// freezeItem 计算变更冻结在 now 时是否生效
+func freezeItem(f model.ChangeFreeze, now time.Time) ChangeFreezeItem {
+	return ChangeFreezeItem{
+		ChangeFreeze: f,
+		Active:       f.Enabled && !f.StartAt.Time().After(now) && f.EndAt.Time().After(now),
+	}
+}
+
+// ChangeFreezeRequest 创建/更新变更冻结请求
+type ChangeFreezeRequest struct {
+	Name    string          `json:"name" binding:"required"`
+	Reason  string          `json:"reason"`
+	StartAt model.LocalTime `json:"start_at" binding:"required"`
+	EndAt   model.LocalTime `json:"end_at" binding:"required"`
+	Enabled bool            `json:"enabled"`
+}
+
+// apply 校验请求并写入变更冻结，返回错误信息
+func (r *ChangeFreezeRequest) apply(f *model.ChangeFreeze) string {
+	f.Name = strings.TrimSpace(r.Name)
+	f.Reason = r.Reason
+	f.StartAt = r.StartAt
+	f.EndAt = r.EndAt
+	f.Enabled = r.Enabled
+
+	if f.Name == "" {
+		return "名称不能为空"
+	}
+	if !f.EndAt.Time().After(f.StartAt.Time()) {
+		return "结束时间必须晚于开始时间"
+	}
+	return ""
+}
+
+// CreateChangeFreeze 创建变更冻结
+// POST /api/v1/change-freezes
+func (h *MaintenanceHandler) CreateChangeFreeze(c *gin.Context) {
+	var req ChangeFreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	f := model.ChangeFreeze{CreatedBy: currentUsername(c)}
+	if msg := req.apply(&f); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+
+	if err := h.db.Create(&f).Error; err != nil {
+		h.logger.Error("创建变更冻结失败", zap.Error(err))
+		InternalError(c, "创建变更冻结失败")
+		return
+	}
+
+	audit.AddTargets(c, f.Name)
+	audit.SetChange(c, nil, f)
+	h.logger.Info("变更冻结已创建", zap.Uint("id", f.ID), zap.String("name", f.Name))
+	Created(c, freezeItem(f, time.Now()))
+}
+
+// UpdateChangeFreeze 更新变更冻结（提前解除冻结时将 enabled 设置为 false）
+// PUT /api/v1/change-freezes/:id
+func (h *MaintenanceHandler) UpdateChangeFreeze(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的变更冻结ID")
+		return
+	}
+
+	var req ChangeFreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	var f model.ChangeFreeze
+	if err := h.db.First(&f, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "变更冻结不存在")
+			return
+		}
+		h.logger.Error("查询变更冻结失败", zap.Error(err))
+		InternalError(c, "查询变更冻结失败")
+		return
+	}
+
+	before := f
+	if msg := req.apply(&f); msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+
+	if err := h.db.Save(&f).Error; err != nil {
+		h.logger.Error("更新变更冻结失败", zap.Error(err))
+		InternalError(c, "更新变更冻结失败")
+		return
+	}
+
+	audit.AddTargets(c, f.Name)
+	audit.SetChange(c, before, f)
+	h.logger.Info("变更冻结已更新", zap.Uint("id", f.ID), zap.String("name", f.Name), zap.Bool("enabled", f.Enabled))
+	Success(c, freezeItem(f, time.Now()))
+}
+
+// DeleteChangeFreeze 删除变更冻结
+// DELETE /api/v1/change-freezes/:id
+func (h *MaintenanceHandler) DeleteChangeFreeze(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的变更冻结ID")
+		return
+	}
+
+	var f model.ChangeFreeze
+	if err := h.db.First(&f, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "变更冻结不存在")
+			return
+		}
+		h.logger.Error("查询变更冻结失败", zap.Error(err))
+		InternalError(c, "查询变更冻结失败")
+		return
+	}
+
+	if err := h.db.Delete(&f).Error; err != nil {
+		h.logger.Error("删除变更冻结失败", zap.Error(err))
+		InternalError(c, "删除变更冻结失败")
+		return
+	}
+
+	audit.AddTargets(c, f.Name)
+	audit.SetChange(c, f, nil)
+	h.logger.Info("变更冻结已删除", zap.Uint("id", f.ID), zap.String("name", f.Name))
+	SuccessMessage(c, "变更冻结已删除")
+}
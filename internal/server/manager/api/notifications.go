@@ -18,6 +18,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
@@ -183,6 +184,8 @@ func (h *NotificationsHandler) CreateNotification(c *gin.Context) {
 		})
 		return
 	}
+	audit.AddTargets(c, strconv.FormatUint(uint64(notification.ID), 10))
+	audit.SetChange(c, nil, notification)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -242,6 +245,7 @@ func (h *NotificationsHandler) UpdateNotification(c *gin.Context) {
 		return
 	}
 
+	before := notification
 	h.updateNotificationFields(&notification, &req)
 
 	if err := h.db.Save(&notification).Error; err != nil {
@@ -252,6 +256,7 @@ func (h *NotificationsHandler) UpdateNotification(c *gin.Context) {
 		})
 		return
 	}
+	audit.SetChange(c, before, notification)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -297,6 +302,7 @@ func (h *NotificationsHandler) DeleteNotification(c *gin.Context) {
 		})
 		return
 	}
+	audit.SetChange(c, notification, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
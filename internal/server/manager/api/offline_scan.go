@@ -0,0 +1,405 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/imkerbos/mxsec-platform/internal/offline"
+	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+const (
+	// offlineTaskPrefix 是离线检查导入生成的任务 ID 前缀
+	offlineTaskPrefix = "offline-"
+	// offlineResolveReason 是离线检查结果自动解决告警的原因
+	offlineResolveReason = "离线检查通过，问题已修复"
+)
+
+// errBundleImported 表示结果包已导入（任务 ID 已存在）
+var errBundleImported = errors.New("offline bundle already imported")
+
+// OfflineScanHandler 离线基线检查结果导入处理器
+type OfflineScanHandler struct {
+	db         *gorm.DB
+	logger     *zap.Logger
+	cfg        config.OfflineScanConfig
+	scoreCache *biz.BaselineScoreCache
+}
+
+// NewOfflineScanHandler 创建离线基线检查结果导入处理器
+func NewOfflineScanHandler(db *gorm.DB, logger *zap.Logger, cfg config.OfflineScanConfig, scoreCache *biz.BaselineScoreCache) *OfflineScanHandler {
+	return &OfflineScanHandler{
+		db:         db,
+		logger:     logger,
+		cfg:        cfg,
+		scoreCache: scoreCache,
+	}
+}
+
+// IssueHostKeyRequest 生成离线主机签名密钥请求
+type IssueHostKeyRequest struct {
+	HostID string `json:"host_id" binding:"required,max=64"` // 离线主机的 Agent ID（mxsec-agent --print-id 输出）
+}
+
+// IssueHostKeyResponse 离线主机签名密钥
+type IssueHostKeyResponse struct {
+	HostID string `json:"host_id"`
+	Key    string `json:"key"` // 写入离线主机 --key 指定的密钥文件
+}
+
+// IssueHostKey 生成离线主机的签名密钥（由主密钥和 Agent ID 派生，只能签名该主机的结果包）
+// POST /api/v1/results/offline-keys
+func (h *OfflineScanHandler) IssueHostKey(c *gin.Context) {
+	if h.cfg.SigningKey == "" {
+		BadRequest(c, "未配置离线检查签名密钥（offline_scan.signing_key），无法生成主机密钥")
+		return
+	}
+	var req IssueHostKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	req.HostID = strings.TrimSpace(req.HostID)
+	if req.HostID == "" {
+		BadRequest(c, "host_id 不能为空")
+		return
+	}
+	// 受业务线范围限制的用户只能为范围内已有主机生成密钥
+	if !requireHostInScope(c, h.db, req.HostID) {
+		return
+	}
+
+	h.logger.Info("已生成离线主机签名密钥",
+		zap.String("host_id", req.HostID),
+		zap.String("username", currentUsername(c)))
+	Success(c, IssueHostKeyResponse{
+		HostID: req.HostID,
+		Key:    offline.HostKey([]byte(h.cfg.SigningKey), req.HostID),
+	})
+}
+
+// hostKey 返回指定 Agent ID 的签名密钥
+func (h *OfflineScanHandler) hostKey(agentID string) []byte {
+	return []byte(offline.HostKey([]byte(h.cfg.SigningKey), agentID))
+}
+
+// ImportOfflineResults 导入离线基线检查结果包（mxsec-agent --scan 生成）
+// 以结果包声明的主机的密钥校验签名后，按一次已完成的扫描任务写入 scan_tasks、task_host_status 和 scan_results，
+// 并同步告警；主机不存在时以离线状态创建，在线主机拒绝导入，同一结果包只能导入一次
+// POST /api/v1/results/offline-import
+func (h *OfflineScanHandler) ImportOfflineResults(c *gin.Context) {
+	if h.cfg.SigningKey == "" {
+		BadRequest(c, "未配置离线检查签名密钥（offline_scan.signing_key），无法导入")
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		BadRequest(c, "请上传结果包文件")
+		return
+	}
+	defer file.Close()
+
+	maxSize := int64(h.cfg.MaxBundleSize) * 1024 * 1024
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		h.logger.Error("读取结果包失败", zap.Error(err))
+		InternalError(c, "读取结果包失败")
+		return
+	}
+	if int64(len(data)) > maxSize {
+		BadRequest(c, fmt.Sprintf("结果包超过大小限制（%d MB）", h.cfg.MaxBundleSize))
+		return
+	}
+
+	payload, err := offline.Verify(data, h.hostKey)
+	if err != nil {
+		if errors.Is(err, offline.ErrInvalidSignature) {
+			h.logger.Warn("离线检查结果包签名校验失败", zap.String("username", currentUsername(c)))
+		}
+		BadRequest(c, err.Error())
+		return
+	}
+	if len(payload.Results) == 0 {
+		BadRequest(c, "结果包中没有检查结果")
+		return
+	}
+
+	taskID := offlineTaskPrefix + payload.BundleID
+	if len(taskID) > 64 {
+		BadRequest(c, "结果包 ID 过长")
+		return
+	}
+	var count int64
+	if err := h.db.Model(&model.ScanTask{}).Where("task_id = ?", taskID).Count(&count).Error; err != nil {
+		h.logger.Error("查询结果包导入记录失败", zap.String("task_id", taskID), zap.Error(err))
+		InternalError(c, "查询结果包导入记录失败")
+		return
+	}
+	if count > 0 {
+		Conflict(c, "该结果包已导入")
+		return
+	}
+
+	hostID := payload.Host.AgentID
+	// 受业务线范围限制的用户只能导入范围内已有主机的结果（新主机未分配业务线）
+	if !requireHostInScope(c, h.db, hostID) {
+		return
+	}
+	// 在线主机的检查结果由 AgentCenter 上报，不接受离线结果覆盖
+	var online int64
+	if err := h.db.Model(&model.Host{}).
+		Where("host_id = ? AND status = ?", hostID, model.HostStatusOnline).
+		Count(&online).Error; err != nil {
+		h.logger.Error("查询主机状态失败", zap.String("host_id", hostID), zap.Error(err))
+		InternalError(c, "查询主机状态失败")
+		return
+	}
+	if online > 0 {
+		Conflict(c, "主机当前在线，请通过 AgentCenter 执行基线检查")
+		return
+	}
+	results := h.buildResults(payload, taskID)
+
+	var created, resolved int
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := h.ensureHost(tx, payload); err != nil {
+			return err
+		}
+		if err := h.createTask(tx, payload, taskID); err != nil {
+			return err
+		}
+		if err := h.saveResults(tx, hostID, results); err != nil {
+			return err
+		}
+		sync, err := service.SyncBaselineAlerts(tx, results, 200, offlineResolveReason)
+		if err != nil {
+			return err
+		}
+		created, resolved = len(sync.Created), len(sync.Resolved)
+		return nil
+	})
+	if errors.Is(err, errBundleImported) {
+		Conflict(c, "该结果包已导入")
+		return
+	}
+	if err != nil {
+		h.logger.Error("导入离线检查结果失败",
+			zap.String("host_id", hostID),
+			zap.String("bundle_id", payload.BundleID),
+			zap.Error(err))
+		InternalError(c, "导入离线检查结果失败")
+		return
+	}
+
+	h.scoreCache.InvalidateHostScore(hostID)
+
+	h.logger.Info("离线检查结果已导入",
+		zap.String("host_id", hostID),
+		zap.String("hostname", payload.Host.Hostname),
+		zap.String("task_id", taskID),
+		zap.Int("result_count", len(results)),
+		zap.Int("alerts_created", created),
+		zap.Int("alerts_resolved", resolved),
+		zap.String("username", currentUsername(c)),
+	)
+
+	SuccessWithMessage(c, "离线检查结果导入成功", gin.H{
+		"task_id":         taskID,
+		"host_id":         hostID,
+		"hostname":        payload.Host.Hostname,
+		"result_count":    len(results),
+		"alerts_created":  created,
+		"alerts_resolved": resolved,
+		"checked_at":      model.ToLocalTime(payload.FinishedAt),
+	})
+}
+
+// buildResults 将结果包中的检查结果转换为 ScanResult（同一规则只保留最后一条）
+func (h *OfflineScanHandler) buildResults(payload *offline.Payload, taskID string) []*model.ScanResult {
+	policyNames := make(map[string]string, len(payload.Policies))
+	for _, p := range payload.Policies {
+		policyNames[p.ID] = p.Name
+	}
+
+	results := make([]*model.ScanResult, 0, len(payload.Results))
+	index := make(map[string]int, len(payload.Results))
+	for _, r := range payload.Results {
+		if r.RuleID == "" {
+			continue
+		}
+		status := model.ResultStatus(r.Status)
+		switch status {
+		case model.ResultStatusPass, model.ResultStatusFail, model.ResultStatusError, model.ResultStatusNA:
+		default:
+			status = model.ResultStatusError
+		}
+		checkedAt := r.CheckedAt
+		if checkedAt.IsZero() {
+			checkedAt = payload.FinishedAt
+		}
+
+		result := &model.ScanResult{
+			ResultID:      uuid.New().String(),
+			HostID:        payload.Host.AgentID,
+			Hostname:      payload.Host.Hostname,
+			PolicyID:      r.PolicyID,
+			PolicyName:    policyNames[r.PolicyID],
+			RuleID:        r.RuleID,
+			TaskID:        taskID,
+			Status:        status,
+			Severity:      r.Severity,
+			Category:      r.Category,
+			Title:         r.Title,
+			Actual:        r.Actual,
+			Expected:      r.Expected,
+			FixSuggestion: r.FixSuggestion,
+			CheckedAt:     model.ToLocalTime(checkedAt),
+		}
+		if i, ok := index[r.RuleID]; ok {
+			results[i] = result
+			continue
+		}
+		index[r.RuleID] = len(results)
+		results = append(results, result)
+	}
+	return results
+}
+
+// ensureHost 主机不存在时以离线状态创建
+func (h *OfflineScanHandler) ensureHost(tx *gorm.DB, payload *offline.Payload) error {
+	host := model.Host{
+		HostID:        payload.Host.AgentID,
+		Hostname:      payload.Host.Hostname,
+		OSFamily:      payload.Host.OSFamily,
+		OSVersion:     payload.Host.OSVersion,
+		KernelVersion: truncate(payload.Host.Kernel, 100),
+		Arch:          payload.Host.Arch,
+		IPv4:          model.StringArray(payload.Host.IPv4),
+		Status:        model.HostStatusOffline,
+		AgentVersion:  payload.Host.AgentVersion,
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&host).Error; err != nil {
+		return fmt.Errorf("创建主机失败: %w", err)
+	}
+	return nil
+}
+
+// createTask 创建已完成的扫描任务及主机执行状态
+func (h *OfflineScanHandler) createTask(tx *gorm.DB, payload *offline.Payload, taskID string) error {
+	policyIDs := make([]string, 0, len(payload.Policies))
+	for _, p := range payload.Policies {
+		policyIDs = append(policyIDs, p.ID)
+	}
+	executedAt := model.ToLocalTime(payload.StartedAt)
+	completedAt := model.ToLocalTime(payload.FinishedAt)
+
+	// 任务记录主机所属业务线，便于受业务线范围限制的用户查看
+	businessLines, err := hostBusinessLines(tx, []string{payload.Host.AgentID})
+	if err != nil {
+		return fmt.Errorf("查询主机业务线失败: %w", err)
+	}
+
+	task := model.ScanTask{
+		TaskID:              taskID,
+		Name:                fmt.Sprintf("离线检查 - %s", payload.Host.Hostname),
+		Type:                model.TaskTypeBaselineScan,
+		TargetType:          model.TargetTypeHostIDs,
+		TargetConfig:        model.TargetConfig{HostIDs: []string{payload.Host.AgentID}, BusinessLines: businessLines},
+		PolicyIDs:           model.StringArray(policyIDs),
+		Status:              model.TaskStatusCompleted,
+		DispatchedHostCount: 1,
+		CompletedHostCount:  1,
+		ExecutedAt:          &executedAt,
+		CompletedAt:         &completedAt,
+	}
+	if len(policyIDs) > 0 {
+		task.PolicyID = policyIDs[0]
+	}
+	// 并发重放同一结果包时由 task_id 主键保证只导入一次
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&task)
+	if result.Error != nil {
+		return fmt.Errorf("创建扫描任务失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errBundleImported
+	}
+
+	var ipAddress string
+	if len(payload.Host.IPv4) > 0 {
+		ipAddress = payload.Host.IPv4[0]
+	}
+	hostStatus := model.TaskHostStatus{
+		TaskID:       taskID,
+		HostID:       payload.Host.AgentID,
+		Hostname:     payload.Host.Hostname,
+		IPAddress:    ipAddress,
+		OSFamily:     payload.Host.OSFamily,
+		OSVersion:    payload.Host.OSVersion,
+		Status:       model.TaskHostStatusCompleted,
+		DispatchedAt: &executedAt,
+		CompletedAt:  &completedAt,
+	}
+	if err := tx.Create(&hostStatus).Error; err != nil {
+		return fmt.Errorf("创建主机任务状态失败: %w", err)
+	}
+	return nil
+}
+
+// saveResults 写入检查结果：每个主机的每个规则只保留一条最新结果，已存在的结果复用原 result_id 覆盖更新
+func (h *OfflineScanHandler) saveResults(tx *gorm.DB, hostID string, results []*model.ScanResult) error {
+	ruleIDs := make([]string, 0, len(results))
+	index := make(map[string]int, len(results))
+	for i, r := range results {
+		ruleIDs = append(ruleIDs, r.RuleID)
+		index[r.RuleID] = i
+	}
+
+	var existing []model.ScanResult
+	if err := tx.Select("result_id", "rule_id").
+		Where("host_id = ? AND container_id = '' AND rule_id IN ?", hostID, ruleIDs).
+		Find(&existing).Error; err != nil {
+		return fmt.Errorf("查询检测结果失败: %w", err)
+	}
+	for _, e := range existing {
+		if i, ok := index[e.RuleID]; ok {
+			results[i].ResultID = e.ResultID
+		}
+	}
+
+	err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "result_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "actual", "expected", "checked_at", "severity", "fix_suggestion",
+			"task_id", "hostname", "policy_name",
+		}),
+	}).CreateInBatches(results, 200).Error
+	if err != nil {
+		return fmt.Errorf("保存检测结果失败: %w", err)
+	}
+	return nil
+}
+
+// truncate 截断字符串到不超过 n 字节，不拆分多字节字符
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
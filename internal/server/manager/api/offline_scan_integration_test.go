@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/offline"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestImportOfflineResults 测试离线结果导入：写入任务和结果、同步告警、拒绝重复导入和在线主机
+func TestImportOfflineResults(t *testing.T) {
+	db := testdb.Open(t, &model.Host{}, &model.ScanTask{}, &model.TaskHostStatus{}, &model.ScanResult{}, &model.Alert{})
+	h := NewOfflineScanHandler(db, zap.NewNop(), config.OfflineScanConfig{SigningKey: testSigningKey, MaxBundleSize: 20},
+		biz.NewBaselineScoreCache(db, zap.NewNop(), time.Minute))
+
+	bundle := func(agentID, bundleID, status string) []byte {
+		data, err := offline.Sign(&offline.Payload{
+			BundleID:   bundleID,
+			Host:       offline.Host{AgentID: agentID, Hostname: agentID, Kernel: "内核版本"},
+			Policies:   []offline.PolicyRef{{ID: "LINUX_SSH_BASELINE", Name: "SSH"}},
+			StartedAt:  time.Now().Add(-time.Minute),
+			FinishedAt: time.Now(),
+			Results: []offline.Result{
+				{RuleID: "LINUX_SSH_001", PolicyID: "LINUX_SSH_BASELINE", Status: status, Severity: "high", Title: "禁止 root 登录"},
+			},
+		}, []byte(offline.HostKey([]byte(testSigningKey), agentID)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	// 新主机：以离线状态创建，失败结果生成告警
+	code, resp := serveOffline(h, offlineBundleRequest(t, bundle("air-1", "b1", "fail")))
+	if code != http.StatusOK {
+		t.Fatalf("import: status = %d: %v", code, resp)
+	}
+	var host model.Host
+	if err := db.First(&host, "host_id = ?", "air-1").Error; err != nil || host.Status != model.HostStatusOffline {
+		t.Fatalf("host not created offline: %+v, %v", host, err)
+	}
+	var alert model.Alert
+	if err := db.First(&alert, "host_id = ? AND rule_id = ?", "air-1", "LINUX_SSH_001").Error; err != nil || alert.Status != model.AlertStatusActive {
+		t.Fatalf("alert not created: %+v, %v", alert, err)
+	}
+
+	// 同一结果包重复导入
+	if code, _ := serveOffline(h, offlineBundleRequest(t, bundle("air-1", "b1", "fail"))); code != http.StatusConflict {
+		t.Fatalf("duplicate bundle: status = %d, want 409", code)
+	}
+	// 并发重放越过预检查时，由事务内的任务主键拒绝
+	if err := h.createTask(db, &offline.Payload{BundleID: "b1", Host: offline.Host{AgentID: "air-1"}}, offlineTaskPrefix+"b1"); !errors.Is(err, errBundleImported) {
+		t.Fatalf("createTask for imported bundle: err = %v, want errBundleImported", err)
+	}
+
+	// 通过结果复用 result_id 并自动解决告警
+	if code, resp := serveOffline(h, offlineBundleRequest(t, bundle("air-1", "b2", "pass"))); code != http.StatusOK {
+		t.Fatalf("second import: status = %d: %v", code, resp)
+	}
+	var results int64
+	db.Model(&model.ScanResult{}).Where("host_id = ?", "air-1").Count(&results)
+	if results != 1 {
+		t.Fatalf("expected 1 result per rule, got %d", results)
+	}
+	db.First(&alert, alert.ID)
+	if alert.Status != model.AlertStatusResolved || alert.ResolveReason != offlineResolveReason {
+		t.Fatalf("alert not resolved: %+v", alert)
+	}
+
+	// 在线主机拒绝导入，不写入任何数据
+	if err := db.Create(&model.Host{HostID: "online-1", Hostname: "online-1", Status: model.HostStatusOnline}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := serveOffline(h, offlineBundleRequest(t, bundle("online-1", "b3", "fail"))); code != http.StatusConflict {
+		t.Fatalf("online host: status = %d, want 409", code)
+	}
+	var tasks int64
+	db.Model(&model.ScanTask{}).Where("task_id = ?", offlineTaskPrefix+"b3").Count(&tasks)
+	db.Model(&model.ScanResult{}).Where("host_id = ?", "online-1").Count(&results)
+	if tasks != 0 || results != 0 {
+		t.Fatalf("online host import wrote data: tasks=%d results=%d", tasks, results)
+	}
+}
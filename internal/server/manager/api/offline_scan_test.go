@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/offline"
+	"github.com/imkerbos/mxsec-platform/internal/server/config"
+)
+
+const testSigningKey = "offline-master-key-0123456789"
+
+// offlineBundleRequest 构造上传结果包的 multipart 请求
+func offlineBundleRequest(t *testing.T, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "result.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	w.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/results/offline-import", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+// signedBundle 使用指定主机的密钥签名结果包，payload 声明的主机为 claimedHost
+func signedBundle(t *testing.T, signer, claimedHost string) []byte {
+	t.Helper()
+	data, err := offline.Sign(&offline.Payload{
+		BundleID: "bundle-" + claimedHost,
+		Host:     offline.Host{AgentID: claimedHost, Hostname: claimedHost},
+		Results:  []offline.Result{{RuleID: "LINUX_SSH_001", Status: "pass"}},
+	}, []byte(offline.HostKey([]byte(testSigningKey), signer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func serveOffline(h *OfflineScanHandler, req *http.Request) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/results/offline-keys", h.IssueHostKey)
+	router.POST("/api/v1/results/offline-import", h.ImportOfflineResults)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+// TestImportOfflineResultsRejected 测试签名校验不通过的结果包在访问数据库前被拒绝
+func TestImportOfflineResultsRejected(t *testing.T) {
+	h := NewOfflineScanHandler(nil, zap.NewNop(), config.OfflineScanConfig{SigningKey: testSigningKey, MaxBundleSize: 1}, nil)
+
+	tests := []struct {
+		name    string
+		handler *OfflineScanHandler
+		data    []byte
+		want    string
+	}{
+		{"signing key not configured", NewOfflineScanHandler(nil, zap.NewNop(), config.OfflineScanConfig{MaxBundleSize: 1}, nil), signedBundle(t, "agent-1", "agent-1"), "未配置离线检查签名密钥"},
+		{"forged host id", h, signedBundle(t, "agent-1", "agent-2"), offline.ErrInvalidSignature.Error()},
+		{"signed with master key", h, func() []byte {
+			data, _ := offline.Sign(&offline.Payload{BundleID: "b", Host: offline.Host{AgentID: "agent-1"}}, []byte(testSigningKey))
+			return data
+		}(), offline.ErrInvalidSignature.Error()},
+		{"not a bundle", h, []byte("not json"), "结果包格式错误"},
+		{"too large", h, bytes.Repeat([]byte(" "), 1024*1024+1), "超过大小限制"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := serveOffline(tt.handler, offlineBundleRequest(t, tt.data))
+			if code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %v", code, resp)
+			}
+			if msg, _ := resp["message"].(string); !strings.Contains(msg, tt.want) {
+				t.Fatalf("message = %q, want containing %q", msg, tt.want)
+			}
+		})
+	}
+}
+
+// TestIssueHostKey 测试生成的主机密钥可以签名该主机的结果包
+func TestIssueHostKey(t *testing.T) {
+	h := NewOfflineScanHandler(nil, zap.NewNop(), config.OfflineScanConfig{SigningKey: testSigningKey}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/results/offline-keys", strings.NewReader(`{"host_id":" agent-1 "}`))
+	req.Header.Set("Content-Type", "application/json")
+	code, resp := serveOffline(h, req)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d: %v", code, resp)
+	}
+	data, _ := resp["data"].(map[string]interface{})
+	key, _ := data["key"].(string)
+	if data["host_id"] != "agent-1" || key != offline.HostKey([]byte(testSigningKey), "agent-1") {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/results/offline-keys", strings.NewReader(`{"host_id":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	if code, _ := serveOffline(h, req); code != http.StatusBadRequest {
+		t.Fatalf("empty host_id: status = %d, want 400", code)
+	}
+}
+
+// TestTruncate 测试截断不拆分多字节字符
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"5.14.0-362.el9.x86_64", 100, "5.14.0-362.el9.x86_64"},
+		{"  abcdef  ", 3, "abc"},
+		{"内核版本", 7, "内核"}, // 每个汉字 3 字节，第 3 个字只剩 1 字节
+		{"内核版本", 6, "内核"},
+		{"a内核", 3, "a"},
+		{"内核", 2, ""},
+		{"", 10, ""},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.in, tt.n)
+		if got != tt.want || !utf8.ValidString(got) || len(got) > tt.n {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
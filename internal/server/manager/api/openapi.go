@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/imkerbos/mxsec-platform/api/openapi"
+)
+
+// OpenAPIHandler 是 OpenAPI 文档处理器
+type OpenAPIHandler struct{}
+
+// NewOpenAPIHandler 创建 OpenAPI 文档处理器
+func NewOpenAPIHandler() *OpenAPIHandler {
+	return &OpenAPIHandler{}
+}
+
+// GetOpenAPISpec 获取 Manager API 的 OpenAPI 3 文档
+// GET /api/v1/openapi.json
+func (h *OpenAPIHandler) GetOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec)
+}
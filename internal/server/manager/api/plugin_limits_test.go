@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// TestValidatePluginLimits 测试插件资源限制的取值范围（0 表示不限制）与 capability 名称规范化
+func TestValidatePluginLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  model.PluginLimits
+		caps    []string
+		wantErr bool
+	}{
+		{"no limits", model.PluginLimits{}, nil, false},
+		{"all set", model.PluginLimits{CPUPercent: 200, MemoryMaxMB: 256, IOWeight: 500, PidsMax: 64}, nil, false},
+		{"cpu max", model.PluginLimits{CPUPercent: 12800}, nil, false},
+		{"cpu too high", model.PluginLimits{CPUPercent: 12801}, nil, true},
+		{"cpu negative", model.PluginLimits{CPUPercent: -1}, nil, true},
+		{"memory min", model.PluginLimits{MemoryMaxMB: 32}, nil, false},
+		{"memory too low", model.PluginLimits{MemoryMaxMB: 31}, nil, true},
+		{"io weight bounds", model.PluginLimits{IOWeight: 10000}, nil, false},
+		{"io weight too high", model.PluginLimits{IOWeight: 10001}, nil, true},
+		{"io weight negative", model.PluginLimits{IOWeight: -1}, nil, true},
+		{"pids min", model.PluginLimits{PidsMax: 8}, nil, false},
+		{"pids too low", model.PluginLimits{PidsMax: 7}, nil, true},
+		{"valid caps", model.PluginLimits{}, []string{"net_raw", " CAP_SYS_ADMIN "}, false},
+		{"unknown cap", model.PluginLimits{}, []string{"CAP_FLY"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &UpdatePluginLimitsRequest{Limits: tt.limits}
+			req.Sandbox.DropCapabilities = tt.caps
+			if err := validatePluginLimits(req); (err != nil) != tt.wantErr {
+				t.Fatalf("validatePluginLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	req := &UpdatePluginLimitsRequest{}
+	req.Sandbox.DropCapabilities = []string{" net_raw ", "", "all"}
+	if err := validatePluginLimits(req); err != nil {
+		t.Fatalf("validatePluginLimits() error = %v", err)
+	}
+	got := req.Sandbox.DropCapabilities
+	if len(got) != 2 || got[0] != "NET_RAW" || got[1] != "ALL" {
+		t.Errorf("normalized capabilities = %v, want [NET_RAW ALL]", got)
+	}
+}
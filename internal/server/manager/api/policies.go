@@ -2,6 +2,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -10,6 +11,9 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/approval"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -248,6 +252,8 @@ func (h *PoliciesHandler) CreatePolicy(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("查询创建的策略失败", zap.Error(err))
 	}
+	audit.AddTargets(c, policy.ID)
+	audit.SetChange(c, nil, createdPolicy)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"code": 0,
@@ -292,6 +298,8 @@ func (h *PoliciesHandler) UpdatePolicy(c *gin.Context) {
 		return
 	}
 
+	before := *policy
+
 	// 解析请求
 	var req UpdatePolicyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -375,6 +383,7 @@ func (h *PoliciesHandler) UpdatePolicy(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("查询更新的策略失败", zap.Error(err))
 	}
+	audit.SetChange(c, &before, updatedPolicy)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -388,7 +397,7 @@ func (h *PoliciesHandler) DeletePolicy(c *gin.Context) {
 	policyID := c.Param("policy_id")
 
 	// 检查策略是否存在
-	_, err := h.service.GetPolicy(policyID)
+	policy, err := h.service.GetPolicy(policyID)
 	if err != nil {
 		if strings.Contains(err.Error(), "不存在") {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -405,6 +414,23 @@ func (h *PoliciesHandler) DeletePolicy(c *gin.Context) {
 		return
 	}
 
+	// 命中审批策略时只创建审批单，批准后再删除
+	cfg, ok := loadApprovalConfig(c, h.db, h.logger)
+	if !ok {
+		return
+	}
+	if cfg.PolicyDelete {
+		submitApproval(c, h.db, h.logger, &model.ApprovalRequest{
+			Action:     approval.ActionPolicyDelete,
+			ResourceID: policyID,
+			Summary:    fmt.Sprintf("删除策略 %s（%s）", policy.Name, policyID),
+			Reason:     "删除策略需要审批",
+			Targets:    model.StringArray{policyID},
+			Permission: string(rbac.PoliciesManage),
+		})
+		return
+	}
+
 	// 删除策略（会级联删除规则）
 	if err := h.service.DeletePolicy(policyID); err != nil {
 		h.logger.Error("删除策略失败", zap.Error(err))
@@ -414,6 +440,7 @@ func (h *PoliciesHandler) DeletePolicy(c *gin.Context) {
 		})
 		return
 	}
+	audit.SetChange(c, policy, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -452,7 +479,7 @@ func (h *PoliciesHandler) GetPolicyStatistics(c *gin.Context) {
 
 	// 查询该策略的检查结果（仅包含当前存在的规则）
 	var results []model.ScanResult
-	query := h.db.Where("policy_id = ?", policyID)
+	query := scopeByHost(c, h.db, h.db.Where("policy_id = ?", policyID), "host_id")
 	if len(currentRuleIDs) > 0 {
 		query = query.Where("rule_id IN ?", currentRuleIDs)
 	}
@@ -566,6 +593,7 @@ func (h *PoliciesHandler) BatchEnableDisable(c *gin.Context) {
 		BadRequest(c, "参数错误")
 		return
 	}
+	audit.AddTargets(c, req.PolicyIDs...)
 
 	if err := h.db.Model(&model.Policy{}).Where("id IN ?", req.PolicyIDs).Update("enabled", req.Enabled).Error; err != nil {
 		h.logger.Error("批量更新策略状态失败", zap.Error(err))
@@ -586,18 +614,24 @@ func (h *PoliciesHandler) BatchDelete(c *gin.Context) {
 		BadRequest(c, "参数错误")
 		return
 	}
+	audit.AddTargets(c, req.PolicyIDs...)
 
-	err := h.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("policy_id IN ?", req.PolicyIDs).Delete(&model.Rule{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("id IN ?", req.PolicyIDs).Delete(&model.Policy{}).Error; err != nil {
-			return err
-		}
-		return nil
-	})
+	cfg, ok := loadApprovalConfig(c, h.db, h.logger)
+	if !ok {
+		return
+	}
+	if cfg.PolicyDelete {
+		submitApproval(c, h.db, h.logger, &model.ApprovalRequest{
+			Action:     approval.ActionPolicyDelete,
+			Summary:    fmt.Sprintf("批量删除 %d 个策略", len(req.PolicyIDs)),
+			Reason:     "删除策略需要审批",
+			Targets:    model.StringArray(req.PolicyIDs),
+			Permission: string(rbac.PoliciesManage),
+		})
+		return
+	}
 
-	if err != nil {
+	if err := deletePolicies(h.db, req.PolicyIDs); err != nil {
 		h.logger.Error("批量删除策略失败", zap.Error(err))
 		InternalError(c, "批量删除策略失败")
 		return
@@ -606,6 +640,16 @@ func (h *PoliciesHandler) BatchDelete(c *gin.Context) {
 	Success(c, gin.H{"deleted": len(req.PolicyIDs)})
 }
 
+// deletePolicies 在事务中删除策略及其规则
+func deletePolicies(db *gorm.DB, policyIDs []string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("policy_id IN ?", policyIDs).Delete(&model.Rule{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", policyIDs).Delete(&model.Policy{}).Error
+	})
+}
+
 // BatchExport 批量导出策略
 func (h *PoliciesHandler) BatchExport(c *gin.Context) {
 	var req struct {
@@ -75,11 +75,11 @@ func (h *PolicyGroupsHandler) ListPolicyGroups(c *gin.Context) {
 
 		// 获取通过率和主机数（基于最近检查结果）
 		var totalResults, passResults int64
-		h.db.Model(&model.ScanResult{}).
+		scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 			Joins("JOIN policies ON scan_results.policy_id = policies.id").
 			Where("policies.group_id = ?", group.ID).
 			Count(&totalResults)
-		h.db.Model(&model.ScanResult{}).
+		scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 			Joins("JOIN policies ON scan_results.policy_id = policies.id").
 			Where("policies.group_id = ? AND scan_results.status = ?", group.ID, model.ResultStatusPass).
 			Count(&passResults)
@@ -90,7 +90,7 @@ func (h *PolicyGroupsHandler) ListPolicyGroups(c *gin.Context) {
 
 		// 获取检查的主机数
 		var hostCount int64
-		h.db.Model(&model.ScanResult{}).
+		scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 			Select("COUNT(DISTINCT host_id)").
 			Joins("JOIN policies ON scan_results.policy_id = policies.id").
 			Where("policies.group_id = ?", group.ID).
@@ -383,15 +383,15 @@ func (h *PolicyGroupsHandler) GetPolicyGroupStatistics(c *gin.Context) {
 
 	// 获取检查结果统计
 	var totalResults, passResults, failResults int64
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 		Joins("JOIN policies ON scan_results.policy_id = policies.id").
 		Where("policies.group_id = ?", id).
 		Count(&totalResults)
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 		Joins("JOIN policies ON scan_results.policy_id = policies.id").
 		Where("policies.group_id = ? AND scan_results.status = ?", id, model.ResultStatusPass).
 		Count(&passResults)
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 		Joins("JOIN policies ON scan_results.policy_id = policies.id").
 		Where("policies.group_id = ? AND scan_results.status = ?", id, model.ResultStatusFail).
 		Count(&failResults)
@@ -404,7 +404,7 @@ func (h *PolicyGroupsHandler) GetPolicyGroupStatistics(c *gin.Context) {
 
 	// 获取检查的主机数
 	var hostCount int64
-	h.db.Model(&model.ScanResult{}).
+	scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 		Select("COUNT(DISTINCT host_id)").
 		Joins("JOIN policies ON scan_results.policy_id = policies.id").
 		Where("policies.group_id = ?", id).
@@ -413,7 +413,7 @@ func (h *PolicyGroupsHandler) GetPolicyGroupStatistics(c *gin.Context) {
 	// 获取最近检查时间
 	var lastCheckTime *model.LocalTime
 	var lastResult model.ScanResult
-	if err := h.db.Model(&model.ScanResult{}).
+	if err := scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "scan_results.host_id").
 		Joins("JOIN policies ON scan_results.policy_id = policies.id").
 		Where("policies.group_id = ?", id).
 		Order("checked_at DESC").
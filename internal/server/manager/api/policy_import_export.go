@@ -386,15 +386,3 @@ func (h *PolicyImportExportHandler) updatePolicy(existing *model.Policy, data *P
 		return nil
 	})
 }
-
-// RegisterPolicyImportExportRoutes 注册策略导入导出路由
-func RegisterPolicyImportExportRoutes(r *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
-	handler := NewPolicyImportExportHandler(db, logger)
-
-	// 导出路由
-	r.GET("/policies/export", handler.ExportAllPolicies)
-	r.GET("/policies/:policy_id/export", handler.ExportPolicy)
-
-	// 导入路由
-	r.POST("/policies/import", handler.ImportPolicy)
-}
@@ -74,16 +74,16 @@ func (h *ReportsHandler) GetStats(c *gin.Context) {
 		Offline int64
 	}
 
-	h.db.Model(&model.Host{}).Count(&hostStats.Total)
-	h.db.Model(&model.Host{}).Where("status = ?", "online").Count(&hostStats.Online)
-	h.db.Model(&model.Host{}).Where("status = ?", "offline").Count(&hostStats.Offline)
+	h.hosts(c).Count(&hostStats.Total)
+	h.hosts(c).Where("status = ?", "online").Count(&hostStats.Online)
+	h.hosts(c).Where("status = ?", "offline").Count(&hostStats.Offline)
 
 	// 按操作系统统计
 	var osFamilyStats []struct {
 		OSFamily string
 		Count    int64
 	}
-	h.db.Model(&model.Host{}).
+	h.hosts(c).
 		Select("os_family, COUNT(*) as count").
 		Group("os_family").
 		Find(&osFamilyStats)
@@ -103,7 +103,7 @@ func (h *ReportsHandler) GetStats(c *gin.Context) {
 		Warning     int64
 	}
 
-	baselineQuery := h.db.Model(&model.ScanResult{}).
+	baselineQuery := h.results(c).
 		Where("checked_at >= ? AND checked_at <= ?", startTime, endTime)
 
 	baselineQuery.Count(&baselineStats.TotalChecks)
@@ -116,7 +116,7 @@ func (h *ReportsHandler) GetStats(c *gin.Context) {
 		Severity string
 		Count    int64
 	}
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Select("severity, COUNT(*) as count").
 		Where("checked_at >= ? AND checked_at <= ? AND status = ?", startTime, endTime, "fail").
 		Group("severity").
@@ -139,7 +139,7 @@ func (h *ReportsHandler) GetStats(c *gin.Context) {
 		Category string
 		Count    int64
 	}
-	h.db.Model(&model.ScanResult{}).
+	h.results(c).
 		Select("category, COUNT(*) as count").
 		Where("checked_at >= ? AND checked_at <= ? AND status = ?", startTime, endTime, "fail").
 		Group("category").
@@ -177,10 +177,10 @@ func (h *ReportsHandler) GetStats(c *gin.Context) {
 		Failed    int64
 	}
 
-	h.db.Model(&model.ScanTask{}).Count(&taskStats.Total)
-	h.db.Model(&model.ScanTask{}).Where("status = ?", "completed").Count(&taskStats.Completed)
-	h.db.Model(&model.ScanTask{}).Where("status = ?", "running").Count(&taskStats.Running)
-	h.db.Model(&model.ScanTask{}).Where("status = ?", "failed").Count(&taskStats.Failed)
+	h.tasks(c).Count(&taskStats.Total)
+	h.tasks(c).Where("status = ?", "completed").Count(&taskStats.Completed)
+	h.tasks(c).Where("status = ?", "running").Count(&taskStats.Running)
+	h.tasks(c).Where("status = ?", "failed").Count(&taskStats.Failed)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -278,7 +278,7 @@ func (h *ReportsHandler) GetBaselineScoreTrend(c *gin.Context) {
 	}
 
 	// 构建查询
-	query := h.db.Model(&model.ScanResult{}).
+	query := h.results(c).
 		Where("checked_at >= ? AND checked_at <= ?", startTime, endTime)
 
 	if hostID != "" {
@@ -329,6 +329,9 @@ func (h *ReportsHandler) GetBaselineScoreTrend(c *gin.Context) {
 		rawSQL += " AND policy_id = ?"
 		args = append(args, policyID)
 	}
+	scopeCond, scopeArgs := scopeSQL(c, "host_id")
+	rawSQL += scopeCond
+	args = append(args, scopeArgs...)
 
 	rawSQL += " GROUP BY date ORDER BY date"
 
@@ -517,6 +520,10 @@ func (h *ReportsHandler) GetTaskReport(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	// 2. 获取策略信息
 	var policy model.Policy
 	policyName := ""
@@ -933,7 +940,7 @@ func (h *ReportsHandler) GetCheckResultTrend(c *gin.Context) {
 	}
 
 	// 构建查询
-	query := h.db.Model(&model.ScanResult{}).
+	query := h.results(c).
 		Where("checked_at >= ? AND checked_at <= ?", startTime, endTime)
 
 	if hostID != "" {
@@ -972,6 +979,9 @@ func (h *ReportsHandler) GetCheckResultTrend(c *gin.Context) {
 		rawSQL += " AND policy_id = ?"
 		args = append(args, policyID)
 	}
+	scopeCond, scopeArgs := scopeSQL(c, "host_id")
+	rawSQL += scopeCond
+	args = append(args, scopeArgs...)
 
 	rawSQL += " GROUP BY date ORDER BY date"
 
@@ -1280,6 +1290,7 @@ func (h *ReportsHandler) GetTopFailedRules(c *gin.Context) {
 	}
 	var ruleStats []ruleStatRow
 
+	scopeCond, scopeArgs := scopeSQL(c, "host_id")
 	h.db.Raw(`
 		SELECT
 			rule_id,
@@ -1288,7 +1299,7 @@ func (h *ReportsHandler) GetTopFailedRules(c *gin.Context) {
 			category,
 			COUNT(DISTINCT host_id) as affected_hosts
 		FROM scan_results
-		WHERE status = 'fail'
+		WHERE status = 'fail'`+scopeCond+`
 		GROUP BY rule_id, title, severity, category
 		ORDER BY
 			CASE severity
@@ -1300,7 +1311,7 @@ func (h *ReportsHandler) GetTopFailedRules(c *gin.Context) {
 			END,
 			affected_hosts DESC
 		LIMIT ?
-	`, limit).Scan(&ruleStats)
+	`, append(scopeArgs, limit)...).Scan(&ruleStats)
 
 	topRules := make([]TopFailedRule, 0, len(ruleStats))
 	for _, rs := range ruleStats {
@@ -1343,20 +1354,18 @@ func (h *ReportsHandler) GetTopRiskHosts(c *gin.Context) {
 	}
 	var hostStats []hostStatRow
 
-	h.db.Raw(`
-		SELECT
-			host_id,
+	h.results(c).
+		Select(`host_id,
 			SUM(CASE WHEN status = 'fail' THEN 1 ELSE 0 END) as fail_count,
 			SUM(CASE WHEN status = 'fail' AND severity = 'critical' THEN 1 ELSE 0 END) as critical_count,
 			SUM(CASE WHEN status = 'fail' AND severity = 'high' THEN 1 ELSE 0 END) as high_count,
 			COUNT(*) as total_checks,
-			SUM(CASE WHEN status = 'pass' THEN 1 ELSE 0 END) as passed_checks
-		FROM scan_results
-		GROUP BY host_id
-		HAVING fail_count > 0
-		ORDER BY critical_count DESC, high_count DESC, fail_count DESC
-		LIMIT ?
-	`, limit).Scan(&hostStats)
+			SUM(CASE WHEN status = 'pass' THEN 1 ELSE 0 END) as passed_checks`).
+		Group("host_id").
+		Having("fail_count > 0").
+		Order("critical_count DESC, high_count DESC, fail_count DESC").
+		Limit(limit).
+		Scan(&hostStats)
 
 	// 获取主机信息
 	hostIDs := make([]string, 0, len(hostStats))
@@ -1429,6 +1438,10 @@ func (h *ReportsHandler) GetExecutiveTaskReport(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	// 2. 获取策略信息（支持多策略）
 	policyIDs := task.GetPolicyIDs()
 	var policyNames []string
@@ -2017,3 +2030,18 @@ func (h *ReportsHandler) GetExecutiveTaskReport(c *gin.Context) {
 		"data": report,
 	})
 }
+
+// hosts 返回限制在当前用户业务线范围内的主机查询
+func (h *ReportsHandler) hosts(c *gin.Context) *gorm.DB {
+	return scopeHosts(c, h.db.Model(&model.Host{}), "business_line")
+}
+
+// results 返回限制在当前用户业务线范围内的检查结果查询
+func (h *ReportsHandler) results(c *gin.Context) *gorm.DB {
+	return scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id")
+}
+
+// tasks 返回限制在当前用户业务线范围内的扫描任务查询
+func (h *ReportsHandler) tasks(c *gin.Context) *gorm.DB {
+	return scopeByBusinessLines(c, h.db.Model(&model.ScanTask{}), "target_config", "$.business_lines")
+}
@@ -42,9 +42,11 @@ func (h *ResultsHandler) ListResults(c *gin.Context) {
 	taskID := c.Query("task_id")
 	status := c.Query("status")
 	severity := c.Query("severity")
+	containerID := c.Query("container_id")
+	scope := c.Query("scope") // host: 仅宿主机结果；container: 仅容器内检查结果
 
 	// 构建查询
-	query := h.db.Model(&model.ScanResult{})
+	query := scopeByHost(c, h.db, h.db.Model(&model.ScanResult{}), "host_id")
 
 	// 过滤条件
 	if hostID != "" {
@@ -65,6 +67,15 @@ func (h *ResultsHandler) ListResults(c *gin.Context) {
 	if severity != "" {
 		query = query.Where("severity = ?", severity)
 	}
+	if containerID != "" {
+		query = query.Where("container_id = ?", containerID)
+	}
+	switch scope {
+	case "host":
+		query = query.Where("container_id = ''")
+	case "container":
+		query = query.Where("container_id <> ''")
+	}
 
 	// 计算总数
 	var total int64
@@ -104,7 +115,8 @@ func (h *ResultsHandler) GetResult(c *gin.Context) {
 	resultID := c.Param("result_id")
 
 	var result model.ScanResult
-	if err := h.db.Where("result_id = ?", resultID).Preload("Host").Preload("Rule").First(&result).Error; err != nil {
+	query := scopeByHost(c, h.db, h.db.Where("result_id = ?", resultID), "host_id")
+	if err := query.Preload("Host").Preload("Rule").First(&result).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"code":    404,
@@ -141,14 +153,14 @@ func (h *ResultsHandler) GetHostBaselineScore(c *gin.Context) {
 	// 使用子查询获取每个规则的最新结果（过滤已删除的规则）
 	subQuery := h.db.Model(&model.ScanResult{}).
 		Select("rule_id, MAX(checked_at) as max_checked_at").
-		Where("host_id = ?", hostID).
+		Where("host_id = ? AND container_id = ''", hostID).
 		Group("rule_id")
 
 	if err := h.db.Table("scan_results").
 		Select("scan_results.rule_id, scan_results.status, scan_results.severity").
 		Joins("INNER JOIN (?) AS latest ON scan_results.rule_id = latest.rule_id AND scan_results.checked_at = latest.max_checked_at", subQuery).
 		Joins("INNER JOIN rules ON scan_results.rule_id = rules.rule_id").
-		Where("scan_results.host_id = ?", hostID).
+		Where("scan_results.host_id = ? AND scan_results.container_id = ''", hostID).
 		Find(&latestResults).Error; err != nil {
 		h.logger.Error("查询主机基线得分失败", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -254,14 +266,14 @@ func (h *ResultsHandler) GetHostBaselineSummary(c *gin.Context) {
 
 	subQuery := h.db.Model(&model.ScanResult{}).
 		Select("rule_id, MAX(checked_at) as max_checked_at").
-		Where("host_id = ?", hostID).
+		Where("host_id = ? AND container_id = ''", hostID).
 		Group("rule_id")
 
 	if err := h.db.Table("scan_results").
 		Select("scan_results.rule_id, scan_results.status, scan_results.severity, scan_results.category").
 		Joins("INNER JOIN (?) AS latest ON scan_results.rule_id = latest.rule_id AND scan_results.checked_at = latest.max_checked_at", subQuery).
 		Joins("INNER JOIN rules ON scan_results.rule_id = rules.rule_id").
-		Where("scan_results.host_id = ?", hostID).
+		Where("scan_results.host_id = ? AND scan_results.container_id = ''", hostID).
 		Find(&latestResults).Error; err != nil {
 		h.logger.Error("查询主机基线摘要失败", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -308,6 +320,54 @@ func (h *ResultsHandler) GetHostBaselineSummary(c *gin.Context) {
 	})
 }
 
+// GetHostContainerResults 获取主机上各容器的基线检查汇总（容器视图）
+// 容器结果由宿主机 Agent 进入容器检查产生，无需在容器内部署 Agent
+// GET /api/v1/results/host/:host_id/containers
+func (h *ResultsHandler) GetHostContainerResults(c *gin.Context) {
+	hostID := c.Param("host_id")
+
+	var items []struct {
+		ContainerID    string    `json:"container_id"`
+		ContainerName  string    `json:"container_name"`
+		ContainerImage string    `json:"container_image"`
+		TotalRules     int       `json:"total_rules"`
+		PassCount      int       `json:"pass_count"`
+		FailCount      int       `json:"fail_count"`
+		ErrorCount     int       `json:"error_count"`
+		NACount        int       `json:"na_count"`
+		LastCheckedAt  time.Time `json:"last_checked_at"`
+	}
+
+	if err := h.db.Model(&model.ScanResult{}).
+		Select(`container_id, MAX(container_name) AS container_name, MAX(container_image) AS container_image,
+			COUNT(*) AS total_rules,
+			SUM(CASE WHEN status = 'pass' THEN 1 ELSE 0 END) AS pass_count,
+			SUM(CASE WHEN status = 'fail' THEN 1 ELSE 0 END) AS fail_count,
+			SUM(CASE WHEN status = 'error' THEN 1 ELSE 0 END) AS error_count,
+			SUM(CASE WHEN status = 'na' THEN 1 ELSE 0 END) AS na_count,
+			MAX(checked_at) AS last_checked_at`).
+		Where("host_id = ? AND container_id <> ''", hostID).
+		Group("container_id").
+		Order("fail_count DESC").
+		Scan(&items).Error; err != nil {
+		h.logger.Error("查询容器检查结果失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "查询容器检查结果失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"host_id": hostID,
+			"total":   len(items),
+			"items":   items,
+		},
+	})
+}
+
 // ExportHostBaselineResults 导出主机基线检查结果
 // GET /api/v1/results/host/:host_id/export?format=markdown|excel
 func (h *ResultsHandler) ExportHostBaselineResults(c *gin.Context) {
@@ -336,13 +396,13 @@ func (h *ResultsHandler) ExportHostBaselineResults(c *gin.Context) {
 	var results []model.ScanResult
 	subQuery := h.db.Model(&model.ScanResult{}).
 		Select("rule_id, MAX(checked_at) as max_checked_at").
-		Where("host_id = ?", hostID).
+		Where("host_id = ? AND container_id = ''", hostID).
 		Group("rule_id")
 
 	if err := h.db.Table("scan_results").
 		Select("scan_results.*").
 		Joins("INNER JOIN (?) AS latest ON scan_results.rule_id = latest.rule_id AND scan_results.checked_at = latest.max_checked_at", subQuery).
-		Where("scan_results.host_id = ? AND scan_results.status = ?", hostID, "fail").
+		Where("scan_results.host_id = ? AND scan_results.container_id = '' AND scan_results.status = ?", hostID, "fail").
 		Order("scan_results.severity DESC, scan_results.category ASC").
 		Find(&results).Error; err != nil {
 		h.logger.Error("查询基线检查结果失败", zap.Error(err))
@@ -0,0 +1,296 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// roleNamePattern 是自定义角色名称格式
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,63}$`)
+
+// RolesHandler 是角色管理 API 处理器
+type RolesHandler struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewRolesHandler 创建角色管理处理器
+func NewRolesHandler(db *gorm.DB, logger *zap.Logger) *RolesHandler {
+	return &RolesHandler{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// RoleItem 角色列表项（内置角色 ID 为 0）
+type RoleItem struct {
+	ID            uint              `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	Permissions   []rbac.Permission `json:"permissions"`
+	BusinessLines []string          `json:"business_lines"`
+	Builtin       bool              `json:"builtin"`
+	UserCount     int64             `json:"user_count"`
+}
+
+// CreateRoleRequest 创建角色请求
+type CreateRoleRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Description   string   `json:"description" binding:"max=255"`
+	Permissions   []string `json:"permissions"`
+	BusinessLines []string `json:"business_lines"` // 业务线范围（业务线代码），为空表示不限制
+}
+
+// UpdateRoleRequest 更新角色请求（角色名称不可修改）
+type UpdateRoleRequest struct {
+	Description   string   `json:"description" binding:"max=255"`
+	Permissions   []string `json:"permissions"`
+	BusinessLines []string `json:"business_lines"` // 业务线范围（业务线代码），为空表示不限制
+}
+
+// ListPermissions 获取全部权限定义
+// GET /api/v1/permissions
+func (h *RolesHandler) ListPermissions(c *gin.Context) {
+	Success(c, rbac.AllPermissions)
+}
+
+// ListRoles 获取角色列表（内置角色在前）
+// GET /api/v1/roles
+func (h *RolesHandler) ListRoles(c *gin.Context) {
+	var roles []model.Role
+	if err := h.db.Order("name ASC").Find(&roles).Error; err != nil {
+		h.logger.Error("查询角色列表失败", zap.Error(err))
+		InternalError(c, "查询角色列表失败")
+		return
+	}
+
+	userCounts := h.userCounts()
+	items := make([]RoleItem, 0, len(rbac.BuiltinRoles)+len(roles))
+	for _, r := range rbac.BuiltinRoles {
+		items = append(items, RoleItem{
+			Name:          r.Name,
+			Description:   r.Description,
+			Permissions:   r.Permissions,
+			BusinessLines: []string{},
+			Builtin:       true,
+			UserCount:     userCounts[r.Name],
+		})
+	}
+	for _, r := range roles {
+		perms := make([]rbac.Permission, 0, len(r.Permissions))
+		for _, p := range r.Permissions {
+			perms = append(perms, rbac.Permission(p))
+		}
+		items = append(items, RoleItem{
+			ID:            r.ID,
+			Name:          r.Name,
+			Description:   r.Description,
+			Permissions:   perms,
+			BusinessLines: r.BusinessLines,
+			UserCount:     userCounts[r.Name],
+		})
+	}
+
+	SuccessPaginated(c, int64(len(items)), items)
+}
+
+// CreateRole 创建自定义角色
+// POST /api/v1/roles
+func (h *RolesHandler) CreateRole(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	if !roleNamePattern.MatchString(req.Name) {
+		BadRequest(c, "角色名称只能包含小写字母、数字、下划线和连字符，且以字母开头（2-64 个字符）")
+		return
+	}
+	if rbac.IsBuiltin(req.Name) {
+		Conflict(c, "不能使用内置角色名称")
+		return
+	}
+	perms, msg := validatePermissions(req.Permissions)
+	if msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+	if !checkGrantable(c, perms) {
+		return
+	}
+	businessLines, ok := checkBusinessLines(c, h.db, req.BusinessLines)
+	if !ok {
+		return
+	}
+
+	var count int64
+	h.db.Model(&model.Role{}).Where("name = ?", req.Name).Count(&count)
+	if count > 0 {
+		Conflict(c, "角色名称已存在")
+		return
+	}
+
+	role := model.Role{
+		Name:          req.Name,
+		Description:   req.Description,
+		Permissions:   perms,
+		BusinessLines: businessLines,
+	}
+	if err := h.db.Create(&role).Error; err != nil {
+		h.logger.Error("创建角色失败", zap.Error(err))
+		InternalError(c, "创建角色失败")
+		return
+	}
+
+	audit.AddTargets(c, role.Name)
+	audit.SetChange(c, nil, role)
+	h.logger.Info("角色已创建", zap.String("name", role.Name), zap.Strings("permissions", []string(role.Permissions)))
+	Created(c, role)
+}
+
+// UpdateRole 更新自定义角色的描述、权限和业务线范围（内置角色不可修改）
+// PUT /api/v1/roles/:id
+func (h *RolesHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的角色ID")
+		return
+	}
+
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	perms, msg := validatePermissions(req.Permissions)
+	if msg != "" {
+		BadRequest(c, msg)
+		return
+	}
+	if !checkGrantable(c, perms) {
+		return
+	}
+	businessLines, ok := checkBusinessLines(c, h.db, req.BusinessLines)
+	if !ok {
+		return
+	}
+
+	var role model.Role
+	if err := h.db.First(&role, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "角色不存在")
+			return
+		}
+		h.logger.Error("查询角色失败", zap.Error(err))
+		InternalError(c, "查询角色失败")
+		return
+	}
+
+	before := role
+	role.Description = req.Description
+	role.Permissions = perms
+	role.BusinessLines = businessLines
+	if err := h.db.Save(&role).Error; err != nil {
+		h.logger.Error("更新角色失败", zap.Error(err))
+		InternalError(c, "更新角色失败")
+		return
+	}
+
+	audit.AddTargets(c, role.Name)
+	audit.SetChange(c, before, role)
+	h.logger.Info("角色已更新", zap.String("name", role.Name), zap.Strings("permissions", []string(role.Permissions)))
+	Success(c, role)
+}
+
+// DeleteRole 删除自定义角色（仍有用户使用时不可删除）
+// DELETE /api/v1/roles/:id
+func (h *RolesHandler) DeleteRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的角色ID")
+		return
+	}
+
+	var role model.Role
+	if err := h.db.First(&role, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "角色不存在")
+			return
+		}
+		h.logger.Error("查询角色失败", zap.Error(err))
+		InternalError(c, "查询角色失败")
+		return
+	}
+
+	var count int64
+	h.db.Model(&model.User{}).Where("role = ?", role.Name).Count(&count)
+	if count > 0 {
+		Conflict(c, "仍有 "+strconv.FormatInt(count, 10)+" 个用户使用该角色，请先修改这些用户的角色")
+		return
+	}
+
+	if err := h.db.Delete(&role).Error; err != nil {
+		h.logger.Error("删除角色失败", zap.Error(err))
+		InternalError(c, "删除角色失败")
+		return
+	}
+
+	audit.AddTargets(c, role.Name)
+	audit.SetChange(c, role, nil)
+	h.logger.Info("角色已删除", zap.String("name", role.Name))
+	SuccessMessage(c, "删除成功")
+}
+
+// userCounts 统计各角色的用户数
+func (h *RolesHandler) userCounts() map[string]int64 {
+	var rows []struct {
+		Role  string
+		Count int64
+	}
+	if err := h.db.Model(&model.User{}).Select("role, COUNT(*) AS count").Group("role").Scan(&rows).Error; err != nil {
+		h.logger.Warn("统计角色用户数失败", zap.Error(err))
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Role] = row.Count
+	}
+	return counts
+}
+
+// checkGrantable 校验角色权限不超出操作者自身的权限，避免通过修改角色提升权限，否则返回 403 并返回 false
+func checkGrantable(c *gin.Context, perms model.StringArray) bool {
+	required := make([]rbac.Permission, 0, len(perms))
+	for _, p := range perms {
+		required = append(required, rbac.Permission(p))
+	}
+	if missing := rbac.Missing(currentPermissions(c), required); len(missing) > 0 {
+		Forbidden(c, "不能授予超出自身权限的权限: "+string(missing[0]))
+		return false
+	}
+	return true
+}
+
+// validatePermissions 校验并去重权限标识，返回错误信息
+func validatePermissions(perms []string) (model.StringArray, string) {
+	result := make(model.StringArray, 0, len(perms))
+	seen := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		if !rbac.IsValid(rbac.Permission(p)) {
+			return nil, "未知的权限: " + p
+		}
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result, ""
+}
@@ -10,6 +10,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -187,6 +188,8 @@ func (h *RulesHandler) CreateRule(c *gin.Context) {
 
 	// 重新查询规则
 	createdRule, _ := h.service.GetRule(req.RuleID)
+	audit.AddTargets(c, req.RuleID)
+	audit.SetChange(c, nil, createdRule)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"code": 0,
@@ -228,6 +231,8 @@ func (h *RulesHandler) UpdateRule(c *gin.Context) {
 		return
 	}
 
+	before := *rule
+
 	var req UpdateRuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -271,6 +276,7 @@ func (h *RulesHandler) UpdateRule(c *gin.Context) {
 
 	// 重新查询规则
 	updatedRule, _ := h.service.GetRule(ruleID)
+	audit.SetChange(c, &before, updatedRule)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -284,7 +290,7 @@ func (h *RulesHandler) DeleteRule(c *gin.Context) {
 	ruleID := c.Param("rule_id")
 
 	// 检查规则是否存在
-	_, err := h.service.GetRule(ruleID)
+	rule, err := h.service.GetRule(ruleID)
 	if err != nil {
 		if strings.Contains(err.Error(), "不存在") {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -309,6 +315,7 @@ func (h *RulesHandler) DeleteRule(c *gin.Context) {
 		})
 		return
 	}
+	audit.SetChange(c, rule, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -0,0 +1,133 @@
+// Package api 提供 HTTP API 处理器
+package api
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// 业务线范围（数据隔离）
+//
+// 用户绑定业务线后只能访问这些业务线的主机及其数据（检查结果、告警、资产、FIM 事件等），
+// 发起的扫描/修复/FIM 任务和 Agent 重启也只作用于范围内的主机，越权访问返回 403。
+// 不属于任何业务线的主机只对不限制范围的用户可见。
+
+// scopeOf 返回当前用户的业务线范围（认证中间件设置，未设置时不限制）
+func scopeOf(c *gin.Context) rbac.Scope {
+	if v, ok := c.Get(rbac.ScopeContextKey); ok {
+		if scope, ok := v.(rbac.Scope); ok {
+			return scope
+		}
+	}
+	return rbac.Scope{}
+}
+
+// scopeHosts 将 hosts 表查询限制在当前用户的业务线范围内
+// column 为业务线列名（联表查询时需带表名，如 "hosts.business_line"）
+func scopeHosts(c *gin.Context, query *gorm.DB, column string) *gorm.DB {
+	scope := scopeOf(c)
+	if scope.Unrestricted() {
+		return query
+	}
+	return query.Where(column+" IN ?", scope.BusinessLines)
+}
+
+// scopeByHost 将按主机关联的数据查询（检查结果、告警、资产等）限制在当前用户的业务线范围内
+// column 为主机 ID 列名（联表查询时需带表名，如 "scan_results.host_id"）
+func scopeByHost(c *gin.Context, db *gorm.DB, query *gorm.DB, column string) *gorm.DB {
+	scope := scopeOf(c)
+	if scope.Unrestricted() {
+		return query
+	}
+	return query.Where(column+" IN (?)", scope.HostIDs(db))
+}
+
+// scopeSQL 返回原生 SQL 的业务线范围条件（以 " AND " 开头）和参数，不限制时返回空
+func scopeSQL(c *gin.Context, column string) (string, []interface{}) {
+	scope := scopeOf(c)
+	if scope.Unrestricted() {
+		return "", nil
+	}
+	return " AND " + column + " IN (SELECT host_id FROM hosts WHERE business_line IN ?)", []interface{}{scope.BusinessLines}
+}
+
+// scopeByBusinessLines 将记录了业务线列表（JSON 数组）的任务查询限制在当前用户的业务线范围内
+// 只有业务线列表非空且全部在范围内的记录可见；path 为 JSON 路径（列本身为数组时使用 "$"）
+func scopeByBusinessLines(c *gin.Context, query *gorm.DB, column, path string) *gorm.DB {
+	scope := scopeOf(c)
+	if scope.Unrestricted() {
+		return query
+	}
+	lines, _ := json.Marshal(scope.BusinessLines)
+	return query.Where("JSON_LENGTH("+column+", ?) > 0 AND JSON_CONTAINS(?, JSON_EXTRACT("+column+", ?))",
+		path, string(lines), path)
+}
+
+// requireHostInScope 校验主机在当前用户的业务线范围内，不在范围内（或不存在）时返回 403
+func requireHostInScope(c *gin.Context, db *gorm.DB, hostID string) bool {
+	return requireHostsInScope(c, db, []string{hostID})
+}
+
+// requireHostsInScope 校验主机全部在当前用户的业务线范围内，否则返回 403
+func requireHostsInScope(c *gin.Context, db *gorm.DB, hostIDs []string) bool {
+	ok, err := scopeOf(c).HostsInScope(db, hostIDs)
+	if err != nil {
+		InternalError(c, "校验业务线范围失败")
+		return false
+	}
+	if !ok {
+		Forbidden(c, "无权访问业务线范围外的主机")
+		return false
+	}
+	return true
+}
+
+// requireBusinessLinesInScope 校验任务记录的业务线全部在当前用户的范围内，否则返回 403
+func requireBusinessLinesInScope(c *gin.Context, businessLines []string) bool {
+	if scopeOf(c).ContainsAll(businessLines) {
+		return true
+	}
+	Forbidden(c, "无权访问业务线范围外的任务")
+	return false
+}
+
+// hostBusinessLines 返回主机所属的业务线（去重，忽略未分配业务线的主机）
+func hostBusinessLines(db *gorm.DB, hostIDs []string) ([]string, error) {
+	var lines []string
+	err := db.Model(&model.Host{}).
+		Where("host_id IN ? AND business_line <> ''", hostIDs).
+		Distinct().Pluck("business_line", &lines).Error
+	return lines, err
+}
+
+// checkBusinessLines 校验并去重排序要绑定到用户或角色的业务线代码，校验失败时返回错误响应并返回 false
+// 受业务线范围限制的操作者只能分配其范围内的业务线
+func checkBusinessLines(c *gin.Context, db *gorm.DB, lines []string) (model.StringArray, bool) {
+	result := model.StringArray(slices.Compact(slices.Sorted(slices.Values(lines))))
+	if !scopeOf(c).ContainsAll(result) {
+		Forbidden(c, "无权分配业务线范围外的业务线")
+		return nil, false
+	}
+	if len(result) == 0 {
+		return result, true
+	}
+
+	var existing []string
+	if err := db.Model(&model.BusinessLine{}).Where("code IN ?", []string(result)).Pluck("code", &existing).Error; err != nil {
+		InternalError(c, "查询业务线失败")
+		return nil, false
+	}
+	for _, code := range result {
+		if !slices.Contains(existing, code) {
+			BadRequest(c, "业务线不存在: "+code)
+			return nil, false
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,165 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestBusinessLineScope 测试受业务线范围限制的用户只能看到范围内的主机，访问范围外的主机或任务返回 403
+func TestBusinessLineScope(t *testing.T) {
+	db := testdb.Open(t, &model.Host{}, &model.ScanTask{}, &model.AgentRestartRecord{})
+	hosts := []model.Host{
+		{HostID: "host-pay", Hostname: "pay-1", BusinessLine: "pay", Status: model.HostStatusOnline},
+		{HostID: "host-ops", Hostname: "ops-1", BusinessLine: "ops", Status: model.HostStatusOnline},
+		{HostID: "host-none", Hostname: "none-1", Status: model.HostStatusOnline},
+	}
+	if err := db.Create(&hosts).Error; err != nil {
+		t.Fatal(err)
+	}
+	tasks := []model.ScanTask{
+		{TaskID: "task-pay", Type: model.TaskTypeBaselineScan, TargetType: model.TargetTypeHostIDs,
+			TargetConfig: model.TargetConfig{HostIDs: []string{"host-pay"}, BusinessLines: []string{"pay"}}},
+		{TaskID: "task-ops", Type: model.TaskTypeBaselineScan, TargetType: model.TargetTypeHostIDs,
+			TargetConfig: model.TargetConfig{HostIDs: []string{"host-ops"}, BusinessLines: []string{"ops"}}},
+		{TaskID: "task-all", Type: model.TaskTypeBaselineScan, TargetType: model.TargetTypeAll},
+	}
+	if err := db.Create(&tasks).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	hostsHandler := NewHostsHandler(db, zap.NewNop(), nil, nil)
+	tasksHandler := NewTasksHandler(db, zap.NewNop())
+	payOnly := rbac.Scope{BusinessLines: []string{"pay"}}
+
+	type response struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	do := func(scope rbac.Scope, method, path string, body any) (int, response) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("username", "alice")
+			c.Set(rbac.ScopeContextKey, scope)
+			c.Next()
+		})
+		router.GET("/api/v1/hosts", hostsHandler.ListHosts)
+		router.POST("/api/v1/hosts/restart-agent", hostsHandler.RestartAgent)
+		router.GET("/api/v1/tasks/:task_id", tasksHandler.GetTask)
+
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		var resp response
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	t.Run("scopeHosts", func(t *testing.T) {
+		listHosts := func(scope rbac.Scope) []string {
+			t.Helper()
+			code, resp := do(scope, http.MethodGet, "/api/v1/hosts?page_size=100", nil)
+			if code != http.StatusOK {
+				t.Fatalf("list hosts: status = %d, message = %q", code, resp.Message)
+			}
+			var data struct {
+				Items []struct {
+					HostID string `json:"host_id"`
+				} `json:"items"`
+			}
+			if err := json.Unmarshal(resp.Data, &data); err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]string, 0, len(data.Items))
+			for _, item := range data.Items {
+				ids = append(ids, item.HostID)
+			}
+			return ids
+		}
+
+		if ids := listHosts(payOnly); len(ids) != 1 || ids[0] != "host-pay" {
+			t.Errorf("scoped user sees hosts %v, want [host-pay]", ids)
+		}
+		if ids := listHosts(rbac.Scope{}); len(ids) != 3 {
+			t.Errorf("unrestricted user sees hosts %v, want all 3", ids)
+		}
+	})
+
+	t.Run("requireHostsInScope", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			hostIDs []string
+			want    int
+		}{
+			{"other business line", []string{"host-ops"}, http.StatusForbidden},
+			{"mixed", []string{"host-pay", "host-ops"}, http.StatusForbidden},
+			{"unassigned host", []string{"host-none"}, http.StatusForbidden},
+			{"unknown host", []string{"host-missing"}, http.StatusForbidden},
+			{"in scope", []string{"host-pay"}, http.StatusOK},
+		}
+		for _, tt := range tests {
+			code, resp := do(payOnly, http.MethodPost, "/api/v1/hosts/restart-agent", RestartAgentRequest{HostIDs: tt.hostIDs})
+			if code != tt.want {
+				t.Errorf("%s: status = %d, message = %q, want %d", tt.name, code, resp.Message, tt.want)
+			}
+			if tt.want == http.StatusForbidden && resp.Message != "无权访问业务线范围外的主机" {
+				t.Errorf("%s: message = %q", tt.name, resp.Message)
+			}
+		}
+
+		var records []model.AgentRestartRecord
+		if err := db.Find(&records).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || len(records[0].BusinessLines) != 1 || records[0].BusinessLines[0] != "pay" {
+			t.Errorf("restart records = %+v, want one record scoped to pay", records)
+		}
+
+		// 未指定主机时只统计范围内的在线主机
+		code, resp := do(payOnly, http.MethodPost, "/api/v1/hosts/restart-agent", RestartAgentRequest{})
+		var data struct {
+			TotalCount int64 `json:"total_count"`
+		}
+		json.Unmarshal(resp.Data, &data)
+		if code != http.StatusOK || data.TotalCount != 1 {
+			t.Errorf("restart all: status = %d, total_count = %d, want 200 and 1", code, data.TotalCount)
+		}
+	})
+
+	t.Run("requireBusinessLinesInScope", func(t *testing.T) {
+		tests := []struct {
+			scope  rbac.Scope
+			taskID string
+			want   int
+		}{
+			{payOnly, "task-pay", http.StatusOK},
+			{payOnly, "task-ops", http.StatusForbidden},
+			{payOnly, "task-all", http.StatusForbidden},
+			{rbac.Scope{}, "task-ops", http.StatusOK},
+			{rbac.Scope{}, "task-all", http.StatusOK},
+		}
+		for _, tt := range tests {
+			code, resp := do(tt.scope, http.MethodGet, "/api/v1/tasks/"+tt.taskID, nil)
+			if code != tt.want {
+				t.Errorf("scope %v get %s: status = %d, message = %q, want %d", tt.scope.BusinessLines, tt.taskID, code, resp.Message, tt.want)
+			}
+			if tt.want == http.StatusForbidden && resp.Message != "无权访问业务线范围外的任务" {
+				t.Errorf("get %s: message = %q", tt.taskID, resp.Message)
+			}
+		}
+	})
+}
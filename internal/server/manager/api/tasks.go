@@ -13,6 +13,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/imkerbos/mxsec-platform/internal/server/agentcenter/service"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -82,6 +83,12 @@ func (h *TasksHandler) enrichTaskWithTargetHosts(task *model.ScanTask) *TaskResp
 		}
 	}
 
+	// 任务只作用于创建人业务线范围内的主机
+	if len(task.TargetConfig.BusinessLines) > 0 {
+		baseQuery = baseQuery.Where("business_line IN ?", task.TargetConfig.BusinessLines)
+		onlineQuery = onlineQuery.Where("business_line IN ?", task.TargetConfig.BusinessLines)
+	}
+
 	switch task.TargetType {
 	case model.TargetTypeAll:
 		// 查询所有主机
@@ -231,6 +238,12 @@ func (h *TasksHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
+	// 受业务线范围限制的用户只能对范围内的主机创建任务，任务记录其业务线范围
+	if !requireHostsInScope(c, h.db, targetConfig.HostIDs) {
+		return
+	}
+	targetConfig.BusinessLines = scopeOf(c).BusinessLines
+
 	// 创建任务（状态为 created，等待用户确认执行）
 	task := &model.ScanTask{
 		TaskID:       uuid.New().String(),
@@ -254,6 +267,9 @@ func (h *TasksHandler) CreateTask(c *gin.Context) {
 	}
 
 	h.logger.Info("任务已创建", zap.String("task_id", task.TaskID))
+	audit.AddTargets(c, task.TaskID)
+	audit.AddTargets(c, task.TargetConfig.HostIDs...)
+	audit.SetChange(c, nil, task)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"code": 0,
@@ -270,8 +286,8 @@ func (h *TasksHandler) ListTasks(c *gin.Context) {
 	status := c.Query("status")
 	policyID := c.Query("policy_id")
 
-	// 构建查询
-	query := h.db.Model(&model.ScanTask{})
+	// 构建查询（只返回业务线范围内的任务）
+	query := scopeByBusinessLines(c, h.db.Model(&model.ScanTask{}), "target_config", "$.business_lines")
 
 	// 过滤条件
 	if status != "" {
@@ -341,6 +357,10 @@ func (h *TasksHandler) GetTask(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
 		"data": h.enrichTaskWithTargetHosts(&task),
@@ -369,6 +389,10 @@ func (h *TasksHandler) RunTask(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	// 检查任务状态
 	if task.Status == model.TaskStatusRunning {
 		c.JSON(http.StatusConflict, gin.H{
@@ -433,6 +457,10 @@ func (h *TasksHandler) CancelTask(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	// 检查任务状态，只有 created、pending 或 running 状态的任务可以取消
 	if task.Status != model.TaskStatusCreated && task.Status != model.TaskStatusPending && task.Status != model.TaskStatusRunning {
 		c.JSON(http.StatusConflict, gin.H{
@@ -491,6 +519,10 @@ func (h *TasksHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	// 检查任务状态，running 状态的任务不能删除
 	if task.Status == model.TaskStatusRunning {
 		c.JSON(http.StatusConflict, gin.H{
@@ -535,6 +567,10 @@ func (h *TasksHandler) GetTaskHostStatus(c *gin.Context) {
 		return
 	}
 
+	if !requireBusinessLinesInScope(c, task.TargetConfig.BusinessLines) {
+		return
+	}
+
 	// 查询主机执行状态
 	var hostStatuses []model.TaskHostStatus
 	if err := h.db.Where("task_id = ?", taskID).
@@ -9,6 +9,12 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/apitoken"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/passwd"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/session"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/sso"
 	"github.com/imkerbos/mxsec-platform/internal/server/model"
 )
 
@@ -33,6 +39,7 @@ type ListUsersRequest struct {
 	Username string `form:"username"`
 	Role     string `form:"role"`
 	Status   string `form:"status"`
+	Source   string `form:"source"` // local/oidc/ldap/service
 }
 
 // ListUsersResponse 用户列表响应
@@ -43,19 +50,22 @@ type ListUsersResponse struct {
 
 // CreateUserRequest 创建用户请求
 type CreateUserRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=64"`
-	Password string `json:"password" binding:"required,min=6"`
-	Email    string `json:"email" binding:"omitempty,email"`
-	Role     string `json:"role" binding:"required,oneof=admin user"`
-	Status   string `json:"status" binding:"omitempty,oneof=active inactive"`
+	Username       string   `json:"username" binding:"required,min=3,max=64"`
+	Password       string   `json:"password" binding:"omitempty,min=6"` // 服务账号不需要密码，其他用户必填
+	Email          string   `json:"email" binding:"omitempty,email"`
+	Role           string   `json:"role" binding:"required,max=64"`
+	Status         string   `json:"status" binding:"omitempty,oneof=active inactive"`
+	BusinessLines  []string `json:"business_lines"`  // 业务线范围（业务线代码），为空表示不限制
+	ServiceAccount bool     `json:"service_account"` // 服务账号：不能登录，只能通过 API Token 访问
 }
 
 // UpdateUserRequest 更新用户请求
 type UpdateUserRequest struct {
-	Password string `json:"password" binding:"omitempty,min=6"`
-	Email    string `json:"email" binding:"omitempty,email"`
-	Role     string `json:"role" binding:"omitempty,oneof=admin user"`
-	Status   string `json:"status" binding:"omitempty,oneof=active inactive"`
+	Password      string    `json:"password" binding:"omitempty,min=6"`
+	Email         string    `json:"email" binding:"omitempty,email"`
+	Role          string    `json:"role" binding:"omitempty,max=64"`
+	Status        string    `json:"status" binding:"omitempty,oneof=active inactive"`
+	BusinessLines *[]string `json:"business_lines"` // 业务线范围，未提供时不修改，空数组表示不限制
 }
 
 // ListUsers 获取用户列表
@@ -78,8 +88,8 @@ func (h *UsersHandler) ListUsers(c *gin.Context) {
 		req.PageSize = 20
 	}
 
-	// 构建查询
-	query := h.db.Model(&model.User{})
+	// 构建查询，受业务线范围限制的用户只能看到范围内的用户
+	query := scopeByBusinessLines(c, h.db.Model(&model.User{}), "business_lines", "$")
 
 	if req.Username != "" {
 		query = query.Where("username LIKE ?", "%"+req.Username+"%")
@@ -90,6 +100,9 @@ func (h *UsersHandler) ListUsers(c *gin.Context) {
 	if req.Status != "" {
 		query = query.Where("status = ?", req.Status)
 	}
+	if req.Source != "" {
+		query = query.Where("source = ?", req.Source)
+	}
 
 	// 获取总数
 	var total int64
@@ -142,6 +155,10 @@ func (h *UsersHandler) GetUser(c *gin.Context) {
 		})
 		return
 	}
+	if !scopeOf(c).ContainsAll(user.BusinessLines) {
+		Forbidden(c, "无权访问业务线范围外的用户")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -161,6 +178,39 @@ func (h *UsersHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	if req.Password == "" && !req.ServiceAccount {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "密码不能为空",
+		})
+		return
+	}
+	if req.Password != "" && req.ServiceAccount {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "服务账号不能设置密码",
+		})
+		return
+	}
+	if !h.checkRole(c, req.Role) {
+		return
+	}
+	if !req.ServiceAccount {
+		cfg, err := sso.LoadConfig(h.db)
+		if err != nil {
+			h.logger.Error("读取认证配置失败", zap.Error(err))
+			InternalError(c, "创建失败")
+			return
+		}
+		if !checkPassword(c, h.db, h.logger, cfg.PasswordPolicy, nil, req.Password) {
+			return
+		}
+	}
+	businessLines, ok := checkBusinessLines(c, h.db, req.BusinessLines)
+	if !ok {
+		return
+	}
+
 	// 检查用户名是否已存在
 	var existingUser model.User
 	if err := h.db.Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
@@ -178,15 +228,19 @@ func (h *UsersHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		h.logger.Error("加密密码失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "创建失败",
-		})
-		return
+	// 加密密码（服务账号没有密码）
+	source, hashedPassword := model.UserSourceService, []byte(nil)
+	if !req.ServiceAccount {
+		source = model.UserSourceLocal
+		var err error
+		if hashedPassword, err = bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost); err != nil {
+			h.logger.Error("加密密码失败", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "创建失败",
+			})
+			return
+		}
 	}
 
 	// 设置默认状态
@@ -197,11 +251,13 @@ func (h *UsersHandler) CreateUser(c *gin.Context) {
 
 	// 创建用户
 	user := &model.User{
-		Username: req.Username,
-		Password: string(hashedPassword),
-		Email:    req.Email,
-		Role:     model.UserRole(req.Role),
-		Status:   status,
+		Username:      req.Username,
+		Password:      string(hashedPassword),
+		Email:         req.Email,
+		Role:          model.UserRole(req.Role),
+		Status:        status,
+		BusinessLines: businessLines,
+		Source:        source,
 	}
 
 	if err := h.db.Create(user).Error; err != nil {
@@ -212,6 +268,8 @@ func (h *UsersHandler) CreateUser(c *gin.Context) {
 		})
 		return
 	}
+	audit.AddTargets(c, user.Username)
+	audit.SetChange(c, nil, user)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -240,6 +298,8 @@ func (h *UsersHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	before := user
+
 	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -248,9 +308,41 @@ func (h *UsersHandler) UpdateUser(c *gin.Context) {
 		})
 		return
 	}
+	if !h.checkTargetUser(c, &user) {
+		return
+	}
+	if req.Role != "" && req.Role != string(user.Role) && !h.checkRole(c, req.Role) {
+		return
+	}
+	if req.BusinessLines != nil {
+		businessLines, ok := checkBusinessLines(c, h.db, *req.BusinessLines)
+		if !ok {
+			return
+		}
+		user.BusinessLines = businessLines
+	}
 
-	// 更新密码（如果提供）
+	// 更新密码（如果提供），SSO 用户和服务账号没有本地密码
+	if req.Password != "" && user.Source != "" && user.Source != model.UserSourceLocal {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "SSO 用户和服务账号不能设置本地密码",
+		})
+		return
+	}
+	historyCount := 0
 	if req.Password != "" {
+		cfg, err := sso.LoadConfig(h.db)
+		if err != nil {
+			h.logger.Error("读取认证配置失败", zap.Error(err))
+			InternalError(c, "更新失败")
+			return
+		}
+		if !checkPassword(c, h.db, h.logger, cfg.PasswordPolicy, &user, req.Password) {
+			return
+		}
+		historyCount = cfg.PasswordPolicy.HistoryCount
+
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 		if err != nil {
 			h.logger.Error("加密密码失败", zap.Error(err))
@@ -274,7 +366,21 @@ func (h *UsersHandler) UpdateUser(c *gin.Context) {
 		user.Status = model.UserStatus(req.Status)
 	}
 
-	if err := h.db.Save(&user).Error; err != nil {
+	// 重置密码或禁用用户时吊销其全部会话，已签发的访问令牌和刷新令牌立即失效
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if req.Password != "" {
+			if err := passwd.Record(tx, user.ID, before.Password, historyCount); err != nil {
+				return err
+			}
+		}
+		if req.Password != "" || user.Status != model.UserStatusActive {
+			return session.RevokeUser(tx, user.Username, 0)
+		}
+		return nil
+	}); err != nil {
 		h.logger.Error("更新用户失败", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    500,
@@ -282,6 +388,11 @@ func (h *UsersHandler) UpdateUser(c *gin.Context) {
 		})
 		return
 	}
+	audit.AddTargets(c, user.Username)
+	audit.SetChange(c, before, user)
+	if req.Password != "" {
+		audit.SetField(c, "password", "", "reset")
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
@@ -319,8 +430,20 @@ func (h *UsersHandler) DeleteUser(c *gin.Context) {
 		})
 		return
 	}
+	if !h.checkTargetUser(c, &user) {
+		return
+	}
 
-	if err := h.db.Delete(&user).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		// 吊销该用户的会话和 API Token，避免同名用户重建后继承
+		if err := session.RevokeUser(tx, user.Username, 0); err != nil {
+			return err
+		}
+		return apitoken.RevokeOwner(tx, user.Username)
+	}); err != nil {
 		h.logger.Error("删除用户失败", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    500,
@@ -328,9 +451,125 @@ func (h *UsersHandler) DeleteUser(c *gin.Context) {
 		})
 		return
 	}
+	audit.AddTargets(c, user.Username)
+	audit.SetChange(c, user, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "删除成功",
 	})
 }
+
+// ResetTOTP 清除用户的两步验证（用户丢失认证器时由管理员重置），并吊销其全部会话
+// DELETE /api/v1/users/:id/totp
+func (h *UsersHandler) ResetTOTP(c *gin.Context) {
+	var user model.User
+	if err := h.db.First(&user, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			NotFound(c, "用户不存在")
+			return
+		}
+		h.logger.Error("查询用户失败", zap.Error(err))
+		InternalError(c, "查询用户失败")
+		return
+	}
+	if !scopeOf(c).ContainsAll(user.BusinessLines) {
+		Forbidden(c, "无权管理业务线范围外的用户")
+		return
+	}
+	if !user.TOTPEnabled && user.TOTPSecret == "" {
+		BadRequest(c, "该用户未启用两步验证")
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearTOTP(tx, &user); err != nil {
+			return err
+		}
+		return session.RevokeUser(tx, user.Username, 0)
+	}); err != nil {
+		h.logger.Error("重置两步验证失败", zap.String("username", user.Username), zap.Error(err))
+		InternalError(c, "重置失败")
+		return
+	}
+	audit.AddTargets(c, user.Username)
+	audit.SetField(c, "totp_enabled", user.TOTPEnabled, false)
+	h.logger.Info("重置两步验证", zap.String("username", user.Username), zap.String("operator", c.GetString("username")))
+	SuccessMessage(c, "两步验证已重置")
+}
+
+// checkRole 校验角色是否存在且操作者拥有该角色的全部权限（避免通过分配角色提升权限），否则返回错误响应并返回 false
+func (h *UsersHandler) checkRole(c *gin.Context, role string) bool {
+	exists, err := rbac.RoleExists(h.db, role)
+	if err != nil {
+		h.logger.Error("查询角色失败", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "查询角色失败",
+		})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "角色不存在",
+		})
+		return false
+	}
+
+	perms, err := rbac.Resolve(h.db, role)
+	if err != nil {
+		h.logger.Error("查询角色权限失败", zap.String("role", role), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "查询角色失败",
+		})
+		return false
+	}
+	if missing := rbac.Missing(currentPermissions(c), perms); len(missing) > 0 {
+		h.logger.Warn("拒绝分配超出自身权限的角色",
+			zap.String("role", role),
+			zap.Any("missing", missing),
+			zap.String("operator", c.GetString("username")))
+		Forbidden(c, "不能分配超出自身权限的角色，缺少权限: "+string(missing[0]))
+		return false
+	}
+	return true
+}
+
+// checkTargetUser 校验目标用户在操作者的业务线范围内，且操作者拥有目标用户的全部权限
+// （避免重置高权限用户的密码、禁用或删除高权限用户），否则返回错误响应并返回 false
+func (h *UsersHandler) checkTargetUser(c *gin.Context, user *model.User) bool {
+	if !scopeOf(c).ContainsAll(user.BusinessLines) {
+		Forbidden(c, "无权管理业务线范围外的用户")
+		return false
+	}
+
+	role := string(user.Role)
+	exists, err := rbac.RoleExists(h.db, role)
+	if err != nil {
+		h.logger.Error("查询角色失败", zap.Error(err))
+		InternalError(c, "查询角色失败")
+		return false
+	}
+	// 角色已被删除的用户没有任何权限
+	if !exists {
+		return true
+	}
+
+	perms, err := rbac.Resolve(h.db, role)
+	if err != nil {
+		h.logger.Error("查询角色权限失败", zap.String("role", role), zap.Error(err))
+		InternalError(c, "查询角色失败")
+		return false
+	}
+	if missing := rbac.Missing(currentPermissions(c), perms); len(missing) > 0 {
+		h.logger.Warn("拒绝管理权限高于自身的用户",
+			zap.String("target", user.Username),
+			zap.Any("missing", missing),
+			zap.String("operator", c.GetString("username")))
+		Forbidden(c, "不能管理权限高于自身的用户，缺少权限: "+string(missing[0]))
+		return false
+	}
+	return true
+}
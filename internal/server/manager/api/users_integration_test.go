@@ -0,0 +1,181 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestAssignRoleEscalation 测试只有 users:manage 权限的用户不能为用户分配、也不能创建权限更高的角色，
+// 也不能修改或删除权限更高的用户
+func TestAssignRoleEscalation(t *testing.T) {
+	db := testdb.Open(t, &model.User{}, &model.Role{})
+	h := NewUsersHandler(db, zap.NewNop())
+	viewer, err := rbac.Resolve(db, rbac.RoleViewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := append([]rbac.Permission{rbac.UsersRead, rbac.UsersManage}, viewer...)
+	admin, err := rbac.Resolve(db, rbac.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path string, perms []rbac.Permission, body any) int {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		setPerms := func(c *gin.Context) {
+			c.Set("username", "manager")
+			c.Set(rbac.ContextKey, perms)
+			c.Next()
+		}
+		router.POST("/api/v1/users", setPerms, h.CreateUser)
+		router.PUT("/api/v1/users/:id", setPerms, h.UpdateUser)
+		router.DELETE("/api/v1/users/:id", setPerms, h.DeleteUser)
+		router.POST("/api/v1/roles", setPerms, NewRolesHandler(db, zap.NewNop()).CreateRole)
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w.Code
+	}
+	create := func(perms []rbac.Permission, username, role string) int {
+		return do(http.MethodPost, "/api/v1/users", perms, map[string]any{
+			"username": username, "role": role, "service_account": true,
+		})
+	}
+
+	if code := create(manager, "svc-admin", rbac.RoleAdmin); code != http.StatusForbidden {
+		t.Fatalf("create admin: status = %d, want 403", code)
+	}
+	if code := create(manager, "svc-operator", rbac.RoleOperator); code != http.StatusForbidden {
+		t.Fatalf("create operator: status = %d, want 403", code)
+	}
+	if code := create(manager, "svc-viewer", rbac.RoleViewer); code != http.StatusOK && code != http.StatusCreated {
+		t.Fatalf("create viewer: status = %d, want success", code)
+	}
+
+	var user model.User
+	if err := db.First(&user, "username = ?", "svc-viewer").Error; err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+	if code := do(http.MethodPut, path, manager, map[string]any{"role": rbac.RoleAdmin}); code != http.StatusForbidden {
+		t.Fatalf("update to admin: status = %d, want 403", code)
+	}
+	if err := db.First(&user, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != model.UserRole(rbac.RoleViewer) {
+		t.Fatalf("role after rejected update = %s, want viewer", user.Role)
+	}
+	if code := do(http.MethodPut, path, admin, map[string]any{"role": rbac.RoleAdmin}); code != http.StatusOK {
+		t.Fatalf("admin update to admin: status = %d, want 200", code)
+	}
+
+	// 目标用户已是管理员：不改角色也不能停用或删除
+	if code := do(http.MethodPut, path, manager, map[string]any{"status": string(model.UserStatusInactive)}); code != http.StatusForbidden {
+		t.Fatalf("deactivate admin: status = %d, want 403", code)
+	}
+	if code := do(http.MethodDelete, path, manager, nil); code != http.StatusForbidden {
+		t.Fatalf("delete admin: status = %d, want 403", code)
+	}
+	if err := db.First(&user, user.ID).Error; err != nil {
+		t.Fatalf("admin deleted by manager: %v", err)
+	}
+	if user.Status == model.UserStatusInactive {
+		t.Fatal("admin deactivated by manager")
+	}
+	if code := do(http.MethodDelete, path, admin, nil); code != http.StatusOK {
+		t.Fatalf("admin delete admin: status = %d, want 200", code)
+	}
+
+	if code := do(http.MethodPost, "/api/v1/roles", manager, map[string]any{
+		"name": "super", "permissions": []string{string(rbac.UsersManage), string(rbac.SystemManage)},
+	}); code != http.StatusForbidden {
+		t.Fatalf("create role beyond own permissions: status = %d, want 403", code)
+	}
+}
+
+// TestUserBusinessLineScope 测试受业务线范围限制的用户只能查看和管理范围内的用户
+func TestUserBusinessLineScope(t *testing.T) {
+	db := testdb.Open(t, &model.User{}, &model.Role{})
+	users := []model.User{
+		{Username: "pay-user", Role: model.UserRole(rbac.RoleViewer), Status: model.UserStatusActive, BusinessLines: model.StringArray{"pay"}},
+		{Username: "ops-user", Role: model.UserRole(rbac.RoleViewer), Status: model.UserStatusActive, BusinessLines: model.StringArray{"ops"}},
+		{Username: "global-user", Role: model.UserRole(rbac.RoleViewer), Status: model.UserStatusActive, BusinessLines: model.StringArray{}},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	h := NewUsersHandler(db, zap.NewNop())
+	admin, err := rbac.Resolve(db, rbac.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path string, body any) (int, []byte) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("username", "manager")
+			c.Set(rbac.ContextKey, admin)
+			c.Set(rbac.ScopeContextKey, rbac.Scope{BusinessLines: []string{"pay"}})
+			c.Next()
+		})
+		router.GET("/api/v1/users", h.ListUsers)
+		router.GET("/api/v1/users/:id", h.GetUser)
+		router.PUT("/api/v1/users/:id", h.UpdateUser)
+		router.DELETE("/api/v1/users/:id", h.DeleteUser)
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w.Code, w.Body.Bytes()
+	}
+
+	code, body := do(http.MethodGet, "/api/v1/users", nil)
+	var list struct {
+		Data ListUsersResponse `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil || code != http.StatusOK {
+		t.Fatalf("list users: status = %d, err = %v", code, err)
+	}
+	if list.Data.Total != 1 || len(list.Data.Items) != 1 || list.Data.Items[0].Username != "pay-user" {
+		t.Fatalf("list users = %d %+v, want only pay-user", list.Data.Total, list.Data.Items)
+	}
+
+	for _, user := range users {
+		path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+		want := http.StatusForbidden
+		if user.Username == "pay-user" {
+			want = http.StatusOK
+		}
+		if code, _ := do(http.MethodGet, path, nil); code != want {
+			t.Errorf("get %s: status = %d, want %d", user.Username, code, want)
+		}
+		if code, _ := do(http.MethodPut, path, map[string]any{"status": string(model.UserStatusInactive)}); code != want {
+			t.Errorf("update %s: status = %d, want %d", user.Username, code, want)
+		}
+		if code, _ := do(http.MethodDelete, path, nil); code != want {
+			t.Errorf("delete %s: status = %d, want %d", user.Username, code, want)
+		}
+	}
+
+	var remaining []string
+	db.Model(&model.User{}).Order("username").Pluck("username", &remaining)
+	if len(remaining) != 2 || remaining[0] != "global-user" || remaining[1] != "ops-user" {
+		t.Errorf("remaining users = %v, want [global-user ops-user]", remaining)
+	}
+}
@@ -0,0 +1,104 @@
+package apispec
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const moduleRoot = "../../../.."
+
+var (
+	buildOnce sync.Once
+	built     *Document
+	buildErr  error
+)
+
+// build 从源码生成一次文档，供各测试共用
+func build(t *testing.T) *Document {
+	t.Helper()
+	buildOnce.Do(func() {
+		built, buildErr = Build(moduleRoot)
+	})
+	if buildErr != nil {
+		t.Fatalf("生成失败: %v", buildErr)
+	}
+	return built
+}
+
+// TestGeneratedFilesUpToDate 契约测试：处理器请求/响应结构或路由变化后必须重新生成文档和客户端
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	files, err := Render(build(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		current, err := os.ReadFile(filepath.Join(moduleRoot, f.Path))
+		if err != nil {
+			t.Errorf("读取 %s 失败: %v（请运行 make openapi）", f.Path, err)
+			continue
+		}
+		if !bytes.Equal(current, f.Content) {
+			t.Errorf("%s 与 router/api 源码不一致，请运行 make openapi 重新生成并提交\n首个差异: %s", f.Path, firstDiff(current, f.Content))
+		}
+	}
+}
+
+func TestBuildOperations(t *testing.T) {
+	doc := build(t)
+
+	hosts := doc.Paths["/hosts"]
+	if hosts == nil || hosts.Get == nil {
+		t.Fatal("缺少 GET /hosts")
+	}
+	if hosts.Get.Permission != "hosts:read" || len(hosts.Get.Security) != 1 {
+		t.Errorf("GET /hosts 权限或认证不正确: %q %v", hosts.Get.Permission, hosts.Get.Security)
+	}
+	data := hosts.Get.Responses["200"].Content["application/json"].Schema.Properties.Get("data")
+	if data == nil || data.Properties.Get("items") == nil || data.Properties.Get("total") == nil {
+		t.Errorf("GET /hosts 应返回分页数据，得到 %+v", data)
+	}
+
+	if op := doc.Paths["/auth/login"].Post; len(op.Security) != 0 || op.AuditAction != "auth.login" {
+		t.Errorf("POST /auth/login 应为公开接口并记录审计，得到 %v %q", op.Security, op.AuditAction)
+	}
+	if op := doc.Paths["/hosts/{host_id}"].Delete; op.Responses["202"] == nil {
+		t.Error("DELETE /hosts/{host_id} 需要审批时返回 202")
+	}
+	if op := doc.Paths["/tasks"].Post; op.RequestBody == nil || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/CreateTaskRequest" {
+		t.Error("POST /tasks 请求体应为 CreateTaskRequest")
+	}
+	if doc.Paths["/openapi.json"] == nil {
+		t.Error("缺少 GET /openapi.json")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for in, want := range map[string]string{
+		"host_id":      "HostID",
+		"os_family":    "OSFamily",
+		"ipv4":         "IPv4",
+		"CreatedAt":    "CreatedAt",
+		"sha256":       "SHA256",
+		"2fa":          "X2fa",
+		"scope-values": "ScopeValues",
+	} {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func firstDiff(a, b []byte) string {
+	al, bl := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+	for i := 0; i < len(al) && i < len(bl); i++ {
+		if al[i] != bl[i] {
+			return "第 " + strconv.Itoa(i+1) + " 行: 提交的 " + strings.TrimSpace(al[i]) + " / 生成的 " + strings.TrimSpace(bl[i])
+		}
+	}
+	return "文件长度不同"
+}
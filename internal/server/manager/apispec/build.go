@@ -0,0 +1,308 @@
+package apispec
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	modulePath = "github.com/imkerbos/mxsec-platform"
+	routerPkg  = modulePath + "/internal/server/manager/router"
+	apiPkg     = modulePath + "/internal/server/manager/api"
+
+	// basePath 文档只收录该前缀下的接口，路径相对于 servers.url
+	basePath = "/api/v1"
+
+	// Version API 版本，接口出现不兼容变更时递增主版本并新建客户端包
+	Version = "1.0.0"
+)
+
+// Build 从模块根目录 dir 下的源码生成 OpenAPI 文档
+func Build(dir string) (*Document, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	// 依赖包从源码做类型检查（不依赖编译产物的导出数据格式），只保留声明、丢弃函数体以加快速度
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+			if err != nil || isAnalyzed(filename) {
+				return file, err
+			}
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok {
+					fn.Body = nil
+				}
+			}
+			return file, nil
+		},
+	}
+	pkgs, err := packages.Load(cfg, routerPkg, apiPkg)
+	if err != nil {
+		return nil, fmt.Errorf("加载源码失败: %w", err)
+	}
+	var router, handlers *packages.Package
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("加载 %s 失败: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		switch pkg.PkgPath {
+		case routerPkg:
+			router = pkg
+		case apiPkg:
+			handlers = pkg
+		}
+	}
+	if router == nil || handlers == nil {
+		return nil, fmt.Errorf("未找到 router 或 api 包")
+	}
+
+	b := newBuilder(dir, router.Fset)
+	b.indexHandlers(handlers)
+	routes, err := extractRoutes(router)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range routes {
+		if err := b.addRoute(r); err != nil {
+			return nil, err
+		}
+	}
+	return b.document(), nil
+}
+
+// isAnalyzed 判断源文件是否属于需要分析函数体的 router 和 api 包
+func isAnalyzed(filename string) bool {
+	dir := filepath.ToSlash(filepath.Dir(filename))
+	return strings.HasSuffix(dir, "/internal/server/manager/router") || strings.HasSuffix(dir, "/internal/server/manager/api")
+}
+
+// builder 汇总路由和处理器分析结果
+type builder struct {
+	pkg     *packages.Package
+	funcs   map[*types.Func]*ast.FuncDecl
+	methods map[string]*ast.FuncDecl // Handler.Method -> 声明
+	schemas *schemaRegistry
+	paths   map[string]*PathItem
+	ops     []*Operation
+}
+
+func newBuilder(dir string, fset *token.FileSet) *builder {
+	return &builder{
+		funcs:   make(map[*types.Func]*ast.FuncDecl),
+		methods: make(map[string]*ast.FuncDecl),
+		schemas: newSchemaRegistry(dir, fset),
+		paths:   make(map[string]*PathItem),
+	}
+}
+
+// indexHandlers 索引 api 包中的函数声明，供处理器分析时跟进调用
+func (b *builder) indexHandlers(pkg *packages.Package) {
+	b.pkg = pkg
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			b.funcs[obj] = fn
+			if fn.Recv != nil {
+				b.methods[methodKey(obj)] = fn
+			}
+		}
+	}
+}
+
+// methodKey 返回方法的 接收者类型.方法名
+func methodKey(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return fn.Name()
+	}
+	t := sig.Recv().Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name() + "." + fn.Name()
+	}
+	return fn.Name()
+}
+
+// addRoute 分析路由对应的处理器并生成操作
+func (b *builder) addRoute(r route) error {
+	if !strings.HasPrefix(r.path, basePath+"/") {
+		return nil
+	}
+	decl := b.methods[r.handler]
+	if decl == nil {
+		return fmt.Errorf("路由 %s %s 的处理器 %s 未找到", r.method, r.path, r.handler)
+	}
+	path, pathParams := openAPIPath(strings.TrimPrefix(r.path, basePath))
+
+	op := &Operation{
+		Tags:        []string{strings.TrimSuffix(strings.SplitN(r.handler, ".", 2)[0], "Handler")},
+		OperationID: decl.Name.Name,
+		Permission:  r.permission,
+		AuditAction: r.audit,
+		Security:    []map[string][]string{},
+		method:      r.method,
+		path:        path,
+		handler:     r.handler,
+	}
+	if r.auth {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+	op.Summary, op.Description = docSummary(decl)
+	for _, name := range pathParams {
+		op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+
+	a := newHandlerAnalysis(b, r.method)
+	a.analyze(decl, 0)
+	a.apply(op)
+
+	item := b.paths[path]
+	if item == nil {
+		item = &PathItem{}
+		b.paths[path] = item
+	}
+	item.setOperation(r.method, op)
+	b.ops = append(b.ops, op)
+	return nil
+}
+
+// document 生成最终文档：处理 operationId 冲突并汇总标签
+func (b *builder) document() *Document {
+	// 同名方法出现在多个处理器时加上标签前缀
+	owners := make(map[string]map[string]bool)
+	for _, op := range b.ops {
+		if owners[op.OperationID] == nil {
+			owners[op.OperationID] = make(map[string]bool)
+		}
+		owners[op.OperationID][op.handler] = true
+	}
+	used := make(map[string]int)
+	for _, op := range b.ops {
+		if len(owners[op.OperationID]) > 1 {
+			op.OperationID = op.Tags[0] + op.OperationID
+		}
+		used[op.OperationID]++
+		if n := used[op.OperationID]; n > 1 {
+			op.OperationID = fmt.Sprintf("%s%d", op.OperationID, n)
+		}
+	}
+
+	tagSet := make(map[string]bool)
+	for _, op := range b.ops {
+		tagSet[op.Tags[0]] = true
+	}
+	var tags []Tag
+	for _, name := range sortedKeys(tagSet) {
+		tags = append(tags, Tag{Name: name})
+	}
+
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "MxSec Platform Manager API",
+			Description: "由 router 和 api 包源码生成（make openapi），请勿手工修改。x-permission 为接口所需权限，x-audit-action 为审计日志中的操作类型。",
+			Version:     Version,
+		},
+		Servers: []Server{{URL: basePath}},
+		Tags:    tags,
+		Paths:   b.paths,
+		Components: Components{
+			Schemas: b.schemas.components,
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "错误响应（code 为 HTTP 状态码或业务错误码）",
+					Content: map[string]*MediaType{
+						"application/json": {Schema: &Schema{
+							Type: "object",
+							Properties: Properties{
+								{Name: "code", Schema: &Schema{Type: "integer"}},
+								{Name: "message", Schema: &Schema{Type: "string"}},
+							},
+							Required: []string{"code", "message"},
+						}},
+					},
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "登录获取的访问令牌或个人/服务账号 API Token",
+				},
+			},
+		},
+	}
+}
+
+// openAPIPath 把 gin 路径参数 :name 转换为 {name}
+func openAPIPath(path string) (string, []string) {
+	var params []string
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			params = append(params, seg[1:])
+			segs[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segs, "/"), params
+}
+
+// docSummary 从处理器文档注释提取摘要和说明，去掉方法名前缀和路由行
+func docSummary(decl *ast.FuncDecl) (string, string) {
+	if decl.Doc == nil {
+		return "", ""
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(decl.Doc.Text()), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isRouteComment(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "", ""
+	}
+	summary := strings.TrimSpace(strings.TrimPrefix(lines[0], decl.Name.Name))
+	return summary, strings.Join(lines[1:], "\n")
+}
+
+func isRouteComment(line string) bool {
+	for _, m := range []string{"GET ", "POST ", "PUT ", "PATCH ", "DELETE ", "HEAD "} {
+		if strings.HasPrefix(line, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
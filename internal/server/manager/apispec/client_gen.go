@@ -0,0 +1,486 @@
+package apispec
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 客户端包中手写的标识符，生成的类型名需避开
+var reservedNames = map[string]bool{
+	"Client": true, "Option": true, "APIError": true, "PendingApprovalError": true,
+}
+
+// 生成 Go 字段名时使用的缩写写法
+var initialisms = map[string]string{
+	"api": "API", "cpu": "CPU", "dns": "DNS", "fim": "FIM", "gid": "GID", "html": "HTML", "http": "HTTP",
+	"https": "HTTPS", "id": "ID", "ip": "IP", "ipv4": "IPv4", "ipv6": "IPv6", "json": "JSON", "ldap": "LDAP",
+	"md5": "MD5", "oidc": "OIDC", "os": "OS", "pid": "PID", "ppid": "PPID", "sha256": "SHA256", "sql": "SQL",
+	"ssh": "SSH", "sso": "SSO", "tcp": "TCP", "tls": "TLS", "totp": "TOTP", "ttl": "TTL", "udp": "UDP",
+	"uid": "UID", "uri": "URI", "url": "URL", "uuid": "UUID", "xml": "XML",
+}
+
+// clientGen 从文档生成客户端代码：components.schemas 生成结构体，每个操作生成一个 Client 方法
+type clientGen struct {
+	doc     *Document
+	types   map[string]bool
+	pending []pendingType
+	imports map[string]bool
+	docs    map[string]string // 内联类型名 -> 说明
+}
+
+// pendingType 等待输出的内联对象类型
+type pendingType struct {
+	name   string
+	schema *Schema
+	doc    string
+}
+
+// GenerateClient 根据文档生成 Go 客户端代码（gofmt 格式）
+func GenerateClient(doc *Document) ([]byte, error) {
+	g := &clientGen{
+		doc:     doc,
+		types:   make(map[string]bool),
+		imports: map[string]bool{"context": true, "net/http": true},
+		docs:    make(map[string]string),
+	}
+	for name := range doc.Components.Schemas {
+		g.types[name] = true
+	}
+
+	var body bytes.Buffer
+	body.WriteString("// APIVersion 生成客户端所用 OpenAPI 文档的版本\n")
+	fmt.Fprintf(&body, "const APIVersion = %q\n\n", doc.Info.Version)
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schema := doc.Components.Schemas[name]
+		g.writeStruct(&body, name, schema, schema.Description)
+		g.flush(&body)
+	}
+	for _, op := range doc.operations() {
+		if err := g.writeOperation(&body, op); err != nil {
+			return nil, err
+		}
+		g.flush(&body)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by openapi-gen. DO NOT EDIT.\n\n")
+	out.WriteString("package v1\n\n")
+	out.WriteString("import (\n")
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	for _, imp := range imports {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	out.WriteString(")\n\n")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的客户端代码失败: %w", err)
+	}
+	return src, nil
+}
+
+// flush 输出生成过程中产生的内联类型
+func (g *clientGen) flush(buf *bytes.Buffer) {
+	for len(g.pending) > 0 {
+		t := g.pending[0]
+		g.pending = g.pending[1:]
+		g.writeStruct(buf, t.name, t.schema, t.doc)
+	}
+}
+
+// writeStruct 输出对象结构体
+func (g *clientGen) writeStruct(buf *bytes.Buffer, name string, schema *Schema, doc string) {
+	writeComment(buf, "", name, doc)
+	if len(schema.Properties) == 0 {
+		fmt.Fprintf(buf, "type %s %s\n\n", name, g.goType(schema, name, false))
+		return
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	used := make(map[string]bool)
+	for _, prop := range schema.Properties {
+		field := uniqueName(goName(prop.Name), used)
+		used[field] = true
+		g.docs[name+field] = name + " 的 " + prop.Name + " 字段"
+		g.docs[name+field+"Item"] = name + " 的 " + prop.Name + " 字段元素"
+		typ := g.goType(prop.Schema, name+field, true)
+		tag := prop.Name
+		if isPointer(typ) || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+			tag += ",omitempty"
+		}
+		if desc := fieldDescription(prop.Schema); desc != "" {
+			writeComment(buf, "\t", "", desc)
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+	buf.WriteString("}\n\n")
+}
+
+// goType 返回结构对应的 Go 类型；ctx 为内联对象生成的类型名，field 表示作为结构体字段（可空时用指针）
+func (g *clientGen) goType(s *Schema, ctx string, field bool) string {
+	if s == nil {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if len(s.AllOf) == 1 {
+		inner := g.goType(s.AllOf[0], ctx, false)
+		if field && s.Nullable {
+			return "*" + inner
+		}
+		return inner
+	}
+	if s.Ref != "" {
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	}
+	var typ string
+	switch s.Type {
+	case "string":
+		typ = "string"
+		if s.Format == "byte" || s.Format == "binary" {
+			return "[]byte"
+		}
+	case "integer":
+		switch s.Format {
+		case "int64":
+			typ = "int64"
+		case "int32":
+			typ = "int32"
+		default:
+			typ = "int"
+		}
+	case "number":
+		typ = "float64"
+		if s.Format == "float" {
+			typ = "float32"
+		}
+	case "boolean":
+		typ = "bool"
+	case "array":
+		return "[]" + g.goType(s.Items, ctx+"Item", false)
+	case "object":
+		switch {
+		case len(s.Properties) > 0:
+			name := g.newType(ctx, s, s.Description)
+			if field {
+				return "*" + name
+			}
+			return name
+		case s.AdditionalProperties != nil && (s.AdditionalProperties.Type != "" || s.AdditionalProperties.Ref != ""):
+			return "map[string]" + g.goType(s.AdditionalProperties, ctx+"Value", false)
+		default:
+			return "map[string]interface{}"
+		}
+	default:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if field && s.Nullable {
+		return "*" + typ
+	}
+	return typ
+}
+
+// newType 登记内联对象类型，名称冲突时加序号
+func (g *clientGen) newType(name string, s *Schema, doc string) string {
+	base := name
+	for i := 2; g.types[name] || reservedNames[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.types[name] = true
+	if doc == "" {
+		doc = g.docs[base]
+	}
+	g.pending = append(g.pending, pendingType{name: name, schema: s, doc: doc})
+	return name
+}
+
+// writeOperation 输出操作对应的参数类型和 Client 方法
+func (g *clientGen) writeOperation(buf *bytes.Buffer, op *Operation) error {
+	name := op.OperationID
+
+	// 方法参数：路径参数、查询参数、请求体
+	args := []string{"ctx context.Context"}
+	pathExpr := strconv.Quote(op.path)
+	var query []*Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			arg := lowerName(goName(p.Name))
+			args = append(args, arg+" string")
+			pathExpr = strings.Replace(pathExpr, "{"+p.Name+"}", `" + url.PathEscape(`+arg+`) + "`, 1)
+			g.imports["net/url"] = true
+		case "query":
+			query = append(query, p)
+		}
+	}
+	pathExpr = strings.TrimSuffix(strings.ReplaceAll(pathExpr, ` + ""`, ""), ` + ""`)
+
+	queryExpr := "nil"
+	if len(query) > 0 {
+		paramsType := g.reserveType(name + "Params")
+		g.writeParams(buf, paramsType, name, query)
+		args = append(args, "params *"+paramsType)
+		queryExpr = "params.values()"
+	}
+
+	bodyExpr, contentType := "nil", ""
+	if op.RequestBody != nil {
+		if media := op.RequestBody.Content["application/json"]; media != nil {
+			g.docs[name+"Request"] = name + " 的请求体"
+			typ := g.goType(media.Schema, name+"Request", false)
+			if isStructType(typ, g) {
+				typ = "*" + typ
+			}
+			args = append(args, "body "+typ)
+			bodyExpr = "body"
+		} else if op.RequestBody.Content["multipart/form-data"] != nil {
+			// multipart 请求由调用方构造（mime/multipart.Writer），contentType 含 boundary
+			args = append(args, "contentType string", "body io.Reader")
+			g.imports["io"] = true
+			bodyExpr, contentType = "body", "multipart"
+		}
+	}
+
+	// 返回值：取第一个非 202 的成功响应（202 由 PendingApprovalError 表示）
+	var resp *Response
+	for _, code := range sortedKeys(op.Responses) {
+		if strings.HasPrefix(code, "2") && code != "202" {
+			resp = op.Responses[code]
+			break
+		}
+	}
+	result, binary := "", false
+	if resp != nil {
+		if media := resp.Content["application/json"]; media != nil {
+			if data := media.Schema.Properties.Get("data"); data != nil {
+				g.docs[name+"Result"] = name + " 的响应数据"
+				g.docs[name+"ResultItem"] = name + " 的响应数据元素"
+				result = g.goType(data, name+"Result", false)
+			}
+		} else if len(resp.Content) > 0 {
+			binary = true
+			result = "[]byte"
+		}
+	}
+
+	summary := op.Summary
+	if op.Description != "" {
+		summary += "\n\n" + op.Description
+	}
+	route := op.method + " " + op.path
+	if op.Permission != "" {
+		route += "（权限 " + op.Permission + "）"
+	}
+	writeComment(buf, "", name, summary+"\n\n"+route)
+
+	method := "http.Method" + methodConst(op.method)
+	call := func(out string) string {
+		switch {
+		case contentType == "multipart":
+			return fmt.Sprintf("c.doMultipart(ctx, %s, %s, %s, contentType, body, %s)", method, pathExpr, queryExpr, out)
+		case binary:
+			return fmt.Sprintf("c.doRaw(ctx, %s, %s, %s, %s)", method, pathExpr, queryExpr, bodyExpr)
+		default:
+			return fmt.Sprintf("c.do(ctx, %s, %s, %s, %s, %s)", method, pathExpr, queryExpr, bodyExpr, out)
+		}
+	}
+	sig := fmt.Sprintf("func (c *Client) %s(%s)", name, strings.Join(args, ", "))
+	switch {
+	case binary:
+		if contentType == "multipart" {
+			return fmt.Errorf("操作 %s 同时为 multipart 请求和二进制响应，客户端不支持", name)
+		}
+		fmt.Fprintf(buf, "%s ([]byte, error) {\n\treturn %s\n}\n\n", sig, call(""))
+	case result == "":
+		fmt.Fprintf(buf, "%s error {\n\treturn %s\n}\n\n", sig, call("nil"))
+	case isStructType(result, g):
+		fmt.Fprintf(buf, "%s (*%s, error) {\n\tvar out %s\n\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n",
+			sig, result, result, call("&out"))
+	default:
+		fmt.Fprintf(buf, "%s (%s, error) {\n\tvar out %s\n\terr := %s\n\treturn out, err\n}\n\n",
+			sig, result, result, call("&out"))
+	}
+	return nil
+}
+
+// writeParams 输出查询参数结构体和编码方法，零值参数不发送
+func (g *clientGen) writeParams(buf *bytes.Buffer, typeName, opName string, params []*Parameter) {
+	writeComment(buf, "", typeName, opName+" 的查询参数")
+	fmt.Fprintf(buf, "type %s struct {\n", typeName)
+	used := make(map[string]bool)
+	fields := make([]string, len(params))
+	for i, p := range params {
+		field := uniqueName(goName(p.Name), used)
+		used[field] = true
+		fields[i] = field
+		typ := g.goType(p.Schema, typeName+field, false)
+		if strings.HasPrefix(typ, "[]") {
+			typ = "[]string"
+		} else if typ != "int" && typ != "int32" && typ != "int64" && typ != "bool" && typ != "float64" && typ != "float32" {
+			typ = "string"
+		}
+		desc := fieldDescription(p.Schema)
+		if p.Schema != nil && p.Schema.Default != "" {
+			desc = appendNote(desc, "服务端默认 "+p.Schema.Default)
+		}
+		if p.Required {
+			desc = appendNote(desc, "必填")
+		}
+		if desc != "" {
+			writeComment(buf, "\t", "", desc)
+		}
+		fmt.Fprintf(buf, "\t%s %s\n", field, typ)
+	}
+	buf.WriteString("}\n\n")
+
+	g.imports["net/url"] = true
+	fmt.Fprintf(buf, "func (p *%s) values() url.Values {\n\tq := url.Values{}\n\tif p == nil {\n\t\treturn q\n\t}\n", typeName)
+	for i, p := range params {
+		if p.Schema != nil && p.Schema.Type == "array" {
+			fmt.Fprintf(buf, "\taddQueryArray(q, %q, p.%s)\n", p.Name, fields[i])
+		} else {
+			fmt.Fprintf(buf, "\tsetQuery(q, %q, p.%s)\n", p.Name, fields[i])
+		}
+	}
+	buf.WriteString("\treturn q\n}\n\n")
+}
+
+// reserveType 登记方法专用的类型名
+func (g *clientGen) reserveType(name string) string {
+	base := name
+	for i := 2; g.types[name] || reservedNames[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.types[name] = true
+	return name
+}
+
+// isStructType 判断生成的类型是否为结构体（组件或内联对象）
+func isStructType(typ string, g *clientGen) bool {
+	if !g.types[typ] {
+		return false
+	}
+	if s := g.doc.Components.Schemas[typ]; s != nil {
+		return len(s.Properties) > 0
+	}
+	return true
+}
+
+func isPointer(typ string) bool {
+	return strings.HasPrefix(typ, "*")
+}
+
+// fieldDescription 字段注释：说明和枚举值
+func fieldDescription(s *Schema) string {
+	if s == nil {
+		return ""
+	}
+	desc := s.Description
+	enum := s.Enum
+	if len(s.AllOf) == 1 && desc == "" {
+		desc = s.AllOf[0].Description
+	}
+	if s.Type == "array" && s.Items != nil && len(enum) == 0 {
+		enum = s.Items.Enum
+	}
+	if len(enum) > 0 {
+		desc = appendNote(desc, "可选值："+strings.Join(enum, ", "))
+	}
+	return desc
+}
+
+// appendNote 在说明后追加括号备注，说明为空时直接使用备注
+func appendNote(desc, note string) string {
+	if desc == "" {
+		return note
+	}
+	return desc + "（" + note + "）"
+}
+
+// writeComment 输出注释；name 非空时作为首行前缀
+func writeComment(buf *bytes.Buffer, indent, name, text string) {
+	text = strings.TrimSpace(text)
+	if name != "" {
+		if text == "" {
+			text = name
+		} else {
+			text = name + " " + text
+		}
+	}
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			fmt.Fprintf(buf, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(buf, "%s// %s\n", indent, line)
+	}
+}
+
+// goName 把 JSON 字段名转换为导出的 Go 标识符，如 host_id -> HostID
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		if word, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(word)
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	out := b.String()
+	if out == "" || unicode.IsDigit([]rune(out)[0]) {
+		out = "X" + out
+	}
+	return out
+}
+
+// lowerName 把导出标识符转换为参数名，如 HostID -> hostID、ID -> id
+func lowerName(name string) string {
+	r := []rune(name)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		i++
+	}
+	switch {
+	case i == len(r):
+		name = strings.ToLower(name)
+	case i > 1:
+		name = strings.ToLower(string(r[:i-1])) + string(r[i-1:])
+	default:
+		name = strings.ToLower(string(r[:1])) + string(r[1:])
+	}
+	switch name {
+	case "ctx", "params", "body", "contentType", "c", "type", "func", "range", "map", "package", "default":
+		name += "Param"
+	}
+	return name
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	for used[name] {
+		name += "_"
+	}
+	return name
+}
+
+func methodConst(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
@@ -0,0 +1,38 @@
+package apispec
+
+// 生成文件相对模块根目录的路径
+const (
+	SpecFile   = "api/openapi/openapi.json"
+	ClientFile = "api/client/v1/zz_generated.go"
+)
+
+// File 生成的文件
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Generate 生成 OpenAPI 文档和 Go 客户端
+func Generate(dir string) ([]File, error) {
+	doc, err := Build(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Render(doc)
+}
+
+// Render 输出文档和客户端文件内容
+func Render(doc *Document) ([]File, error) {
+	spec, err := doc.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	client, err := GenerateClient(doc)
+	if err != nil {
+		return nil, err
+	}
+	return []File{
+		{Path: SpecFile, Content: spec},
+		{Path: ClientFile, Content: client},
+	}, nil
+}
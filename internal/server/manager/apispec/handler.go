@@ -0,0 +1,562 @@
+package apispec
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// queryParam 处理器读取的查询参数
+type queryParam struct {
+	name     string
+	def      string
+	array    bool
+	required bool
+	schema   *Schema
+}
+
+// responseInfo 同一状态码的响应
+type responseInfo struct {
+	envelope     *Schema  // JSON 响应结构（含 code/message/data）
+	contentTypes []string // 非 JSON 响应的内容类型
+}
+
+// handlerAnalysis 分析处理器（及其调用的 api 包辅助函数）读取的参数和写出的响应
+type handlerAnalysis struct {
+	b      *builder
+	info   *types.Info
+	method string
+
+	body        types.Type
+	queryStruct types.Type
+	query       []queryParam
+	form        Properties
+	multipart   bool
+	responses   map[int]*responseInfo
+	contentType string // c.Header("Content-Type", ...) 声明的内容类型
+	rawWriter   bool   // 直接写 c.Writer（如 CSV 流式导出）
+
+	visited map[*ast.FuncDecl]bool
+	stack   []*ast.FuncDecl
+}
+
+func newHandlerAnalysis(b *builder, method string) *handlerAnalysis {
+	return &handlerAnalysis{
+		b:         b,
+		info:      b.pkg.TypesInfo,
+		method:    method,
+		responses: make(map[int]*responseInfo),
+		visited:   make(map[*ast.FuncDecl]bool),
+	}
+}
+
+// responseHelpers api 包中的统一响应函数（见 response.go），按语义处理而不跟进函数体
+var responseHelpers = map[string]bool{
+	"Success": true, "SuccessWithMessage": true, "SuccessMessage": true, "SuccessPaginated": true, "Created": true,
+	"BadRequest": true, "NotFound": true, "Conflict": true, "InternalError": true, "Unauthorized": true, "Forbidden": true,
+	"approvalSubmitted": true,
+}
+
+func (a *handlerAnalysis) analyze(decl *ast.FuncDecl, depth int) {
+	if a.visited[decl] || depth > 6 {
+		return
+	}
+	a.visited[decl] = true
+	a.stack = append(a.stack, decl)
+	defer func() { a.stack = a.stack[:len(a.stack)-1] }()
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// 闭包（如 goroutine、事务回调）中不会写响应
+			return false
+		case *ast.CallExpr:
+			a.call(n, depth)
+		}
+		return true
+	})
+}
+
+func (a *handlerAnalysis) call(call *ast.CallExpr, depth int) {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if a.isContext(fun.X) {
+			a.contextCall(fun.Sel.Name, call)
+			return
+		}
+		// c.Request.FormFile / c.Writer.WriteString
+		if inner, ok := fun.X.(*ast.SelectorExpr); ok && a.isContext(inner.X) {
+			switch {
+			case inner.Sel.Name == "Request" && (fun.Sel.Name == "FormFile" || fun.Sel.Name == "FormValue" || fun.Sel.Name == "ParseMultipartForm"):
+				a.formCall(fun.Sel.Name, call)
+			case inner.Sel.Name == "Writer" && (fun.Sel.Name == "Write" || fun.Sel.Name == "WriteString"):
+				a.rawWriter = true
+			}
+			return
+		}
+		if fn, ok := a.info.Uses[fun.Sel].(*types.Func); ok {
+			a.otherCall(fn, call, depth)
+		}
+	case *ast.Ident:
+		if fn, ok := a.info.Uses[fun].(*types.Func); ok {
+			a.otherCall(fn, call, depth)
+		}
+	}
+}
+
+// otherCall 处理统一响应函数，跟进接收 *gin.Context 的 api 包函数，识别 c.Writer 作为参数的流式输出
+func (a *handlerAnalysis) otherCall(fn *types.Func, call *ast.CallExpr, depth int) {
+	passesContext := false
+	for _, arg := range call.Args {
+		if a.isContext(arg) {
+			passesContext = true
+		}
+		if sel, ok := arg.(*ast.SelectorExpr); ok && sel.Sel.Name == "Writer" && a.isContext(sel.X) &&
+			!(fn.Pkg() != nil && fn.Pkg().Path() == "net/http" && fn.Name() == "SetCookie") {
+			a.rawWriter = true
+		}
+	}
+	if fn.Pkg() == nil || fn.Pkg().Path() != apiPkg || !passesContext {
+		return
+	}
+	if responseHelpers[fn.Name()] {
+		a.helperCall(fn.Name(), call)
+		return
+	}
+	if decl := a.b.funcs[fn]; decl != nil {
+		a.analyze(decl, depth+1)
+	}
+}
+
+// helperCall 按 response.go 中的响应结构记录响应
+func (a *handlerAnalysis) helperCall(name string, call *ast.CallExpr) {
+	args := call.Args
+	switch name {
+	case "Success":
+		a.addJSON(http.StatusOK, envelope(a.exprSchema(args[1]), false))
+	case "SuccessWithMessage":
+		a.addJSON(http.StatusOK, envelope(a.exprSchema(args[2]), true))
+	case "SuccessMessage":
+		a.addJSON(http.StatusOK, envelope(nil, true))
+	case "SuccessPaginated":
+		a.addJSON(http.StatusOK, envelope(paginated(a.exprSchema(args[2])), false))
+	case "Created":
+		a.addJSON(http.StatusCreated, envelope(a.exprSchema(args[1]), false))
+	case "approvalSubmitted":
+		data := a.exprSchema(args[len(args)-1])
+		if data == nil || data.Type != "object" || data.Properties == nil {
+			data = &Schema{Type: "object"}
+		}
+		data.Properties = append(data.Properties,
+			Property{Name: "approval_id", Schema: &Schema{Type: "integer", Format: "int64", Description: "审批单 ID"}},
+			Property{Name: "status", Schema: &Schema{Type: "string", Description: "审批单状态（pending）"}},
+		)
+		a.addJSON(http.StatusAccepted, envelope(data, true))
+	}
+}
+
+// contextCall 处理 *gin.Context 的方法调用
+func (a *handlerAnalysis) contextCall(name string, call *ast.CallExpr) {
+	args := call.Args
+	switch name {
+	case "ShouldBindJSON", "BindJSON":
+		a.body = a.boundType(args[0])
+	case "ShouldBind", "Bind":
+		if a.method == http.MethodGet || a.method == http.MethodDelete {
+			a.queryStruct = a.boundType(args[0])
+		} else {
+			a.body = a.boundType(args[0])
+		}
+	case "ShouldBindQuery", "BindQuery":
+		a.queryStruct = a.boundType(args[0])
+	case "Query", "GetQuery":
+		a.addQuery(queryParam{name: a.stringConst(args[0])})
+	case "DefaultQuery":
+		a.addQuery(queryParam{name: a.stringConst(args[0]), def: a.stringConst(args[1])})
+	case "QueryArray", "GetQueryArray":
+		a.addQuery(queryParam{name: a.stringConst(args[0]), array: true})
+	case "PostForm", "DefaultPostForm", "GetPostForm", "PostFormArray", "FormFile", "MultipartForm":
+		a.formCall(name, call)
+	case "Header":
+		if len(args) == 2 && a.stringConst(args[0]) == "Content-Type" {
+			a.contentType = a.stringConst(args[1])
+		}
+	case "JSON", "IndentedJSON", "PureJSON":
+		if status, ok := a.intConst(args[0]); ok {
+			a.addJSON(status, a.envelopeExpr(args[1]))
+		}
+	case "Data":
+		if status, ok := a.intConst(args[0]); ok {
+			ct := a.stringConst(args[1])
+			if ct == "" {
+				ct = a.contentType
+			}
+			a.addRaw(status, ct)
+		}
+	case "String":
+		if status, ok := a.intConst(args[0]); ok {
+			ct := a.contentType
+			if ct == "" {
+				ct = "text/plain"
+			}
+			a.addRaw(status, ct)
+		}
+	case "File", "FileAttachment":
+		a.addRaw(http.StatusOK, a.contentType)
+	case "DataFromReader":
+		if status, ok := a.intConst(args[0]); ok {
+			a.addRaw(status, a.stringConst(args[2]))
+		}
+	case "Redirect":
+		if status, ok := a.intConst(args[0]); ok {
+			a.responses[status] = &responseInfo{}
+		}
+	}
+}
+
+// formCall 记录 multipart 表单字段
+func (a *handlerAnalysis) formCall(name string, call *ast.CallExpr) {
+	a.multipart = true
+	if len(call.Args) == 0 {
+		return
+	}
+	field := a.stringConst(call.Args[0])
+	if field == "" || a.form.Get(field) != nil {
+		return
+	}
+	schema := &Schema{Type: "string"}
+	switch name {
+	case "FormFile":
+		schema.Format = "binary"
+	case "PostFormArray":
+		schema = &Schema{Type: "array", Items: &Schema{Type: "string"}}
+	}
+	a.form = append(a.form, Property{Name: field, Schema: schema})
+}
+
+func (a *handlerAnalysis) addQuery(p queryParam) {
+	if p.name == "" {
+		return
+	}
+	for _, q := range a.query {
+		if q.name == p.name {
+			return
+		}
+	}
+	a.query = append(a.query, p)
+}
+
+// addJSON 记录 JSON 响应；同一状态码以带 data 的响应为准
+func (a *handlerAnalysis) addJSON(status int, env *Schema) {
+	if status < 200 || status >= 300 || env == nil {
+		return
+	}
+	if existing := a.responses[status]; existing != nil && existing.envelope != nil &&
+		(existing.envelope.Properties.Get("data") != nil || env.Properties.Get("data") == nil) {
+		return
+	}
+	a.responses[status] = &responseInfo{envelope: env}
+}
+
+// addRaw 记录文件下载等非 JSON 响应
+func (a *handlerAnalysis) addRaw(status int, contentType string) {
+	if status < 200 || status >= 300 {
+		return
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp := a.responses[status]
+	if resp == nil {
+		resp = &responseInfo{}
+		a.responses[status] = resp
+	}
+	for _, ct := range resp.contentTypes {
+		if ct == contentType {
+			return
+		}
+	}
+	resp.contentTypes = append(resp.contentTypes, contentType)
+}
+
+// apply 把分析结果写入操作
+func (a *handlerAnalysis) apply(op *Operation) {
+	var params []queryParam
+	if a.queryStruct != nil {
+		for _, f := range a.b.schemas.fields(a.queryStruct, "form") {
+			params = append(params, queryParam{name: f.name, required: f.required, schema: f.schema})
+		}
+	}
+	params = append(params, a.query...)
+	seen := make(map[string]bool)
+	for _, p := range params {
+		if seen[p.name] {
+			continue
+		}
+		seen[p.name] = true
+		schema := p.schema
+		if schema == nil {
+			schema = &Schema{Type: "string", Default: p.def}
+			if p.array {
+				schema = &Schema{Type: "array", Items: &Schema{Type: "string"}}
+			}
+		}
+		op.Parameters = append(op.Parameters, &Parameter{Name: p.name, In: "query", Required: p.required, Schema: schema})
+	}
+
+	switch {
+	case a.multipart:
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"multipart/form-data": {Schema: &Schema{Type: "object", Properties: a.form}}},
+		}
+	case a.body != nil:
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: a.b.schemas.schemaFor(a.body)}},
+		}
+	}
+
+	op.Responses = map[string]*Response{"default": {Ref: "#/components/responses/Error"}}
+	if len(a.responses) == 0 && a.rawWriter {
+		a.addRaw(http.StatusOK, a.contentType)
+	}
+	statuses := make([]int, 0, len(a.responses))
+	for status := range a.responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		info := a.responses[status]
+		resp := &Response{Description: statusDescription(status)}
+		switch {
+		case info.envelope != nil:
+			resp.Content = map[string]*MediaType{"application/json": {Schema: info.envelope}}
+		case len(info.contentTypes) > 0:
+			resp.Content = make(map[string]*MediaType)
+			for _, ct := range info.contentTypes {
+				resp.Content[ct] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+	if len(statuses) == 0 {
+		op.Responses["200"] = &Response{Description: statusDescription(http.StatusOK)}
+	}
+}
+
+func statusDescription(status int) string {
+	switch status {
+	case http.StatusOK:
+		return "成功"
+	case http.StatusCreated:
+		return "已创建"
+	case http.StatusAccepted:
+		return "操作需要审批，已提交审批申请"
+	case http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect:
+		return "重定向"
+	}
+	return http.StatusText(status)
+}
+
+// envelope 统一响应结构 {code, message, data}
+func envelope(data *Schema, message bool) *Schema {
+	s := &Schema{Type: "object", Required: []string{"code"}}
+	s.Properties = append(s.Properties, Property{Name: "code", Schema: &Schema{Type: "integer"}})
+	if message {
+		s.Properties = append(s.Properties, Property{Name: "message", Schema: &Schema{Type: "string"}})
+	}
+	if data != nil {
+		s.Properties = append(s.Properties, Property{Name: "data", Schema: data})
+	}
+	return s
+}
+
+// paginated 分页数据 {total, items}
+func paginated(items *Schema) *Schema {
+	if items == nil {
+		items = &Schema{}
+	}
+	return &Schema{
+		Type: "object",
+		Properties: Properties{
+			{Name: "total", Schema: &Schema{Type: "integer", Format: "int64"}},
+			{Name: "items", Schema: items},
+		},
+		Required: []string{"total", "items"},
+	}
+}
+
+// envelopeExpr 处理 c.JSON 的响应体：gin.H 字面量按键展开，其他表达式按类型生成
+func (a *handlerAnalysis) envelopeExpr(expr ast.Expr) *Schema {
+	s := a.exprSchema(expr)
+	if s == nil {
+		return nil
+	}
+	for i, p := range s.Properties {
+		if p.Name == "code" {
+			s.Properties[i].Schema = &Schema{Type: "integer"}
+			s.Required = []string{"code"}
+		}
+	}
+	return s
+}
+
+// exprSchema 返回表达式的结构；nil 字面量返回 nil
+func (a *handlerAnalysis) exprSchema(expr ast.Expr) *Schema {
+	expr = ast.Unparen(expr)
+	if ident, ok := expr.(*ast.Ident); ok && ident.Name == "nil" && a.info.Uses[ident] == types.Universe.Lookup("nil") {
+		return nil
+	}
+	if lit, ok := expr.(*ast.CompositeLit); ok && isObjectMap(a.info.TypeOf(lit)) {
+		return a.mapLiteral(lit, nil)
+	}
+	if ident, ok := expr.(*ast.Ident); ok && isObjectMap(a.info.TypeOf(ident)) {
+		if s := a.localMap(a.info.Uses[ident]); s != nil {
+			return s
+		}
+	}
+	if call, ok := expr.(*ast.CallExpr); ok {
+		// 类型转换或函数调用按结果类型处理
+		if t := a.info.TypeOf(call); t != nil {
+			return a.b.schemas.schemaFor(t)
+		}
+	}
+	t := a.info.TypeOf(expr)
+	if t == nil {
+		return &Schema{}
+	}
+	return a.b.schemas.schemaFor(t)
+}
+
+// mapLiteral 把 gin.H 字面量展开为对象，键按源码顺序
+func (a *handlerAnalysis) mapLiteral(lit *ast.CompositeLit, extra []*ast.AssignStmt) *Schema {
+	s := &Schema{Type: "object", Properties: Properties{}}
+	add := func(key ast.Expr, value ast.Expr) {
+		name := a.stringConst(key)
+		if name == "" {
+			return
+		}
+		schema := a.exprSchema(value)
+		if schema == nil {
+			schema = &Schema{Nullable: true}
+		}
+		for i, p := range s.Properties {
+			if p.Name == name {
+				s.Properties[i].Schema = schema
+				return
+			}
+		}
+		s.Properties = append(s.Properties, Property{Name: name, Schema: schema})
+	}
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			add(kv.Key, kv.Value)
+		}
+	}
+	for _, assign := range extra {
+		index := assign.Lhs[0].(*ast.IndexExpr)
+		add(index.Index, assign.Rhs[0])
+	}
+	return s
+}
+
+// localMap 查找当前函数中 gin.H 局部变量的字面量定义和后续的 m["key"] = v 赋值
+func (a *handlerAnalysis) localMap(obj types.Object) *Schema {
+	if obj == nil || len(a.stack) == 0 {
+		return nil
+	}
+	var lit *ast.CompositeLit
+	var extra []*ast.AssignStmt
+	ast.Inspect(a.stack[len(a.stack)-1].Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			switch lhs := n.Lhs[0].(type) {
+			case *ast.Ident:
+				if l, ok := ast.Unparen(n.Rhs[0]).(*ast.CompositeLit); ok && lit == nil && a.objectOf(lhs) == obj {
+					lit = l
+				}
+			case *ast.IndexExpr:
+				if ident, ok := lhs.X.(*ast.Ident); ok && a.info.Uses[ident] == obj && n.Tok == token.ASSIGN {
+					extra = append(extra, n)
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if a.info.Defs[name] == obj && i < len(n.Values) {
+					if l, ok := ast.Unparen(n.Values[i]).(*ast.CompositeLit); ok {
+						lit = l
+					}
+				}
+			}
+		}
+		return true
+	})
+	if lit == nil {
+		return nil
+	}
+	return a.mapLiteral(lit, extra)
+}
+
+// boundType 返回 ShouldBindJSON(&req) 等绑定目标的类型
+func (a *handlerAnalysis) boundType(expr ast.Expr) types.Type {
+	t := a.info.TypeOf(expr)
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+func (a *handlerAnalysis) isContext(expr ast.Expr) bool {
+	p, ok := a.info.TypeOf(expr).(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := p.Elem().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/gin-gonic/gin" && named.Obj().Name() == "Context"
+}
+
+func (a *handlerAnalysis) objectOf(ident *ast.Ident) types.Object {
+	if obj := a.info.Defs[ident]; obj != nil {
+		return obj
+	}
+	return a.info.Uses[ident]
+}
+
+func (a *handlerAnalysis) stringConst(expr ast.Expr) string {
+	tv, ok := a.info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+func (a *handlerAnalysis) intConst(expr ast.Expr) (int, bool) {
+	tv, ok := a.info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	v, ok := constant.Int64Val(tv.Value)
+	return int(v), ok
+}
+
+// isObjectMap 判断是否为 gin.H 或 map[string]interface{}，这类字面量按键展开为对象
+func isObjectMap(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	m, ok := types.Unalias(t).Underlying().(*types.Map)
+	if !ok {
+		return false
+	}
+	key, ok := m.Key().(*types.Basic)
+	iface, isIface := types.Unalias(m.Elem()).Underlying().(*types.Interface)
+	return ok && key.Kind() == types.String && isIface && iface.Empty()
+}
@@ -0,0 +1,253 @@
+package apispec
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// route router 包中注册的一条路由
+type route struct {
+	method     string
+	path       string // 完整路径（含 /api/v1 前缀）
+	handler    string // 处理器 接收者类型.方法名
+	permission string // can(...) 声明的权限
+	audit      string // audited(...) 声明的审计 action
+	auth       bool   // 是否经过 AuthMiddleware
+}
+
+// group 路由分组：路径前缀和是否已挂载认证中间件
+type group struct {
+	prefix string
+	auth   bool
+}
+
+var routeMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+// routeWalker 从 Setup 开始按调用顺序遍历路由注册代码
+type routeWalker struct {
+	pkg    *packages.Package
+	decls  map[types.Object]*ast.FuncDecl
+	routes []route
+	err    error
+}
+
+// extractRoutes 提取 router 包注册的所有路由
+// 分组通过 Group/Use 调用跟踪，setupXAPI 等函数的 *gin.RouterGroup 参数按调用处的实参传递
+func extractRoutes(pkg *packages.Package) ([]route, error) {
+	w := &routeWalker{pkg: pkg, decls: make(map[types.Object]*ast.FuncDecl)}
+	var setup *ast.FuncDecl
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil {
+				continue
+			}
+			w.decls[pkg.TypesInfo.Defs[fn.Name]] = fn
+			if fn.Name.Name == "Setup" {
+				setup = fn
+			}
+		}
+	}
+	if setup == nil {
+		return nil, fmt.Errorf("router 包中未找到 Setup 函数")
+	}
+	w.walk(setup, make(map[types.Object]*group), 0)
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.routes, nil
+}
+
+func (w *routeWalker) walk(fn *ast.FuncDecl, groups map[types.Object]*group, depth int) {
+	if depth > 8 {
+		w.fail(fn, "路由注册函数调用层级过深")
+		return
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if w.err != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+				if g := w.groupOf(n.Rhs[0], groups); g != nil {
+					if obj := w.objectOf(n.Lhs[0]); obj != nil {
+						groups[obj] = g
+					}
+				}
+			}
+		case *ast.CallExpr:
+			w.call(n, groups, depth)
+		}
+		return true
+	})
+}
+
+// groupOf 识别 gin.New()/gin.Default() 和 x.Group("...")，返回新的分组
+func (w *routeWalker) groupOf(expr ast.Expr, groups map[types.Object]*group) *group {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if fn, ok := w.pkg.TypesInfo.Uses[sel.Sel].(*types.Func); ok && fn.Pkg() != nil &&
+		fn.Pkg().Path() == "github.com/gin-gonic/gin" && (fn.Name() == "New" || fn.Name() == "Default") {
+		return &group{}
+	}
+	if sel.Sel.Name != "Group" || len(call.Args) == 0 {
+		return nil
+	}
+	parent := groups[w.objectOf(sel.X)]
+	if parent == nil {
+		return nil
+	}
+	g := &group{prefix: joinPath(parent.prefix, w.stringConst(call.Args[0])), auth: parent.auth}
+	for _, arg := range call.Args[1:] {
+		if isAuthMiddleware(arg) {
+			g.auth = true
+		}
+	}
+	return g
+}
+
+// call 处理路由注册、Use 和对其他注册函数的调用
+func (w *routeWalker) call(call *ast.CallExpr, groups map[types.Object]*group, depth int) {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		g := groups[w.objectOf(fun.X)]
+		if g == nil {
+			return
+		}
+		if fun.Sel.Name == "Use" {
+			for _, arg := range call.Args {
+				if isAuthMiddleware(arg) {
+					g.auth = true
+				}
+			}
+			return
+		}
+		if routeMethods[fun.Sel.Name] && len(call.Args) >= 2 {
+			w.addRoute(fun.Sel.Name, g, call)
+		}
+	case *ast.Ident:
+		callee := w.decls[w.pkg.TypesInfo.Uses[fun]]
+		if callee == nil {
+			return
+		}
+		bound := make(map[types.Object]*group)
+		i := 0
+		for _, field := range callee.Type.Params.List {
+			for _, name := range field.Names {
+				if i < len(call.Args) {
+					if g := groups[w.objectOf(call.Args[i])]; g != nil {
+						copied := *g
+						bound[w.pkg.TypesInfo.Defs[name]] = &copied
+					}
+				}
+				i++
+			}
+		}
+		if len(bound) > 0 {
+			w.walk(callee, bound, depth+1)
+		}
+	}
+}
+
+// addRoute 解析 path、中间件参数和处理器
+func (w *routeWalker) addRoute(method string, g *group, call *ast.CallExpr) {
+	r := route{
+		method: method,
+		path:   joinPath(g.prefix, w.stringConst(call.Args[0])),
+		auth:   g.auth,
+	}
+	for _, arg := range call.Args[1 : len(call.Args)-1] {
+		if isAuthMiddleware(arg) {
+			r.auth = true
+			continue
+		}
+		mw, ok := arg.(*ast.CallExpr)
+		if !ok || len(mw.Args) != 1 {
+			continue
+		}
+		if ident, ok := mw.Fun.(*ast.Ident); ok {
+			switch ident.Name {
+			case "can":
+				r.permission = w.stringConst(mw.Args[0])
+			case "audited":
+				r.audit = w.stringConst(mw.Args[0])
+			}
+		}
+	}
+
+	// 处理器为方法值，gin.WrapH 等包装的处理器不收录
+	sel, ok := call.Args[len(call.Args)-1].(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	selection := w.pkg.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return
+	}
+	fn, ok := selection.Obj().(*types.Func)
+	if !ok {
+		return
+	}
+	r.handler = methodKey(fn)
+	w.routes = append(w.routes, r)
+}
+
+func (w *routeWalker) objectOf(expr ast.Expr) types.Object {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if obj := w.pkg.TypesInfo.Defs[ident]; obj != nil {
+		return obj
+	}
+	return w.pkg.TypesInfo.Uses[ident]
+}
+
+func (w *routeWalker) stringConst(expr ast.Expr) string {
+	tv, ok := w.pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		w.fail(expr, "需要字符串常量")
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+func (w *routeWalker) fail(node ast.Node, msg string) {
+	if w.err == nil {
+		w.err = fmt.Errorf("%s: %s", w.pkg.Fset.Position(node.Pos()), msg)
+	}
+}
+
+// isAuthMiddleware 判断参数是否为 xxx.AuthMiddleware() 调用
+func isAuthMiddleware(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "AuthMiddleware"
+}
+
+func joinPath(prefix, p string) string {
+	if p == "" {
+		return prefix
+	}
+	joined := path.Join(prefix, p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
@@ -0,0 +1,368 @@
+package apispec
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// specialTypes JSON 编码与 Go 类型结构不一致的类型
+var specialTypes = map[string]func() *Schema{
+	"time.Time": func() *Schema { return &Schema{Type: "string", Format: "date-time"} },
+	modulePath + "/internal/server/model.LocalTime": func() *Schema {
+		return &Schema{Type: "string", Format: "date-time", Description: "格式 2006-01-02 15:04:05"}
+	},
+	"gorm.io/gorm.DeletedAt":     func() *Schema { return &Schema{Type: "string", Format: "date-time", Nullable: true} },
+	"encoding/json.RawMessage":   func() *Schema { return &Schema{} },
+	"github.com/gin-gonic/gin.H": func() *Schema { return &Schema{Type: "object", AdditionalProperties: &Schema{}} },
+	"mime/multipart.FileHeader":  func() *Schema { return &Schema{Type: "string", Format: "binary"} },
+	"google.golang.org/protobuf/types/known/timestamppb.Timestamp": func() *Schema {
+		return &Schema{Type: "string", Format: "date-time"}
+	},
+}
+
+// schemaRegistry 把 Go 类型转换为 JSON Schema，命名结构体登记为 components.schemas
+type schemaRegistry struct {
+	dir        string
+	fset       *token.FileSet
+	components map[string]*Schema
+	names      map[string]string // 类型全名 -> 组件名
+	owners     map[string]string // 组件名 -> 类型全名
+	comments   map[string]map[int]string
+}
+
+func newSchemaRegistry(dir string, fset *token.FileSet) *schemaRegistry {
+	return &schemaRegistry{
+		dir:        dir,
+		fset:       fset,
+		components: make(map[string]*Schema),
+		names:      make(map[string]string),
+		owners:     make(map[string]string),
+		comments:   make(map[string]map[int]string),
+	}
+}
+
+// schemaFor 返回类型对应的结构
+func (r *schemaRegistry) schemaFor(t types.Type) *Schema {
+	t = types.Unalias(t)
+	switch t := t.(type) {
+	case *types.Pointer:
+		return r.schemaFor(t.Elem())
+	case *types.Named:
+		return r.namedSchema(t)
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if b, ok := types.Unalias(t.Elem()).(*types.Basic); ok && b.Kind() == types.Byte {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case *types.Array:
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case *types.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case *types.Struct:
+		return r.structSchema(t)
+	}
+	// interface{}、函数等任意值
+	return &Schema{}
+}
+
+func (r *schemaRegistry) namedSchema(t *types.Named) *Schema {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		// error 等内置类型
+		return &Schema{Type: "string"}
+	}
+	full := obj.Pkg().Path() + "." + obj.Name()
+	if special, ok := specialTypes[full]; ok {
+		return special()
+	}
+	if t.TypeArgs().Len() > 0 {
+		return r.schemaFor(t.Underlying())
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		name, ok := r.names[full]
+		if !ok {
+			name = r.componentName(obj)
+			r.names[full] = name
+			r.owners[name] = full
+			// 先占位以支持递归类型
+			r.components[name] = &Schema{}
+			s := r.structSchema(u)
+			s.Description = r.docOf(obj.Pos(), obj.Name())
+			r.components[name] = s
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	case *types.Basic:
+		s := basicSchema(u)
+		s.Enum = r.enumValues(t)
+		return s
+	default:
+		return r.schemaFor(u)
+	}
+}
+
+// componentName 组件名默认为类型名，不同包同名时加包名前缀
+func (r *schemaRegistry) componentName(obj *types.TypeName) string {
+	name := obj.Name()
+	if _, taken := r.owners[name]; !taken {
+		return name
+	}
+	pkg := []rune(obj.Pkg().Name())
+	pkg[0] = unicode.ToUpper(pkg[0])
+	return string(pkg) + name
+}
+
+// enumValues 收集与命名类型同包、同类型的字符串常量作为枚举值
+func (r *schemaRegistry) enumValues(t *types.Named) []string {
+	if b, ok := t.Underlying().(*types.Basic); !ok || b.Info()&types.IsString == 0 {
+		return nil
+	}
+	scope := t.Obj().Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && types.Identical(c.Type(), t) && c.Val().Kind() == constant.String {
+			consts = append(consts, c)
+		}
+	}
+	sort.SliceStable(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+	var values []string
+	seen := make(map[string]bool)
+	for _, c := range consts {
+		v := constant.StringVal(c.Val())
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func basicSchema(t *types.Basic) *Schema {
+	switch t.Kind() {
+	case types.Bool, types.UntypedBool:
+		return &Schema{Type: "boolean"}
+	case types.Int, types.Int64, types.Uint, types.Uint64, types.Uint32, types.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case types.UntypedInt, types.UntypedRune:
+		return &Schema{Type: "integer"}
+	case types.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case types.Float64, types.UntypedFloat:
+		return &Schema{Type: "number", Format: "double"}
+	case types.String, types.UntypedString:
+		return &Schema{Type: "string"}
+	}
+	return &Schema{}
+}
+
+// structField 按 encoding/json 规则展开后的结构体字段
+type structField struct {
+	name     string
+	schema   *Schema
+	required bool
+	depth    int
+}
+
+// structSchema 生成对象结构，字段说明取自字段注释
+func (r *schemaRegistry) structSchema(t *types.Struct) *Schema {
+	s := &Schema{Type: "object", Properties: Properties{}}
+	for _, f := range r.fields(t, "json") {
+		s.Properties = append(s.Properties, Property{Name: f.name, Schema: f.schema})
+		if f.required {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s
+}
+
+// fields 按 tag（json 或 form）展开字段：匿名嵌入的结构体字段提升到外层，同名时浅层优先
+func (r *schemaRegistry) fields(t types.Type, tag string) []structField {
+	var all []structField
+	r.collectFields(t, tag, 0, &all)
+	best := make(map[string]int)
+	for _, f := range all {
+		if d, ok := best[f.name]; !ok || f.depth < d {
+			best[f.name] = f.depth
+		}
+	}
+	var out []structField
+	seen := make(map[string]bool)
+	for _, f := range all {
+		if f.depth == best[f.name] && !seen[f.name] {
+			seen[f.name] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (r *schemaRegistry) collectFields(t types.Type, tag string, depth int, out *[]structField) {
+	t = types.Unalias(t)
+	if p, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(p.Elem())
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok || depth > 8 {
+		return
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tags := reflect.StructTag(st.Tag(i))
+		name, opts, _ := strings.Cut(tags.Get(tag), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Embedded() && name == "" {
+			ft := types.Unalias(field.Type())
+			if p, ok := ft.(*types.Pointer); ok {
+				ft = types.Unalias(p.Elem())
+			}
+			if _, isStruct := ft.Underlying().(*types.Struct); isStruct {
+				if _, special := specialTypes[typeName(ft)]; !special {
+					r.collectFields(ft, tag, depth+1, out)
+					continue
+				}
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			if tag == "form" {
+				// 查询参数只收录显式声明 form 标签的字段
+				continue
+			}
+			name = field.Name()
+		}
+		var schema *Schema
+		if strings.Contains(","+opts+",", ",string,") {
+			schema = &Schema{Type: "string"}
+		} else {
+			schema = r.schemaFor(field.Type())
+		}
+		// 指针字段可为 null；$ref 不能带其他属性，需要时用 allOf 包装
+		_, nullable := types.Unalias(field.Type()).(*types.Pointer)
+		if doc := r.docOf(field.Pos(), field.Name()); doc != "" || nullable {
+			if schema.Ref != "" {
+				schema = &Schema{AllOf: []*Schema{schema}}
+			} else {
+				copied := *schema
+				schema = &copied
+			}
+			schema.Nullable = schema.Nullable || nullable
+			if doc != "" {
+				schema.Description = doc
+			}
+		}
+		*out = append(*out, structField{
+			name:     name,
+			schema:   schema,
+			required: hasRequired(tags.Get("binding")),
+			depth:    depth,
+		})
+	}
+}
+
+func hasRequired(binding string) bool {
+	for _, rule := range strings.Split(binding, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+// docOf 返回声明（类型或字段）所在行的注释，去掉开头的标识符名
+// 只读取模块内的源文件，依赖库中的类型不带说明
+func (r *schemaRegistry) docOf(pos token.Pos, name string) string {
+	if !pos.IsValid() {
+		return ""
+	}
+	p := r.fset.Position(pos)
+	lines := r.fileComments(p.Filename)
+	doc := strings.TrimSpace(lines[p.Line])
+	if rest, ok := strings.CutPrefix(doc, name); ok && (rest == "" || rest[0] == ' ') {
+		doc = strings.TrimSpace(rest)
+	}
+	return doc
+}
+
+// fileComments 解析源文件，返回 行号 -> 该行声明的类型或字段的注释
+func (r *schemaRegistry) fileComments(filename string) map[int]string {
+	if lines, ok := r.comments[filename]; ok {
+		return lines
+	}
+	lines := make(map[int]string)
+	r.comments[filename] = lines
+
+	path := filename
+	if rest, ok := strings.CutPrefix(filename, modulePath+"/"); ok {
+		path = filepath.Join(r.dir, rest)
+	}
+	rel, err := filepath.Rel(r.dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return lines
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return lines
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return lines
+	}
+	text := func(groups ...*ast.CommentGroup) string {
+		for _, g := range groups {
+			if g != nil {
+				if t := strings.TrimSpace(g.Text()); t != "" {
+					return strings.Join(strings.Fields(strings.ReplaceAll(t, "\n", " ")), " ")
+				}
+			}
+		}
+		return ""
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			if n.Tok == token.TYPE && len(n.Specs) == 1 {
+				spec := n.Specs[0].(*ast.TypeSpec)
+				lines[fset.Position(spec.Name.Pos()).Line] = text(spec.Doc, n.Doc, spec.Comment)
+			}
+		case *ast.TypeSpec:
+			if _, ok := lines[fset.Position(n.Name.Pos()).Line]; !ok {
+				lines[fset.Position(n.Name.Pos()).Line] = text(n.Doc, n.Comment)
+			}
+		case *ast.Field:
+			if len(n.Names) > 0 {
+				lines[fset.Position(n.Names[0].Pos()).Line] = text(n.Doc, n.Comment)
+			} else {
+				lines[fset.Position(n.Type.Pos()).Line] = text(n.Doc, n.Comment)
+			}
+		}
+		return true
+	})
+	return lines
+}
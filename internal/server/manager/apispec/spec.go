@@ -0,0 +1,208 @@
+// Package apispec 从 Manager 路由和处理器源码生成 OpenAPI 3 文档和 Go 客户端
+//
+// 文档由源码推导：路由、权限和审计 action 来自 router 包，请求体、查询参数和响应数据的类型来自 api 包的处理器，
+// 字段说明来自结构体字段注释。生成结果提交在 api/openapi/openapi.json 和 api/client/v1，
+// 契约测试在处理器形状变化而未重新生成时失败（make openapi 重新生成）
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Document OpenAPI 3 文档（只包含本项目用到的字段）
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info 文档信息
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server 服务地址
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag 操作分组
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem 单个路径下的操作
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation 接口操作
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+	Permission  string                `json:"x-permission,omitempty"`   // 所需 RBAC 权限
+	AuditAction string                `json:"x-audit-action,omitempty"` // 审计日志 action
+
+	method  string
+	path    string
+	handler string
+}
+
+// Parameter 路径或查询参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType 内容类型对应的结构
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components 可复用的结构定义
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Schema JSON Schema 子集；Properties 保持字段声明顺序
+type Schema struct {
+	Ref                  string     `json:"$ref,omitempty"`
+	AllOf                []*Schema  `json:"allOf,omitempty"`
+	Type                 string     `json:"type,omitempty"`
+	Format               string     `json:"format,omitempty"`
+	Description          string     `json:"description,omitempty"`
+	Enum                 []string   `json:"enum,omitempty"`
+	Default              string     `json:"default,omitempty"`
+	Items                *Schema    `json:"items,omitempty"`
+	Properties           Properties `json:"properties,omitempty"`
+	AdditionalProperties *Schema    `json:"additionalProperties,omitempty"`
+	Required             []string   `json:"required,omitempty"`
+	Nullable             bool       `json:"nullable,omitempty"`
+}
+
+// Property 对象字段
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties 按声明顺序排列的对象字段
+type Properties []Property
+
+// MarshalJSON 按声明顺序输出字段
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		schema, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Get 按名称查找字段
+func (p Properties) Get(name string) *Schema {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema
+		}
+	}
+	return nil
+}
+
+// Marshal 输出格式化的 JSON 文档（以换行结尾）
+func (d *Document) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// operations 按路径和方法顺序返回所有操作
+func (d *Document) operations() []*Operation {
+	var ops []*Operation
+	for _, path := range sortedKeys(d.Paths) {
+		item := d.Paths[path]
+		for _, op := range []*Operation{item.Get, item.Post, item.Put, item.Patch, item.Delete} {
+			if op != nil {
+				ops = append(ops, op)
+			}
+		}
+	}
+	return ops
+}
+
+// setOperation 按方法写入操作
+func (p *PathItem) setOperation(method string, op *Operation) {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "POST":
+		p.Post = op
+	case "PUT":
+		p.Put = op
+	case "PATCH":
+		p.Patch = op
+	case "DELETE":
+		p.Delete = op
+	}
+}
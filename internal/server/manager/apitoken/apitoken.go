@@ -0,0 +1,115 @@
+// Package apitoken 提供 API Token（个人访问令牌和服务账号令牌）的生成、校验和权限收窄
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+const (
+	// Scheme API Token 明文前缀，Authorization 头以此开头时按 API Token 认证，否则按 JWT 认证
+	Scheme = "mxs_"
+	// ContextKey 当前请求使用的 API Token ID 在 gin 上下文中的键
+	ContextKey = "api_token_id"
+	// prefixLen 保存到数据库用于识别的明文前缀长度
+	prefixLen = 12
+	// touchInterval 最近使用时间的更新间隔，避免每个请求都写数据库
+	touchInterval = time.Minute
+)
+
+var (
+	// ErrInvalid Token 不存在或格式错误
+	ErrInvalid = errors.New("Token无效")
+	// ErrRevoked Token 已吊销
+	ErrRevoked = errors.New("Token已吊销")
+	// ErrExpired Token 已过期
+	ErrExpired = errors.New("Token已过期")
+)
+
+// IsToken 判断 Authorization 头（可带 "Bearer " 前缀）是否为 API Token
+func IsToken(header string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(header, "Bearer "), Scheme)
+}
+
+// Generate 生成新 Token，返回明文（仅返回给用户一次）、识别前缀和哈希
+func Generate() (plaintext, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	plaintext = Scheme + hex.EncodeToString(b)
+	return plaintext, plaintext[:prefixLen], Hash(plaintext), nil
+}
+
+// Hash 计算 Token 明文的哈希（Token 为高熵随机值，无需加盐和慢哈希）
+func Hash(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate 校验 Authorization 头中的 API Token，返回有效的 Token 记录
+func Authenticate(db *gorm.DB, header string) (*model.APIToken, error) {
+	plaintext := strings.TrimPrefix(header, "Bearer ")
+	if !strings.HasPrefix(plaintext, Scheme) {
+		return nil, ErrInvalid
+	}
+	var token model.APIToken
+	if err := db.Where("token_hash = ?", Hash(plaintext)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalid
+		}
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return nil, ErrRevoked
+	}
+	if token.ExpiresAt != nil && !token.ExpiresAt.Time().After(time.Now()) {
+		return nil, ErrExpired
+	}
+	return &token, nil
+}
+
+// Restrict 将所属用户的权限收窄到 Token 限定的权限，Token 未限定时返回用户全部权限
+func Restrict(userPerms []rbac.Permission, scoped []string) []rbac.Permission {
+	if len(scoped) == 0 {
+		return userPerms
+	}
+	result := make([]rbac.Permission, 0, len(scoped))
+	for _, p := range userPerms {
+		for _, s := range scoped {
+			if string(p) == s {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Touch 更新 Token 最近使用时间和来源 IP（间隔不足 touchInterval 时跳过）
+func Touch(db *gorm.DB, token *model.APIToken, ip string) error {
+	now := time.Now()
+	if token.LastUsedAt != nil && now.Sub(token.LastUsedAt.Time()) < touchInterval && token.LastUsedIP == ip {
+		return nil
+	}
+	return db.Model(token).UpdateColumns(map[string]interface{}{
+		"last_used_at": model.ToLocalTime(now),
+		"last_used_ip": ip,
+	}).Error
+}
+
+// RevokeOwner 吊销用户的全部 Token（删除用户时调用，避免同名用户重建后继承旧 Token）
+func RevokeOwner(db *gorm.DB, owner string) error {
+	return db.Model(&model.APIToken{}).
+		Where("owner = ? AND revoked_at IS NULL", owner).
+		UpdateColumn("revoked_at", model.Now()).Error
+}
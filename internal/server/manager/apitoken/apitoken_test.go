@@ -0,0 +1,47 @@
+package apitoken
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+)
+
+// TestGenerate 测试生成的 Token 格式、前缀和哈希
+func TestGenerate(t *testing.T) {
+	plaintext, prefix, hash, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasPrefix(plaintext, Scheme) || len(plaintext) != len(Scheme)+64 {
+		t.Errorf("plaintext = %q", plaintext)
+	}
+	if !strings.HasPrefix(plaintext, prefix) || len(prefix) != prefixLen {
+		t.Errorf("prefix = %q", prefix)
+	}
+	if hash != Hash(plaintext) || strings.Contains(hash, plaintext) {
+		t.Errorf("hash = %q", hash)
+	}
+	if !IsToken("Bearer "+plaintext) || IsToken("Bearer eyJhbGciOiJIUzI1NiJ9") {
+		t.Error("IsToken() should distinguish API tokens from JWTs")
+	}
+
+	other, _, _, _ := Generate()
+	if other == plaintext {
+		t.Error("Generate() should return distinct tokens")
+	}
+}
+
+// TestRestrict 测试 Token 权限与所属用户权限取交集
+func TestRestrict(t *testing.T) {
+	user := []rbac.Permission{rbac.HostsRead, rbac.PoliciesRead}
+	if got := Restrict(user, nil); !reflect.DeepEqual(got, user) {
+		t.Errorf("Restrict(nil) = %v, want all user permissions", got)
+	}
+	// 用户角色降权后 Token 的权限随之收窄
+	got := Restrict(user, []string{string(rbac.HostsRead), string(rbac.UsersManage)})
+	if !reflect.DeepEqual(got, []rbac.Permission{rbac.HostsRead}) {
+		t.Errorf("Restrict() = %v, want [hosts:read]", got)
+	}
+}
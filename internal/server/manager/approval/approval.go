@@ -0,0 +1,141 @@
+// Package approval 提供高危操作的双人审批：审批策略配置、审批单的创建和状态流转
+// 命中审批策略的操作不会立即执行，由另一位拥有相同权限的用户批准后才生效
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+const (
+	// ConfigKey 审批策略在 system_configs 中的键
+	ConfigKey = "approval_config"
+	// ConfigCategory 审批策略在 system_configs 中的分类
+	ConfigCategory = "approval"
+)
+
+// 需要审批的操作，与审计日志的 action 一致
+const (
+	ActionFixCreate    = "fix.create"
+	ActionAgentPush    = "component.push_update"
+	ActionHostDelete   = "host.delete"
+	ActionPolicyDelete = "policy.delete"
+)
+
+var (
+	// ErrDuplicate 同一资源已有待审批的申请
+	ErrDuplicate = errors.New("该操作已有待审批的申请")
+	// ErrNotPending 审批单已被处理（批准、驳回或撤销）
+	ErrNotPending = errors.New("审批单已处理")
+)
+
+// LoadConfig 从系统配置读取审批策略，未配置时返回默认策略（全部关闭）
+func LoadConfig(db *gorm.DB) (model.ApprovalConfig, error) {
+	cfg := model.DefaultApprovalConfig()
+	var sc model.SystemConfig
+	err := db.Where("`key` = ? AND category = ?", ConfigKey, ConfigCategory).First(&sc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal([]byte(sc.Value), &cfg); err != nil {
+		return model.DefaultApprovalConfig(), err
+	}
+	return cfg, nil
+}
+
+// FixTaskReason 判断修复任务是否命中审批规则，命中时返回原因，否则返回空字符串
+// severities 为待修复规则的严重级别
+func FixTaskReason(rule model.FixTaskApprovalRule, hostCount int, severities []string) string {
+	if !rule.Enabled {
+		return ""
+	}
+	if hostCount > rule.HostThreshold {
+		if rule.HostThreshold == 0 {
+			return "所有修复任务都需要审批"
+		}
+		return fmt.Sprintf("目标主机数 %d 超过 %d", hostCount, rule.HostThreshold)
+	}
+	for _, s := range rule.Severities {
+		if slices.Contains(severities, s) {
+			return "包含 " + s + " 级别的规则"
+		}
+	}
+	return ""
+}
+
+// Submit 创建待审批的审批单，同一操作和资源已有待审批的申请时返回 ErrDuplicate
+// ResourceID 为空（如批量删除）时不检查重复
+func Submit(db *gorm.DB, req *model.ApprovalRequest) error {
+	req.Status = model.ApprovalStatusPending
+	if req.ResourceID == "" {
+		return db.Create(req).Error
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.ApprovalRequest{}).
+			Where("action = ? AND resource_id = ? AND status = ?", req.Action, req.ResourceID, model.ApprovalStatusPending).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDuplicate
+		}
+		return tx.Create(req).Error
+	})
+}
+
+// Decide 将待审批的审批单转为 to 状态（批准、驳回或撤销），已被处理时返回 ErrNotPending
+// 使用条件更新，多人同时审批时只有一人成功
+func Decide(db *gorm.DB, req *model.ApprovalRequest, to model.ApprovalStatus, reviewer, comment string) error {
+	now := model.Now()
+	result := db.Model(&model.ApprovalRequest{}).
+		Where("id = ? AND status = ?", req.ID, model.ApprovalStatusPending).
+		Updates(map[string]interface{}{
+			"status":         to,
+			"reviewed_by":    reviewer,
+			"review_comment": comment,
+			"reviewed_at":    &now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotPending
+	}
+	req.Status = to
+	req.ReviewedBy = reviewer
+	req.ReviewComment = comment
+	req.ReviewedAt = &now
+	return nil
+}
+
+// MarkFailed 记录已批准的操作执行失败
+func MarkFailed(db *gorm.DB, req *model.ApprovalRequest, cause error) error {
+	req.Status = model.ApprovalStatusFailed
+	req.ErrorMessage = cause.Error()
+	return db.Model(&model.ApprovalRequest{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+		"status":        req.Status,
+		"error_message": req.ErrorMessage,
+	}).Error
+}
+
+// CancelByResource 撤销资源上待审批的申请（如修复任务在审批前被取消或删除）
+func CancelByResource(db *gorm.DB, action, resourceID, operator string) error {
+	now := model.Now()
+	return db.Model(&model.ApprovalRequest{}).
+		Where("action = ? AND resource_id = ? AND status = ?", action, resourceID, model.ApprovalStatusPending).
+		Updates(map[string]interface{}{
+			"status":      model.ApprovalStatusCancelled,
+			"reviewed_by": operator,
+			"reviewed_at": &now,
+		}).Error
+}
@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestDecideConcurrent 测试多人同时处理同一审批单时只有一人成功，其余返回 ErrNotPending
+func TestDecideConcurrent(t *testing.T) {
+	db := testdb.Open(t, &model.ApprovalRequest{})
+	req := &model.ApprovalRequest{Action: ActionHostDelete, ResourceID: "host-1", Permission: "hosts:delete", RequestedBy: "alice"}
+	if err := Submit(db, req); err != nil {
+		t.Fatal(err)
+	}
+
+	const reviewers = 8
+	var wg sync.WaitGroup
+	errs := make([]error, reviewers)
+	for i := 0; i < reviewers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			to := model.ApprovalStatusApproved
+			if i%2 == 1 {
+				to = model.ApprovalStatusRejected
+			}
+			r := *req
+			errs[i] = Decide(db, &r, to, fmt.Sprintf("reviewer-%d", i), "")
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrNotPending):
+			t.Errorf("reviewer-%d: unexpected error: %v", i, err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d reviewers succeeded, want exactly 1", succeeded)
+	}
+
+	var got model.ApprovalRequest
+	if err := db.First(&got, req.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Status == model.ApprovalStatusPending || got.ReviewedBy == "" {
+		t.Fatalf("approval not decided: %+v", got)
+	}
+	if err := Decide(db, req, model.ApprovalStatusCancelled, "alice", ""); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("cancel after decision: err = %v, want ErrNotPending", err)
+	}
+}
@@ -0,0 +1,29 @@
+package approval
+
+import (
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+func TestFixTaskReason(t *testing.T) {
+	rule := model.FixTaskApprovalRule{Enabled: true, HostThreshold: 10, Severities: []string{"critical"}}
+	tests := []struct {
+		name       string
+		rule       model.FixTaskApprovalRule
+		hosts      int
+		severities []string
+		want       bool
+	}{
+		{"未启用", model.FixTaskApprovalRule{HostThreshold: 0}, 100, []string{"critical"}, false},
+		{"主机数未超过阈值", rule, 10, []string{"high"}, false},
+		{"主机数超过阈值", rule, 11, []string{"low"}, true},
+		{"包含严重级别", rule, 1, []string{"low", "critical"}, true},
+		{"阈值为 0 时全部审批", model.FixTaskApprovalRule{Enabled: true}, 1, nil, true},
+	}
+	for _, tt := range tests {
+		if got := FixTaskReason(tt.rule, tt.hosts, tt.severities) != ""; got != tt.want {
+			t.Errorf("%s: FixTaskReason() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
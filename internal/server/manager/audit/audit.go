@@ -0,0 +1,291 @@
+// Package audit 提供操作审计日志：记录认证事件和关键变更操作，只追加写入，可选哈希链防篡改
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// contextKey 审计条目在 gin 上下文中的键
+const contextKey = "audit_entry"
+
+// tokenRequestAction 通过 API Token 发起、且路由未标记操作的请求（包括只读请求）使用的审计操作
+const tokenRequestAction = "token.request"
+
+// maxCapturedBody 为提取失败原因而缓存的响应体上限
+const maxCapturedBody = 4096
+
+// entry 单次请求的审计条目，由 Action 标记操作，处理器通过 SetActor/AddTargets/SetChange 补充
+type entry struct {
+	action  string
+	actor   string
+	targets []string
+	changes model.AuditChanges
+	failure string
+	tokenID uint
+}
+
+// Recorder 审计日志记录器
+type Recorder struct {
+	db        *gorm.DB
+	logger    *zap.Logger
+	hashChain bool
+	mu        sync.Mutex // 串行化本实例内的追加写入，保证哈希链顺序
+}
+
+// NewRecorder 创建审计日志记录器，hashChain 为 true 时每条记录链接上一条记录的哈希
+func NewRecorder(db *gorm.DB, logger *zap.Logger, hashChain bool) *Recorder {
+	return &Recorder{
+		db:        db,
+		logger:    logger,
+		hashChain: hashChain,
+	}
+}
+
+// Handler 审计中间件，需挂在 API 路由组最外层（认证中间件之前）
+// 仅当路由通过 Action 标记了操作，或请求使用 API Token 认证时才写入审计日志，权限拒绝、参数错误等失败请求同样记录
+func (r *Recorder) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		e := &entry{}
+		c.Set(contextKey, e)
+		w := &bodyWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		if e.action == "" {
+			if e.tokenID == 0 {
+				return
+			}
+			e.action = tokenRequestAction
+		}
+		r.record(c, e, w)
+	}
+}
+
+// record 根据请求上下文和响应生成审计日志并写入
+func (r *Recorder) record(c *gin.Context, e *entry, w *bodyWriter) {
+	actor := e.actor
+	if actor == "" {
+		actor = c.GetString("username")
+	}
+
+	targets := append([]string{}, e.targets...)
+	for _, p := range c.Params {
+		targets = append(targets, p.Value)
+	}
+
+	status := w.Status()
+	result, message := model.AuditResultSuccess, ""
+	if code, msg := parseResponse(w.body.Bytes()); status >= http.StatusBadRequest || code != 0 {
+		result, message = model.AuditResultFailure, truncate(msg, 512)
+	}
+	if e.failure != "" {
+		result, message = model.AuditResultFailure, truncate(e.failure, 512)
+	}
+
+	resource := e.action
+	if i := strings.Index(resource, "."); i > 0 {
+		resource = resource[:i]
+	}
+
+	log := &model.AuditLog{
+		Actor:      actor,
+		ActorRole:  c.GetString("role"),
+		SourceIP:   c.ClientIP(), // 只信任 server.http.trusted_proxies 转发的 X-Forwarded-For（路由引擎配置）
+		Action:     e.action,
+		Resource:   resource,
+		TargetIDs:  dedupe(targets),
+		Method:     c.Request.Method,
+		Path:       truncate(c.Request.URL.Path, 255),
+		StatusCode: status,
+		Result:     result,
+		Message:    message,
+		Changes:    e.changes,
+		TokenID:    e.tokenID,
+	}
+	if err := r.Append(log); err != nil {
+		r.logger.Error("写入审计日志失败",
+			zap.String("action", log.Action),
+			zap.String("actor", log.Actor),
+			zap.Error(err))
+	}
+}
+
+// Append 追加一条审计日志；开启哈希链时在事务内锁定最后一条记录，
+// 保证多实例并发写入时链不分叉
+func (r *Recorder) Append(log *model.AuditLog) error {
+	// 时间截断到秒，与数据库 timestamp 精度一致，保证校验时哈希可复现
+	log.CreatedAt = model.ToLocalTime(time.Now().Truncate(time.Second))
+	if log.TargetIDs == nil {
+		log.TargetIDs = model.StringArray{}
+	}
+	if log.Changes == nil {
+		log.Changes = model.AuditChanges{}
+	}
+	if !r.hashChain {
+		return r.db.Create(log).Error
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var last model.AuditLog
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "hash").Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		log.PrevHash = last.Hash
+		hash, err := ComputeHash(log)
+		if err != nil {
+			return err
+		}
+		log.Hash = hash
+		return tx.Create(log).Error
+	})
+}
+
+// Action 返回标记审计操作的中间件，需放在权限校验中间件之前，以便记录被拒绝的操作
+func Action(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if e := current(c); e != nil {
+			e.action = action
+		}
+		c.Next()
+	}
+}
+
+// SetActor 设置操作人（用于登录等认证前的请求）
+func SetActor(c *gin.Context, actor string) {
+	if e := current(c); e != nil {
+		e.actor = actor
+	}
+}
+
+// SetToken 记录本次请求使用的 API Token，该请求无论是否标记操作都会写入审计日志
+func SetToken(c *gin.Context, tokenID uint) {
+	if e := current(c); e != nil {
+		e.tokenID = tokenID
+	}
+}
+
+// SetFailure 将操作标记为失败（用于以重定向等非错误状态码结束的失败请求）
+func SetFailure(c *gin.Context, reason string) {
+	if e := current(c); e != nil {
+		e.failure = reason
+	}
+}
+
+// AddTargets 追加操作目标 ID（路径参数会自动记录，这里用于请求体中的目标或新建资源的 ID）
+func AddTargets(c *gin.Context, ids ...string) {
+	if e := current(c); e != nil {
+		e.targets = append(e.targets, ids...)
+	}
+}
+
+// SetChange 记录变更前后的差异，新建时 before 为 nil，删除时 after 为 nil
+func SetChange(c *gin.Context, before, after interface{}) {
+	e := current(c)
+	if e == nil {
+		return
+	}
+	changes, err := Diff(before, after)
+	if err != nil {
+		return
+	}
+	e.changes = changes
+}
+
+// SetField 追加单个字段的变更（用于不出现在 JSON 中的字段，如密码），敏感字段同样脱敏
+func SetField(c *gin.Context, field string, before, after interface{}) {
+	e := current(c)
+	if e == nil {
+		return
+	}
+	if e.changes == nil {
+		e.changes = model.AuditChanges{}
+	}
+	e.changes[field] = model.AuditChange{Before: redact(field, before), After: redact(field, after)}
+}
+
+// current 获取当前请求的审计条目，未挂载审计中间件时返回 nil
+func current(c *gin.Context) *entry {
+	v, ok := c.Get(contextKey)
+	if !ok {
+		return nil
+	}
+	e, _ := v.(*entry)
+	return e
+}
+
+// bodyWriter 缓存响应体前若干字节，用于提取失败原因
+type bodyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 实现 io.Writer 接口
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	if remain := maxCapturedBody - w.body.Len(); remain > 0 {
+		if len(b) > remain {
+			w.body.Write(b[:remain])
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 实现 io.StringWriter 接口
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// parseResponse 解析统一响应格式中的 code 和 message，非 JSON 响应返回零值
+func parseResponse(body []byte) (int, string) {
+	var resp struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &resp) != nil {
+		return 0, ""
+	}
+	return resp.Code, resp.Message
+}
+
+// dedupe 去除空值和重复值，保持原有顺序
+func dedupe(values []string) model.StringArray {
+	seen := make(map[string]struct{}, len(values))
+	result := make(model.StringArray, 0, len(values))
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// truncate 按字符截断字符串
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
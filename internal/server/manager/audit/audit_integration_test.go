@@ -0,0 +1,56 @@
+//go:build integration
+// +build integration
+
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+	"github.com/imkerbos/mxsec-platform/internal/server/testdb"
+)
+
+// TestSourceIPTrustedProxies 测试审计日志的来源 IP 只在请求来自可信代理时使用 X-Forwarded-For
+func TestSourceIPTrustedProxies(t *testing.T) {
+	const remoteIP = "198.51.100.7"
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		want           string
+	}{
+		{"no trusted proxies", nil, remoteIP},
+		{"trusted proxy", []string{remoteIP}, "203.0.113.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.Open(t, &model.AuditLog{})
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.trustedProxies); err != nil {
+				t.Fatal(err)
+			}
+			router.Use(NewRecorder(db, zap.NewNop(), false).Handler())
+			router.POST("/api/v1/users", Action("user.create"), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"code": 0})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			req.RemoteAddr = remoteIP + ":40000"
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			var log model.AuditLog
+			if err := db.First(&log).Error; err != nil {
+				t.Fatal(err)
+			}
+			if log.SourceIP != tt.want {
+				t.Errorf("source ip = %s, want %s", log.SourceIP, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// TestDiff 测试差异只包含变化字段，且敏感字段被脱敏
+func TestDiff(t *testing.T) {
+	type user struct {
+		Name      string            `json:"name"`
+		Email     string            `json:"email"`
+		Password  string            `json:"password"`
+		Config    map[string]string `json:"config"`
+		UpdatedAt string            `json:"updated_at"`
+	}
+	before := &user{Name: "alice", Email: "a@example.com", Password: "old", Config: map[string]string{"secret": "s1", "url": "u"}, UpdatedAt: "t1"}
+	after := &user{Name: "alice", Email: "b@example.com", Password: "new", Config: map[string]string{"secret": "s2", "url": "u"}, UpdatedAt: "t2"}
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if _, ok := changes["name"]; ok {
+		t.Error("unchanged field name should not be recorded")
+	}
+	if _, ok := changes["updated_at"]; ok {
+		t.Error("updated_at should be ignored")
+	}
+	if got := changes["email"]; got.Before != "a@example.com" || got.After != "b@example.com" {
+		t.Errorf("email change = %+v", got)
+	}
+	if got := changes["password"]; got.Before != redacted || got.After != redacted {
+		t.Errorf("password should be redacted, got %+v", got)
+	}
+	if cfg, _ := changes["config"].After.(map[string]interface{}); cfg["secret"] != redacted || cfg["url"] != "u" {
+		t.Errorf("nested secret should be redacted, got %+v", changes["config"].After)
+	}
+
+	created, err := Diff(nil, after)
+	if err != nil {
+		t.Fatalf("Diff(nil, after) error = %v", err)
+	}
+	if got := created["name"]; got.Before != nil || got.After != "alice" {
+		t.Errorf("create change = %+v", got)
+	}
+}
+
+// TestComputeHash 测试哈希覆盖记录内容和上一条哈希
+func TestComputeHash(t *testing.T) {
+	log := &model.AuditLog{
+		Actor:     "admin",
+		Action:    "policy.update",
+		TargetIDs: model.StringArray{"p1"},
+		Result:    model.AuditResultSuccess,
+		Changes:   model.AuditChanges{"name": {Before: "a", After: "b"}},
+		CreatedAt: model.ToLocalTime(time.Unix(1700000000, 0)),
+	}
+	h1, err := ComputeHash(log)
+	if err != nil {
+		t.Fatalf("ComputeHash() error = %v", err)
+	}
+	if h2, _ := ComputeHash(log); h1 != h2 {
+		t.Error("hash should be deterministic")
+	}
+
+	tampered := *log
+	tampered.Actor = "someone"
+	if h, _ := ComputeHash(&tampered); h == h1 {
+		t.Error("hash should change when content changes")
+	}
+
+	tokened := *log
+	tokened.TokenID = 7
+	if h, _ := ComputeHash(&tokened); h == h1 {
+		t.Error("hash should change when token id is set")
+	}
+
+	relinked := *log
+	relinked.PrevHash = "deadbeef"
+	if h, _ := ComputeHash(&relinked); h == h1 {
+		t.Error("hash should change when previous hash changes")
+	}
+}
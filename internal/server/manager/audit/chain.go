@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// errChainBroken 校验发现断链时用于提前结束批量遍历
+var errChainBroken = errors.New("audit chain broken")
+
+// VerifyResult 哈希链校验结果
+type VerifyResult struct {
+	Valid     bool   `json:"valid"`
+	Checked   int    `json:"checked"`             // 已校验的链上记录数
+	Unchained int    `json:"unchained"`           // 未开启哈希链时写入的记录数（不参与校验）
+	BrokenID  uint   `json:"broken_id,omitempty"` // 第一条校验失败的记录 ID
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ComputeHash 计算审计日志的哈希：sha256(上一条哈希 + 记录内容的规范化 JSON)
+// 不包含自增 ID，记录被删除后下一条记录的 PrevHash 将无法匹配
+func ComputeHash(log *model.AuditLog) (string, error) {
+	canonical := struct {
+		Actor      string             `json:"actor"`
+		ActorRole  string             `json:"actor_role"`
+		SourceIP   string             `json:"source_ip"`
+		Action     string             `json:"action"`
+		TargetIDs  model.StringArray  `json:"target_ids"`
+		Method     string             `json:"method"`
+		Path       string             `json:"path"`
+		StatusCode int                `json:"status_code"`
+		Result     model.AuditResult  `json:"result"`
+		Message    string             `json:"message"`
+		Changes    model.AuditChanges `json:"changes"`
+		TokenID    uint               `json:"token_id,omitempty"` // 为零时省略，保持该字段加入前写入的记录哈希不变
+		CreatedAt  int64              `json:"created_at"`
+	}{
+		Actor:      log.Actor,
+		ActorRole:  log.ActorRole,
+		SourceIP:   log.SourceIP,
+		Action:     log.Action,
+		TargetIDs:  log.TargetIDs,
+		Method:     log.Method,
+		Path:       log.Path,
+		StatusCode: log.StatusCode,
+		Result:     log.Result,
+		Message:    log.Message,
+		Changes:    log.Changes,
+		TokenID:    log.TokenID,
+		CreatedAt:  log.CreatedAt.Time().Unix(),
+	}
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(log.PrevHash), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify 按 ID 顺序重新计算哈希链，返回第一条被篡改或前序记录被删除的位置
+// 注意：删除链尾记录无法通过哈希链本身发现，需结合外部留存的最新哈希比对
+func Verify(db *gorm.DB) (*VerifyResult, error) {
+	result := &VerifyResult{Valid: true}
+	prevHash := ""
+	var batch []model.AuditLog
+	err := db.Model(&model.AuditLog{}).Order("id ASC").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			log := &batch[i]
+			if log.Hash == "" {
+				result.Unchained++
+				prevHash = ""
+				continue
+			}
+			if log.PrevHash != prevHash {
+				result.Valid, result.BrokenID, result.Reason = false, log.ID, "上一条记录哈希不匹配，可能存在记录被删除"
+				return errChainBroken
+			}
+			hash, err := ComputeHash(log)
+			if err != nil {
+				return err
+			}
+			if hash != log.Hash {
+				result.Valid, result.BrokenID, result.Reason = false, log.ID, "记录哈希不匹配，内容可能被篡改"
+				return errChainBroken
+			}
+			result.Checked++
+			prevHash = log.Hash
+		}
+		return nil
+	}).Error
+	if err != nil && err != errChainBroken {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// redacted 敏感字段的占位值
+const redacted = "******"
+
+// ignoredFields 不参与差异比较的字段
+var ignoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// sensitiveKeywords 字段名包含这些关键字时值会被脱敏
+var sensitiveKeywords = []string{"password", "secret", "token", "private_key"}
+
+// Diff 比较两个对象序列化为 JSON 后的顶层字段，返回发生变化的字段
+// before 为 nil 时返回 after 的全部字段（新建），after 为 nil 时返回 before 的全部字段（删除）
+func Diff(before, after interface{}) (model.AuditChanges, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := model.AuditChanges{}
+	for k, v := range b {
+		if ignoredFields[k] {
+			continue
+		}
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(v, av) {
+			changes[k] = model.AuditChange{Before: redact(k, v), After: redact(k, a[k])}
+		}
+	}
+	for k, v := range a {
+		if ignoredFields[k] {
+			continue
+		}
+		if _, ok := b[k]; !ok {
+			changes[k] = model.AuditChange{Before: nil, After: redact(k, v)}
+		}
+	}
+	return changes, nil
+}
+
+// toMap 将对象序列化为 JSON 再解析为 map，nil 返回空 map
+func toMap(v interface{}) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return m, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// redact 对敏感字段脱敏，嵌套对象递归处理
+func redact(key string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	lower := strings.ToLower(key)
+	for _, kw := range sensitiveKeywords {
+		if strings.Contains(lower, kw) {
+			if s, ok := v.(string); ok && s == "" {
+				return s
+			}
+			return redacted
+		}
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		out := make(map[string]interface{}, len(m))
+		for k, mv := range m {
+			out[k] = redact(k, mv)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,224 @@
+package biz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// ApprovalEvent 审批通知事件
+type ApprovalEvent string
+
+const (
+	ApprovalEventSubmitted ApprovalEvent = "submitted" // 已提交，通知审批人
+	ApprovalEventApproved  ApprovalEvent = "approved"  // 已批准并执行
+	ApprovalEventRejected  ApprovalEvent = "rejected"  // 已驳回
+	ApprovalEventFailed    ApprovalEvent = "failed"    // 已批准但执行失败
+)
+
+// approvalEventTitles 审批通知标题和卡片颜色
+var approvalEventTitles = map[ApprovalEvent][2]string{
+	ApprovalEventSubmitted: {"🔐 高危操作待审批", "orange"},
+	ApprovalEventApproved:  {"✅ 高危操作已批准", "green"},
+	ApprovalEventRejected:  {"⛔ 高危操作已驳回", "grey"},
+	ApprovalEventFailed:    {"❌ 高危操作执行失败", "red"},
+}
+
+// SendApprovalNotification 通过审批类别的通知渠道发送审批通知
+// 提交时提醒审批人处理，批准、驳回或执行失败时告知申请人
+func (s *NotificationService) SendApprovalNotification(req *model.ApprovalRequest, event ApprovalEvent) error {
+	var notifications []model.Notification
+	if err := s.db.Where("enabled = ? AND notify_category = ?", true, model.NotifyCategoryApproval).Find(&notifications).Error; err != nil {
+		s.logger.Error("查询通知配置失败", zap.Error(err))
+		return err
+	}
+
+	for _, notification := range notifications {
+		if !s.matchApprovalScope(&notification, req) {
+			continue
+		}
+		if err := s.sendApprovalNotification(&notification, req, event); err != nil {
+			s.logger.Error("发送审批通知失败",
+				zap.Uint("notification_id", notification.ID),
+				zap.Uint("approval_id", req.ID),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// matchApprovalScope 检查审批单的目标是否在通知的主机范围内
+func (s *NotificationService) matchApprovalScope(notification *model.Notification, req *model.ApprovalRequest) bool {
+	if notification.Scope == model.NotificationScopeGlobal || notification.Scope == model.NotificationScopeHostTags {
+		return true
+	}
+	var scopeValue model.ScopeValueData
+	if err := json.Unmarshal([]byte(notification.ScopeValue), &scopeValue); err != nil {
+		return false
+	}
+	switch notification.Scope {
+	case model.NotificationScopeBusinessLine:
+		return slices.ContainsFunc(req.BusinessLines, func(bl string) bool {
+			return slices.Contains(scopeValue.BusinessLines, bl)
+		})
+	case model.NotificationScopeSpecified:
+		return slices.ContainsFunc(req.Targets, func(id string) bool {
+			return slices.Contains(scopeValue.HostIDs, id)
+		})
+	default:
+		return false
+	}
+}
+
+// sendApprovalNotification 发送单个审批通知
+func (s *NotificationService) sendApprovalNotification(
+	notification *model.Notification,
+	req *model.ApprovalRequest,
+	event ApprovalEvent,
+) error {
+	var message map[string]interface{}
+	if notification.Type == model.NotificationTypeLark {
+		message = s.buildLarkApprovalCard(notification, req, event)
+	} else {
+		message = map[string]interface{}{
+			"alert_type":     "approval",
+			"event":          event,
+			"approval_id":    req.ID,
+			"action":         req.Action,
+			"summary":        req.Summary,
+			"reason":         req.Reason,
+			"targets":        req.Targets,
+			"business_lines": req.BusinessLines,
+			"permission":     req.Permission,
+			"status":         req.Status,
+			"requested_by":   req.RequestedBy,
+			"reviewed_by":    req.ReviewedBy,
+			"review_comment": req.ReviewComment,
+			"error_message":  req.ErrorMessage,
+		}
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(notification.Config.WebhookURL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		bodyStr := string(bodyBytes)
+		if len(bodyStr) > 200 {
+			bodyStr = bodyStr[:200] + "..."
+		}
+		return fmt.Errorf("服务器返回状态码: %d，响应: %s", resp.StatusCode, bodyStr)
+	}
+
+	s.logger.Info("审批通知发送成功",
+		zap.Uint("approval_id", req.ID),
+		zap.String("event", string(event)),
+	)
+	return nil
+}
+
+// buildLarkApprovalCard 构建 Lark 审批卡片消息
+func (s *NotificationService) buildLarkApprovalCard(
+	notification *model.Notification,
+	req *model.ApprovalRequest,
+	event ApprovalEvent,
+) map[string]interface{} {
+	lines := []string{
+		"**操作：** " + req.Summary,
+		"**申请人：** " + req.RequestedBy,
+	}
+	if req.Reason != "" {
+		lines = append(lines, "**审批原因：** "+req.Reason)
+	}
+	if event == ApprovalEventSubmitted {
+		lines = append(lines, "**审批权限：** "+req.Permission, "", "请拥有该权限的其他用户登录平台审批。")
+	} else {
+		lines = append(lines, "**审批人：** "+req.ReviewedBy)
+		if req.ReviewComment != "" {
+			lines = append(lines, "**审批意见：** "+req.ReviewComment)
+		}
+		if req.ErrorMessage != "" {
+			lines = append(lines, "**失败原因：** "+req.ErrorMessage)
+		}
+	}
+
+	elements := []map[string]interface{}{
+		{
+			"tag": "div",
+			"text": map[string]interface{}{
+				"tag":     "lark_md",
+				"content": strings.Join(lines, "\n"),
+			},
+		},
+	}
+
+	if notification.FrontendURL != "" {
+		approvalURL := fmt.Sprintf("%s/approvals?id=%d", strings.TrimSuffix(notification.FrontendURL, "/"), req.ID)
+		elements = append(elements, map[string]interface{}{"tag": "hr"}, map[string]interface{}{
+			"tag": "action",
+			"actions": []map[string]interface{}{
+				{
+					"tag": "button",
+					"text": map[string]interface{}{
+						"tag":     "plain_text",
+						"content": "查看审批",
+					},
+					"type": "primary",
+					"multi_url": map[string]interface{}{
+						"url":         approvalURL,
+						"android_url": approvalURL,
+						"ios_url":     approvalURL,
+						"pc_url":      approvalURL,
+					},
+				},
+			},
+		})
+	}
+
+	title := approvalEventTitles[event]
+	message := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"config": map[string]interface{}{
+				"wide_screen_mode": true,
+			},
+			"header": map[string]interface{}{
+				"title": map[string]interface{}{
+					"tag":     "plain_text",
+					"content": title[0],
+				},
+				"template": title[1],
+			},
+			"elements": elements,
+		},
+	}
+
+	if notification.Config.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		if sign, err := s.generateLarkSign(notification.Config.Secret, timestamp); err == nil {
+			message["timestamp"] = timestamp
+			message["sign"] = sign
+		}
+	}
+	return message
+}
@@ -76,7 +76,7 @@ func (c *BaselineScoreCache) GetHostScore(hostID string) (*HostScore, error) {
 
 // calculateHostScore 计算主机得分
 func (c *BaselineScoreCache) calculateHostScore(hostID string) (*HostScore, error) {
-	// 查询主机最新的检测结果（按规则分组，取最新的）
+	// 查询主机最新的检测结果（按规则分组，取最新的；容器内检查结果不计入主机得分）
 	// 优化：使用窗口函数（如果数据库支持）或优化的子查询
 	var latestResults []struct {
 		RuleID   string
@@ -96,7 +96,7 @@ func (c *BaselineScoreCache) calculateHostScore(hostID string) (*HostScore, erro
 				severity,
 				ROW_NUMBER() OVER (PARTITION BY rule_id ORDER BY checked_at DESC) as rn
 			FROM scan_results
-			WHERE host_id = ?
+			WHERE host_id = ? AND container_id = ''
 		) AS ranked
 		WHERE rn = 1
 	`
@@ -109,13 +109,13 @@ func (c *BaselineScoreCache) calculateHostScore(hostID string) (*HostScore, erro
 		// 使用优化的子查询（利用索引）
 		subQuery := c.db.Model(&model.ScanResult{}).
 			Select("rule_id, MAX(checked_at) as max_checked_at").
-			Where("host_id = ?", hostID).
+			Where("host_id = ? AND container_id = ''", hostID).
 			Group("rule_id")
 
 		if err := c.db.Table("scan_results").
 			Select("scan_results.rule_id, scan_results.status, scan_results.severity").
 			Joins("INNER JOIN (?) AS latest ON scan_results.rule_id = latest.rule_id AND scan_results.checked_at = latest.max_checked_at", subQuery).
-			Where("scan_results.host_id = ?", hostID).
+			Where("scan_results.host_id = ? AND scan_results.container_id = ''", hostID).
 			Find(&latestResults).Error; err != nil {
 			return nil, err
 		}
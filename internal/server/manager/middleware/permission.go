@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+)
+
+// RequirePermission 是权限校验中间件，需在认证中间件之后使用
+// 认证中间件将当前用户的权限存储到上下文，缺少所需权限时返回 403
+func RequirePermission(p rbac.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		perms, _ := c.Get(rbac.ContextKey)
+		granted, _ := perms.([]rbac.Permission)
+		if !rbac.Has(granted, p) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    403,
+				"message": "没有权限执行此操作",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
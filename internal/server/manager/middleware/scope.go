@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
+)
+
+// RequireHostInScope 是业务线范围校验中间件，用于带 :host_id 路径参数的路由，需在认证中间件之后使用
+// 主机不在当前用户的业务线范围内（或不存在）时返回 403
+func RequireHostInScope(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostID := c.Param("host_id")
+		v, _ := c.Get(rbac.ScopeContextKey)
+		scope, _ := v.(rbac.Scope)
+		if hostID == "" || scope.Unrestricted() {
+			c.Next()
+			return
+		}
+
+		ok, err := scope.HostsInScope(db, []string{hostID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "校验业务线范围失败",
+			})
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    403,
+				"message": "无权访问业务线范围外的主机",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,105 @@
+// Package passwd 提供本地账号的密码策略校验和历史密码管理
+package passwd
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+var (
+	// ErrWeak 密码不符合策略
+	ErrWeak = errors.New("密码不符合安全策略")
+	// ErrReused 密码与最近使用过的密码相同
+	ErrReused = errors.New("不能使用最近使用过的密码")
+)
+
+// Validate 按密码策略校验密码强度，不符合时返回包装 ErrWeak 的错误
+func Validate(policy model.PasswordPolicy, password string) error {
+	if n := utf8.RuneCountInString(password); n < policy.MinLength {
+		return fmt.Errorf("%w：长度至少 %d 个字符", ErrWeak, policy.MinLength)
+	}
+	if policy.MinClasses > 1 && classes(password) < policy.MinClasses {
+		return fmt.Errorf("%w：至少包含大写字母、小写字母、数字、符号中的 %d 种", ErrWeak, policy.MinClasses)
+	}
+	return nil
+}
+
+// classes 统计密码包含的字符类型数（大写字母、小写字母、数字、符号）
+func classes(password string) int {
+	var upper, lower, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	n := 0
+	for _, ok := range []bool{upper, lower, digit, other} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckHistory 校验新密码不与当前密码和最近 count-1 个历史密码相同，count 为 0 时不校验
+func CheckHistory(db *gorm.DB, user *model.User, password string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	hashes := make([]string, 0, count)
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+	if count > 1 {
+		var history []model.PasswordHistory
+		if err := db.Where("user_id = ?", user.ID).Order("id DESC").Limit(count - 1).Find(&history).Error; err != nil {
+			return err
+		}
+		for _, h := range history {
+			hashes = append(hashes, h.Password)
+		}
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return ErrReused
+		}
+	}
+	return nil
+}
+
+// Record 将被替换的密码哈希写入历史，只保留最近 count 条（count 为 0 时清空历史）
+func Record(tx *gorm.DB, userID uint, oldHash string, count int) error {
+	if oldHash != "" && count > 1 {
+		if err := tx.Create(&model.PasswordHistory{UserID: userID, Password: oldHash}).Error; err != nil {
+			return err
+		}
+	}
+	// 当前密码保存在 users 表，历史中只需保留 count-1 条
+	keep := count - 1
+	if keep < 0 {
+		keep = 0
+	}
+	var stale []uint
+	if err := tx.Model(&model.PasswordHistory{}).Where("user_id = ?", userID).
+		Order("id DESC").Offset(keep).Pluck("id", &stale).Error; err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return tx.Delete(&model.PasswordHistory{}, stale).Error
+}
@@ -0,0 +1,36 @@
+package passwd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+func TestValidate(t *testing.T) {
+	policy := model.PasswordPolicy{MinLength: 8, MinClasses: 3}
+	tests := []struct {
+		password string
+		wantErr  bool
+	}{
+		{"Abc12", true},       // 长度不足
+		{"abcdefgh", true},    // 只有小写字母
+		{"abcd1234", true},    // 两种字符
+		{"Abcd1234", false},   // 大写、小写、数字
+		{"abcd12#$", false},   // 小写、数字、符号
+		{"密码Abcd1234", false}, // 按字符计算长度
+	}
+	for _, tt := range tests {
+		err := Validate(policy, tt.password)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, wantErr %v", tt.password, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrWeak) {
+			t.Errorf("Validate(%q) error = %v, want ErrWeak", tt.password, err)
+		}
+	}
+
+	if err := Validate(model.PasswordPolicy{MinLength: 6}, "abcdef"); err != nil {
+		t.Errorf("Validate() without class requirement error = %v", err)
+	}
+}
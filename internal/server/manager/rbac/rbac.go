@@ -0,0 +1,240 @@
+// Package rbac 提供基于权限的访问控制
+// 每个 API 路由在 router.go 中声明所需权限，用户通过角色获得权限：
+// 内置角色（admin / operator / auditor / viewer）的权限固定在代码中，自定义角色存储在 roles 表
+package rbac
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// ContextKey 是认证中间件在 gin 上下文中存储当前用户权限（[]Permission）的键
+const ContextKey = "permissions"
+
+// Permission 是权限标识，格式为 "资源:操作"
+type Permission string
+
+const (
+	HostsRead   Permission = "hosts:read"   // 查看主机、插件状态、监控数据
+	HostsManage Permission = "hosts:manage" // 修改主机标签/业务线、删除主机、重启 Agent、收集诊断包、按需采集资产
+
+	AssetsRead Permission = "assets:read" // 查看资产指纹
+
+	PoliciesRead   Permission = "policies:read"   // 查看策略组、策略、规则
+	PoliciesManage Permission = "policies:manage" // 创建/修改/删除/导入策略组、策略、规则
+
+	TasksRead    Permission = "tasks:read"    // 查看扫描任务和检查结果
+	TasksExecute Permission = "tasks:execute" // 创建/执行/取消/删除扫描任务，导入离线检查结果
+
+	FixRead    Permission = "fix:read"    // 查看修复任务
+	FixExecute Permission = "fix:execute" // 创建/取消/删除修复任务（以 root 在主机上执行修复命令）
+
+	AlertsRead   Permission = "alerts:read"   // 查看告警
+	AlertsManage Permission = "alerts:manage" // 处理/忽略/删除告警
+
+	ReportsRead Permission = "reports:read" // 查看概览、报表、巡检
+
+	FIMRead   Permission = "fim:read"   // 查看 FIM 策略、任务、事件
+	FIMManage Permission = "fim:manage" // 管理 FIM 策略、创建/执行 FIM 任务
+
+	ComponentsRead    Permission = "components:read"    // 查看组件、版本、推送记录
+	ComponentsManage  Permission = "components:manage"  // 管理组件和插件运行配置、资源限制
+	ComponentsRelease Permission = "components:release" // 发布版本、上传安装包、推送 Agent 更新、同步插件版本
+
+	BusinessLinesRead   Permission = "business_lines:read"   // 查看业务线
+	BusinessLinesManage Permission = "business_lines:manage" // 管理业务线
+
+	NotificationsRead   Permission = "notifications:read"   // 查看通知配置
+	NotificationsManage Permission = "notifications:manage" // 管理通知配置
+
+	SystemRead   Permission = "system:read"   // 查看系统配置、AgentCenter 接入点
+	SystemManage Permission = "system:manage" // 修改系统配置、管理 AgentCenter 接入点
+
+	UsersRead   Permission = "users:read"   // 查看用户和角色
+	UsersManage Permission = "users:manage" // 管理用户和角色
+
+	AuditRead Permission = "audit:read" // 查看、导出和校验审计日志
+)
+
+// PermissionInfo 是权限说明（用于角色编辑界面）
+type PermissionInfo struct {
+	Permission  Permission `json:"permission"`
+	Group       string     `json:"group"`
+	Description string     `json:"description"`
+}
+
+// AllPermissions 是全部权限（按界面展示顺序）
+var AllPermissions = []PermissionInfo{
+	{HostsRead, "主机", "查看主机"},
+	{HostsManage, "主机", "管理主机（标签、业务线、删除、重启 Agent、诊断、资产采集）"},
+	{AssetsRead, "资产", "查看资产"},
+	{PoliciesRead, "基线策略", "查看策略"},
+	{PoliciesManage, "基线策略", "管理策略"},
+	{TasksRead, "基线任务", "查看任务和检查结果"},
+	{TasksExecute, "基线任务", "执行任务"},
+	{FixRead, "基线修复", "查看修复任务"},
+	{FixExecute, "基线修复", "执行修复"},
+	{AlertsRead, "告警", "查看告警"},
+	{AlertsManage, "告警", "处理告警"},
+	{ReportsRead, "报表", "查看概览和报表"},
+	{FIMRead, "文件完整性", "查看 FIM"},
+	{FIMManage, "文件完整性", "管理 FIM"},
+	{ComponentsRead, "组件", "查看组件"},
+	{ComponentsManage, "组件", "管理组件和插件配置"},
+	{ComponentsRelease, "组件", "发布和推送组件"},
+	{BusinessLinesRead, "业务线", "查看业务线"},
+	{BusinessLinesManage, "业务线", "管理业务线"},
+	{NotificationsRead, "通知", "查看通知配置"},
+	{NotificationsManage, "通知", "管理通知配置"},
+	{SystemRead, "系统", "查看系统配置"},
+	{SystemManage, "系统", "管理系统配置"},
+	{UsersRead, "用户", "查看用户和角色"},
+	{UsersManage, "用户", "管理用户和角色"},
+	{AuditRead, "审计日志", "查看、导出和校验审计日志"},
+}
+
+// 内置角色名称
+const (
+	RoleAdmin    = "admin"    // 管理员：全部权限
+	RoleOperator = "operator" // 运维：日常安全运营，不含用户和系统管理
+	RoleAuditor  = "auditor"  // 审计：只读全部数据（含用户和系统配置）
+	RoleViewer   = "viewer"   // 访客：只读安全数据
+)
+
+// BuiltinRole 是内置角色
+type BuiltinRole struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// viewerPermissions 是只读安全数据的权限
+var viewerPermissions = []Permission{
+	HostsRead, AssetsRead, PoliciesRead, TasksRead, FixRead, AlertsRead,
+	ReportsRead, FIMRead, ComponentsRead, BusinessLinesRead,
+}
+
+// BuiltinRoles 是内置角色（按界面展示顺序）
+var BuiltinRoles = []BuiltinRole{
+	{
+		Name:        RoleAdmin,
+		Description: "管理员，拥有全部权限",
+		Permissions: allPermissions(),
+	},
+	{
+		Name:        RoleOperator,
+		Description: "运维，可执行扫描、修复、发布组件等日常操作，不能管理用户和系统配置",
+		Permissions: append(append([]Permission{}, viewerPermissions...),
+			HostsManage, PoliciesManage, TasksExecute, FixExecute, AlertsManage, FIMManage,
+			ComponentsManage, ComponentsRelease, BusinessLinesManage, NotificationsRead, NotificationsManage,
+			SystemRead),
+	},
+	{
+		Name:        RoleAuditor,
+		Description: "审计，只读访问全部数据",
+		Permissions: append(append([]Permission{}, viewerPermissions...),
+			NotificationsRead, SystemRead, UsersRead, AuditRead),
+	},
+	{
+		Name:        RoleViewer,
+		Description: "访客，只读访问安全数据",
+		Permissions: viewerPermissions,
+	},
+}
+
+// legacyRoleUser 是旧版本的普通用户角色，按 viewer 处理
+const legacyRoleUser = "user"
+
+// IsBuiltin 判断是否为内置角色名称
+func IsBuiltin(role string) bool {
+	return builtinRole(role) != nil
+}
+
+// RoleExists 判断角色是否存在（内置角色或自定义角色），用于为用户分配角色前校验
+func RoleExists(db *gorm.DB, role string) (bool, error) {
+	if role != legacyRoleUser && IsBuiltin(role) {
+		return true, nil
+	}
+	var count int64
+	if err := db.Model(&model.Role{}).Where("name = ?", role).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsValid 判断权限标识是否存在
+func IsValid(p Permission) bool {
+	for _, info := range AllPermissions {
+		if info.Permission == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve 返回角色拥有的权限（内置角色直接返回，自定义角色从 roles 表读取）
+func Resolve(db *gorm.DB, role string) ([]Permission, error) {
+	if r := builtinRole(role); r != nil {
+		return r.Permissions, nil
+	}
+
+	var custom model.Role
+	if err := db.Where("name = ?", role).First(&custom).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("角色不存在: %s", role)
+		}
+		return nil, err
+	}
+	perms := make([]Permission, 0, len(custom.Permissions))
+	for _, p := range custom.Permissions {
+		perms = append(perms, Permission(p))
+	}
+	return perms, nil
+}
+
+// Has 判断权限列表中是否包含指定权限
+func Has(perms []Permission, p Permission) bool {
+	for _, granted := range perms {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing 返回 required 中 granted 未包含的权限，用于校验授予的权限不超出操作者自身的权限
+func Missing(granted, required []Permission) []Permission {
+	var missing []Permission
+	for _, p := range required {
+		if !Has(granted, p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// builtinRole 返回内置角色（旧版本的 user 角色按 viewer 处理），不存在时返回 nil
+func builtinRole(role string) *BuiltinRole {
+	if role == legacyRoleUser {
+		role = RoleViewer
+	}
+	for i := range BuiltinRoles {
+		if BuiltinRoles[i].Name == role {
+			return &BuiltinRoles[i]
+		}
+	}
+	return nil
+}
+
+// allPermissions 返回全部权限标识
+func allPermissions() []Permission {
+	perms := make([]Permission, 0, len(AllPermissions))
+	for _, info := range AllPermissions {
+		perms = append(perms, info.Permission)
+	}
+	return perms
+}
@@ -0,0 +1,88 @@
+package rbac
+
+import "testing"
+
+// TestBuiltinRoles 测试内置角色的权限均已定义，且权限范围符合预期
+func TestBuiltinRoles(t *testing.T) {
+	for _, role := range BuiltinRoles {
+		for _, p := range role.Permissions {
+			if !IsValid(p) {
+				t.Errorf("role %s has undefined permission %s", role.Name, p)
+			}
+		}
+	}
+
+	admin := builtinRole(RoleAdmin)
+	if len(admin.Permissions) != len(AllPermissions) {
+		t.Errorf("admin has %d permissions, want %d", len(admin.Permissions), len(AllPermissions))
+	}
+
+	cases := []struct {
+		role string
+		perm Permission
+		want bool
+	}{
+		{RoleOperator, FixExecute, true},
+		{RoleOperator, ComponentsRelease, true},
+		{RoleOperator, UsersManage, false},
+		{RoleOperator, SystemManage, false},
+		{RoleAuditor, UsersRead, true},
+		{RoleAuditor, TasksExecute, false},
+		{RoleAuditor, AuditRead, true},
+		{RoleOperator, AuditRead, false},
+		{RoleViewer, HostsRead, true},
+		{RoleViewer, SystemRead, false},
+		{legacyRoleUser, HostsRead, true},
+		{legacyRoleUser, HostsManage, false},
+	}
+	for _, tc := range cases {
+		if got := Has(builtinRole(tc.role).Permissions, tc.perm); got != tc.want {
+			t.Errorf("%s has %s = %v, want %v", tc.role, tc.perm, got, tc.want)
+		}
+	}
+}
+
+// TestMissing 测试操作者只能授予自身拥有的权限：operator 不能分配 admin，admin 可以分配任意内置角色
+func TestMissing(t *testing.T) {
+	admin := builtinRole(RoleAdmin).Permissions
+	operator := builtinRole(RoleOperator).Permissions
+	viewer := builtinRole(RoleViewer).Permissions
+	custom := []Permission{UsersRead, UsersManage}
+
+	if missing := Missing(operator, admin); !Has(missing, UsersManage) || !Has(missing, SystemManage) {
+		t.Errorf("operator assigning admin: missing = %v, want users:manage and system:manage", missing)
+	}
+	if missing := Missing(custom, admin); len(missing) != len(admin)-len(custom) {
+		t.Errorf("users:manage role assigning admin: missing = %v", missing)
+	}
+	if missing := Missing(operator, viewer); len(missing) != 0 {
+		t.Errorf("operator assigning viewer: missing = %v, want none", missing)
+	}
+	for _, role := range BuiltinRoles {
+		if missing := Missing(admin, role.Permissions); len(missing) != 0 {
+			t.Errorf("admin assigning %s: missing = %v, want none", role.Name, missing)
+		}
+	}
+	if missing := Missing(nil, nil); len(missing) != 0 {
+		t.Errorf("empty role: missing = %v, want none", missing)
+	}
+}
+
+// TestScope 测试业务线范围判断（空范围不限制，未分配业务线的主机只对不限制范围的用户可见）
+func TestScope(t *testing.T) {
+	all := Scope{}
+	scoped := Scope{BusinessLines: []string{"pay", "trade"}}
+
+	if !all.Contains("") || !all.ContainsAll(nil) {
+		t.Error("unrestricted scope should contain everything")
+	}
+	if scoped.Contains("") || !scoped.Contains("pay") || scoped.Contains("ops") {
+		t.Error("scoped Contains mismatch")
+	}
+	if scoped.ContainsAll(nil) {
+		t.Error("scoped scope should not contain an unscoped task")
+	}
+	if !scoped.ContainsAll([]string{"trade"}) || scoped.ContainsAll([]string{"trade", "ops"}) {
+		t.Error("scoped ContainsAll mismatch")
+	}
+}
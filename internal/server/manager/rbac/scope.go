@@ -0,0 +1,83 @@
+package rbac
+
+import (
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// ScopeContextKey 是认证中间件在 gin 上下文中存储当前用户业务线范围（Scope）的键
+const ScopeContextKey = "scope"
+
+// Scope 是用户可访问的数据范围
+// 用户和自定义角色都可以绑定业务线，两者合并后为用户的业务线范围；均未绑定时不限制
+type Scope struct {
+	BusinessLines []string `json:"business_lines"` // 业务线代码，为空表示不限制
+}
+
+// Unrestricted 判断是否不限制业务线
+func (s Scope) Unrestricted() bool {
+	return len(s.BusinessLines) == 0
+}
+
+// Contains 判断业务线是否在范围内（未分配业务线的主机只对不限制范围的用户可见）
+func (s Scope) Contains(businessLine string) bool {
+	return s.Unrestricted() || (businessLine != "" && slices.Contains(s.BusinessLines, businessLine))
+}
+
+// ContainsAll 判断业务线是否全部在范围内（空列表表示全部业务线，只有不限制范围的用户满足）
+func (s Scope) ContainsAll(businessLines []string) bool {
+	if s.Unrestricted() {
+		return true
+	}
+	if len(businessLines) == 0 {
+		return false
+	}
+	for _, bl := range businessLines {
+		if !slices.Contains(s.BusinessLines, bl) {
+			return false
+		}
+	}
+	return true
+}
+
+// HostIDs 返回范围内主机 ID 的子查询（仅在限制范围时使用）
+func (s Scope) HostIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Host{}).Select("host_id").Where("business_line IN ?", s.BusinessLines)
+}
+
+// HostsInScope 判断主机是否全部在范围内（不存在的主机视为不在范围内）
+func (s Scope) HostsInScope(db *gorm.DB, hostIDs []string) (bool, error) {
+	if s.Unrestricted() || len(hostIDs) == 0 {
+		return true, nil
+	}
+	unique := slices.Clone(hostIDs)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+
+	var count int64
+	if err := db.Model(&model.Host{}).
+		Where("host_id IN ? AND business_line IN ?", unique, s.BusinessLines).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return int(count) == len(unique), nil
+}
+
+// ResolveScope 返回用户的业务线范围（用户绑定的业务线与自定义角色绑定的业务线合并）
+func ResolveScope(db *gorm.DB, user *model.User) (Scope, error) {
+	lines := append([]string{}, user.BusinessLines...)
+	if !IsBuiltin(string(user.Role)) {
+		var role model.Role
+		err := db.Select("business_lines").Where("name = ?", user.Role).First(&role).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return Scope{}, err
+		}
+		lines = append(lines, role.BusinessLines...)
+	}
+	slices.Sort(lines)
+	return Scope{BusinessLines: slices.Compact(lines)}, nil
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/imkerbos/mxsec-platform/internal/server/config"
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/api"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/audit"
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/biz"
 	"github.com/imkerbos/mxsec-platform/internal/server/manager/middleware"
+	"github.com/imkerbos/mxsec-platform/internal/server/manager/rbac"
 	"github.com/imkerbos/mxsec-platform/internal/server/metrics"
 )
 
@@ -24,6 +26,13 @@ func Setup(db *gorm.DB, logger *zap.Logger, cfg *config.Config, scoreCache *biz.
 
 	router := gin.New()
 
+	// 只信任配置的反向代理转发的客户端 IP（默认不信任任何代理），
+	// 否则客户端可以伪造 X-Forwarded-For 绕过登录失败锁定、伪造审计日志中的来源 IP
+	if err := router.SetTrustedProxies(cfg.Server.HTTP.TrustedProxies); err != nil {
+		logger.Error("可信代理配置无效，不信任任何代理", zap.Error(err))
+		router.SetTrustedProxies(nil)
+	}
+
 	// 中间件
 	router.Use(middleware.Logger(logger))
 	router.Use(gin.Recovery())
@@ -54,15 +63,27 @@ func Setup(db *gorm.DB, logger *zap.Logger, cfg *config.Config, scoreCache *biz.
 	// Agent 更新检查路由（不需要认证，Agent CLI 直接调用）
 	router.GET("/api/v1/agent/update-check", componentsHandler.CheckAgentUpdate)
 
+	// Agent 服务发现路由（不需要认证，Agent 获取 AgentCenter 接入点列表）
+	endpointsHandler := api.NewAgentCenterEndpointsHandler(db, logger, cfg)
+	router.GET("/api/v1/agent/discovery", endpointsHandler.Discover)
+
 	// 静态文件服务（用于访问上传的 Logo 等文件）
 	router.Static("/uploads", "./uploads")
 
 	// API 路由
 	apiV1 := router.Group("/api/v1")
 
+	// 操作审计（路由通过 audited 标记需要审计的操作）
+	recorder := audit.NewRecorder(db, logger, cfg.Audit.HashChain)
+	apiV1.Use(recorder.Handler())
+
 	// API 健康检查（用于前端获取版本信息）
 	apiV1.GET("/health", healthHandler.Health)
 
+	// OpenAPI 文档（不需要认证，供客户端生成和接口调试使用）
+	openapiHandler := api.NewOpenAPIHandler()
+	apiV1.GET("/openapi.json", openapiHandler.GetOpenAPISpec)
+
 	// 认证相关路由（不需要认证）
 	jwtSecret := cfg.Server.JWTSecret
 	if jwtSecret == "" {
@@ -70,10 +91,23 @@ func Setup(db *gorm.DB, logger *zap.Logger, cfg *config.Config, scoreCache *biz.
 		logger.Warn("使用默认 JWT 密钥，生产环境请修改配置")
 	}
 	authHandler := api.NewAuthHandler(db, logger, []byte(jwtSecret))
-	apiV1.POST("/auth/login", authHandler.Login)
-	apiV1.POST("/auth/logout", authHandler.Logout)
-	apiV1.GET("/auth/me", authHandler.GetCurrentUser)
-	apiV1.POST("/auth/change-password", authHandler.AuthMiddleware(), authHandler.ChangePassword)
+	apiV1.POST("/auth/login", audited("auth.login"), authHandler.Login)
+	apiV1.POST("/auth/logout", audited("auth.logout"), authHandler.Logout)
+	apiV1.POST("/auth/refresh", authHandler.Refresh)
+	apiV1.GET("/auth/providers", authHandler.GetAuthProviders)
+	apiV1.GET("/auth/oidc/login", authHandler.OIDCLogin)
+	apiV1.GET("/auth/oidc/callback", audited("auth.login"), authHandler.OIDCCallback)
+	apiV1.GET("/auth/me", authHandler.AuthMiddleware(), authHandler.GetCurrentUser)
+	apiV1.POST("/auth/change-password", audited("auth.change_password"), authHandler.AuthMiddleware(), authHandler.ChangePassword)
+	apiV1.POST("/auth/totp/setup", authHandler.AuthMiddleware(), authHandler.SetupTOTP)
+	apiV1.POST("/auth/totp/enable", audited("auth.totp_enable"), authHandler.AuthMiddleware(), authHandler.EnableTOTP)
+	apiV1.POST("/auth/totp/disable", audited("auth.totp_disable"), authHandler.AuthMiddleware(), authHandler.DisableTOTP)
+
+	// 个人访问令牌（所有登录用户可管理自己的 Token）
+	tokensHandler := api.NewAPITokensHandler(db, logger)
+	apiV1.GET("/auth/tokens", authHandler.AuthMiddleware(), tokensHandler.ListMyTokens)
+	apiV1.POST("/auth/tokens", audited("token.create"), authHandler.AuthMiddleware(), tokensHandler.CreateMyToken)
+	apiV1.DELETE("/auth/tokens/:id", audited("token.revoke"), authHandler.AuthMiddleware(), tokensHandler.RevokeMyToken)
 
 	// 系统配置 - 获取站点配置（不需要认证，登录页面也需要显示站点名称）
 	systemConfigHandler := api.NewSystemConfigHandler(db, logger, "./uploads", "/uploads")
@@ -89,6 +123,21 @@ func Setup(db *gorm.DB, logger *zap.Logger, cfg *config.Config, scoreCache *biz.
 	return router
 }
 
+// can 返回权限校验中间件，每个需要认证的路由都必须声明所需权限
+func can(p rbac.Permission) gin.HandlerFunc {
+	return middleware.RequirePermission(p)
+}
+
+// audited 返回审计操作标记中间件，关键变更路由需声明，并放在 can 之前以记录被拒绝的操作
+func audited(action string) gin.HandlerFunc {
+	return audit.Action(action)
+}
+
+// inScope 返回业务线范围校验中间件，带 :host_id 路径参数的路由都必须声明
+func inScope(db *gorm.DB) gin.HandlerFunc {
+	return middleware.RequireHostInScope(db)
+}
+
 // setupAPIRoutes 注册所有需要认证的 API 路由
 func setupAPIRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, cfg *config.Config, scoreCache *biz.BaselineScoreCache, metricsService *biz.MetricsService) {
 	setupHostsAPI(router, db, logger, scoreCache, metricsService)
@@ -97,6 +146,7 @@ func setupAPIRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, cf
 	setupRulesAPI(router, db, logger)
 	setupTasksAPI(router, db, logger)
 	setupResultsAPI(router, db, logger)
+	setupOfflineScanAPI(router, db, logger, cfg, scoreCache)
 	setupFixAPI(router, db, logger)
 	setupDashboardAPI(router, db, logger)
 	setupUsersAPI(router, db, logger)
@@ -110,153 +160,188 @@ func setupAPIRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, cf
 	setupPolicyImportExportAPI(router, db, logger)
 	setupInspectionAPI(router, db, logger)
 	setupFIMAPI(router, db, logger)
+	setupAgentCenterEndpointsAPI(router, db, logger, cfg)
+	setupAuditLogsAPI(router, db, logger)
+	setupApprovalsAPI(router, db, logger, scoreCache)
+	setupMaintenanceAPI(router, db, logger)
 }
 
 // setupHostsAPI 设置主机 API 路由
 func setupHostsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, scoreCache *biz.BaselineScoreCache, metricsService *biz.MetricsService) {
 	handler := api.NewHostsHandler(db, logger, scoreCache, metricsService)
-	router.GET("/hosts", handler.ListHosts)
-	router.POST("/hosts/restart-agent", handler.RestartAgent)
-	router.GET("/hosts/restart-records", handler.GetRestartRecords)
-	router.GET("/hosts/:host_id", handler.GetHost)
-	router.GET("/hosts/:host_id/metrics", handler.GetHostMetrics)
-	router.GET("/hosts/:host_id/risk-statistics", handler.GetHostRiskStatistics)
-	router.GET("/hosts/:host_id/plugins", handler.GetHostPlugins)
-	router.PUT("/hosts/:host_id/tags", handler.UpdateHostTags)
-	router.PUT("/hosts/:host_id/business-line", handler.UpdateHostBusinessLine)
-	router.DELETE("/hosts/:host_id", handler.DeleteHost)
-	router.GET("/hosts/status-distribution", handler.GetHostStatusDistribution)
-	router.GET("/hosts/risk-distribution", handler.GetHostRiskDistribution)
+	router.GET("/hosts", can(rbac.HostsRead), handler.ListHosts)
+	router.POST("/hosts/restart-agent", audited("host.restart_agent"), can(rbac.HostsManage), handler.RestartAgent)
+	router.GET("/hosts/restart-records", can(rbac.HostsRead), handler.GetRestartRecords)
+	router.GET("/hosts/:host_id", can(rbac.HostsRead), inScope(db), handler.GetHost)
+	router.GET("/hosts/:host_id/metrics", can(rbac.HostsRead), inScope(db), handler.GetHostMetrics)
+	router.GET("/hosts/:host_id/risk-statistics", can(rbac.HostsRead), inScope(db), handler.GetHostRiskStatistics)
+	router.GET("/hosts/:host_id/plugins", can(rbac.HostsRead), inScope(db), handler.GetHostPlugins)
+	router.PUT("/hosts/:host_id/tags", audited("host.update_tags"), can(rbac.HostsManage), inScope(db), handler.UpdateHostTags)
+	router.PUT("/hosts/:host_id/business-line", audited("host.update_business_line"), can(rbac.HostsManage), inScope(db), handler.UpdateHostBusinessLine)
+	router.DELETE("/hosts/:host_id", audited("host.delete"), can(rbac.HostsManage), inScope(db), handler.DeleteHost)
+	router.POST("/hosts/:host_id/diagnostics", audited("host.collect_diagnostics"), can(rbac.HostsManage), inScope(db), handler.CollectDiagnostics)
+	router.GET("/hosts/:host_id/diagnostics", can(rbac.HostsRead), inScope(db), handler.ListDiagnostics)
+	router.GET("/diagnostics/:id/download", can(rbac.HostsManage), handler.DownloadDiagnostics)
+	router.GET("/hosts/status-distribution", can(rbac.HostsRead), handler.GetHostStatusDistribution)
+	router.GET("/hosts/risk-distribution", can(rbac.HostsRead), handler.GetHostRiskDistribution)
 }
 
 // setupPolicyGroupsAPI 设置策略组 API 路由
 func setupPolicyGroupsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewPolicyGroupsHandler(db, logger)
-	router.GET("/policy-groups", handler.ListPolicyGroups)
-	router.GET("/policy-groups/:id", handler.GetPolicyGroup)
-	router.GET("/policy-groups/:id/statistics", handler.GetPolicyGroupStatistics)
-	router.POST("/policy-groups", handler.CreatePolicyGroup)
-	router.PUT("/policy-groups/:id", handler.UpdatePolicyGroup)
-	router.DELETE("/policy-groups/:id", handler.DeletePolicyGroup)
+	router.GET("/policy-groups", can(rbac.PoliciesRead), handler.ListPolicyGroups)
+	router.GET("/policy-groups/:id", can(rbac.PoliciesRead), handler.GetPolicyGroup)
+	router.GET("/policy-groups/:id/statistics", can(rbac.PoliciesRead), handler.GetPolicyGroupStatistics)
+	router.POST("/policy-groups", audited("policy_group.create"), can(rbac.PoliciesManage), handler.CreatePolicyGroup)
+	router.PUT("/policy-groups/:id", audited("policy_group.update"), can(rbac.PoliciesManage), handler.UpdatePolicyGroup)
+	router.DELETE("/policy-groups/:id", audited("policy_group.delete"), can(rbac.PoliciesManage), handler.DeletePolicyGroup)
 }
 
 // setupPoliciesAPI 设置策略 API 路由
 func setupPoliciesAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewPoliciesHandler(db, logger)
-	router.GET("/policies", handler.ListPolicies)
-	router.GET("/policies/:policy_id", handler.GetPolicy)
-	router.GET("/policies/:policy_id/statistics", handler.GetPolicyStatistics)
-	router.POST("/policies", handler.CreatePolicy)
-	router.PUT("/policies/:policy_id", handler.UpdatePolicy)
-	router.DELETE("/policies/:policy_id", handler.DeletePolicy)
+	router.GET("/policies", can(rbac.PoliciesRead), handler.ListPolicies)
+	router.GET("/policies/:policy_id", can(rbac.PoliciesRead), handler.GetPolicy)
+	router.GET("/policies/:policy_id/statistics", can(rbac.PoliciesRead), handler.GetPolicyStatistics)
+	router.POST("/policies", audited("policy.create"), can(rbac.PoliciesManage), handler.CreatePolicy)
+	router.PUT("/policies/:policy_id", audited("policy.update"), can(rbac.PoliciesManage), handler.UpdatePolicy)
+	router.DELETE("/policies/:policy_id", audited("policy.delete"), can(rbac.PoliciesManage), handler.DeletePolicy)
 
 	// 批量操作
-	router.POST("/policies/batch/enable", handler.BatchEnableDisable)
-	router.POST("/policies/batch/delete", handler.BatchDelete)
-	router.POST("/policies/batch/export", handler.BatchExport)
+	router.POST("/policies/batch/enable", audited("policy.batch_enable"), can(rbac.PoliciesManage), handler.BatchEnableDisable)
+	router.POST("/policies/batch/delete", audited("policy.batch_delete"), can(rbac.PoliciesManage), handler.BatchDelete)
+	router.POST("/policies/batch/export", can(rbac.PoliciesRead), handler.BatchExport)
 }
 
 // setupRulesAPI 设置规则 API 路由
 func setupRulesAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewRulesHandler(db, logger)
-	router.GET("/policies/:policy_id/rules", handler.ListRules)
-	router.POST("/policies/:policy_id/rules", handler.CreateRule)
-	router.GET("/rules/:rule_id", handler.GetRule)
-	router.PUT("/rules/:rule_id", handler.UpdateRule)
-	router.DELETE("/rules/:rule_id", handler.DeleteRule)
+	router.GET("/policies/:policy_id/rules", can(rbac.PoliciesRead), handler.ListRules)
+	router.POST("/policies/:policy_id/rules", audited("rule.create"), can(rbac.PoliciesManage), handler.CreateRule)
+	router.GET("/rules/:rule_id", can(rbac.PoliciesRead), handler.GetRule)
+	router.PUT("/rules/:rule_id", audited("rule.update"), can(rbac.PoliciesManage), handler.UpdateRule)
+	router.DELETE("/rules/:rule_id", audited("rule.delete"), can(rbac.PoliciesManage), handler.DeleteRule)
 }
 
 // setupTasksAPI 设置任务 API 路由
 func setupTasksAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewTasksHandler(db, logger)
-	router.GET("/tasks", handler.ListTasks)
-	router.GET("/tasks/:task_id", handler.GetTask)
-	router.GET("/tasks/:task_id/host-status", handler.GetTaskHostStatus)
-	router.POST("/tasks", handler.CreateTask)
-	router.POST("/tasks/:task_id/run", handler.RunTask)
-	router.POST("/tasks/:task_id/cancel", handler.CancelTask)
-	router.DELETE("/tasks/:task_id", handler.DeleteTask)
+	router.GET("/tasks", can(rbac.TasksRead), handler.ListTasks)
+	router.GET("/tasks/:task_id", can(rbac.TasksRead), handler.GetTask)
+	router.GET("/tasks/:task_id/host-status", can(rbac.TasksRead), handler.GetTaskHostStatus)
+	router.POST("/tasks", audited("task.create"), can(rbac.TasksExecute), handler.CreateTask)
+	router.POST("/tasks/:task_id/run", audited("task.run"), can(rbac.TasksExecute), handler.RunTask)
+	router.POST("/tasks/:task_id/cancel", audited("task.cancel"), can(rbac.TasksExecute), handler.CancelTask)
+	router.DELETE("/tasks/:task_id", audited("task.delete"), can(rbac.TasksExecute), handler.DeleteTask)
 }
 
 // setupResultsAPI 设置结果 API 路由
 func setupResultsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewResultsHandler(db, logger)
-	router.GET("/results", handler.ListResults)
-	router.GET("/results/:result_id", handler.GetResult)
-	router.GET("/results/host/:host_id/score", handler.GetHostBaselineScore)
-	router.GET("/results/host/:host_id/summary", handler.GetHostBaselineSummary)
-	router.GET("/results/host/:host_id/export", handler.ExportHostBaselineResults)
+	router.GET("/results", can(rbac.TasksRead), handler.ListResults)
+	router.GET("/results/:result_id", can(rbac.TasksRead), handler.GetResult)
+	router.GET("/results/host/:host_id/score", can(rbac.TasksRead), inScope(db), handler.GetHostBaselineScore)
+	router.GET("/results/host/:host_id/summary", can(rbac.TasksRead), inScope(db), handler.GetHostBaselineSummary)
+	router.GET("/results/host/:host_id/containers", can(rbac.TasksRead), inScope(db), handler.GetHostContainerResults)
+	router.GET("/results/host/:host_id/export", can(rbac.TasksRead), inScope(db), handler.ExportHostBaselineResults)
+}
+
+// setupOfflineScanAPI 设置离线检查结果导入 API 路由
+func setupOfflineScanAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, cfg *config.Config, scoreCache *biz.BaselineScoreCache) {
+	handler := api.NewOfflineScanHandler(db, logger, cfg.OfflineScan, scoreCache)
+	router.POST("/results/offline-keys", audited("task.offline_key"), can(rbac.TasksExecute), handler.IssueHostKey)
+	router.POST("/results/offline-import", audited("task.offline_import"), can(rbac.TasksExecute), handler.ImportOfflineResults)
 }
 
 // setupFixAPI 设置基线修复 API 路由
 func setupFixAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewFixHandler(db, logger)
-	router.GET("/fix/fixable-items", handler.GetFixableItems)
-	router.POST("/fix-tasks", handler.CreateFixTask)
-	router.GET("/fix-tasks", handler.ListFixTasks)
-	router.GET("/fix-tasks/:task_id", handler.GetFixTask)
-	router.GET("/fix-tasks/:task_id/results", handler.GetFixResults)
-	router.GET("/fix-tasks/:task_id/host-status", handler.GetFixTaskHostStatus)
-	router.POST("/fix-tasks/:task_id/cancel", handler.CancelFixTask)
-	router.DELETE("/fix-tasks/:task_id", handler.DeleteFixTask)
+	router.GET("/fix/fixable-items", can(rbac.FixRead), handler.GetFixableItems)
+	router.POST("/fix-tasks", audited("fix.create"), can(rbac.FixExecute), handler.CreateFixTask)
+	router.GET("/fix-tasks", can(rbac.FixRead), handler.ListFixTasks)
+	router.GET("/fix-tasks/:task_id", can(rbac.FixRead), handler.GetFixTask)
+	router.GET("/fix-tasks/:task_id/results", can(rbac.FixRead), handler.GetFixResults)
+	router.GET("/fix-tasks/:task_id/host-status", can(rbac.FixRead), handler.GetFixTaskHostStatus)
+	router.POST("/fix-tasks/:task_id/cancel", audited("fix.cancel"), can(rbac.FixExecute), handler.CancelFixTask)
+	router.POST("/fix-tasks/:task_id/emergency", audited("fix.emergency_override"), can(rbac.FixExecute), handler.EmergencyOverrideFixTask)
+	router.DELETE("/fix-tasks/:task_id", audited("fix.delete"), can(rbac.FixExecute), handler.DeleteFixTask)
 }
 
 
 // setupDashboardAPI 设置 Dashboard API 路由
 func setupDashboardAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewDashboardHandler(db, logger)
-	router.GET("/dashboard/stats", handler.GetDashboardStats)
+	router.GET("/dashboard/stats", can(rbac.ReportsRead), handler.GetDashboardStats)
 }
 
 // setupUsersAPI 设置用户管理 API 路由
 func setupUsersAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewUsersHandler(db, logger)
-	router.GET("/users", handler.ListUsers)
-	router.GET("/users/:id", handler.GetUser)
-	router.POST("/users", handler.CreateUser)
-	router.PUT("/users/:id", handler.UpdateUser)
-	router.DELETE("/users/:id", handler.DeleteUser)
+	router.GET("/users", can(rbac.UsersRead), handler.ListUsers)
+	router.GET("/users/:id", can(rbac.UsersRead), handler.GetUser)
+	router.POST("/users", audited("user.create"), can(rbac.UsersManage), handler.CreateUser)
+	router.PUT("/users/:id", audited("user.update"), can(rbac.UsersManage), handler.UpdateUser)
+	router.DELETE("/users/:id", audited("user.delete"), can(rbac.UsersManage), handler.DeleteUser)
+	router.DELETE("/users/:id/totp", audited("user.reset_totp"), can(rbac.UsersManage), handler.ResetTOTP)
+
+	// API Token 管理（服务账号 Token 签发、全部 Token 查询和吊销）
+	tokensHandler := api.NewAPITokensHandler(db, logger)
+	router.POST("/users/:id/tokens", audited("token.create"), can(rbac.UsersManage), tokensHandler.CreateServiceAccountToken)
+	router.GET("/api-tokens", can(rbac.UsersRead), tokensHandler.ListTokens)
+	router.DELETE("/api-tokens/:id", audited("token.revoke"), can(rbac.UsersManage), tokensHandler.RevokeToken)
+
+	rolesHandler := api.NewRolesHandler(db, logger)
+	router.GET("/permissions", can(rbac.UsersRead), rolesHandler.ListPermissions)
+	router.GET("/roles", can(rbac.UsersRead), rolesHandler.ListRoles)
+	router.POST("/roles", audited("role.create"), can(rbac.UsersManage), rolesHandler.CreateRole)
+	router.PUT("/roles/:id", audited("role.update"), can(rbac.UsersManage), rolesHandler.UpdateRole)
+	router.DELETE("/roles/:id", audited("role.delete"), can(rbac.UsersManage), rolesHandler.DeleteRole)
 }
 
 // setupAssetsAPI 设置资产 API 路由
 func setupAssetsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewAssetsHandler(db, logger)
-	router.GET("/assets/processes", handler.ListProcesses)
-	router.GET("/assets/ports", handler.ListPorts)
-	router.GET("/assets/users", handler.ListUsers)
-	router.GET("/assets/software", handler.ListSoftware)
-	router.GET("/assets/containers", handler.ListContainers)
-	router.GET("/assets/apps", handler.ListApps)
-	router.GET("/assets/network-interfaces", handler.ListNetInterfaces)
-	router.GET("/assets/volumes", handler.ListVolumes)
-	router.GET("/assets/kmods", handler.ListKmods)
-	router.GET("/assets/services", handler.ListServices)
-	router.GET("/assets/crons", handler.ListCrons)
+	router.GET("/assets/processes", can(rbac.AssetsRead), handler.ListProcesses)
+	router.GET("/hosts/:host_id/process-tree", can(rbac.AssetsRead), inScope(db), handler.GetProcessTree)
+	router.GET("/assets/ports", can(rbac.AssetsRead), handler.ListPorts)
+	router.GET("/assets/users", can(rbac.AssetsRead), handler.ListUsers)
+	router.GET("/assets/software", can(rbac.AssetsRead), handler.ListSoftware)
+	router.GET("/assets/containers", can(rbac.AssetsRead), handler.ListContainers)
+	router.GET("/assets/apps", can(rbac.AssetsRead), handler.ListApps)
+	router.GET("/assets/network-interfaces", can(rbac.AssetsRead), handler.ListNetInterfaces)
+	router.GET("/assets/volumes", can(rbac.AssetsRead), handler.ListVolumes)
+	router.GET("/assets/kmods", can(rbac.AssetsRead), handler.ListKmods)
+	router.GET("/assets/services", can(rbac.AssetsRead), handler.ListServices)
+	router.GET("/assets/crons", can(rbac.AssetsRead), handler.ListCrons)
+	// 资产按需采集
+	router.POST("/hosts/:host_id/assets/refresh", audited("host.refresh_assets"), can(rbac.HostsManage), inScope(db), handler.RefreshHostAssets)
+	router.POST("/hosts/assets/refresh", audited("host.refresh_assets"), can(rbac.HostsManage), handler.BatchRefreshAssets)
+	router.GET("/hosts/assets/refresh/:task_id", can(rbac.HostsRead), handler.GetAssetRefreshTask)
 }
 
 // setupReportsAPI 设置报表 API 路由
 func setupReportsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewReportsHandler(db, logger)
-	router.GET("/reports/stats", handler.GetStats)
-	router.GET("/reports/baseline-score-trend", handler.GetBaselineScoreTrend)
-	router.GET("/reports/check-result-trend", handler.GetCheckResultTrend)
+	router.GET("/reports/stats", can(rbac.ReportsRead), handler.GetStats)
+	router.GET("/reports/baseline-score-trend", can(rbac.ReportsRead), handler.GetBaselineScoreTrend)
+	router.GET("/reports/check-result-trend", can(rbac.ReportsRead), handler.GetCheckResultTrend)
 	// 任务报告
-	router.GET("/reports/task/:task_id", handler.GetTaskReport)
-	router.GET("/reports/task/:task_id/host/:host_id", handler.GetTaskHostDetail)
-	router.GET("/reports/task/:task_id/executive", handler.GetExecutiveTaskReport)
+	router.GET("/reports/task/:task_id", can(rbac.ReportsRead), handler.GetTaskReport)
+	router.GET("/reports/task/:task_id/host/:host_id", can(rbac.ReportsRead), inScope(db), handler.GetTaskHostDetail)
+	router.GET("/reports/task/:task_id/executive", can(rbac.ReportsRead), handler.GetExecutiveTaskReport)
 	// Top 统计
-	router.GET("/reports/top-failed-rules", handler.GetTopFailedRules)
-	router.GET("/reports/top-risk-hosts", handler.GetTopRiskHosts)
+	router.GET("/reports/top-failed-rules", can(rbac.ReportsRead), handler.GetTopFailedRules)
+	router.GET("/reports/top-risk-hosts", can(rbac.ReportsRead), handler.GetTopRiskHosts)
 }
 
 // setupBusinessLinesAPI 设置业务线 API 路由
 func setupBusinessLinesAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewBusinessLinesHandler(db, logger)
-	router.GET("/business-lines", handler.ListBusinessLines)
-	router.GET("/business-lines/:id", handler.GetBusinessLine)
-	router.POST("/business-lines", handler.CreateBusinessLine)
-	router.PUT("/business-lines/:id", handler.UpdateBusinessLine)
-	router.DELETE("/business-lines/:id", handler.DeleteBusinessLine)
+	router.GET("/business-lines", can(rbac.BusinessLinesRead), handler.ListBusinessLines)
+	router.GET("/business-lines/:id", can(rbac.BusinessLinesRead), handler.GetBusinessLine)
+	router.POST("/business-lines", audited("business_line.create"), can(rbac.BusinessLinesManage), handler.CreateBusinessLine)
+	router.PUT("/business-lines/:id", audited("business_line.update"), can(rbac.BusinessLinesManage), handler.UpdateBusinessLine)
+	router.DELETE("/business-lines/:id", audited("business_line.delete"), can(rbac.BusinessLinesManage), handler.DeleteBusinessLine)
 }
 
 // setupSystemConfigAPI 设置系统配置 API 路由（需要认证）
@@ -264,43 +349,51 @@ func setupSystemConfigAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logg
 	handler := api.NewSystemConfigHandler(db, logger, "./uploads", "/uploads")
 
 	// Kubernetes 镜像配置
-	router.GET("/system-config/kubernetes-image", handler.GetKubernetesImageConfig)
-	router.PUT("/system-config/kubernetes-image", handler.UpdateKubernetesImageConfig)
+	router.GET("/system-config/kubernetes-image", can(rbac.SystemRead), handler.GetKubernetesImageConfig)
+	router.PUT("/system-config/kubernetes-image", audited("system.update_kubernetes_image"), can(rbac.SystemManage), handler.UpdateKubernetesImageConfig)
 
 	// 站点配置（更新和上传需要认证）
-	router.PUT("/system-config/site", handler.UpdateSiteConfig)
+	router.PUT("/system-config/site", audited("system.update_site"), can(rbac.SystemManage), handler.UpdateSiteConfig)
 
 	// Logo 上传
-	router.POST("/system-config/upload-logo", handler.UploadLogo)
+	router.POST("/system-config/upload-logo", audited("system.upload_logo"), can(rbac.SystemManage), handler.UploadLogo)
 
 	// 告警配置
-	router.GET("/system-config/alert", handler.GetAlertConfig)
-	router.PUT("/system-config/alert", handler.UpdateAlertConfig)
+	router.GET("/system-config/alert", can(rbac.SystemRead), handler.GetAlertConfig)
+	router.PUT("/system-config/alert", audited("system.update_alert"), can(rbac.SystemManage), handler.UpdateAlertConfig)
+
+	// 登录认证配置（OIDC/LDAP 单点登录）
+	router.GET("/system-config/auth", can(rbac.SystemRead), handler.GetAuthConfig)
+	router.PUT("/system-config/auth", audited("system.update_auth"), can(rbac.SystemManage), handler.UpdateAuthConfig)
+
+	// 高危操作审批策略
+	router.GET("/system-config/approval", can(rbac.SystemRead), handler.GetApprovalConfig)
+	router.PUT("/system-config/approval", audited("system.update_approval"), can(rbac.SystemManage), handler.UpdateApprovalConfig)
 }
 
 // setupNotificationsAPI 设置通知管理 API 路由
 func setupNotificationsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewNotificationsHandler(db, logger)
-	router.GET("/notifications", handler.ListNotifications)
-	router.GET("/notifications/:id", handler.GetNotification)
-	router.POST("/notifications", handler.CreateNotification)
-	router.PUT("/notifications/:id", handler.UpdateNotification)
-	router.DELETE("/notifications/:id", handler.DeleteNotification)
-	router.POST("/notifications/test", handler.TestNotification)
+	router.GET("/notifications", can(rbac.NotificationsRead), handler.ListNotifications)
+	router.GET("/notifications/:id", can(rbac.NotificationsRead), handler.GetNotification)
+	router.POST("/notifications", audited("notification.create"), can(rbac.NotificationsManage), handler.CreateNotification)
+	router.PUT("/notifications/:id", audited("notification.update"), can(rbac.NotificationsManage), handler.UpdateNotification)
+	router.DELETE("/notifications/:id", audited("notification.delete"), can(rbac.NotificationsManage), handler.DeleteNotification)
+	router.POST("/notifications/test", audited("notification.test"), can(rbac.NotificationsManage), handler.TestNotification)
 }
 
 // setupAlertsAPI 设置告警管理 API 路由
 func setupAlertsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewAlertsHandler(db, logger)
-	router.GET("/alerts", handler.ListAlerts)
-	router.GET("/alerts/statistics", handler.GetAlertStatistics)
-	router.GET("/alerts/:id", handler.GetAlert)
-	router.POST("/alerts/:id/resolve", handler.ResolveAlert)
-	router.POST("/alerts/:id/ignore", handler.IgnoreAlert)
+	router.GET("/alerts", can(rbac.AlertsRead), handler.ListAlerts)
+	router.GET("/alerts/statistics", can(rbac.AlertsRead), handler.GetAlertStatistics)
+	router.GET("/alerts/:id", can(rbac.AlertsRead), handler.GetAlert)
+	router.POST("/alerts/:id/resolve", audited("alert.resolve"), can(rbac.AlertsManage), handler.ResolveAlert)
+	router.POST("/alerts/:id/ignore", audited("alert.ignore"), can(rbac.AlertsManage), handler.IgnoreAlert)
 	// 批量操作
-	router.POST("/alerts/batch/resolve", handler.BatchResolveAlerts)
-	router.POST("/alerts/batch/ignore", handler.BatchIgnoreAlerts)
-	router.POST("/alerts/batch/delete", handler.BatchDeleteAlerts)
+	router.POST("/alerts/batch/resolve", audited("alert.batch_resolve"), can(rbac.AlertsManage), handler.BatchResolveAlerts)
+	router.POST("/alerts/batch/ignore", audited("alert.batch_ignore"), can(rbac.AlertsManage), handler.BatchIgnoreAlerts)
+	router.POST("/alerts/batch/delete", audited("alert.batch_delete"), can(rbac.AlertsManage), handler.BatchDeleteAlerts)
 }
 
 // setupComponentsAPI 设置组件管理 API 路由
@@ -308,68 +401,125 @@ func setupComponentsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger
 	handler := api.NewComponentsHandler(db, logger, cfg, "./uploads", "/uploads")
 
 	// 组件管理
-	router.GET("/components", handler.ListComponents)
-	router.POST("/components", handler.CreateComponent)
-	router.GET("/components/plugin-status", handler.GetPluginSyncStatus)
-	router.GET("/components/:id", handler.GetComponent)
-	router.DELETE("/components/:id", handler.DeleteComponent)
+	router.GET("/components", can(rbac.ComponentsRead), handler.ListComponents)
+	router.POST("/components", audited("component.create"), can(rbac.ComponentsManage), handler.CreateComponent)
+	router.GET("/components/plugin-status", can(rbac.ComponentsRead), handler.GetPluginSyncStatus)
+	router.GET("/components/:id", can(rbac.ComponentsRead), handler.GetComponent)
+	router.DELETE("/components/:id", audited("component.delete"), can(rbac.ComponentsManage), handler.DeleteComponent)
 
 	// 版本管理
-	router.GET("/components/:id/versions", handler.ListVersions)
-	router.POST("/components/:id/versions", handler.ReleaseVersion)
-	router.GET("/components/:id/versions/:version_id", handler.GetVersion)
-	router.PUT("/components/:id/versions/:version_id/set-latest", handler.SetLatestVersion)
-	router.DELETE("/components/:id/versions/:version_id", handler.DeleteVersion)
+	router.GET("/components/:id/versions", can(rbac.ComponentsRead), handler.ListVersions)
+	router.POST("/components/:id/versions", audited("component.release"), can(rbac.ComponentsRelease), handler.ReleaseVersion)
+	router.GET("/components/:id/versions/:version_id", can(rbac.ComponentsRead), handler.GetVersion)
+	router.PUT("/components/:id/versions/:version_id/set-latest", audited("component.set_latest"), can(rbac.ComponentsRelease), handler.SetLatestVersion)
+	router.DELETE("/components/:id/versions/:version_id", audited("component.delete_version"), can(rbac.ComponentsRelease), handler.DeleteVersion)
 
 	// 包上传
-	router.POST("/components/:id/versions/:version_id/packages", handler.UploadPackage)
-	router.DELETE("/packages/:id", handler.DeletePackage)
+	router.POST("/components/:id/versions/:version_id/packages", audited("component.upload_package"), can(rbac.ComponentsRelease), handler.UploadPackage)
+	router.DELETE("/packages/:id", audited("component.delete_package"), can(rbac.ComponentsRelease), handler.DeletePackage)
 
 	// Agent 更新推送
-	router.POST("/components/agent/push-update", handler.PushAgentUpdate)
+	router.POST("/components/agent/push-update", audited("component.push_update"), can(rbac.ComponentsRelease), handler.PushAgentUpdate)
 
 	// 同步所有插件到最新版本
-	router.POST("/components/plugins/sync-latest", handler.SyncAllPluginsToLatest)
+	router.POST("/components/plugins/sync-latest", audited("component.sync_plugins"), can(rbac.ComponentsRelease), handler.SyncAllPluginsToLatest)
 
 	// 插件配置手动广播
-	router.POST("/components/plugins/broadcast", handler.BroadcastPluginConfigs)
+	router.POST("/components/plugins/broadcast", audited("component.broadcast_config"), can(rbac.ComponentsManage), handler.BroadcastPluginConfigs)
+
+	// 等待维护窗口的插件配置下发
+	router.GET("/components/plugins/deferrals", can(rbac.ComponentsRead), handler.ListPluginConfigDeferrals)
+	router.POST("/components/plugins/deferrals/emergency", audited("component.plugin_emergency_override"), can(rbac.ComponentsRelease), handler.EmergencyOverridePluginDeferrals)
+
+	// 插件运行配置（Config.detail，如 collector 采集间隔），无需重启插件即可生效
+	router.GET("/components/plugins/:name/config", can(rbac.ComponentsRead), handler.GetPluginDetail)
+	router.PUT("/components/plugins/:name/config", audited("component.update_plugin_config"), can(rbac.ComponentsManage), handler.UpdatePluginDetail)
+
+	// 插件资源限制（cgroup v2）与进程加固选项
+	router.GET("/components/plugins/:name/limits", can(rbac.ComponentsRead), handler.GetPluginLimits)
+	router.PUT("/components/plugins/:name/limits", audited("component.update_plugin_limits"), can(rbac.ComponentsManage), handler.UpdatePluginLimits)
 
 	// 推送记录查询
-	router.GET("/components/push-records", handler.ListPushRecords)
-	router.GET("/components/push-records/:id", handler.GetPushRecord)
+	router.GET("/components/push-records", can(rbac.ComponentsRead), handler.ListPushRecords)
+	router.GET("/components/push-records/:id", can(rbac.ComponentsRead), handler.GetPushRecord)
+	router.POST("/components/push-records/:id/emergency", audited("component.emergency_override"), can(rbac.ComponentsRelease), handler.EmergencyOverridePushRecord)
 }
 
 // setupPolicyImportExportAPI 设置策略导入导出 API 路由
 func setupPolicyImportExportAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
-	api.RegisterPolicyImportExportRoutes(router, db, logger)
+	handler := api.NewPolicyImportExportHandler(db, logger)
+	router.GET("/policies/export", can(rbac.PoliciesRead), handler.ExportAllPolicies)
+	router.GET("/policies/:policy_id/export", can(rbac.PoliciesRead), handler.ExportPolicy)
+	router.POST("/policies/import", audited("policy.import"), can(rbac.PoliciesManage), handler.ImportPolicy)
 }
 
 // setupInspectionAPI 设置运维巡检 API 路由
 func setupInspectionAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	handler := api.NewInspectionHandler(db, logger)
-	router.GET("/inspection/overview", handler.GetOverview)
+	router.GET("/inspection/overview", can(rbac.ReportsRead), handler.GetOverview)
 }
 
 // setupFIMAPI 设置 FIM（文件完整性监控）API 路由
 func setupFIMAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 	// 策略管理
 	policiesHandler := api.NewFIMPoliciesHandler(db, logger)
-	router.GET("/fim/policies", policiesHandler.ListFIMPolicies)
-	router.POST("/fim/policies", policiesHandler.CreateFIMPolicy)
-	router.GET("/fim/policies/:id", policiesHandler.GetFIMPolicy)
-	router.PUT("/fim/policies/:id", policiesHandler.UpdateFIMPolicy)
-	router.DELETE("/fim/policies/:id", policiesHandler.DeleteFIMPolicy)
+	router.GET("/fim/policies", can(rbac.FIMRead), policiesHandler.ListFIMPolicies)
+	router.POST("/fim/policies", audited("fim_policy.create"), can(rbac.FIMManage), policiesHandler.CreateFIMPolicy)
+	router.GET("/fim/policies/:id", can(rbac.FIMRead), policiesHandler.GetFIMPolicy)
+	router.PUT("/fim/policies/:id", audited("fim_policy.update"), can(rbac.FIMManage), policiesHandler.UpdateFIMPolicy)
+	router.DELETE("/fim/policies/:id", audited("fim_policy.delete"), can(rbac.FIMManage), policiesHandler.DeleteFIMPolicy)
 
 	// 任务管理
 	tasksHandler := api.NewFIMTasksHandler(db, logger)
-	router.GET("/fim/tasks", tasksHandler.ListFIMTasks)
-	router.POST("/fim/tasks", tasksHandler.CreateFIMTask)
-	router.GET("/fim/tasks/:id", tasksHandler.GetFIMTask)
-	router.POST("/fim/tasks/:id/run", tasksHandler.RunFIMTask)
+	router.GET("/fim/tasks", can(rbac.FIMRead), tasksHandler.ListFIMTasks)
+	router.POST("/fim/tasks", audited("fim_task.create"), can(rbac.FIMManage), tasksHandler.CreateFIMTask)
+	router.GET("/fim/tasks/:id", can(rbac.FIMRead), tasksHandler.GetFIMTask)
+	router.POST("/fim/tasks/:id/run", audited("fim_task.run"), can(rbac.FIMManage), tasksHandler.RunFIMTask)
 
 	// 事件查询
 	eventsHandler := api.NewFIMEventsHandler(db, logger)
-	router.GET("/fim/events", eventsHandler.ListFIMEvents)
-	router.GET("/fim/events/stats", eventsHandler.GetFIMEventStats)
-	router.GET("/fim/events/:id", eventsHandler.GetFIMEvent)
+	router.GET("/fim/events", can(rbac.FIMRead), eventsHandler.ListFIMEvents)
+	router.GET("/fim/events/stats", can(rbac.FIMRead), eventsHandler.GetFIMEventStats)
+	router.GET("/fim/events/:id", can(rbac.FIMRead), eventsHandler.GetFIMEvent)
+}
+
+// setupAgentCenterEndpointsAPI 设置 AgentCenter 接入点管理 API 路由
+func setupAgentCenterEndpointsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, cfg *config.Config) {
+	handler := api.NewAgentCenterEndpointsHandler(db, logger, cfg)
+	router.GET("/agentcenter-endpoints", can(rbac.SystemRead), handler.ListEndpoints)
+	router.POST("/agentcenter-endpoints", audited("agentcenter_endpoint.create"), can(rbac.SystemManage), handler.CreateEndpoint)
+	router.PUT("/agentcenter-endpoints/:id", audited("agentcenter_endpoint.update"), can(rbac.SystemManage), handler.UpdateEndpoint)
+	router.DELETE("/agentcenter-endpoints/:id", audited("agentcenter_endpoint.delete"), can(rbac.SystemManage), handler.DeleteEndpoint)
+}
+
+// setupAuditLogsAPI 设置审计日志 API 路由（只读：审计日志不提供修改和删除接口）
+func setupAuditLogsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
+	handler := api.NewAuditLogsHandler(db, logger)
+	router.GET("/audit-logs", can(rbac.AuditRead), handler.ListAuditLogs)
+	router.GET("/audit-logs/export", audited("audit.export"), can(rbac.AuditRead), handler.ExportAuditLogs)
+	router.GET("/audit-logs/verify", can(rbac.AuditRead), handler.VerifyAuditLogs)
+}
+
+// setupApprovalsAPI 设置高危操作审批 API 路由
+// 所有登录用户都可访问：列表只返回自己的申请和有权审批的申请，审批权限在处理器中按审批单所需权限校验
+func setupApprovalsAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, scoreCache *biz.BaselineScoreCache) {
+	handler := api.NewApprovalsHandler(db, logger, scoreCache)
+	router.GET("/approvals", handler.ListApprovals)
+	router.GET("/approvals/:id", handler.GetApproval)
+	router.POST("/approvals/:id/approve", audited("approval.approve"), handler.ApproveApproval)
+	router.POST("/approvals/:id/reject", audited("approval.reject"), handler.RejectApproval)
+	router.POST("/approvals/:id/cancel", audited("approval.cancel"), handler.CancelApproval)
+}
+
+// setupMaintenanceAPI 设置维护窗口和变更冻结 API 路由
+func setupMaintenanceAPI(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
+	handler := api.NewMaintenanceHandler(db, logger)
+	router.GET("/maintenance-windows", can(rbac.SystemRead), handler.ListMaintenanceWindows)
+	router.POST("/maintenance-windows", audited("maintenance.create_window"), can(rbac.SystemManage), handler.CreateMaintenanceWindow)
+	router.PUT("/maintenance-windows/:id", audited("maintenance.update_window"), can(rbac.SystemManage), handler.UpdateMaintenanceWindow)
+	router.DELETE("/maintenance-windows/:id", audited("maintenance.delete_window"), can(rbac.SystemManage), handler.DeleteMaintenanceWindow)
+	router.GET("/change-freezes", can(rbac.SystemRead), handler.ListChangeFreezes)
+	router.POST("/change-freezes", audited("maintenance.create_freeze"), can(rbac.SystemManage), handler.CreateChangeFreeze)
+	router.PUT("/change-freezes/:id", audited("maintenance.update_freeze"), can(rbac.SystemManage), handler.UpdateChangeFreeze)
+	router.DELETE("/change-freezes/:id", audited("maintenance.delete_freeze"), can(rbac.SystemManage), handler.DeleteChangeFreeze)
 }
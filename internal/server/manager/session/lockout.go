@@ -0,0 +1,68 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// ErrLocked 登录失败次数过多，用户或来源 IP 被暂时锁定
+var ErrLocked = errors.New("登录失败次数过多")
+
+// CheckLockout 检查用户和来源 IP 在锁定时长内的失败次数，达到阈值时返回包装 ErrLocked 的错误
+func CheckLockout(db *gorm.DB, cfg model.LockoutConfig, username, ip string) error {
+	if cfg.LockMinutes <= 0 {
+		return nil
+	}
+	since := time.Now().Add(-time.Duration(cfg.LockMinutes) * time.Minute)
+	locked := fmt.Errorf("%w，请 %d 分钟后再试", ErrLocked, cfg.LockMinutes)
+
+	if cfg.MaxUserFailures > 0 && username != "" {
+		var count int64
+		if err := db.Model(&model.LoginFailure{}).
+			Where("username = ? AND created_at > ?", username, since).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(cfg.MaxUserFailures) {
+			return locked
+		}
+	}
+	if cfg.MaxIPFailures > 0 && ip != "" {
+		var count int64
+		if err := db.Model(&model.LoginFailure{}).
+			Where("ip = ? AND created_at > ?", ip, since).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(cfg.MaxIPFailures) {
+			return locked
+		}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次登录失败，并清理统计窗口外的旧记录
+func RecordFailure(db *gorm.DB, cfg model.LockoutConfig, username, ip string) error {
+	if len(username) > 64 {
+		username = username[:64]
+	}
+	if err := db.Create(&model.LoginFailure{Username: username, IP: ip}).Error; err != nil {
+		return err
+	}
+	window := time.Duration(cfg.LockMinutes) * time.Minute
+	if window < time.Hour {
+		window = time.Hour
+	}
+	return db.Where("created_at < ?", time.Now().Add(-window)).Delete(&model.LoginFailure{}).Error
+}
+
+// ResetFailures 登录成功后清零用户的失败计数
+// 只解除记录与用户的关联，来源 IP 的计数保留，避免攻击者用自己的账号重置 IP 计数
+func ResetFailures(db *gorm.DB, username string) error {
+	return db.Model(&model.LoginFailure{}).Where("username = ?", username).UpdateColumn("username", "").Error
+}
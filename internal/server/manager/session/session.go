@@ -0,0 +1,188 @@
+// Package session 提供登录会话管理：刷新令牌的签发与轮换、会话吊销和登录失败锁定
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+const (
+	// ContextKey 当前请求的会话 ID 在 gin 上下文中的键（API Token 认证的请求没有会话）
+	ContextKey = "session_id"
+	// reuseGrace 刷新令牌轮换后的宽限期，宽限期内重复提交旧令牌（如多个标签页同时刷新）只拒绝不吊销会话
+	reuseGrace = 30 * time.Second
+	// retention 已过期或已吊销的会话保留时长，超过后在创建新会话时清理
+	retention = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalid 会话或刷新令牌不存在
+	ErrInvalid = errors.New("登录状态无效")
+	// ErrExpired 会话已过期
+	ErrExpired = errors.New("登录已过期")
+	// ErrRevoked 会话已吊销（登出、修改密码、禁用用户）
+	ErrRevoked = errors.New("登录已失效")
+	// ErrReused 已轮换的刷新令牌被再次使用，可能已泄露，会话已吊销
+	ErrReused = errors.New("刷新令牌已被使用，请重新登录")
+)
+
+// Create 为用户创建会话，返回会话和刷新令牌明文（仅返回给客户端一次）
+func Create(db *gorm.DB, username string, ttl time.Duration, ip, userAgent string) (*model.UserSession, string, error) {
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	sess := model.UserSession{
+		Username:    username,
+		RefreshHash: hash(refresh),
+		IP:          ip,
+		UserAgent:   userAgent,
+		ExpiresAt:   model.ToLocalTime(time.Now().Add(ttl)),
+	}
+	if err := db.Create(&sess).Error; err != nil {
+		return nil, "", err
+	}
+	// 顺带清理早已失效的会话，失败不影响登录
+	cutoff := time.Now().Add(-retention)
+	db.Where("expires_at < ? OR revoked_at < ?", cutoff, cutoff).Delete(&model.UserSession{})
+	return &sess, refresh, nil
+}
+
+// Rotate 校验刷新令牌并轮换为新的刷新令牌，返回会话和新令牌明文
+// 宽限期外再次提交已轮换的旧令牌时吊销整个会话，使窃取的令牌和合法客户端同时失效
+func Rotate(db *gorm.DB, refresh, ip string) (*model.UserSession, string, error) {
+	var sess model.UserSession
+	err := db.Where("refresh_hash = ?", hash(refresh)).First(&sess).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", reused(db, refresh)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if err := check(&sess); err != nil {
+		return nil, "", err
+	}
+
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	now := model.Now()
+	// 以当前哈希为条件更新，并发刷新时只有一个请求成功
+	result := db.Model(&model.UserSession{}).
+		Where("id = ? AND refresh_hash = ?", sess.ID, sess.RefreshHash).
+		Updates(map[string]interface{}{
+			"refresh_hash":      hash(next),
+			"prev_refresh_hash": sess.RefreshHash,
+			"refreshed_at":      &now,
+			"ip":                ip,
+		})
+	if result.Error != nil {
+		return nil, "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, "", ErrInvalid
+	}
+	sess.RefreshHash, sess.PrevRefreshHash, sess.RefreshedAt, sess.IP = hash(next), sess.RefreshHash, &now, ip
+	return &sess, next, nil
+}
+
+// reused 处理找不到的刷新令牌：如果是已轮换的旧令牌且超过宽限期，吊销对应会话
+func reused(db *gorm.DB, refresh string) error {
+	var sess model.UserSession
+	err := db.Where("prev_refresh_hash = ?", hash(refresh)).First(&sess).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if sess.RevokedAt != nil {
+		return ErrRevoked
+	}
+	if sess.RefreshedAt != nil && time.Since(sess.RefreshedAt.Time()) < reuseGrace {
+		return ErrInvalid
+	}
+	if err := Revoke(db, sess.ID); err != nil {
+		return err
+	}
+	return ErrReused
+}
+
+// Validate 校验访问令牌对应的会话仍然有效
+func Validate(db *gorm.DB, id uint, username string) error {
+	if id == 0 {
+		return ErrInvalid
+	}
+	var sess model.UserSession
+	if err := db.Where("id = ? AND username = ?", id, username).First(&sess).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalid
+		}
+		return err
+	}
+	return check(&sess)
+}
+
+// check 校验会话未吊销且未过期
+func check(sess *model.UserSession) error {
+	if sess.RevokedAt != nil {
+		return ErrRevoked
+	}
+	if !sess.ExpiresAt.Time().After(time.Now()) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Revoke 吊销指定会话
+func Revoke(db *gorm.DB, id uint) error {
+	return db.Model(&model.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		UpdateColumn("revoked_at", model.Now()).Error
+}
+
+// RevokeByRefreshToken 吊销刷新令牌对应的会话（访问令牌已过期时登出），返回会话所属用户名
+func RevokeByRefreshToken(db *gorm.DB, refresh string) (string, error) {
+	var sess model.UserSession
+	if err := db.Where("refresh_hash = ?", hash(refresh)).First(&sess).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrInvalid
+		}
+		return "", err
+	}
+	return sess.Username, Revoke(db, sess.ID)
+}
+
+// RevokeUser 吊销用户的全部会话，except 不为 0 时保留该会话（如修改密码时保留当前会话）
+func RevokeUser(db *gorm.DB, username string, except uint) error {
+	return db.Model(&model.UserSession{}).
+		Where("username = ? AND id <> ? AND revoked_at IS NULL", username, except).
+		UpdateColumn("revoked_at", model.Now()).Error
+}
+
+// newRefreshToken 生成刷新令牌明文
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hash 计算刷新令牌的哈希（令牌为高熵随机值，无需加盐和慢哈希）
+func hash(refresh string) string {
+	sum := sha256.Sum256([]byte(refresh))
+	return hex.EncodeToString(sum[:])
+}
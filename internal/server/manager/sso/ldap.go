@@ -0,0 +1,172 @@
+package sso
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// ldapTimeout LDAP 连接和单次请求的超时时间
+const ldapTimeout = 10 * time.Second
+
+// ldapConn LDAP 连接中登录用到的操作（便于测试替换）
+type ldapConn interface {
+	Bind(username, password string) error
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+// LDAPAuthenticator LDAP 认证器：服务账号搜索用户条目，再以用户 DN 绑定校验密码
+type LDAPAuthenticator struct {
+	cfg  model.LDAPConfig
+	dial func(cfg *model.LDAPConfig) (ldapConn, error)
+}
+
+// NewLDAPAuthenticator 创建 LDAP 认证器
+func NewLDAPAuthenticator(cfg model.LDAPConfig) *LDAPAuthenticator {
+	return &LDAPAuthenticator{cfg: cfg, dial: dialLDAP}
+}
+
+// Authenticate 校验用户名和密码，返回用户身份和所属组
+func (a *LDAPAuthenticator) Authenticate(username, password string) (*Identity, error) {
+	// 空密码在 LDAP 中是匿名绑定，会直接成功，必须拒绝
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := a.dial(&a.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("连接 LDAP 服务器失败: %w", err)
+	}
+	defer conn.Close()
+
+	if err := a.bindService(conn); err != nil {
+		return nil, err
+	}
+
+	filter := strings.ReplaceAll(a.cfg.UserFilter, "{username}", ldap.EscapeFilter(username))
+	attrs := []string{a.cfg.UsernameAttr, a.cfg.EmailAttr}
+	if a.cfg.GroupAttr != "" {
+		attrs = append(attrs, a.cfg.GroupAttr)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(ldapTimeout.Seconds()), false,
+		filter, attrs, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("搜索 LDAP 用户失败: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("LDAP 用户绑定失败: %w", err)
+	}
+
+	identity := &Identity{
+		Username: entry.GetAttributeValue(a.cfg.UsernameAttr),
+		Email:    entry.GetAttributeValue(a.cfg.EmailAttr),
+		Source:   model.UserSourceLDAP,
+	}
+	if identity.Username == "" {
+		identity.Username = username
+	}
+	if a.cfg.GroupAttr != "" {
+		for _, v := range entry.GetAttributeValues(a.cfg.GroupAttr) {
+			identity.Groups = append(identity.Groups, groupName(v))
+		}
+	}
+
+	if a.cfg.GroupBaseDN != "" {
+		groups, err := a.searchGroups(conn, entry.DN, username)
+		if err != nil {
+			return nil, err
+		}
+		identity.Groups = append(identity.Groups, groups...)
+	}
+	return identity, nil
+}
+
+// bindService 以服务账号绑定，未配置服务账号时使用匿名搜索
+func (a *LDAPAuthenticator) bindService(conn ldapConn) error {
+	if a.cfg.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(a.cfg.BindDN, a.cfg.BindPassword); err != nil {
+		return fmt.Errorf("LDAP 服务账号绑定失败: %w", err)
+	}
+	return nil
+}
+
+// searchGroups 在组目录下搜索包含该用户的组，返回组名
+func (a *LDAPAuthenticator) searchGroups(conn ldapConn, userDN, username string) ([]string, error) {
+	// 用户绑定后可能没有搜索组目录的权限，切回服务账号
+	if err := a.bindService(conn); err != nil {
+		return nil, err
+	}
+	filter := strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(userDN),
+		"{username}", ldap.EscapeFilter(username),
+	).Replace(a.cfg.GroupFilter)
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(ldapTimeout.Seconds()), false,
+		filter, []string{a.cfg.GroupNameAttr}, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("搜索 LDAP 组失败: %w", err)
+	}
+	groups := make([]string, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		if name := e.GetAttributeValue(a.cfg.GroupNameAttr); name != "" {
+			groups = append(groups, name)
+		}
+	}
+	return groups, nil
+}
+
+// groupName 组属性取值为 DN（如 memberOf）时返回第一个 RDN 的值，否则原样返回
+func groupName(value string) string {
+	dn, err := ldap.ParseDN(value)
+	if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+		return value
+	}
+	return dn.RDNs[0].Attributes[0].Value
+}
+
+// dialLDAP 建立 LDAP 连接，按配置启用 StartTLS
+func dialLDAP(cfg *model.LDAPConfig) (ldapConn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // 仅用于自签名证书的测试环境
+	}
+	conn, err := ldap.DialURL(cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: ldapTimeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(ldapTimeout)
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
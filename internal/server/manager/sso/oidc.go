@@ -0,0 +1,155 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// providerCacheTTL OIDC 发现文档缓存时间，过期后重新发现以感知 IdP 配置变更
+const providerCacheTTL = time.Hour
+
+var (
+	providerMu    sync.Mutex
+	providerCache = map[string]cachedProvider{}
+)
+
+// cachedProvider 缓存的 OIDC Provider（按 issuer 缓存，避免每次登录都请求发现文档）
+type cachedProvider struct {
+	provider  *oidc.Provider
+	fetchedAt time.Time
+}
+
+// OIDCAuthenticator OIDC 授权码登录（PKCE + nonce）
+type OIDCAuthenticator struct {
+	cfg      model.OIDCConfig
+	provider *oidc.Provider
+	oauth    *oauth2.Config
+}
+
+// NewOIDCAuthenticator 创建 OIDC 认证器，首次使用某个 issuer 时请求其发现文档
+func NewOIDCAuthenticator(ctx context.Context, cfg model.OIDCConfig) (*OIDCAuthenticator, error) {
+	provider, err := getProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("获取 OIDC 发现文档失败: %w", err)
+	}
+	scopes := []string{oidc.ScopeOpenID}
+	for _, s := range cfg.Scopes {
+		if s != "" && s != oidc.ScopeOpenID {
+			scopes = append(scopes, s)
+		}
+	}
+	return &OIDCAuthenticator{
+		cfg:      cfg,
+		provider: provider,
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+	}, nil
+}
+
+// AuthURL 返回 IdP 授权地址，verifier 为 PKCE code verifier（通过 oauth2.GenerateVerifier 生成）
+func (a *OIDCAuthenticator) AuthURL(state, nonce, verifier string) string {
+	return a.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange 用授权码换取 Token，校验 ID Token 签名、受众和 nonce 后返回用户身份
+func (a *OIDCAuthenticator) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	token, err := a.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("授权码换取 Token 失败: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("IdP 未返回 id_token")
+	}
+	idToken, err := a.provider.Verifier(&oidc.Config{ClientID: a.cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("校验 id_token 失败: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce 不匹配")
+	}
+
+	claims := map[string]interface{}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("解析 id_token 失败: %w", err)
+	}
+	// 部分 IdP 只在 userinfo 中返回用户名或组，ID Token 缺少时补充查询
+	if _, ok := claims[a.cfg.GroupsClaim]; !ok || stringClaim(claims, a.cfg.UsernameClaim) == "" {
+		if info, err := a.provider.UserInfo(ctx, oauth2.StaticTokenSource(token)); err == nil {
+			extra := map[string]interface{}{}
+			if info.Claims(&extra) == nil {
+				for k, v := range extra {
+					if _, exists := claims[k]; !exists {
+						claims[k] = v
+					}
+				}
+			}
+		}
+	}
+
+	identity := &Identity{
+		Username: stringClaim(claims, a.cfg.UsernameClaim),
+		Email:    stringClaim(claims, a.cfg.EmailClaim),
+		Groups:   stringsClaim(claims, a.cfg.GroupsClaim),
+		Source:   model.UserSourceOIDC,
+	}
+	if identity.Username == "" {
+		return nil, fmt.Errorf("id_token 缺少用户名 claim: %s", a.cfg.UsernameClaim)
+	}
+	return identity, nil
+}
+
+// getProvider 获取缓存的 OIDC Provider，不存在或过期时重新发现（失败不缓存）
+func getProvider(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	providerMu.Lock()
+	cached, ok := providerCache[issuer]
+	providerMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < providerCacheTTL {
+		return cached.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	providerMu.Lock()
+	providerCache[issuer] = cachedProvider{provider: provider, fetchedAt: time.Now()}
+	providerMu.Unlock()
+	return provider, nil
+}
+
+// stringClaim 读取字符串 claim
+func stringClaim(claims map[string]interface{}, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// stringsClaim 读取字符串数组 claim，兼容单个字符串
+func stringsClaim(claims map[string]interface{}, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
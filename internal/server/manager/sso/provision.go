@@ -0,0 +1,60 @@
+package sso
+
+import (
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// Provision 根据外部身份创建或更新平台用户，返回登录用户
+// 首次登录时自动创建（无本地密码）；已存在的 SSO 用户每次登录按目录组刷新角色、业务线和邮箱，
+// 目录中的组变更因此在下次登录时生效。同名本地账号不会被外部身份接管
+func Provision(db *gorm.DB, identity *Identity, role string, lines []string) (*model.User, error) {
+	businessLines := model.StringArray(slices.Compact(slices.Sorted(slices.Values(lines))))
+	now := model.Now()
+
+	var user model.User
+	err := db.Where("username = ?", identity.Username).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user = model.User{
+			Username:      identity.Username,
+			Email:         identity.Email,
+			Role:          model.UserRole(role),
+			Status:        model.UserStatusActive,
+			BusinessLines: businessLines,
+			Source:        identity.Source,
+			LastLogin:     &now,
+		}
+		if err := db.Create(&user).Error; err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Source != model.UserSourceOIDC && user.Source != model.UserSourceLDAP {
+		return nil, ErrLocalAccountConflict
+	}
+	if user.Status != model.UserStatusActive {
+		return nil, ErrUserDisabled
+	}
+
+	updates := map[string]interface{}{
+		"role":           model.UserRole(role),
+		"business_lines": businessLines,
+		"source":         identity.Source,
+		"last_login":     &now,
+	}
+	if identity.Email != "" {
+		updates["email"] = identity.Email
+	}
+	if err := db.Model(&user).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,106 @@
+// Package sso 提供外部身份认证：OIDC 授权码登录、LDAP 绑定登录、目录组到角色/业务线的映射和用户自动创建
+package sso
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+const (
+	// ConfigKey 认证配置在 system_configs 中的键
+	ConfigKey = "auth_config"
+	// ConfigCategory 认证配置在 system_configs 中的分类
+	ConfigCategory = "auth"
+)
+
+var (
+	// ErrInvalidCredentials 用户名或密码错误（不区分用户不存在和密码错误）
+	ErrInvalidCredentials = errors.New("用户名或密码错误")
+	// ErrNoMatchingGroup 目录组未匹配任何映射且未配置默认角色
+	ErrNoMatchingGroup = errors.New("用户所在的目录组未授权登录本平台")
+	// ErrLocalAccountConflict 同名本地账号或服务账号已存在，拒绝外部身份接管
+	ErrLocalAccountConflict = errors.New("同名本地账号已存在")
+	// ErrUserDisabled 用户已被禁用
+	ErrUserDisabled = errors.New("用户已被禁用")
+)
+
+// Identity 外部身份源认证通过后的用户身份
+type Identity struct {
+	Username string
+	Email    string
+	Groups   []string
+	Source   model.UserSource
+}
+
+// LoadConfig 从系统配置读取认证配置，未配置时返回默认配置（仅本地登录）
+func LoadConfig(db *gorm.DB) (model.AuthConfig, error) {
+	cfg := model.DefaultAuthConfig()
+	var sc model.SystemConfig
+	err := db.Where("`key` = ? AND category = ?", ConfigKey, ConfigCategory).First(&sc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal([]byte(sc.Value), &cfg); err != nil {
+		return model.DefaultAuthConfig(), err
+	}
+	return cfg, nil
+}
+
+// IsBreakGlass 判断用户是否为应急账号（关闭本地登录后仍可使用本地密码登录）
+func IsBreakGlass(cfg *model.AuthConfig, username string) bool {
+	for _, u := range cfg.BreakGlassUsers {
+		if u == username {
+			return true
+		}
+	}
+	return false
+}
+
+// MapGroups 将目录组映射为角色和业务线
+// 角色取第一条匹配的映射；业务线取所有匹配映射的并集，任一匹配映射未限制业务线时不限制；
+// 没有匹配时使用默认角色（不限制业务线），未配置默认角色时返回 false
+func MapGroups(cfg *model.AuthConfig, groups []string) (string, []string, bool) {
+	role := ""
+	lines := []string{}
+	unrestricted := false
+	for _, m := range cfg.GroupMappings {
+		if !containsFold(groups, m.Group) {
+			continue
+		}
+		if role == "" {
+			role = m.Role
+		}
+		if len(m.BusinessLines) == 0 {
+			unrestricted = true
+		}
+		lines = append(lines, m.BusinessLines...)
+	}
+	if role == "" {
+		if cfg.DefaultRole == "" {
+			return "", nil, false
+		}
+		return cfg.DefaultRole, []string{}, true
+	}
+	if unrestricted {
+		lines = []string{}
+	}
+	return role, lines, true
+}
+
+// containsFold 不区分大小写判断 values 是否包含 target
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
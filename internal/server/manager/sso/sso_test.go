@@ -0,0 +1,258 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/imkerbos/mxsec-platform/internal/server/model"
+)
+
+// TestMapGroups 测试目录组映射：首个匹配决定角色，业务线取并集，未限制的映射优先
+func TestMapGroups(t *testing.T) {
+	cfg := &model.AuthConfig{
+		GroupMappings: []model.GroupMapping{
+			{Group: "sec-ops", Role: "operator", BusinessLines: []string{"pay"}},
+			{Group: "sec-audit", Role: "auditor", BusinessLines: []string{"mall"}},
+			{Group: "sec-admin", Role: "admin"},
+		},
+	}
+	tests := []struct {
+		name      string
+		groups    []string
+		wantRole  string
+		wantLines []string
+		wantOK    bool
+	}{
+		{"single match", []string{"SEC-OPS"}, "operator", []string{"pay"}, true},
+		{"union of lines", []string{"sec-audit", "sec-ops"}, "operator", []string{"pay", "mall"}, true},
+		{"unrestricted wins", []string{"sec-ops", "sec-admin"}, "operator", []string{}, true},
+		{"no match", []string{"dev"}, "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, lines, ok := MapGroups(cfg, tt.groups)
+			if role != tt.wantRole || ok != tt.wantOK || !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("MapGroups(%v) = %q, %v, %v; want %q, %v, %v", tt.groups, role, lines, ok, tt.wantRole, tt.wantLines, tt.wantOK)
+			}
+		})
+	}
+
+	cfg.DefaultRole = "viewer"
+	if role, lines, ok := MapGroups(cfg, []string{"dev"}); role != "viewer" || len(lines) != 0 || !ok {
+		t.Errorf("default role = %q, %v, %v", role, lines, ok)
+	}
+}
+
+// fakeLDAP 内存 LDAP 目录
+type fakeLDAP struct {
+	passwords map[string]string // DN -> 密码
+	users     []*ldap.Entry
+	groups    []*ldap.Entry
+	bound     string
+}
+
+func (f *fakeLDAP) Bind(dn, password string) error {
+	if p, ok := f.passwords[dn]; !ok || p != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	f.bound = dn
+	return nil
+}
+
+func (f *fakeLDAP) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	if f.bound != "cn=svc,dc=example,dc=com" {
+		return nil, ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("insufficient access"))
+	}
+	entries := f.users
+	if req.BaseDN == "ou=groups,dc=example,dc=com" {
+		entries = f.groups
+	}
+	var matched []*ldap.Entry
+	for _, e := range entries {
+		// 只支持 (attr=value) 形式的过滤器
+		kv := strings.SplitN(strings.Trim(req.Filter, "()"), "=", 2)
+		for _, v := range e.GetAttributeValues(kv[0]) {
+			if v == kv[1] {
+				matched = append(matched, e)
+				break
+			}
+		}
+	}
+	return &ldap.SearchResult{Entries: matched}, nil
+}
+
+func (f *fakeLDAP) Close() error { return nil }
+
+// TestLDAPAuthenticate 测试 LDAP 搜索绑定登录和组解析
+func TestLDAPAuthenticate(t *testing.T) {
+	userDN := "uid=alice,ou=people,dc=example,dc=com"
+	dir := &fakeLDAP{
+		passwords: map[string]string{"cn=svc,dc=example,dc=com": "svc-pass", userDN: "alice-pass"},
+		users: []*ldap.Entry{ldap.NewEntry(userDN, map[string][]string{
+			"uid":      {"alice"},
+			"mail":     {"alice@example.com"},
+			"memberOf": {"cn=sec-ops,ou=groups,dc=example,dc=com"},
+		})},
+		groups: []*ldap.Entry{ldap.NewEntry("cn=sec-audit,ou=groups,dc=example,dc=com", map[string][]string{
+			"cn":     {"sec-audit"},
+			"member": {userDN},
+		})},
+	}
+	cfg := model.DefaultAuthConfig().LDAP
+	cfg.BindDN = "cn=svc,dc=example,dc=com"
+	cfg.BindPassword = "svc-pass"
+	cfg.BaseDN = "ou=people,dc=example,dc=com"
+	cfg.GroupBaseDN = "ou=groups,dc=example,dc=com"
+	a := NewLDAPAuthenticator(cfg)
+	a.dial = func(*model.LDAPConfig) (ldapConn, error) { return dir, nil }
+
+	identity, err := a.Authenticate("alice", "alice-pass")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	want := &Identity{Username: "alice", Email: "alice@example.com", Groups: []string{"sec-ops", "sec-audit"}, Source: model.UserSourceLDAP}
+	if !reflect.DeepEqual(identity, want) {
+		t.Errorf("Authenticate() = %+v, want %+v", identity, want)
+	}
+
+	for _, tc := range [][2]string{{"alice", "wrong"}, {"alice", ""}, {"bob", "alice-pass"}} {
+		if _, err := a.Authenticate(tc[0], tc[1]); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate(%q, %q) error = %v, want ErrInvalidCredentials", tc[0], tc[1], err)
+		}
+	}
+}
+
+// testIssuer 本地 OIDC IdP：提供发现文档、JWKS 和 token 端点，签发 RS256 ID Token
+type testIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	nonce    string
+	verifier string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	iss.server = httptest.NewServer(mux)
+	t.Cleanup(iss.server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                iss.server.URL,
+			"authorization_endpoint":                iss.server.URL + "/authorize",
+			"token_endpoint":                        iss.server.URL + "/token",
+			"jwks_uri":                              iss.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("code") != "good-code" || r.Form.Get("code_verifier") != iss.verifier {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":                iss.server.URL,
+			"sub":                "u-1",
+			"aud":                "mxsec",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"iat":                time.Now().Unix(),
+			"nonce":              iss.nonce,
+			"preferred_username": "carol",
+			"email":              "carol@example.com",
+			"groups":             []string{"sec-ops"},
+		})
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     signed,
+		})
+	})
+	return iss
+}
+
+// TestOIDCExchange 测试授权地址携带 PKCE 参数，以及授权码换取 Token 后校验 ID Token 和 nonce
+func TestOIDCExchange(t *testing.T) {
+	iss := newTestIssuer(t)
+	cfg := model.DefaultAuthConfig().OIDC
+	cfg.Issuer = iss.server.URL
+	cfg.ClientID = "mxsec"
+	cfg.ClientSecret = "secret"
+	cfg.RedirectURL = "http://localhost/api/v1/auth/oidc/callback"
+
+	ctx := context.Background()
+	a, err := NewOIDCAuthenticator(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewOIDCAuthenticator() error = %v", err)
+	}
+
+	iss.verifier = oauth2.GenerateVerifier()
+	iss.nonce = "n-123"
+	authURL, err := url.Parse(a.AuthURL("s-1", iss.nonce, iss.verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := authURL.Query()
+	if q.Get("state") != "s-1" || q.Get("nonce") != iss.nonce || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Errorf("AuthURL() query = %v", q)
+	}
+	if !strings.Contains(q.Get("scope"), "openid") {
+		t.Errorf("AuthURL() scope = %q, want openid", q.Get("scope"))
+	}
+
+	identity, err := a.Exchange(ctx, "good-code", iss.verifier, iss.nonce)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	want := &Identity{Username: "carol", Email: "carol@example.com", Groups: []string{"sec-ops"}, Source: model.UserSourceOIDC}
+	if !reflect.DeepEqual(identity, want) {
+		t.Errorf("Exchange() = %+v, want %+v", identity, want)
+	}
+
+	if _, err := a.Exchange(ctx, "good-code", iss.verifier, "other-nonce"); err == nil {
+		t.Error("Exchange() with mismatched nonce should fail")
+	}
+	if _, err := a.Exchange(ctx, "good-code", oauth2.GenerateVerifier(), iss.nonce); err == nil {
+		t.Error("Exchange() with wrong PKCE verifier should fail")
+	}
+}
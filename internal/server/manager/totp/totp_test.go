@@ -0,0 +1,75 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret 是 RFC 6238 附录 B 中 SHA-1 测试密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 附录 B 的 8 位验证码取后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, tt.unix/period)
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	counter := now.Unix() / period
+
+	if got, ok := Verify(rfcSecret, "081804", now, 0); !ok || got != counter {
+		t.Fatalf("Verify() = %d, %v, want %d, true", got, ok, counter)
+	}
+	// 前一个时间步的验证码在容忍范围内
+	prev, _ := Code(rfcSecret, counter-1)
+	if _, ok := Verify(rfcSecret, prev, now, 0); !ok {
+		t.Error("Verify() should accept code from previous step")
+	}
+	// 超出容忍范围
+	old, _ := Code(rfcSecret, counter-2)
+	if _, ok := Verify(rfcSecret, old, now, 0); ok {
+		t.Error("Verify() should reject code outside skew")
+	}
+	// 已使用的时间步不能重放
+	if _, ok := Verify(rfcSecret, "081804", now, counter); ok {
+		t.Error("Verify() should reject replayed code")
+	}
+	if _, ok := Verify(rfcSecret, "12345", now, 0); ok {
+		t.Error("Verify() should reject malformed code")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("len(secret) = %d, want 32", len(secret))
+	}
+	if _, err := Code(secret, 1); err != nil {
+		t.Errorf("Code() with generated secret error: %v", err)
+	}
+	uri := URI("MxSec", "admin", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/MxSec:admin?") || !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("URI() = %s", uri)
+	}
+}
@@ -3,6 +3,7 @@ package metrics
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -89,6 +90,80 @@ var (
 		[]string{"method", "endpoint"},
 	)
 
+	// Transfer 通道按数据类型统计的记录数
+	transferRecordsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mxsec_transfer_records_total",
+			Help: "Agent 上报的记录总数（按数据类型）",
+		},
+		[]string{"data_type"},
+	)
+
+	// Transfer 通道按数据类型统计的字节数（解压后的记录数据大小）
+	transferRecordBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mxsec_transfer_record_bytes_total",
+			Help: "Agent 上报的记录字节数（按数据类型，解压后）",
+		},
+		[]string{"data_type"},
+	)
+
+	// Transfer 通道消息字节数（按压缩算法，wire 为网络传输字节数，raw 为解压后字节数）
+	transferPayloadBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mxsec_transfer_payload_bytes_total",
+			Help: "Agent 上报的消息字节数（按压缩算法，kind=wire 为压缩后，kind=raw 为解压后）",
+		},
+		[]string{"compression", "kind"},
+	)
+
+	// 入库队列深度
+	ingestQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mxsec_ingest_queue_depth",
+			Help: "入库队列中等待处理的记录数",
+		},
+		[]string{"queue"}, // baseline, fim, asset, default
+	)
+
+	// 入库延迟（从进入队列到处理完成）
+	ingestLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mxsec_ingest_latency_seconds",
+			Help:    "记录从进入入库队列到处理完成的延迟（秒）",
+			Buckets: []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"queue"},
+	)
+
+	// 批量写入大小
+	ingestBatchSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mxsec_ingest_batch_size",
+			Help:    "入库批量写入的记录数",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 200, 500},
+		},
+		[]string{"queue"},
+	)
+
+	// 背压通知次数
+	ingestThrottleTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mxsec_ingest_throttle_total",
+			Help: "因入库队列饱和通知 Agent 暂停上报的次数",
+		},
+		[]string{"queue"},
+	)
+
+	// 数据外发记录数
+	sinkRecordsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mxsec_sink_records_total",
+			Help: "数据外发的记录数（按输出和结果统计）",
+		},
+		[]string{"sink", "result"}, // result: sent, failed, dropped, invalid
+	)
+
 	// 数据库查询指标
 	dbQueryDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -119,6 +194,14 @@ func Init(logger *zap.Logger) *prometheus.Registry {
 			taskDuration,
 			httpRequestsTotal,
 			httpRequestDuration,
+			transferRecordsTotal,
+			transferRecordBytesTotal,
+			transferPayloadBytesTotal,
+			ingestQueueDepth,
+			ingestLatency,
+			ingestBatchSize,
+			ingestThrottleTotal,
+			sinkRecordsTotal,
 			dbQueryDuration,
 		)
 
@@ -180,6 +263,47 @@ func RecordHTTPRequestDuration(method, endpoint string, duration float64) {
 	httpRequestDuration.WithLabelValues(method, endpoint).Observe(duration)
 }
 
+// RecordTransferRecord 记录 Agent 上报的一条记录（按数据类型统计条数和字节数）
+func RecordTransferRecord(dataType int32, bytes int) {
+	label := strconv.Itoa(int(dataType))
+	transferRecordsTotal.WithLabelValues(label).Inc()
+	transferRecordBytesTotal.WithLabelValues(label).Add(float64(bytes))
+}
+
+// RecordTransferPayload 记录 Agent 上报的一条消息（压缩前后字节数）
+func RecordTransferPayload(compression string, wireBytes, rawBytes int) {
+	if compression == "" {
+		compression = "identity"
+	}
+	transferPayloadBytesTotal.WithLabelValues(compression, "wire").Add(float64(wireBytes))
+	transferPayloadBytesTotal.WithLabelValues(compression, "raw").Add(float64(rawBytes))
+}
+
+// RecordIngestQueueDepth 记录入库队列深度
+func RecordIngestQueueDepth(queue string, depth int) {
+	ingestQueueDepth.WithLabelValues(queue).Set(float64(depth))
+}
+
+// RecordIngestLatency 记录入库延迟
+func RecordIngestLatency(queue string, seconds float64) {
+	ingestLatency.WithLabelValues(queue).Observe(seconds)
+}
+
+// RecordIngestBatch 记录一次批量写入的记录数
+func RecordIngestBatch(queue string, size int) {
+	ingestBatchSize.WithLabelValues(queue).Observe(float64(size))
+}
+
+// RecordIngestThrottle 记录一次背压通知
+func RecordIngestThrottle(queue string) {
+	ingestThrottleTotal.WithLabelValues(queue).Inc()
+}
+
+// RecordSinkRecord 记录一条数据外发结果
+func RecordSinkRecord(sink, result string) {
+	sinkRecordsTotal.WithLabelValues(sink, result).Inc()
+}
+
 // RecordDBQueryDuration 记录数据库查询延迟
 func RecordDBQueryDuration(operation, table string, duration float64) {
 	dbQueryDuration.WithLabelValues(operation, table).Observe(duration)
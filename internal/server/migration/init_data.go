@@ -316,6 +316,21 @@ func associateExistingPoliciesWithGroup(db *gorm.DB, logger *zap.Logger) error {
 	return nil
 }
 
+// defaultCollectorDetail 是 collector 插件的默认运行配置（采集间隔单位：秒）
+// 与插件内置的默认间隔一致，管理员可通过 PUT /components/plugins/collector/config 修改
+const defaultCollectorDetail = `{"handlers": {` +
+	`"process": {"interval": 3600, "enabled": true}, ` +
+	`"port": {"interval": 3600, "enabled": true}, ` +
+	`"user": {"interval": 21600, "enabled": true}, ` +
+	`"software": {"interval": 43200, "enabled": true}, ` +
+	`"container": {"interval": 3600, "enabled": true}, ` +
+	`"app": {"interval": 21600, "enabled": true}, ` +
+	`"network": {"interval": 21600, "enabled": true}, ` +
+	`"volume": {"interval": 21600, "enabled": true}, ` +
+	`"kmod": {"interval": 43200, "enabled": true}, ` +
+	`"service": {"interval": 21600, "enabled": true}, ` +
+	`"cron": {"interval": 43200, "enabled": true}}}`
+
 // initDefaultPluginConfigs 初始化默认插件配置
 func initDefaultPluginConfigs(db *gorm.DB, logger *zap.Logger, pluginsCfg *config.PluginsConfig) error {
 	// 构建插件下载 URL
@@ -366,7 +381,7 @@ func initDefaultPluginConfigs(db *gorm.DB, logger *zap.Logger, pluginsCfg *confi
 			DownloadURLs: model.StringArray{
 				collectorURL,
 			},
-			Detail:      `{"collect_interval": 300}`,
+			Detail:      defaultCollectorDetail,
 			Enabled:     true,
 			Description: "资产采集插件，采集主机进程、端口、用户等信息",
 		},
@@ -57,6 +57,11 @@ func Migrate(db *gorm.DB, logger *zap.Logger) error {
 		logger.Warn("通知类别迁移处理", zap.Error(err))
 	}
 
+	// 执行数据迁移：旧版本的 user 角色转换为 viewer
+	if err := migrateLegacyUserRole(db, logger); err != nil {
+		logger.Warn("用户角色迁移处理", zap.Error(err))
+	}
+
 	logger.Info("数据库迁移完成")
 	return nil
 }
@@ -100,6 +105,22 @@ func migrateNotificationCategory(db *gorm.DB, logger *zap.Logger) error {
 	return nil
 }
 
+// migrateLegacyUserRole 将旧版本的 user 角色转换为只读的 viewer 角色
+func migrateLegacyUserRole(db *gorm.DB, logger *zap.Logger) error {
+	result := db.Model(&model.User{}).
+		Where("role = ?", model.UserRoleUser).
+		Update("role", model.UserRoleViewer)
+	if result.Error != nil {
+		logger.Warn("转换旧版本用户角色失败", zap.Error(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("已将旧版本 user 角色转换为 viewer",
+			zap.Int64("count", result.RowsAffected))
+	}
+	return nil
+}
+
 // migrateRuntimeTypes 为现有数据设置默认的运行时类型
 func migrateRuntimeTypes(db *gorm.DB, logger *zap.Logger) error {
 	// 1. 更新现有主机的 runtime_type
@@ -0,0 +1,36 @@
+// Package model 提供数据库模型定义
+package model
+
+// AgentDiagnosticsStatus 诊断信息收集状态
+type AgentDiagnosticsStatus string
+
+const (
+	AgentDiagnosticsStatusPending    AgentDiagnosticsStatus = "pending"    // 等待 AgentCenter 下发
+	AgentDiagnosticsStatusCollecting AgentDiagnosticsStatus = "collecting" // 已下发，等待 Agent 上传
+	AgentDiagnosticsStatusCompleted  AgentDiagnosticsStatus = "completed"  // 已上传，可下载
+	AgentDiagnosticsStatusFailed     AgentDiagnosticsStatus = "failed"
+)
+
+// AgentDiagnostics Agent 诊断信息收集记录
+// 诊断包（tar.gz）由 Agent 通过 FileExt.Upload 上传，保存在 Content 中，列表查询时不加载
+type AgentDiagnostics struct {
+	ID          uint                   `gorm:"primaryKey" json:"id"`
+	HostID      string                 `gorm:"column:host_id;type:varchar(64);not null;index" json:"host_id"`
+	Hostname    string                 `gorm:"column:hostname;type:varchar(255)" json:"hostname"`
+	Token       string                 `gorm:"column:token;type:varchar(64);not null;uniqueIndex" json:"-"` // 一次性上传令牌
+	Status      AgentDiagnosticsStatus `gorm:"column:status;size:32;default:pending;index" json:"status"`
+	Message     string                 `gorm:"column:message;type:text" json:"message"`
+	FileSize    int64                  `gorm:"column:file_size;default:0" json:"file_size"`
+	SHA256      string                 `gorm:"column:sha256;type:varchar(64)" json:"sha256"`
+	Content     []byte                 `gorm:"column:content" json:"-"`
+	CreatedBy   string                 `gorm:"column:created_by;size:64" json:"created_by"`
+	PushedAt    *LocalTime             `gorm:"column:pushed_at" json:"pushed_at,omitempty"`
+	CompletedAt *LocalTime             `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	CreatedAt   LocalTime              `json:"created_at"`
+	UpdatedAt   LocalTime              `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AgentDiagnostics) TableName() string {
+	return "agent_diagnostics"
+}
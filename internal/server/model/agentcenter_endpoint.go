@@ -0,0 +1,31 @@
+// Package model 提供数据库模型定义
+package model
+
+// AgentCenterEndpointStatus AgentCenter 接入点状态
+type AgentCenterEndpointStatus string
+
+const (
+	AgentCenterEndpointActive   AgentCenterEndpointStatus = "active"   // 正常接入
+	AgentCenterEndpointDraining AgentCenterEndpointStatus = "draining" // 排空中：不再下发给 Agent，已连接的 Agent 在下次重平衡时迁移
+	AgentCenterEndpointDisabled AgentCenterEndpointStatus = "disabled" // 停用
+)
+
+// AgentCenterEndpoint AgentCenter 接入点（Agent 服务发现返回的地址）
+type AgentCenterEndpoint struct {
+	ID           uint                      `gorm:"primaryKey;column:id;autoIncrement" json:"id"`
+	Address      string                    `gorm:"column:address;type:varchar(255);not null;uniqueIndex" json:"address"`   // Agent 连接地址（host:port）
+	InstanceID   string                    `gorm:"column:instance_id;type:varchar(128)" json:"instance_id"`                // 对应的集群实例 ID（可选，用于按连接数计算负载）
+	Region       string                    `gorm:"column:region;type:varchar(64)" json:"region"`                           // 服务区域（空表示所有区域）
+	BusinessLine string                    `gorm:"column:business_line;type:varchar(100)" json:"business_line"`            // 服务业务线（空表示所有业务线）
+	Weight       int                       `gorm:"column:weight;not null;default:100" json:"weight"`                       // 权重
+	MaxAgents    int                       `gorm:"column:max_agents;not null;default:0" json:"max_agents"`                 // 最大连接数（0 表示不限）
+	Status       AgentCenterEndpointStatus `gorm:"column:status;type:varchar(20);not null;default:'active'" json:"status"` // 状态
+	Description  string                    `gorm:"column:description;type:varchar(255)" json:"description"`
+	CreatedAt    LocalTime                 `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    LocalTime                 `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AgentCenterEndpoint) TableName() string {
+	return "agentcenter_endpoints"
+}
@@ -0,0 +1,24 @@
+// Package model 提供数据库模型定义
+package model
+
+// APIToken API Token（个人访问令牌或服务账号令牌），只保存哈希，明文仅在创建时返回一次
+type APIToken struct {
+	ID          uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string      `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Owner       string      `gorm:"column:owner;type:varchar(64);not null;index:idx_api_token_owner" json:"owner"` // 所属用户名（个人用户或服务账号），权限和业务线范围随所属用户
+	Prefix      string      `gorm:"column:prefix;type:varchar(16);not null" json:"prefix"`                         // 明文前缀，用于识别 Token
+	TokenHash   string      `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`              // 明文的 SHA-256
+	Permissions StringArray `gorm:"column:permissions;type:json" json:"permissions"`                               // 限定的权限（与所属用户权限取交集），为空表示继承所属用户的全部权限
+	ExpiresAt   *LocalTime  `gorm:"column:expires_at;type:timestamp" json:"expires_at"`                            // 为空表示永不过期
+	LastUsedAt  *LocalTime  `gorm:"column:last_used_at;type:timestamp" json:"last_used_at"`
+	LastUsedIP  string      `gorm:"column:last_used_ip;type:varchar(64)" json:"last_used_ip"`
+	RevokedAt   *LocalTime  `gorm:"column:revoked_at;type:timestamp" json:"revoked_at"`
+	CreatedBy   string      `gorm:"column:created_by;type:varchar(64)" json:"created_by"`
+	CreatedAt   LocalTime   `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   LocalTime   `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
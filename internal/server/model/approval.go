@@ -0,0 +1,40 @@
+// Package model 提供数据库模型定义
+package model
+
+// ApprovalStatus 审批状态
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending   ApprovalStatus = "pending_approval" // 待审批
+	ApprovalStatusApproved  ApprovalStatus = "approved"         // 已批准并执行
+	ApprovalStatusRejected  ApprovalStatus = "rejected"         // 已驳回
+	ApprovalStatusCancelled ApprovalStatus = "cancelled"        // 申请人撤销或关联任务已取消
+	ApprovalStatusFailed    ApprovalStatus = "failed"           // 已批准但执行失败
+)
+
+// ApprovalRequest 高危操作审批单
+// 修复任务和 Agent 推送先以 pending_approval 状态创建，批准后转为 pending 由调度器下发；
+// 删除主机和删除策略在批准后才执行
+type ApprovalRequest struct {
+	ID            uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Action        string         `gorm:"column:action;type:varchar(64);not null;index:idx_approval_resource,priority:1" json:"action"`           // 操作标识，与审计日志 action 一致（如 fix.create）
+	ResourceID    string         `gorm:"column:resource_id;type:varchar(64);not null;index:idx_approval_resource,priority:2" json:"resource_id"` // 关联资源 ID（修复任务 ID、推送记录 ID、主机 ID、策略 ID）
+	Summary       string         `gorm:"column:summary;type:varchar(500)" json:"summary"`                                                        // 操作摘要，用于列表和通知
+	Reason        string         `gorm:"column:reason;type:varchar(500)" json:"reason"`                                                          // 触发审批的原因（命中的审批策略）
+	Targets       StringArray    `gorm:"column:targets;type:json" json:"targets"`                                                                // 目标主机或策略 ID
+	BusinessLines StringArray    `gorm:"column:business_lines;type:json" json:"business_lines"`                                                  // 目标主机所属业务线，审批人必须覆盖这些业务线
+	Permission    string         `gorm:"column:permission;type:varchar(64);not null;index" json:"permission"`                                    // 审批人需要的权限（与操作本身所需权限相同）
+	Status        ApprovalStatus `gorm:"column:status;type:varchar(20);not null;default:'pending_approval';index" json:"status"`
+	RequestedBy   string         `gorm:"column:requested_by;type:varchar(64);not null;index" json:"requested_by"`
+	ReviewedBy    string         `gorm:"column:reviewed_by;type:varchar(64)" json:"reviewed_by"`
+	ReviewComment string         `gorm:"column:review_comment;type:varchar(500)" json:"review_comment"`
+	ReviewedAt    *LocalTime     `gorm:"column:reviewed_at;type:timestamp" json:"reviewed_at"`
+	ErrorMessage  string         `gorm:"column:error_message;type:text" json:"error_message,omitempty"` // 执行失败原因
+	CreatedAt     LocalTime      `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     LocalTime      `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ApprovalRequest) TableName() string {
+	return "approval_requests"
+}
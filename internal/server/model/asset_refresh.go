@@ -0,0 +1,61 @@
+package model
+
+// AssetRefreshStatus 资产按需采集任务状态
+type AssetRefreshStatus string
+
+const (
+	AssetRefreshStatusPending   AssetRefreshStatus = "pending"   // 等待下发
+	AssetRefreshStatusRunning   AssetRefreshStatus = "running"   // 已下发，等待主机上报
+	AssetRefreshStatusCompleted AssetRefreshStatus = "completed" // 全部成功
+	AssetRefreshStatusPartial   AssetRefreshStatus = "partial"   // 部分失败
+	AssetRefreshStatusFailed    AssetRefreshStatus = "failed"    // 全部失败
+)
+
+// AssetRefreshHostStatus 单台主机单个采集器的执行状态
+const (
+	AssetRefreshHostPending    = "pending"
+	AssetRefreshHostDispatched = "dispatched"
+	AssetRefreshHostCompleted  = "completed"
+	AssetRefreshHostFailed     = "failed"
+	AssetRefreshHostTimeout    = "timeout"
+)
+
+// AssetRefreshTask 资产按需采集任务（由 Manager 创建，AgentCenter 下发到 collector 插件）
+type AssetRefreshTask struct {
+	ID             uint               `gorm:"primaryKey" json:"id"`
+	TargetHosts    StringArray        `gorm:"type:json" json:"target_hosts"`
+	Handlers       StringArray        `gorm:"type:json" json:"handlers"` // 采集器名称（process、port 等）
+	Status         AssetRefreshStatus `gorm:"size:32;default:pending;index" json:"status"`
+	TotalCount     int                `gorm:"default:0" json:"total_count"` // 主机数 × 采集器数
+	CompletedCount int                `gorm:"default:0" json:"completed_count"`
+	FailedCount    int                `gorm:"default:0" json:"failed_count"`
+	Message        string             `gorm:"type:text" json:"message"`
+	CreatedBy      string             `gorm:"size:64" json:"created_by"`
+	DispatchedAt   *LocalTime         `json:"dispatched_at,omitempty"`
+	CreatedAt      LocalTime          `json:"created_at"`
+	UpdatedAt      LocalTime          `json:"updated_at"`
+	CompletedAt    *LocalTime         `json:"completed_at,omitempty"`
+}
+
+// TableName 指定表名
+func (AssetRefreshTask) TableName() string {
+	return "asset_refresh_tasks"
+}
+
+// AssetRefreshHost 资产按需采集任务的主机明细（每台主机每个采集器一行）
+type AssetRefreshHost struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TaskID       uint       `gorm:"not null;index:idx_asset_refresh_host_task" json:"task_id"`
+	HostID       string     `gorm:"size:64;not null;index:idx_asset_refresh_host_task" json:"host_id"`
+	Handler      string     `gorm:"size:32;not null" json:"handler"`
+	Status       string     `gorm:"size:32;default:pending" json:"status"`
+	Count        int        `gorm:"default:0" json:"count"` // 采集到的资产数量
+	ErrorMessage string     `gorm:"type:text" json:"error_message"`
+	DispatchedAt *LocalTime `json:"dispatched_at,omitempty"`
+	CompletedAt  *LocalTime `json:"completed_at,omitempty"`
+}
+
+// TableName 指定表名
+func (AssetRefreshHost) TableName() string {
+	return "asset_refresh_hosts"
+}
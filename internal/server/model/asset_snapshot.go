@@ -0,0 +1,16 @@
+// Package model 提供数据库模型定义
+package model
+
+// AssetSnapshot 主机每类资产最近一次应用的快照（分块增量上报的基准）
+type AssetSnapshot struct {
+	HostID     string    `gorm:"primaryKey;column:host_id;type:varchar(64);not null" json:"host_id"`
+	DataType   int32     `gorm:"primaryKey;column:data_type;not null" json:"data_type"`
+	SnapshotID string    `gorm:"column:snapshot_id;type:varchar(128);not null" json:"snapshot_id"`
+	Mode       string    `gorm:"column:mode;type:varchar(16)" json:"mode"` // full / delta
+	AppliedAt  LocalTime `gorm:"column:applied_at;type:timestamp;not null" json:"applied_at"`
+}
+
+// TableName 指定表名
+func (AssetSnapshot) TableName() string {
+	return "asset_snapshots"
+}
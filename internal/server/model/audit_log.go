@@ -0,0 +1,71 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// AuditResult 审计操作结果
+type AuditResult string
+
+const (
+	AuditResultSuccess AuditResult = "success" // 操作成功
+	AuditResultFailure AuditResult = "failure" // 操作失败（含权限拒绝、参数错误）
+)
+
+// AuditChange 单个字段的变更前后值
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges 字段名到变更前后值的映射，用于 JSON 字段
+type AuditChanges map[string]AuditChange
+
+// Value 实现 driver.Valuer 接口
+func (a AuditChanges) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (a *AuditChanges) Scan(value interface{}) error {
+	if value == nil {
+		*a = AuditChanges{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// AuditLog 操作审计日志模型
+// 只追加：不提供修改和删除接口；开启哈希链后每条记录的 Hash 覆盖上一条记录的 Hash，用于发现篡改和删除
+type AuditLog struct {
+	ID         uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Actor      string       `gorm:"column:actor;type:varchar(64);index:idx_audit_actor" json:"actor"`
+	ActorRole  string       `gorm:"column:actor_role;type:varchar(64)" json:"actor_role"`
+	SourceIP   string       `gorm:"column:source_ip;type:varchar(64)" json:"source_ip"`
+	Action     string       `gorm:"column:action;type:varchar(64);not null;index:idx_audit_action" json:"action"` // 操作，如 policy.update
+	Resource   string       `gorm:"column:resource;type:varchar(32);index:idx_audit_resource" json:"resource"`    // 资源类型，如 policy
+	TargetIDs  StringArray  `gorm:"column:target_ids;type:json" json:"target_ids"`
+	Method     string       `gorm:"column:method;type:varchar(10)" json:"method"`
+	Path       string       `gorm:"column:path;type:varchar(255)" json:"path"`
+	StatusCode int          `gorm:"column:status_code" json:"status_code"`
+	Result     AuditResult  `gorm:"column:result;type:varchar(16);index:idx_audit_result" json:"result"`
+	Message    string       `gorm:"column:message;type:varchar(512)" json:"message"` // 失败原因
+	Changes    AuditChanges `gorm:"column:changes;type:json" json:"changes"`
+	TokenID    uint         `gorm:"column:token_id;index:idx_audit_token" json:"token_id,omitempty"` // 通过 API Token 访问时的 Token ID
+	PrevHash   string       `gorm:"column:prev_hash;type:varchar(64)" json:"prev_hash,omitempty"`
+	Hash       string       `gorm:"column:hash;type:varchar(64)" json:"hash,omitempty"`
+	CreatedAt  LocalTime    `gorm:"column:created_at;type:timestamp;not null;index:idx_audit_created_at" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
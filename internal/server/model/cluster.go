@@ -0,0 +1,38 @@
+// Package model 提供数据库模型定义
+package model
+
+// ClusterInstance AgentCenter 集群实例（实例定期刷新心跳，超时视为下线）
+type ClusterInstance struct {
+	InstanceID  string    `gorm:"primaryKey;column:instance_id;type:varchar(128);not null" json:"instance_id"`
+	RPCAddress  string    `gorm:"column:rpc_address;type:varchar(255);not null" json:"rpc_address"`
+	HeartbeatAt LocalTime `gorm:"column:heartbeat_at;type:timestamp;not null;index" json:"heartbeat_at"`
+}
+
+// TableName 指定表名
+func (ClusterInstance) TableName() string {
+	return "cluster_instances"
+}
+
+// AgentRoute Agent 连接归属（记录 Agent 当前连接在哪个 AgentCenter 实例上）
+type AgentRoute struct {
+	AgentID     string    `gorm:"primaryKey;column:agent_id;type:varchar(64);not null" json:"agent_id"`
+	InstanceID  string    `gorm:"column:instance_id;type:varchar(128);not null;index" json:"instance_id"`
+	ConnectedAt LocalTime `gorm:"column:connected_at;type:timestamp;not null" json:"connected_at"`
+}
+
+// TableName 指定表名
+func (AgentRoute) TableName() string {
+	return "cluster_agent_routes"
+}
+
+// ClusterLease 集群租约（用于 leader 选举，持有者需在过期前续约）
+type ClusterLease struct {
+	Name      string    `gorm:"primaryKey;column:name;type:varchar(64);not null" json:"name"`
+	Holder    string    `gorm:"column:holder;type:varchar(128);not null" json:"holder"`
+	ExpiresAt LocalTime `gorm:"column:expires_at;type:timestamp;not null" json:"expires_at"`
+}
+
+// TableName 指定表名
+func (ClusterLease) TableName() string {
+	return "cluster_leases"
+}
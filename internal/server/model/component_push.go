@@ -5,11 +5,13 @@ package model
 type ComponentPushStatus string
 
 const (
-	ComponentPushStatusPending   ComponentPushStatus = "pending"   // 待推送
-	ComponentPushStatusPushing   ComponentPushStatus = "pushing"   // 推送中
-	ComponentPushStatusSuccess   ComponentPushStatus = "success"   // 推送成功
-	ComponentPushStatusFailed    ComponentPushStatus = "failed"    // 推送失败
-	ComponentPushStatusCancelled ComponentPushStatus = "cancelled" // 已取消
+	ComponentPushStatusPendingApproval ComponentPushStatus = "pending_approval" // 待审批，批准后转为 pending
+	ComponentPushStatusPending         ComponentPushStatus = "pending"          // 待推送
+	ComponentPushStatusPushing         ComponentPushStatus = "pushing"          // 推送中
+	ComponentPushStatusSuccess         ComponentPushStatus = "success"          // 推送成功
+	ComponentPushStatusFailed          ComponentPushStatus = "failed"           // 推送失败
+	ComponentPushStatusCancelled       ComponentPushStatus = "cancelled"        // 已取消
+	ComponentPushStatusRejected        ComponentPushStatus = "rejected"         // 审批被驳回
 )
 
 // ComponentPushRecord 组件推送记录表
@@ -32,6 +34,7 @@ type ComponentPushRecord struct {
 	CreatedAt     LocalTime           `json:"created_at"`                             // 创建时间
 	UpdatedAt     LocalTime           `json:"updated_at"`                             // 更新时间
 	CompletedAt   *LocalTime          `json:"completed_at,omitempty"`                 // 完成时间
+	EmergencyOverride
 
 	// 关联
 	Component *Component `gorm:"foreignKey:ComponentID" json:"component,omitempty"`
@@ -6,6 +6,7 @@ type ComponentPushHostStatus string
 
 const (
 	ComponentPushHostStatusPending ComponentPushHostStatus = "pending" // 待推送
+	ComponentPushHostStatusWaiting ComponentPushHostStatus = "waiting_window" // 等待维护窗口
 	ComponentPushHostStatusSuccess ComponentPushHostStatus = "success" // 推送成功
 	ComponentPushHostStatusFailed  ComponentPushHostStatus = "failed"  // 推送失败
 )
@@ -13,6 +13,19 @@ type Container struct {
 	Status        string    `gorm:"column:status;type:varchar(50)" json:"status"`   // running、stopped 等
 	CreatedAt     string    `gorm:"column:created_at;type:varchar(50)" json:"created_at"`
 	CollectedAt   LocalTime `gorm:"column:collected_at;type:timestamp;not null;index" json:"collected_at"`
+
+	// 运行时详情（通过 CRI / Docker Engine API 采集）
+	PID          int         `gorm:"column:pid" json:"pid"`
+	PodName      string      `gorm:"column:pod_name;type:varchar(255);index" json:"pod_name"`
+	PodNamespace string      `gorm:"column:pod_namespace;type:varchar(255)" json:"pod_namespace"`
+	PodUID       string      `gorm:"column:pod_uid;type:varchar(64)" json:"pod_uid"`
+	Labels       StringMap   `gorm:"column:labels;type:json" json:"labels"`
+	Privileged   bool        `gorm:"column:privileged;default:false" json:"privileged"`
+	HostNetwork  bool        `gorm:"column:host_network;default:false" json:"host_network"`
+	HostPID      bool        `gorm:"column:host_pid;default:false" json:"host_pid"`
+	HostIPC      bool        `gorm:"column:host_ipc;default:false" json:"host_ipc"`
+	Capabilities StringArray `gorm:"column:capabilities;type:json" json:"capabilities"`
+	HostMounts   StringArray `gorm:"column:host_mounts;type:json" json:"host_mounts"`
 }
 
 // TableName 指定表名
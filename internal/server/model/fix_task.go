@@ -1,6 +1,8 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 )
 
@@ -8,10 +10,12 @@ import (
 type FixTaskStatus string
 
 const (
-	FixTaskStatusPending   FixTaskStatus = "pending"   // 待执行
-	FixTaskStatusRunning   FixTaskStatus = "running"   // 执行中
-	FixTaskStatusCompleted FixTaskStatus = "completed" // 已完成
-	FixTaskStatusFailed    FixTaskStatus = "failed"    // 失败
+	FixTaskStatusPendingApproval FixTaskStatus = "pending_approval" // 待审批，批准后转为 pending
+	FixTaskStatusPending         FixTaskStatus = "pending"          // 待执行
+	FixTaskStatusRunning         FixTaskStatus = "running"          // 执行中
+	FixTaskStatusCompleted       FixTaskStatus = "completed"        // 已完成
+	FixTaskStatusFailed          FixTaskStatus = "failed"           // 失败
+	FixTaskStatusRejected        FixTaskStatus = "rejected"         // 审批被驳回
 )
 
 // FixResultStatus 修复结果状态
@@ -35,7 +39,8 @@ type FixTaskHostStatus struct {
 	OSFamily     string     `gorm:"type:varchar(50)" json:"os_family"`      // OS 系列（如 rocky, ubuntu）
 	OSVersion    string     `gorm:"type:varchar(50)" json:"os_version"`     // OS 版本
 	RuntimeType  string     `gorm:"type:varchar(20)" json:"runtime_type"`   // 运行时类型（vm, docker, k8s）
-	Status       string     `gorm:"type:varchar(20);not null;default:'dispatched'" json:"status"` // dispatched, completed, timeout, failed
+	Status       string     `gorm:"type:varchar(20);not null;default:'dispatched'" json:"status"` // waiting_window, dispatched, completed, timeout, failed
+	WaitingReason string    `gorm:"type:varchar(500)" json:"waiting_reason,omitempty"`          // 等待维护窗口的原因（窗口未开启或变更冻结中）
 	DispatchedAt *LocalTime `gorm:"type:datetime" json:"dispatched_at"`
 	CompletedAt  *LocalTime `gorm:"type:datetime" json:"completed_at"`
 	ErrorMessage string     `gorm:"type:text" json:"error_message,omitempty"`
@@ -48,6 +53,9 @@ func (FixTaskHostStatus) TableName() string {
 	return "fix_task_host_status"
 }
 
+// FixTaskHostStatusWaiting 等待维护窗口（窗口开启后由调度器下发）
+const FixTaskHostStatusWaiting = "waiting_window"
+
 // FixTaskHostStatusDispatched 已下发
 const FixTaskHostStatusDispatched = "dispatched"
 
@@ -62,18 +70,23 @@ const FixTaskHostStatusFailed = "failed"
 
 // FixTask 修复任务模型
 type FixTask struct {
-	TaskID       string        `gorm:"primaryKey;column:task_id;type:varchar(64);not null" json:"task_id"`
-	HostIDs      StringArray   `gorm:"column:host_ids;type:json;not null" json:"host_ids"`
-	RuleIDs      StringArray   `gorm:"column:rule_ids;type:json;not null" json:"rule_ids"`
-	Severities   StringArray   `gorm:"column:severities;type:json" json:"severities"`
-	Status       FixTaskStatus `gorm:"column:status;type:varchar(20);default:'pending'" json:"status"`
-	TotalCount   int           `gorm:"column:total_count;type:int;default:0" json:"total_count"`
-	SuccessCount int           `gorm:"column:success_count;type:int;default:0" json:"success_count"`
-	FailedCount  int           `gorm:"column:failed_count;type:int;default:0" json:"failed_count"`
-	Progress     int           `gorm:"column:progress;type:int;default:0" json:"progress"` // 进度百分比 0-100
-	CreatedBy    string        `gorm:"column:created_by;type:varchar(64)" json:"created_by"`
-	CreatedAt    LocalTime     `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
-	CompletedAt  *LocalTime    `gorm:"column:completed_at;type:timestamp" json:"completed_at"`
+	TaskID        string        `gorm:"primaryKey;column:task_id;type:varchar(64);not null" json:"task_id"`
+	HostIDs       StringArray   `gorm:"column:host_ids;type:json;not null" json:"host_ids"`
+	RuleIDs       StringArray   `gorm:"column:rule_ids;type:json;not null" json:"rule_ids"`
+	Severities    StringArray   `gorm:"column:severities;type:json" json:"severities"`
+	BusinessLines StringArray   `gorm:"column:business_lines;type:json" json:"business_lines"` // 目标主机所属业务线（用于按业务线范围过滤）
+	Status        FixTaskStatus `gorm:"column:status;type:varchar(20);default:'pending'" json:"status"`
+	TotalCount    int           `gorm:"column:total_count;type:int;default:0" json:"total_count"`
+	SuccessCount  int           `gorm:"column:success_count;type:int;default:0" json:"success_count"`
+	FailedCount   int           `gorm:"column:failed_count;type:int;default:0" json:"failed_count"`
+	Progress      int           `gorm:"column:progress;type:int;default:0" json:"progress"` // 进度百分比 0-100
+	CreatedBy     string        `gorm:"column:created_by;type:varchar(64)" json:"created_by"`
+	CreatedAt     LocalTime     `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CompletedAt   *LocalTime    `gorm:"column:completed_at;type:timestamp" json:"completed_at"`
+	// 创建任务时的规则快照（含修复命令），下发时使用快照而不是规则的当前内容，
+	// 避免审批通过后修改规则的修复命令；为空时（旧任务）使用规则的当前内容
+	RuleSnapshot FixRuleSnapshots `gorm:"column:rule_snapshot;type:json" json:"rule_snapshot,omitempty"`
+	EmergencyOverride
 }
 
 // TableName 指定表名
@@ -81,6 +94,76 @@ func (FixTask) TableName() string {
 	return "fix_tasks"
 }
 
+// FixRuleSnapshot 修复任务中一条规则的快照
+type FixRuleSnapshot struct {
+	RuleID       string      `json:"rule_id"`
+	PolicyID     string      `json:"policy_id"`
+	Category     string      `json:"category"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	Severity     string      `json:"severity"`
+	Enabled      bool        `json:"enabled"`
+	RuntimeTypes StringArray `json:"runtime_types"`
+	CheckConfig  CheckConfig `json:"check"`
+	FixConfig    FixConfig   `json:"fix"`
+}
+
+// NewFixRuleSnapshot 创建规则快照
+func NewFixRuleSnapshot(rule *Rule) FixRuleSnapshot {
+	return FixRuleSnapshot{
+		RuleID:       rule.RuleID,
+		PolicyID:     rule.PolicyID,
+		Category:     rule.Category,
+		Title:        rule.Title,
+		Description:  rule.Description,
+		Severity:     rule.Severity,
+		Enabled:      rule.Enabled,
+		RuntimeTypes: rule.RuntimeTypes,
+		CheckConfig:  rule.CheckConfig,
+		FixConfig:    rule.FixConfig,
+	}
+}
+
+// Rule 将快照还原为规则
+func (s FixRuleSnapshot) Rule() Rule {
+	return Rule{
+		RuleID:       s.RuleID,
+		PolicyID:     s.PolicyID,
+		Category:     s.Category,
+		Title:        s.Title,
+		Description:  s.Description,
+		Severity:     s.Severity,
+		Enabled:      s.Enabled,
+		RuntimeTypes: s.RuntimeTypes,
+		CheckConfig:  s.CheckConfig,
+		FixConfig:    s.FixConfig,
+	}
+}
+
+// FixRuleSnapshots 规则快照列表，用于 JSON 字段
+type FixRuleSnapshots []FixRuleSnapshot
+
+// Value 实现 driver.Valuer 接口
+func (s FixRuleSnapshots) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (s *FixRuleSnapshots) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
 // FixResult 修复结果模型
 type FixResult struct {
 	ResultID  string          `gorm:"primaryKey;column:result_id;type:varchar(64);not null" json:"result_id"`
@@ -54,6 +54,30 @@ func (a *StringArray) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, a)
 }
 
+// StringMap 字符串键值对类型，用于 JSON 字段（如容器标签）
+type StringMap map[string]string
+
+// Value 实现 driver.Valuer 接口
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (m *StringMap) Scan(value interface{}) error {
+	if value == nil {
+		*m = StringMap{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, m)
+}
+
 // Host 主机信息模型
 type Host struct {
 	HostID        string      `gorm:"primaryKey;column:host_id;type:varchar(64);not null" json:"host_id"`
@@ -100,6 +124,9 @@ type Host struct {
 	PodUID       string `gorm:"column:pod_uid;type:varchar(64)" json:"pod_uid"`              // Pod UID（K8s 环境）
 	// Agent 版本信息
 	AgentVersion string `gorm:"column:agent_version;type:varchar(32)" json:"agent_version"` // Agent 当前版本号
+	// Agent 资源保护状态
+	GuardState  string `gorm:"column:guard_state;type:varchar(20)" json:"guard_state"` // normal / throttled / suspended
+	GuardStatus string `gorm:"column:guard_status;type:text" json:"guard_status"`      // JSON 对象，包含原因、截止时间和资源占用
 	// 标签
 	Tags      StringArray `gorm:"column:tags;type:json" json:"tags"`
 	CreatedAt LocalTime   `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
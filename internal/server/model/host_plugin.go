@@ -2,6 +2,9 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+
 	"gorm.io/gorm"
 )
 
@@ -24,6 +27,7 @@ type HostPlugin struct {
 	Version   string           `gorm:"size:32" json:"version"`                // 实际运行的版本
 	Status    HostPluginStatus `gorm:"size:32;default:running" json:"status"` // 插件状态
 	StartTime *LocalTime       `json:"start_time,omitempty"`                  // 启动时间
+	LimitHits *PluginLimitHits `gorm:"type:json" json:"limit_hits,omitempty"` // 资源限制触发次数（Agent 未启用 cgroup 时为空）
 	UpdatedAt LocalTime        `json:"updated_at"`                            // 更新时间
 	DeletedAt gorm.DeletedAt   `gorm:"index" json:"-"`                        // 软删除
 
@@ -36,6 +40,38 @@ func (HostPlugin) TableName() string {
 	return "host_plugins"
 }
 
+// PluginLimitHits 插件触发资源限制的累计次数（由 Agent 心跳上报，插件重启后清零）
+type PluginLimitHits struct {
+	MemoryMax        int64 `json:"memory_max"`         // 内存达到上限的次数
+	OOMKill          int64 `json:"oom_kill"`           // OOM Kill 次数
+	CPUThrottled     int64 `json:"cpu_throttled"`      // CPU 被限流的周期数
+	CPUThrottledUsec int64 `json:"cpu_throttled_usec"` // CPU 被限流的总时长（微秒）
+	PidsMax          int64 `json:"pids_max"`           // 达到进程数上限的次数
+}
+
+// Any 是否触发过资源限制
+func (h *PluginLimitHits) Any() bool {
+	return h != nil && (h.MemoryMax > 0 || h.OOMKill > 0 || h.CPUThrottled > 0 || h.PidsMax > 0)
+}
+
+// Value 实现 driver.Valuer 接口
+func (h PluginLimitHits) Value() (driver.Value, error) {
+	return json.Marshal(h)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (h *PluginLimitHits) Scan(value interface{}) error {
+	if value == nil {
+		*h = PluginLimitHits{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, h)
+}
+
 // HostPluginWithLatest 带有最新版本信息的主机插件
 type HostPluginWithLatest struct {
 	HostPlugin
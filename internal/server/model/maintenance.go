@@ -0,0 +1,65 @@
+// Package model 提供数据库模型定义
+package model
+
+// MaintenanceScope 维护窗口适用范围
+type MaintenanceScope string
+
+const (
+	MaintenanceScopeGlobal       MaintenanceScope = "global"        // 所有主机
+	MaintenanceScopeBusinessLine MaintenanceScope = "business_line" // 指定业务线的主机
+	MaintenanceScopeHostTags     MaintenanceScope = "host_tags"     // 带有指定标签的主机
+	MaintenanceScopeHosts        MaintenanceScope = "hosts"         // 指定主机
+)
+
+// MaintenanceWindow 维护窗口
+// 修复任务和 Agent/插件更新只在主机所属的维护窗口内下发；主机不属于任何窗口时不受限制
+type MaintenanceWindow struct {
+	ID              uint             `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name            string           `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Description     string           `gorm:"column:description;type:varchar(500)" json:"description"`
+	Cron            string           `gorm:"column:cron;type:varchar(100);not null" json:"cron"`                      // 窗口开始时间（5 段 cron：分 时 日 月 周）
+	DurationMinutes int              `gorm:"column:duration_minutes;type:int;not null" json:"duration_minutes"`       // 窗口持续时长（分钟）
+	Timezone        string           `gorm:"column:timezone;type:varchar(64);not null;default:'UTC'" json:"timezone"` // cron 所在时区（IANA 名称，如 Asia/Shanghai）
+	Scope           MaintenanceScope `gorm:"column:scope;type:varchar(20);not null" json:"scope"`                     // 适用范围
+	ScopeValues     StringArray      `gorm:"column:scope_values;type:json" json:"scope_values"`                       // 业务线、标签或主机 ID
+	Enabled         bool             `gorm:"column:enabled;type:tinyint(1);not null;default:0" json:"enabled"`
+	CreatedBy       string           `gorm:"column:created_by;type:varchar(64)" json:"created_by"`
+	CreatedAt       LocalTime        `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       LocalTime        `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (MaintenanceWindow) TableName() string {
+	return "maintenance_windows"
+}
+
+// ChangeFreeze 全局变更冻结
+// 冻结期间所有主机都不下发修复任务和 Agent/插件更新（紧急放行的任务除外）
+type ChangeFreeze struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Reason    string    `gorm:"column:reason;type:varchar(500)" json:"reason"`
+	StartAt   LocalTime `gorm:"column:start_at;type:timestamp;not null;index" json:"start_at"`
+	EndAt     LocalTime `gorm:"column:end_at;type:timestamp;not null;index" json:"end_at"`
+	Enabled   bool      `gorm:"column:enabled;type:tinyint(1);not null;default:0" json:"enabled"`
+	CreatedBy string    `gorm:"column:created_by;type:varchar(64)" json:"created_by"`
+	CreatedAt LocalTime `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt LocalTime `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ChangeFreeze) TableName() string {
+	return "change_freezes"
+}
+
+// EmergencyOverride 紧急放行记录：任务不受维护窗口和变更冻结限制，立即下发
+type EmergencyOverride struct {
+	EmergencyReason string     `gorm:"column:emergency_reason;type:varchar(500)" json:"emergency_reason,omitempty"` // 放行原因，非空表示已紧急放行
+	EmergencyBy     string     `gorm:"column:emergency_by;type:varchar(64)" json:"emergency_by,omitempty"`
+	EmergencyAt     *LocalTime `gorm:"column:emergency_at;type:timestamp" json:"emergency_at,omitempty"`
+}
+
+// Overridden 是否已紧急放行
+func (o EmergencyOverride) Overridden() bool {
+	return o.EmergencyReason != ""
+}
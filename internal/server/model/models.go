@@ -151,6 +151,7 @@ var (
 		&FixResult{},
 		&FixTaskHostStatus{},
 		&User{},
+		&Role{},
 		&Process{},
 		&Port{},
 		&AssetUser{},
@@ -179,5 +180,23 @@ var (
 		&FIMEvent{},
 		&FIMTask{},
 		&FIMTaskHostStatus{},
+		&AssetRefreshTask{},
+		&AssetRefreshHost{},
+		&AssetSnapshot{},
+		&RecordReceipt{},
+		&ClusterInstance{},
+		&AgentRoute{},
+		&ClusterLease{},
+		&AgentCenterEndpoint{},
+		&AgentDiagnostics{},
+		&AuditLog{},
+		&APIToken{},
+		&UserSession{},
+		&LoginFailure{},
+		&PasswordHistory{},
+		&ApprovalRequest{},
+		&MaintenanceWindow{},
+		&ChangeFreeze{},
+		&PluginConfigDeferral{},
 	}
 )
@@ -30,6 +30,7 @@ type NotifyCategory string
 const (
 	NotifyCategoryBaselineAlert NotifyCategory = "baseline_alert" // 基线告警通知
 	NotifyCategoryAgentOffline  NotifyCategory = "agent_offline"  // Agent 离线通知
+	NotifyCategoryApproval      NotifyCategory = "approval"       // 高危操作审批通知
 )
 
 // NotificationSeverity 通知等级
@@ -2,6 +2,9 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+
 	"gorm.io/gorm"
 )
 
@@ -25,6 +28,8 @@ type PluginConfig struct {
 	Signature    string         `gorm:"size:256" json:"signature"`                              // 签名
 	DownloadURLs StringArray    `gorm:"type:json" json:"download_urls"`                         // 下载地址列表 (JSON 数组)
 	Detail       string         `gorm:"type:text" json:"detail"`                                // 配置详情 (JSON 字符串)
+	Limits       PluginLimits   `gorm:"type:json" json:"limits"`                                // 资源限制 (cgroup v2)
+	Sandbox      PluginSandbox  `gorm:"type:json" json:"sandbox"`                               // 进程加固选项
 	Enabled      bool           `gorm:"default:true" json:"enabled"`                            // 是否启用
 	Description  string         `gorm:"size:256" json:"description"`                            // 描述
 	CreatedAt    LocalTime      `json:"created_at"`                                             // 创建时间
@@ -36,3 +41,70 @@ type PluginConfig struct {
 func (PluginConfig) TableName() string {
 	return "plugin_configs"
 }
+
+// PluginLimits 插件资源限制（Agent 为每个插件创建独立的 cgroup v2 实施，0 表示不限制）
+type PluginLimits struct {
+	CPUPercent  int32 `json:"cpu_percent"`   // CPU 配额（单核百分比，200 表示两个核）
+	MemoryMaxMB int64 `json:"memory_max_mb"` // 内存上限（MB）
+	IOWeight    int32 `json:"io_weight"`     // IO 权重（1-10000，0 表示默认权重 100）
+	PidsMax     int32 `json:"pids_max"`      // 最大进程/线程数
+}
+
+// Value 实现 driver.Valuer 接口
+func (l PluginLimits) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (l *PluginLimits) Scan(value interface{}) error {
+	if value == nil {
+		*l = PluginLimits{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// PluginSandbox 插件进程加固选项（变更后 Agent 重启插件）
+type PluginSandbox struct {
+	NoNewPrivs       bool        `json:"no_new_privs"`      // 禁止通过 setuid 等方式提权
+	DropCapabilities StringArray `json:"drop_capabilities"` // 丢弃的 capabilities，ALL 表示全部丢弃
+	MinimalEnv       bool        `json:"minimal_env"`       // 仅传递最小环境变量
+}
+
+// Value 实现 driver.Valuer 接口
+func (s PluginSandbox) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (s *PluginSandbox) Scan(value interface{}) error {
+	if value == nil {
+		*s = PluginSandbox{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// PluginConfigDeferral 等待维护窗口的插件配置下发
+// 插件配置更新时不在维护窗口内的在线主机记入此表，窗口开启（或紧急放行）后由持有该 Agent 连接的 AgentCenter 实例补发并删除；
+// Agent 重连时会同步最新配置，同时删除记录
+type PluginConfigDeferral struct {
+	HostID    string    `gorm:"column:host_id;primaryKey;size:64" json:"host_id"`
+	Reason    string    `gorm:"column:reason;type:varchar(500)" json:"reason"` // 等待原因
+	CreatedAt LocalTime `json:"created_at"`                                    // 开始等待时间
+	UpdatedAt LocalTime `json:"updated_at"`
+	EmergencyOverride
+}
+
+// TableName 返回表名
+func (PluginConfigDeferral) TableName() string {
+	return "plugin_config_deferrals"
+}
@@ -3,19 +3,34 @@ package model
 
 // Process 进程资产模型
 type Process struct {
-	ID          string    `gorm:"primaryKey;column:id;type:varchar(128);not null" json:"id"`
-	HostID      string    `gorm:"column:host_id;type:varchar(64);not null;index" json:"host_id"`
-	PID         string    `gorm:"column:pid;type:varchar(20);not null" json:"pid"`
-	PPID        string    `gorm:"column:ppid;type:varchar(20)" json:"ppid"`
-	Cmdline     string    `gorm:"column:cmdline;type:text" json:"cmdline"`
-	Exe         string    `gorm:"column:exe;type:varchar(512)" json:"exe"`
-	ExeHash     string    `gorm:"column:exe_hash;type:varchar(64)" json:"exe_hash"`
-	ContainerID string    `gorm:"column:container_id;type:varchar(64)" json:"container_id"`
-	UID         string    `gorm:"column:uid;type:varchar(20)" json:"uid"`
-	GID         string    `gorm:"column:gid;type:varchar(20)" json:"gid"`
-	Username    string    `gorm:"column:username;type:varchar(100)" json:"username"`
-	Groupname   string    `gorm:"column:groupname;type:varchar(100)" json:"groupname"`
-	CollectedAt LocalTime `gorm:"column:collected_at;type:timestamp;not null;index" json:"collected_at"`
+	ID           string      `gorm:"primaryKey;column:id;type:varchar(128);not null" json:"id"`
+	HostID       string      `gorm:"column:host_id;type:varchar(64);not null;index" json:"host_id"`
+	PID          string      `gorm:"column:pid;type:varchar(20);not null" json:"pid"`
+	PPID         string      `gorm:"column:ppid;type:varchar(20)" json:"ppid"`
+	Cmdline      string      `gorm:"column:cmdline;type:text" json:"cmdline"`
+	Exe          string      `gorm:"column:exe;type:varchar(512)" json:"exe"`
+	ExeHash      string      `gorm:"column:exe_hash;type:varchar(64)" json:"exe_hash"`
+	ContainerID  string      `gorm:"column:container_id;type:varchar(64)" json:"container_id"`
+	UID          string      `gorm:"column:uid;type:varchar(20)" json:"uid"`
+	GID          string      `gorm:"column:gid;type:varchar(20)" json:"gid"`
+	Username     string      `gorm:"column:username;type:varchar(100)" json:"username"`
+	Groupname    string      `gorm:"column:groupname;type:varchar(100)" json:"groupname"`
+	StartTime    *LocalTime  `gorm:"column:start_time" json:"start_time,omitempty"`
+	Cwd          string      `gorm:"column:cwd;type:varchar(512)" json:"cwd"`
+	ExeInode     uint64      `gorm:"column:exe_inode" json:"exe_inode"`
+	ExeDev       uint64      `gorm:"column:exe_dev" json:"exe_dev"`
+	ExeDeleted   bool        `gorm:"column:exe_deleted;default:false" json:"exe_deleted"`
+	ExeMemfd     bool        `gorm:"column:exe_memfd;default:false" json:"exe_memfd"`
+	CapInh       string      `gorm:"column:cap_inh;type:varchar(16)" json:"cap_inh"`
+	CapPrm       string      `gorm:"column:cap_prm;type:varchar(16)" json:"cap_prm"`
+	CapEff       string      `gorm:"column:cap_eff;type:varchar(16)" json:"cap_eff"`
+	CapBnd       string      `gorm:"column:cap_bnd;type:varchar(16)" json:"cap_bnd"`
+	CapAmb       string      `gorm:"column:cap_amb;type:varchar(16)" json:"cap_amb"`
+	Namespaces   StringMap   `gorm:"column:namespaces;type:json" json:"namespaces"`
+	PackageName  string      `gorm:"column:package_name;type:varchar(255)" json:"package_name"`
+	ExeIntegrity string      `gorm:"column:exe_integrity;type:varchar(16);index" json:"exe_integrity"` // verified/modified/unpackaged
+	Suspicious   StringArray `gorm:"column:suspicious;type:json" json:"suspicious"`                    // 可疑特征
+	CollectedAt  LocalTime   `gorm:"column:collected_at;type:timestamp;not null;index" json:"collected_at"`
 }
 
 // TableName 指定表名
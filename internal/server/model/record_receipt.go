@@ -0,0 +1,16 @@
+// Package model 提供数据库模型定义
+package model
+
+// RecordReceipt 已处理的 Agent 事件类记录回执（按 host_id + seq 去重，防止重发的记录被重复入库）
+type RecordReceipt struct {
+	HostID     string    `gorm:"primaryKey;column:host_id;type:varchar(64);not null" json:"host_id"`
+	Seq        uint64    `gorm:"primaryKey;column:seq;autoIncrement:false" json:"seq"`
+	DataType   int32     `gorm:"column:data_type;not null" json:"data_type"`
+	ClaimID    string    `gorm:"column:claim_id;type:varchar(36);not null;default:''" json:"-"` // 批量认领标识，区分本次写入与已存在的回执
+	ReceivedAt LocalTime `gorm:"column:received_at;type:timestamp;not null;index" json:"received_at"`
+}
+
+// TableName 指定表名
+func (RecordReceipt) TableName() string {
+	return "agent_record_receipts"
+}
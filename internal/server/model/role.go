@@ -0,0 +1,18 @@
+// Package model 提供数据库模型定义
+package model
+
+// Role 自定义角色（内置角色不存储在数据库中）
+type Role struct {
+	ID            uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name          string      `gorm:"column:name;type:varchar(64);uniqueIndex;not null" json:"name"`
+	Description   string      `gorm:"column:description;type:varchar(255)" json:"description"`
+	Permissions   StringArray `gorm:"column:permissions;type:json" json:"permissions"`       // 权限标识列表，如 hosts:read
+	BusinessLines StringArray `gorm:"column:business_lines;type:json" json:"business_lines"` // 可访问的业务线代码，为空表示不限制
+	CreatedAt     LocalTime   `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     LocalTime   `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
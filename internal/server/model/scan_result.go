@@ -29,6 +29,11 @@ type ScanResult struct {
 	CheckedAt     LocalTime    `gorm:"column:checked_at;type:timestamp;not null" json:"checked_at"`
 	CreatedAt     LocalTime    `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
 
+	// 容器检查结果归属（宿主机检查结果为空）
+	ContainerID    string `gorm:"column:container_id;type:varchar(128);not null;default:'';index" json:"container_id,omitempty"`
+	ContainerName  string `gorm:"column:container_name;type:varchar(255)" json:"container_name,omitempty"`
+	ContainerImage string `gorm:"column:container_image;type:varchar(255)" json:"container_image,omitempty"`
+
 	// 关联关系（可选，主要用于查询时预加载）
 	Host Host `gorm:"foreignKey:HostID;references:HostID" json:"host,omitempty"`
 	Rule Rule `gorm:"foreignKey:RuleID;references:RuleID" json:"rule,omitempty"`
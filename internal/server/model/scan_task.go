@@ -38,6 +38,13 @@ type TargetConfig struct {
 	HostIDs     []string    `json:"host_ids,omitempty"`
 	OSFamily    []string    `json:"os_family,omitempty"`
 	RuntimeType RuntimeType `json:"runtime_type,omitempty"` // 运行时类型筛选：vm/docker/k8s
+
+	// 容器检查：由宿主机 Agent 进入运行中的容器执行检查（runtime_type=docker 时自动启用）
+	ScanContainers bool     `json:"scan_containers,omitempty"` // 宿主机检查时同时检查其上运行的容器
+	ContainerIDs   []string `json:"container_ids,omitempty"`   // 仅检查指定容器（为空表示全部运行中的容器）
+
+	// 业务线范围：创建者受业务线范围限制时自动设置，任务只下发到这些业务线的主机
+	BusinessLines []string `json:"business_lines,omitempty"`
 }
 
 // Value 实现 driver.Valuer 接口
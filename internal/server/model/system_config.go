@@ -48,3 +48,141 @@ func DefaultAlertConfig() AlertConfig {
 		EnablePeriodicSummary: true,
 	}
 }
+
+// ApprovalConfig 高危操作审批策略（key: approval_config, category: approval）
+// 命中策略的操作需要另一位拥有相同权限的用户批准后才会执行
+type ApprovalConfig struct {
+	FixTask      FixTaskApprovalRule `json:"fix_task"`
+	AgentPush    bool                `json:"agent_push"`    // Agent 更新推送
+	HostDelete   bool                `json:"host_delete"`   // 删除主机
+	PolicyDelete bool                `json:"policy_delete"` // 删除策略（含批量删除）
+}
+
+// FixTaskApprovalRule 修复任务审批规则，主机数和严重级别满足其一即需要审批
+type FixTaskApprovalRule struct {
+	Enabled       bool     `json:"enabled"`
+	HostThreshold int      `json:"host_threshold"` // 目标主机数超过该值时需要审批，0 表示所有修复任务都需要审批
+	Severities    []string `json:"severities"`     // 修复的规则包含这些严重级别时需要审批
+}
+
+// DefaultApprovalConfig 默认审批策略：全部关闭
+func DefaultApprovalConfig() ApprovalConfig {
+	return ApprovalConfig{
+		FixTask: FixTaskApprovalRule{
+			HostThreshold: 10,
+			Severities:    []string{"critical"},
+		},
+	}
+}
+
+// AuthConfig 登录认证配置（key: auth_config, category: auth）
+type AuthConfig struct {
+	LocalLoginEnabled bool           `json:"local_login_enabled"` // 是否允许本地账号密码登录
+	BreakGlassUsers   []string       `json:"break_glass_users"`   // 关闭本地登录后仍可用本地密码登录的应急账号
+	DefaultRole       string         `json:"default_role"`        // 目录组未匹配时的默认角色，为空表示拒绝登录
+	OIDC              OIDCConfig     `json:"oidc"`
+	LDAP              LDAPConfig     `json:"ldap"`
+	GroupMappings     []GroupMapping `json:"group_mappings"` // 目录组到角色和业务线的映射，按顺序匹配
+	Session           SessionConfig  `json:"session"`
+	Lockout           LockoutConfig  `json:"lockout"`
+	PasswordPolicy    PasswordPolicy `json:"password_policy"` // 仅对本地账号生效
+	TOTPRoles         []string       `json:"totp_roles"`      // 允许绑定 TOTP 两步验证的角色
+}
+
+// SessionConfig 登录会话配置
+type SessionConfig struct {
+	AccessTokenMinutes int `json:"access_token_minutes"` // 访问令牌（JWT）有效期，过期后前端用刷新令牌换取
+	RefreshTokenHours  int `json:"refresh_token_hours"`  // 会话最长有效期，刷新令牌轮换不延长，到期后需重新登录
+}
+
+// LockoutConfig 登录失败锁定配置，次数为 0 表示不限制
+type LockoutConfig struct {
+	MaxUserFailures int `json:"max_user_failures"` // 同一用户在锁定时长内允许的失败次数
+	MaxIPFailures   int `json:"max_ip_failures"`   // 同一来源 IP 在锁定时长内允许的失败次数
+	LockMinutes     int `json:"lock_minutes"`      // 失败次数的统计窗口，也是锁定时长
+}
+
+// PasswordPolicy 密码策略
+type PasswordPolicy struct {
+	MinLength    int `json:"min_length"`
+	MinClasses   int `json:"min_classes"`   // 至少包含的字符类型数（大写字母、小写字母、数字、符号）
+	HistoryCount int `json:"history_count"` // 不能与最近 N 个密码（含当前密码）相同，0 表示不限制
+}
+
+// OIDCConfig OIDC 授权码登录配置
+type OIDCConfig struct {
+	Enabled       bool     `json:"enabled"`
+	DisplayName   string   `json:"display_name"` // 登录页按钮名称
+	Issuer        string   `json:"issuer"`       // IdP 地址，用于发现 /.well-known/openid-configuration
+	ClientID      string   `json:"client_id"`
+	ClientSecret  string   `json:"client_secret"`
+	RedirectURL   string   `json:"redirect_url"`   // 回调地址，如 https://mxsec.example.com/api/v1/auth/oidc/callback
+	Scopes        []string `json:"scopes"`         // 额外的 scope（openid 自动添加）
+	UsernameClaim string   `json:"username_claim"` // 用户名 claim
+	EmailClaim    string   `json:"email_claim"`    // 邮箱 claim
+	GroupsClaim   string   `json:"groups_claim"`   // 组 claim（字符串或字符串数组）
+}
+
+// LDAPConfig LDAP 绑定/搜索登录配置
+type LDAPConfig struct {
+	Enabled            bool   `json:"enabled"`
+	URL                string `json:"url"` // 如 ldap://ldap.example.com:389 或 ldaps://ldap.example.com:636
+	StartTLS           bool   `json:"start_tls"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	BindDN             string `json:"bind_dn"` // 搜索用户使用的服务账号
+	BindPassword       string `json:"bind_password"`
+	BaseDN             string `json:"base_dn"`
+	UserFilter         string `json:"user_filter"` // {username} 替换为转义后的登录名
+	UsernameAttr       string `json:"username_attr"`
+	EmailAttr          string `json:"email_attr"`
+	GroupAttr          string `json:"group_attr"`    // 用户条目上的组属性（如 memberOf），取值为组 DN 时使用第一个 RDN 的值
+	GroupBaseDN        string `json:"group_base_dn"` // 为空时不搜索组条目
+	GroupFilter        string `json:"group_filter"`  // {dn} 替换为用户 DN，{username} 替换为登录名
+	GroupNameAttr      string `json:"group_name_attr"`
+}
+
+// GroupMapping 目录组映射
+type GroupMapping struct {
+	Group         string   `json:"group"`          // 组名（不区分大小写）
+	Role          string   `json:"role"`           // 映射的角色
+	BusinessLines []string `json:"business_lines"` // 可访问的业务线，为空表示不限制
+}
+
+// DefaultAuthConfig 默认认证配置：仅本地登录
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		LocalLoginEnabled: true,
+		BreakGlassUsers:   []string{},
+		OIDC: OIDCConfig{
+			DisplayName:   "SSO",
+			Scopes:        []string{"profile", "email"},
+			UsernameClaim: "preferred_username",
+			EmailClaim:    "email",
+			GroupsClaim:   "groups",
+		},
+		LDAP: LDAPConfig{
+			UserFilter:    "(uid={username})",
+			UsernameAttr:  "uid",
+			EmailAttr:     "mail",
+			GroupAttr:     "memberOf",
+			GroupFilter:   "(member={dn})",
+			GroupNameAttr: "cn",
+		},
+		GroupMappings: []GroupMapping{},
+		Session: SessionConfig{
+			AccessTokenMinutes: 15,
+			RefreshTokenHours:  168,
+		},
+		Lockout: LockoutConfig{
+			MaxUserFailures: 5,
+			MaxIPFailures:   20,
+			LockMinutes:     15,
+		},
+		PasswordPolicy: PasswordPolicy{
+			MinLength:    8,
+			MinClasses:   2,
+			HistoryCount: 3,
+		},
+		TOTPRoles: []string{string(UserRoleAdmin)},
+	}
+}
@@ -1,12 +1,18 @@
 // Package model 提供数据库模型定义
 package model
 
-// UserRole 用户角色
+// UserRole 用户角色（内置角色或 roles 表中的自定义角色名称）
 type UserRole string
 
+// 内置角色，权限定义见 rbac 包
 const (
-	UserRoleAdmin UserRole = "admin"
-	UserRoleUser  UserRole = "user"
+	UserRoleAdmin    UserRole = "admin"
+	UserRoleOperator UserRole = "operator"
+	UserRoleAuditor  UserRole = "auditor"
+	UserRoleViewer   UserRole = "viewer"
+
+	// UserRoleUser 是旧版本的普通用户角色，迁移时转换为 viewer
+	UserRoleUser UserRole = "user"
 )
 
 // UserStatus 用户状态
@@ -17,17 +23,32 @@ const (
 	UserStatusInactive UserStatus = "inactive"
 )
 
+// UserSource 用户来源
+type UserSource string
+
+const (
+	UserSourceLocal   UserSource = "local"   // 本地账号（密码保存在 users 表）
+	UserSourceOIDC    UserSource = "oidc"    // OIDC 单点登录自动创建
+	UserSourceLDAP    UserSource = "ldap"    // LDAP 登录自动创建
+	UserSourceService UserSource = "service" // 服务账号，只能通过 API Token 访问
+)
+
 // User 用户模型
 type User struct {
-	ID        uint       `gorm:"primaryKey;autoIncrement" json:"id"`
-	Username  string     `gorm:"column:username;type:varchar(64);uniqueIndex;not null" json:"username"`
-	Password  string     `gorm:"column:password;type:varchar(255);not null" json:"-"` // 密码不返回给前端
-	Email     string     `gorm:"column:email;type:varchar(255)" json:"email"`
-	Role      UserRole   `gorm:"column:role;type:varchar(20);default:'user'" json:"role"`
-	Status    UserStatus `gorm:"column:status;type:varchar(20);default:'active'" json:"status"`
-	LastLogin *LocalTime `gorm:"column:last_login;type:timestamp" json:"last_login"`
-	CreatedAt LocalTime  `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt LocalTime  `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID              uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Username        string      `gorm:"column:username;type:varchar(64);uniqueIndex;not null" json:"username"`
+	Password        string      `gorm:"column:password;type:varchar(255);not null" json:"-"` // 密码不返回给前端
+	Email           string      `gorm:"column:email;type:varchar(255)" json:"email"`
+	Role            UserRole    `gorm:"column:role;type:varchar(64);default:'viewer'" json:"role"`
+	Status          UserStatus  `gorm:"column:status;type:varchar(20);default:'active'" json:"status"`
+	BusinessLines   StringArray `gorm:"column:business_lines;type:json" json:"business_lines"`        // 可访问的业务线代码（与角色的业务线合并），为空表示不限制
+	Source          UserSource  `gorm:"column:source;type:varchar(20);default:'local'" json:"source"` // SSO 用户的角色和业务线在每次登录时按目录组映射刷新
+	TOTPSecret      string      `gorm:"column:totp_secret;type:varchar(64)" json:"-"`                 // TOTP 密钥（Base32），开始绑定时生成，验证通过后启用
+	TOTPEnabled     bool        `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`        // 是否启用 TOTP 两步验证
+	TOTPLastCounter int64       `gorm:"column:totp_last_counter;default:0" json:"-"`                  // 最近一次使用的 TOTP 时间步，防止验证码重放
+	LastLogin       *LocalTime  `gorm:"column:last_login;type:timestamp" json:"last_login"`
+	CreatedAt       LocalTime   `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       LocalTime   `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,47 @@
+// Package model 提供数据库模型定义
+package model
+
+// UserSession 登录会话，访问令牌（JWT）携带会话 ID，会话吊销后访问令牌和刷新令牌立即失效
+type UserSession struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Username        string     `gorm:"column:username;type:varchar(64);not null;index:idx_user_session_username" json:"username"`
+	RefreshHash     string     `gorm:"column:refresh_hash;type:varchar(64);not null;uniqueIndex" json:"-"` // 当前刷新令牌的 SHA-256
+	PrevRefreshHash string     `gorm:"column:prev_refresh_hash;type:varchar(64);index" json:"-"`           // 上一个刷新令牌的 SHA-256，用于检测刷新令牌重放
+	IP              string     `gorm:"column:ip;type:varchar(64)" json:"ip"`                               // 最近一次登录或刷新的来源 IP
+	UserAgent       string     `gorm:"column:user_agent;type:varchar(255)" json:"user_agent"`              // 登录时的浏览器标识
+	ExpiresAt       LocalTime  `gorm:"column:expires_at;type:timestamp;not null" json:"expires_at"`        // 会话最长有效期，刷新不延长
+	RefreshedAt     *LocalTime `gorm:"column:refreshed_at;type:timestamp" json:"refreshed_at"`             // 最近一次轮换刷新令牌的时间
+	RevokedAt       *LocalTime `gorm:"column:revoked_at;type:timestamp;index" json:"revoked_at"`           // 登出、修改密码、禁用用户或检测到重放时吊销
+	CreatedAt       LocalTime  `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// LoginFailure 登录失败记录，用于按用户和来源 IP 锁定暴力破解
+type LoginFailure struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Username  string    `gorm:"column:username;type:varchar(64);index:idx_login_failure_username" json:"username"`
+	IP        string    `gorm:"column:ip;type:varchar(64);index:idx_login_failure_ip" json:"ip"`
+	CreatedAt LocalTime `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;index:idx_login_failure_created" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginFailure) TableName() string {
+	return "login_failures"
+}
+
+// PasswordHistory 本地账号的历史密码哈希，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint      `gorm:"column:user_id;not null;index:idx_password_history_user" json:"user_id"`
+	Password  string    `gorm:"column:password;type:varchar(255);not null" json:"-"`
+	CreatedAt LocalTime `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
@@ -0,0 +1,6 @@
+// Package testdb 为集成测试提供 MySQL 测试数据库连接（仅在 integration 构建标签下可用）
+//
+// 连接参数通过环境变量 TEST_DB_HOST、TEST_DB_PORT、TEST_DB_USER、TEST_DB_PASSWORD、TEST_DB_NAME 配置：
+//
+//	go test -tags integration ./internal/server/...
+package testdb
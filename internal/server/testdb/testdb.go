@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package testdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DSN 返回测试数据库 DSN（环境变量未设置时使用默认值）
+func DSN() string {
+	host := os.Getenv("TEST_DB_HOST")
+	if host == "" {
+		// 在容器中运行时通过 host.docker.internal 访问宿主机 MySQL（Linux 可通过环境变量指定为 172.17.0.1）
+		if _, err := os.Stat("/.dockerenv"); err == nil {
+			host = "host.docker.internal"
+		} else {
+			host = "127.0.0.1"
+		}
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		env("TEST_DB_USER", "mxsec_user"), env("TEST_DB_PASSWORD", "mxsec_password"),
+		host, env("TEST_DB_PORT", "3306"), env("TEST_DB_NAME", "mxsec_test"))
+}
+
+// Open 连接测试数据库并重建指定模型的表
+// 不创建外键约束，测试可以只准备用到的表和数据
+func Open(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.Open(DSN()), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	if err := db.Migrator().DropTable(models...); err != nil {
+		t.Fatalf("drop test tables: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate test tables: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func env(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"os/user"
 	"regexp"
 	"strconv"
 	"strings"
@@ -37,7 +36,7 @@ func (c *FileKVChecker) Check(ctx context.Context, rule *CheckRule) (*CheckResul
 	expected := rule.Param[2]
 
 	// 读取文件
-	file, err := os.Open(filePath)
+	file, err := os.Open(resolvePath(ctx, filePath))
 	if err != nil {
 		return &CheckResult{
 			Pass:     false,
@@ -132,7 +131,7 @@ func (c *FileExistsChecker) Check(ctx context.Context, rule *CheckRule) (*CheckR
 	}
 
 	filePath := rule.Param[0]
-	_, err := os.Stat(filePath)
+	_, err := os.Stat(resolvePath(ctx, filePath))
 
 	if err == nil {
 		return &CheckResult{
@@ -175,7 +174,7 @@ func (c *FilePermissionChecker) Check(ctx context.Context, rule *CheckRule) (*Ch
 	}
 
 	// 获取文件信息
-	info, err := os.Stat(filePath)
+	info, err := os.Stat(resolvePath(ctx, filePath))
 	if err != nil {
 		return &CheckResult{
 			Pass:     false,
@@ -224,7 +223,7 @@ func (c *CommandExecChecker) Check(ctx context.Context, rule *CheckRule) (*Check
 	expected := rule.Param[1]
 
 	// 执行命令
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd := commandInTarget(ctx, "sh", "-c", command)
 	output, err := cmd.CombinedOutput()
 	actual := strings.TrimSpace(string(output))
 
@@ -332,7 +331,7 @@ func (c *FileLineMatchChecker) Check(ctx context.Context, rule *CheckRule) (*Che
 	}
 
 	// 读取文件
-	file, err := os.Open(filePath)
+	file, err := os.Open(resolvePath(ctx, filePath))
 	if err != nil {
 		return &CheckResult{
 			Pass:     false,
@@ -427,7 +426,7 @@ func (c *SysctlChecker) Check(ctx context.Context, rule *CheckRule) (*CheckResul
 	expected := rule.Param[1]
 
 	// 读取 sysctl 值
-	cmd := exec.CommandContext(ctx, "sysctl", "-n", key)
+	cmd := commandInTarget(ctx, "sysctl", "-n", key)
 	output, err := cmd.Output()
 	if err != nil {
 		return &CheckResult{
@@ -707,7 +706,7 @@ func (c *ServiceStatusChecker) checkSystemdServiceDetailed(ctx context.Context,
 	}
 
 	// 检查服务是否活跃（is-active）
-	cmd := exec.CommandContext(ctx, "systemctl", "is-active", serviceName)
+	cmd := commandInTarget(ctx, "systemctl", "is-active", serviceName)
 	output, err := cmd.CombinedOutput()
 	activeStatus := strings.TrimSpace(string(output))
 
@@ -752,7 +751,7 @@ func (c *ServiceStatusChecker) checkSystemdServiceDetailed(ctx context.Context,
 	}
 
 	// 检查服务是否启用（is-enabled）
-	cmd = exec.CommandContext(ctx, "systemctl", "is-enabled", serviceName)
+	cmd = commandInTarget(ctx, "systemctl", "is-enabled", serviceName)
 	output, err = cmd.CombinedOutput()
 	enabledStatus := strings.TrimSpace(string(output))
 
@@ -819,7 +818,7 @@ func (c *ServiceStatusChecker) checkSystemdService(ctx context.Context, serviceN
 // checkSysVService 检查 SysV 服务状态
 func (c *ServiceStatusChecker) checkSysVService(ctx context.Context, serviceName string) (string, error) {
 	// 使用 service 命令检查状态
-	cmd := exec.CommandContext(ctx, "service", serviceName, "status")
+	cmd := commandInTarget(ctx, "service", serviceName, "status")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -852,7 +851,7 @@ func (c *FileOwnerChecker) Check(ctx context.Context, rule *CheckRule) (*CheckRe
 	expectedOwner := rule.Param[1]
 
 	// 获取文件信息
-	info, err := os.Stat(filePath)
+	info, err := os.Stat(resolvePath(ctx, filePath))
 	if err != nil {
 		return &CheckResult{
 			Pass:     false,
@@ -870,11 +869,11 @@ func (c *FileOwnerChecker) Check(ctx context.Context, rule *CheckRule) (*CheckRe
 	// 解析用户名和组名（可选，用于更友好的显示）
 	username := ""
 	groupname := ""
-	if u, err := user.LookupId(actualUID); err == nil {
-		username = u.Username
+	if name, ok := lookupTargetUserName(ctx, actualUID); ok {
+		username = name
 	}
-	if g, err := user.LookupGroupId(actualGID); err == nil {
-		groupname = g.Name
+	if name, ok := lookupTargetGroupName(ctx, actualGID); ok {
+		groupname = name
 	}
 
 	// 解析期望值（支持 uid:gid 或 username:groupname 格式）
@@ -918,13 +917,13 @@ func (c *FileOwnerChecker) Check(ctx context.Context, rule *CheckRule) (*CheckRe
 
 	// 如果提供了用户名，尝试解析
 	if expectedUsername != "" {
-		if u, err := user.Lookup(expectedUsername); err == nil {
-			expectedUID = u.Uid
+		if uid, ok := lookupTargetUID(ctx, expectedUsername); ok {
+			expectedUID = uid
 		}
 	}
 	if expectedGroupname != "" {
-		if g, err := user.LookupGroup(expectedGroupname); err == nil {
-			expectedGID = g.Gid
+		if gid, ok := lookupTargetGID(ctx, expectedGroupname); ok {
+			expectedGID = gid
 		}
 	}
 
@@ -981,8 +980,19 @@ func (c *PackageInstalledChecker) Check(ctx context.Context, rule *CheckRule) (*
 	var installed bool
 	var installedVersion string
 
-	// 尝试 RPM（CentOS/Rocky/Oracle）
-	if _, err := exec.LookPath("rpm"); err == nil {
+	// 容器目标：根据容器根文件系统中的包数据库判断
+	if target := TargetFromContext(ctx); target.IsContainer() {
+		var err error
+		installed, installedVersion, err = c.checkContainerPackage(ctx, target, packageName)
+		if err != nil {
+			return &CheckResult{
+				Pass:     false,
+				Actual:   fmt.Sprintf("检查容器软件包失败: %v", err),
+				Expected: fmt.Sprintf("软件包 %s 应已安装", packageName),
+			}, nil
+		}
+	} else if _, err := exec.LookPath("rpm"); err == nil {
+		// 尝试 RPM（CentOS/Rocky/Oracle）
 		var err error
 		installed, installedVersion, err = c.checkRPMPackage(ctx, packageName)
 		if err != nil {
@@ -1113,6 +1123,78 @@ func (c *PackageInstalledChecker) checkDEBPackage(ctx context.Context, packageNa
 	return false, "", nil
 }
 
+// checkContainerPackage 检查容器内的软件包是否安装
+// 直接读取容器根文件系统中的包数据库，不依赖容器内存在 rpm/dpkg 命令
+func (c *PackageInstalledChecker) checkContainerPackage(ctx context.Context, target *Target, packageName string) (bool, string, error) {
+	rootfs := target.RootFS()
+
+	if _, err := os.Stat(resolvePath(ctx, "/var/lib/dpkg/status")); err == nil {
+		return c.checkPackageDB(resolvePath(ctx, "/var/lib/dpkg/status"), packageName, "Package: ", "Version: ")
+	}
+	if _, err := os.Stat(resolvePath(ctx, "/lib/apk/db/installed")); err == nil {
+		return c.checkPackageDB(resolvePath(ctx, "/lib/apk/db/installed"), packageName, "P:", "V:")
+	}
+	if _, err := os.Stat(resolvePath(ctx, "/var/lib/rpm")); err == nil {
+		if _, err := exec.LookPath("rpm"); err != nil {
+			return false, "", fmt.Errorf("容器使用 RPM 包数据库，但宿主机未安装 rpm 命令")
+		}
+		cmd := exec.CommandContext(ctx, "rpm", "--root", rootfs, "-q", "--qf", "%{VERSION}", packageName)
+		output, err := cmd.Output()
+		if err != nil {
+			if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
+				return false, "", nil
+			}
+			return false, "", err
+		}
+		return true, strings.TrimSpace(string(output)), nil
+	}
+
+	return false, "", fmt.Errorf("未在容器中找到 dpkg/apk/rpm 包数据库")
+}
+
+// checkPackageDB 解析段落式包数据库（dpkg status、apk installed），查找指定包的版本
+func (c *PackageInstalledChecker) checkPackageDB(dbPath, packageName, nameKey, versionKey string) (bool, string, error) {
+	file, err := os.Open(dbPath)
+	if err != nil {
+		return false, "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var name, version string
+	notInstalled := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// 段落结束
+			if name == packageName && !notInstalled {
+				return true, version, nil
+			}
+			name, version, notInstalled = "", "", false
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, nameKey):
+			name = strings.TrimSpace(strings.TrimPrefix(line, nameKey))
+		case strings.HasPrefix(line, versionKey):
+			version = strings.TrimSpace(strings.TrimPrefix(line, versionKey))
+		case strings.HasPrefix(line, "Status: "):
+			// dpkg 中被移除但保留配置的包状态为 deinstall ok config-files
+			notInstalled = !strings.HasSuffix(line, " installed")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, "", err
+	}
+
+	if name == packageName && !notInstalled {
+		return true, version, nil
+	}
+	return false, "", nil
+}
+
 // compareVersion 比较版本（支持 >=、<=、==、>、<）
 func (c *PackageInstalledChecker) compareVersion(actual, constraint string) (bool, error) {
 	constraint = strings.TrimSpace(constraint)
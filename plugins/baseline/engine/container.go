@@ -0,0 +1,86 @@
+// Package engine 提供容器发现功能，用于容器内基线检查
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// dockerInspectFormat 是 docker inspect 的输出格式（字段以 | 分隔）
+const dockerInspectFormat = "{{.Id}}|{{.Name}}|{{.Config.Image}}|{{.Image}}|{{.State.Pid}}|{{.State.Running}}"
+
+// DiscoverContainers 发现宿主机上正在运行的容器
+// containerIDs 不为空时只返回匹配的容器（支持短 ID 前缀匹配）
+func DiscoverContainers(ctx context.Context, logger *zap.Logger, containerIDs []string) ([]*Target, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		logger.Debug("docker not found, skip container discovery")
+		return nil, nil
+	}
+
+	output, err := exec.CommandContext(ctx, "docker", "ps", "-q", "--no-trunc").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute docker ps: %w", err)
+	}
+
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := append([]string{"inspect", "--format", dockerInspectFormat}, ids...)
+	output, err = exec.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute docker inspect: %w", err)
+	}
+
+	var targets []*Target
+	for _, line := range strings.Split(string(output), "\n") {
+		target := parseDockerInspectLine(line)
+		if target == nil {
+			continue
+		}
+		if len(containerIDs) > 0 && !matchContainerID(target.ContainerID, containerIDs) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// parseDockerInspectLine 解析一行 docker inspect 输出，非运行状态或格式错误时返回 nil
+func parseDockerInspectLine(line string) *Target {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 6 || parts[5] != "true" {
+		return nil
+	}
+
+	pid, err := strconv.Atoi(parts[4])
+	if err != nil || pid <= 0 {
+		return nil
+	}
+
+	return &Target{
+		ContainerID:   parts[0],
+		ContainerName: strings.TrimPrefix(parts[1], "/"),
+		Image:         parts[2],
+		ImageID:       parts[3],
+		Runtime:       "docker",
+		PID:           pid,
+	}
+}
+
+// matchContainerID 判断容器 ID 是否在过滤列表中（支持前缀匹配）
+func matchContainerID(containerID string, filters []string) bool {
+	for _, f := range filters {
+		if f != "" && strings.HasPrefix(containerID, f) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+// Package engine 提供容器发现和目标解析的单元测试
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// fakeDocker 在 PATH 中放置一个模拟 docker 命令：ps 返回固定容器列表，inspect 返回固定输出
+func fakeDocker(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+ps)
+	echo aaaa1111
+	echo bbbb2222
+	echo cccc3333
+	;;
+inspect)
+	echo 'aaaa1111|/web|nginx:1.25|sha256:a|101|true'
+	echo 'bbbb2222|/db|postgres:16|sha256:b|202|true'
+	echo 'cccc3333|/job|busybox|sha256:c|0|false'
+	;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestDiscoverContainers 测试容器发现和按 ID 前缀筛选目标
+func TestDiscoverContainers(t *testing.T) {
+	fakeDocker(t)
+	logger := setupTestLogger(t)
+
+	tests := []struct {
+		name    string
+		filters []string
+		want    []string
+	}{
+		{"all running", nil, []string{"aaaa1111", "bbbb2222"}},
+		{"short id prefix", []string{"bbbb"}, []string{"bbbb2222"}},
+		{"full id", []string{"aaaa1111"}, []string{"aaaa1111"}},
+		{"stopped container filtered", []string{"cccc"}, nil},
+		{"no match", []string{"zzzz"}, nil},
+		{"empty filter ignored", []string{""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := DiscoverContainers(context.Background(), logger, tt.filters)
+			if err != nil {
+				t.Fatalf("DiscoverContainers: %v", err)
+			}
+			var got []string
+			for _, target := range targets {
+				if target.Runtime != "docker" || !target.IsContainer() {
+					t.Errorf("unexpected target %+v", target)
+				}
+				got = append(got, target.ContainerID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseDockerInspectLineCases 测试 docker inspect 输出的各类行
+func TestParseDockerInspectLineCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantNil  bool
+		wantName string
+		wantPID  int
+	}{
+		{"running", "id1|/web|nginx|sha256:x|10|true", false, "web", 10},
+		{"surrounding whitespace", "  id1|/api|app|sha256:x|11|true \n", false, "api", 11},
+		{"name without slash", "id1|web|nginx|sha256:x|12|true", false, "web", 12},
+		{"not running", "id1|/web|nginx|sha256:x|10|false", true, "", 0},
+		{"zero pid", "id1|/web|nginx|sha256:x|0|true", true, "", 0},
+		{"negative pid", "id1|/web|nginx|sha256:x|-1|true", true, "", 0},
+		{"non-numeric pid", "id1|/web|nginx|sha256:x|abc|true", true, "", 0},
+		{"too few fields", "id1|/web|nginx|true", true, "", 0},
+		{"empty", "", true, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := parseDockerInspectLine(tt.line)
+			if tt.wantNil {
+				if target != nil {
+					t.Errorf("expected nil, got %+v", target)
+				}
+				return
+			}
+			if target == nil {
+				t.Fatal("expected target, got nil")
+			}
+			if target.ContainerName != tt.wantName || target.PID != tt.wantPID {
+				t.Errorf("got name=%q pid=%d, want name=%q pid=%d", target.ContainerName, target.PID, tt.wantName, tt.wantPID)
+			}
+		})
+	}
+}
+
+// TestMatchContainerID 测试容器 ID 前缀匹配
+func TestMatchContainerID(t *testing.T) {
+	tests := []struct {
+		id      string
+		filters []string
+		want    bool
+	}{
+		{"abcdef123456", []string{"abc"}, true},
+		{"abcdef123456", []string{"abcdef123456"}, true},
+		{"abcdef123456", []string{"xyz", "abcd"}, true},
+		{"abcdef123456", []string{"bcd"}, false},
+		{"abcdef123456", []string{""}, false},
+		{"abcdef123456", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchContainerID(tt.id, tt.filters); got != tt.want {
+			t.Errorf("matchContainerID(%q, %v) = %v, want %v", tt.id, tt.filters, got, tt.want)
+		}
+	}
+}
+
+// TestTargetResolution 测试上下文中的执行目标和根文件系统路径映射
+func TestTargetResolution(t *testing.T) {
+	// 使用不存在的 PID，路径解析不会命中宿主机上的真实符号链接
+	const pid = 999999999
+	containerRoot := filepath.Join("/proc", strconv.Itoa(pid), "root")
+
+	tests := []struct {
+		name        string
+		target      *Target
+		wantIsCtr   bool
+		wantRootFS  string
+		path        string
+		wantResolve string
+	}{
+		{"no target", nil, false, "/", "/etc/passwd", "/etc/passwd"},
+		{"host target", &Target{}, false, "/", "/etc/ssh/sshd_config", "/etc/ssh/sshd_config"},
+		{"container", &Target{ContainerID: "c1", PID: pid}, true, containerRoot, "/etc/passwd", filepath.Join(containerRoot, "etc", "passwd")},
+		{"container relative path", &Target{PID: pid}, true, containerRoot, "etc/shadow", filepath.Join(containerRoot, "etc", "shadow")},
+		{"container dotdot", &Target{PID: pid}, true, containerRoot, "/etc/../../../root/.ssh", filepath.Join(containerRoot, "root", ".ssh")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.target != nil {
+				ctx = WithTarget(ctx, tt.target)
+			}
+			got := TargetFromContext(ctx)
+			if got != tt.target {
+				t.Fatalf("TargetFromContext() = %+v, want %+v", got, tt.target)
+			}
+			if got.IsContainer() != tt.wantIsCtr {
+				t.Errorf("IsContainer() = %v, want %v", got.IsContainer(), tt.wantIsCtr)
+			}
+			if got.RootFS() != tt.wantRootFS {
+				t.Errorf("RootFS() = %q, want %q", got.RootFS(), tt.wantRootFS)
+			}
+			if p := resolvePath(ctx, tt.path); p != tt.wantResolve {
+				t.Errorf("resolvePath(%q) = %q, want %q", tt.path, p, tt.wantResolve)
+			}
+		})
+	}
+}
+
+// TestLookupTargetDB 测试在容器根文件系统的 passwd/group 中查找用户和组
+func TestLookupTargetDB(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "etc"), 0755)
+	os.WriteFile(filepath.Join(root, "etc", "passwd"), []byte("# comment\nroot:x:0:0:root:/root:/bin/sh\nnginx:x:101:101::/var/cache/nginx:/sbin/nologin\n"), 0644)
+	os.WriteFile(filepath.Join(root, "etc", "group"), []byte("root:x:0:\nnginx:x:101:\n"), 0644)
+
+	// 直接在测试目录内解析，等价于容器根文件系统
+	lookup := func(dbFile string, matchField int, value string, wantField int) (string, bool) {
+		file := joinInRoot(root, dbFile)
+		ctx := context.Background()
+		return lookupTargetDB(ctx, file, matchField, value, wantField)
+	}
+
+	tests := []struct {
+		name      string
+		dbFile    string
+		match     int
+		value     string
+		want      int
+		wantValue string
+		wantOK    bool
+	}{
+		{"uid to name", "/etc/passwd", 2, "101", 0, "nginx", true},
+		{"name to uid", "/etc/passwd", 0, "root", 2, "0", true},
+		{"gid to name", "/etc/group", 2, "101", 0, "nginx", true},
+		{"missing user", "/etc/passwd", 0, "www-data", 2, "", false},
+		{"missing file", "/etc/shadow", 0, "root", 1, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lookup(tt.dbFile, tt.match, tt.value, tt.want)
+			if got != tt.wantValue || ok != tt.wantOK {
+				t.Errorf("lookup = (%q, %v), want (%q, %v)", got, ok, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
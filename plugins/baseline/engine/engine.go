@@ -68,6 +68,30 @@ func (e *Engine) Execute(ctx context.Context, policies []*Policy, osFamily, osVe
 	return results
 }
 
+// ExecuteTarget 在指定目标（宿主机或容器）中执行基线检查
+// 容器目标的 OS 信息从容器根文件系统中探测，结果会附带容器 ID 和镜像信息
+func (e *Engine) ExecuteTarget(ctx context.Context, policies []*Policy, target *Target, osFamily, osVersion string) []*Result {
+	if !target.IsContainer() {
+		return e.Execute(ctx, policies, osFamily, osVersion)
+	}
+
+	if family, version, err := DetectTargetOS(target); err == nil && family != "" {
+		osFamily, osVersion = family, version
+	} else {
+		e.logger.Debug("failed to detect container OS, fallback to host OS",
+			zap.String("container_id", target.ContainerID),
+			zap.Error(err))
+	}
+
+	results := e.Execute(WithTarget(ctx, target), policies, osFamily, osVersion)
+	for _, result := range results {
+		result.ContainerID = target.ContainerID
+		result.ContainerName = target.ContainerName
+		result.Image = target.Image
+	}
+	return results
+}
+
 // executeRule 执行单条规则
 func (e *Engine) executeRule(ctx context.Context, policy *Policy, rule *Rule) *Result {
 	result := &Result{
@@ -65,6 +65,11 @@ type Result struct {
 	Expected      string
 	FixSuggestion string
 	CheckedAt     time.Time
+
+	// 容器目标信息（宿主机检查时为空）
+	ContainerID   string
+	ContainerName string
+	Image         string
 }
 
 // Status 是检查状态
@@ -0,0 +1,232 @@
+// Package engine 提供基线检查的执行目标（主机或容器）
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxSymlinkHops 是在容器根文件系统内解析路径时允许的最大符号链接跳转次数
+const maxSymlinkHops = 255
+
+// Target 是基线检查的执行目标
+// 为 nil 或 PID 为 0 时表示宿主机本身
+type Target struct {
+	ContainerID   string // 容器 ID
+	ContainerName string // 容器名称
+	Image         string // 镜像名称
+	ImageID       string // 镜像 ID
+	Runtime       string // 运行时（docker、containerd）
+	PID           int    // 容器 init 进程在宿主机上的 PID
+}
+
+// IsContainer 判断目标是否为容器
+func (t *Target) IsContainer() bool {
+	return t != nil && t.PID > 0
+}
+
+// RootFS 返回目标的根文件系统路径（通过 /proc/<pid>/root 访问容器文件系统）
+func (t *Target) RootFS() string {
+	if !t.IsContainer() {
+		return "/"
+	}
+	return filepath.Join("/proc", strconv.Itoa(t.PID), "root")
+}
+
+type targetKey struct{}
+
+// WithTarget 将执行目标绑定到上下文，检查器通过上下文感知当前目标
+func WithTarget(ctx context.Context, target *Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+// TargetFromContext 从上下文获取执行目标，未设置时返回 nil（宿主机）
+func TargetFromContext(ctx context.Context) *Target {
+	target, _ := ctx.Value(targetKey{}).(*Target)
+	return target
+}
+
+// resolvePath 将检查规则中的路径映射到当前目标的文件系统
+// 宿主机目标原样返回；容器目标在其根文件系统内解析（包括符号链接），避免逃逸到宿主机
+func resolvePath(ctx context.Context, path string) string {
+	target := TargetFromContext(ctx)
+	if !target.IsContainer() {
+		return path
+	}
+	return joinInRoot(target.RootFS(), path)
+}
+
+// joinInRoot 在 root 内解析 path，绝对符号链接以 root 为根重新解析
+func joinInRoot(root, path string) string {
+	pending := strings.Split(filepath.Clean("/"+path), "/")
+	resolved := ""
+	hops := 0
+
+	for len(pending) > 0 {
+		part := pending[0]
+		pending = pending[1:]
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			if resolved == "." || resolved == "/" {
+				resolved = ""
+			}
+			continue
+		}
+
+		candidate := resolved + "/" + part
+		info, err := os.Lstat(filepath.Join(root, candidate))
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			resolved = candidate
+			continue
+		}
+
+		hops++
+		if hops > maxSymlinkHops {
+			resolved = candidate
+			continue
+		}
+
+		link, err := os.Readlink(filepath.Join(root, candidate))
+		if err != nil {
+			resolved = candidate
+			continue
+		}
+		if filepath.IsAbs(link) {
+			resolved = ""
+		}
+		pending = append(strings.Split(link, "/"), pending...)
+	}
+
+	return filepath.Join(root, resolved)
+}
+
+// commandInTarget 创建在当前目标中执行的命令
+// 容器目标通过 nsenter 进入容器的 mount/uts/ipc/net/pid 命名空间执行
+func commandInTarget(ctx context.Context, name string, args ...string) *exec.Cmd {
+	target := TargetFromContext(ctx)
+	if !target.IsContainer() {
+		return exec.CommandContext(ctx, name, args...)
+	}
+
+	nsArgs := []string{"-t", strconv.Itoa(target.PID), "-m", "-u", "-i", "-n", "-p", "--", name}
+	return exec.CommandContext(ctx, "nsenter", append(nsArgs, args...)...)
+}
+
+// DetectTargetOS 读取目标根文件系统中的 /etc/os-release，返回 OS 系列和版本
+func DetectTargetOS(target *Target) (string, string, error) {
+	path := joinInRoot(target.RootFS(), "/etc/os-release")
+	if !target.IsContainer() {
+		path = "/etc/os-release"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open os-release: %w", err)
+	}
+	defer file.Close()
+
+	var osFamily, osVersion string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			osFamily = strings.ToLower(value)
+		case "VERSION_ID":
+			osVersion = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("failed to read os-release: %w", err)
+	}
+
+	return osFamily, osVersion, nil
+}
+
+// lookupTargetUserName 根据 UID 查找当前目标中的用户名
+func lookupTargetUserName(ctx context.Context, uid string) (string, bool) {
+	if !TargetFromContext(ctx).IsContainer() {
+		u, err := user.LookupId(uid)
+		if err != nil {
+			return "", false
+		}
+		return u.Username, true
+	}
+	return lookupTargetDB(ctx, "/etc/passwd", 2, uid, 0)
+}
+
+// lookupTargetGroupName 根据 GID 查找当前目标中的组名
+func lookupTargetGroupName(ctx context.Context, gid string) (string, bool) {
+	if !TargetFromContext(ctx).IsContainer() {
+		g, err := user.LookupGroupId(gid)
+		if err != nil {
+			return "", false
+		}
+		return g.Name, true
+	}
+	return lookupTargetDB(ctx, "/etc/group", 2, gid, 0)
+}
+
+// lookupTargetUID 根据用户名查找当前目标中的 UID
+func lookupTargetUID(ctx context.Context, username string) (string, bool) {
+	if !TargetFromContext(ctx).IsContainer() {
+		u, err := user.Lookup(username)
+		if err != nil {
+			return "", false
+		}
+		return u.Uid, true
+	}
+	return lookupTargetDB(ctx, "/etc/passwd", 0, username, 2)
+}
+
+// lookupTargetGID 根据组名查找当前目标中的 GID
+func lookupTargetGID(ctx context.Context, groupname string) (string, bool) {
+	if !TargetFromContext(ctx).IsContainer() {
+		g, err := user.LookupGroup(groupname)
+		if err != nil {
+			return "", false
+		}
+		return g.Gid, true
+	}
+	return lookupTargetDB(ctx, "/etc/group", 0, groupname, 2)
+}
+
+// lookupTargetDB 在目标的 passwd/group 文件中查找 matchField 等于 value 的行，返回 wantField 的值
+func lookupTargetDB(ctx context.Context, dbFile string, matchField int, value string, wantField int) (string, bool) {
+	file, err := os.Open(resolvePath(ctx, dbFile))
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) <= matchField || len(fields) <= wantField {
+			continue
+		}
+		if fields[matchField] == value {
+			return fields[wantField], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,106 @@
+// Package engine 提供容器执行目标的单元测试
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestJoinInRoot 测试在容器根文件系统内解析路径（符号链接不能逃逸到宿主机）
+func TestJoinInRoot(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "etc", "ssh"), 0755)
+	os.MkdirAll(filepath.Join(root, "usr", "lib"), 0755)
+	os.WriteFile(filepath.Join(root, "etc", "ssh", "sshd_config"), []byte("PermitRootLogin no\n"), 0644)
+	os.WriteFile(filepath.Join(root, "usr", "lib", "os-release"), []byte("ID=debian\n"), 0644)
+
+	// 绝对符号链接：/etc/os-release -> /usr/lib/os-release（应在 root 内解析）
+	os.Symlink("/usr/lib/os-release", filepath.Join(root, "etc", "os-release"))
+	// 相对符号链接：/etc/sshd -> ssh
+	os.Symlink("ssh", filepath.Join(root, "etc", "sshd"))
+	// 逃逸尝试：/etc/escape -> ../../../../etc
+	os.Symlink("../../../../etc", filepath.Join(root, "etc", "escape"))
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"plain path", "/etc/ssh/sshd_config", filepath.Join(root, "etc", "ssh", "sshd_config")},
+		{"absolute symlink", "/etc/os-release", filepath.Join(root, "usr", "lib", "os-release")},
+		{"relative symlink", "/etc/sshd/sshd_config", filepath.Join(root, "etc", "ssh", "sshd_config")},
+		{"dotdot escape", "/../../etc/passwd", filepath.Join(root, "etc", "passwd")},
+		{"symlink escape", "/etc/escape/passwd", filepath.Join(root, "etc", "passwd")},
+		{"missing file", "/etc/missing.conf", filepath.Join(root, "etc", "missing.conf")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinInRoot(root, tt.path); got != tt.want {
+				t.Errorf("joinInRoot(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseDockerInspectLine 测试解析 docker inspect 输出
+func TestParseDockerInspectLine(t *testing.T) {
+	target := parseDockerInspectLine("abc123|/web|nginx:1.25|sha256:def|4242|true")
+	if target == nil {
+		t.Fatal("expected target, got nil")
+	}
+	if target.ContainerID != "abc123" || target.ContainerName != "web" || target.Image != "nginx:1.25" || target.PID != 4242 {
+		t.Errorf("unexpected target: %+v", target)
+	}
+	if target.RootFS() != "/proc/4242/root" {
+		t.Errorf("RootFS() = %q", target.RootFS())
+	}
+
+	if parseDockerInspectLine("abc123|/web|nginx|sha256:def|0|false") != nil {
+		t.Error("stopped container should be skipped")
+	}
+	if parseDockerInspectLine("garbage") != nil {
+		t.Error("malformed line should be skipped")
+	}
+}
+
+// TestCheckPackageDB 测试解析 dpkg status 格式的包数据库
+func TestCheckPackageDB(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "status")
+	os.WriteFile(db, []byte(`Package: openssh-server
+Status: install ok installed
+Version: 1:9.2p1-2
+
+Package: telnet
+Status: deinstall ok config-files
+Version: 0.17-44
+
+Package: curl
+Status: install ok installed
+Version: 7.88.1-10
+`), 0644)
+
+	checker := NewPackageInstalledChecker(setupTestLogger(t))
+
+	tests := []struct {
+		pkg         string
+		wantFound   bool
+		wantVersion string
+	}{
+		{"openssh-server", true, "1:9.2p1-2"},
+		{"telnet", false, ""},
+		{"curl", true, "7.88.1-10"},
+		{"vim", false, ""},
+	}
+
+	for _, tt := range tests {
+		found, version, err := checker.checkPackageDB(db, tt.pkg, "Package: ", "Version: ")
+		if err != nil {
+			t.Fatalf("checkPackageDB(%s) error: %v", tt.pkg, err)
+		}
+		if found != tt.wantFound || version != tt.wantVersion {
+			t.Errorf("checkPackageDB(%s) = (%v, %q), want (%v, %q)", tt.pkg, found, version, tt.wantFound, tt.wantVersion)
+		}
+	}
+}
@@ -191,22 +191,9 @@ func handleBaselineTask(ctx context.Context, taskData map[string]interface{}, ch
 	if !ok {
 		return fmt.Errorf("missing policies in task data")
 	}
-
-	var policiesData []map[string]interface{}
-	if err := json.Unmarshal([]byte(policiesJSON), &policiesData); err != nil {
-		return fmt.Errorf("failed to unmarshal policies: %w", err)
-	}
-
-	// 转换为 Policy 对象
-	var policies []*engine.Policy
-	for _, pd := range policiesData {
-		policyJSON, _ := json.Marshal(pd)
-		var p engine.Policy
-		if err := json.Unmarshal(policyJSON, &p); err != nil {
-			logger.Warn("failed to unmarshal policy", zap.Error(err))
-			continue
-		}
-		policies = append(policies, &p)
+	policies, err := parsePolicies(policiesJSON, logger)
+	if err != nil {
+		return err
 	}
 
 	// 提取主机信息（用于 OS 匹配）
@@ -223,26 +210,40 @@ func handleBaselineTask(ctx context.Context, taskData map[string]interface{}, ch
 	// 执行检查
 	results := checkEngine.Execute(ctx, policies, osFamily, osVersion)
 
+	// 容器内检查（Server 下发 container_policies 时执行）
+	if containerPoliciesJSON, ok := taskData["container_policies"].(string); ok && containerPoliciesJSON != "" {
+		containerResults, err := executeContainerChecks(ctx, taskData, containerPoliciesJSON, checkEngine, osFamily, osVersion, logger)
+		if err != nil {
+			logger.Error("failed to execute container checks", zap.String("task_id", taskID), zap.Error(err))
+		}
+		results = append(results, containerResults...)
+	}
+
 	// 上报结果
 	for _, result := range results {
+		fields := map[string]string{
+			"task_id":        taskID, // 添加 task_id
+			"rule_id":        result.RuleID,
+			"policy_id":      result.PolicyID,
+			"status":         string(result.Status),
+			"severity":       result.Severity,
+			"category":       result.Category,
+			"title":          result.Title,
+			"actual":         result.Actual,
+			"expected":       result.Expected,
+			"fix_suggestion": result.FixSuggestion,
+			"checked_at":     result.CheckedAt.Format(time.RFC3339),
+		}
+		if result.ContainerID != "" {
+			fields["container_id"] = result.ContainerID
+			fields["container_name"] = result.ContainerName
+			fields["container_image"] = result.Image
+		}
+
 		record := &bridge.Record{
 			DataType:  8000, // 基线检查结果
 			Timestamp: time.Now().UnixNano(),
-			Data: &bridge.Payload{
-				Fields: map[string]string{
-					"task_id":        taskID,   // 添加 task_id
-					"rule_id":        result.RuleID,
-					"policy_id":      result.PolicyID,
-					"status":         string(result.Status),
-					"severity":       result.Severity,
-					"category":       result.Category,
-					"title":          result.Title,
-					"actual":         result.Actual,
-					"expected":       result.Expected,
-					"fix_suggestion": result.FixSuggestion,
-					"checked_at":     result.CheckedAt.Format(time.RFC3339),
-				},
-			},
+			Data:      &bridge.Payload{Fields: fields},
 		}
 
 		if err := client.SendRecord(record); err != nil {
@@ -275,6 +276,72 @@ func handleBaselineTask(ctx context.Context, taskData map[string]interface{}, ch
 	return nil
 }
 
+// executeContainerChecks 发现宿主机上运行的容器，并在每个容器中执行基线检查
+func executeContainerChecks(ctx context.Context, taskData map[string]interface{}, policiesJSON string, checkEngine *engine.Engine, osFamily, osVersion string, logger *zap.Logger) ([]*engine.Result, error) {
+	policies, err := parsePolicies(policiesJSON, logger)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	// 可选：仅检查指定容器
+	var containerIDs []string
+	if ids, ok := taskData["container_ids"].([]interface{}); ok {
+		for _, id := range ids {
+			if idStr, ok := id.(string); ok {
+				containerIDs = append(containerIDs, idStr)
+			}
+		}
+	}
+
+	targets, err := engine.DiscoverContainers(ctx, logger, containerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*engine.Result
+	for _, target := range targets {
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		default:
+		}
+
+		containerResults := checkEngine.ExecuteTarget(ctx, policies, target, osFamily, osVersion)
+		logger.Info("container baseline check completed",
+			zap.String("container_id", target.ContainerID),
+			zap.String("image", target.Image),
+			zap.Int("result_count", len(containerResults)))
+		results = append(results, containerResults...)
+	}
+
+	return results, nil
+}
+
+// parsePolicies 解析任务数据中的策略 JSON
+func parsePolicies(policiesJSON string, logger *zap.Logger) ([]*engine.Policy, error) {
+	var policiesData []map[string]interface{}
+	if err := json.Unmarshal([]byte(policiesJSON), &policiesData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policies: %w", err)
+	}
+
+	// 转换为 Policy 对象
+	var policies []*engine.Policy
+	for _, pd := range policiesData {
+		policyJSON, _ := json.Marshal(pd)
+		var p engine.Policy
+		if err := json.Unmarshal(policyJSON, &p); err != nil {
+			logger.Warn("failed to unmarshal policy", zap.Error(err))
+			continue
+		}
+		policies = append(policies, &p)
+	}
+
+	return policies, nil
+}
+
 // newPluginLogger 创建插件专用的 logger
 // 输出到 stderr，由 Agent 重定向到 /var/log/mxsec/plugins/baseline.log
 func newPluginLogger() (*zap.Logger, error) {
@@ -4,6 +4,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -23,23 +24,45 @@ type Handler interface {
 type HandlerConfig struct {
 	Name     string        // 采集器名称
 	Interval time.Duration // 采集间隔
+	Enabled  bool          // 是否启用定时采集（禁用时仍可通过任务按需触发）
 	Handler  Handler       // 采集器实现
+
+	reload    chan struct{} // 通知 runHandler 重新加载间隔/启用状态
+	collectMu sync.Mutex    // 串行化同一采集器的定时采集和按需采集，保证快照基准一致
+}
+
+// HandlerSetting 是 Server 下发的单个采集器配置（Config.detail 中的 handlers 字段）
+type HandlerSetting struct {
+	Interval int   `json:"interval,omitempty"` // 采集间隔（秒），0 表示保持当前值
+	Enabled  *bool `json:"enabled,omitempty"`  // 是否启用，nil 表示保持当前值
+}
+
+// PluginConfig 是 collector 插件的配置详情（grpc.Config.detail）
+type PluginConfig struct {
+	Handlers map[string]HandlerSetting `json:"handlers"`
 }
 
+// MinInterval 是允许配置的最小采集间隔，避免过于频繁的采集影响主机性能
+const MinInterval = time.Minute
+
 // Engine 是采集引擎
 type Engine struct {
 	client   *plugins.Client
 	logger   *zap.Logger
 	handlers map[string]*HandlerConfig
 	mu       sync.RWMutex
+
+	snapshots map[string]*snapshotState // 采集器名称 -> 快照状态
+	snapMu    sync.Mutex
 }
 
 // NewEngine 创建新的采集引擎
 func NewEngine(client *plugins.Client, logger *zap.Logger) *Engine {
 	return &Engine{
-		client:   client,
-		logger:   logger,
-		handlers: make(map[string]*HandlerConfig),
+		client:    client,
+		logger:    logger,
+		handlers:  make(map[string]*HandlerConfig),
+		snapshots: make(map[string]*snapshotState),
 	}
 }
 
@@ -51,7 +74,9 @@ func (e *Engine) RegisterHandler(name string, interval time.Duration, handler Ha
 	e.handlers[name] = &HandlerConfig{
 		Name:     name,
 		Interval: interval,
+		Enabled:  true,
 		Handler:  handler,
+		reload:   make(chan struct{}, 1),
 	}
 
 	e.logger.Info("registered collector handler",
@@ -80,14 +105,13 @@ func (e *Engine) Run(ctx context.Context) {
 
 // runHandler 运行单个采集器
 func (e *Engine) runHandler(ctx context.Context, h *HandlerConfig) {
-	ticker := time.NewTicker(h.Interval)
+	interval, enabled := e.handlerState(h)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// 立即执行一次
-	if err := e.collectAndReport(ctx, h); err != nil {
-		e.logger.Error("failed to collect",
-			zap.String("handler", h.Name),
-			zap.Error(err))
+	if enabled {
+		e.collectScheduled(ctx, h)
 	}
 
 	// 定时采集
@@ -95,54 +119,149 @@ func (e *Engine) runHandler(ctx context.Context, h *HandlerConfig) {
 		select {
 		case <-ctx.Done():
 			return
+		case <-h.reload:
+			newInterval, newEnabled := e.handlerState(h)
+			if newInterval != interval {
+				ticker.Reset(newInterval)
+				interval = newInterval
+			}
+			// 从禁用切换为启用时立即采集一次
+			if newEnabled && !enabled {
+				e.collectScheduled(ctx, h)
+			}
+			enabled = newEnabled
 		case <-ticker.C:
-			if err := e.collectAndReport(ctx, h); err != nil {
-				e.logger.Error("failed to collect",
-					zap.String("handler", h.Name),
-					zap.Error(err))
+			if enabled {
+				e.collectScheduled(ctx, h)
 			}
 		}
 	}
 }
 
-// CollectOnce 执行一次采集（用于任务触发）
-func (e *Engine) CollectOnce(ctx context.Context, collectType string) error {
+// collectScheduled 执行一次定时采集，失败只记录日志
+// Agent 限流期间推迟到限流解除后执行（按需采集任务不受影响）
+func (e *Engine) collectScheduled(ctx context.Context, h *HandlerConfig) {
+	if e.client != nil {
+		if throttled, reason := e.client.Throttled(); throttled {
+			e.logger.Info("collection deferred by agent throttle",
+				zap.String("handler", h.Name),
+				zap.String("reason", reason))
+			if err := e.client.WaitThrottle(ctx); err != nil {
+				return
+			}
+		}
+	}
+
+	if _, err := e.collectAndReport(ctx, h); err != nil {
+		e.logger.Error("failed to collect",
+			zap.String("handler", h.Name),
+			zap.Error(err))
+	}
+}
+
+// handlerState 读取采集器当前的间隔和启用状态
+func (e *Engine) handlerState(h *HandlerConfig) (time.Duration, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return h.Interval, h.Enabled
+}
+
+// ApplyConfig 应用 Server 下发的采集器配置，运行中的采集器立即生效，无需重启插件
+// 未知的采集器名称会被忽略，小于 MinInterval 的间隔会被提升到 MinInterval
+func (e *Engine) ApplyConfig(cfg *PluginConfig) {
+	if cfg == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name, setting := range cfg.Handlers {
+		h, ok := e.handlers[name]
+		if !ok {
+			e.logger.Warn("ignoring config for unknown handler", zap.String("name", name))
+			continue
+		}
+
+		changed := false
+		if setting.Interval > 0 {
+			interval := time.Duration(setting.Interval) * time.Second
+			if interval < MinInterval {
+				interval = MinInterval
+			}
+			if interval != h.Interval {
+				h.Interval = interval
+				changed = true
+			}
+		}
+		if setting.Enabled != nil && *setting.Enabled != h.Enabled {
+			h.Enabled = *setting.Enabled
+			changed = true
+		}
+
+		if !changed {
+			continue
+		}
+
+		e.logger.Info("collector handler config updated",
+			zap.String("name", name),
+			zap.Duration("interval", h.Interval),
+			zap.Bool("enabled", h.Enabled))
+
+		select {
+		case h.reload <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// CollectOnce 执行一次采集（用于任务触发），返回采集到的资产数量
+// 禁用定时采集的采集器同样可以按需触发
+func (e *Engine) CollectOnce(ctx context.Context, collectType string) (int, error) {
 	e.mu.RLock()
 	h, ok := e.handlers[collectType]
 	e.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("handler not found: %s", collectType)
+		return 0, fmt.Errorf("handler not found: %s", collectType)
 	}
 
 	return e.collectAndReport(ctx, h)
 }
 
-// collectAndReport 执行采集并上报
-func (e *Engine) collectAndReport(ctx context.Context, h *HandlerConfig) error {
+// collectAndReport 执行采集并上报，返回采集到的资产数量
+func (e *Engine) collectAndReport(ctx context.Context, h *HandlerConfig) (int, error) {
+	h.collectMu.Lock()
+	defer h.collectMu.Unlock()
+
 	e.logger.Debug("collecting assets", zap.String("handler", h.Name))
 
 	// 执行采集
 	assets, err := h.Handler.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("collect failed: %w", err)
+		return 0, fmt.Errorf("collect failed: %w", err)
+	}
+
+	// 获取 data_type
+	dataType := GetDataType(h.Name)
+	if dataType == 0 {
+		return 0, fmt.Errorf("unknown collect type: %s", h.Name)
+	}
+
+	// 资产实现了 KeyedAsset 时按分块增量快照上报（空结果也需要上报，用于同步删除）
+	if allKeyed(assets) {
+		return e.reportSnapshot(h, dataType, assets)
 	}
 
 	if len(assets) == 0 {
 		e.logger.Debug("no assets collected", zap.String("handler", h.Name))
-		return nil
+		return 0, nil
 	}
 
 	// 序列化资产数据
 	data, err := SerializeAssets(assets)
 	if err != nil {
-		return fmt.Errorf("failed to serialize assets: %w", err)
-	}
-
-	// 获取 data_type
-	dataType := GetDataType(h.Name)
-	if dataType == 0 {
-		return fmt.Errorf("unknown collect type: %s", h.Name)
+		return 0, fmt.Errorf("failed to serialize assets: %w", err)
 	}
 
 	// 创建记录
@@ -158,14 +277,49 @@ func (e *Engine) collectAndReport(ctx context.Context, h *HandlerConfig) error {
 
 	// 上报数据
 	if err := e.client.SendRecord(record); err != nil {
-		return fmt.Errorf("failed to send record: %w", err)
+		return 0, fmt.Errorf("failed to send record: %w", err)
 	}
 
 	e.logger.Info("assets collected and reported",
 		zap.String("handler", h.Name),
 		zap.Int("count", len(assets)))
 
-	return nil
+	return len(assets), nil
+}
+
+// allKeyed 检查所有资产是否都实现了 KeyedAsset
+func allKeyed(assets []interface{}) bool {
+	for _, asset := range assets {
+		if _, ok := asset.(KeyedAsset); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ReportCollectComplete 上报按需采集任务完成信号（DataType 5099）
+func (e *Engine) ReportCollectComplete(taskID, token, collectType string, count int, collectErr error) error {
+	fields := map[string]string{
+		"task_id":      taskID,
+		"token":        token,
+		"type":         collectType,
+		"status":       "completed",
+		"count":        strconv.Itoa(count),
+		"completed_at": time.Now().Format(time.RFC3339),
+	}
+	if collectErr != nil {
+		fields["status"] = "failed"
+		fields["error_message"] = collectErr.Error()
+	}
+
+	record := &bridge.Record{
+		DataType:  DataTypeCollectComplete,
+		Timestamp: time.Now().UnixNano(),
+		Data: &bridge.Payload{
+			Fields: fields,
+		},
+	}
+	return e.client.SendRecord(record)
 }
 
 // GetHandlerNames 获取所有已注册的采集器名称
@@ -0,0 +1,94 @@
+// Package engine 提供采集引擎的单元测试
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type nopHandler struct{}
+
+func (nopHandler) Collect(ctx context.Context) ([]interface{}, error) { return nil, nil }
+
+// TestApplyConfig 测试运行时应用 Server 下发的采集器配置
+func TestApplyConfig(t *testing.T) {
+	e := NewEngine(nil, zap.NewNop())
+	e.RegisterHandler("process", time.Hour, nopHandler{})
+	e.RegisterHandler("port", time.Hour, nopHandler{})
+
+	disabled := false
+	e.ApplyConfig(&PluginConfig{Handlers: map[string]HandlerSetting{
+		"process": {Interval: 600},
+		"port":    {Interval: 5, Enabled: &disabled},
+		"unknown": {Interval: 600},
+	}})
+
+	process := e.handlers["process"]
+	if process.Interval != 10*time.Minute || !process.Enabled {
+		t.Errorf("process = (%v, %v), want (10m, true)", process.Interval, process.Enabled)
+	}
+	if len(process.reload) != 1 {
+		t.Error("process handler should be notified to reload")
+	}
+
+	port := e.handlers["port"]
+	if port.Interval != MinInterval {
+		t.Errorf("port interval = %v, want clamped to %v", port.Interval, MinInterval)
+	}
+	if port.Enabled {
+		t.Error("port handler should be disabled")
+	}
+
+	if _, ok := e.handlers["unknown"]; ok {
+		t.Error("unknown handler should be ignored")
+	}
+}
+
+// TestChunkEntries 测试快照分块：按字节切分且每块都是合法的 JSON 数组
+func TestChunkEntries(t *testing.T) {
+	var entries []snapshotEntry
+	for i := 0; i < 10; i++ {
+		data, _ := json.Marshal(ProcessAsset{PID: strconv.Itoa(i), Cmdline: strings.Repeat("x", 100)})
+		entries = append(entries, snapshotEntry{data: data})
+	}
+
+	chunks := chunkEntries(entries, 400)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	total := 0
+	for _, chunk := range chunks {
+		keys, err := DecodeAssetKeys(5050, chunk)
+		if err != nil {
+			t.Fatalf("invalid chunk %s: %v", chunk, err)
+		}
+		total += len(keys)
+	}
+	if total != len(entries) {
+		t.Fatalf("expected %d assets across chunks, got %d", len(entries), total)
+	}
+
+	if empty := chunkEntries(nil, 400); len(empty) != 1 || string(empty[0]) != "[]" {
+		t.Fatalf("expected a single empty chunk, got %q", empty)
+	}
+}
+
+// TestContentHashIgnoresCollectedAt 测试内容哈希忽略采集时间
+func TestContentHashIgnoresCollectedAt(t *testing.T) {
+	a, _ := json.Marshal(PortAsset{Protocol: "tcp", Port: 22, Asset: Asset{CollectedAt: time.Unix(1, 0)}})
+	b, _ := json.Marshal(PortAsset{Protocol: "tcp", Port: 22, Asset: Asset{CollectedAt: time.Unix(2, 0)}})
+	c, _ := json.Marshal(PortAsset{Protocol: "tcp", Port: 22, PID: "1", Asset: Asset{CollectedAt: time.Unix(2, 0)}})
+
+	if contentHash(a) != contentHash(b) {
+		t.Fatal("hash should not change when only collected_at differs")
+	}
+	if contentHash(b) == contentHash(c) {
+		t.Fatal("hash should change when asset content differs")
+	}
+}
@@ -22,10 +22,52 @@ type ContainerHandler struct {
 }
 
 // Collect 采集容器信息
+// 优先通过 CRI socket 和 Docker Engine API 采集（不依赖 docker/ctr 命令行），
+// 两者都不可用时才回退到命令行采集
 func (h *ContainerHandler) Collect(ctx context.Context) ([]interface{}, error) {
 	var containers []interface{}
+	seen := make(map[string]bool)
+	appendUnique := func(items []interface{}) {
+		for _, item := range items {
+			container, ok := item.(*engine.ContainerAsset)
+			if !ok || seen[container.ContainerID] {
+				continue
+			}
+			seen[container.ContainerID] = true
+			containers = append(containers, item)
+		}
+	}
+
+	nativeAvailable := false
+
+	// 采集 CRI 运行时容器（containerd、CRI-O）
+	if cri := newCRIClient(ctx, h.Logger); cri != nil {
+		nativeAvailable = true
+		criContainers, err := cri.ListContainers(ctx)
+		cri.Close()
+		if err != nil {
+			h.Logger.Warn("failed to collect CRI containers", zap.String("socket", cri.socket), zap.Error(err))
+		} else {
+			appendUnique(criContainers)
+		}
+	}
+
+	// 采集 Docker 容器（Docker 与 containerd 共存时按容器 ID 去重）
+	if docker := newDockerAPIClient(h.Logger); docker != nil {
+		nativeAvailable = true
+		dockerContainers, err := docker.ListContainers(ctx)
+		if err != nil {
+			h.Logger.Warn("failed to collect Docker containers via engine api", zap.String("socket", docker.socket), zap.Error(err))
+		} else {
+			appendUnique(dockerContainers)
+		}
+	}
+
+	if nativeAvailable {
+		return containers, nil
+	}
 
-	// 检测容器运行时
+	// 回退：检测容器运行时命令行工具
 	runtimes := h.detectContainerRuntimes()
 	if len(runtimes) == 0 {
 		h.Logger.Debug("no container runtime detected")
@@ -38,7 +80,7 @@ func (h *ContainerHandler) Collect(ctx context.Context) ([]interface{}, error) {
 		if err != nil {
 			h.Logger.Warn("failed to collect Docker containers", zap.Error(err))
 		} else {
-			containers = append(containers, dockerContainers...)
+			appendUnique(dockerContainers)
 		}
 	}
 
@@ -48,14 +90,14 @@ func (h *ContainerHandler) Collect(ctx context.Context) ([]interface{}, error) {
 		if err != nil {
 			h.Logger.Warn("failed to collect containerd containers", zap.Error(err))
 		} else {
-			containers = append(containers, containerdContainers...)
+			appendUnique(containerdContainers)
 		}
 	}
 
 	return containers, nil
 }
 
-// detectContainerRuntimes 检测容器运行时
+// detectContainerRuntimes 检测可用于回退采集的容器运行时命令行工具
 func (h *ContainerHandler) detectContainerRuntimes() []string {
 	var runtimes []string
 
@@ -69,7 +111,7 @@ func (h *ContainerHandler) detectContainerRuntimes() []string {
 		runtimes = append(runtimes, "containerd")
 	}
 
-	// 检测 containerd socket
+	// containerd 未启用 CRI 插件时，仍可通过元数据目录采集
 	if _, err := os.Stat("/run/containerd/containerd.sock"); err == nil {
 		if !contains(runtimes, "containerd") {
 			runtimes = append(runtimes, "containerd")
@@ -0,0 +1,256 @@
+// Package handlers 提供各类资产采集器的实现
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// criSocketPaths 是 CRI gRPC socket 路径（containerd、CRI-O）
+var criSocketPaths = []string{
+	"/run/containerd/containerd.sock",
+	"/var/run/containerd/containerd.sock",
+	"/var/run/crio/crio.sock",
+	"/run/crio/crio.sock",
+}
+
+// criRequestTimeout 是单次 CRI 请求的超时时间
+const criRequestTimeout = 10 * time.Second
+
+// criClient 通过 CRI gRPC socket 直接访问容器运行时，不依赖 ctr/crictl 命令行
+type criClient struct {
+	socket  string
+	runtime string // 运行时名称（containerd、cri-o）
+	conn    *grpc.ClientConn
+	client  runtimeapi.RuntimeServiceClient
+	logger  *zap.Logger
+}
+
+// criVerboseInfo 是 ContainerStatus(verbose=true) 返回的 info 字段（containerd 与 CRI-O 格式不同，取交集）
+type criVerboseInfo struct {
+	Pid        int  `json:"pid"`
+	Privileged bool `json:"privileged"` // CRI-O
+	Config     struct {
+		Linux struct {
+			SecurityContext struct {
+				Privileged   bool `json:"privileged"` // containerd
+				Capabilities struct {
+					AddCapabilities []string `json:"add_capabilities"`
+				} `json:"capabilities"`
+			} `json:"security_context"`
+		} `json:"linux"`
+	} `json:"config"`
+}
+
+// newCRIClient 探测 CRI socket 并建立连接，没有可用的 CRI 运行时时返回 nil
+func newCRIClient(ctx context.Context, logger *zap.Logger) *criClient {
+	for _, socket := range criSocketPaths {
+		if _, err := os.Stat(socket); err != nil {
+			continue
+		}
+
+		conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.Debug("failed to create cri client", zap.String("socket", socket), zap.Error(err))
+			continue
+		}
+
+		client := runtimeapi.NewRuntimeServiceClient(conn)
+		reqCtx, cancel := context.WithTimeout(ctx, criRequestTimeout)
+		version, err := client.Version(reqCtx, &runtimeapi.VersionRequest{})
+		cancel()
+		if err != nil {
+			// containerd 未启用 CRI 插件等情况
+			logger.Debug("cri version request failed", zap.String("socket", socket), zap.Error(err))
+			conn.Close()
+			continue
+		}
+
+		return &criClient{
+			socket:  socket,
+			runtime: strings.ToLower(version.GetRuntimeName()),
+			conn:    conn,
+			client:  client,
+			logger:  logger,
+		}
+	}
+	return nil
+}
+
+// Close 关闭 CRI 连接
+func (c *criClient) Close() error {
+	return c.conn.Close()
+}
+
+// ListContainers 采集 CRI 运行时中的所有容器，并补充 Pod 信息和安全相关配置
+func (c *criClient) ListContainers(ctx context.Context) ([]interface{}, error) {
+	sandboxes, err := c.listSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, criRequestTimeout)
+	resp, err := c.client.ListContainers(reqCtx, &runtimeapi.ListContainersRequest{})
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cri containers: %w", err)
+	}
+
+	var containers []interface{}
+	for _, ctr := range resp.GetContainers() {
+		select {
+		case <-ctx.Done():
+			return containers, ctx.Err()
+		default:
+		}
+
+		container := &engine.ContainerAsset{
+			Asset: engine.Asset{
+				CollectedAt: time.Now(),
+			},
+			ContainerID:   ctr.GetId(),
+			ContainerName: ctr.GetMetadata().GetName(),
+			Image:         ctr.GetImage().GetImage(),
+			ImageID:       ctr.GetImageRef(),
+			Runtime:       c.runtime,
+			Status:        criContainerState(ctr.GetState()),
+			CreatedAt:     time.Unix(0, ctr.GetCreatedAt()).Format(time.RFC3339),
+			Labels:        ctr.GetLabels(),
+		}
+
+		if sandbox, ok := sandboxes[ctr.GetPodSandboxId()]; ok {
+			container.PodName = sandbox.name
+			container.PodNamespace = sandbox.namespace
+			container.PodUID = sandbox.uid
+			container.HostNetwork = sandbox.hostNetwork
+			container.HostPID = sandbox.hostPID
+			container.HostIPC = sandbox.hostIPC
+		}
+
+		c.fillContainerStatus(ctx, container)
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+// criSandbox 是 Pod Sandbox 的摘要信息
+type criSandbox struct {
+	name        string
+	namespace   string
+	uid         string
+	hostNetwork bool
+	hostPID     bool
+	hostIPC     bool
+}
+
+// listSandboxes 获取所有 Pod Sandbox，返回 sandbox ID 到摘要信息的映射
+func (c *criClient) listSandboxes(ctx context.Context) (map[string]*criSandbox, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, criRequestTimeout)
+	resp, err := c.client.ListPodSandbox(reqCtx, &runtimeapi.ListPodSandboxRequest{})
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod sandboxes: %w", err)
+	}
+
+	sandboxes := make(map[string]*criSandbox, len(resp.GetItems()))
+	for _, item := range resp.GetItems() {
+		sandbox := &criSandbox{
+			name:      item.GetMetadata().GetName(),
+			namespace: item.GetMetadata().GetNamespace(),
+			uid:       item.GetMetadata().GetUid(),
+		}
+
+		// Pod 级别的命名空间配置（hostNetwork/hostPID/hostIPC）只能从 sandbox 状态中获取
+		reqCtx, cancel := context.WithTimeout(ctx, criRequestTimeout)
+		status, err := c.client.PodSandboxStatus(reqCtx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: item.GetId()})
+		cancel()
+		if err != nil {
+			c.logger.Debug("failed to get pod sandbox status",
+				zap.String("sandbox_id", item.GetId()),
+				zap.Error(err))
+		} else {
+			options := status.GetStatus().GetLinux().GetNamespaces().GetOptions()
+			sandbox.hostNetwork = options.GetNetwork() == runtimeapi.NamespaceMode_NODE
+			sandbox.hostPID = options.GetPid() == runtimeapi.NamespaceMode_NODE
+			sandbox.hostIPC = options.GetIpc() == runtimeapi.NamespaceMode_NODE
+		}
+
+		sandboxes[item.GetId()] = sandbox
+	}
+
+	return sandboxes, nil
+}
+
+// fillContainerStatus 通过 ContainerStatus(verbose) 补充挂载、特权和 capabilities 信息
+func (c *criClient) fillContainerStatus(ctx context.Context, container *engine.ContainerAsset) {
+	reqCtx, cancel := context.WithTimeout(ctx, criRequestTimeout)
+	resp, err := c.client.ContainerStatus(reqCtx, &runtimeapi.ContainerStatusRequest{
+		ContainerId: container.ContainerID,
+		Verbose:     true,
+	})
+	cancel()
+	if err != nil {
+		c.logger.Debug("failed to get container status",
+			zap.String("container_id", container.ContainerID),
+			zap.Error(err))
+		return
+	}
+
+	for _, mount := range resp.GetStatus().GetMounts() {
+		if mount.GetHostPath() != "" {
+			container.HostMounts = append(container.HostMounts, mount.GetHostPath())
+		}
+	}
+
+	raw, ok := resp.GetInfo()["info"]
+	if !ok {
+		return
+	}
+	var info criVerboseInfo
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		c.logger.Debug("failed to parse container verbose info",
+			zap.String("container_id", container.ContainerID),
+			zap.Error(err))
+		return
+	}
+
+	container.PID = info.Pid
+	container.Privileged = info.Privileged || info.Config.Linux.SecurityContext.Privileged
+	container.Capabilities = info.Config.Linux.SecurityContext.Capabilities.AddCapabilities
+}
+
+// criContainerState 将 CRI 容器状态转换为与 Docker 一致的状态字符串
+func criContainerState(state runtimeapi.ContainerState) string {
+	switch state {
+	case runtimeapi.ContainerState_CONTAINER_CREATED:
+		return "created"
+	case runtimeapi.ContainerState_CONTAINER_RUNNING:
+		return "running"
+	case runtimeapi.ContainerState_CONTAINER_EXITED:
+		return "exited"
+	default:
+		return "unknown"
+	}
+}
+
+// findSocket 返回第一个存在的 socket 路径
+func findSocket(paths []string) string {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return path
+		}
+	}
+	return ""
+}
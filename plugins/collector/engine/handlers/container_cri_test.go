@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// fakeRuntimeService 是模拟的 CRI RuntimeService，未设置的响应返回 Unimplemented
+type fakeRuntimeService struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+	versionErr   error
+	sandboxes    []*runtimeapi.PodSandbox
+	sandboxErr   error
+	namespaces   map[string]*runtimeapi.NamespaceOption
+	containers   []*runtimeapi.Container
+	statuses     map[string]*runtimeapi.ContainerStatusResponse
+	containerErr error
+}
+
+func (f *fakeRuntimeService) Version(ctx context.Context, req *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	if f.versionErr != nil {
+		return nil, f.versionErr
+	}
+	return &runtimeapi.VersionResponse{RuntimeName: "containerd", RuntimeVersion: "1.7.0"}, nil
+}
+
+func (f *fakeRuntimeService) ListPodSandbox(ctx context.Context, req *runtimeapi.ListPodSandboxRequest) (*runtimeapi.ListPodSandboxResponse, error) {
+	if f.sandboxErr != nil {
+		return nil, f.sandboxErr
+	}
+	return &runtimeapi.ListPodSandboxResponse{Items: f.sandboxes}, nil
+}
+
+func (f *fakeRuntimeService) PodSandboxStatus(ctx context.Context, req *runtimeapi.PodSandboxStatusRequest) (*runtimeapi.PodSandboxStatusResponse, error) {
+	options, ok := f.namespaces[req.PodSandboxId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "sandbox not found")
+	}
+	return &runtimeapi.PodSandboxStatusResponse{Status: &runtimeapi.PodSandboxStatus{
+		Id:    req.PodSandboxId,
+		Linux: &runtimeapi.LinuxPodSandboxStatus{Namespaces: &runtimeapi.Namespace{Options: options}},
+	}}, nil
+}
+
+func (f *fakeRuntimeService) ListContainers(ctx context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	if f.containerErr != nil {
+		return nil, f.containerErr
+	}
+	return &runtimeapi.ListContainersResponse{Containers: f.containers}, nil
+}
+
+func (f *fakeRuntimeService) ContainerStatus(ctx context.Context, req *runtimeapi.ContainerStatusRequest) (*runtimeapi.ContainerStatusResponse, error) {
+	if !req.Verbose {
+		return nil, status.Error(codes.InvalidArgument, "verbose expected")
+	}
+	resp, ok := f.statuses[req.ContainerId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "container not found")
+	}
+	return resp, nil
+}
+
+// startFakeCRI 在临时 unix socket 上启动模拟的 CRI 服务，并让客户端探测到该 socket
+func startFakeCRI(t *testing.T, svc *fakeRuntimeService) {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "containerd.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(server, svc)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	paths := criSocketPaths
+	criSocketPaths = []string{filepath.Join(t.TempDir(), "missing.sock"), socket}
+	t.Cleanup(func() { criSocketPaths = paths })
+}
+
+func newTestCRIClient(t *testing.T, svc *fakeRuntimeService) *criClient {
+	t.Helper()
+	startFakeCRI(t, svc)
+	client := newCRIClient(context.Background(), zap.NewNop())
+	if client == nil {
+		t.Fatal("cri socket not detected")
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// TestCRIListContainers 测试容器与 Pod 信息合并、verbose 信息解析，单个容器状态获取失败时仍上报基础信息
+func TestCRIListContainers(t *testing.T) {
+	svc := &fakeRuntimeService{
+		sandboxes: []*runtimeapi.PodSandbox{
+			{Id: "pod-1", Metadata: &runtimeapi.PodSandboxMetadata{Name: "web-0", Namespace: "prod", Uid: "uid-1"}},
+			{Id: "pod-2", Metadata: &runtimeapi.PodSandboxMetadata{Name: "job-0", Namespace: "batch", Uid: "uid-2"}},
+		},
+		namespaces: map[string]*runtimeapi.NamespaceOption{
+			"pod-1": {Network: runtimeapi.NamespaceMode_NODE, Pid: runtimeapi.NamespaceMode_CONTAINER, Ipc: runtimeapi.NamespaceMode_NODE},
+		},
+		containers: []*runtimeapi.Container{
+			{
+				Id:           "c1",
+				PodSandboxId: "pod-1",
+				Metadata:     &runtimeapi.ContainerMetadata{Name: "nginx"},
+				Image:        &runtimeapi.ImageSpec{Image: "nginx:1.25"},
+				ImageRef:     "sha256:aaa",
+				State:        runtimeapi.ContainerState_CONTAINER_RUNNING,
+				CreatedAt:    1700000000000000000,
+				Labels:       map[string]string{"app": "web"},
+			},
+			{
+				Id:           "c2",
+				PodSandboxId: "pod-2",
+				Metadata:     &runtimeapi.ContainerMetadata{Name: "job"},
+				Image:        &runtimeapi.ImageSpec{Image: "busybox"},
+				State:        runtimeapi.ContainerState_CONTAINER_EXITED,
+			},
+			{
+				Id:       "c3",
+				Metadata: &runtimeapi.ContainerMetadata{Name: "orphan"},
+				State:    runtimeapi.ContainerState_CONTAINER_UNKNOWN,
+			},
+		},
+		statuses: map[string]*runtimeapi.ContainerStatusResponse{
+			"c1": {
+				Status: &runtimeapi.ContainerStatus{Mounts: []*runtimeapi.Mount{
+					{ContainerPath: "/host/etc", HostPath: "/etc"},
+					{ContainerPath: "/tmp"},
+				}},
+				Info: map[string]string{"info": `{"pid":4321,"config":{"linux":{"security_context":{"privileged":true,"capabilities":{"add_capabilities":["NET_ADMIN"]}}}}}`},
+			},
+			"c3": {
+				Status: &runtimeapi.ContainerStatus{},
+				Info:   map[string]string{"info": "not json"},
+			},
+		},
+	}
+	client := newTestCRIClient(t, svc)
+	if client.runtime != "containerd" {
+		t.Errorf("runtime = %q, want containerd", client.runtime)
+	}
+
+	result, err := client.ListContainers(context.Background())
+	if err != nil {
+		t.Fatalf("ListContainers: %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("got %d containers, want 3", len(result))
+	}
+
+	web := result[0].(*engine.ContainerAsset)
+	if web.ContainerName != "nginx" || web.Image != "nginx:1.25" || web.ImageID != "sha256:aaa" ||
+		web.Status != "running" || web.Runtime != "containerd" || web.Labels["app"] != "web" {
+		t.Errorf("web summary = %+v", web)
+	}
+	if web.PodName != "web-0" || web.PodNamespace != "prod" || web.PodUID != "uid-1" {
+		t.Errorf("web pod = %s/%s (%s)", web.PodNamespace, web.PodName, web.PodUID)
+	}
+	if !web.HostNetwork || web.HostPID || !web.HostIPC {
+		t.Errorf("web namespaces = net %v pid %v ipc %v, want true false true", web.HostNetwork, web.HostPID, web.HostIPC)
+	}
+	if web.PID != 4321 || !web.Privileged || len(web.Capabilities) != 1 || web.Capabilities[0] != "NET_ADMIN" {
+		t.Errorf("web verbose = pid %d privileged %v caps %v", web.PID, web.Privileged, web.Capabilities)
+	}
+	if len(web.HostMounts) != 1 || web.HostMounts[0] != "/etc" {
+		t.Errorf("web host mounts = %v, want [/etc]", web.HostMounts)
+	}
+
+	// sandbox 状态和容器状态都获取失败：仍上报 Pod 名称和基础信息
+	job := result[1].(*engine.ContainerAsset)
+	if job.Status != "exited" || job.PodName != "job-0" || job.HostNetwork || job.PID != 0 {
+		t.Errorf("job = %+v", job)
+	}
+
+	// 没有对应 sandbox、verbose 信息无法解析
+	orphan := result[2].(*engine.ContainerAsset)
+	if orphan.Status != "unknown" || orphan.PodName != "" || orphan.Privileged {
+		t.Errorf("orphan = %+v", orphan)
+	}
+}
+
+// TestCRIListContainersError 测试 sandbox 或容器列表请求失败时返回错误
+func TestCRIListContainersError(t *testing.T) {
+	tests := []struct {
+		name string
+		svc  *fakeRuntimeService
+	}{
+		{"sandbox list failed", &fakeRuntimeService{sandboxErr: status.Error(codes.Unavailable, "runtime down")}},
+		{"container list failed", &fakeRuntimeService{containerErr: status.Error(codes.Internal, "list failed")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestCRIClient(t, tt.svc)
+			if result, err := client.ListContainers(context.Background()); err == nil {
+				t.Fatalf("ListContainers() = %v, want error", result)
+			}
+		})
+	}
+}
+
+// TestCRIVersionUnavailable 测试 socket 存在但未启用 CRI（Version 请求失败）时不创建客户端
+func TestCRIVersionUnavailable(t *testing.T) {
+	startFakeCRI(t, &fakeRuntimeService{versionErr: status.Error(codes.Unimplemented, "cri plugin disabled")})
+	if client := newCRIClient(context.Background(), zap.NewNop()); client != nil {
+		client.Close()
+		t.Fatal("newCRIClient() should return nil when Version fails")
+	}
+}
@@ -0,0 +1,160 @@
+// Package handlers 提供各类资产采集器的实现
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// dockerSocketPaths 是 Docker Engine API 的 unix socket 路径
+var dockerSocketPaths = []string{
+	"/var/run/docker.sock",
+	"/run/docker.sock",
+}
+
+// dockerAPIClient 通过 unix socket 直接访问 Docker Engine API，不依赖 docker 命令行
+type dockerAPIClient struct {
+	socket string
+	http   *http.Client
+	logger *zap.Logger
+}
+
+// dockerContainerSummary 是 GET /containers/json 返回的容器摘要
+type dockerContainerSummary struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	ImageID string            `json:"ImageID"`
+	State   string            `json:"State"`
+	Created int64             `json:"Created"`
+	Labels  map[string]string `json:"Labels"`
+}
+
+// dockerContainerInspect 是 GET /containers/{id}/json 返回的容器详情（仅取需要的字段）
+type dockerContainerInspect struct {
+	State struct {
+		Pid int `json:"Pid"`
+	} `json:"State"`
+	HostConfig struct {
+		Privileged  bool     `json:"Privileged"`
+		NetworkMode string   `json:"NetworkMode"`
+		PidMode     string   `json:"PidMode"`
+		IpcMode     string   `json:"IpcMode"`
+		CapAdd      []string `json:"CapAdd"`
+	} `json:"HostConfig"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+	} `json:"Mounts"`
+}
+
+// newDockerAPIClient 探测 Docker Engine API socket，不存在时返回 nil
+func newDockerAPIClient(logger *zap.Logger) *dockerAPIClient {
+	socket := findSocket(dockerSocketPaths)
+	if socket == "" {
+		return nil
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+
+	return &dockerAPIClient{
+		socket: socket,
+		http:   &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		logger: logger,
+	}
+}
+
+// get 请求 Docker Engine API 并解析 JSON 响应
+func (c *dockerAPIClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request docker api %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker api %s returned status %d", path, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ListContainers 采集所有 Docker 容器（包括已停止的容器）
+func (c *dockerAPIClient) ListContainers(ctx context.Context) ([]interface{}, error) {
+	var summaries []dockerContainerSummary
+	if err := c.get(ctx, "/containers/json?all=1", &summaries); err != nil {
+		return nil, err
+	}
+
+	var containers []interface{}
+	for _, summary := range summaries {
+		select {
+		case <-ctx.Done():
+			return containers, ctx.Err()
+		default:
+		}
+
+		container := &engine.ContainerAsset{
+			Asset: engine.Asset{
+				CollectedAt: time.Now(),
+			},
+			ContainerID:  summary.ID,
+			Image:        summary.Image,
+			ImageID:      summary.ImageID,
+			Runtime:      "docker",
+			Status:       summary.State,
+			CreatedAt:    time.Unix(summary.Created, 0).Format(time.RFC3339),
+			Labels:       summary.Labels,
+			PodName:      summary.Labels["io.kubernetes.pod.name"],
+			PodNamespace: summary.Labels["io.kubernetes.pod.namespace"],
+			PodUID:       summary.Labels["io.kubernetes.pod.uid"],
+		}
+		if len(summary.Names) > 0 {
+			container.ContainerName = strings.TrimPrefix(summary.Names[0], "/")
+		}
+
+		// 详情获取失败不影响基础信息上报
+		var inspect dockerContainerInspect
+		if err := c.get(ctx, "/containers/"+summary.ID+"/json", &inspect); err != nil {
+			c.logger.Debug("failed to inspect docker container",
+				zap.String("container_id", summary.ID),
+				zap.Error(err))
+		} else {
+			container.PID = inspect.State.Pid
+			container.Privileged = inspect.HostConfig.Privileged
+			container.HostNetwork = inspect.HostConfig.NetworkMode == "host"
+			container.HostPID = inspect.HostConfig.PidMode == "host"
+			container.HostIPC = inspect.HostConfig.IpcMode == "host"
+			container.Capabilities = inspect.HostConfig.CapAdd
+			for _, mount := range inspect.Mounts {
+				if mount.Type == "bind" && mount.Source != "" {
+					container.HostMounts = append(container.HostMounts, mount.Source)
+				}
+			}
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
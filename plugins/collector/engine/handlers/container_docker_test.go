@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// newFakeDockerAPI 在临时 unix socket 上启动模拟的 Docker Engine API，并让客户端探测到该 socket
+func newFakeDockerAPI(t *testing.T, handler http.Handler) *dockerAPIClient {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	paths := dockerSocketPaths
+	dockerSocketPaths = []string{filepath.Join(t.TempDir(), "missing.sock"), socket}
+	t.Cleanup(func() { dockerSocketPaths = paths })
+
+	client := newDockerAPIClient(zap.NewNop())
+	if client == nil {
+		t.Fatal("docker socket not detected")
+	}
+	return client
+}
+
+// TestDockerListContainers 测试列表与详情合并，单个容器详情获取失败时仍上报基础信息
+func TestDockerListContainers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("all") != "1" {
+			t.Errorf("list query = %q, want all=1", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[
+			{"Id":"c1","Names":["/web"],"Image":"nginx:1.25","ImageID":"sha256:aaa","State":"running","Created":1700000000,
+			 "Labels":{"io.kubernetes.pod.name":"web-0","io.kubernetes.pod.namespace":"prod","io.kubernetes.pod.uid":"uid-1"}},
+			{"Id":"c2","Names":["/job"],"Image":"busybox","State":"exited","Created":1700000100}
+		]`))
+	})
+	mux.HandleFunc("/containers/c1/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"State":{"Pid":4321},
+			"HostConfig":{"Privileged":true,"NetworkMode":"host","PidMode":"","IpcMode":"host","CapAdd":["NET_ADMIN"]},
+			"Mounts":[
+				{"Type":"bind","Source":"/etc","Destination":"/host/etc"},
+				{"Type":"volume","Source":"/var/lib/docker/volumes/data","Destination":"/data"}
+			]
+		}`))
+	})
+	mux.HandleFunc("/containers/c2/json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such container", http.StatusNotFound)
+	})
+	client := newFakeDockerAPI(t, mux)
+
+	result, err := client.ListContainers(context.Background())
+	if err != nil {
+		t.Fatalf("ListContainers: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("got %d containers, want 2", len(result))
+	}
+
+	web := result[0].(*engine.ContainerAsset)
+	if web.ContainerName != "web" || web.Runtime != "docker" || web.Status != "running" || web.ImageID != "sha256:aaa" {
+		t.Errorf("web summary = %+v", web)
+	}
+	if web.PodName != "web-0" || web.PodNamespace != "prod" || web.PodUID != "uid-1" {
+		t.Errorf("web pod = %s/%s (%s)", web.PodNamespace, web.PodName, web.PodUID)
+	}
+	if web.PID != 4321 || !web.Privileged || !web.HostNetwork || web.HostPID || !web.HostIPC {
+		t.Errorf("web inspect = pid %d privileged %v net %v pid %v ipc %v",
+			web.PID, web.Privileged, web.HostNetwork, web.HostPID, web.HostIPC)
+	}
+	if len(web.Capabilities) != 1 || web.Capabilities[0] != "NET_ADMIN" {
+		t.Errorf("web capabilities = %v", web.Capabilities)
+	}
+	if len(web.HostMounts) != 1 || web.HostMounts[0] != "/etc" {
+		t.Errorf("web host mounts = %v, want [/etc]", web.HostMounts)
+	}
+
+	job := result[1].(*engine.ContainerAsset)
+	if job.ContainerName != "job" || job.Status != "exited" {
+		t.Errorf("job summary = %+v", job)
+	}
+	if job.PID != 0 || job.Privileged || len(job.HostMounts) != 0 {
+		t.Errorf("job should only have summary fields after inspect failure: %+v", job)
+	}
+}
+
+// TestDockerListContainersError 测试列表请求失败或响应无法解析时返回错误
+func TestDockerListContainersError(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "daemon error", http.StatusInternalServerError)
+		}},
+		{"invalid json", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"message":`))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeDockerAPI(t, tt.handler)
+			if result, err := client.ListContainers(context.Background()); err == nil {
+				t.Fatalf("ListContainers() = %v, want error", result)
+			}
+		})
+	}
+}
+
+// TestDockerSocketMissing 测试没有 Docker socket 时不创建客户端
+func TestDockerSocketMissing(t *testing.T) {
+	paths := dockerSocketPaths
+	dockerSocketPaths = []string{filepath.Join(t.TempDir(), "docker.sock")}
+	t.Cleanup(func() { dockerSocketPaths = paths })
+
+	if client := newDockerAPIClient(zap.NewNop()); client != nil {
+		t.Fatalf("newDockerAPIClient() = %+v, want nil", client)
+	}
+}
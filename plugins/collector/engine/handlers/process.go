@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"go.uber.org/zap"
@@ -18,15 +19,37 @@ import (
 	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
 )
 
+// clockTicks 是 /proc/{pid}/stat 中时间字段的单位（USER_HZ，Linux 上固定为 100）
+const clockTicks = 100
+
+// namespaceTypes 是需要采集的命名空间类型
+var namespaceTypes = []string{"pid", "mnt", "net", "uts", "ipc", "user", "cgroup"}
+
+// tmpExeDirs 是可疑的可执行文件所在目录
+var tmpExeDirs = []string{"/tmp/", "/var/tmp/", "/dev/shm/"}
+
 // ProcessHandler 是进程采集器
 type ProcessHandler struct {
 	Logger *zap.Logger
+
+	hashCache map[string]string // 文件身份 -> MD5，避免每次采集重复计算
+	verifier  *exeVerifier      // 包管理器完整性校验
 }
 
 // Collect 采集进程信息
 func (h *ProcessHandler) Collect(ctx context.Context) ([]interface{}, error) {
 	var processes []interface{}
 
+	if h.verifier == nil {
+		h.verifier = newExeVerifier()
+	}
+	// 每轮采集重建缓存，只保留仍在运行的可执行文件
+	prevHashes := h.hashCache
+	h.hashCache = make(map[string]string)
+	h.verifier.beginRound()
+
+	bootTime := readBootTime()
+
 	// 遍历 /proc 目录
 	procDir := "/proc"
 	entries, err := os.ReadDir(procDir)
@@ -52,7 +75,7 @@ func (h *ProcessHandler) Collect(ctx context.Context) ([]interface{}, error) {
 		}
 
 		// 采集进程信息
-		proc, err := h.collectProcess(pid)
+		proc, err := h.collectProcess(ctx, pid, bootTime, prevHashes)
 		if err != nil {
 			h.Logger.Debug("failed to collect process",
 				zap.String("pid", pid),
@@ -69,21 +92,35 @@ func (h *ProcessHandler) Collect(ctx context.Context) ([]interface{}, error) {
 }
 
 // collectProcess 采集单个进程信息
-func (h *ProcessHandler) collectProcess(pid string) (*engine.ProcessAsset, error) {
+func (h *ProcessHandler) collectProcess(ctx context.Context, pid string, bootTime time.Time, prevHashes map[string]string) (*engine.ProcessAsset, error) {
 	procPath := filepath.Join("/proc", pid)
 
 	// 读取命令行
 	cmdline, _ := h.readFile(filepath.Join(procPath, "cmdline"))
 	cmdline = strings.ReplaceAll(cmdline, "\x00", " ")
 
-	// 读取可执行文件路径
+	// 读取可执行文件路径（已删除的文件带 " (deleted)" 后缀，memfd 形如 "/memfd:name"）
 	exe, _ := os.Readlink(filepath.Join(procPath, "exe"))
+	exeDeleted := strings.HasSuffix(exe, " (deleted)")
+	exe = strings.TrimSuffix(exe, " (deleted)")
+	exeMemfd := strings.HasPrefix(exe, "/memfd:")
+
+	// 读取工作目录
+	cwd, _ := os.Readlink(filepath.Join(procPath, "cwd"))
 
-	// 读取 stat 文件获取基本信息
+	// 读取 stat 文件获取 PPID 和启动时间
 	stat, _ := h.readFile(filepath.Join(procPath, "stat"))
-	ppid := h.parseStatField(stat, 3) // PPID 在第 4 个字段
+	statFields := h.parseStatFields(stat)
+	var ppid string
+	var startTime time.Time
+	if len(statFields) > 19 {
+		ppid = statFields[1]
+		if ticks, err := strconv.ParseInt(statFields[19], 10, 64); err == nil && !bootTime.IsZero() {
+			startTime = bootTime.Add(time.Duration(ticks) * time.Second / clockTicks)
+		}
+	}
 
-	// 读取 status 文件获取 UID/GID
+	// 读取 status 文件获取 UID/GID 和 capability 集合
 	status, _ := h.readFile(filepath.Join(procPath, "status"))
 	uid := h.parseStatusField(status, "Uid:")
 	gid := h.parseStatusField(status, "Gid:")
@@ -91,14 +128,6 @@ func (h *ProcessHandler) collectProcess(pid string) (*engine.ProcessAsset, error
 	// 解析用户名和组名
 	username, groupname := h.resolveUserGroup(uid, gid)
 
-	// 计算可执行文件 MD5（如果文件存在）
-	var exeHash string
-	if exe != "" {
-		if hash, err := h.calculateMD5(exe); err == nil {
-			exeHash = hash
-		}
-	}
-
 	// 检测容器关联（通过 cgroup）
 	containerID := h.detectContainer(pid)
 
@@ -112,17 +141,114 @@ func (h *ProcessHandler) collectProcess(pid string) (*engine.ProcessAsset, error
 		PPID:        ppid,
 		Cmdline:     cmdline,
 		Exe:         exe,
-		ExeHash:     exeHash,
 		ContainerID: containerID,
 		UID:         uid,
 		GID:         gid,
 		Username:    username,
 		Groupname:   groupname,
+		StartTime:   startTime,
+		Cwd:         cwd,
+		ExeDeleted:  exeDeleted,
+		ExeMemfd:    exeMemfd,
+		CapInh:      h.parseStatusField(status, "CapInh:"),
+		CapPrm:      h.parseStatusField(status, "CapPrm:"),
+		CapEff:      h.parseStatusField(status, "CapEff:"),
+		CapBnd:      h.parseStatusField(status, "CapBnd:"),
+		CapAmb:      h.parseStatusField(status, "CapAmb:"),
+		Namespaces:  h.readNamespaces(procPath),
+	}
+
+	// 通过 /proc/{pid}/exe 访问可执行文件，已删除或位于其他挂载命名空间的文件同样可读
+	if exe != "" {
+		if info, err := os.Stat(filepath.Join(procPath, "exe")); err == nil {
+			fileKey := exe
+			if st, ok := info.Sys().(*syscall.Stat_t); ok {
+				proc.ExeInode = uint64(st.Ino)
+				proc.ExeDev = uint64(st.Dev)
+				fileKey = fmt.Sprintf("%d:%d:%d:%d", st.Dev, st.Ino, info.Size(), info.ModTime().UnixNano())
+			}
+
+			// 计算可执行文件 MD5
+			if hash, ok := prevHashes[fileKey]; ok {
+				proc.ExeHash = hash
+			} else if hash, ok := h.hashCache[fileKey]; ok {
+				proc.ExeHash = hash
+			} else if hash, err := h.calculateMD5(filepath.Join(procPath, "exe")); err == nil {
+				proc.ExeHash = hash
+			}
+			if proc.ExeHash != "" {
+				h.hashCache[fileKey] = proc.ExeHash
+			}
+
+			// 校验宿主机上软件包内可执行文件的完整性（容器内的文件不属于宿主机包管理器）
+			if containerID == "" && !exeDeleted && !exeMemfd && proc.ExeHash != "" {
+				proc.PackageName, proc.ExeIntegrity = h.verifier.verify(ctx, fileKey, exe, proc.ExeHash)
+			}
+		}
 	}
 
+	proc.Suspicious = suspiciousFlags(proc)
+
 	return proc, nil
 }
 
+// suspiciousFlags 根据进程特征计算可疑标记
+func suspiciousFlags(proc *engine.ProcessAsset) []string {
+	var flags []string
+	if proc.ExeDeleted {
+		flags = append(flags, engine.SuspiciousDeletedExe)
+	}
+	if proc.ExeMemfd {
+		flags = append(flags, engine.SuspiciousMemfdExe)
+	}
+	for _, dir := range tmpExeDirs {
+		if strings.HasPrefix(proc.Exe, dir) {
+			flags = append(flags, engine.SuspiciousTmpExe)
+			break
+		}
+	}
+	if proc.ExeIntegrity == engine.ExeIntegrityModified {
+		flags = append(flags, engine.SuspiciousModifiedBinary)
+	}
+	return flags
+}
+
+// readNamespaces 读取进程所属的命名空间（/proc/{pid}/ns/* 链接形如 "pid:[4026531836]"）
+func (h *ProcessHandler) readNamespaces(procPath string) map[string]string {
+	namespaces := make(map[string]string, len(namespaceTypes))
+	for _, ns := range namespaceTypes {
+		link, err := os.Readlink(filepath.Join(procPath, "ns", ns))
+		if err != nil {
+			continue
+		}
+		start := strings.Index(link, "[")
+		end := strings.LastIndex(link, "]")
+		if start >= 0 && end > start {
+			namespaces[ns] = link[start+1 : end]
+		}
+	}
+	if len(namespaces) == 0 {
+		return nil
+	}
+	return namespaces
+}
+
+// readBootTime 读取系统启动时间（/proc/stat 中的 btime）
+func readBootTime() time.Time {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "btime ") {
+			if sec, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "btime ")), 10, 64); err == nil {
+				return time.Unix(sec, 0)
+			}
+		}
+	}
+	return time.Time{}
+}
+
 // readFile 读取文件内容（简化实现，忽略错误）
 func (h *ProcessHandler) readFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
@@ -132,13 +258,15 @@ func (h *ProcessHandler) readFile(path string) (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
-// parseStatField 解析 /proc/{pid}/stat 文件的字段
-func (h *ProcessHandler) parseStatField(stat string, index int) string {
-	fields := strings.Fields(stat)
-	if len(fields) > index {
-		return fields[index]
+// parseStatFields 解析 /proc/{pid}/stat 文件中进程名之后的字段
+// 进程名（第 2 个字段）可能包含空格和括号，因此从最后一个 ')' 之后开始解析，
+// 返回值的第 0 个元素是 state（原第 3 个字段）
+func (h *ProcessHandler) parseStatFields(stat string) []string {
+	idx := strings.LastIndex(stat, ")")
+	if idx < 0 {
+		return nil
 	}
-	return ""
+	return strings.Fields(stat[idx+1:])
 }
 
 // parseStatusField 解析 /proc/{pid}/status 文件的字段
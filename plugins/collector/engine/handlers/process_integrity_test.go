@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// fakeRPM 模拟 rpm -qf 与 rpm -V 的输出
+const fakeRPM = `#!/bin/sh
+case "$1" in
+-qf)
+	case "$4" in
+	/usr/bin/ls) echo coreutils ;;
+	/usr/sbin/sshd) echo openssh-server ;;
+	*) echo "file $4 is not owned by any package"; exit 1 ;;
+	esac ;;
+-V)
+	case "$4" in
+	coreutils) echo "..5......    /usr/bin/cat"; exit 1 ;;
+	openssh-server) echo "S.5....T.    /usr/sbin/sshd"; echo "..5......  c /etc/ssh/sshd_config"; exit 1 ;;
+	esac ;;
+esac
+`
+
+// fakeDPKG 模拟 dpkg -S 的输出（含 diversion 行和多个软件包共享路径）
+const fakeDPKG = `#!/bin/sh
+case "$2" in
+/usr/bin/ls) echo "coreutils: /usr/bin/ls" ;;
+/bin/bash) echo "diversion by dash from: /bin/bash"; echo "bash: /bin/bash" ;;
+/usr/bin/vim) echo "vim, vim-tiny: /usr/bin/vim" ;;
+*) echo "dpkg-query: no path found matching pattern $2" >&2; exit 1 ;;
+esac
+`
+
+// installFakeTool 将模拟的包管理器命令放入 PATH，使其成为唯一可用的包管理器
+func installFakeTool(t *testing.T, name, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestExeVerifierRPM 测试 rpm 校验只根据可执行文件自身的摘要差异判断是否被篡改
+func TestExeVerifierRPM(t *testing.T) {
+	installFakeTool(t, "rpm", fakeRPM)
+	v := newExeVerifier()
+	if v.pkgManager != "rpm" {
+		t.Fatalf("pkgManager = %q, want rpm", v.pkgManager)
+	}
+	v.beginRound()
+
+	tests := []struct {
+		exe           string
+		wantPkg       string
+		wantIntegrity string
+	}{
+		{"/usr/bin/ls", "coreutils", engine.ExeIntegrityVerified},
+		{"/usr/sbin/sshd", "openssh-server", engine.ExeIntegrityModified},
+		{"/opt/app/server", "", engine.ExeIntegrityUnpackaged},
+	}
+	for _, tt := range tests {
+		pkg, integrity := v.verify(context.Background(), tt.exe, tt.exe, "")
+		if pkg != tt.wantPkg || integrity != tt.wantIntegrity {
+			t.Errorf("verify(%s) = (%q, %q), want (%q, %q)", tt.exe, pkg, integrity, tt.wantPkg, tt.wantIntegrity)
+		}
+	}
+}
+
+// TestExeVerifierDPKG 测试 dpkg 摘要对比，包括 usrmerge 路径、多架构 md5sums 文件和未记录摘要的文件
+func TestExeVerifierDPKG(t *testing.T) {
+	installFakeTool(t, "dpkg", fakeDPKG)
+	infoDir := t.TempDir()
+	orig := dpkgInfoDir
+	dpkgInfoDir = infoDir
+	t.Cleanup(func() { dpkgInfoDir = orig })
+	writeFile(t, filepath.Join(infoDir, "coreutils.md5sums"), "aaaa  usr/bin/cat\nbbbb  usr/bin/ls\n")
+	writeFile(t, filepath.Join(infoDir, "bash:amd64.md5sums"), "cccc  bin/bash\n")
+	writeFile(t, filepath.Join(infoDir, "vim.md5sums"), "dddd  usr/share/vim/vimrc\n")
+
+	v := newExeVerifier()
+	if v.pkgManager != "dpkg" {
+		t.Fatalf("pkgManager = %q, want dpkg", v.pkgManager)
+	}
+	v.beginRound()
+
+	tests := []struct {
+		name          string
+		exe           string
+		md5           string
+		wantPkg       string
+		wantIntegrity string
+	}{
+		{"verified", "/usr/bin/ls", "bbbb", "coreutils", engine.ExeIntegrityVerified},
+		{"modified", "/usr/bin/ls", "ffff", "coreutils", engine.ExeIntegrityModified},
+		{"usrmerge and multiarch", "/usr/bin/bash", "cccc", "bash", engine.ExeIntegrityVerified},
+		{"no recorded digest", "/usr/bin/vim", "eeee", "vim", ""},
+		{"unpackaged", "/opt/app/server", "ffff", "", engine.ExeIntegrityUnpackaged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 以用例名作为文件身份，避免命中其他用例的缓存
+			pkg, integrity := v.verify(context.Background(), tt.name, tt.exe, tt.md5)
+			if pkg != tt.wantPkg || integrity != tt.wantIntegrity {
+				t.Errorf("verify(%s) = (%q, %q), want (%q, %q)", tt.exe, pkg, integrity, tt.wantPkg, tt.wantIntegrity)
+			}
+		})
+	}
+}
+
+// TestExeVerifierCache 测试校验结果按文件身份缓存，连续一轮未使用的结果被淘汰，被取消的校验不缓存
+func TestExeVerifierCache(t *testing.T) {
+	installFakeTool(t, "rpm", fakeRPM)
+	v := newExeVerifier()
+	v.beginRound()
+	ctx := context.Background()
+
+	if _, integrity := v.verify(ctx, "key-1", "/usr/bin/ls", ""); integrity != engine.ExeIntegrityVerified {
+		t.Fatalf("first verify = %q, want verified", integrity)
+	}
+
+	// 移除 rpm 命令后只能命中缓存
+	t.Setenv("PATH", t.TempDir())
+	if _, integrity := v.verify(ctx, "key-1", "/usr/bin/ls", ""); integrity != engine.ExeIntegrityVerified {
+		t.Errorf("cached verify = %q, want verified", integrity)
+	}
+	v.beginRound()
+	if pkg, integrity := v.verify(ctx, "key-1", "/usr/bin/ls", ""); pkg != "coreutils" || integrity != engine.ExeIntegrityVerified {
+		t.Errorf("verify from previous round = (%q, %q), want (coreutils, verified)", pkg, integrity)
+	}
+
+	v.beginRound()
+	v.beginRound()
+	if _, integrity := v.verify(ctx, "key-1", "/usr/bin/ls", ""); integrity != engine.ExeIntegrityUnpackaged {
+		t.Errorf("verify after eviction = %q, want re-run (unpackaged without rpm)", integrity)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	v.verify(canceled, "key-2", "/usr/bin/ls", "")
+	if _, ok := v.cache["key-2"]; ok {
+		t.Error("result of canceled verification should not be cached")
+	}
+}
+
+// TestExeVerifierNoPackageManager 测试没有包管理器时不做判断
+func TestExeVerifierNoPackageManager(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	v := newExeVerifier()
+	v.beginRound()
+	if pkg, integrity := v.verify(context.Background(), "key", "/usr/bin/ls", "bbbb"); pkg != "" || integrity != "" {
+		t.Errorf("verify() = (%q, %q), want empty", pkg, integrity)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/imkerbos/mxsec-platform/plugins/collector/engine"
+)
+
+// TestSuspiciousFlags 测试可疑进程标记的判断规则
+func TestSuspiciousFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		proc engine.ProcessAsset
+		want []string
+	}{
+		{"normal", engine.ProcessAsset{Exe: "/usr/sbin/sshd", ExeIntegrity: engine.ExeIntegrityVerified}, nil},
+		{"unpackaged", engine.ProcessAsset{Exe: "/opt/app/bin/server", ExeIntegrity: engine.ExeIntegrityUnpackaged}, nil},
+		{"deleted", engine.ProcessAsset{Exe: "/usr/bin/miner", ExeDeleted: true}, []string{engine.SuspiciousDeletedExe}},
+		{"memfd", engine.ProcessAsset{Exe: "/memfd:payload", ExeMemfd: true}, []string{engine.SuspiciousMemfdExe}},
+		{"tmp", engine.ProcessAsset{Exe: "/tmp/x"}, []string{engine.SuspiciousTmpExe}},
+		{"var tmp", engine.ProcessAsset{Exe: "/var/tmp/.cache/x"}, []string{engine.SuspiciousTmpExe}},
+		{"dev shm", engine.ProcessAsset{Exe: "/dev/shm/x"}, []string{engine.SuspiciousTmpExe}},
+		{"tmp prefix only", engine.ProcessAsset{Exe: "/tmpfs/bin/x"}, nil},
+		{"modified", engine.ProcessAsset{Exe: "/usr/bin/ls", ExeIntegrity: engine.ExeIntegrityModified}, []string{engine.SuspiciousModifiedBinary}},
+		{
+			"deleted from tmp",
+			engine.ProcessAsset{Exe: "/dev/shm/kworker", ExeDeleted: true},
+			[]string{engine.SuspiciousDeletedExe, engine.SuspiciousTmpExe},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suspiciousFlags(&tt.proc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suspiciousFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package engine
 
 import (
 	"encoding/json"
+	"strconv"
 	"time"
 )
 
@@ -25,8 +26,39 @@ type ProcessAsset struct {
 	GID         string `json:"gid"`
 	Username    string `json:"username,omitempty"`
 	Groupname   string `json:"groupname,omitempty"`
+
+	StartTime    time.Time         `json:"start_time,omitempty"`    // 进程启动时间
+	Cwd          string            `json:"cwd,omitempty"`           // 工作目录
+	ExeInode     uint64            `json:"exe_inode,omitempty"`     // 可执行文件 inode
+	ExeDev       uint64            `json:"exe_dev,omitempty"`       // 可执行文件所在设备号
+	ExeDeleted   bool              `json:"exe_deleted,omitempty"`   // 可执行文件已被删除
+	ExeMemfd     bool              `json:"exe_memfd,omitempty"`     // 可执行文件来自 memfd（无文件落地）
+	CapInh       string            `json:"cap_inh,omitempty"`       // Inheritable capability 集合（十六进制）
+	CapPrm       string            `json:"cap_prm,omitempty"`       // Permitted capability 集合
+	CapEff       string            `json:"cap_eff,omitempty"`       // Effective capability 集合
+	CapBnd       string            `json:"cap_bnd,omitempty"`       // Bounding capability 集合
+	CapAmb       string            `json:"cap_amb,omitempty"`       // Ambient capability 集合
+	Namespaces   map[string]string `json:"namespaces,omitempty"`    // 命名空间类型 -> inode（pid、mnt、net 等）
+	PackageName  string            `json:"package_name,omitempty"`  // 可执行文件所属的软件包
+	ExeIntegrity string            `json:"exe_integrity,omitempty"` // 可执行文件完整性：verified/modified/unpackaged，空表示未校验
+	Suspicious   []string          `json:"suspicious,omitempty"`    // 可疑特征（deleted_exe、memfd_exe 等）
 }
 
+// 可执行文件完整性校验结果
+const (
+	ExeIntegrityVerified   = "verified"   // 与包管理器记录的摘要一致
+	ExeIntegrityModified   = "modified"   // 与包管理器记录的摘要不一致
+	ExeIntegrityUnpackaged = "unpackaged" // 不属于任何软件包
+)
+
+// 进程可疑特征
+const (
+	SuspiciousDeletedExe     = "deleted_exe"     // 可执行文件已被删除（常见于恶意程序运行后自删除）
+	SuspiciousMemfdExe       = "memfd_exe"       // 从 memfd 执行（无文件落地）
+	SuspiciousTmpExe         = "tmp_exe"         // 从 /tmp、/dev/shm 等临时目录执行
+	SuspiciousModifiedBinary = "modified_binary" // 软件包内的可执行文件被篡改
+)
+
 // PortAsset 是端口资产数据
 type PortAsset struct {
 	Asset
@@ -72,6 +104,19 @@ type ContainerAsset struct {
 	Runtime       string `json:"runtime"`              // 运行时（docker、containerd）
 	Status        string `json:"status"`               // 状态（running、stopped 等）
 	CreatedAt     string `json:"created_at,omitempty"` // 创建时间
+
+	// 以下字段由 CRI / Docker Engine API 采集，CLI 回退模式下可能为空
+	PID          int               `json:"pid,omitempty"`           // 容器主进程 PID（宿主机视角）
+	PodName      string            `json:"pod_name,omitempty"`      // Pod 名称（Kubernetes）
+	PodNamespace string            `json:"pod_namespace,omitempty"` // Pod 命名空间（Kubernetes）
+	PodUID       string            `json:"pod_uid,omitempty"`       // Pod UID（Kubernetes）
+	Labels       map[string]string `json:"labels,omitempty"`        // 容器标签
+	Privileged   bool              `json:"privileged"`              // 是否特权容器
+	HostNetwork  bool              `json:"host_network"`            // 是否共享宿主机网络命名空间
+	HostPID      bool              `json:"host_pid"`                // 是否共享宿主机 PID 命名空间
+	HostIPC      bool              `json:"host_ipc"`                // 是否共享宿主机 IPC 命名空间
+	Capabilities []string          `json:"capabilities,omitempty"`  // 额外添加的 Linux capabilities
+	HostMounts   []string          `json:"host_mounts,omitempty"`   // 挂载的宿主机路径
 }
 
 // AppAsset 是应用资产数据
@@ -138,11 +183,59 @@ type CronAsset struct {
 	Enabled  bool   `json:"enabled"`   // 是否启用
 }
 
+// KeyedAsset 是可以唯一标识的资产，用于增量上报时比对变化
+// AssetKey 返回资产在单台主机内的唯一键（与 Server 端资产表主键的组成一致）
+type KeyedAsset interface {
+	AssetKey() []string
+}
+
+// AssetKey 实现 KeyedAsset 接口
+func (a ProcessAsset) AssetKey() []string { return []string{a.PID} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a PortAsset) AssetKey() []string { return []string{a.Protocol, strconv.Itoa(a.Port)} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a UserAsset) AssetKey() []string { return []string{a.Username} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a SoftwareAsset) AssetKey() []string { return []string{a.PackageType, a.Name} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a ContainerAsset) AssetKey() []string { return []string{a.ContainerID} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a AppAsset) AssetKey() []string { return []string{a.AppType, a.AppName} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a NetInterfaceAsset) AssetKey() []string { return []string{a.InterfaceName} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a VolumeAsset) AssetKey() []string { return []string{a.MountPoint} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a KmodAsset) AssetKey() []string { return []string{a.ModuleName} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a ServiceAsset) AssetKey() []string { return []string{a.ServiceName} }
+
+// AssetKey 实现 KeyedAsset 接口
+func (a CronAsset) AssetKey() []string { return []string{a.User, a.Schedule} }
+
 // SerializeAssets 序列化资产数据为 JSON
 func SerializeAssets(assets interface{}) ([]byte, error) {
 	return json.Marshal(assets)
 }
 
+// DataTypeCollectComplete 是按需采集任务完成信号的 data_type
+const DataTypeCollectComplete int32 = 5099
+
+// CollectTypes 是所有采集类型（与 GetDataType 一一对应）
+var CollectTypes = []string{
+	"process", "port", "user", "software", "container", "app",
+	"network", "volume", "kmod", "service", "cron",
+}
+
 // GetDataType 根据采集类型返回对应的 data_type
 func GetDataType(collectType string) int32 {
 	switch collectType {
@@ -172,3 +265,13 @@ func GetDataType(collectType string) int32 {
 		return 0
 	}
 }
+
+// GetCollectType 根据 data_type 获取采集类型，未知类型返回空字符串
+func GetCollectType(dataType int32) string {
+	for _, name := range CollectTypes {
+		if GetDataType(name) == dataType {
+			return name
+		}
+	}
+	return ""
+}
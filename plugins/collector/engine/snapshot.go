@@ -0,0 +1,285 @@
+// Package engine 提供采集引擎的核心功能
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/imkerbos/mxsec-platform/api/proto/bridge"
+)
+
+// 分块快照上报协议
+//
+// 每次采集生成一个快照（snapshot_id），按字节大小切分为多个记录（seq 从 0 开始），
+// 最后一个记录带 end=true 结束标记。已有 Server 确认的快照时只上报相对该快照的变化（delta），
+// 否则上报全量（full）。Server 收齐所有分块后原子应用，并通过 DataType 5098 任务回复确认。
+const (
+	// DataTypeSnapshotAck 是 Server 下发的快照确认任务类型
+	DataTypeSnapshotAck int32 = 5098
+
+	// MaxChunkBytes 是单个分块记录中资产数据的最大字节数
+	MaxChunkBytes = 256 * 1024
+
+	// SnapshotModeFull 表示全量快照：Server 应用后删除快照中不存在的资产
+	SnapshotModeFull = "full"
+	// SnapshotModeDelta 表示增量快照：只包含新增/变化的资产和被删除资产的 key
+	SnapshotModeDelta = "delta"
+
+	// SnapshotAckOK 表示快照已应用，可作为后续增量的基准
+	SnapshotAckOK = "ok"
+	// SnapshotAckResync 表示 Server 无法应用增量（基准不一致），需要重新上报全量
+	SnapshotAckResync = "resync"
+
+	// maxPendingSnapshots 是等待确认的快照数量上限，超出时丢弃最旧的
+	maxPendingSnapshots = 4
+)
+
+// SnapshotAck 是 Server 对资产快照的确认（DataType 5098 任务的 Data）
+type SnapshotAck struct {
+	Type       string `json:"type"`        // 采集类型（process、port 等）
+	SnapshotID string `json:"snapshot_id"` // 快照 ID
+	Status     string `json:"status"`      // ok / resync
+}
+
+// snapshotState 是单个采集器的快照状态
+type snapshotState struct {
+	ackedID string            // 最近一次被 Server 确认的快照 ID
+	acked   map[string]string // 已确认快照的资产 key -> 内容哈希
+	pending []pendingSnapshot // 已发送、等待确认的快照（按发送顺序）
+}
+
+// pendingSnapshot 是已发送、等待确认的快照
+type pendingSnapshot struct {
+	id    string
+	state map[string]string
+}
+
+// snapshotEntry 是快照中的单个资产
+type snapshotEntry struct {
+	key  string
+	hash string
+	data json.RawMessage
+}
+
+// reportSnapshot 以分块快照方式上报资产，返回上报的资产数量
+func (e *Engine) reportSnapshot(h *HandlerConfig, dataType int32, assets []interface{}) (int, error) {
+	entries := make([]snapshotEntry, 0, len(assets))
+	current := make(map[string]string, len(assets))
+	for _, asset := range assets {
+		keyed, ok := asset.(KeyedAsset)
+		if !ok {
+			return 0, fmt.Errorf("asset %T does not implement KeyedAsset", asset)
+		}
+		data, err := json.Marshal(asset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to serialize asset: %w", err)
+		}
+		key := strings.Join(keyed.AssetKey(), "\x00")
+		hash := contentHash(data)
+		entries = append(entries, snapshotEntry{key: key, hash: hash, data: data})
+		current[key] = hash
+	}
+
+	e.snapMu.Lock()
+	state, ok := e.snapshots[h.Name]
+	if !ok {
+		state = &snapshotState{}
+		e.snapshots[h.Name] = state
+	}
+	ackedID, acked := state.ackedID, state.acked
+	e.snapMu.Unlock()
+
+	// 首次采集且没有资产：无需上报
+	if ackedID == "" && len(entries) == 0 {
+		return 0, nil
+	}
+
+	mode := SnapshotModeFull
+	upserts := entries
+	var deleted [][]string
+	if ackedID != "" {
+		mode = SnapshotModeDelta
+		upserts = upserts[:0:0]
+		for _, entry := range entries {
+			if acked[entry.key] != entry.hash {
+				upserts = append(upserts, entry)
+			}
+		}
+		for key := range acked {
+			if _, exists := current[key]; !exists {
+				deleted = append(deleted, strings.Split(key, "\x00"))
+			}
+		}
+	}
+
+	deletedJSON, err := json.Marshal(deleted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize deleted keys: %w", err)
+	}
+
+	snapshotID := fmt.Sprintf("%s-%d", h.Name, time.Now().UnixNano())
+	chunks := chunkEntries(upserts, MaxChunkBytes)
+	for seq, chunk := range chunks {
+		fields := map[string]string{
+			"data":             string(chunk),
+			"snapshot_id":      snapshotID,
+			"seq":              strconv.Itoa(seq),
+			"total":            strconv.Itoa(len(chunks)),
+			"mode":             mode,
+			"base_snapshot_id": ackedID,
+		}
+		if seq == len(chunks)-1 {
+			fields["end"] = "true"
+			fields["deleted"] = string(deletedJSON)
+		}
+
+		record := &bridge.Record{
+			DataType:  dataType,
+			Timestamp: time.Now().UnixNano(),
+			Data:      &bridge.Payload{Fields: fields},
+		}
+		if err := e.client.SendRecord(record); err != nil {
+			return 0, fmt.Errorf("failed to send snapshot chunk %d/%d: %w", seq+1, len(chunks), err)
+		}
+	}
+
+	e.snapMu.Lock()
+	state.pending = append(state.pending, pendingSnapshot{id: snapshotID, state: current})
+	if len(state.pending) > maxPendingSnapshots {
+		state.pending = state.pending[len(state.pending)-maxPendingSnapshots:]
+	}
+	e.snapMu.Unlock()
+
+	e.logger.Info("asset snapshot reported",
+		zap.String("handler", h.Name),
+		zap.String("snapshot_id", snapshotID),
+		zap.String("mode", mode),
+		zap.Int("total", len(entries)),
+		zap.Int("upserts", len(upserts)),
+		zap.Int("deleted", len(deleted)),
+		zap.Int("chunks", len(chunks)))
+
+	return len(entries), nil
+}
+
+// HandleSnapshotAck 处理 Server 的快照确认，返回是否需要立即重新上报全量快照
+func (e *Engine) HandleSnapshotAck(ack *SnapshotAck) bool {
+	e.snapMu.Lock()
+	defer e.snapMu.Unlock()
+
+	state, ok := e.snapshots[ack.Type]
+	if !ok {
+		return false
+	}
+
+	switch ack.Status {
+	case SnapshotAckOK:
+		for i, pending := range state.pending {
+			if pending.id != ack.SnapshotID {
+				continue
+			}
+			state.ackedID = pending.id
+			state.acked = pending.state
+			// 更早的快照已经过时
+			state.pending = state.pending[i+1:]
+			return false
+		}
+		e.logger.Debug("ack for unknown snapshot ignored",
+			zap.String("handler", ack.Type),
+			zap.String("snapshot_id", ack.SnapshotID))
+		return false
+	case SnapshotAckResync:
+		e.logger.Info("server requested full snapshot resync",
+			zap.String("handler", ack.Type),
+			zap.String("snapshot_id", ack.SnapshotID))
+		state.ackedID = ""
+		state.acked = nil
+		state.pending = nil
+		return true
+	default:
+		return false
+	}
+}
+
+// chunkEntries 将资产按字节大小切分为多个 JSON 数组，没有资产时返回一个空数组（用于携带结束标记）
+func chunkEntries(entries []snapshotEntry, maxBytes int) [][]byte {
+	var chunks [][]byte
+	buf := []byte{'['}
+	for _, entry := range entries {
+		// 当前分块已有数据且加入后超过上限时，先结束当前分块
+		if len(buf) > 1 && len(buf)+len(entry.data)+2 > maxBytes {
+			chunks = append(chunks, append(buf, ']'))
+			buf = []byte{'['}
+		}
+		if len(buf) > 1 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, entry.data...)
+	}
+	return append(chunks, append(buf, ']'))
+}
+
+// contentHash 计算资产内容哈希（忽略 collected_at、host_id，避免未变化的资产被判定为变化）
+func contentHash(data []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err == nil {
+		delete(fields, "collected_at")
+		delete(fields, "host_id")
+		// map 序列化时按 key 排序，结果稳定
+		if normalized, err := json.Marshal(fields); err == nil {
+			data = normalized
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// DecodeAssetKeys 解析分块数据中所有资产的唯一键（供 Server 计算资产表主键）
+func DecodeAssetKeys(dataType int32, data []byte) ([][]string, error) {
+	switch dataType {
+	case 5050:
+		return decodeKeys[ProcessAsset](data)
+	case 5051:
+		return decodeKeys[PortAsset](data)
+	case 5052:
+		return decodeKeys[UserAsset](data)
+	case 5053:
+		return decodeKeys[SoftwareAsset](data)
+	case 5054:
+		return decodeKeys[ContainerAsset](data)
+	case 5055:
+		return decodeKeys[AppAsset](data)
+	case 5056:
+		return decodeKeys[NetInterfaceAsset](data)
+	case 5057:
+		return decodeKeys[VolumeAsset](data)
+	case 5058:
+		return decodeKeys[KmodAsset](data)
+	case 5059:
+		return decodeKeys[ServiceAsset](data)
+	case 5060:
+		return decodeKeys[CronAsset](data)
+	default:
+		return nil, fmt.Errorf("unknown asset data type: %d", dataType)
+	}
+}
+
+// decodeKeys 解析指定类型的资产数组并返回每个资产的唯一键
+func decodeKeys[T KeyedAsset](data []byte) ([][]string, error) {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	keys := make([][]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.AssetKey())
+	}
+	return keys, nil
+}
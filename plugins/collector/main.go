@@ -52,7 +52,7 @@ func main() {
 	// 3. 创建采集引擎
 	collectEngine := engine.NewEngine(client, logger)
 
-	// 4. 注册所有采集器
+	// 4. 注册所有采集器（此处为默认间隔，Server 可通过插件配置 detail.handlers 覆盖）
 	// 基础采集器
 	collectEngine.RegisterHandler("process", time.Hour, &handlers.ProcessHandler{Logger: logger})
 	collectEngine.RegisterHandler("port", time.Hour, &handlers.PortHandler{Logger: logger})
@@ -134,6 +134,16 @@ func handleTask(ctx context.Context, task *bridge.Task, collectEngine *engine.En
 		zap.Int32("data_type", task.DataType),
 		zap.String("object_name", task.ObjectName))
 
+	// 插件配置（Config.detail）：运行时应用，无需重启插件
+	if task.DataType == plugins.DataTypePluginConfig {
+		return handleConfigTask(task, collectEngine, logger)
+	}
+
+	// 资产快照确认：推进增量基准，或按 Server 要求重新上报全量
+	if task.DataType == engine.DataTypeSnapshotAck {
+		return handleSnapshotAck(ctx, task, collectEngine, logger)
+	}
+
 	// 解析任务数据（JSON）
 	var taskData map[string]interface{}
 	if err := json.Unmarshal([]byte(task.Data), &taskData); err != nil {
@@ -150,6 +160,44 @@ func handleTask(ctx context.Context, task *bridge.Task, collectEngine *engine.En
 	}
 }
 
+// handleConfigTask 处理插件配置下发
+func handleConfigTask(task *bridge.Task, collectEngine *engine.Engine, logger *zap.Logger) error {
+	if task.Data == "" {
+		return nil
+	}
+
+	var cfg engine.PluginConfig
+	if err := json.Unmarshal([]byte(task.Data), &cfg); err != nil {
+		return fmt.Errorf("failed to unmarshal plugin config: %w", err)
+	}
+
+	logger.Info("applying plugin config", zap.Int("handler_count", len(cfg.Handlers)))
+	collectEngine.ApplyConfig(&cfg)
+	return nil
+}
+
+// handleSnapshotAck 处理 Server 的资产快照确认
+func handleSnapshotAck(ctx context.Context, task *bridge.Task, collectEngine *engine.Engine, logger *zap.Logger) error {
+	var ack engine.SnapshotAck
+	if err := json.Unmarshal([]byte(task.Data), &ack); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot ack: %w", err)
+	}
+
+	if !collectEngine.HandleSnapshotAck(&ack) {
+		return nil
+	}
+
+	// 基准不一致，立即重新采集并上报全量快照（异步执行，避免阻塞任务处理）
+	go func() {
+		if _, err := collectEngine.CollectOnce(ctx, ack.Type); err != nil {
+			logger.Error("failed to resync asset snapshot",
+				zap.String("type", ack.Type),
+				zap.Error(err))
+		}
+	}()
+	return nil
+}
+
 // handleCollectTask 处理资产采集任务
 func handleCollectTask(ctx context.Context, task *bridge.Task, taskData map[string]interface{}, collectEngine *engine.Engine, logger *zap.Logger) error {
 	// 提取采集类型
@@ -157,15 +205,30 @@ func handleCollectTask(ctx context.Context, task *bridge.Task, taskData map[stri
 	if !ok {
 		return fmt.Errorf("missing type in task data")
 	}
+	taskID, _ := taskData["task_id"].(string)
 
-	logger.Info("executing collect task", zap.String("type", collectType))
+	logger.Info("executing collect task",
+		zap.String("type", collectType),
+		zap.String("task_id", taskID))
 
 	// 触发对应采集器执行
-	if err := collectEngine.CollectOnce(ctx, collectType); err != nil {
-		return fmt.Errorf("failed to collect %s: %w", collectType, err)
+	count, collectErr := collectEngine.CollectOnce(ctx, collectType)
+
+	// 由 Server 发起的按需采集需要上报完成信号
+	if taskID != "" {
+		if err := collectEngine.ReportCollectComplete(taskID, task.Token, collectType, count, collectErr); err != nil {
+			logger.Error("failed to report collect completion",
+				zap.String("task_id", taskID),
+				zap.String("type", collectType),
+				zap.Error(err))
+		}
+	}
+
+	if collectErr != nil {
+		return fmt.Errorf("failed to collect %s: %w", collectType, collectErr)
 	}
 
-	logger.Info("collect task completed", zap.String("type", collectType))
+	logger.Info("collect task completed", zap.String("type", collectType), zap.Int("count", count))
 	return nil
 }
 
@@ -152,11 +152,20 @@ func handleTask(ctx context.Context, task *bridge.Task, fimEngine *engine.Engine
 
 // handleFIMCheckTask 处理 FIM 检查任务
 func handleFIMCheckTask(ctx context.Context, task *bridge.Task, fimEngine *engine.Engine, client *plugins.Client, logger *zap.Logger) error {
-	startTime := time.Now()
-
 	// 提取 task_id（通过 Token 传递）
 	taskID := task.Token
 
+	// Agent 限流期间推迟检查（AIDE 全量比对开销较大），限流解除后再执行
+	if throttled, reason := client.Throttled(); throttled {
+		logger.Info("FIM check deferred by agent throttle",
+			zap.String("task_id", taskID),
+			zap.String("reason", reason))
+		if err := client.WaitThrottle(ctx); err != nil {
+			return err
+		}
+	}
+
+	startTime := time.Now()
 	logger.Info("executing FIM check", zap.String("task_id", taskID))
 
 	// 执行检查
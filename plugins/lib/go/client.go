@@ -4,7 +4,9 @@ package plugins
 
 import (
 	"bufio"
+	"context"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -23,6 +25,11 @@ type Client struct {
 	writer *bufio.Writer  // 带缓冲的写入器
 	rmu    *sync.Mutex    // 读取锁
 	wmu    *sync.Mutex    // 写入锁
+
+	tmu            sync.Mutex    // 保护限流状态
+	throttleUntil  time.Time     // 限流截止时间（零值表示未限流）
+	throttleReason string        // 限流原因
+	throttleCh     chan struct{} // 限流状态变化时关闭并重建，用于唤醒 WaitThrottle
 }
 
 // NewClient 创建新的插件客户端
@@ -47,6 +54,8 @@ func NewClient() (*Client, error) {
 		writer: bufio.NewWriter(tx),
 		rmu:    &sync.Mutex{},
 		wmu:    &sync.Mutex{},
+
+		throttleCh: make(chan struct{}),
 	}, nil
 }
 
@@ -103,9 +112,26 @@ const (
 	dataTypeHeartbeatPong int32 = 9001
 )
 
+// DataTypePluginConfig 是 Agent 下发插件配置的任务类型
+// Task.Data 为 Server 下发的 Config.detail（JSON 字符串），插件启动后及配置变更时各下发一次，
+// 插件应在不重启的情况下应用新配置
+const DataTypePluginConfig int32 = 9002
+
+// DataTypeThrottle 是 Agent 下发限流状态的任务类型
+// Task.Data 为 JSON：{"until": Unix 秒, "reason": "..."}，until 为 0 表示解除限流。
+// Agent 自身及插件超出资源预算或主机负载过高时下发，SDK 自动拦截，
+// 插件通过 Throttled / WaitThrottle 推迟周期性工作
+const DataTypeThrottle int32 = 9003
+
+// throttleState 是 DataTypeThrottle 任务的数据
+type throttleState struct {
+	Until  int64  `json:"until"`
+	Reason string `json:"reason"`
+}
+
 // ReceiveTask 从 Agent 接收任务
 // 协议格式：4 字节长度（小端序） + protobuf 序列化的 Task
-// 自动拦截心跳 ping（DataType=9000）并回复 pong，对业务调用方透明
+// 自动拦截心跳 ping（DataType=9000）并回复 pong、拦截限流状态（DataType=9003），对业务调用方透明
 func (c *Client) ReceiveTask() (*bridge.Task, error) {
 	for {
 		c.rmu.Lock()
@@ -151,10 +177,70 @@ func (c *Client) ReceiveTask() (*bridge.Task, error) {
 			continue
 		}
 
+		// 拦截限流状态：更新本地状态，不返回给业务调用方
+		if task.DataType == DataTypeThrottle {
+			c.setThrottle(task.Data)
+			continue
+		}
+
 		return task, nil
 	}
 }
 
+// setThrottle 更新限流状态并唤醒等待中的 WaitThrottle
+func (c *Client) setThrottle(data string) {
+	var state throttleState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return
+	}
+
+	c.tmu.Lock()
+	defer c.tmu.Unlock()
+	if state.Until > 0 {
+		c.throttleUntil = time.Unix(state.Until, 0)
+	} else {
+		c.throttleUntil = time.Time{}
+	}
+	c.throttleReason = state.Reason
+	close(c.throttleCh)
+	c.throttleCh = make(chan struct{})
+}
+
+// Throttled 返回插件当前是否被 Agent 限流及限流原因
+func (c *Client) Throttled() (bool, string) {
+	c.tmu.Lock()
+	defer c.tmu.Unlock()
+	if time.Now().Before(c.throttleUntil) {
+		return true, c.throttleReason
+	}
+	return false, ""
+}
+
+// WaitThrottle 在限流期间阻塞，直到限流解除、到期或 ctx 取消；未限流时立即返回
+// 插件应在执行周期性工作（定时采集、文件完整性检查等）前调用
+func (c *Client) WaitThrottle(ctx context.Context) error {
+	for {
+		c.tmu.Lock()
+		wait := time.Until(c.throttleUntil)
+		changed := c.throttleCh
+		c.tmu.Unlock()
+
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
 // ReceiveTaskWithTimeout 从 Agent 接收任务，带超时机制
 func (c *Client) ReceiveTaskWithTimeout(timeout time.Duration) (*bridge.Task, error) {
 	type result struct {
@@ -14,6 +14,8 @@
 #   --arch=ARCH     架构: amd64, arm64, all (默认: amd64)
 #   --version=VER   版本号 (默认: 从 VERSION 文件读取)
 #   --server=HOST   Server 地址 (默认: localhost:6751)
+#   --discovery=URL 服务发现地址，即 Manager HTTP 地址 (默认: 不启用)
+#   --region=NAME   Agent 所在区域，用于筛选接入点 (默认: 空)
 
 set -e
 
@@ -30,6 +32,8 @@ cd "$PROJECT_ROOT"
 TARGET="${1:-all}"
 ARCH="${GOARCH:-amd64}"
 SERVER_HOST="${SERVER_HOST:-localhost:6751}"
+DISCOVERY_URL="${DISCOVERY_URL:-}"  # 服务发现地址（Manager HTTP 地址，可选）
+REGION="${REGION:-}"                # Agent 所在区域（可选）
 
 # 版本
 if [ -n "${VERSION:-}" ]; then
@@ -47,6 +51,8 @@ for arg in "$@"; do
         --arch=*) ARCH="${arg#*=}" ;;
         --version=*) VERSION="${arg#*=}" ;;
         --server=*) SERVER_HOST="${arg#*=}" ;;
+        --discovery=*) DISCOVERY_URL="${arg#*=}" ;;
+        --region=*) REGION="${arg#*=}" ;;
     esac
 done
 
@@ -97,7 +103,7 @@ package_agent() {
     # 编译
     local bin="$TMP_DIR/mxsec-agent-$arch"
     CGO_ENABLED=0 GOOS=linux GOARCH=$arch go build -ldflags \
-        "-X main.serverHost=$SERVER_HOST -X main.buildVersion=$VERSION -X main.buildTime=$BUILD_TIME -s -w" \
+        "-X main.serverHost=$SERVER_HOST -X main.discoveryURL=$DISCOVERY_URL -X main.region=$REGION -X main.buildVersion=$VERSION -X main.buildTime=$BUILD_TIME -s -w" \
         -o "$bin" ./cmd/agent
 
     # 准备打包目录
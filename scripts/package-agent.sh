@@ -13,6 +13,8 @@ NC='\033[0m'
 
 # 配置
 SERVER_HOST="${SERVER_HOST:-localhost:6751}"
+DISCOVERY_URL="${DISCOVERY_URL:-}"  # 服务发现地址（Manager HTTP 地址，可选）
+REGION="${REGION:-}"  # Agent 所在区域（可选）
 BUILD_TIME=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
 ARCH="${GOARCH:-amd64}"
 OS="linux"  # 始终构建 Linux 二进制
@@ -85,6 +87,8 @@ fi
 echo -e "${GREEN}[1/4] Building agent binary...${NC}"
 CGO_ENABLED=0 GOOS=$OS GOARCH=$ARCH go build -ldflags "\
     -X main.serverHost=$SERVER_HOST \
+    -X main.discoveryURL=$DISCOVERY_URL \
+    -X main.region=$REGION \
     -X main.buildVersion=$VERSION \
     -X main.buildTime=$BUILD_TIME \
     -s -w" \
@@ -0,0 +1,79 @@
+import apiClient from './client'
+
+export type ApprovalStatus = 'pending_approval' | 'approved' | 'rejected' | 'cancelled' | 'failed'
+
+// ApprovalRequest 高危操作审批单
+export interface ApprovalRequest {
+  id: number
+  action: string
+  resource_id: string
+  summary: string
+  reason: string
+  targets: string[] | null
+  business_lines: string[] | null
+  permission: string
+  status: ApprovalStatus
+  requested_by: string
+  reviewed_by: string
+  review_comment: string
+  reviewed_at: string | null
+  error_message?: string
+  created_at: string
+  updated_at: string
+}
+
+export interface ApprovalItem extends ApprovalRequest {
+  can_review: boolean // 当前用户能否审批（非申请人、拥有相应权限且业务线覆盖目标）
+}
+
+export interface ListApprovalsParams {
+  page?: number
+  page_size?: number
+  view?: 'todo' | 'mine' // 不传表示全部（我提交的和我可审批的）
+  status?: ApprovalStatus
+  action?: string
+}
+
+// 需要审批的操作显示名称
+export const approvalActionLabels: Record<string, string> = {
+  'fix.create': '基线修复任务',
+  'component.push_update': 'Agent 推送更新',
+  'host.delete': '删除主机',
+  'policy.delete': '删除策略',
+}
+
+// 审批状态显示名称和颜色
+export const approvalStatusMap: Record<ApprovalStatus, { text: string; color: string }> = {
+  pending_approval: { text: '待审批', color: 'orange' },
+  approved: { text: '已批准', color: 'success' },
+  rejected: { text: '已驳回', color: 'default' },
+  cancelled: { text: '已撤销', color: 'default' },
+  failed: { text: '执行失败', color: 'error' },
+}
+
+// isPendingApproval 操作是否命中审批策略，尚未执行（服务端返回 202）
+export const isPendingApproval = (data: unknown): boolean => {
+  return (data as { status?: string } | null | undefined)?.status === 'pending_approval'
+}
+
+export const approvalsApi = {
+  list: async (params?: ListApprovalsParams): Promise<{ total: number; items: ApprovalItem[] }> => {
+    return apiClient.get('/approvals', { params })
+  },
+
+  get: async (id: number): Promise<ApprovalItem> => {
+    return apiClient.get(`/approvals/${id}`)
+  },
+
+  approve: async (id: number, comment?: string): Promise<ApprovalRequest> => {
+    return apiClient.post(`/approvals/${id}/approve`, { comment: comment || '' })
+  },
+
+  reject: async (id: number, comment?: string): Promise<ApprovalRequest> => {
+    return apiClient.post(`/approvals/${id}/reject`, { comment: comment || '' })
+  },
+
+  cancel: async (id: number): Promise<ApprovalRequest> => {
+    return apiClient.post(`/approvals/${id}/cancel`)
+  },
+}
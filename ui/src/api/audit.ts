@@ -0,0 +1,116 @@
+import apiClient, { rawGet } from './client'
+
+// AuditChange 单个字段的变更前后值（敏感字段已脱敏）
+export interface AuditChange {
+  before: unknown
+  after: unknown
+}
+
+export interface AuditLog {
+  id: number
+  actor: string
+  actor_role: string
+  token_id?: number // 通过 API Token 发起的操作
+  source_ip: string
+  action: string
+  resource: string
+  target_ids: string[]
+  method: string
+  path: string
+  status_code: number
+  result: 'success' | 'failure'
+  message: string
+  changes: Record<string, AuditChange>
+  prev_hash?: string
+  hash?: string
+  created_at: string
+}
+
+export interface ListAuditLogsParams {
+  page?: number
+  page_size?: number
+  actor?: string
+  action?: string
+  resource?: string
+  target?: string
+  result?: string
+  source_ip?: string
+  token_id?: number
+  start_time?: string
+  end_time?: string
+}
+
+export interface ListAuditLogsResponse {
+  total: number
+  items: AuditLog[]
+}
+
+// AuditVerifyResult 哈希链校验结果
+export interface AuditVerifyResult {
+  valid: boolean
+  checked: number
+  unchained: number
+  broken_id?: number
+  reason?: string
+}
+
+// 审计资源类型显示名称
+export const auditResourceLabels: Record<string, string> = {
+  auth: '认证',
+  host: '主机',
+  policy_group: '策略组',
+  policy: '策略',
+  rule: '规则',
+  task: '基线任务',
+  fix: '基线修复',
+  user: '用户',
+  role: '角色',
+  business_line: '业务线',
+  system: '系统配置',
+  notification: '通知',
+  alert: '告警',
+  component: '组件',
+  fim_policy: 'FIM 策略',
+  fim_task: 'FIM 任务',
+  agentcenter_endpoint: 'AgentCenter 接入点',
+  audit: '审计日志',
+  token: 'API Token',
+  approval: '审批',
+  maintenance: '维护窗口',
+}
+
+export const auditApi = {
+  list: async (params?: ListAuditLogsParams): Promise<ListAuditLogsResponse> => {
+    return apiClient.get('/audit-logs', { params })
+  },
+
+  verify: async (): Promise<AuditVerifyResult> => {
+    return apiClient.get('/audit-logs/verify')
+  },
+
+  // 按查询条件导出 CSV
+  export: async (params?: ListAuditLogsParams) => {
+    const response = await rawGet('/api/v1/audit-logs/export', {
+      params,
+      responseType: 'blob',
+    })
+
+    const contentDisposition = response.headers['content-disposition']
+    let filename = 'audit_logs.csv'
+    if (contentDisposition) {
+      const matches = /filename="?([^"]+)"?/.exec(contentDisposition)
+      if (matches && matches[1]) {
+        filename = matches[1]
+      }
+    }
+
+    const url = window.URL.createObjectURL(new Blob([response.data]))
+    const link = document.createElement('a')
+    link.href = url
+    link.setAttribute('download', filename)
+    document.body.appendChild(link)
+    link.click()
+    link.remove()
+    window.URL.revokeObjectURL(url)
+  },
+}
@@ -3,16 +3,45 @@ import apiClient from './client'
 export interface LoginRequest {
   username: string
   password: string
+  provider?: 'local' | 'ldap'
+  totp_code?: string // 已启用两步验证的账号需提交动态验证码
+}
+
+// AuthProviders 登录页可用的认证方式
+export interface AuthProviders {
+  local_login_enabled: boolean
+  ldap_enabled: boolean
+  oidc_enabled: boolean
+  oidc_display_name: string
 }
 
 export interface LoginResponse {
-  token: string
+  token: string // 访问令牌，有效期较短，过期后由 API 客户端用刷新令牌续期
+  refresh_token: string
+  expires_in: number
   user: {
     username: string
     role: string
   }
 }
 
+// CurrentUser 当前用户及其有效权限（如 hosts:read、fix:execute）和业务线范围（为空表示不限制）
+export interface CurrentUser {
+  username: string
+  role: string
+  permissions: string[]
+  business_lines?: string[] | null
+  source?: string
+  totp_enabled?: boolean
+  totp_available?: boolean // 本地账号且角色允许绑定两步验证
+}
+
+// TOTPSetup 两步验证密钥，仅在绑定时返回
+export interface TOTPSetup {
+  secret: string
+  uri: string
+}
+
 export interface ChangePasswordRequest {
   old_password: string
   new_password: string
@@ -23,15 +52,36 @@ export const authApi = {
     return apiClient.post('/auth/login', data)
   },
 
-  logout: async (): Promise<void> => {
-    return apiClient.post('/auth/logout')
+  // 同时提交刷新令牌，访问令牌已过期时服务端也能吊销会话
+  logout: async (refreshToken?: string | null): Promise<void> => {
+    return apiClient.post('/auth/logout', { refresh_token: refreshToken || '' })
+  },
+
+  getProviders: async (): Promise<AuthProviders> => {
+    return apiClient.get('/auth/providers')
   },
 
-  getCurrentUser: async (): Promise<{ username: string; role: string }> => {
+  // OIDC 登录由浏览器整页跳转完成，回调后 Token 通过登录页 URL 片段返回
+  oidcLoginUrl: '/api/v1/auth/oidc/login',
+
+  getCurrentUser: async (): Promise<CurrentUser> => {
     return apiClient.get('/auth/me')
   },
 
   changePassword: async (data: ChangePasswordRequest): Promise<void> => {
     return apiClient.post('/auth/change-password', data)
   },
+
+  // 两步验证：生成密钥后提交验证码启用
+  setupTOTP: async (): Promise<TOTPSetup> => {
+    return apiClient.post('/auth/totp/setup')
+  },
+
+  enableTOTP: async (code: string): Promise<void> => {
+    return apiClient.post('/auth/totp/enable', { code })
+  },
+
+  disableTOTP: async (password: string, code: string): Promise<void> => {
+    return apiClient.post('/auth/totp/disable', { password, code })
+  },
 }
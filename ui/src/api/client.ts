@@ -3,7 +3,7 @@
  *
  * 提供统一的 HTTP 请求客户端，包含：
  * - 请求拦截器：自动添加认证 Token
- * - 响应拦截器：统一处理错误和业务响应
+ * - 响应拦截器：统一处理错误和业务响应，访问令牌过期时用刷新令牌续期并重试，提示需要审批的操作
  * - 全局错误提示：使用 Ant Design Vue message 显示错误信息
  */
 
@@ -11,6 +11,10 @@ import axios, { AxiosRequestConfig } from 'axios'
 import { message } from 'ant-design-vue'
 import type { ApiResponse } from './types'
 
+export const TOKEN_KEY = 'mxcsec_token'
+export const REFRESH_TOKEN_KEY = 'mxcsec_refresh_token'
+export const USER_KEY = 'mxcsec_user'
+
 /**
  * 创建 axios 实例
  *
@@ -37,7 +41,7 @@ const axiosInstance = axios.create({
 axiosInstance.interceptors.request.use(
   (config) => {
     // 添加 token 认证信息
-    const token = localStorage.getItem('mxcsec_token')
+    const token = localStorage.getItem(TOKEN_KEY)
     if (token) {
       config.headers.Authorization = `Bearer ${token}`
     }
@@ -48,13 +52,47 @@ axiosInstance.interceptors.request.use(
   }
 )
 
+/**
+ * 刷新访问令牌
+ *
+ * 同一时间只发起一次刷新，并发的 401 请求等待同一个结果。
+ * 刷新令牌每次使用后轮换，刷新失败时如果其他标签页已经轮换过，则直接使用其保存的新令牌。
+ */
+let refreshing: Promise<string | null> | null = null
+
+export const refreshAccessToken = (): Promise<string | null> => {
+  if (!refreshing) {
+    const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY)
+    refreshing = (async () => {
+      if (!refreshToken) return null
+      try {
+        const { data } = await axios.post('/api/v1/auth/refresh', { refresh_token: refreshToken })
+        if (data.code !== 0) return null
+        localStorage.setItem(TOKEN_KEY, data.data.token)
+        localStorage.setItem(REFRESH_TOKEN_KEY, data.data.refresh_token)
+        return data.data.token as string
+      } catch {
+        const latest = localStorage.getItem(REFRESH_TOKEN_KEY)
+        return latest && latest !== refreshToken ? localStorage.getItem(TOKEN_KEY) : null
+      }
+    })().finally(() => {
+      refreshing = null
+    })
+  }
+  return refreshing
+}
+
+// 不参与自动刷新的认证接口：登录失败的 401 由登录页处理，刷新和登出失败无需重试
+const isAuthRequest = (url?: string) =>
+  !!url && ['/auth/login', '/auth/refresh', '/auth/logout'].some((path) => url.startsWith(path))
+
 /**
  * 响应拦截器
  *
  * 功能：
  * - 统一处理业务响应格式（code, message, data）
  * - 自动显示错误提示（使用 Ant Design Vue message）
- * - 处理认证失败（401）自动跳转登录
+ * - 处理认证失败（401）：先用刷新令牌续期并重试，续期失败再跳转登录
  * - 处理网络错误和 HTTP 错误
  */
 axiosInstance.interceptors.response.use(
@@ -72,18 +110,37 @@ axiosInstance.interceptors.response.use(
 
       return Promise.reject(new Error(errorMessage))
     }
+    // 202：高危操作已提交审批，尚未执行（data.status 为 pending_approval）
+    if (response.status === 202 && res.message) {
+      message.info(res.message, 5)
+    }
     return res.data
   },
-  (error) => {
+  async (error) => {
     // 处理 HTTP 错误
-    if (error.response?.status === 401) {
+    const config = error.config as (AxiosRequestConfig & { _retried?: boolean }) | undefined
+    if (error.response?.status === 401 && !isAuthRequest(config?.url)) {
+      // 访问令牌过期，刷新后重试一次
+      if (config && !config._retried) {
+        config._retried = true
+        const token = await refreshAccessToken()
+        if (token) {
+          config.headers = { ...config.headers, Authorization: `Bearer ${token}` }
+          return axiosInstance(config)
+        }
+      }
       // 未授权，清除认证信息并跳转到登录页
-      localStorage.removeItem('mxcsec_token')
-      localStorage.removeItem('mxcsec_user')
+      localStorage.removeItem(TOKEN_KEY)
+      localStorage.removeItem(REFRESH_TOKEN_KEY)
+      localStorage.removeItem(USER_KEY)
       message.warning('登录已过期，请重新登录')
       window.location.href = '/login'
       return Promise.reject(error)
     }
+    if (error.response?.status === 401) {
+      // 登录失败由登录页显示原因
+      return Promise.reject(error)
+    }
 
     // 处理网络错误
     if (!error.response) {
@@ -140,4 +197,25 @@ const apiClient = {
   },
 }
 
+/**
+ * 获取原始响应（用于文件下载等不使用统一响应格式的接口）
+ *
+ * 访问令牌过期时刷新后重试一次
+ */
+export const rawGet = async (url: string, config: AxiosRequestConfig = {}) => {
+  const request = (token: string | null) =>
+    axios.get(url, {
+      ...config,
+      headers: { ...config.headers, Authorization: token ? `Bearer ${token}` : '' },
+    })
+  try {
+    return await request(localStorage.getItem(TOKEN_KEY))
+  } catch (error: any) {
+    if (error.response?.status !== 401) throw error
+    const token = await refreshAccessToken()
+    if (!token) throw error
+    return request(token)
+  }
+}
+
 export default apiClient
@@ -220,6 +220,14 @@ export const componentsApi = {
     return await apiClient.get(`/components/push-records/${id}`)
   },
 
+  /**
+   * 紧急放行推送记录
+   * 等待维护窗口的主机不再等待，下一个调度周期立即推送
+   */
+  emergencyOverridePushRecord: async (id: number, reason: string): Promise<void> => {
+    return await apiClient.post(`/components/push-records/${id}/emergency`, { reason })
+  },
+
   /**
    * 手动广播插件配置
    * 触发立即广播插件配置到所有在线 Agent
@@ -227,6 +235,60 @@ export const componentsApi = {
   broadcastPluginConfigs: async (): Promise<BroadcastPluginConfigsResponse> => {
     return await apiClient.post('/components/plugins/broadcast')
   },
+
+  /**
+   * 获取等待维护窗口的插件配置下发主机
+   */
+  listPluginConfigDeferrals: async (): Promise<PaginatedResponse<PluginConfigDeferral>> => {
+    return await apiClient.get('/components/plugins/deferrals')
+  },
+
+  /**
+   * 紧急放行等待维护窗口的插件配置，hostIds 为空时放行所有等待的主机
+   */
+  emergencyOverridePluginDeferrals: async (reason: string, hostIds?: string[]): Promise<{ host_count: number }> => {
+    return await apiClient.post('/components/plugins/deferrals/emergency', { reason, host_ids: hostIds })
+  },
+
+  /**
+   * 获取插件资源限制与加固选项
+   */
+  getPluginLimits: async (name: string): Promise<PluginLimitsConfig> => {
+    return await apiClient.get(`/components/plugins/${name}/limits`)
+  },
+
+  /**
+   * 更新插件资源限制与加固选项
+   * 资源限制无需重启插件即可生效，加固选项变化时 Agent 会重启插件
+   */
+  updatePluginLimits: async (
+    name: string,
+    data: { limits: PluginLimits; sandbox: PluginSandbox }
+  ): Promise<void> => {
+    return await apiClient.put(`/components/plugins/${name}/limits`, data)
+  },
+}
+
+// 插件资源限制（0 表示不限制）
+export interface PluginLimits {
+  cpu_percent: number
+  memory_max_mb: number
+  io_weight: number
+  pids_max: number
+}
+
+// 插件进程加固选项
+export interface PluginSandbox {
+  no_new_privs: boolean
+  drop_capabilities: string[]
+  minimal_env: boolean
+}
+
+export interface PluginLimitsConfig {
+  name: string
+  version: string
+  limits: PluginLimits
+  sandbox: PluginSandbox
 }
 
 // 推送记录类型
@@ -247,16 +309,31 @@ export interface ComponentPushRecord {
   created_at: string
   updated_at: string
   completed_at?: string
+  emergency_reason?: string // 紧急放行原因（不受维护窗口和变更冻结限制）
+  emergency_by?: string
+  emergency_at?: string
   push_hosts?: ComponentPushHost[]
 }
 
+// 等待维护窗口的插件配置下发主机
+export interface PluginConfigDeferral {
+  host_id: string
+  hostname: string
+  reason: string
+  created_at: string
+  updated_at: string
+  emergency_reason?: string // 已紧急放行时为放行原因，下一个调度周期下发
+  emergency_by?: string
+  emergency_at?: string
+}
+
 // 主机推送详情类型
 export interface ComponentPushHost {
   id: number
   record_id: number
   host_id: string
   hostname: string
-  status: 'pending' | 'success' | 'failed'
+  status: 'pending' | 'waiting_window' | 'success' | 'failed'
   message: string
   pushed_at?: string
   created_at: string
@@ -25,8 +25,9 @@ export const fixApi = {
     // 方式3：使用筛选条件（用于全选所有筛选结果）
     use_filters?: boolean
     business_line?: string
-  }): Promise<{ task_id: string }> {
-    const response = await apiClient.post<{ task_id: string }>('/fix-tasks', data)
+  }): Promise<{ task_id: string; approval_id?: number; status?: string }> {
+    // 命中审批策略时返回 202，status 为 pending_approval，批准后才会下发
+    const response = await apiClient.post<{ task_id: string; approval_id?: number; status?: string }>('/fix-tasks', data)
     return response
   },
 
@@ -61,6 +62,11 @@ export const fixApi = {
     await apiClient.post(`/fix-tasks/${taskId}/cancel`)
   },
 
+  // 紧急放行修复任务（不再等待维护窗口和变更冻结）
+  async emergencyOverride(taskId: string, reason: string): Promise<void> {
+    await apiClient.post(`/fix-tasks/${taskId}/emergency`, { reason })
+  },
+
   // 删除修复任务
   async deleteFixTask(taskId: string): Promise<void> {
     await apiClient.delete(`/fix-tasks/${taskId}`)
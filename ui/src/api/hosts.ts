@@ -1,5 +1,4 @@
-import apiClient from './client'
-import axios from 'axios'
+import apiClient, { rawGet } from './client'
 import type { Host, HostDetail, PaginatedResponse, BaselineScore, BaselineSummary, HostMetrics } from './types'
 
 export type { Host } from './types'
@@ -52,6 +51,31 @@ export interface HostPlugin {
   updated_at?: string
   latest_version: string
   need_update: boolean
+  limit_hits?: PluginLimitHits
+}
+
+// 插件触发资源限制的累计次数（插件重启后清零）
+export interface PluginLimitHits {
+  memory_max: number
+  oom_kill: number
+  cpu_throttled: number
+  cpu_throttled_usec: number
+  pids_max: number
+}
+
+export interface AgentDiagnostics {
+  id: number
+  host_id: string
+  hostname: string
+  status: 'pending' | 'collecting' | 'completed' | 'failed'
+  message: string
+  file_size: number
+  sha256: string
+  created_by: string
+  pushed_at?: string
+  completed_at?: string
+  created_at: string
+  updated_at: string
 }
 
 export const hostsApi = {
@@ -137,15 +161,46 @@ export const hostsApi = {
     return apiClient.get('/hosts/restart-records')
   },
 
+  // 触发 Agent 诊断信息收集
+  collectDiagnostics: (hostId: string) => {
+    return apiClient.post<AgentDiagnostics>(`/hosts/${hostId}/diagnostics`)
+  },
+
+  // 获取主机诊断信息收集记录
+  listDiagnostics: (hostId: string) => {
+    return apiClient.get<AgentDiagnostics[]>(`/hosts/${hostId}/diagnostics`)
+  },
+
+  // 下载诊断包
+  downloadDiagnostics: async (id: number) => {
+    const response = await rawGet(`/api/v1/diagnostics/${id}/download`, {
+      responseType: 'blob',
+    })
+
+    const contentDisposition = response.headers['content-disposition']
+    let filename = `diagnostics_${id}.tar.gz`
+    if (contentDisposition) {
+      const matches = /filename="?([^"]+)"?/.exec(contentDisposition)
+      if (matches && matches[1]) {
+        filename = matches[1]
+      }
+    }
+
+    const url = window.URL.createObjectURL(new Blob([response.data]))
+    const link = document.createElement('a')
+    link.href = url
+    link.setAttribute('download', filename)
+    document.body.appendChild(link)
+    link.click()
+    link.remove()
+    window.URL.revokeObjectURL(url)
+  },
+
   // 导出主机基线检查结果
   exportBaselineResults: async (hostId: string, format: 'markdown' | 'excel') => {
-    const token = localStorage.getItem('mxcsec_token')
-    const response = await axios.get(`/api/v1/results/host/${hostId}/export`, {
+    const response = await rawGet(`/api/v1/results/host/${hostId}/export`, {
       params: { format },
       responseType: 'blob',
-      headers: {
-        Authorization: token ? `Bearer ${token}` : '',
-      },
     })
 
     // 从响应头获取文件名
@@ -0,0 +1,96 @@
+import apiClient from './client'
+
+export type MaintenanceScope = 'global' | 'business_line' | 'host_tags' | 'hosts'
+
+// MaintenanceWindow 维护窗口：修复任务和 Agent/插件更新只在主机所属的窗口开启期间下发
+export interface MaintenanceWindow {
+  id: number
+  name: string
+  description: string
+  cron: string // 窗口开始时间（5 段 cron：分 时 日 月 周）
+  duration_minutes: number
+  timezone: string
+  scope: MaintenanceScope
+  scope_values: string[] | null
+  enabled: boolean
+  created_by: string
+  created_at: string
+  updated_at: string
+  open: boolean // 当前是否开启
+  ends_at?: string // 开启时：本次窗口结束时间
+  next_start?: string // 未开启时：下次开启时间
+}
+
+export interface MaintenanceWindowRequest {
+  name: string
+  description?: string
+  cron: string
+  duration_minutes: number
+  timezone: string
+  scope: MaintenanceScope
+  scope_values: string[]
+  enabled: boolean
+}
+
+// ChangeFreeze 全局变更冻结
+export interface ChangeFreeze {
+  id: number
+  name: string
+  reason: string
+  start_at: string
+  end_at: string
+  enabled: boolean
+  created_by: string
+  created_at: string
+  updated_at: string
+  active: boolean // 当前是否生效
+}
+
+export interface ChangeFreezeRequest {
+  name: string
+  reason?: string
+  start_at: string
+  end_at: string
+  enabled: boolean
+}
+
+export const maintenanceScopeLabels: Record<MaintenanceScope, string> = {
+  global: '所有主机',
+  business_line: '业务线',
+  host_tags: '主机标签',
+  hosts: '指定主机',
+}
+
+export const maintenanceApi = {
+  listWindows: async (): Promise<{ total: number; items: MaintenanceWindow[] }> => {
+    return apiClient.get('/maintenance-windows')
+  },
+
+  createWindow: async (data: MaintenanceWindowRequest): Promise<MaintenanceWindow> => {
+    return apiClient.post('/maintenance-windows', data)
+  },
+
+  updateWindow: async (id: number, data: MaintenanceWindowRequest): Promise<MaintenanceWindow> => {
+    return apiClient.put(`/maintenance-windows/${id}`, data)
+  },
+
+  deleteWindow: async (id: number): Promise<void> => {
+    return apiClient.delete(`/maintenance-windows/${id}`)
+  },
+
+  listFreezes: async (): Promise<{ total: number; items: ChangeFreeze[] }> => {
+    return apiClient.get('/change-freezes')
+  },
+
+  createFreeze: async (data: ChangeFreezeRequest): Promise<ChangeFreeze> => {
+    return apiClient.post('/change-freezes', data)
+  },
+
+  updateFreeze: async (id: number, data: ChangeFreezeRequest): Promise<ChangeFreeze> => {
+    return apiClient.put(`/change-freezes/${id}`, data)
+  },
+
+  deleteFreeze: async (id: number): Promise<void> => {
+    return apiClient.delete(`/change-freezes/${id}`)
+  },
+}
@@ -2,7 +2,7 @@ import apiClient from './client'
 import type { PaginatedResponse } from './types'
 
 // 通知类别
-export type NotifyCategory = 'baseline_alert' | 'agent_offline'
+export type NotifyCategory = 'baseline_alert' | 'agent_offline' | 'approval'
 
 export interface Notification {
   id: number
@@ -20,4 +20,30 @@ export const resultsApi = {
   get: (resultId: string) => {
     return apiClient.get<ScanResult>(`/results/${resultId}`)
   },
+
+  // 生成离线主机签名密钥（hostId 为离线主机 mxsec-agent --print-id 的输出）
+  issueOfflineKey: (hostId: string) => {
+    return apiClient.post<{ host_id: string; key: string }>('/results/offline-keys', {
+      host_id: hostId,
+    })
+  },
+
+  // 导入离线检查结果包（mxsec-agent --scan 生成）
+  importOffline: (file: File) => {
+    const formData = new FormData()
+    formData.append('file', file)
+    return apiClient.post<{
+      task_id: string
+      host_id: string
+      hostname: string
+      result_count: number
+      alerts_created: number
+      alerts_resolved: number
+      checked_at: string
+    }>('/results/offline-import', formData, {
+      headers: {
+        'Content-Type': 'multipart/form-data',
+      },
+    })
+  },
 }
@@ -18,6 +18,90 @@ export interface AlertConfig {
   enable_periodic_summary: boolean // 是否启用定期汇总
 }
 
+// GroupMapping 目录组到角色和业务线的映射（按顺序匹配，业务线为空表示不限制）
+export interface GroupMapping {
+  group: string
+  role: string
+  business_lines: string[]
+}
+
+export interface OIDCConfig {
+  enabled: boolean
+  display_name: string
+  issuer: string
+  client_id: string
+  client_secret: string // 已设置时返回 ******，提交 ****** 或空值表示保持不变
+  redirect_url: string
+  scopes: string[]
+  username_claim: string
+  email_claim: string
+  groups_claim: string
+}
+
+export interface LDAPConfig {
+  enabled: boolean
+  url: string
+  start_tls: boolean
+  insecure_skip_verify: boolean
+  bind_dn: string
+  bind_password: string // 已设置时返回 ******，提交 ****** 或空值表示保持不变
+  base_dn: string
+  user_filter: string
+  username_attr: string
+  email_attr: string
+  group_attr: string
+  group_base_dn: string
+  group_filter: string
+  group_name_attr: string
+}
+
+export interface SessionConfig {
+  access_token_minutes: number
+  refresh_token_hours: number
+}
+
+// LockoutConfig 登录失败锁定，次数为 0 表示不限制
+export interface LockoutConfig {
+  max_user_failures: number
+  max_ip_failures: number
+  lock_minutes: number
+}
+
+export interface PasswordPolicy {
+  min_length: number
+  min_classes: number
+  history_count: number
+}
+
+// AuthConfig 登录认证配置
+export interface AuthConfig {
+  local_login_enabled: boolean
+  break_glass_users: string[]
+  default_role: string
+  oidc: OIDCConfig
+  ldap: LDAPConfig
+  group_mappings: GroupMapping[]
+  session: SessionConfig
+  lockout: LockoutConfig
+  password_policy: PasswordPolicy
+  totp_roles: string[]
+}
+
+// FixTaskApprovalRule 修复任务审批规则：目标主机数超过阈值或包含指定严重级别的规则时需要审批
+export interface FixTaskApprovalRule {
+  enabled: boolean
+  host_threshold: number // 0 表示所有修复任务都需要审批
+  severities: string[]
+}
+
+// ApprovalConfig 高危操作审批策略
+export interface ApprovalConfig {
+  fix_task: FixTaskApprovalRule
+  agent_push: boolean
+  host_delete: boolean
+  policy_delete: boolean
+}
+
 export const systemConfigApi = {
   // 获取 Kubernetes 镜像配置
   getKubernetesImageConfig: async (): Promise<KubernetesImageConfig> => {
@@ -68,4 +152,24 @@ export const systemConfigApi = {
   updateAlertConfig: async (data: AlertConfig): Promise<AlertConfig> => {
     return apiClient.put<AlertConfig>('/system-config/alert', data)
   },
+
+  // 获取登录认证配置
+  getAuthConfig: async (): Promise<AuthConfig> => {
+    return apiClient.get<AuthConfig>('/system-config/auth')
+  },
+
+  // 更新登录认证配置
+  updateAuthConfig: async (data: AuthConfig): Promise<AuthConfig> => {
+    return apiClient.put<AuthConfig>('/system-config/auth', data)
+  },
+
+  // 获取高危操作审批策略
+  getApprovalConfig: async (): Promise<ApprovalConfig> => {
+    return apiClient.get<ApprovalConfig>('/system-config/approval')
+  },
+
+  // 更新高危操作审批策略
+  updateApprovalConfig: async (data: ApprovalConfig): Promise<ApprovalConfig> => {
+    return apiClient.put<ApprovalConfig>('/system-config/approval', data)
+  },
 }
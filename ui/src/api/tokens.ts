@@ -0,0 +1,69 @@
+import apiClient from './client'
+
+// APIToken API Token（个人访问令牌或服务账号令牌），明文仅在创建时返回一次
+export interface APIToken {
+  id: number
+  name: string
+  owner: string
+  prefix: string
+  permissions: string[] // 为空表示继承所属用户的全部权限
+  expires_at: string | null
+  last_used_at: string | null
+  last_used_ip: string
+  revoked_at: string | null
+  created_by: string
+  created_at: string
+}
+
+export interface CreateTokenRequest {
+  name: string
+  permissions?: string[]
+  expires_in_days?: number // 为 0 或不传表示永不过期
+}
+
+export interface CreateTokenResponse extends APIToken {
+  token: string
+}
+
+export interface ListTokensParams {
+  page?: number
+  page_size?: number
+  owner?: string
+  status?: 'active' | 'revoked' | 'expired'
+}
+
+// Token 状态
+export const tokenStatus = (token: APIToken): 'active' | 'revoked' | 'expired' => {
+  if (token.revoked_at) return 'revoked'
+  if (token.expires_at && new Date(token.expires_at.replace(' ', 'T')) <= new Date()) return 'expired'
+  return 'active'
+}
+
+export const tokensApi = {
+  // 当前用户的个人访问令牌
+  listMine: async (): Promise<{ total: number; items: APIToken[] }> => {
+    return apiClient.get('/auth/tokens')
+  },
+
+  createMine: async (data: CreateTokenRequest): Promise<CreateTokenResponse> => {
+    return apiClient.post('/auth/tokens', data)
+  },
+
+  revokeMine: async (id: number): Promise<void> => {
+    return apiClient.delete(`/auth/tokens/${id}`)
+  },
+
+  // 管理员：查询和吊销所有 Token
+  list: async (params?: ListTokensParams): Promise<{ total: number; items: APIToken[] }> => {
+    return apiClient.get('/api-tokens', { params })
+  },
+
+  revoke: async (id: number): Promise<void> => {
+    return apiClient.delete(`/api-tokens/${id}`)
+  },
+
+  // 管理员：为服务账号签发 Token
+  createForServiceAccount: async (userId: number, data: CreateTokenRequest): Promise<CreateTokenResponse> => {
+    return apiClient.post(`/users/${userId}/tokens`, data)
+  },
+}
@@ -91,6 +91,25 @@ export interface HostDetail extends Host {
   tags?: string[]
   disk_info?: string // JSON 字符串，解析后为 DiskInfo[]
   network_interfaces?: string // JSON 字符串，解析后为 NetworkInterfaceInfo[]
+  guard_state?: GuardState // Agent 资源保护状态
+  guard_status?: string // JSON 字符串，解析后为 GuardStatus
+}
+
+// Agent 资源保护状态：正常 / 限流（插件暂停周期性工作）/ 熔断（插件全部停止）
+export type GuardState = 'normal' | 'throttled' | 'suspended'
+
+// Agent 资源保护详情（用于 HostDetail 的 guard_status 字段）
+export interface GuardStatus {
+  state: GuardState
+  reason?: string // agent_cpu / agent_memory / host_cpu / host_memory
+  since: number // 进入当前状态的时间（Unix 秒）
+  until?: number // 限流截止时间（Unix 秒）
+  usage: {
+    agent_cpu: number // Agent 及插件 CPU（单核百分比）
+    agent_mem_mb: number // Agent 及插件常驻内存（MB）
+    host_cpu: number
+    host_mem: number
+  }
 }
 
 // 策略组相关类型
@@ -441,7 +460,7 @@ export interface FixTask {
   host_ids: string[]
   rule_ids: string[]
   severities?: string[]
-  status: 'pending' | 'running' | 'completed' | 'failed'
+  status: 'pending_approval' | 'pending' | 'running' | 'completed' | 'failed' | 'rejected'
   total_count: number
   success_count: number
   failed_count: number
@@ -449,6 +468,22 @@ export interface FixTask {
   created_by: string
   created_at: string
   completed_at?: string
+  emergency_reason?: string // 紧急放行原因（不受维护窗口和变更冻结限制）
+  emergency_by?: string
+  emergency_at?: string
+  rule_snapshot?: FixRuleSnapshot[] // 创建时的规则快照，下发时使用快照中的修复命令
+}
+
+export interface FixRuleSnapshot {
+  rule_id: string
+  policy_id: string
+  title: string
+  severity: string
+  fix: {
+    suggestion: string
+    command?: string
+    restart_services?: string[]
+  }
 }
 
 export interface FixResult {
@@ -476,7 +511,8 @@ export interface FixTaskHostStatus {
   os_family: string
   os_version: string
   runtime_type: string
-  status: 'dispatched' | 'completed' | 'timeout' | 'failed'
+  status: 'waiting_window' | 'dispatched' | 'completed' | 'timeout' | 'failed'
+  waiting_reason?: string // 等待维护窗口的原因
   dispatched_at: string
   completed_at?: string
   error_message?: string
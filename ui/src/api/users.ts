@@ -4,19 +4,58 @@ export interface User {
   id: number
   username: string
   email: string
-  role: 'admin' | 'user'
+  role: string
   status: 'active' | 'inactive'
+  business_lines: string[] | null
+  source?: 'local' | 'oidc' | 'ldap' | 'service' // SSO 用户的角色和业务线在每次登录时按目录组刷新；服务账号只能通过 API Token 访问
+  totp_enabled?: boolean
   last_login?: string
   created_at: string
   updated_at: string
 }
 
+// Role 角色（内置角色 id 为 0，不可修改和删除）
+export interface Role {
+  id: number
+  name: string
+  description: string
+  permissions: string[]
+  business_lines: string[] | null
+  builtin: boolean
+  user_count: number
+}
+
+// PermissionInfo 权限定义
+export interface PermissionInfo {
+  permission: string
+  group: string
+  description: string
+}
+
+export interface RoleRequest {
+  name?: string
+  description: string
+  permissions: string[]
+  business_lines: string[]
+}
+
+// 内置角色显示名称
+export const builtinRoleLabels: Record<string, string> = {
+  admin: '管理员',
+  operator: '运维',
+  auditor: '审计',
+  viewer: '访客',
+}
+
+export const roleLabel = (role: string) => builtinRoleLabels[role] || role
+
 export interface ListUsersParams {
   page?: number
   page_size?: number
   username?: string
   role?: string
   status?: string
+  source?: string
 }
 
 export interface ListUsersResponse {
@@ -26,17 +65,20 @@ export interface ListUsersResponse {
 
 export interface CreateUserRequest {
   username: string
-  password: string
+  password?: string // 服务账号不设置密码
   email?: string
-  role: 'admin' | 'user'
+  role: string
   status?: 'active' | 'inactive'
+  business_lines?: string[]
+  service_account?: boolean
 }
 
 export interface UpdateUserRequest {
   password?: string
   email?: string
-  role?: 'admin' | 'user'
+  role?: string
   status?: 'active' | 'inactive'
+  business_lines?: string[]
 }
 
 export const usersApi = {
@@ -59,4 +101,31 @@ export const usersApi = {
   delete: async (id: number): Promise<void> => {
     return apiClient.delete(`/users/${id}`)
   },
+
+  // 重置两步验证（用户丢失认证器时使用），同时吊销其全部登录会话
+  resetTOTP: async (id: number): Promise<void> => {
+    return apiClient.delete(`/users/${id}/totp`)
+  },
+}
+
+export const rolesApi = {
+  list: async (): Promise<{ total: number; items: Role[] }> => {
+    return apiClient.get('/roles')
+  },
+
+  permissions: async (): Promise<PermissionInfo[]> => {
+    return apiClient.get('/permissions')
+  },
+
+  create: async (data: RoleRequest): Promise<Role> => {
+    return apiClient.post('/roles', data)
+  },
+
+  update: async (id: number, data: RoleRequest): Promise<Role> => {
+    return apiClient.put(`/roles/${id}`, data)
+  },
+
+  delete: async (id: number): Promise<void> => {
+    return apiClient.delete(`/roles/${id}`)
+  },
 }
@@ -1,6 +1,7 @@
 import { createRouter, createWebHistory } from 'vue-router'
 import type { RouteRecordRaw } from 'vue-router'
 import Layout from '@/layouts/BasicLayout.vue'
+import { message } from 'ant-design-vue'
 import { useAuthStore } from '@/stores/auth'
 
 const routes: RouteRecordRaw[] = [
@@ -20,158 +21,200 @@ const routes: RouteRecordRaw[] = [
         path: 'dashboard',
         name: 'Dashboard',
         component: () => import('@/views/Dashboard/index.vue'),
-        meta: { title: '安全概览' },
+        meta: { title: '安全概览', permission: 'reports:read' },
       },
       {
         path: 'hosts',
         name: 'Hosts',
         component: () => import('@/views/Hosts/index.vue'),
-        meta: { title: '主机列表' },
+        meta: { title: '主机列表', permission: 'hosts:read' },
       },
       {
         path: 'hosts/:hostId',
         name: 'HostDetail',
         component: () => import('@/views/Hosts/Detail.vue'),
-        meta: { title: '主机详情' },
+        meta: { title: '主机详情', permission: 'hosts:read' },
       },
       {
         path: 'business-lines',
         name: 'BusinessLines',
         component: () => import('@/views/BusinessLines/index.vue'),
-        meta: { title: '业务线管理' },
+        meta: { title: '业务线管理', permission: 'business_lines:read' },
       },
       {
         path: 'policies',
         name: 'Policies',
         component: () => import('@/views/Policies/index.vue'),
-        meta: { title: '基线检查' },
+        meta: { title: '基线检查', permission: 'policies:read' },
       },
       {
         path: 'policies/:policyId',
         name: 'PolicyDetail',
         component: () => import('@/views/Policies/Detail.vue'),
-        meta: { title: '基线检查详情' },
+        meta: { title: '基线检查详情', permission: 'policies:read' },
       },
       {
         path: 'policy-groups',
         name: 'PolicyGroups',
         component: () => import('@/views/PolicyGroups/index.vue'),
-        meta: { title: '策略组管理' },
+        meta: { title: '策略组管理', permission: 'policies:read' },
       },
       {
         path: 'policy-groups/policies/:policyId/rules',
         name: 'PolicyRules',
         component: () => import('@/views/PolicyGroups/PolicyRules.vue'),
-        meta: { title: '规则管理' },
+        meta: { title: '规则管理', permission: 'policies:read' },
       },
       {
         path: 'tasks',
         name: 'Tasks',
         component: () => import('@/views/Tasks/index.vue'),
-        meta: { title: '任务执行' },
+        meta: { title: '任务执行', permission: 'tasks:read' },
       },
       {
         path: 'baseline/fix',
         name: 'BaselineFix',
         component: () => import('@/views/Baseline/Fix.vue'),
-        meta: { title: '基线修复' },
+        meta: { title: '基线修复', permission: 'fix:read' },
       },
       {
         path: 'baseline/fix-history',
         name: 'BaselineFixHistory',
         component: () => import('@/views/Baseline/FixHistory.vue'),
-        meta: { title: '修复历史' },
+        meta: { title: '修复历史', permission: 'fix:read' },
       },
       {
         path: 'system/collection',
         name: 'SystemCollection',
         component: () => import('@/views/System/Collection.vue'),
-        meta: { title: '平台授权' },
+        meta: { title: '平台授权', permission: 'system:read' },
       },
       {
         path: 'system/components',
         name: 'SystemComponents',
         component: () => import('@/views/System/Components.vue'),
-        meta: { title: '组件列表' },
+        meta: { title: '组件列表', permission: 'components:read' },
       },
       {
         path: 'system/install',
         name: 'SystemInstall',
         component: () => import('@/views/System/Install.vue'),
-        meta: { title: '安装配置' },
+        meta: { title: '安装配置', permission: 'components:read' },
       },
       {
         path: 'users',
         name: 'Users',
         component: () => import('@/views/Users/index.vue'),
-        meta: { title: '用户管理' },
+        meta: { title: '用户管理', permission: 'users:read' },
+      },
+      {
+        path: 'account/tokens',
+        name: 'AccountTokens',
+        component: () => import('@/views/Account/Tokens.vue'),
+        meta: { title: 'API Token' },
+      },
+      {
+        path: 'account/security',
+        name: 'AccountSecurity',
+        component: () => import('@/views/Account/Security.vue'),
+        meta: { title: '两步验证' },
+      },
+      {
+        path: 'system/audit-logs',
+        name: 'SystemAuditLogs',
+        component: () => import('@/views/System/AuditLogs.vue'),
+        meta: { title: '审计日志', permission: 'audit:read' },
+      },
+      {
+        path: 'system/sso',
+        name: 'SystemSso',
+        component: () => import('@/views/System/Sso.vue'),
+        meta: { title: '单点登录', permission: 'system:read' },
+      },
+      {
+        path: 'system/approval',
+        name: 'SystemApproval',
+        component: () => import('@/views/System/Approval.vue'),
+        meta: { title: '审批策略', permission: 'system:read' },
+      },
+      {
+        path: 'system/maintenance',
+        name: 'SystemMaintenance',
+        component: () => import('@/views/System/Maintenance.vue'),
+        meta: { title: '维护窗口', permission: 'system:read' },
+      },
+      {
+        path: 'approvals',
+        name: 'Approvals',
+        component: () => import('@/views/Approvals/index.vue'),
+        meta: { title: '审批中心' },
       },
       {
         path: 'system/settings',
         name: 'SystemSettings',
         component: () => import('@/views/System/Settings.vue'),
-        meta: { title: '基本设置' },
+        meta: { title: '基本设置', permission: 'system:read' },
       },
       {
         path: 'system/notification',
         name: 'SystemNotification',
         component: () => import('@/views/System/Notification.vue'),
-        meta: { title: '通知管理' },
+        meta: { title: '通知管理', permission: 'notifications:read' },
       },
       {
         path: 'system/reports',
         name: 'SystemReports',
         component: () => import('@/views/System/Reports.vue'),
-        meta: { title: '统计报表' },
+        meta: { title: '统计报表', permission: 'reports:read' },
       },
       {
         path: 'system/task-report',
         name: 'SystemTaskReport',
         component: () => import('@/views/System/TaskReport.vue'),
-        meta: { title: '任务报告' },
+        meta: { title: '任务报告', permission: 'reports:read' },
       },
       {
         path: 'alerts',
         name: 'Alerts',
         component: () => import('@/views/Alerts/index.vue'),
-        meta: { title: '告警管理' },
+        meta: { title: '告警管理', permission: 'alerts:read' },
       },
       {
         path: 'alerts/:alertId',
         name: 'AlertDetail',
         component: () => import('@/views/Alerts/Detail.vue'),
-        meta: { title: '告警详情' },
+        meta: { title: '告警详情', permission: 'alerts:read' },
       },
       {
         path: 'system/inspection',
         name: 'Inspection',
         component: () => import('@/views/Inspection/index.vue'),
-        meta: { title: '运维巡检' },
+        meta: { title: '运维巡检', permission: 'reports:read' },
       },
       // FIM（文件完整性监控）
       {
         path: 'fim/dashboard',
         name: 'FIMDashboard',
         component: () => import('@/views/FIM/Dashboard/index.vue'),
-        meta: { title: 'FIM 概览' },
+        meta: { title: 'FIM 概览', permission: 'fim:read' },
       },
       {
         path: 'fim/policies',
         name: 'FIMPolicies',
         component: () => import('@/views/FIM/Policies/index.vue'),
-        meta: { title: 'FIM 策略' },
+        meta: { title: 'FIM 策略', permission: 'fim:read' },
       },
       {
         path: 'fim/events',
         name: 'FIMEvents',
         component: () => import('@/views/FIM/Events/index.vue'),
-        meta: { title: 'FIM 事件' },
+        meta: { title: 'FIM 事件', permission: 'fim:read' },
       },
       {
         path: 'fim/tasks',
         name: 'FIMTasks',
         component: () => import('@/views/FIM/Tasks/index.vue'),
-        meta: { title: 'FIM 任务' },
+        meta: { title: 'FIM 任务', permission: 'fim:read' },
       },
     ],
   },
@@ -245,6 +288,20 @@ router.beforeEach(async (to, _from, next) => {
       next('/login')
       return
     }
+    // 无权限访问的页面：从其他页面跳转时停留在原页面，直接访问时跳转到第一个有权限的页面
+    const permission = to.meta.permission as string | undefined
+    if (permission && !authStore.hasPermission(permission)) {
+      if (_from.name) {
+        message.error('没有权限访问该页面')
+        next(false)
+        return
+      }
+      const fallback = routes
+        .find((r) => r.path === '/')
+        ?.children?.find((r) => !r.path.includes(':') && authStore.hasPermission(r.meta?.permission as string))
+      next(fallback ? `/${fallback.path}` : '/404')
+      return
+    }
   }
 
   next()
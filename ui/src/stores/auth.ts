@@ -1,14 +1,12 @@
 import { defineStore } from 'pinia'
 import { ref } from 'vue'
 import { authApi } from '@/api/auth'
-import type { LoginRequest } from '@/api/auth'
-
-const TOKEN_KEY = 'mxcsec_token'
-const USER_KEY = 'mxcsec_user'
+import type { CurrentUser, LoginRequest } from '@/api/auth'
+import { TOKEN_KEY, REFRESH_TOKEN_KEY, USER_KEY } from '@/api/client'
 
 export const useAuthStore = defineStore('auth', () => {
   const token = ref<string | null>(localStorage.getItem(TOKEN_KEY))
-  const user = ref<{ username: string; role: string } | null>(
+  const user = ref<CurrentUser | null>(
     (() => {
       const stored = localStorage.getItem(USER_KEY)
       return stored ? JSON.parse(stored) : null
@@ -19,24 +17,38 @@ export const useAuthStore = defineStore('auth', () => {
     return !!token.value
   }
 
+  // 是否拥有指定权限（前端仅用于隐藏无权限的菜单和操作，权限以后端校验为准）
+  const hasPermission = (permission: string) => {
+    return !!user.value?.permissions?.includes(permission)
+  }
+
   const login = async (data: LoginRequest) => {
     const response = await authApi.login(data)
-    token.value = response.token
-    user.value = response.user
-    localStorage.setItem(TOKEN_KEY, response.token)
-    localStorage.setItem(USER_KEY, JSON.stringify(response.user))
+    await loginWithToken(response.token, response.refresh_token)
     return response
   }
 
+  // 保存访问令牌和刷新令牌并获取当前用户（OIDC 回调登录时令牌由登录页 URL 片段传入）
+  const loginWithToken = async (newToken: string, refreshToken: string) => {
+    token.value = newToken
+    localStorage.setItem(TOKEN_KEY, newToken)
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken)
+    // 登录响应不含权限，再获取一次当前用户
+    const currentUser = await authApi.getCurrentUser()
+    user.value = currentUser
+    localStorage.setItem(USER_KEY, JSON.stringify(currentUser))
+  }
+
   const logout = async () => {
     try {
-      await authApi.logout()
+      await authApi.logout(localStorage.getItem(REFRESH_TOKEN_KEY))
     } catch (error) {
       console.error('Logout error:', error)
     } finally {
       token.value = null
       user.value = null
       localStorage.removeItem(TOKEN_KEY)
+      localStorage.removeItem(REFRESH_TOKEN_KEY)
       localStorage.removeItem(USER_KEY)
     }
   }
@@ -48,10 +60,11 @@ export const useAuthStore = defineStore('auth', () => {
         user.value = currentUser
         localStorage.setItem(USER_KEY, JSON.stringify(currentUser))
       } catch (error) {
-        // Token 可能已过期，清除认证信息
+        // 会话已失效且无法刷新，清除认证信息
         token.value = null
         user.value = null
         localStorage.removeItem(TOKEN_KEY)
+        localStorage.removeItem(REFRESH_TOKEN_KEY)
         localStorage.removeItem(USER_KEY)
       }
     }
@@ -61,7 +74,9 @@ export const useAuthStore = defineStore('auth', () => {
     token,
     user,
     isAuthenticated,
+    hasPermission,
     login,
+    loginWithToken,
     logout,
     initAuth,
   }